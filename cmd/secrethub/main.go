@@ -1,26 +1,25 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/secrethub/secrethub-cli/internals/secrethub"
 )
 
 func main() {
-	err := secrethub.NewApp().Version(secrethub.Version, secrethub.Commit).Run(os.Args[1:])
+	app := secrethub.NewApp().Version(secrethub.Version, secrethub.Commit)
+	err := app.Run(os.Args[1:])
 	if err != nil {
-		handleError(err)
+		handleError(app, err)
 	}
 
 	os.Exit(0)
 }
 
 // handleError will process the error.
-// If the user wants to then a bug report is sent.
-func handleError(err error) {
+// The error is written to stderr and the application exits with the exit code for the error.
+func handleError(app *secrethub.App, err error) {
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Encountered an error: %s\n", err)
-		os.Exit(1)
+		os.Exit(app.HandleError(os.Stderr, err))
 	}
 }
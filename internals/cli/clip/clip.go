@@ -13,6 +13,8 @@ var (
 	ErrCannotRead = errClip.Code("cannot_read").ErrorPref("cannot read from clipboard: %s")
 	// ErrCannotWrite is returned when data cannot be written to the clipboard.
 	ErrCannotWrite = errClip.Code("cannot_write").ErrorPref("cannot write to clipboard: %s")
+	// ErrReadUnsupported is returned when the clipboard can only be written to, as is the case over OSC 52.
+	ErrReadUnsupported = errClip.Code("read_unsupported").Error("cannot read from clipboard: reading the clipboard is not supported by the terminal over OSC 52")
 )
 
 // Clipper allows you to read from and write to the clipboard.
@@ -40,7 +42,22 @@ func (c *clip) WriteAll(value []byte) error {
 	return nil
 }
 
-// NewClipboard creates a new Clipper.
+// NewClipboard creates a new Clipper. On systems with a display server, the clipboard is accessed
+// with wl-clipboard in a Wayland session and otherwise with xclip or xsel when an X11 display is
+// available. When neither can be used and the process runs in an SSH session or no clipboard
+// utility is installed at all, OSC 52 escape sequences are written to the terminal when it supports
+// them. In all other cases, the clipboard utilities of the OS are used.
 func NewClipboard() Clipper {
+	if usesDisplayServer() {
+		if clipper, ok := newWaylandClipper(); ok {
+			return clipper
+		}
+		if clipper, ok := newX11Clipper(); ok {
+			return clipper
+		}
+	}
+	if (clipboard.Unsupported || isRemoteSession()) && supportsOSC52() {
+		return newOSC52()
+	}
 	return &clip{}
 }
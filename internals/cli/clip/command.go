@@ -0,0 +1,101 @@
+package clip
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// commandClipper implements the Clipper interface with command-line clipboard utilities.
+type commandClipper struct {
+	copyArgs  []string
+	pasteArgs []string
+}
+
+// ReadAll runs the paste command and returns its output.
+func (c *commandClipper) ReadAll() ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(c.pasteArgs[0], c.pasteArgs[1:]...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, ErrCannotRead(commandError(err, stderr.String()))
+	}
+	return out, nil
+}
+
+// WriteAll runs the copy command with the value on its stdin.
+// The output of the command is not captured, as the copy commands leave a process running
+// in the background that serves the clipboard and that would keep the output open.
+func (c *commandClipper) WriteAll(value []byte) error {
+	cmd := exec.Command(c.copyArgs[0], c.copyArgs[1:]...)
+	cmd.Stdin = bytes.NewReader(value)
+
+	err := cmd.Run()
+	if err != nil {
+		return ErrCannotWrite(err)
+	}
+	return nil
+}
+
+// commandError returns the output the command wrote to stderr or the error when it did not write anything.
+func commandError(err error, stderr string) string {
+	msg := strings.TrimSpace(stderr)
+	if msg == "" {
+		return err.Error()
+	}
+	return msg
+}
+
+// newWaylandClipper returns a Clipper that uses wl-clipboard, if it is installed and
+// the process runs in a Wayland session.
+func newWaylandClipper() (Clipper, bool) {
+	if os.Getenv("WAYLAND_DISPLAY") == "" || !isInstalled("wl-copy", "wl-paste") {
+		return nil, false
+	}
+	return &commandClipper{
+		copyArgs:  []string{"wl-copy"},
+		pasteArgs: []string{"wl-paste", "--no-newline"},
+	}, true
+}
+
+// newX11Clipper returns a Clipper that uses xclip or xsel, if one of them is installed
+// and an X11 display is available.
+func newX11Clipper() (Clipper, bool) {
+	if os.Getenv("DISPLAY") == "" {
+		return nil, false
+	}
+	if isInstalled("xclip") {
+		return &commandClipper{
+			copyArgs:  []string{"xclip", "-in", "-selection", "clipboard"},
+			pasteArgs: []string{"xclip", "-out", "-selection", "clipboard"},
+		}, true
+	}
+	if isInstalled("xsel") {
+		return &commandClipper{
+			copyArgs:  []string{"xsel", "--input", "--clipboard"},
+			pasteArgs: []string{"xsel", "--output", "--clipboard"},
+		}, true
+	}
+	return nil, false
+}
+
+// usesDisplayServer returns whether the clipboard of the OS is provided by a display
+// server like Wayland or X11, as opposed to macOS and Windows.
+func usesDisplayServer() bool {
+	return runtime.GOOS != "darwin" && runtime.GOOS != "windows"
+}
+
+// isInstalled returns whether all given commands can be found in the PATH.
+func isInstalled(commands ...string) bool {
+	for _, command := range commands {
+		_, err := exec.LookPath(command)
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
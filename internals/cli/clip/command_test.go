@@ -0,0 +1,55 @@
+package clip
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCommandClipper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands require a POSIX shell")
+	}
+
+	dir, err := ioutil.TempDir("", "secrethub-clip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "clipboard")
+
+	clipper := &commandClipper{
+		copyArgs:  []string{"sh", "-c", "cat > " + file},
+		pasteArgs: []string{"cat", file},
+	}
+
+	err = clipper.WriteAll([]byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	actual, err := clipper.ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(actual) != "secret" {
+		t.Errorf("unexpected clipboard content: %q (actual) != %q (expected)", actual, "secret")
+	}
+}
+
+func TestCommandClipper_ReadError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands require a POSIX shell")
+	}
+
+	clipper := &commandClipper{
+		pasteArgs: []string{"sh", "-c", "echo 'no display' >&2; exit 1"},
+	}
+
+	_, err := clipper.ReadAll()
+	if err == nil || err.Error() != ErrCannotRead("no display").Error() {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package clip
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	errOSC52NoTerminal = errors.New("no terminal is available to write the OSC 52 escape sequence to")
+)
+
+// The terminal multiplexers that need the OSC 52 escape sequence to be wrapped to pass it on to the terminal.
+const (
+	multiplexerNone   = ""
+	multiplexerTmux   = "tmux"
+	multiplexerScreen = "screen"
+)
+
+// screenChunkSize is the maximum length of a string screen passes on to the terminal at once.
+const screenChunkSize = 76
+
+// osc52 implements the Clipper interface by writing OSC 52 escape sequences to the terminal,
+// which tell the terminal emulator to set the clipboard. This also works when the CLI runs on
+// a remote machine over SSH, as the escape sequence is passed on to the local terminal.
+// Terminal emulators do not allow to read the clipboard this way, so only writing is supported.
+type osc52 struct {
+	openTerminal func() (io.WriteCloser, error)
+	multiplexer  string
+}
+
+// newOSC52 creates a Clipper that writes OSC 52 escape sequences to the controlling terminal.
+func newOSC52() Clipper {
+	return &osc52{
+		openTerminal: openTTY,
+		multiplexer:  detectMultiplexer(),
+	}
+}
+
+// ReadAll returns ErrReadUnsupported, as the clipboard cannot be read over OSC 52.
+func (c *osc52) ReadAll() ([]byte, error) {
+	return nil, ErrReadUnsupported
+}
+
+// WriteAll sets the clipboard of the terminal emulator to the value.
+func (c *osc52) WriteAll(value []byte) error {
+	terminal, err := c.openTerminal()
+	if err != nil {
+		return ErrCannotWrite(errOSC52NoTerminal)
+	}
+	defer terminal.Close()
+
+	_, err = io.WriteString(terminal, osc52Sequence(value, c.multiplexer))
+	if err != nil {
+		return ErrCannotWrite(err)
+	}
+	return nil
+}
+
+// osc52Sequence returns the escape sequence that sets the clipboard to the value,
+// wrapped so that the given terminal multiplexer passes it on to the terminal.
+func osc52Sequence(value []byte, multiplexer string) string {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString(value) + "\a"
+
+	switch multiplexer {
+	case multiplexerTmux:
+		return "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case multiplexerScreen:
+		var b strings.Builder
+		for len(sequence) > 0 {
+			n := screenChunkSize
+			if n > len(sequence) {
+				n = len(sequence)
+			}
+			b.WriteString("\x1bP" + sequence[:n] + "\x1b\\")
+			sequence = sequence[n:]
+		}
+		return b.String()
+	default:
+		return sequence
+	}
+}
+
+// detectMultiplexer returns the terminal multiplexer the process runs in, if any.
+func detectMultiplexer() string {
+	if os.Getenv("TMUX") != "" {
+		return multiplexerTmux
+	}
+	if os.Getenv("STY") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return multiplexerScreen
+	}
+	return multiplexerNone
+}
+
+// supportsOSC52 returns whether the terminal is expected to understand OSC 52 escape sequences.
+// Dumb terminals and the Linux console do not, and without a terminal there is nothing to write to.
+func supportsOSC52() bool {
+	switch os.Getenv("TERM") {
+	case "", "dumb", "linux":
+		return false
+	}
+
+	terminal, err := openTTY()
+	if err != nil {
+		return false
+	}
+	_ = terminal.Close()
+	return true
+}
+
+// isRemoteSession returns whether the process runs in an SSH session.
+func isRemoteSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// openTTY opens the controlling terminal of the process for writing.
+func openTTY() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
@@ -0,0 +1,98 @@
+package clip
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type fakeTerminal struct {
+	*bytes.Buffer
+}
+
+func (fakeTerminal) Close() error {
+	return nil
+}
+
+func TestOSC52Sequence(t *testing.T) {
+	cases := map[string]struct {
+		value       string
+		multiplexer string
+		expected    string
+	}{
+		"plain": {
+			value:    "secret",
+			expected: "\x1b]52;c;c2VjcmV0\a",
+		},
+		"empty": {
+			value:    "",
+			expected: "\x1b]52;c;\a",
+		},
+		"tmux": {
+			value:       "secret",
+			multiplexer: multiplexerTmux,
+			expected:    "\x1bPtmux;\x1b\x1b]52;c;c2VjcmV0\a\x1b\\",
+		},
+		"screen": {
+			value:       "secret",
+			multiplexer: multiplexerScreen,
+			expected:    "\x1bP\x1b]52;c;c2VjcmV0\a\x1b\\",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := osc52Sequence([]byte(tc.value), tc.multiplexer)
+			if actual != tc.expected {
+				t.Errorf("unexpected sequence: %q (actual) != %q (expected)", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOSC52Sequence_ScreenChunks(t *testing.T) {
+	value := bytes.Repeat([]byte("a"), 100)
+
+	actual := osc52Sequence(value, multiplexerScreen)
+
+	chunks := bytes.Count([]byte(actual), []byte("\x1bP"))
+	if chunks != 2 {
+		t.Errorf("unexpected number of chunks: %d (actual) != 2 (expected)", chunks)
+	}
+}
+
+func TestOSC52_WriteAll(t *testing.T) {
+	var buf bytes.Buffer
+	clipper := &osc52{
+		openTerminal: func() (io.WriteCloser, error) {
+			return fakeTerminal{&buf}, nil
+		},
+	}
+
+	err := clipper.WriteAll([]byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if buf.String() != "\x1b]52;c;c2VjcmV0\a" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+
+	_, err = clipper.ReadAll()
+	if err != ErrReadUnsupported {
+		t.Errorf("unexpected error: %v (actual) != %v (expected)", err, ErrReadUnsupported)
+	}
+}
+
+func TestOSC52_WriteAll_NoTerminal(t *testing.T) {
+	clipper := &osc52{
+		openTerminal: func() (io.WriteCloser, error) {
+			return nil, errors.New("no tty")
+		},
+	}
+
+	err := clipper.WriteAll([]byte("secret"))
+	if err == nil {
+		t.Error("expected an error when no terminal is available")
+	}
+}
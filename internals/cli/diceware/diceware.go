@@ -0,0 +1,57 @@
+// Package diceware generates passphrases of random words, as described by the Diceware method.
+//
+// The words are picked from an embedded wordlist with a cryptographically secure random number
+// generator, which takes the place of the dice.
+package diceware
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DefaultSeparator is the separator that is put between the words by default.
+const DefaultSeparator = "-"
+
+// ErrInvalidWordCount is returned when a passphrase of less than one word is requested.
+var ErrInvalidWordCount = errors.New("the number of words must be larger than 0")
+
+// Generator generates passphrases.
+type Generator struct {
+	separator string
+	rand      io.Reader
+}
+
+// NewGenerator returns a generator of passphrases with the words separated by the given separator.
+func NewGenerator(separator string) Generator {
+	return Generator{
+		separator: separator,
+		rand:      rand.Reader,
+	}
+}
+
+// Generate returns a passphrase of n random words.
+func (g Generator) Generate(n int) ([]byte, error) {
+	if n <= 0 {
+		return nil, ErrInvalidWordCount
+	}
+
+	max := big.NewInt(int64(len(wordlist)))
+	words := make([]string, n)
+	for i := range words {
+		index, err := rand.Int(g.rand, max)
+		if err != nil {
+			return nil, err
+		}
+		words[i] = wordlist[index.Int64()]
+	}
+	return []byte(strings.Join(words, g.separator)), nil
+}
+
+// Entropy returns the number of bits of entropy of a passphrase of n words.
+func Entropy(n int) float64 {
+	return float64(n) * math.Log2(float64(len(wordlist)))
+}
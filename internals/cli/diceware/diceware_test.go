@@ -0,0 +1,63 @@
+package diceware
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestWordlist(t *testing.T) {
+	assert.Equal(t, len(wordlist), 6*6*6*6*6)
+	assert.Equal(t, wordlist[0], "abacus")
+	assert.Equal(t, wordlist[len(wordlist)-1], "zoom")
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	cases := map[string]struct {
+		n         int
+		separator string
+		rand      []byte
+		expected  string
+		err       error
+	}{
+		"words": {
+			n:         3,
+			separator: "-",
+			rand:      []byte{0x00, 0x00, 0x00, 0x01, 0x1e, 0x5f},
+			expected:  "abacus-abdomen-zoom",
+		},
+		"separator": {
+			n:         2,
+			separator: " ",
+			rand:      []byte{0x00, 0x00, 0x00, 0x01},
+			expected:  "abacus abdomen",
+		},
+		"no words": {
+			n:   0,
+			err: ErrInvalidWordCount,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := Generator{
+				separator: tc.separator,
+				rand:      bytes.NewReader(tc.rand),
+			}
+
+			actual, err := g.Generate(tc.n)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, string(actual), tc.expected)
+		})
+	}
+}
+
+func TestNewGenerator(t *testing.T) {
+	actual, err := NewGenerator("-").Generate(6)
+
+	assert.OK(t, err)
+	assert.Equal(t, len(strings.Split(string(actual), "-")), 6)
+}
@@ -0,0 +1,60 @@
+// Package fuse serves a filesystem to the kernel with the FUSE protocol,
+// so applications can read its files like any other files.
+package fuse
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// Errors that a FS returns to report the corresponding error to the application.
+var (
+	ErrNotExist     = errors.New("no such file or directory")
+	ErrReadOnly     = errors.New("read-only file system")
+	ErrPermission   = errors.New("permission denied")
+	ErrNotSupported = errors.New("mounting a FUSE filesystem is only supported on Linux")
+)
+
+// FS is a filesystem of directories and files that can be mounted. Paths are slash separated
+// and relative to the root of the filesystem, which has the empty path.
+type FS interface {
+	// Stat returns the attributes of the file or directory at the path.
+	Stat(path string) (Attr, error)
+	// ReadDir returns the entries of the directory at the path.
+	ReadDir(path string) ([]Dirent, error)
+	// ReadFile returns the contents of the file at the path.
+	ReadFile(path string) ([]byte, error)
+	// WriteFile replaces the contents of the file at the path, creating it when it does not exist.
+	WriteFile(path string, data []byte) error
+	// Mkdir creates a directory at the path.
+	Mkdir(path string) error
+}
+
+// Attr holds the attributes of a file or directory.
+type Attr struct {
+	Dir     bool
+	Size    uint64
+	Mode    os.FileMode
+	ModTime time.Time
+}
+
+// Dirent is an entry of a directory.
+type Dirent struct {
+	Name string
+	Dir  bool
+}
+
+// MountOptions configure how a filesystem is mounted.
+type MountOptions struct {
+	// Name is the name of the filesystem that is shown in the list of mounts.
+	Name string
+	// ReadOnly mounts the filesystem read-only, so the kernel rejects all changes.
+	ReadOnly bool
+	// AttrValid is the time the kernel caches the attributes and entries of the filesystem.
+	AttrValid time.Duration
+	// ErrorLog is written a line for every call to the filesystem that fails with an
+	// error other than ErrNotExist. It is not written to when it is nil.
+	ErrorLog io.Writer
+}
@@ -0,0 +1,21 @@
+// +build !linux
+
+package fuse
+
+// Server serves a mounted filesystem.
+type Server struct{}
+
+// Mount is not supported on this platform.
+func Mount(mountpoint string, fs FS, options MountOptions) (*Server, error) {
+	return nil, ErrNotSupported
+}
+
+// Serve is not supported on this platform.
+func (s *Server) Serve() error {
+	return ErrNotSupported
+}
+
+// Unmount is not supported on this platform.
+func (s *Server) Unmount() error {
+	return ErrNotSupported
+}
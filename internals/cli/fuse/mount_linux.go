@@ -0,0 +1,161 @@
+package fuse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Mount mounts the filesystem at the mountpoint, which must be an existing directory.
+// The filesystem is served once Serve is called. Only the user that mounts the filesystem
+// can access it and the kernel checks the file modes of the files on every access.
+//
+// When running as root, the filesystem is mounted with the mount system call. Otherwise,
+// it is mounted with the setuid fusermount helper of the FUSE package of the system.
+func Mount(mountpoint string, fs FS, options MountOptions) (*Server, error) {
+	if options.Name == "" {
+		options.Name = "fuse"
+	}
+
+	var fd int
+	var err error
+	privileged := os.Geteuid() == 0
+	if privileged {
+		fd, err = mountPrivileged(mountpoint, options)
+	} else {
+		fd, err = mountFusermount(mountpoint, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newServer(fd, mountpoint, privileged, fs, options), nil
+}
+
+// mountPrivileged opens the FUSE device and mounts it at the mountpoint.
+func mountPrivileged(mountpoint string, options MountOptions) (int, error) {
+	fd, err := unix.Open("/dev/fuse", unix.O_RDWR|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, fmt.Errorf("cannot open /dev/fuse: %s", err)
+	}
+
+	flags := uintptr(unix.MS_NOSUID | unix.MS_NODEV)
+	if options.ReadOnly {
+		flags |= unix.MS_RDONLY
+	}
+	data := fmt.Sprintf("fd=%d,rootmode=%o,user_id=%d,group_id=%d,default_permissions",
+		fd, unix.S_IFDIR, os.Getuid(), os.Getgid())
+
+	err = unix.Mount(options.Name, mountpoint, "fuse."+options.Name, flags, data)
+	if err != nil {
+		_ = unix.Close(fd)
+		return -1, fmt.Errorf("cannot mount %s: %s", mountpoint, err)
+	}
+	return fd, nil
+}
+
+// mountFusermount mounts the filesystem with the fusermount helper, which passes
+// the opened FUSE device back over a socket.
+func mountFusermount(mountpoint string, options MountOptions) (int, error) {
+	bin, err := fusermount()
+	if err != nil {
+		return -1, err
+	}
+
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return -1, err
+	}
+	local := os.NewFile(uintptr(fds[0]), "fusermount")
+	defer local.Close()
+	remote := os.NewFile(uintptr(fds[1]), "fusermount")
+
+	mountOptions := []string{"fsname=" + options.Name, "subtype=" + options.Name, "default_permissions"}
+	if options.ReadOnly {
+		mountOptions = append(mountOptions, "ro")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(bin, "-o", strings.Join(mountOptions, ","), "--", mountpoint)
+	// The socket is the first extra file, which is file descriptor 3 in the helper.
+	cmd.ExtraFiles = []*os.File{remote}
+	cmd.Env = append(os.Environ(), "_FUSE_COMMFD=3")
+	cmd.Stderr = &stderr
+	err = cmd.Start()
+	remote.Close()
+	if err != nil {
+		return -1, fmt.Errorf("cannot run %s: %s", bin, err)
+	}
+
+	fd, recvErr := receiveFD(local)
+	err = cmd.Wait()
+	if err != nil {
+		if fd >= 0 {
+			_ = unix.Close(fd)
+		}
+		return -1, fmt.Errorf("cannot mount %s: %s", mountpoint, strings.TrimSpace(stderr.String()))
+	}
+	if recvErr != nil {
+		return -1, fmt.Errorf("cannot mount %s: %s", mountpoint, recvErr)
+	}
+	return fd, nil
+}
+
+// receiveFD receives a file descriptor over the socket.
+func receiveFD(socket *os.File) (int, error) {
+	buf := make([]byte, 1)
+	oob := make([]byte, unix.CmsgSpace(4))
+	_, oobn, _, _, err := unix.Recvmsg(int(socket.Fd()), buf, oob, 0)
+	if err != nil {
+		return -1, err
+	}
+
+	messages, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return -1, err
+	}
+	if len(messages) != 1 {
+		return -1, errors.New("fusermount did not pass the FUSE device")
+	}
+	fds, err := unix.ParseUnixRights(&messages[0])
+	if err != nil {
+		return -1, err
+	}
+	if len(fds) != 1 {
+		return -1, errors.New("fusermount did not pass the FUSE device")
+	}
+	return fds[0], nil
+}
+
+// fusermount returns the path of the fusermount helper of FUSE 3 or FUSE 2.
+func fusermount() (string, error) {
+	for _, name := range []string{"fusermount3", "fusermount"} {
+		path, err := exec.LookPath(name)
+		if err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("cannot find fusermount: install the FUSE package of your system to mount as a normal user")
+}
+
+// unmount unmounts the filesystem at the mountpoint.
+func unmount(mountpoint string, privileged bool) error {
+	if privileged {
+		return unix.Unmount(mountpoint, 0)
+	}
+
+	bin, err := fusermount()
+	if err != nil {
+		return err
+	}
+	out, err := exec.Command(bin, "-u", mountpoint).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cannot unmount %s: %s", mountpoint, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
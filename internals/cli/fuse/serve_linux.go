@@ -0,0 +1,740 @@
+package fuse
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// The version of the FUSE protocol that is spoken. The kernel adapts to the minor version
+// of the server, which determines the layout of the messages below.
+const (
+	protocolMajor = 7
+	protocolMinor = 12
+)
+
+// The opcodes of the requests of the kernel.
+const (
+	opLookup     = 1
+	opForget     = 2
+	opGetattr    = 3
+	opSetattr    = 4
+	opMkdir      = 9
+	opOpen       = 14
+	opRead       = 15
+	opWrite      = 16
+	opStatfs     = 17
+	opRelease    = 18
+	opFsync      = 20
+	opFlush      = 25
+	opInit       = 26
+	opOpendir    = 27
+	opReaddir    = 28
+	opReleasedir = 29
+	opCreate     = 35
+	opInterrupt  = 36
+	opDestroy    = 38
+)
+
+const (
+	// inHeaderSize is the size of the header of every request.
+	inHeaderSize = 40
+	// outHeaderSize is the size of the header of every reply.
+	outHeaderSize = 16
+	// maxWrite is the maximum size of the data of a write request.
+	maxWrite = 128 * 1024
+	// rootID is the node ID of the root directory.
+	rootID = 1
+	// blockSize is the block size that is reported for all files.
+	blockSize = 4096
+)
+
+// The flags of the valid field of a setattr request.
+const (
+	setattrSize = 1 << 3
+	setattrFH   = 1 << 6
+)
+
+// byteOrder is the byte order of the host, in which the FUSE messages are encoded.
+var byteOrder binary.ByteOrder = binary.LittleEndian
+
+func init() {
+	x := uint16(1)
+	if (*[2]byte)(unsafe.Pointer(&x))[0] == 0 {
+		byteOrder = binary.BigEndian
+	}
+}
+
+// Server serves a mounted filesystem to the kernel. Requests are handled one at a time.
+type Server struct {
+	fd         int
+	mountpoint string
+	privileged bool
+	fs         FS
+	options    MountOptions
+	write      func([]byte) error
+
+	// paths holds the path of every node ID and ids the node ID of every path.
+	// Node IDs are never reused, so the kernel can keep referring to them.
+	paths []string
+	ids   map[string]uint64
+
+	handles    map[uint64]*handle
+	nextHandle uint64
+
+	unmountOnce sync.Once
+}
+
+// handle is a file or directory opened by an application. The contents of a file are read
+// when it is opened and the changes are written to the filesystem when it is flushed.
+type handle struct {
+	path    string
+	mode    os.FileMode
+	data    []byte
+	dirty   bool
+	entries []Dirent
+}
+
+// newServer returns a server that serves the filesystem on the opened FUSE device.
+func newServer(fd int, mountpoint string, privileged bool, fs FS, options MountOptions) *Server {
+	s := &Server{
+		fd:         fd,
+		mountpoint: mountpoint,
+		privileged: privileged,
+		fs:         fs,
+		options:    options,
+		paths:      []string{"", ""},
+		ids:        map[string]uint64{"": rootID},
+		handles:    map[uint64]*handle{},
+	}
+	s.write = func(msg []byte) error {
+		_, err := unix.Write(s.fd, msg)
+		return err
+	}
+	return s
+}
+
+// Serve handles the requests of the kernel until the filesystem is unmounted.
+func (s *Server) Serve() error {
+	defer unix.Close(s.fd)
+
+	buf := make([]byte, maxWrite+os.Getpagesize())
+	for {
+		n, err := unix.Read(s.fd, buf)
+		switch err {
+		case nil:
+		case unix.EINTR, unix.EAGAIN, unix.ENOENT:
+			// The request was interrupted before it was read.
+			continue
+		case unix.ENODEV:
+			// The filesystem has been unmounted.
+			return nil
+		default:
+			return err
+		}
+
+		if n < inHeaderSize {
+			return fmt.Errorf("short FUSE request of %d bytes", n)
+		}
+		done := s.handle(buf[:n])
+		if done {
+			return nil
+		}
+	}
+}
+
+// Unmount unmounts the filesystem, after which Serve returns.
+func (s *Server) Unmount() error {
+	var err error
+	s.unmountOnce.Do(func() {
+		err = unmount(s.mountpoint, s.privileged)
+	})
+	return err
+}
+
+// request is a request of the kernel.
+type request struct {
+	opcode uint32
+	unique uint64
+	nodeID uint64
+	body   []byte
+}
+
+// handle handles a request and returns whether the filesystem is being destroyed.
+func (s *Server) handle(msg []byte) bool {
+	req := request{
+		opcode: byteOrder.Uint32(msg[4:]),
+		unique: byteOrder.Uint64(msg[8:]),
+		nodeID: byteOrder.Uint64(msg[16:]),
+		body:   msg[inHeaderSize:],
+	}
+
+	var reply []byte
+	var err error
+	switch req.opcode {
+	case opInit:
+		reply, err = s.init(req)
+	case opLookup:
+		reply, err = s.lookup(req)
+	case opForget, opInterrupt:
+		// These requests have no reply.
+		return false
+	case opGetattr:
+		reply, err = s.getattr(req)
+	case opSetattr:
+		reply, err = s.setattr(req)
+	case opMkdir:
+		reply, err = s.mkdir(req)
+	case opOpen:
+		reply, err = s.open(req)
+	case opCreate:
+		reply, err = s.create(req)
+	case opRead:
+		reply, err = s.read(req)
+	case opWrite:
+		reply, err = s.writeFile(req)
+	case opFlush, opFsync:
+		reply, err = s.flush(req)
+	case opRelease:
+		reply, err = s.release(req)
+	case opOpendir:
+		reply, err = s.opendir(req)
+	case opReaddir:
+		reply, err = s.readdir(req)
+	case opReleasedir:
+		delete(s.handles, byteOrder.Uint64(req.body))
+	case opStatfs:
+		reply = s.statfs()
+	case opDestroy:
+		_ = s.reply(req, nil, nil)
+		return true
+	default:
+		err = syscall.ENOSYS
+	}
+
+	_ = s.reply(req, reply, err)
+	return false
+}
+
+// reply sends the reply to the request, or the error when it is not nil.
+func (s *Server) reply(req request, payload []byte, err error) error {
+	errno := int32(0)
+	if err != nil {
+		errno = -int32(s.errno(req, err))
+		payload = nil
+	}
+
+	msg := make([]byte, outHeaderSize, outHeaderSize+len(payload))
+	byteOrder.PutUint32(msg[0:], uint32(outHeaderSize+len(payload)))
+	byteOrder.PutUint32(msg[4:], uint32(errno))
+	byteOrder.PutUint64(msg[8:], req.unique)
+	msg = append(msg, payload...)
+	return s.write(msg)
+}
+
+// errno returns the error number of the error and logs errors of the filesystem.
+func (s *Server) errno(req request, err error) syscall.Errno {
+	var errno syscall.Errno
+	switch {
+	case errors.As(err, &errno):
+		return errno
+	case errors.Is(err, ErrNotExist):
+		return syscall.ENOENT
+	case errors.Is(err, ErrReadOnly):
+		return syscall.EROFS
+	case errors.Is(err, ErrPermission):
+		return syscall.EACCES
+	}
+
+	if s.options.ErrorLog != nil {
+		fmt.Fprintf(s.options.ErrorLog, "%s: %s\n", s.path(req.nodeID), err)
+	}
+	return syscall.EIO
+}
+
+// init negotiates the version of the protocol and the limits of the requests.
+func (s *Server) init(req request) ([]byte, error) {
+	if len(req.body) < 16 {
+		return nil, syscall.EINVAL
+	}
+	major := byteOrder.Uint32(req.body[0:])
+	maxReadahead := byteOrder.Uint32(req.body[8:])
+	if major < protocolMajor {
+		return nil, syscall.EPROTO
+	}
+
+	var e encoder
+	e.u32(protocolMajor)
+	e.u32(protocolMinor)
+	e.u32(maxReadahead)
+	e.u32(0) // flags
+	e.u16(0) // max_background
+	e.u16(0) // congestion_threshold
+	e.u32(maxWrite)
+	return e, nil
+}
+
+// lookup returns the entry of a name in a directory.
+func (s *Server) lookup(req request) ([]byte, error) {
+	path := join(s.path(req.nodeID), cstring(req.body))
+	attr, err := s.stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.entry(path, attr), nil
+}
+
+// getattr returns the attributes of a node.
+func (s *Server) getattr(req request) ([]byte, error) {
+	path := s.path(req.nodeID)
+	attr, err := s.stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.attrOut(path, attr), nil
+}
+
+// setattr changes the size of a file. Other attributes cannot be changed and are ignored.
+func (s *Server) setattr(req request) ([]byte, error) {
+	if len(req.body) < 24 {
+		return nil, syscall.EINVAL
+	}
+	valid := byteOrder.Uint32(req.body[0:])
+	fh := byteOrder.Uint64(req.body[8:])
+	size := byteOrder.Uint64(req.body[16:])
+	path := s.path(req.nodeID)
+
+	if valid&setattrSize != 0 {
+		if s.options.ReadOnly {
+			return nil, syscall.EROFS
+		}
+		// The size of an opened file is changed in the handles of the file, so the change is
+		// written together with the data that is written to it, e.g. when a file is opened with
+		// O_TRUNC. Otherwise, the file is resized in the filesystem right away.
+		var handles []*handle
+		if h, ok := s.handles[fh]; ok && valid&setattrFH != 0 {
+			handles = append(handles, h)
+		} else {
+			for _, h := range s.handles {
+				if h.path == path && h.entries == nil {
+					handles = append(handles, h)
+				}
+			}
+		}
+
+		for _, h := range handles {
+			h.data = resize(h.data, size)
+			h.dirty = true
+		}
+		if len(handles) == 0 {
+			data, err := s.fs.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			err = s.fs.WriteFile(path, resize(data, size))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	attr, err := s.stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.attrOut(path, attr), nil
+}
+
+// mkdir creates a directory.
+func (s *Server) mkdir(req request) ([]byte, error) {
+	if len(req.body) < 8 {
+		return nil, syscall.EINVAL
+	}
+	path := join(s.path(req.nodeID), cstring(req.body[8:]))
+	err := s.fs.Mkdir(path)
+	if err != nil {
+		return nil, err
+	}
+	attr, err := s.fs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.entry(path, attr), nil
+}
+
+// open opens a file, reading its contents.
+func (s *Server) open(req request) ([]byte, error) {
+	if len(req.body) < 4 {
+		return nil, syscall.EINVAL
+	}
+	flags := byteOrder.Uint32(req.body[0:])
+	if s.options.ReadOnly && flags&unix.O_ACCMODE != unix.O_RDONLY {
+		return nil, syscall.EROFS
+	}
+
+	path := s.path(req.nodeID)
+	data, err := s.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.openOut(&handle{path: path, data: data}), nil
+}
+
+// create creates and opens a file. The file is created in the filesystem when it is flushed.
+func (s *Server) create(req request) ([]byte, error) {
+	if len(req.body) < 16 {
+		return nil, syscall.EINVAL
+	}
+	if s.options.ReadOnly {
+		return nil, syscall.EROFS
+	}
+	mode := os.FileMode(byteOrder.Uint32(req.body[4:])).Perm()
+	path := join(s.path(req.nodeID), cstring(req.body[16:]))
+
+	h := &handle{path: path, mode: mode}
+	attr, err := s.fs.Stat(path)
+	switch {
+	case err == nil && attr.Dir:
+		return nil, syscall.EISDIR
+	case err == nil:
+		h.data, err = s.fs.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, ErrNotExist):
+		return nil, err
+	}
+
+	open := s.openOut(h)
+	attr, err = s.stat(path)
+	if err != nil {
+		delete(s.handles, byteOrder.Uint64(open))
+		return nil, err
+	}
+	return append(s.entry(path, attr), open...), nil
+}
+
+// read returns the contents of an opened file at the requested offset.
+func (s *Server) read(req request) ([]byte, error) {
+	if len(req.body) < 20 {
+		return nil, syscall.EINVAL
+	}
+	h, ok := s.handles[byteOrder.Uint64(req.body[0:])]
+	if !ok {
+		return nil, syscall.EBADF
+	}
+	offset := byteOrder.Uint64(req.body[8:])
+	size := uint64(byteOrder.Uint32(req.body[16:]))
+
+	if offset >= uint64(len(h.data)) {
+		return []byte{}, nil
+	}
+	end := offset + size
+	if end > uint64(len(h.data)) {
+		end = uint64(len(h.data))
+	}
+	return h.data[offset:end], nil
+}
+
+// writeFile writes data to an opened file at the requested offset.
+func (s *Server) writeFile(req request) ([]byte, error) {
+	if len(req.body) < 40 {
+		return nil, syscall.EINVAL
+	}
+	h, ok := s.handles[byteOrder.Uint64(req.body[0:])]
+	if !ok {
+		return nil, syscall.EBADF
+	}
+	offset := byteOrder.Uint64(req.body[8:])
+	size := byteOrder.Uint32(req.body[16:])
+	data := req.body[40:]
+	if uint64(len(data)) < uint64(size) {
+		return nil, syscall.EINVAL
+	}
+
+	end := offset + uint64(size)
+	if end > uint64(len(h.data)) {
+		h.data = resize(h.data, end)
+	}
+	copy(h.data[offset:], data[:size])
+	h.dirty = true
+
+	var e encoder
+	e.u32(size)
+	e.u32(0) // padding
+	return e, nil
+}
+
+// flush writes the changes to an opened file to the filesystem.
+func (s *Server) flush(req request) ([]byte, error) {
+	if len(req.body) < 8 {
+		return nil, syscall.EINVAL
+	}
+	h, ok := s.handles[byteOrder.Uint64(req.body[0:])]
+	if !ok {
+		return nil, syscall.EBADF
+	}
+	if !h.dirty {
+		return nil, nil
+	}
+
+	err := s.fs.WriteFile(h.path, h.data)
+	if err != nil {
+		return nil, err
+	}
+	h.dirty = false
+	return nil, nil
+}
+
+// release closes an opened file, writing the changes that have not been flushed.
+func (s *Server) release(req request) ([]byte, error) {
+	if len(req.body) < 8 {
+		return nil, syscall.EINVAL
+	}
+	_, err := s.flush(req)
+	delete(s.handles, byteOrder.Uint64(req.body[0:]))
+	return nil, err
+}
+
+// opendir opens a directory, reading its entries.
+func (s *Server) opendir(req request) ([]byte, error) {
+	path := s.path(req.nodeID)
+	entries, err := s.fs.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	entries = append([]Dirent{{Name: ".", Dir: true}, {Name: "..", Dir: true}}, entries...)
+	return s.openOut(&handle{path: path, entries: entries}), nil
+}
+
+// readdir returns the entries of an opened directory from the requested offset, which is
+// the index of the next entry.
+func (s *Server) readdir(req request) ([]byte, error) {
+	if len(req.body) < 20 {
+		return nil, syscall.EINVAL
+	}
+	h, ok := s.handles[byteOrder.Uint64(req.body[0:])]
+	if !ok {
+		return nil, syscall.EBADF
+	}
+	offset := byteOrder.Uint64(req.body[8:])
+	size := int(byteOrder.Uint32(req.body[16:]))
+
+	var e encoder
+	for i := offset; i < uint64(len(h.entries)); i++ {
+		entry := h.entries[i]
+		entrySize := align8(24 + len(entry.Name))
+		if len(e)+entrySize > size {
+			break
+		}
+
+		var ino uint64
+		var typ uint32
+		switch entry.Name {
+		case ".", "..":
+			ino = rootID
+		default:
+			ino = s.id(join(h.path, entry.Name))
+		}
+		if entry.Dir {
+			typ = unix.DT_DIR
+		} else {
+			typ = unix.DT_REG
+		}
+
+		e.u64(ino)
+		e.u64(i + 1)
+		e.u32(uint32(len(entry.Name)))
+		e.u32(typ)
+		e = append(e, entry.Name...)
+		e = append(e, make([]byte, entrySize-24-len(entry.Name))...)
+	}
+	if e == nil {
+		e = encoder{}
+	}
+	return e, nil
+}
+
+// statfs returns the statistics of the filesystem, which has no limits.
+func (s *Server) statfs() []byte {
+	var e encoder
+	for i := 0; i < 5; i++ {
+		e.u64(0) // blocks, bfree, bavail, files, ffree
+	}
+	e.u32(blockSize) // bsize
+	e.u32(255)       // namelen
+	e.u32(blockSize) // frsize
+	e.u32(0)         // padding
+	for i := 0; i < 6; i++ {
+		e.u32(0) // spare
+	}
+	return e
+}
+
+// stat returns the attributes of the path, which are the attributes of a file that
+// is being created when it does not exist in the filesystem yet.
+func (s *Server) stat(path string) (Attr, error) {
+	attr, err := s.fs.Stat(path)
+	if errors.Is(err, ErrNotExist) {
+		for _, h := range s.handles {
+			if h.path == path && h.entries == nil {
+				return Attr{Size: uint64(len(h.data)), Mode: h.mode, ModTime: time.Now()}, nil
+			}
+		}
+	}
+	if err != nil {
+		return Attr{}, err
+	}
+
+	for _, h := range s.handles {
+		if h.path == path && h.dirty {
+			attr.Size = uint64(len(h.data))
+		}
+	}
+	return attr, nil
+}
+
+// openOut registers the handle and returns the reply to an open request.
+func (s *Server) openOut(h *handle) []byte {
+	s.nextHandle++
+	s.handles[s.nextHandle] = h
+
+	var e encoder
+	e.u64(s.nextHandle)
+	e.u32(0) // open_flags
+	e.u32(0) // padding
+	return e
+}
+
+// entry returns the reply to a lookup of the path.
+func (s *Server) entry(path string, attr Attr) []byte {
+	valid := s.options.AttrValid
+
+	var e encoder
+	e.u64(s.id(path))
+	e.u64(0) // generation
+	e.u64(uint64(valid / time.Second))
+	e.u64(uint64(valid / time.Second))
+	e.u32(uint32(valid % time.Second))
+	e.u32(uint32(valid % time.Second))
+	e.attr(s.id(path), attr)
+	return e
+}
+
+// attrOut returns the reply to a getattr request on the path.
+func (s *Server) attrOut(path string, attr Attr) []byte {
+	valid := s.options.AttrValid
+
+	var e encoder
+	e.u64(uint64(valid / time.Second))
+	e.u32(uint32(valid % time.Second))
+	e.u32(0) // dummy
+	e.attr(s.id(path), attr)
+	return e
+}
+
+// id returns the node ID of the path, assigning one when the path has none yet.
+func (s *Server) id(path string) uint64 {
+	id, ok := s.ids[path]
+	if !ok {
+		id = uint64(len(s.paths))
+		s.paths = append(s.paths, path)
+		s.ids[path] = id
+	}
+	return id
+}
+
+// path returns the path of the node ID.
+func (s *Server) path(id uint64) string {
+	if id >= uint64(len(s.paths)) {
+		return ""
+	}
+	return s.paths[id]
+}
+
+// encoder encodes the fields of a reply.
+type encoder []byte
+
+func (e *encoder) u16(v uint16) {
+	*e = append(*e, 0, 0)
+	byteOrder.PutUint16((*e)[len(*e)-2:], v)
+}
+
+func (e *encoder) u32(v uint32) {
+	*e = append(*e, 0, 0, 0, 0)
+	byteOrder.PutUint32((*e)[len(*e)-4:], v)
+}
+
+func (e *encoder) u64(v uint64) {
+	*e = append(*e, 0, 0, 0, 0, 0, 0, 0, 0)
+	byteOrder.PutUint64((*e)[len(*e)-8:], v)
+}
+
+// attr encodes the attributes of the node.
+func (e *encoder) attr(id uint64, attr Attr) {
+	mode := uint32(attr.Mode.Perm())
+	nlink := uint32(1)
+	if attr.Dir {
+		mode |= unix.S_IFDIR
+		nlink = 2
+	} else {
+		mode |= unix.S_IFREG
+	}
+	sec := uint64(attr.ModTime.Unix())
+	nsec := uint32(attr.ModTime.Nanosecond())
+
+	e.u64(id)
+	e.u64(attr.Size)
+	e.u64((attr.Size + 511) / 512) // blocks
+	e.u64(sec)                     // atime
+	e.u64(sec)                     // mtime
+	e.u64(sec)                     // ctime
+	e.u32(nsec)
+	e.u32(nsec)
+	e.u32(nsec)
+	e.u32(mode)
+	e.u32(nlink)
+	e.u32(uint32(os.Getuid()))
+	e.u32(uint32(os.Getgid()))
+	e.u32(0) // rdev
+	e.u32(blockSize)
+	e.u32(0) // padding
+}
+
+// join returns the path of the name in the directory at the path.
+func join(dir string, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// cstring returns the NUL-terminated string at the start of b.
+func cstring(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+// resize returns the data truncated or extended with zeros to the size.
+func resize(data []byte, size uint64) []byte {
+	if size <= uint64(len(data)) {
+		return data[:size]
+	}
+	return append(data, make([]byte, size-uint64(len(data)))...)
+}
+
+// align8 rounds n up to a multiple of 8.
+func align8(n int) int {
+	return (n + 7) &^ 7
+}
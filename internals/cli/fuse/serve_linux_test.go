@@ -0,0 +1,203 @@
+package fuse
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// fakeFS is a FS with files in the root directory.
+type fakeFS struct {
+	files map[string][]byte
+}
+
+func (fs *fakeFS) Stat(path string) (Attr, error) {
+	if path == "" {
+		return Attr{Dir: true, Mode: 0500}, nil
+	}
+	data, ok := fs.files[path]
+	if !ok {
+		return Attr{}, ErrNotExist
+	}
+	return Attr{Size: uint64(len(data)), Mode: 0400}, nil
+}
+
+func (fs *fakeFS) ReadDir(path string) ([]Dirent, error) {
+	var entries []Dirent
+	for name := range fs.files {
+		entries = append(entries, Dirent{Name: name})
+	}
+	return entries, nil
+}
+
+func (fs *fakeFS) ReadFile(path string) ([]byte, error) {
+	data, ok := fs.files[path]
+	if !ok {
+		return nil, ErrNotExist
+	}
+	return data, nil
+}
+
+func (fs *fakeFS) WriteFile(path string, data []byte) error {
+	fs.files[path] = data
+	return nil
+}
+
+func (fs *fakeFS) Mkdir(path string) error {
+	return ErrPermission
+}
+
+// testServer serves a fakeFS and records the replies.
+type testServer struct {
+	*Server
+	t       *testing.T
+	unique  uint64
+	replies [][]byte
+}
+
+func newTestServer(t *testing.T, readOnly bool, files map[string][]byte) *testServer {
+	s := &testServer{
+		Server: newServer(-1, "", false, &fakeFS{files: files}, MountOptions{ReadOnly: readOnly, AttrValid: time.Second}),
+		t:      t,
+	}
+	s.write = func(msg []byte) error {
+		s.replies = append(s.replies, msg)
+		return nil
+	}
+	return s
+}
+
+// call sends a request and returns the error number and payload of the reply.
+func (s *testServer) call(opcode uint32, nodeID uint64, body []byte) (syscall.Errno, []byte) {
+	s.unique++
+	var msg encoder
+	msg.u32(uint32(inHeaderSize + len(body)))
+	msg.u32(opcode)
+	msg.u64(s.unique)
+	msg.u64(nodeID)
+	msg.u32(0) // uid
+	msg.u32(0) // gid
+	msg.u32(0) // pid
+	msg.u32(0) // padding
+	msg = append(msg, body...)
+
+	s.handle(msg)
+	reply := s.replies[len(s.replies)-1]
+	assert.Equal(s.t, byteOrder.Uint32(reply[0:]), uint32(len(reply)))
+	assert.Equal(s.t, byteOrder.Uint64(reply[8:]), s.unique)
+	return syscall.Errno(-int32(byteOrder.Uint32(reply[4:]))), reply[outHeaderSize:]
+}
+
+// fileBody returns the body of a request on the opened file with the given offset and size.
+func fileBody(fh uint64, offset uint64, size uint32) []byte {
+	var e encoder
+	e.u64(fh)
+	e.u64(offset)
+	e.u32(size)
+	for len(e) < 40 {
+		e = append(e, 0)
+	}
+	return e
+}
+
+func TestServer_init(t *testing.T) {
+	s := newTestServer(t, true, nil)
+
+	var body encoder
+	body.u32(7)
+	body.u32(31)
+	body.u32(65536)
+	body.u32(0)
+	errno, reply := s.call(opInit, 0, body)
+
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, len(reply), 24)
+	assert.Equal(t, byteOrder.Uint32(reply[0:]), uint32(protocolMajor))
+	assert.Equal(t, byteOrder.Uint32(reply[4:]), uint32(protocolMinor))
+	assert.Equal(t, byteOrder.Uint32(reply[20:]), uint32(maxWrite))
+}
+
+func TestServer_lookupAndRead(t *testing.T) {
+	s := newTestServer(t, true, map[string][]byte{"secret": []byte("value")})
+
+	errno, _ := s.call(opLookup, rootID, []byte("missing\x00"))
+	assert.Equal(t, errno, syscall.ENOENT)
+
+	errno, entry := s.call(opLookup, rootID, []byte("secret\x00"))
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, len(entry), 128)
+	nodeID := byteOrder.Uint64(entry[0:])
+	assert.Equal(t, byteOrder.Uint64(entry[40+8:]), uint64(5))                     // size
+	assert.Equal(t, byteOrder.Uint32(entry[40+60:]), uint32(syscall.S_IFREG|0400)) // mode
+
+	var openBody encoder
+	openBody.u32(syscall.O_WRONLY)
+	openBody.u32(0)
+	errno, _ = s.call(opOpen, nodeID, openBody)
+	assert.Equal(t, errno, syscall.EROFS)
+
+	openBody = encoder{}
+	openBody.u32(syscall.O_RDONLY)
+	openBody.u32(0)
+	errno, open := s.call(opOpen, nodeID, openBody)
+	assert.Equal(t, errno, syscall.Errno(0))
+	fh := byteOrder.Uint64(open[0:])
+
+	errno, data := s.call(opRead, nodeID, fileBody(fh, 1, 3))
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, string(data), "alu")
+
+	errno, data = s.call(opRead, nodeID, fileBody(fh, 5, 10))
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, string(data), "")
+}
+
+func TestServer_createAndWrite(t *testing.T) {
+	files := map[string][]byte{}
+	s := newTestServer(t, false, files)
+
+	var body encoder
+	body.u32(syscall.O_WRONLY | syscall.O_CREAT)
+	body.u32(0600) // mode
+	body.u32(0)    // umask
+	body.u32(0)    // padding
+	body = append(body, "new\x00"...)
+	errno, reply := s.call(opCreate, rootID, body)
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, len(reply), 128+16)
+	nodeID := byteOrder.Uint64(reply[0:])
+	fh := byteOrder.Uint64(reply[128:])
+
+	errno, _ = s.call(opWrite, nodeID, append(fileBody(fh, 0, 5), "value"...))
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, len(files), 0)
+
+	errno, _ = s.call(opFlush, nodeID, fileBody(fh, 0, 0))
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, string(files["new"]), "value")
+}
+
+func TestServer_readdir(t *testing.T) {
+	s := newTestServer(t, true, map[string][]byte{"secret": []byte("value")})
+
+	errno, open := s.call(opOpendir, rootID, make([]byte, 8))
+	assert.Equal(t, errno, syscall.Errno(0))
+	fh := byteOrder.Uint64(open[0:])
+
+	errno, entries := s.call(opReaddir, rootID, fileBody(fh, 0, 4096))
+	assert.Equal(t, errno, syscall.Errno(0))
+
+	var names []string
+	for len(entries) > 0 {
+		nameLen := int(byteOrder.Uint32(entries[16:]))
+		names = append(names, string(entries[24:24+nameLen]))
+		entries = entries[align8(24+nameLen):]
+	}
+	assert.Equal(t, names, []string{".", "..", "secret"})
+
+	errno, entries = s.call(opReaddir, rootID, fileBody(fh, 3, 4096))
+	assert.Equal(t, errno, syscall.Errno(0))
+	assert.Equal(t, len(entries), 0)
+}
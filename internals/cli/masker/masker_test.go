@@ -79,6 +79,18 @@ func TestMasker(t *testing.T) {
 			},
 			expected: maskString + " " + maskString + " fo",
 		},
+		"mask at start of write": {
+			maskStrings: []string{"foo"},
+			inputFunc: func(w io.Writer) {
+				_, err := w.Write([]byte("test "))
+				assert.OK(t, err)
+				_, err = w.Write([]byte("foo"))
+				assert.OK(t, err)
+				_, err = w.Write([]byte(" foo"))
+				assert.OK(t, err)
+			},
+			expected: "test " + maskString + " " + maskString,
+		},
 		"within buffer delay": {
 			maskStrings: []string{"foo", "bar"},
 			inputFunc: func(w io.Writer) {
@@ -16,6 +16,9 @@ type stream struct {
 	matcher     *matcher
 	matches     matches
 	matchesLock sync.Mutex
+
+	// redacted is whether the last bytes written to the destination are the redaction text.
+	redacted bool
 }
 
 // Write implements the io.Writer interface for the stream.
@@ -66,18 +69,19 @@ func (s *stream) flush(n int) error {
 			// Get any unprocessed bytes before this match to the destination.
 			beforeMatch := s.buf.upToIndex(i)
 
-			_, err := s.dest.Write(beforeMatch)
+			err := s.write(beforeMatch)
 			if err != nil {
 				return err
 			}
 
-			// Only write the redaction text if there were bytes between this match and the previous match
-			// or this is the first flush for the buffer.
-			if len(beforeMatch) > 0 || s.buf.currentIndex == 0 {
+			// Only write the redaction text if there were bytes between this match and the previous match,
+			// so adjacent and overlapping matches are redacted once.
+			if !s.redacted {
 				_, err = s.dest.Write([]byte("<redacted by SecretHub>"))
 				if err != nil {
 					return err
 				}
+				s.redacted = true
 			}
 
 			// Drop all bytes until the end of the mask.
@@ -88,11 +92,18 @@ func (s *stream) flush(n int) error {
 	}
 
 	// Write all bytes after the last match.
-	_, err := s.dest.Write(s.buf.upToIndex(endIndex))
+	return s.write(s.buf.upToIndex(endIndex))
+}
+
+// write writes bytes that are not redacted to the destination.
+func (s *stream) write(p []byte) error {
+	_, err := s.dest.Write(p)
 	if err != nil {
 		return err
 	}
-
+	if len(p) > 0 {
+		s.redacted = false
+	}
 	return nil
 }
 
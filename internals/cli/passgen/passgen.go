@@ -0,0 +1,18 @@
+// Package passgen generates passwords that follow a template pattern or that are pronounceable,
+// for systems with constraints that a random string of characters cannot meet.
+package passgen
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+)
+
+// pick returns a random character from the given characters.
+func pick(reader io.Reader, characters string) (byte, error) {
+	i, err := rand.Int(reader, big.NewInt(int64(len(characters))))
+	if err != nil {
+		return 0, err
+	}
+	return characters[i.Int64()], nil
+}
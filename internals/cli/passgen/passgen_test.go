@@ -0,0 +1,69 @@
+package passgen
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestNewPattern(t *testing.T) {
+	cases := map[string]struct {
+		template string
+		expected *regexp.Regexp
+		err      error
+	}{
+		"recovery code": {
+			template: "Aaaa-9999-aaaa",
+			expected: regexp.MustCompile(`^[A-Z][a-z]{3}-[0-9]{4}-[a-z]{4}$`),
+		},
+		"alphanumeric and symbols": {
+			template: "***#",
+			expected: regexp.MustCompile(`^[a-zA-Z0-9]{3}[!@#$%^*\-_+=.,?]$`),
+		},
+		"escaped": {
+			template: `\A\9-99`,
+			expected: regexp.MustCompile(`^A9-[0-9]{2}$`),
+		},
+		"empty": {
+			template: "",
+			err:      ErrEmptyPattern,
+		},
+		"trailing escape": {
+			template: `99\`,
+			err:      ErrTrailingEscape,
+		},
+		"no placeholders": {
+			template: `XYZ-\9`,
+			err:      ErrNoPlaceholders,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pattern, err := NewPattern(tc.template)
+
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+
+			for i := 0; i < 100; i++ {
+				actual, err := pattern.Generate(pattern.Len())
+				assert.OK(t, err)
+				assert.Equal(t, tc.expected.Match(actual), true)
+			}
+		})
+	}
+}
+
+func TestPronounceable_Generate(t *testing.T) {
+	expected := regexp.MustCompile(`^([bdfghjkmnprstvz][aeiou])*[bdfghjkmnprstvz]$`)
+
+	for i := 0; i < 100; i++ {
+		actual, err := NewPronounceable().Generate(21)
+
+		assert.OK(t, err)
+		assert.Equal(t, expected.Match(actual), true)
+	}
+}
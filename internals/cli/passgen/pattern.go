@@ -0,0 +1,114 @@
+package passgen
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The characters a placeholder in a pattern is replaced with.
+const (
+	lowercase    = "abcdefghijklmnopqrstuvwxyz"
+	uppercase    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits       = "0123456789"
+	symbols      = "!@#$%^*-_+=.,?"
+	alphanumeric = lowercase + uppercase + digits
+)
+
+// placeholders maps the placeholders that can be used in a pattern to the characters they are replaced with.
+var placeholders = map[byte]string{
+	'a': lowercase,
+	'A': uppercase,
+	'9': digits,
+	'*': alphanumeric,
+	'#': symbols,
+}
+
+// Errors
+var (
+	ErrEmptyPattern   = errors.New("the pattern is empty")
+	ErrTrailingEscape = errors.New("the pattern ends with an escape character")
+	ErrNoPlaceholders = errors.New("the pattern does not contain any placeholders, so it would always generate the same value")
+)
+
+// Pattern generates passwords from a template. In the template, a is replaced with a random lowercase letter,
+// A with an uppercase letter, 9 with a digit, * with a letter or digit and # with a symbol.
+// Any other character is kept as is, as is a placeholder that is preceded by a backslash.
+// For example, Aaaa-9999-aaaa generates values like Kqzm-4821-tbwe.
+type Pattern struct {
+	// parts are the character sets of the characters of generated passwords.
+	// Literal characters are a set of one character.
+	parts []string
+	rand  io.Reader
+}
+
+// NewPattern parses the given template.
+func NewPattern(template string) (Pattern, error) {
+	if template == "" {
+		return Pattern{}, ErrEmptyPattern
+	}
+
+	var parts []string
+	hasPlaceholder := false
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if c == '\\' {
+			i++
+			if i == len(template) {
+				return Pattern{}, ErrTrailingEscape
+			}
+			parts = append(parts, string(template[i]))
+			continue
+		}
+
+		characters, ok := placeholders[c]
+		if ok {
+			hasPlaceholder = true
+			parts = append(parts, characters)
+		} else {
+			parts = append(parts, string(c))
+		}
+	}
+	if !hasPlaceholder {
+		return Pattern{}, ErrNoPlaceholders
+	}
+
+	return Pattern{
+		parts: parts,
+		rand:  rand.Reader,
+	}, nil
+}
+
+// Len returns the length of the passwords the pattern generates.
+func (p Pattern) Len() int {
+	return len(p.parts)
+}
+
+// Generate returns a password that follows the pattern. The length of the password is
+// set by the pattern, so n must be equal to the length of the pattern.
+func (p Pattern) Generate(n int) ([]byte, error) {
+	if n != p.Len() {
+		return nil, fmt.Errorf("the pattern generates %d characters, not %d", p.Len(), n)
+	}
+
+	res := make([]byte, len(p.parts))
+	for i, characters := range p.parts {
+		c, err := pick(p.rand, characters)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = c
+	}
+	return res, nil
+}
+
+// Entropy returns the number of bits of entropy of the passwords the pattern generates.
+func (p Pattern) Entropy() float64 {
+	var res float64
+	for _, characters := range p.parts {
+		res += math.Log2(float64(len(characters)))
+	}
+	return res
+}
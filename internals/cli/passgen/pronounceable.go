@@ -0,0 +1,53 @@
+package passgen
+
+import (
+	"crypto/rand"
+	"io"
+	"math"
+)
+
+// The letters pronounceable passwords are made of. Letters that sound alike when read out loud
+// or that can be confused with digits, such as c and k or l and 1, are left out.
+const (
+	consonants = "bdfghjkmnprstvz"
+	vowels     = "aeiou"
+)
+
+// Pronounceable generates passwords of lowercase letters that alternate between consonants and vowels,
+// so they can be read out loud or typed over, e.g. as recovery codes. Every pair of characters adds
+// about 6.2 bits of entropy, so a pronounceable password must be about twice as long as an
+// alphanumeric one to be as strong.
+type Pronounceable struct {
+	rand io.Reader
+}
+
+// NewPronounceable creates a generator of pronounceable passwords.
+func NewPronounceable() Pronounceable {
+	return Pronounceable{
+		rand: rand.Reader,
+	}
+}
+
+// Generate returns a pronounceable password of n characters.
+func (p Pronounceable) Generate(n int) ([]byte, error) {
+	res := make([]byte, n)
+	for i := range res {
+		characters := consonants
+		if i%2 == 1 {
+			characters = vowels
+		}
+
+		c, err := pick(p.rand, characters)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = c
+	}
+	return res, nil
+}
+
+// Entropy returns the number of bits of entropy of a pronounceable password of n characters.
+func (p Pronounceable) Entropy(n int) float64 {
+	consonantCount := (n + 1) / 2
+	return float64(consonantCount)*math.Log2(float64(len(consonants))) + float64(n-consonantCount)*math.Log2(float64(len(vowels)))
+}
@@ -0,0 +1,129 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// barWidth is the number of characters of the bar that is drawn on terminals.
+const barWidth = 30
+
+// Counter reports the progress of an operation on a known number of items.
+// It is safe to mark items as done from multiple goroutines.
+type Counter interface {
+	// Done marks an item as done.
+	Done()
+	// Fail marks an item as failed.
+	Fail()
+	// Finish reports the final progress. It is a no-op when the final progress has already been reported.
+	Finish()
+}
+
+// NewCounter creates a new Counter that reports the progress of the operation with the given
+// description on total items to w. When redraw is set, w must be a terminal: a progress bar is
+// drawn on a single line that is redrawn on every update. Otherwise, a line is written for every
+// tenth of the items that is done, so logs are not flooded.
+func NewCounter(w io.Writer, description string, total int, redraw bool) Counter {
+	return &counter{
+		w:           w,
+		description: description,
+		total:       total,
+		redraw:      redraw,
+	}
+}
+
+// NewDiscardCounter creates a new Counter that does not report anything.
+func NewDiscardCounter() Counter {
+	return discardCounter{}
+}
+
+type counter struct {
+	mutex       sync.Mutex
+	w           io.Writer
+	description string
+	total       int
+	done        int
+	failed      int
+	reported    int
+	finished    bool
+	redraw      bool
+}
+
+// Done marks an item as done.
+func (c *counter) Done() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.done++
+	c.report()
+}
+
+// Fail marks an item as failed.
+func (c *counter) Fail() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.done++
+	c.failed++
+	c.report()
+}
+
+// Finish reports the final progress.
+func (c *counter) Finish() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.finished {
+		return
+	}
+	c.finished = true
+	if c.redraw {
+		fmt.Fprintf(c.w, "\r%s\n", c.line())
+	} else if c.reported != c.done {
+		fmt.Fprintln(c.w, c.line())
+	}
+}
+
+// report writes the progress after an item is done.
+func (c *counter) report() {
+	if c.finished {
+		return
+	}
+	if c.redraw {
+		fmt.Fprintf(c.w, "\r%s", c.line())
+		return
+	}
+	if c.total > 0 && c.done*10/c.total > c.reported*10/c.total {
+		c.reported = c.done
+		fmt.Fprintln(c.w, c.line())
+	}
+}
+
+// line returns the description of the progress.
+func (c *counter) line() string {
+	res := fmt.Sprintf("%s: %d/%d", c.description, c.done, c.total)
+	if c.redraw {
+		filled := barWidth
+		if c.total > 0 {
+			filled = c.done * barWidth / c.total
+		}
+		if filled > barWidth {
+			filled = barWidth
+		}
+		res = fmt.Sprintf("%s [%s%s] %d/%d", c.description, strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled), c.done, c.total)
+	}
+	if c.failed > 0 {
+		res += fmt.Sprintf(", %d failed", c.failed)
+	}
+	return res
+}
+
+type discardCounter struct{}
+
+// Done does nothing.
+func (discardCounter) Done() {}
+
+// Fail does nothing.
+func (discardCounter) Fail() {}
+
+// Finish does nothing.
+func (discardCounter) Finish() {}
@@ -0,0 +1,64 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCounter(t *testing.T) {
+	cases := map[string]struct {
+		total    int
+		failed   int
+		redraw   bool
+		expected string
+	}{
+		"lines": {
+			total: 20,
+			expected: "Importing secrets: 2/20\nImporting secrets: 4/20\nImporting secrets: 6/20\nImporting secrets: 8/20\nImporting secrets: 10/20\n" +
+				"Importing secrets: 12/20\nImporting secrets: 14/20\nImporting secrets: 16/20\nImporting secrets: 18/20\nImporting secrets: 20/20\n",
+		},
+		"lines with failures": {
+			total:    3,
+			failed:   1,
+			expected: "Importing secrets: 1/3, 1 failed\nImporting secrets: 2/3, 1 failed\nImporting secrets: 3/3, 1 failed\n",
+		},
+		"redraw": {
+			total:  2,
+			redraw: true,
+			expected: "\rImporting secrets [===============               ] 1/2" +
+				"\rImporting secrets [==============================] 2/2" +
+				"\rImporting secrets [==============================] 2/2\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			counter := NewCounter(&buf, "Importing secrets", tc.total, tc.redraw)
+
+			for i := 0; i < tc.failed; i++ {
+				counter.Fail()
+			}
+			for i := tc.failed; i < tc.total; i++ {
+				counter.Done()
+			}
+			counter.Finish()
+			counter.Finish()
+
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestCounter_FinishEarly(t *testing.T) {
+	var buf bytes.Buffer
+	counter := NewCounter(&buf, "Exporting secrets", 100, false)
+
+	counter.Done()
+	counter.Finish()
+	counter.Done()
+
+	assert.Equal(t, buf.String(), "Exporting secrets: 1/100\n")
+}
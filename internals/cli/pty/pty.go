@@ -0,0 +1,11 @@
+// Package pty runs commands in a pseudo-terminal, so interactive programs such as
+// shells behave as if they are attached to a terminal while their output can be
+// read, and rewritten, before it is shown.
+package pty
+
+import (
+	"errors"
+)
+
+// ErrNotSupported is returned on platforms on which pseudo-terminals are not supported.
+var ErrNotSupported = errors.New("pseudo-terminals are only supported on Linux")
@@ -0,0 +1,100 @@
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Start starts the command with a new pseudo-terminal as its controlling terminal
+// and as its stdin, stdout and stderr. It returns the master side of the terminal,
+// which reads what the command writes and writes what the command reads.
+// Reads return an error once the command and its children have closed the terminal.
+func Start(c *exec.Cmd) (*os.File, error) {
+	master, slave, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close()
+
+	c.Stdin = slave
+	c.Stdout = slave
+	c.Stderr = slave
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	c.SysProcAttr.Setsid = true
+	c.SysProcAttr.Setctty = true
+	// Ctty is the file descriptor of the terminal in the child, which is stdin.
+	c.SysProcAttr.Ctty = 0
+
+	err = c.Start()
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+// open opens a new pseudo-terminal and returns its master and slave side.
+func open() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, os.NewSyscallError("unlockpt", err)
+	}
+	n, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, nil, os.NewSyscallError("ptsname", err)
+	}
+
+	slave, err := os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// WatchSize gives the pseudo-terminal the size of the terminal, now and whenever the
+// terminal is resized, until the returned function is called. Nothing happens when
+// the terminal is not a terminal.
+func WatchSize(terminal *os.File, pty *os.File) func() {
+	resize := func() {
+		size, err := unix.IoctlGetWinsize(int(terminal.Fd()), unix.TIOCGWINSZ)
+		if err != nil {
+			return
+		}
+		_ = unix.IoctlSetWinsize(int(pty.Fd()), unix.TIOCSWINSZ, size)
+	}
+	resize()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				resize()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
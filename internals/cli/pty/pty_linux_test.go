@@ -0,0 +1,31 @@
+package pty
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestStart(t *testing.T) {
+	c := exec.Command("sh", "-c", `test -t 0 && test -t 1 && echo "is a terminal" || echo "not a terminal"; read -r line; echo "read $line"`)
+
+	master, err := Start(c)
+	assert.OK(t, err)
+	defer master.Close()
+
+	_, err = master.Write([]byte("hello\n"))
+	assert.OK(t, err)
+
+	// Reading returns an error when the command has exited and the terminal is closed.
+	out, _ := ioutil.ReadAll(master)
+	assert.OK(t, c.Wait())
+
+	// The terminal translates newlines to CRLF. It also echoes the input, which can be
+	// before or after the first line of the command.
+	assert.Equal(t, strings.Contains(string(out), "is a terminal\r\n"), true)
+	assert.Equal(t, strings.Contains(string(out), "hello\r\n"), true)
+	assert.Equal(t, strings.HasSuffix(string(out), "read hello\r\n"), true)
+}
@@ -0,0 +1,18 @@
+// +build !linux
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Start is not supported on this platform.
+func Start(c *exec.Cmd) (*os.File, error) {
+	return nil, ErrNotSupported
+}
+
+// WatchSize is not supported on this platform, so it does nothing.
+func WatchSize(terminal *os.File, pty *os.File) func() {
+	return func() {}
+}
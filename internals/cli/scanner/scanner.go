@@ -0,0 +1,276 @@
+// Package scanner finds secrets that are leaked in files or in the history of a git repository.
+//
+// Lines are matched against patterns of known credentials, such as AWS access keys and private
+// keys, and against known secret values. Words that are long and random enough to be a key or
+// a token are reported as high-entropy strings.
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RuleHighEntropy is the rule of findings that are words with a high entropy.
+	RuleHighEntropy = "high-entropy"
+	// RuleKnownValue is the rule of findings that are known secret values.
+	RuleKnownValue = "known-value"
+
+	// minEntropyLength is the minimum length of a word for it to be checked for its entropy.
+	minEntropyLength = 20
+	// hexEntropy and base64Entropy are the minimum Shannon entropies in bits per character of
+	// hex encoded and other words to be reported.
+	hexEntropy    = 3.0
+	base64Entropy = 4.5
+	// minKnownValueLength is the minimum length of known values, as shorter values would match too often.
+	minKnownValueLength = 6
+	// maxLineLength is the maximum length of a scanned line, which is far longer than lines in source code.
+	maxLineLength = 1 << 20
+	// sniffLength is the number of bytes at the start of a file that are checked to detect binary files.
+	sniffLength = 8000
+)
+
+// patterns are the rules for credentials of which the format is known.
+var patterns = []struct {
+	rule    string
+	pattern *regexp.Regexp
+}{
+	{"aws-access-key-id", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z-]{10,}`)},
+	{"stripe-key", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"private-key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----`)},
+}
+
+// words matches the words that are checked for their entropy, which are base64 encoded
+// strings with optional padding, so that the value of an assignment such as KEY=value is a word.
+var words = regexp.MustCompile(`[A-Za-z0-9+/_-]{` + strconv.Itoa(minEntropyLength) + `,}=*`)
+
+// Finding is a possible secret.
+type Finding struct {
+	// Path is the path of the file, relative to the scanned directory.
+	Path string
+	// Commit is the git commit that added the line, or empty when a file is scanned.
+	Commit string
+	Line   int
+	Rule   string
+	// Match is the text that is found.
+	Match string
+	// Secret is the path of the secret with the value that is found, if any.
+	Secret string
+}
+
+// Scanner finds secrets in text.
+type Scanner struct {
+	known map[string]string
+}
+
+// New returns a Scanner that also finds the given known values, which are mapped to the names
+// of their secrets. Values shorter than 6 characters are ignored.
+func New(known map[string]string) *Scanner {
+	s := &Scanner{known: map[string]string{}}
+	for value, secret := range known {
+		if len(value) >= minKnownValueLength {
+			s.known[value] = secret
+		}
+	}
+	return s
+}
+
+// ScanLine returns the findings in a line. A known value is reported instead of the other
+// findings that overlap with it, as it is a confirmed leak.
+func (s *Scanner) ScanLine(line string) []Finding {
+	var findings []Finding
+	var knownValues []string
+	for value, secret := range s.known {
+		if strings.Contains(line, value) {
+			findings = append(findings, Finding{Rule: RuleKnownValue, Match: value, Secret: secret})
+			knownValues = append(knownValues, value)
+		}
+	}
+	isKnown := func(match string) bool {
+		for _, value := range knownValues {
+			if strings.Contains(value, match) || strings.Contains(match, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	matched := map[string]bool{}
+	for _, p := range patterns {
+		for _, match := range p.pattern.FindAllString(line, -1) {
+			matched[match] = true
+			if !isKnown(match) {
+				findings = append(findings, Finding{Rule: p.rule, Match: match})
+			}
+		}
+	}
+	for _, word := range words.FindAllString(line, -1) {
+		if matched[word] || isKnown(word) || !isHighEntropy(word) {
+			continue
+		}
+		findings = append(findings, Finding{Rule: RuleHighEntropy, Match: word})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return strings.Index(line, findings[i].Match) < strings.Index(line, findings[j].Match)
+	})
+	return findings
+}
+
+// Scan returns the findings in the lines read from r, which are reported at the path.
+func (s *Scanner) Scan(r io.Reader, path string) ([]Finding, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+	var findings []Finding
+	for line := 1; scanner.Scan(); line++ {
+		for _, finding := range s.ScanLine(scanner.Text()) {
+			finding.Path = path
+			finding.Line = line
+			findings = append(findings, finding)
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// ScanDir returns the findings in the files in the directory and its subdirectories.
+// Binary files and the .git directory are skipped.
+func (s *Scanner) ScanDir(root string) ([]Finding, error) {
+	var findings []Finding
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		fileFindings, err := s.scanFile(path, filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		findings = append(findings, fileFindings...)
+		return nil
+	})
+	return findings, err
+}
+
+// scanFile returns the findings in the file, which are reported at the given name.
+func (s *Scanner) scanFile(path string, name string) ([]Finding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	start, err := r.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if bytes.IndexByte(start, 0) >= 0 {
+		return nil, nil
+	}
+	return s.Scan(r, name)
+}
+
+// ScanGitLog returns the findings in the lines added in the output of
+// git log --patch --unified=0 --format="commit %H".
+func (s *Scanner) ScanGitLog(r io.Reader) ([]Finding, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+	var findings []Finding
+	var commit, path string
+	line := 0
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "commit "):
+			commit = strings.TrimPrefix(text, "commit ")
+			path = ""
+		case strings.HasPrefix(text, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+		case strings.HasPrefix(text, "@@ "):
+			line = hunkStart(text)
+		case strings.HasPrefix(text, "+") && path != "":
+			for _, finding := range s.ScanLine(text[1:]) {
+				finding.Path = path
+				finding.Commit = commit
+				finding.Line = line
+				findings = append(findings, finding)
+			}
+			line++
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// hunkStart returns the number of the first added line of a hunk with a header
+// such as @@ -1,2 +3,4 @@.
+func hunkStart(header string) int {
+	for _, field := range strings.Fields(header) {
+		if strings.HasPrefix(field, "+") {
+			n, err := strconv.Atoi(strings.SplitN(field[1:], ",", 2)[0])
+			if err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// isHighEntropy returns whether the word is random enough to be a key or a token.
+func isHighEntropy(word string) bool {
+	threshold := base64Entropy
+	if isHex(word) {
+		threshold = hexEntropy
+	}
+	return entropy(word) >= threshold
+}
+
+// isHex returns whether the word only consists of hexadecimal characters.
+func isHex(word string) bool {
+	for _, c := range word {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// entropy returns the Shannon entropy of the word in bits per character.
+func entropy(word string) float64 {
+	counts := map[rune]int{}
+	for _, c := range word {
+		counts[c]++
+	}
+
+	result := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(len(word))
+		result -= p * math.Log2(p)
+	}
+	return result
+}
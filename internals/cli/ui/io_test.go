@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"go/build"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// TestIOBuildConstraints checks that every platform gets exactly one implementation of NewUserIO.
+func TestIOBuildConstraints(t *testing.T) {
+	for _, goos := range []string{"darwin", "linux", "freebsd", "openbsd", "netbsd", "solaris", "windows"} {
+		t.Run(goos, func(t *testing.T) {
+			ctx := build.Default
+			ctx.GOOS = goos
+
+			unix, err := ctx.MatchFile(".", "io_unix.go")
+			assert.OK(t, err)
+			windows, err := ctx.MatchFile(".", "io_windows.go")
+			assert.OK(t, err)
+
+			assert.Equal(t, unix, goos != "windows")
+			assert.Equal(t, windows, goos == "windows")
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// +build !windows
+
+package ui
+
+import (
+	"os"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestTTYIO_Prompts(t *testing.T) {
+	pipe, _, err := os.Pipe()
+	assert.OK(t, err)
+	defer pipe.Close()
+
+	// The null device is a character device, so it is not piped, like a terminal.
+	terminal, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	assert.OK(t, err)
+	defer terminal.Close()
+
+	tty, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	assert.OK(t, err)
+	defer tty.Close()
+
+	cases := map[string]struct {
+		input    *os.File
+		output   *os.File
+		expected *os.File
+	}{
+		"not piped": {
+			input:    terminal,
+			output:   terminal,
+			expected: terminal,
+		},
+		"input piped": {
+			input:    pipe,
+			output:   terminal,
+			expected: tty,
+		},
+		"output piped": {
+			input:    terminal,
+			output:   pipe,
+			expected: tty,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := ttyIO{
+				input:  tc.input,
+				output: tc.output,
+				tty:    tty,
+			}
+
+			in, out, err := io.Prompts()
+
+			assert.OK(t, err)
+			// The files are compared by identity, as the terminal and the tty are the same file.
+			if in != tc.expected || out != tc.expected {
+				t.Error("the prompts do not use the expected file")
+			}
+		})
+	}
+}
@@ -13,14 +13,54 @@ import (
 type windowsIO struct {
 	standardIO
 	coloredOutput io.Writer
+	// conin and conout are the input and output of the console, which are
+	// nil when the process is not attached to a console.
+	conin  *os.File
+	conout *os.File
 }
 
-// NewUserIO creates a new windowsIO.
+// NewUserIO creates a new windowsIO. The console is opened when it is available,
+// so the user can still be prompted when the input or output is piped.
 func NewUserIO() IO {
-	return windowsIO{
+	io := windowsIO{
 		standardIO:    newStdUserIO(),
 		coloredOutput: colorable.NewColorable(os.Stdout),
 	}
+
+	conin, err := os.OpenFile("CONIN$", os.O_RDWR, 0)
+	if err != nil {
+		return io
+	}
+	conout, err := os.OpenFile("CONOUT$", os.O_RDWR, 0)
+	if err != nil {
+		_ = conin.Close()
+		return io
+	}
+	io.conin = conin
+	io.conout = conout
+	return io
+}
+
+// Prompts returns Stdin and Stdout when both input and output are not piped.
+// When either input or output is piped, Prompts bypasses stdin and stdout by returning
+// the console, like ssh and sudo do. When there is no console, it returns an error.
+func (o windowsIO) Prompts() (io.Reader, io.Writer, error) {
+	if o.IsOutputPiped() || o.IsInputPiped() {
+		if o.conin == nil {
+			return nil, nil, ErrCannotAsk
+		}
+		return o.conin, colorable.NewColorable(o.conout), nil
+	}
+	return o.input, o.Output(), nil
+}
+
+// ReadSecret reads a line from the console without echoing it when the input is piped
+// and from stdin otherwise.
+func (o windowsIO) ReadSecret() ([]byte, error) {
+	if o.IsInputPiped() && o.conin != nil {
+		return readSecret(o.conin)
+	}
+	return readSecret(o.input)
 }
 
 // Stdout returns the standardIO's Output.
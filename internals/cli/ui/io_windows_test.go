@@ -0,0 +1,54 @@
+// +build windows
+
+package ui
+
+import (
+	"os"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestWindowsIO_Prompts(t *testing.T) {
+	pipe, _, err := os.Pipe()
+	assert.OK(t, err)
+	defer pipe.Close()
+
+	conin, _, err := os.Pipe()
+	assert.OK(t, err)
+	defer conin.Close()
+
+	cases := map[string]struct {
+		conin    *os.File
+		expected *os.File
+		err      error
+	}{
+		"console": {
+			conin:    conin,
+			expected: conin,
+		},
+		"no console": {
+			err: ErrCannotAsk,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := windowsIO{
+				standardIO: standardIO{
+					input:  pipe,
+					output: os.Stdout,
+				},
+				conin:  tc.conin,
+				conout: os.Stdout,
+			}
+
+			in, _, err := io.Prompts()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil && in != tc.expected {
+				t.Error("the prompts are not read from the console")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package demo
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secretpath"
+)
+
+// CleanCommand removes everything that is created by the init command.
+type CleanCommand struct {
+	repo  api.RepoPath
+	force bool
+
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewCleanCommand creates a new CleanCommand.
+func NewCleanCommand(io ui.IO, newClient newClientFunc) *CleanCommand {
+	return &CleanCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CleanCommand) Register(r command.Registerer) {
+	clause := r.Command("clean", "Remove the repository, secrets and service accounts used for the demo application.")
+	clause.HelpLong("demo clean permanently removes the repository created by demo init, together with the secrets and service accounts in it.")
+
+	clause.Flag("repo", "The path of the demo repository to remove. Defaults to the "+defaultDemoRepo+" repo in your personal namespace.").SetValue(&cmd.repo)
+	clause.Flag("force", "Ignore confirmation and fail instead of prompt for missing arguments.").Short('f').BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run handles the command with the options as specified in the command.
+func (cmd *CleanCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	repoPath := cmd.repo.Value()
+	if cmd.repo == "" {
+		me, err := client.Me().GetUser()
+		if err != nil {
+			return err
+		}
+		repoPath = secretpath.Join(me.Username, defaultDemoRepo)
+	}
+
+	_, err = client.Repos().Get(repoPath)
+	if api.IsErrNotFound(err) {
+		fmt.Fprintf(cmd.io.Output(), "The demo repo %s does not exist. Nothing to clean up.\n", repoPath)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	services, err := client.Services().List(repoPath)
+	if err != nil {
+		return err
+	}
+
+	if !cmd.force {
+		question := fmt.Sprintf("This will permanently remove the demo repo %s and all its secrets", repoPath)
+		if len(services) > 0 {
+			question += fmt.Sprintf(" and %d service account(s)", len(services))
+		}
+		confirmed, err := ui.AskYesNo(cmd.io, question+". Do you want to continue?", ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return ErrAborted
+		}
+	}
+
+	for _, service := range services {
+		_, err = client.Services().Delete(service.ServiceID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Removed service account %s\n", service.ServiceID)
+	}
+
+	err = client.Repos().Delete(repoPath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.io.Output(), "Removed the demo repo %s\n", repoPath)
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package demo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// fakeMeClient is a fake client of which the Me service returns the given user.
+type fakeMeClient struct {
+	fakeclient.Client
+	user *api.User
+}
+
+// Me returns a MeService of which GetUser returns the user of the client.
+func (c fakeMeClient) Me() secrethub.MeService {
+	return fakeMeService{user: c.user}
+}
+
+// fakeMeService is a MeService of which only GetUser is implemented.
+type fakeMeService struct {
+	secrethub.MeService
+	user *api.User
+}
+
+// GetUser returns the user of the service.
+func (s fakeMeService) GetUser() (*api.User, error) {
+	return s.user, nil
+}
+
+func TestCleanCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		repo            api.RepoPath
+		force           bool
+		promptIn        string
+		repoErr         error
+		services        []*api.Service
+		deletedRepos    []string
+		deletedServices []string
+		out             string
+		err             error
+	}{
+		"default repo": {
+			force:        true,
+			deletedRepos: []string{"dev1/demo"},
+			out:          "Removed the demo repo dev1/demo\n",
+		},
+		"repo with services": {
+			repo:  "company/demo",
+			force: true,
+			services: []*api.Service{
+				{ServiceID: "s-first"},
+				{ServiceID: "s-second"},
+			},
+			deletedRepos:    []string{"company/demo"},
+			deletedServices: []string{"s-first", "s-second"},
+			out:             "Removed service account s-first\nRemoved service account s-second\nRemoved the demo repo company/demo\n",
+		},
+		"confirmed": {
+			repo:         "company/demo",
+			promptIn:     "y\n",
+			deletedRepos: []string{"company/demo"},
+			out:          "Removed the demo repo company/demo\n",
+		},
+		"declined": {
+			repo:     "company/demo",
+			services: []*api.Service{{ServiceID: "s-first"}},
+			promptIn: "n\n",
+			out:      "Aborting.\n",
+			err:      ErrAborted,
+		},
+		"repo does not exist": {
+			repo:    "company/demo",
+			force:   true,
+			repoErr: api.ErrRepoNotFound("company/demo"),
+			out:     "The demo repo company/demo does not exist. Nothing to clean up.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deletedRepos []string
+			var deletedServices []string
+			client := fakeMeClient{
+				Client: fakeclient.Client{
+					RepoService: &fakeclient.RepoService{
+						GetFunc: func(path string) (*api.Repo, error) {
+							return &api.Repo{}, tc.repoErr
+						},
+						DeleteFunc: func(path string) error {
+							deletedRepos = append(deletedRepos, path)
+							return nil
+						},
+					},
+					ServiceService: &fakeclient.ServiceService{
+						ListFunc: func(path string) ([]*api.Service, error) {
+							return tc.services, nil
+						},
+						DeleteFunc: func(id string) (*api.RevokeRepoResponse, error) {
+							deletedServices = append(deletedServices, id)
+							return &api.RevokeRepoResponse{}, nil
+						},
+					},
+				},
+				user: &api.User{Username: "dev1"},
+			}
+
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.promptIn)
+			cmd := NewCleanCommand(io, func() (secrethub.ClientInterface, error) {
+				return client, nil
+			})
+			cmd.repo = tc.repo
+			cmd.force = tc.force
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, deletedRepos, tc.deletedRepos)
+			assert.Equal(t, deletedServices, tc.deletedServices)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
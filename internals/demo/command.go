@@ -1,8 +1,6 @@
 package demo
 
 import (
-	"github.com/secrethub/demo-app/cli"
-
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 )
@@ -27,5 +25,6 @@ func (cmd *Command) Register(r command.Registerer) {
 	clause.Hidden()
 
 	NewInitCommand(cmd.io, cmd.newClient).Register(clause)
-	cli.NewServeCommand(cmd.io).Register(clause)
+	NewCleanCommand(cmd.io, cmd.newClient).Register(clause)
+	NewServeCommand(cmd.io).Register(clause)
 }
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -19,7 +20,8 @@ type newClientFunc func() (secrethub.ClientInterface, error)
 const defaultDemoRepo = "demo"
 
 type InitCommand struct {
-	repo api.RepoPath
+	repo     api.RepoPath
+	scenario string
 
 	io        ui.IO
 	newClient newClientFunc
@@ -35,15 +37,27 @@ func NewInitCommand(io ui.IO, newClient newClientFunc) *InitCommand {
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *InitCommand) Register(r command.Registerer) {
 	clause := r.Command("init", "Create the secrets necessary to connect with the demo application.")
-	clause.HelpLong("demo init creates a repository with the username and password needed to connect to the demo API.")
+	clause.HelpLong("demo init creates a repository with the username and password needed to connect to the demo API.\n\n" +
+		"With --scenario, the repository is seeded with a realistic directory tree instead, with secrets for multiple environments " +
+		"and service accounts that have access to part of them, and a list of commands to try out on it is printed.")
 
 	clause.Flag("repo", "The path of the repository to create. Defaults to a "+defaultDemoRepo+" repo in your personal namespace.").SetValue(&cmd.repo)
+	clause.Flag("scenario", "Seed the repository with the secrets and service accounts of a scenario. Options are: "+strings.Join(scenarioNames(), ", ")+".").HintOptions(scenarioNames()...).StringVar(&cmd.scenario)
 
 	command.BindAction(clause, cmd.Run)
 }
 
 // Run handles the command with the options as specified in the command.
 func (cmd *InitCommand) Run() error {
+	var s scenario
+	if cmd.scenario != "" {
+		var err error
+		s, err = getScenario(cmd.scenario)
+		if err != nil {
+			return err
+		}
+	}
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
@@ -70,14 +84,17 @@ func (cmd *InitCommand) Run() error {
 		return err
 	}
 
+	if cmd.scenario != "" {
+		return cmd.seed(client, s, repoPath)
+	}
+
 	usernamePath := secretpath.Join(repoPath, "username")
 	_, err = client.Secrets().Write(usernamePath, []byte(username))
 	if err != nil {
 		return err
 	}
 
-	h := hmac.New(sha256.New, []byte("this-is-no-good-way-to-generate-a-password-that-is-why-we-only-use-it-for-demo-purposes"))
-	password := base64.RawStdEncoding.EncodeToString(h.Sum([]byte(username)))[:20]
+	password := demoPassword(username)
 
 	passwordPath := secretpath.Join(repoPath, "password")
 	_, err = client.Secrets().Write(passwordPath, []byte(password))
@@ -89,3 +106,34 @@ func (cmd *InitCommand) Run() error {
 
 	return nil
 }
+
+// demoPassword returns the password of the demo API for the given username.
+// The demo API derives it in the same way, which is why the offline demo app can check it.
+func demoPassword(username string) string {
+	h := hmac.New(sha256.New, []byte("this-is-no-good-way-to-generate-a-password-that-is-why-we-only-use-it-for-demo-purposes"))
+	return base64.RawStdEncoding.EncodeToString(h.Sum([]byte(username)))[:20]
+}
+
+// seed seeds the repository with the scenario and prints what has been created.
+func (cmd *InitCommand) seed(client secrethub.ClientInterface, s scenario, repoPath string) error {
+	secretPaths, serviceIDs, err := s.seed(client, repoPath)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.io.Output()
+	fmt.Fprintf(out, "Created the %s repo with %s.\n\n", repoPath, s.description)
+	fmt.Fprintf(out, "Secrets:\n")
+	for _, path := range secretPaths {
+		fmt.Fprintf(out, "  %s\n", path)
+	}
+	fmt.Fprintf(out, "\nService accounts:\n")
+	for i, id := range serviceIDs {
+		service := s.services[i]
+		fmt.Fprintf(out, "  %s (%s) with %s access on %s\n", id, service.description, service.permission, secretpath.Join(repoPath, service.dir))
+	}
+	fmt.Fprintln(out, "\nThe credentials of the service accounts are not stored, so they are for show only.")
+	fmt.Fprintln(out)
+	s.printTour(out, repoPath)
+	return nil
+}
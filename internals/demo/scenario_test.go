@@ -0,0 +1,120 @@
+package demo
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestInitCommand_Run_Scenario(t *testing.T) {
+	var createdRepos []string
+	var createdDirs []string
+	written := map[string]string{}
+	var services []string
+	rules := map[string]string{}
+
+	client := fakeclient.Client{
+		RepoService: &fakeclient.RepoService{
+			CreateFunc: func(path string) (*api.Repo, error) {
+				createdRepos = append(createdRepos, path)
+				return &api.Repo{}, nil
+			},
+		},
+		DirService: &fakeclient.DirService{
+			CreateFunc: func(path string) (*api.Dir, error) {
+				createdDirs = append(createdDirs, path)
+				return &api.Dir{}, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				written[path] = string(data)
+				return &api.SecretVersion{Version: 1}, nil
+			},
+		},
+		ServiceService: &fakeclient.ServiceService{
+			CreateFunc: func(path string, description string, credentialCreator credentials.Creator) (*api.Service, error) {
+				services = append(services, description)
+				return &api.Service{ServiceID: "s-" + strings.Replace(description, " ", "-", -1)}, nil
+			},
+		},
+		AccessRuleService: &fakeclient.AccessRuleService{
+			SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+				rules[accountName] = permission + " " + path
+				return &api.AccessRule{}, nil
+			},
+		},
+	}
+
+	io := fakeui.NewIO(t)
+	cmd := NewInitCommand(io, func() (secrethub.ClientInterface, error) {
+		return client, nil
+	})
+	cmd.repo = "company/demo"
+	cmd.scenario = scenarioCI
+
+	err := cmd.Run()
+	assert.OK(t, err)
+
+	assert.Equal(t, createdRepos, []string{"company/demo"})
+
+	// Every directory is created once, before the secrets in it are written.
+	sort.Strings(createdDirs)
+	assert.Equal(t, createdDirs, []string{
+		"company/demo/build",
+		"company/demo/build/docker",
+		"company/demo/build/npm",
+		"company/demo/deploy",
+		"company/demo/deploy/aws",
+		"company/demo/deploy/slack",
+		"company/demo/integration-tests",
+		"company/demo/integration-tests/api",
+		"company/demo/integration-tests/db",
+	})
+
+	assert.Equal(t, len(written), len(scenarios[scenarioCI].secrets))
+	for path, value := range scenarios[scenarioCI].secrets {
+		actual, ok := written["company/demo/"+path]
+		if !ok {
+			t.Errorf("secret %s has not been written", path)
+		} else if value != "" && actual != value {
+			t.Errorf("secret %s has value %q, expected %q", path, actual, value)
+		} else if value == "" && len(actual) != generatedLength {
+			t.Errorf("secret %s has a generated value of length %d, expected %d", path, len(actual), generatedLength)
+		}
+	}
+
+	assert.Equal(t, services, []string{"CI build job", "CI deploy job"})
+	assert.Equal(t, rules, map[string]string{
+		"s-CI-build-job":  "read company/demo/build",
+		"s-CI-deploy-job": "read company/demo/deploy",
+	})
+
+	out := io.Out.String()
+	if !strings.Contains(out, "  s-CI-deploy-job (CI deploy job) with read access on company/demo/deploy\n") {
+		t.Errorf("output does not list the created service accounts:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "  secrethub audit company/demo\n  secrethub demo clean --repo company/demo\n") {
+		t.Errorf("output does not end with the tour:\n%s", out)
+	}
+}
+
+func TestInitCommand_Run_UnknownScenario(t *testing.T) {
+	cmd := NewInitCommand(fakeui.NewIO(t), func() (secrethub.ClientInterface, error) {
+		t.Fatal("no client should be created for an unknown scenario")
+		return nil, nil
+	})
+	cmd.scenario = "unknown"
+
+	err := cmd.Run()
+
+	assert.Equal(t, err, ErrUnknownScenario("unknown", "ci, k8s, webapp"))
+}
@@ -0,0 +1,175 @@
+package demo
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/secrethub/demo-app/app"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// ServeCommand runs the demo application.
+type ServeCommand struct {
+	io ui.IO
+
+	host    string
+	port    int
+	offline bool
+}
+
+// NewServeCommand creates a new ServeCommand.
+func NewServeCommand(io ui.IO) *ServeCommand {
+	return &ServeCommand{
+		io: io,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ServeCommand) Register(r command.Registerer) {
+	clause := r.Command("serve", "Runs the secrethub example by serving a web page.")
+	clause.HelpLong("demo serve runs a web app that connects to the demo API with the username and password " +
+		"in the DEMO_USERNAME and DEMO_PASSWORD environment variables.\n\n" +
+		"With --offline, the web app does not connect to the demo API, but checks the credentials itself. " +
+		"It does not load anything from the internet either, so it also works without an internet connection.")
+
+	clause.Flag("host", "The host to serve the webpage on").Short('h').Default("127.0.0.1").StringVar(&cmd.host)
+	clause.Flag("port", "The port to serve the webpage on").Default("8080").IntVar(&cmd.port)
+	clause.Flag("offline", "Check the credentials locally instead of connecting to the demo API.").BoolVar(&cmd.offline)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run handles the command with the options as specified in the command.
+func (cmd *ServeCommand) Run() error {
+	fmt.Fprintf(cmd.io.Stdout(), "Serving example app on http://%s:%d\n", cmd.host, cmd.port)
+	if !cmd.offline {
+		return app.NewServer(cmd.host, cmd.port).Serve()
+	}
+
+	server := newOfflineServer(os.Getenv("DEMO_USERNAME"), os.Getenv("DEMO_PASSWORD"))
+	return http.ListenAndServe(fmt.Sprintf("%s:%d", cmd.host, cmd.port), server)
+}
+
+// offlineServer serves the demo app without connecting to the demo API.
+type offlineServer struct {
+	username string
+	password string
+}
+
+func newOfflineServer(username, password string) http.Handler {
+	s := &offlineServer{
+		username: username,
+		password: password,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.serveIndex)
+	mux.HandleFunc("/api", s.serveAPI)
+	return mux
+}
+
+func (s *offlineServer) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, offlinePage)
+}
+
+// serveAPI checks the credentials in the same way as the demo API does and responds like it.
+func (s *offlineServer) serveAPI(w http.ResponseWriter, r *http.Request) {
+	if s.username == "" {
+		writeMessage(w, http.StatusInternalServerError, "DEMO_USERNAME environment variable not set")
+		return
+	}
+
+	if s.password == "" {
+		writeMessage(w, http.StatusInternalServerError, "DEMO_PASSWORD environment variable not set")
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(s.password), []byte(demoPassword(s.username))) != 1 {
+		writeMessage(w, http.StatusUnauthorized, "Invalid username or password.")
+		return
+	}
+
+	writeMessage(w, http.StatusOK, fmt.Sprintf("Welcome %s! The credentials were loaded from SecretHub and checked offline.", s.username))
+}
+
+func writeMessage(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// offlinePage is the page of the demo app, without any stylesheets or scripts from the internet.
+const offlinePage = `<!doctype html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no">
+	<title>SecretHub Example App</title>
+	<style>
+		html, body { height: 100%; margin: 0; }
+		body {
+			display: flex;
+			align-items: center;
+			justify-content: center;
+			background-color: #f5f5f5;
+			font-family: -apple-system, "Segoe UI", Roboto, "Helvetica Neue", Arial, sans-serif;
+			text-align: center;
+		}
+		.container { width: 100%; max-width: 400px; padding: 15px; }
+		#icon { font-size: 64px; }
+		#result { margin-top: 16px; padding: 12px; border: 1px solid #ddd; border-radius: 4px; background-color: #fff; }
+	</style>
+	<script>
+		function show(status, icon, color, message) {
+			document.getElementById("status").textContent = status;
+			var el = document.getElementById("icon");
+			el.textContent = icon;
+			el.style.color = color;
+			if (message) {
+				var result = document.getElementById("result");
+				result.hidden = false;
+				result.textContent = message;
+			}
+		}
+
+		window.onload = function () {
+			var xhr = new XMLHttpRequest();
+			xhr.open("GET", "/api");
+			xhr.onload = function () {
+				var message = xhr.responseText;
+				try {
+					message = JSON.parse(xhr.responseText).message;
+				} catch (e) {}
+				if (xhr.status === 200) {
+					show("Successfully connected to the offline demo API!", "✔", "green", message);
+				} else {
+					show("An error occurred!", "✘", "red", message);
+				}
+			};
+			xhr.onerror = function () {
+				show("An error occurred!", "✘", "red", "Cannot reach the demo app.");
+			};
+			xhr.send();
+		};
+	</script>
+</head>
+<body>
+<div class="container">
+	<h1>Demo App</h1>
+	<div id="icon">&hellip;</div>
+	<p id="status">Checking the credentials offline...</p>
+	<div id="result" hidden></div>
+</div>
+</body>
+</html>
+`
@@ -0,0 +1,47 @@
+package demo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestOfflineServer_API(t *testing.T) {
+	cases := map[string]struct {
+		username string
+		password string
+		expected int
+	}{
+		"valid credentials": {
+			username: "dev",
+			password: demoPassword("dev"),
+			expected: http.StatusOK,
+		},
+		"wrong password": {
+			username: "dev",
+			password: demoPassword("other"),
+			expected: http.StatusUnauthorized,
+		},
+		"no username": {
+			password: demoPassword("dev"),
+			expected: http.StatusInternalServerError,
+		},
+		"no password": {
+			username: "dev",
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newOfflineServer(tc.username, tc.password)
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest("GET", "/api", nil))
+
+			assert.Equal(t, recorder.Code, tc.expected)
+		})
+	}
+}
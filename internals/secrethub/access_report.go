@@ -0,0 +1,315 @@
+package secrethub
+
+import (
+	"encoding/csv"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// Errors
+var (
+	ErrInvalidReportTarget = errMain.Code("invalid_report_target").ErrorPref("%q is neither a repository path (namespace/repo) nor an organization name")
+)
+
+const (
+	formatHTML = "html"
+)
+
+// accessReportFields are the names of the columns of an access report, in the order of accessReportRow.values.
+var accessReportFields = []string{
+	"Repo", "Account", "AccountType", "Directory", "Permission", "CredentialCreatedAt", "CredentialAgeDays", "LastActivity",
+}
+
+// accessReportTemplate renders an access report as a standalone HTML page.
+var accessReportTemplate = template.Must(template.New("access-report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Access report for {{.Target}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Access report for {{.Target}}</h1>
+<p>Generated at {{.GeneratedAt}}</p>
+<table>
+<thead>
+<tr>{{range .Fields}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+// AccessReportCommand prints a point-in-time report of the access to a repository or organization.
+type AccessReportCommand struct {
+	io        ui.IO
+	target    string
+	format    string
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewAccessReportCommand creates a new AccessReportCommand.
+func NewAccessReportCommand(io ui.IO, newClient newClientFunc) *AccessReportCommand {
+	return &AccessReportCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AccessReportCommand) Register(r command.Registerer) {
+	clause := r.Command("access-report", "Print a report of every account with access to a repository or to all repositories of an organization, "+
+		"with its permission per directory, the age of its credential and its last activity, for access reviews. "+
+		"Credential ages are only available for service accounts. The last activity is the newest event of the account in the audit log of the repository.")
+	clause.Arg("repo-path or org-name", "The repository ("+repoPathPlaceHolder+") or the organization to report on").Required().StringVar(&cmd.target)
+	clause.Flag("output", "The format of the report. Options are: csv and html.").HintOptions(formatCSV, formatHTML).Default(formatCSV).StringVar(&cmd.format)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the access report.
+func (cmd *AccessReportCommand) Run() error {
+	if cmd.format != formatCSV && cmd.format != formatHTML {
+		return errNoSuchFormat(cmd.format)
+	}
+
+	var repos []api.RepoPath
+	if strings.Contains(cmd.target, "/") {
+		repoPath, err := api.NewRepoPath(cmd.target)
+		if err != nil {
+			return ErrInvalidReportTarget(cmd.target)
+		}
+		repos = append(repos, repoPath)
+	} else if api.ValidateOrgName(cmd.target) != nil {
+		return ErrInvalidReportTarget(cmd.target)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	if repos == nil {
+		orgRepos, err := client.Repos().List(cmd.target)
+		if err != nil {
+			return err
+		}
+		for _, repo := range orgRepos {
+			repos = append(repos, repo.Path())
+		}
+	}
+
+	now := cmd.now()
+	var rows []accessReportRow
+	for _, repoPath := range repos {
+		repoRows, err := accessReportRows(client, repoPath, now)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, repoRows...)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Repo != rows[j].Repo {
+			return rows[i].Repo < rows[j].Repo
+		}
+		if rows[i].Account != rows[j].Account {
+			return rows[i].Account < rows[j].Account
+		}
+		return rows[i].Directory < rows[j].Directory
+	})
+
+	values := make([][]string, len(rows))
+	for i, row := range rows {
+		values[i] = row.values()
+	}
+
+	if cmd.format == formatHTML {
+		return accessReportTemplate.Execute(cmd.io.Output(), struct {
+			Target      string
+			GeneratedAt string
+			Fields      []string
+			Rows        [][]string
+		}{
+			Target:      cmd.target,
+			GeneratedAt: now.UTC().Format(time.RFC3339),
+			Fields:      accessReportFields,
+			Rows:        values,
+		})
+	}
+	return writeCSV(cmd.io.Output(), accessReportFields, values)
+}
+
+// accessReportRow is a single permission of an account in an access report.
+type accessReportRow struct {
+	Repo                string
+	Account             string
+	AccountType         string
+	Directory           string
+	Permission          string
+	CredentialCreatedAt time.Time
+	CredentialAgeDays   int
+	LastActivity        time.Time
+}
+
+// values returns the values of the row, in the order of accessReportFields.
+// Unknown times are left empty.
+func (r accessReportRow) values() []string {
+	credentialCreatedAt := ""
+	credentialAge := ""
+	if !r.CredentialCreatedAt.IsZero() {
+		credentialCreatedAt = r.CredentialCreatedAt.UTC().Format(time.RFC3339)
+		credentialAge = strconv.Itoa(r.CredentialAgeDays)
+	}
+
+	lastActivity := ""
+	if !r.LastActivity.IsZero() {
+		lastActivity = r.LastActivity.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		r.Repo, r.Account, r.AccountType, r.Directory, r.Permission, credentialCreatedAt, credentialAge, lastActivity,
+	}
+}
+
+// accessReportRows returns a row for every access rule of every account of the repository.
+// Accounts without access rules get a single row with the permission none.
+func accessReportRows(client secrethub.ClientInterface, repoPath api.RepoPath, now time.Time) ([]accessReportRow, error) {
+	accounts, err := client.Repos().ListAccounts(repoPath.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := client.Services().List(repoPath.Value())
+	if err != nil {
+		return nil, err
+	}
+	credentialCreatedAt := make(map[string]time.Time, len(services))
+	for _, service := range services {
+		if service.Credential != nil {
+			credentialCreatedAt[service.ServiceID] = service.Credential.CreatedAt
+		}
+	}
+
+	tree, err := client.Dirs().GetTree(repoPath.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := client.AccessRules().List(repoPath.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+	rulesByAccount := make(map[api.AccountName][]*api.AccessRule)
+	for _, rule := range rules {
+		if rule.Account == nil {
+			continue
+		}
+		rulesByAccount[rule.Account.Name] = append(rulesByAccount[rule.Account.Name], rule)
+	}
+
+	names := make([]string, len(accounts))
+	for i, account := range accounts {
+		names[i] = account.Name.String()
+	}
+	lastActivity, err := lastAuditActivity(client.Repos().EventIterator(repoPath.Value(), &secrethub.AuditEventIteratorParams{}), names)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []accessReportRow
+	for _, account := range accounts {
+		row := accessReportRow{
+			Repo:         repoPath.String(),
+			Account:      account.Name.String(),
+			AccountType:  account.AccountType,
+			Permission:   "none",
+			LastActivity: lastActivity[account.Name.String()],
+		}
+		if createdAt, ok := credentialCreatedAt[account.Name.String()]; ok {
+			row.CredentialCreatedAt = createdAt
+			row.CredentialAgeDays = int(now.Sub(createdAt).Hours() / 24)
+		}
+
+		accountRules := rulesByAccount[account.Name]
+		if len(accountRules) == 0 {
+			rows = append(rows, row)
+			continue
+		}
+
+		for _, rule := range accountRules {
+			dirPath, err := tree.AbsDirPath(rule.DirID)
+			if err != nil {
+				return nil, err
+			}
+
+			row.Directory = dirPath.String()
+			row.Permission = rule.Permission.String()
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// lastAuditActivity returns the time of the newest event of each of the given accounts.
+// The iterator is expected to return events from newest to oldest, so iteration stops
+// as soon as an event has been found for every account.
+func lastAuditActivity(iter secrethub.AuditEventIterator, accounts []string) (map[string]time.Time, error) {
+	remaining := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		remaining[account] = true
+	}
+
+	res := make(map[string]time.Time, len(accounts))
+	for len(remaining) > 0 {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		actor, err := getAuditActor(event)
+		if err != nil || !remaining[actor] {
+			continue
+		}
+		res[actor] = event.LoggedAt
+		delete(remaining, actor)
+	}
+	return res, nil
+}
+
+// writeCSV writes the header and rows as CSV records.
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,209 @@
+package secrethub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestAccessReportCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+	now := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	rootDirID := uuid.New()
+	subDirID := uuid.New()
+	developer := &api.Account{
+		Name:        "developer",
+		AccountType: "user",
+	}
+	service := &api.Account{
+		Name:        "s-abc",
+		AccountType: "service",
+	}
+
+	client := fakeclient.Client{
+		RepoService: &fakeclient.RepoService{
+			ListAccountsFunc: func(path string) ([]*api.Account, error) {
+				return []*api.Account{service, developer}, nil
+			},
+			AuditEventIterator: &fakeclient.AuditEventIterator{
+				Events: []api.Audit{
+					{
+						Action:   "read",
+						LoggedAt: now.Add(-time.Hour),
+						Actor: api.AuditActor{
+							Type: "user",
+							User: &api.User{Username: "developer"},
+						},
+					},
+					{
+						Action:   "read",
+						LoggedAt: now.Add(-2 * time.Hour),
+						Actor: api.AuditActor{
+							Type: "user",
+							User: &api.User{Username: "developer"},
+						},
+					},
+				},
+			},
+		},
+		ServiceService: &fakeclient.ServiceService{
+			ListFunc: func(path string) ([]*api.Service, error) {
+				return []*api.Service{
+					{
+						ServiceID: "s-abc",
+						Credential: &api.Credential{
+							CreatedAt: now.Add(-90 * 24 * time.Hour),
+						},
+					},
+				}, nil
+			},
+		},
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return &api.Tree{
+					ParentPath: "namespace",
+					Dirs: map[uuid.UUID]*api.Dir{
+						rootDirID: {
+							Name:  "repo",
+							DirID: rootDirID,
+						},
+						subDirID: {
+							Name:     "dir",
+							DirID:    subDirID,
+							ParentID: &rootDirID,
+						},
+					},
+					RootDir: &api.Dir{
+						Name:  "repo",
+						DirID: rootDirID,
+					},
+				}, nil
+			},
+		},
+		AccessRuleService: &fakeclient.AccessRuleService{
+			ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+				return []*api.AccessRule{
+					{
+						Account:    developer,
+						DirID:      subDirID,
+						Permission: api.PermissionAdmin,
+					},
+					{
+						Account:    developer,
+						DirID:      rootDirID,
+						Permission: api.PermissionRead,
+					},
+				}, nil
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		cmd       AccessReportCommand
+		newClient newClientFunc
+		out       string
+		contains  []string
+		err       error
+	}{
+		"csv": {
+			cmd: AccessReportCommand{
+				target: "namespace/repo",
+				format: formatCSV,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return client, nil
+			},
+			out: "Repo,Account,AccountType,Directory,Permission,CredentialCreatedAt,CredentialAgeDays,LastActivity\n" +
+				"namespace/repo,developer,user,namespace/repo,read,,,2020-04-01T11:00:00Z\n" +
+				"namespace/repo,developer,user,namespace/repo/dir,admin,,,2020-04-01T11:00:00Z\n" +
+				"namespace/repo,s-abc,service,,none,2020-01-02T12:00:00Z,90,\n",
+		},
+		"html": {
+			cmd: AccessReportCommand{
+				target: "namespace/repo",
+				format: formatHTML,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return client, nil
+			},
+			contains: []string{
+				"<title>Access report for namespace/repo</title>",
+				"<p>Generated at 2020-04-01T12:00:00Z</p>",
+				"<tr><td>namespace/repo</td><td>developer</td><td>user</td><td>namespace/repo/dir</td><td>admin</td><td></td><td></td><td>2020-04-01T11:00:00Z</td></tr>",
+			},
+		},
+		"org": {
+			cmd: AccessReportCommand{
+				target: "namespace",
+				format: formatCSV,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					RepoService: &fakeclient.RepoService{
+						ListFunc: func(namespace string) ([]*api.Repo, error) {
+							return nil, nil
+						},
+					},
+				}, nil
+			},
+			out: "Repo,Account,AccountType,Directory,Permission,CredentialCreatedAt,CredentialAgeDays,LastActivity\n",
+		},
+		"invalid format": {
+			cmd: AccessReportCommand{
+				target: "namespace/repo",
+				format: "pdf",
+			},
+			err: errNoSuchFormat("pdf"),
+		},
+		"invalid target": {
+			cmd: AccessReportCommand{
+				target: "namespace/repo/dir",
+				format: formatCSV,
+			},
+			err: ErrInvalidReportTarget("namespace/repo/dir"),
+		},
+		"client error": {
+			cmd: AccessReportCommand{
+				target: "namespace/repo",
+				format: formatCSV,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return nil, testErr
+			},
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+			tc.cmd.newClient = tc.newClient
+			tc.cmd.now = func() time.Time {
+				return now
+			}
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.contains == nil {
+				assert.Equal(t, io.Out.String(), tc.out)
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(io.Out.String(), s) {
+					t.Errorf("output does not contain %q:\n%s", s, io.Out.String())
+				}
+			}
+		})
+	}
+}
@@ -26,5 +26,6 @@ func (cmd *AccountCommand) Register(r command.Registerer) {
 	clause := r.Command("account", "Manage your personal account.")
 	NewAccountInspectCommand(cmd.io, cmd.newClient).Register(clause)
 	NewAccountInitCommand(cmd.io, cmd.newClient, cmd.credentialStore).Register(clause)
-	NewAccountEmailVerifyCommand(cmd.io, cmd.newClient).Register(clause)
+	NewAccountEmailCommand(cmd.io, cmd.newClient).Register(clause)
+	NewAccountEmailResendCommand(cmd.io, cmd.newClient).registerVerifyEmail(clause)
 }
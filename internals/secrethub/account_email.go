@@ -0,0 +1,27 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// AccountEmailCommand handles operations on the email address of an account.
+type AccountEmailCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewAccountEmailCommand creates a new AccountEmailCommand.
+func NewAccountEmailCommand(io ui.IO, newClient newClientFunc) *AccountEmailCommand {
+	return &AccountEmailCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *AccountEmailCommand) Register(r command.Registerer) {
+	clause := r.Command("email", "Verify the email address of your account. The email address itself can be changed in the account settings of the dashboard.")
+	NewAccountEmailVerifyCommand(cmd.io, cmd.newClient).Register(clause)
+	NewAccountEmailResendCommand(cmd.io, cmd.newClient).Register(clause)
+}
@@ -0,0 +1,74 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// AccountEmailResendCommand is a command to resend the verification email.
+type AccountEmailResendCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewAccountEmailResendCommand creates a new AccountEmailResendCommand.
+func NewAccountEmailResendCommand(io ui.IO, newClient newClientFunc) *AccountEmailResendCommand {
+	return &AccountEmailResendCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AccountEmailResendCommand) Register(r command.Registerer) {
+	cmd.register(r, "resend")
+}
+
+// registerVerifyEmail registers the command as `account verify-email`, which is what it was called
+// before the email commands were grouped. It is hidden, so scripts that use it keep working.
+func (cmd *AccountEmailResendCommand) registerVerifyEmail(r command.Registerer) {
+	cmd.register(r, "verify-email").Hidden()
+}
+
+// register registers the command with the given name.
+func (cmd *AccountEmailResendCommand) register(r command.Registerer, name string) *cli.CommandClause {
+	clause := r.Command(name, "Resend verification email to the registered email address.")
+	clause.HelpLong("When you create your account, a verification email is automatically sent to the email address you used to sign up. " +
+		"In case anything goes wrong (e.g. the email ended up in your junk folder), this command lets you resend the verification email. " +
+		"Once received, click the link in the verification email to verify your email address.")
+
+	command.BindAction(clause, cmd.Run)
+	return clause
+}
+
+// Run handles the command with the options as specified in the command.
+func (cmd *AccountEmailResendCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	user, err := client.Me().GetUser()
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified {
+		fmt.Fprintln(cmd.io.Output(), "Your email address is already verified.")
+		return nil
+	}
+
+	err = client.Me().SendVerificationEmail()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "An email has been sent to %s with an email verification link. Please check your mail and click the link.\n\n", user.Email)
+
+	fmt.Fprintf(cmd.io.Output(), "Please contact support@secrethub.io if the problem persists.\n\n")
+
+	return nil
+}
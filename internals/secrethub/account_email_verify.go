@@ -2,15 +2,29 @@ package secrethub
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 )
 
-// AccountEmailVerifyCommand is a command to inspect account details.
+// Errors
+var (
+	ErrEmailNotVerified          = errMain.Code("email_not_verified").ErrorPref("your email address %s is not verified yet. Click the link in the verification email or run `secrethub account email resend` to receive a new one")
+	ErrEmailVerificationTimedOut = errMain.Code("email_verification_timed_out").ErrorPref("your email address %s was not verified within %s")
+)
+
+// emailVerificationPollInterval is the time between the checks of whether the email address is verified.
+const emailVerificationPollInterval = 5 * time.Second
+
+// AccountEmailVerifyCommand is a command to check whether the email address of the account is verified.
 type AccountEmailVerifyCommand struct {
-	io        ui.IO
-	newClient newClientFunc
+	wait        bool
+	waitTimeout time.Duration
+	io          ui.IO
+	newClient   newClientFunc
+	now         func() time.Time
+	sleep       func(time.Duration)
 }
 
 // NewAccountEmailVerifyCommand creates a new AccountEmailVerifyCommand.
@@ -18,15 +32,19 @@ func NewAccountEmailVerifyCommand(io ui.IO, newClient newClientFunc) *AccountEma
 	return &AccountEmailVerifyCommand{
 		io:        io,
 		newClient: newClient,
+		now:       time.Now,
+		sleep:     time.Sleep,
 	}
 }
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *AccountEmailVerifyCommand) Register(r command.Registerer) {
-	clause := r.Command("verify-email", "Resend verification email to the registered email address.")
-	clause.HelpLong("When you create your account, a verification email is automatically sent to the email address you used to sign up. " +
-		"In case anything goes wrong (e.g. the email ended up in your junk folder), this command lets you resend the verification email. " +
-		"Once received, click the link in the verification email to verify your email address.")
+	clause := r.Command("verify", "Check whether the email address of your account is verified.")
+	clause.HelpLong("The command fails when the email address is not verified yet, so it can be used in scripts. " +
+		"With --wait, it waits until you have clicked the link in the verification email, " +
+		"e.g. to complete the onboarding of an account from the command-line.")
+	clause.Flag("wait", "Wait until the email address is verified.").BoolVar(&cmd.wait)
+	clause.Flag("wait-timeout", "The maximum time to wait for the email address to be verified, e.g. 30m or 1d.").Default("10m").SetValue((*durationValue)(&cmd.waitTimeout))
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -43,19 +61,26 @@ func (cmd *AccountEmailVerifyCommand) Run() error {
 		return err
 	}
 
-	if user.EmailVerified {
-		fmt.Fprintln(cmd.io.Output(), "Your email address is already verified.")
-		return nil
-	}
+	if !user.EmailVerified {
+		if !cmd.wait {
+			return ErrEmailNotVerified(user.Email)
+		}
 
-	err = client.Me().SendVerificationEmail()
-	if err != nil {
-		return err
-	}
+		fmt.Fprintf(statusWriter(cmd.io.Output()), "Waiting for you to click the link in the verification email sent to %s...\n", user.Email)
+		deadline := cmd.now().Add(cmd.waitTimeout)
+		for !user.EmailVerified {
+			if !cmd.now().Before(deadline) {
+				return ErrEmailVerificationTimedOut(user.Email, cmd.waitTimeout)
+			}
+			cmd.sleep(emailVerificationPollInterval)
 
-	fmt.Fprintf(cmd.io.Output(), "An email has been sent to %s with an email verification link. Please check your mail and click the link.\n\n", user.Email)
-
-	fmt.Fprintf(cmd.io.Output(), "Please contact support@secrethub.io if the problem persists.\n\n")
+			user, err = client.Me().GetUser()
+			if err != nil {
+				return err
+			}
+		}
+	}
 
+	fmt.Fprintf(cmd.io.Output(), "Your email address %s is verified.\n", user.Email)
 	return nil
 }
@@ -0,0 +1,74 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+func TestAccountEmailVerifyCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		wait bool
+		// verifiedAfter is the number of times the user is retrieved before the email address is verified.
+		verifiedAfter int
+		out           string
+		err           error
+	}{
+		"verified": {
+			out: "Your email address dev1@secrethub.io is verified.\n",
+		},
+		"not verified": {
+			verifiedAfter: 1,
+			err:           ErrEmailNotVerified("dev1@secrethub.io"),
+		},
+		"wait": {
+			wait:          true,
+			verifiedAfter: 3,
+			out: "Waiting for you to click the link in the verification email sent to dev1@secrethub.io...\n" +
+				"Your email address dev1@secrethub.io is verified.\n",
+		},
+		"wait timeout": {
+			wait:          true,
+			verifiedAfter: 100,
+			out:           "Waiting for you to click the link in the verification email sent to dev1@secrethub.io...\n",
+			err:           ErrEmailVerificationTimedOut("dev1@secrethub.io", time.Minute),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+			retrieved := 0
+
+			io := fakeui.NewIO(t)
+			cmd := AccountEmailVerifyCommand{
+				wait:        tc.wait,
+				waitTimeout: time.Minute,
+				io:          io,
+				now:         func() time.Time { return now },
+				sleep:       func(d time.Duration) { now = now.Add(d) },
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeMeClient{
+						getUser: func() (*api.User, error) {
+							retrieved++
+							return &api.User{
+								Email:         "dev1@secrethub.io",
+								EmailVerified: retrieved > tc.verifiedAfter,
+							}, nil
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -106,7 +106,7 @@ func (cmd *AccountInitCommand) Run() error {
 
 						if !confirmed {
 							fmt.Fprintln(cmd.io.Output(), "Aborting.")
-							return nil
+							return ErrAborted
 						}
 					}
 					return cmd.createAccountKey()
@@ -139,7 +139,7 @@ func (cmd *AccountInitCommand) Run() error {
 
 				if !confirmed {
 					fmt.Fprintln(cmd.io.Output(), "Aborting.")
-					return nil
+					return ErrAborted
 				}
 			}
 		}
@@ -7,15 +7,17 @@ import (
 
 // ACLCommand handles operations on access rules.
 type ACLCommand struct {
-	io        ui.IO
-	newClient newClientFunc
+	io              ui.IO
+	newClient       newClientFunc
+	credentialStore CredentialConfig
 }
 
 // NewACLCommand creates a new ACLCommand.
-func NewACLCommand(io ui.IO, newClient newClientFunc) *ACLCommand {
+func NewACLCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *ACLCommand {
 	return &ACLCommand{
-		io:        io,
-		newClient: newClient,
+		io:              io,
+		newClient:       newClient,
+		credentialStore: credentialStore,
 	}
 }
 
@@ -23,7 +25,8 @@ func NewACLCommand(io ui.IO, newClient newClientFunc) *ACLCommand {
 func (cmd *ACLCommand) Register(r command.Registerer) {
 	clause := r.Command("acl", "Manage access rules on directories.")
 	NewACLCheckCommand(cmd.io, cmd.newClient).Register(clause)
+	NewACLExpireSweepCommand(cmd.io, cmd.newClient, cmd.credentialStore).Register(clause)
 	NewACLListCommand(cmd.io, cmd.newClient).Register(clause)
 	NewACLRmCommand(cmd.io, cmd.newClient).Register(clause)
-	NewACLSetCommand(cmd.io, cmd.newClient).Register(clause)
+	NewACLSetCommand(cmd.io, cmd.newClient, cmd.credentialStore).Register(clause)
 }
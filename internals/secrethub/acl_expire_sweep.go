@@ -0,0 +1,132 @@
+package secrethub
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrGrantSweepFailed = errMain.Code("grant_sweep_failed").ErrorPref("could not remove %d expired access rule(s)")
+)
+
+// ACLExpireSweepCommand removes temporary access rules that have expired.
+type ACLExpireSweepCommand struct {
+	dryRun    bool
+	io        ui.IO
+	grants    func() grantStore
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewACLExpireSweepCommand creates a new ACLExpireSweepCommand.
+func NewACLExpireSweepCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *ACLExpireSweepCommand {
+	return &ACLExpireSweepCommand{
+		io:        io,
+		newClient: newClient,
+		grants: func() grantStore {
+			return newGrantStore(credentialStore.ConfigDir())
+		},
+		now: time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ACLExpireSweepCommand) Register(r command.Registerer) {
+	clause := r.Command("expire-sweep", "Remove all temporary access rules set with `acl set --expires-in` that have expired, restoring the access rule the account had before. Run this periodically, e.g. from a cron job, to make sure temporary access is revoked in time.")
+	clause.Flag("dry-run", "Only print the expired access rules, without removing them.").BoolVar(&cmd.dryRun)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run removes all expired access rules.
+func (cmd *ACLExpireSweepCommand) Run() error {
+	store := cmd.grants()
+	grants, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	sweeper := expirySweeper{
+		io:          cmd.io,
+		newClient:   cmd.newClient,
+		dryRun:      cmd.dryRun,
+		noneExpired: "No expired access rules found.",
+		isExpired: func(i int) bool {
+			return grants[i].isExpired(now)
+		},
+		describe: func(i int) string {
+			grant := grants[i]
+			if grant.PreviousPermission != "" {
+				return fmt.Sprintf("%s has expired %s access on %s, which is restored to %s", grant.Account, grant.Permission, grant.Path, grant.PreviousPermission)
+			}
+			return fmt.Sprintf("%s has expired %s access on %s", grant.Account, grant.Permission, grant.Path)
+		},
+		remove: func(client secrethub.ClientInterface, i int) bool {
+			return cmd.revoke(client, grants[i])
+		},
+		save: func(remaining []int) error {
+			kept := make([]temporaryGrant, len(remaining))
+			for j, i := range remaining {
+				kept[j] = grants[i]
+			}
+			return store.Save(kept)
+		},
+	}
+
+	failed, err := sweeper.sweep(len(grants))
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return ErrGrantSweepFailed(failed)
+	}
+	return nil
+}
+
+// revoke removes the expired access rule, or restores the access rule the account had before it was granted.
+// It returns false when the access rule could not be revoked.
+func (cmd *ACLExpireSweepCommand) revoke(client secrethub.ClientInterface, grant temporaryGrant) bool {
+	rule, err := client.AccessRules().Get(grant.Path, grant.Account)
+	if err == api.ErrAccessRuleNotFound {
+		fmt.Fprintf(cmd.io.Output(), "The access rule for %s on %s has already been removed.\n", grant.Account, grant.Path)
+		return true
+	} else if err != nil {
+		fmt.Fprintf(cmd.io.Output(), "Could not get the access rule for %s on %s: %s\n", grant.Account, grant.Path, err)
+		return false
+	}
+
+	// When the rule has been changed after it was granted temporarily, it is no longer ours to remove.
+	if !rule.LastChangedAt.Equal(grant.GrantedAt) {
+		fmt.Fprintf(cmd.io.Output(), "The access rule for %s on %s has changed since it was granted. Skipping.\n", grant.Account, grant.Path)
+		return true
+	}
+
+	// The account keeps the access it had before the rule was granted temporarily.
+	if grant.PreviousPermission != "" {
+		_, err = client.AccessRules().Set(grant.Path, grant.PreviousPermission, grant.Account)
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "Could not restore the access rule for %s on %s: %s\n", grant.Account, grant.Path, err)
+			return false
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "Restored the previous %s access of %s on %s.\n", grant.PreviousPermission, grant.Account, grant.Path)
+		return true
+	}
+
+	err = client.AccessRules().Delete(grant.Path, grant.Account)
+	if err != nil {
+		fmt.Fprintf(cmd.io.Output(), "Could not remove the access rule for %s on %s: %s\n", grant.Account, grant.Path, err)
+		return false
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Removed the expired access rule for %s on %s.\n", grant.Account, grant.Path)
+	return true
+}
@@ -0,0 +1,154 @@
+package secrethub
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestACLExpireSweepCommand_Run(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	grantedAt := now.Add(-5 * time.Hour)
+
+	expiredGrant := temporaryGrant{
+		Path:       "namespace/repo/dir",
+		Account:    "dev1",
+		Permission: "read",
+		GrantedAt:  grantedAt,
+		ExpiresAt:  now.Add(-time.Hour),
+	}
+	upgradeGrant := temporaryGrant{
+		Path:               "namespace/repo/dir",
+		Account:            "dev3",
+		Permission:         "admin",
+		GrantedAt:          grantedAt,
+		ExpiresAt:          now.Add(-time.Hour),
+		PreviousPermission: "read",
+	}
+	activeGrant := temporaryGrant{
+		Path:       "namespace/repo",
+		Account:    "dev2",
+		Permission: "write",
+		GrantedAt:  grantedAt,
+		ExpiresAt:  now.Add(time.Hour),
+	}
+
+	cases := map[string]struct {
+		dryRun    bool
+		grants    []temporaryGrant
+		getRule   func(path string, accountName string) (*api.AccessRule, error)
+		deleted   []string
+		set       []string
+		remaining []temporaryGrant
+		out       string
+		err       error
+	}{
+		"nothing expired": {
+			grants:    []temporaryGrant{activeGrant},
+			remaining: []temporaryGrant{activeGrant},
+			out:       "No expired access rules found.\n",
+		},
+		"dry run": {
+			dryRun:    true,
+			grants:    []temporaryGrant{expiredGrant, activeGrant},
+			remaining: []temporaryGrant{expiredGrant, activeGrant},
+			out:       "dev1 has expired read access on namespace/repo/dir\n",
+		},
+		"remove expired": {
+			grants: []temporaryGrant{expiredGrant, activeGrant},
+			getRule: func(path string, accountName string) (*api.AccessRule, error) {
+				return &api.AccessRule{LastChangedAt: grantedAt}, nil
+			},
+			deleted:   []string{"namespace/repo/dir:dev1"},
+			remaining: []temporaryGrant{activeGrant},
+			out:       "Removed the expired access rule for dev1 on namespace/repo/dir.\n",
+		},
+		"restore previous permission": {
+			grants: []temporaryGrant{upgradeGrant, activeGrant},
+			getRule: func(path string, accountName string) (*api.AccessRule, error) {
+				return &api.AccessRule{LastChangedAt: grantedAt}, nil
+			},
+			set:       []string{"namespace/repo/dir:dev3:read"},
+			remaining: []temporaryGrant{activeGrant},
+			out:       "Restored the previous read access of dev3 on namespace/repo/dir.\n",
+		},
+		"dry run restore": {
+			dryRun:    true,
+			grants:    []temporaryGrant{upgradeGrant},
+			remaining: []temporaryGrant{upgradeGrant},
+			out:       "dev3 has expired admin access on namespace/repo/dir, which is restored to read\n",
+		},
+		"rule changed since grant": {
+			grants: []temporaryGrant{expiredGrant},
+			getRule: func(path string, accountName string) (*api.AccessRule, error) {
+				return &api.AccessRule{LastChangedAt: now}, nil
+			},
+			remaining: []temporaryGrant{},
+			out:       "The access rule for dev1 on namespace/repo/dir has changed since it was granted. Skipping.\n",
+		},
+		"rule already removed": {
+			grants: []temporaryGrant{expiredGrant},
+			getRule: func(path string, accountName string) (*api.AccessRule, error) {
+				return nil, api.ErrAccessRuleNotFound
+			},
+			remaining: []temporaryGrant{},
+			out:       "The access rule for dev1 on namespace/repo/dir has already been removed.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			store := grantStore{jsonFile{path: filepath.Join(dir, grantsFilename)}}
+			err := store.Save(tc.grants)
+			assert.OK(t, err)
+
+			var deleted, set []string
+			io := fakeui.NewIO(t)
+			cmd := ACLExpireSweepCommand{
+				dryRun: tc.dryRun,
+				io:     io,
+				grants: func() grantStore { return store },
+				now:    func() time.Time { return now },
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							GetFunc: tc.getRule,
+							DeleteFunc: func(path string, accountName string) error {
+								deleted = append(deleted, path+":"+accountName)
+								return nil
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								set = append(set, path+":"+accountName+":"+permission)
+								return &api.AccessRule{}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err = cmd.Run()
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, deleted, tc.deleted)
+			assert.Equal(t, set, tc.set)
+
+			remaining, err := store.List()
+			assert.OK(t, err)
+			if len(tc.remaining) == 0 {
+				assert.Equal(t, len(remaining), 0)
+			} else {
+				assert.Equal(t, remaining, tc.remaining)
+			}
+		})
+	}
+}
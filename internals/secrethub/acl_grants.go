@@ -0,0 +1,84 @@
+package secrethub
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// grantsFilename is the name of the file in the configuration directory
+// that keeps track of temporary access rules.
+const grantsFilename = "grants.json"
+
+// temporaryGrant is an access rule that should be removed once it expires.
+type temporaryGrant struct {
+	Path       string    `json:"path"`
+	Account    string    `json:"account"`
+	Permission string    `json:"permission"`
+	GrantedAt  time.Time `json:"granted_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// PreviousPermission is the permission the account had on the path before the rule was granted
+	// temporarily, which is restored when the grant expires. It is empty when there was no rule before.
+	PreviousPermission string `json:"previous_permission,omitempty"`
+}
+
+// isExpired returns whether the grant has expired at the given time.
+func (g temporaryGrant) isExpired(now time.Time) bool {
+	return !now.Before(g.ExpiresAt)
+}
+
+// grantStore keeps track of temporary access rules in a file in the configuration directory.
+type grantStore struct {
+	jsonFile
+}
+
+// newGrantStore creates a grantStore that stores its grants in the given configuration directory.
+func newGrantStore(dir configdir.Dir) grantStore {
+	return grantStore{
+		jsonFile{path: filepath.Join(dir.Path(), grantsFilename)},
+	}
+}
+
+// List returns all tracked grants. When no grants have been stored yet, an empty list is returned.
+func (s grantStore) List() ([]temporaryGrant, error) {
+	grants := []temporaryGrant{}
+	err := s.read(&grants)
+	if err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// Get returns the tracked grant for the account on the path and whether such a grant is tracked.
+func (s grantStore) Get(path string, account string) (temporaryGrant, bool, error) {
+	grants, err := s.List()
+	if err != nil {
+		return temporaryGrant{}, false, err
+	}
+	for _, grant := range grants {
+		if grant.Path == path && grant.Account == account {
+			return grant, true, nil
+		}
+	}
+	return temporaryGrant{}, false, nil
+}
+
+// Add starts tracking the given grant, replacing any grant for the same account on the same path.
+func (s grantStore) Add(grant temporaryGrant) error {
+	grants := []temporaryGrant{}
+	return s.update(&grants, func() {
+		res := []temporaryGrant{grant}
+		for _, g := range grants {
+			if g.Path != grant.Path || g.Account != grant.Account {
+				res = append(res, g)
+			}
+		}
+		grants = res
+	})
+}
+
+// Save overwrites the tracked grants with the given grants.
+func (s grantStore) Save(grants []temporaryGrant) error {
+	return s.write(grants)
+}
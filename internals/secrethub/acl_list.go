@@ -1,9 +1,13 @@
 package secrethub
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 
@@ -15,20 +19,24 @@ import (
 
 // ACLListCommand prints access rules for the given directory.
 type ACLListCommand struct {
-	path          api.DirPath
-	depth         int
-	ancestors     bool
-	useTimestamps bool
-	timeFormatter TimeFormatter
-	io            ui.IO
-	newClient     newClientFunc
+	path               api.DirPath
+	depth              int
+	ancestors          bool
+	recursive          bool
+	format             string
+	useTimestamps      bool
+	timeFormatter      TimeFormatter
+	io                 ui.IO
+	newClient          newClientFunc
+	newPaginatedWriter func(io.Writer) (io.WriteCloser, error)
 }
 
 // NewACLListCommand creates a new ACLListCommand.
 func NewACLListCommand(io ui.IO, newClient newClientFunc) *ACLListCommand {
 	return &ACLListCommand{
-		io:        io,
-		newClient: newClient,
+		io:                 io,
+		newClient:          newClient,
+		newPaginatedWriter: newPaginatedWriter,
 	}
 }
 
@@ -39,6 +47,8 @@ func (cmd *ACLListCommand) Register(r command.Registerer) {
 	clause.Arg("dir-path", "The path of the directory to list the access rules for").Required().PlaceHolder(optionalDirPathPlaceHolder).SetValue(&cmd.path)
 	clause.Flag("depth", "The maximum depth to which the rules of child directories should be displayed. Defaults to -1 (no limit).").Short('d').Default("-1").IntVar(&cmd.depth)
 	clause.Flag("all", "List all rules that apply on the directory, including rules on parent directories.").Short('a').BoolVar(&cmd.ancestors)
+	clause.Flag("recursive", "List the rules of all child directories, regardless of their depth. Overrides the --depth flag.").Short('r').BoolVar(&cmd.recursive)
+	registerOutputFlag(clause, &cmd.format, formatTable, formatJSON, formatYAML, formatCSV)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
 
 	command.BindAction(clause, cmd.Run)
@@ -47,12 +57,15 @@ func (cmd *ACLListCommand) Register(r command.Registerer) {
 // Run prints access rules for the given directory.
 func (cmd *ACLListCommand) Run() error {
 	cmd.beforeRun()
-	return cmd.run()
+	return withPager(&cmd.io, cmd.newPaginatedWriter, cmd.run)
 }
 
 // beforeRun configures the command using the flag values.
 func (cmd *ACLListCommand) beforeRun() {
 	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+	if cmd.recursive {
+		cmd.depth = -1
+	}
 }
 
 func (cmd *ACLListCommand) run() error {
@@ -90,6 +103,7 @@ func (cmd *ACLListCommand) run() error {
 		for i, ruleIndex := range list {
 			dirRules[i] = rules[ruleIndex]
 		}
+		sort.Sort(api.SortAccessRules(dirRules))
 
 		ruleMap[dirPath] = dirRules
 	}
@@ -103,14 +117,25 @@ func (cmd *ACLListCommand) run() error {
 
 	sort.Sort(api.SortDirPaths(paths))
 
+	switch cmd.format {
+	case formatJSON, formatYAML:
+		return cmd.printOutput(paths, ruleMap)
+	case formatCSV:
+		return cmd.printCSV(paths, ruleMap)
+	case formatTable, "":
+		return cmd.printTable(paths, ruleMap)
+	default:
+		return errNoSuchFormat(cmd.format)
+	}
+}
+
+// printTable prints the access rules in a human readable table.
+func (cmd *ACLListCommand) printTable(paths []api.DirPath, ruleMap map[api.DirPath][]*api.AccessRule) error {
 	tabWriter := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 4, ' ', 0)
 	fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", "PATH", "PERMISSIONS", "LAST EDITED", "ACCOUNT")
 
 	for _, p := range paths {
-		rulesForPath := ruleMap[p]
-		sort.Sort(api.SortAccessRules(rules))
-
-		for _, rule := range rulesForPath {
+		for _, rule := range ruleMap[p] {
 			fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n",
 				p,
 				rule.Permission,
@@ -120,10 +145,78 @@ func (cmd *ACLListCommand) run() error {
 		}
 	}
 
-	err = tabWriter.Flush()
+	return tabWriter.Flush()
+}
+
+// printOutput prints the access rules as a JSON or YAML list.
+func (cmd *ACLListCommand) printOutput(paths []api.DirPath, ruleMap map[api.DirPath][]*api.AccessRule) error {
+	rules := []aclListOutput{}
+	for _, p := range paths {
+		for _, rule := range ruleMap[p] {
+			rules = append(rules, cmd.newACLListOutput(p, rule))
+		}
+	}
+
+	return writeOutput(cmd.io.Output(), cmd.format, rules)
+}
+
+// printCSV prints the access rules as comma separated values, including a header row
+// with the same field names as the JSON output.
+func (cmd *ACLListCommand) printCSV(paths []api.DirPath, ruleMap map[api.DirPath][]*api.AccessRule) error {
+	w := csv.NewWriter(cmd.io.Output())
+	err := w.Write([]string{"Path", "Account", "AccountType", "Permission", "Source", "CreatedAt", "LastChangedAt"})
 	if err != nil {
 		return err
 	}
 
-	return nil
+	for _, p := range paths {
+		for _, rule := range ruleMap[p] {
+			out := cmd.newACLListOutput(p, rule)
+			err = w.Write([]string{out.Path, out.Account, out.AccountType, out.Permission, out.Source, out.CreatedAt, out.LastChangedAt})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// aclListOutput is the machine readable format of a single access rule.
+type aclListOutput struct {
+	Path          string
+	Account       string
+	AccountType   string
+	Permission    string
+	Source        string
+	CreatedAt     string
+	LastChangedAt string
+}
+
+const (
+	aclSourceDirect    = "direct"
+	aclSourceInherited = "inherited"
+)
+
+// newACLListOutput converts an access rule on the given directory to its machine readable format.
+// Rules set on a parent directory of the listed path are marked as inherited.
+func (cmd *ACLListCommand) newACLListOutput(dirPath api.DirPath, rule *api.AccessRule) aclListOutput {
+	source := aclSourceDirect
+	if dirPath != cmd.path && strings.HasPrefix(cmd.path.String()+"/", dirPath.String()+"/") {
+		source = aclSourceInherited
+	}
+
+	out := aclListOutput{
+		Path:          dirPath.String(),
+		Permission:    rule.Permission.String(),
+		Source:        source,
+		CreatedAt:     rule.CreatedAt.UTC().Format(time.RFC3339),
+		LastChangedAt: rule.LastChangedAt.UTC().Format(time.RFC3339),
+	}
+	if rule.Account != nil {
+		out.Account = rule.Account.Name.String()
+		out.AccountType = rule.Account.AccountType
+	}
+	return out
 }
@@ -144,6 +144,165 @@ func TestACLListCommand_run(t *testing.T) {
 				"namespace/repo        read           1 hour ago     developer\n" +
 				"namespace/repo/dir    admin          1 hour ago     developer\n",
 		},
+		"json inherited": {
+			cmd: ACLListCommand{
+				path:      api.DirPath("namespace/repo/dir"),
+				ancestors: true,
+				format:    formatJSON,
+			},
+			accessrules: fakeclient.AccessRuleService{
+				ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+					return []*api.AccessRule{
+						{
+							Account: &api.Account{
+								Name:        "developer",
+								AccountType: "user",
+							},
+							DirID:         dir1ID,
+							Permission:    api.PermissionRead,
+							CreatedAt:     time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC),
+							LastChangedAt: time.Date(2018, 1, 2, 1, 1, 1, 0, time.UTC),
+						},
+					}, nil
+				},
+			},
+			dirs: fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return &api.Tree{
+						ParentPath: "namespace",
+						Dirs: map[uuid.UUID]*api.Dir{
+							dir1ID: {
+								Name:  "repo",
+								DirID: dir1ID,
+							},
+						},
+						RootDir: &api.Dir{
+							Name:  "repo",
+							DirID: dir1ID,
+						},
+					}, nil
+				},
+			},
+			out: "[\n" +
+				"    {\n" +
+				"        \"Path\": \"namespace/repo\",\n" +
+				"        \"Account\": \"developer\",\n" +
+				"        \"AccountType\": \"user\",\n" +
+				"        \"Permission\": \"read\",\n" +
+				"        \"Source\": \"inherited\",\n" +
+				"        \"CreatedAt\": \"2018-01-01T01:01:01Z\",\n" +
+				"        \"LastChangedAt\": \"2018-01-02T01:01:01Z\"\n" +
+				"    }\n" +
+				"]\n",
+		},
+		"csv direct": {
+			cmd: ACLListCommand{
+				path:   api.DirPath("namespace/repo"),
+				format: formatCSV,
+			},
+			accessrules: fakeclient.AccessRuleService{
+				ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+					return []*api.AccessRule{
+						{
+							Account: &api.Account{
+								Name:        "developer",
+								AccountType: "user",
+							},
+							DirID:         dir1ID,
+							Permission:    api.PermissionAdmin,
+							CreatedAt:     time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC),
+							LastChangedAt: time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC),
+						},
+					}, nil
+				},
+			},
+			dirs: fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return &api.Tree{
+						ParentPath: "namespace",
+						Dirs: map[uuid.UUID]*api.Dir{
+							dir1ID: {
+								Name:  "repo",
+								DirID: dir1ID,
+							},
+						},
+						RootDir: &api.Dir{
+							Name:  "repo",
+							DirID: dir1ID,
+						},
+					}, nil
+				},
+			},
+			out: "Path,Account,AccountType,Permission,Source,CreatedAt,LastChangedAt\n" +
+				"namespace/repo,developer,user,admin,direct,2018-01-01T01:01:01Z,2018-01-01T01:01:01Z\n",
+		},
+		"csv sorted within directory": {
+			cmd: ACLListCommand{
+				path:   api.DirPath("namespace/repo"),
+				format: formatCSV,
+			},
+			accessrules: fakeclient.AccessRuleService{
+				ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+					return []*api.AccessRule{
+						{
+							Account:    &api.Account{Name: "developer2", AccountType: "user"},
+							DirID:      dir1ID,
+							Permission: api.PermissionRead,
+							CreatedAt:  time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC),
+						},
+						{
+							Account:    &api.Account{Name: "developer10", AccountType: "user"},
+							DirID:      dir1ID,
+							Permission: api.PermissionRead,
+							CreatedAt:  time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC),
+						},
+						{
+							Account:    &api.Account{Name: "developer3", AccountType: "user"},
+							DirID:      dir1ID,
+							Permission: api.PermissionAdmin,
+							CreatedAt:  time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC),
+						},
+					}, nil
+				},
+			},
+			dirs: fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return &api.Tree{
+						ParentPath: "namespace",
+						Dirs: map[uuid.UUID]*api.Dir{
+							dir1ID: {
+								Name:  "repo",
+								DirID: dir1ID,
+							},
+						},
+						RootDir: &api.Dir{
+							Name:  "repo",
+							DirID: dir1ID,
+						},
+					}, nil
+				},
+			},
+			out: "Path,Account,AccountType,Permission,Source,CreatedAt,LastChangedAt\n" +
+				"namespace/repo,developer3,user,admin,direct,2018-01-01T01:01:01Z,0001-01-01T00:00:00Z\n" +
+				"namespace/repo,developer2,user,read,direct,2018-01-01T01:01:01Z,0001-01-01T00:00:00Z\n" +
+				"namespace/repo,developer10,user,read,direct,2018-01-01T01:01:01Z,0001-01-01T00:00:00Z\n",
+		},
+		"invalid format": {
+			cmd: ACLListCommand{
+				format: "xml",
+			},
+			accessrules: fakeclient.AccessRuleService{
+				ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+					return []*api.AccessRule{}, nil
+				},
+			},
+			dirs: fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return nil, nil
+				},
+			},
+			err: errNoSuchFormat("xml"),
+		},
 	}
 
 	for name, tc := range cases {
@@ -55,7 +55,7 @@ func (cmd *ACLRmCommand) Run() error {
 
 		if !confirmed {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 	}
 
@@ -64,14 +64,14 @@ func (cmd *ACLRmCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Removing access rule...")
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Removing access rule...")
 
 	err = client.AccessRules().Delete(cmd.path.Value(), cmd.accountName.Value())
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Removal complete! The access rule for %s on %s has been removed.\n", cmd.accountName, cmd.path)
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Removal complete! The access rule for %s on %s has been removed.\n", cmd.accountName, cmd.path)
 
 	return nil
 }
@@ -61,6 +61,7 @@ func TestACLRmCommand_Run(t *testing.T) {
 			promptOut: "[WARNING] This can impact the account's ability to read and/or modify secrets. " +
 				"Are you sure you want to remove the access rule for dev1? [y/N]: ",
 			out: "Aborting.\n",
+			err: ErrAborted,
 		},
 		"client creation error": {
 			cmd: ACLRmCommand{
@@ -2,28 +2,41 @@ package secrethub
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrInvalidExpiry = errMain.Code("invalid_expiry").Error("the expiry duration must be positive")
 )
 
 // ACLSetCommand is a command to set access rules.
 type ACLSetCommand struct {
 	accountName api.AccountName
+	expiresIn   time.Duration
+	temporary   bool
 	force       bool
 	io          ui.IO
 	path        api.DirPath
 	permission  api.Permission
+	grants      func() grantStore
 	newClient   newClientFunc
 }
 
 // NewACLSetCommand creates a new ACLSetCommand.
-func NewACLSetCommand(io ui.IO, newClient newClientFunc) *ACLSetCommand {
+func NewACLSetCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *ACLSetCommand {
 	return &ACLSetCommand{
 		io:        io,
 		newClient: newClient,
+		grants: func() grantStore {
+			return newGrantStore(credentialStore.ConfigDir())
+		},
 	}
 }
 
@@ -34,6 +47,7 @@ func (cmd *ACLSetCommand) Register(r command.Registerer) {
 	clause.Arg("dir-path", "The path of the directory to set the access rule for").Required().PlaceHolder(optionalDirPathPlaceHolder).SetValue(&cmd.path)
 	clause.Arg("account-name", "The account name (username or service name) to set the access rule for").Required().SetValue(&cmd.accountName)
 	clause.Arg("permission", "The permission to set in the access rule.").Required().SetValue(&cmd.permission)
+	clause.Flag("expires-in", "Make the access rule temporary. The rule is tracked locally and removed by `acl expire-sweep` once the given duration (e.g. 4h or 2d) has passed.").IsSetByUser(&cmd.temporary).SetValue((*durationValue)(&cmd.expiresIn))
 	registerForceFlag(clause).BoolVar(&cmd.force)
 
 	command.BindAction(clause, cmd.Run)
@@ -41,6 +55,10 @@ func (cmd *ACLSetCommand) Register(r command.Registerer) {
 
 // Run handles the command with the options as specified in the command.
 func (cmd *ACLSetCommand) Run() error {
+	if cmd.temporary && cmd.expiresIn <= 0 {
+		return ErrInvalidExpiry
+	}
+
 	if !cmd.force {
 		confirmed, err := ui.AskYesNo(
 			cmd.io,
@@ -59,7 +77,7 @@ func (cmd *ACLSetCommand) Run() error {
 
 		if !confirmed {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 	}
 
@@ -70,13 +88,68 @@ func (cmd *ACLSetCommand) Run() error {
 		return err
 	}
 
-	_, err = client.AccessRules().Set(cmd.path.Value(), cmd.permission.String(), cmd.accountName.Value())
+	var previousPermission string
+	if cmd.temporary {
+		previousPermission, err = cmd.previousPermission(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	rule, err := client.AccessRules().Set(cmd.path.Value(), cmd.permission.String(), cmd.accountName.Value())
 	if err != nil {
 		return err
 	}
 
 	fmt.Fprintln(cmd.io.Output(), "Access rule set!")
 
+	if cmd.temporary {
+		grantedAt := time.Now().UTC()
+		if rule != nil {
+			grantedAt = rule.LastChangedAt
+		}
+		expiresAt := grantedAt.Add(cmd.expiresIn)
+
+		err = cmd.grants().Add(temporaryGrant{
+			Path:               cmd.path.Value(),
+			Account:            cmd.accountName.Value(),
+			Permission:         cmd.permission.String(),
+			GrantedAt:          grantedAt,
+			ExpiresAt:          expiresAt,
+			PreviousPermission: previousPermission,
+		})
+		if err != nil {
+			return err
+		}
+
+		if previousPermission != "" {
+			fmt.Fprintf(cmd.io.Output(), "The access rule expires at %s. Run `secrethub acl expire-sweep` to restore the previous %s access of %s once it has expired.\n", expiresAt.Local().Format(time.RFC3339), previousPermission, cmd.accountName)
+		} else {
+			fmt.Fprintf(cmd.io.Output(), "The access rule expires at %s. Run `secrethub acl expire-sweep` to remove expired access rules.\n", expiresAt.Local().Format(time.RFC3339))
+		}
+	}
+
 	return nil
 
 }
+
+// previousPermission returns the permission the account has on the path before the rule is set temporarily,
+// or an empty string when it has no access rule on the path. When the rule is already temporary, the permission
+// from before the first temporary grant is returned, so it is that permission that is restored on expiry.
+func (cmd *ACLSetCommand) previousPermission(client secrethub.ClientInterface) (string, error) {
+	grant, tracked, err := cmd.grants().Get(cmd.path.Value(), cmd.accountName.Value())
+	if err != nil {
+		return "", err
+	}
+	if tracked {
+		return grant.PreviousPermission, nil
+	}
+
+	rule, err := client.AccessRules().Get(cmd.path.Value(), cmd.accountName.Value())
+	if err == api.ErrAccessRuleNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return rule.Permission.String(), nil
+}
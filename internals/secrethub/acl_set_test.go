@@ -2,7 +2,9 @@ package secrethub
 
 import (
 	"bytes"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
@@ -54,6 +56,7 @@ func TestACLSetCommand_Run(t *testing.T) {
 			},
 			in:     "n",
 			stdout: "Aborting.\n",
+			err:    ErrAborted,
 			promptOut: "[WARNING] This gives dev1 read rights on all directories and secrets contained in namespace/repo/dir. " +
 				"Are you sure you want to set this access rule? [y/N]: ",
 		},
@@ -123,3 +126,127 @@ func TestACLSetCommand_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestACLSetCommand_Run_ExpiresIn(t *testing.T) {
+	lastChangedAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		expiresIn    time.Duration
+		existing     *api.AccessRule
+		tracked      []temporaryGrant
+		rule         *api.AccessRule
+		err          error
+		expectGrant  bool
+		expectedFrom time.Time
+		previous     string
+	}{
+		"grant recorded from rule": {
+			expiresIn:    4 * time.Hour,
+			rule:         &api.AccessRule{LastChangedAt: lastChangedAt},
+			expectGrant:  true,
+			expectedFrom: lastChangedAt,
+		},
+		"existing rule": {
+			expiresIn:    4 * time.Hour,
+			existing:     &api.AccessRule{Permission: api.PermissionWrite},
+			rule:         &api.AccessRule{LastChangedAt: lastChangedAt},
+			expectGrant:  true,
+			expectedFrom: lastChangedAt,
+			previous:     "write",
+		},
+		"already temporary": {
+			expiresIn: 4 * time.Hour,
+			existing:  &api.AccessRule{Permission: api.PermissionAdmin},
+			tracked: []temporaryGrant{{
+				Path:               "namespace/repo/dir",
+				Account:            "dev1",
+				Permission:         "admin",
+				PreviousPermission: "write",
+			}},
+			rule:         &api.AccessRule{LastChangedAt: lastChangedAt},
+			expectGrant:  true,
+			expectedFrom: lastChangedAt,
+			previous:     "write",
+		},
+		"no rule returned": {
+			expiresIn:   2 * 24 * time.Hour,
+			expectGrant: true,
+		},
+		"zero duration": {
+			expiresIn: 0,
+			err:       ErrInvalidExpiry,
+		},
+		"negative duration": {
+			expiresIn: -time.Hour,
+			err:       ErrInvalidExpiry,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			store := grantStore{jsonFile{path: filepath.Join(dir, grantsFilename)}}
+			err := store.Save(tc.tracked)
+			assert.OK(t, err)
+
+			fakeIO := fakeui.NewIO(t)
+			cmd := ACLSetCommand{
+				io:          fakeIO,
+				accountName: "dev1",
+				permission:  api.PermissionRead,
+				path:        "namespace/repo/dir",
+				force:       true,
+				expiresIn:   tc.expiresIn,
+				temporary:   true,
+				grants: func() grantStore {
+					return store
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							GetFunc: func(path string, accountName string) (*api.AccessRule, error) {
+								if tc.existing == nil {
+									return nil, api.ErrAccessRuleNotFound
+								}
+								return tc.existing, nil
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								return tc.rule, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			// Act
+			before := time.Now().UTC()
+			err = cmd.Run()
+			after := time.Now().UTC()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+
+			grants, err := store.List()
+			assert.OK(t, err)
+			if !tc.expectGrant {
+				assert.Equal(t, len(grants), 0)
+				return
+			}
+
+			assert.Equal(t, len(grants), 1)
+			grant := grants[0]
+			assert.Equal(t, grant.Path, "namespace/repo/dir")
+			assert.Equal(t, grant.Account, "dev1")
+			assert.Equal(t, grant.Permission, "read")
+			assert.Equal(t, grant.PreviousPermission, tc.previous)
+			assert.Equal(t, grant.ExpiresAt.Sub(grant.GrantedAt), tc.expiresIn)
+			if tc.expectedFrom.IsZero() {
+				assert.Equal(t, grant.GrantedAt.Before(before) || grant.GrantedAt.After(after), false)
+			} else {
+				assert.Equal(t, grant.GrantedAt.Equal(tc.expectedFrom), true)
+			}
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package secrethub
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// The human readable parts of the bech32 encoded keys of age (https://age-encryption.org).
+const (
+	ageIdentityHRP  = "age-secret-key-"
+	ageRecipientHRP = "age"
+)
+
+// Errors
+var (
+	ErrInvalidAgeIdentity  = errMain.Code("invalid_age_identity").Error("invalid age identity: must be an X25519 identity starting with AGE-SECRET-KEY-1")
+	ErrInvalidAgeRecipient = errMain.Code("invalid_age_recipient").ErrorPref("invalid age recipient %s: must be an X25519 recipient starting with age1")
+)
+
+// The constants of the age v1 file format (https://age-encryption.org/v1).
+const (
+	ageVersionLine     = "age-encryption.org/v1"
+	ageX25519Label     = "age-encryption.org/v1/X25519"
+	ageFileKeySize     = 16
+	ageStreamNonceSize = 16
+	ageChunkSize       = 64 * 1024
+	ageColumnsPerLine  = 64
+)
+
+// ageIdentity is an age X25519 identity, which is a private key.
+type ageIdentity struct {
+	secretKey [32]byte
+}
+
+// generateAgeIdentity generates a new random age X25519 identity.
+func generateAgeIdentity() (ageIdentity, error) {
+	var identity ageIdentity
+	_, err := rand.Read(identity.secretKey[:])
+	if err != nil {
+		return ageIdentity{}, err
+	}
+	return identity, nil
+}
+
+// parseAgeIdentity parses an identity in the AGE-SECRET-KEY-1... format.
+func parseAgeIdentity(s string) (ageIdentity, error) {
+	hrp, data, err := bech32Decode(strings.TrimSpace(s))
+	if err != nil || hrp != ageIdentityHRP || len(data) != 32 {
+		return ageIdentity{}, ErrInvalidAgeIdentity
+	}
+
+	var identity ageIdentity
+	copy(identity.secretKey[:], data)
+	return identity, nil
+}
+
+// String returns the identity in the AGE-SECRET-KEY-1... format.
+func (i ageIdentity) String() string {
+	return strings.ToUpper(bech32Encode(ageIdentityHRP, i.secretKey[:]))
+}
+
+// Recipient returns the recipient, which is the public key, of the identity.
+func (i ageIdentity) Recipient() ageRecipient {
+	var recipient ageRecipient
+	curve25519.ScalarBaseMult(&recipient.publicKey, &i.secretKey)
+	return recipient
+}
+
+// ageRecipient is an age X25519 recipient, which is a public key.
+type ageRecipient struct {
+	publicKey [32]byte
+}
+
+// parseAgeRecipient parses a recipient in the age1... format.
+func parseAgeRecipient(s string) (ageRecipient, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil || hrp != ageRecipientHRP || len(data) != 32 {
+		return ageRecipient{}, ErrInvalidAgeRecipient(s)
+	}
+
+	var recipient ageRecipient
+	copy(recipient.publicKey[:], data)
+	return recipient, nil
+}
+
+// String returns the recipient in the age1... format.
+func (r ageRecipient) String() string {
+	return bech32Encode(ageRecipientHRP, r.publicKey[:])
+}
+
+// wrap encrypts the file key to the recipient and returns the recipient stanza.
+func (r ageRecipient) wrap(fileKey []byte) (string, error) {
+	var ephemeral, share, sharedSecret [32]byte
+	_, err := rand.Read(ephemeral[:])
+	if err != nil {
+		return "", err
+	}
+	curve25519.ScalarBaseMult(&share, &ephemeral)
+	curve25519.ScalarMult(&sharedSecret, &ephemeral, &r.publicKey)
+	if sharedSecret == [32]byte{} {
+		return "", ErrInvalidAgeRecipient(r.String())
+	}
+
+	salt := make([]byte, 0, 64)
+	salt = append(salt, share[:]...)
+	salt = append(salt, r.publicKey[:]...)
+	wrapKey, err := ageHKDF(sharedSecret[:], salt, ageX25519Label)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := chacha20poly1305.New(wrapKey)
+	if err != nil {
+		return "", err
+	}
+	body := aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), fileKey, nil)
+
+	return "-> X25519 " + ageBase64(share[:]) + "\n" + ageWrapLines(ageBase64(body)), nil
+}
+
+// ageEncrypt returns a writer that encrypts everything written to it to the recipients in the age v1
+// format and writes the result to w. The header is written immediately. The returned writer must be
+// closed to write the last chunk of the payload.
+func ageEncrypt(w io.Writer, recipients ...ageRecipient) (io.WriteCloser, error) {
+	fileKey := make([]byte, ageFileKeySize)
+	_, err := rand.Read(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var header strings.Builder
+	header.WriteString(ageVersionLine + "\n")
+	for _, recipient := range recipients {
+		stanza, err := recipient.wrap(fileKey)
+		if err != nil {
+			return nil, err
+		}
+		header.WriteString(stanza)
+	}
+	header.WriteString("---")
+
+	macKey, err := ageHKDF(fileKey, nil, "header")
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write([]byte(header.String()))
+	header.WriteString(" " + ageBase64(mac.Sum(nil)) + "\n")
+
+	nonce := make([]byte, ageStreamNonceSize)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadKey, err := ageHKDF(fileKey, nonce, "payload")
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(payloadKey)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(w, header.String())
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ageStreamWriter{
+		w:     w,
+		aead:  aead,
+		buf:   make([]byte, 0, ageChunkSize),
+		nonce: make([]byte, chacha20poly1305.NonceSize),
+	}, nil
+}
+
+// ageStreamWriter encrypts the payload of an age file in chunks of 64 KiB with the STREAM construction.
+type ageStreamWriter struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	buf   []byte
+	nonce []byte
+}
+
+// Write buffers p and encrypts every full chunk that is followed by more data.
+// A full chunk is only encrypted when more data follows, as the last chunk is encrypted differently.
+func (s *ageStreamWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if len(s.buf) == ageChunkSize {
+			err := s.flush(false)
+			if err != nil {
+				return n, err
+			}
+		}
+
+		m := copy(s.buf[len(s.buf):ageChunkSize], p)
+		s.buf = s.buf[:len(s.buf)+m]
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// Close encrypts the last chunk. It does not close the underlying writer.
+func (s *ageStreamWriter) Close() error {
+	return s.flush(true)
+}
+
+// flush encrypts the buffered chunk and writes it to the underlying writer.
+// The nonce of a chunk is its 11-byte big endian counter followed by a byte that marks the last chunk.
+func (s *ageStreamWriter) flush(last bool) error {
+	if last {
+		s.nonce[len(s.nonce)-1] = 1
+	}
+	_, err := s.w.Write(s.aead.Seal(nil, s.nonce, s.buf, nil))
+	if err != nil {
+		return err
+	}
+
+	for i := len(s.nonce) - 2; i >= 0; i-- {
+		s.nonce[i]++
+		if s.nonce[i] != 0 {
+			break
+		}
+	}
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// ageHKDF derives a 32-byte key with HKDF-SHA-256.
+func ageHKDF(secret []byte, salt []byte, info string) ([]byte, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	_, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(info)), key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ageBase64 encodes data in the unpadded base64 encoding used in age headers.
+func ageBase64(data []byte) string {
+	return base64.RawStdEncoding.EncodeToString(data)
+}
+
+// ageWrapLines wraps an encoded stanza body at 64 columns. The last line is always
+// shorter than 64 columns, so it is empty when the length is a multiple of 64.
+func ageWrapLines(s string) string {
+	var sb strings.Builder
+	for len(s) >= ageColumnsPerLine {
+		sb.WriteString(s[:ageColumnsPerLine] + "\n")
+		s = s[ageColumnsPerLine:]
+	}
+	sb.WriteString(s + "\n")
+	return sb.String()
+}
+
+// bech32Charset is the alphabet of bech32, as defined in BIP 173.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Polymod computes the BCH checksum of bech32 over the given 5-bit values.
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand returns the human readable part as the 5-bit values used in the checksum.
+func bech32HRPExpand(hrp string) []byte {
+	res := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]>>5)
+	}
+	res = append(res, 0)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]&31)
+	}
+	return res
+}
+
+// bech32ConvertBits regroups the bits of data from groups of fromBits to groups of toBits.
+// When pad is false, leftover bits must be zero padding.
+func bech32ConvertBits(data []byte, fromBits uint, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+	var res []byte
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data value %d", b)
+		}
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			res = append(res, byte(acc>>bits&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			res = append(res, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return res, nil
+}
+
+// bech32Encode encodes the data with the given human readable part in lowercase bech32.
+// Unlike BIP 173, the length of the result is not limited, as age keys do not fit in 90 characters.
+func bech32Encode(hrp string, data []byte) string {
+	values, _ := bech32ConvertBits(data, 8, 5, true)
+
+	checksumInput := append(bech32HRPExpand(hrp), values...)
+	checksumInput = append(checksumInput, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(checksumInput) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// bech32Decode decodes a bech32 string into its lowercase human readable part and data.
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("mixed case")
+	}
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndex(s, "1")
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, fmt.Errorf("invalid separator position")
+	}
+
+	hrp := s[:pos]
+	values := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", s[i])
+		}
+		values = append(values, byte(v))
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("invalid checksum")
+	}
+
+	data, err := bech32ConvertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
@@ -0,0 +1,349 @@
+package secrethub
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestBech32Decode(t *testing.T) {
+	cases := map[string]struct {
+		in   string
+		hrp  string
+		data string
+		ok   bool
+	}{
+		"empty data": {
+			in:  "A12UEL5L",
+			hrp: "a",
+			ok:  true,
+		},
+		"all characters": {
+			in:   "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+			hrp:  "abcdef",
+			data: "00443214c74254b635cf84653a56d7c675be77df",
+			ok:   true,
+		},
+		"invalid checksum": {
+			in: "a12uel5m",
+		},
+		"mixed case": {
+			in: "A12uEL5L",
+		},
+		"invalid character": {
+			in: "a1b2uel5l",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hrp, data, err := bech32Decode(tc.in)
+
+			assert.Equal(t, err == nil, tc.ok)
+			if tc.ok {
+				assert.Equal(t, hrp, tc.hrp)
+				assert.Equal(t, hex.EncodeToString(data), tc.data)
+				assert.Equal(t, bech32Encode(hrp, data), strings.ToLower(tc.in))
+			}
+		})
+	}
+}
+
+func TestAgeIdentity(t *testing.T) {
+	// Test vector from RFC 7748, section 6.1.
+	secretKey, _ := hex.DecodeString("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	publicKey, _ := hex.DecodeString("8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a")
+
+	var identity ageIdentity
+	copy(identity.secretKey[:], secretKey)
+	recipient := identity.Recipient()
+	assert.Equal(t, recipient.publicKey[:], publicKey)
+
+	parsedIdentity, err := parseAgeIdentity(identity.String())
+	assert.OK(t, err)
+	assert.Equal(t, parsedIdentity, identity)
+
+	parsedRecipient, err := parseAgeRecipient(recipient.String())
+	assert.OK(t, err)
+	assert.Equal(t, parsedRecipient, recipient)
+
+	_, err = parseAgeIdentity(recipient.String())
+	assert.Equal(t, err, ErrInvalidAgeIdentity)
+
+	_, err = parseAgeRecipient(identity.String())
+	assert.Equal(t, err, ErrInvalidAgeRecipient(identity.String()))
+}
+
+// The known answers below were generated with the reference implementation of age (filippo.io/age v1.2.1),
+// to check that the keys and files of this implementation are compatible with it.
+const (
+	ageTestAliceIdentity  = "AGE-SECRET-KEY-1HA8N08YMK0M5J93KMJ4Y9X28RDLZN5058T45GV5DF5G47AYEMFCQ243S70"
+	ageTestAliceRecipient = "age1fq80dq2nwvwqa46yd3ks0fmlt20che0vxw3k8u5dn3952xm4ssfqzctq0v"
+	ageTestBobIdentity    = "AGE-SECRET-KEY-15473THW4DVYXDFVYPQZA34LGM7QLLNP8M5DZ6F7CHXA3U8KFA97S8Z2CFX"
+	ageTestBobRecipient   = "age19y69g9a4ugrvktrh4l0spedgyj03wrqqhu9t732u8aqgdlxk89usj69d46"
+)
+
+func TestAgeIdentity_KnownAnswer(t *testing.T) {
+	cases := map[string]struct {
+		identity  string
+		recipient string
+	}{
+		"alice": {
+			identity:  ageTestAliceIdentity,
+			recipient: ageTestAliceRecipient,
+		},
+		"bob": {
+			identity:  ageTestBobIdentity,
+			recipient: ageTestBobRecipient,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			identity, err := parseAgeIdentity(tc.identity)
+			assert.OK(t, err)
+			assert.Equal(t, identity.String(), tc.identity)
+			assert.Equal(t, identity.Recipient().String(), tc.recipient)
+
+			recipient, err := parseAgeRecipient(tc.recipient)
+			assert.OK(t, err)
+			assert.Equal(t, recipient, identity.Recipient())
+		})
+	}
+}
+
+func TestAgeDecrypt_KnownAnswer(t *testing.T) {
+	// Files encrypted to both alice and bob by the reference implementation.
+	cases := map[string]struct {
+		file      string
+		plaintext string
+	}{
+		"empty": {
+			file: "YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBqNFg1alBPOEo4Q3lLQ0dzTWhzdHJiRkRCQTU5TkZ5NitMZnFGaHNTdWtRCkpjbVhEMlBu" +
+				"U1JYanhWcThTQ0EyejlNYWRZM0lVTEI1dHh1K2dmUFNIVHMKLT4gWDI1NTE5IDBxcmFZbFlrSnNkT0VFTWNmRU0wa293UGtJTm1iR3R6b2VlYTVU" +
+				"a21zM1UKSW1za3F5Vi9wcmRiQnlOMU54aGZlWDNuVzFLYmkwbnhwWE5EU3k0WXRKdwotLS0gQUhxaWsyUndqTEh3VFl5c3VpUWZqYVRKZktKT1pS" +
+				"M2Z4K0hML3RDTUhNUQrb7W4Q3+LAQaVndxhPTuqzW+X/cDCmQyEpJJq3SpNR/Q==",
+			plaintext: "",
+		},
+		"short": {
+			file: "YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBMckxxYnA2RTRIMXVoU3oxTkFQUnpvejNBMU1QYUtabVZ2cCs4NzFVSFVrCjlYNEhFdU4w" +
+				"cERybWdzWVlIM0RHRXMwRzNyNFVVdnB0T1lXTkRTT1hOdlkKLT4gWDI1NTE5IFc4MnZVN2FJVW5UZE1sbCsvZERIWnNQQ0Fkc1NWYzQ5c3B1c2Fa" +
+				"SGZjeDQKNkJ5c3RBK0hrS21GQ0JlZDM1cEpjblNaNGJIemhteTZQVTgyMnl1M09rVQotLS0gK1lBVmxzSlJ0UW5ody9leGRadmZ3cFBPVGFrM1Ex" +
+				"ZW1CMHNEeG9mSjQwQQrbwk9ix5sdYpqeLx2Px/Fm1PVYzo5oOKUGal9j7WTbAnY25B/cJzDumJvkXj/Fv93KskLf",
+			plaintext: "db_password=hunter2\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file, err := base64.StdEncoding.DecodeString(tc.file)
+			assert.OK(t, err)
+
+			for _, s := range []string{ageTestAliceIdentity, ageTestBobIdentity} {
+				identity, err := parseAgeIdentity(s)
+				assert.OK(t, err)
+
+				actual, err := ageDecrypt(bytes.NewReader(file), identity)
+				assert.OK(t, err)
+				assert.Equal(t, string(actual), tc.plaintext)
+			}
+		})
+	}
+}
+
+func TestAgeEncrypt(t *testing.T) {
+	cases := map[string]int{
+		"empty":                0,
+		"short":                10,
+		"exactly one chunk":    ageChunkSize,
+		"one chunk and a byte": ageChunkSize + 1,
+		"multiple chunks":      3*ageChunkSize + 100,
+		"exactly three chunks": 3 * ageChunkSize,
+	}
+
+	for name, size := range cases {
+		t.Run(name, func(t *testing.T) {
+			plaintext := make([]byte, size)
+			_, err := rand.Read(plaintext)
+			assert.OK(t, err)
+
+			alice, err := generateAgeIdentity()
+			assert.OK(t, err)
+			bob, err := generateAgeIdentity()
+			assert.OK(t, err)
+			eve, err := generateAgeIdentity()
+			assert.OK(t, err)
+
+			var buf bytes.Buffer
+			w, err := ageEncrypt(&buf, alice.Recipient(), bob.Recipient())
+			assert.OK(t, err)
+			// Write in uneven parts to cover the buffering of chunks.
+			for p := plaintext; len(p) > 0; {
+				n := 1000
+				if n > len(p) {
+					n = len(p)
+				}
+				_, err = w.Write(p[:n])
+				assert.OK(t, err)
+				p = p[n:]
+			}
+			assert.OK(t, w.Close())
+
+			for _, identity := range []ageIdentity{alice, bob} {
+				actual, err := ageDecrypt(bytes.NewReader(buf.Bytes()), identity)
+				assert.OK(t, err)
+				assert.Equal(t, actual, plaintext)
+			}
+
+			_, err = ageDecrypt(bytes.NewReader(buf.Bytes()), eve)
+			assert.Equal(t, err, errAgeNoIdentityMatched)
+		})
+	}
+}
+
+var errAgeNoIdentityMatched = errors.New("no identity matched any of the recipients")
+
+// ageDecrypt decrypts an age v1 file encrypted to X25519 recipients with the identity.
+func ageDecrypt(r io.Reader, identity ageIdentity) ([]byte, error) {
+	br := bufio.NewReader(r)
+	var header bytes.Buffer
+	readLine := func() (string, error) {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		header.WriteString(line)
+		return strings.TrimSuffix(line, "\n"), nil
+	}
+
+	line, err := readLine()
+	if err != nil {
+		return nil, err
+	}
+	if line != ageVersionLine {
+		return nil, errors.New("unexpected version line")
+	}
+
+	var fileKey []byte
+	for {
+		line, err = readLine()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(line, "--- ") {
+			break
+		}
+
+		args := strings.Fields(strings.TrimPrefix(line, "-> "))
+		var body string
+		for {
+			bodyLine, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+			body += bodyLine
+			if len(bodyLine) < ageColumnsPerLine {
+				break
+			}
+		}
+		if len(args) != 2 || args[0] != "X25519" || fileKey != nil {
+			continue
+		}
+
+		share, err := base64.RawStdEncoding.DecodeString(args[1])
+		if err != nil {
+			return nil, err
+		}
+		wrapped, err := base64.RawStdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, err
+		}
+
+		var theirShare, sharedSecret [32]byte
+		copy(theirShare[:], share)
+		curve25519.ScalarMult(&sharedSecret, &identity.secretKey, &theirShare)
+		recipient := identity.Recipient()
+		wrapKey, err := ageHKDF(sharedSecret[:], append(share, recipient.publicKey[:]...), ageX25519Label)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := chacha20poly1305.New(wrapKey)
+		if err != nil {
+			return nil, err
+		}
+		fileKey, err = aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), wrapped, nil)
+		if err != nil {
+			fileKey = nil
+		}
+	}
+	if fileKey == nil {
+		return nil, errAgeNoIdentityMatched
+	}
+
+	macKey, err := ageHKDF(fileKey, nil, "header")
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(header.Bytes()[:header.Len()-len(line)-1+len("---")])
+	expectedMAC, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(line, "--- "))
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac.Sum(nil), expectedMAC) {
+		return nil, errors.New("invalid header MAC")
+	}
+
+	payload, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < ageStreamNonceSize {
+		return nil, errors.New("missing payload nonce")
+	}
+	payloadKey, err := ageHKDF(fileKey, payload[:ageStreamNonceSize], "payload")
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(payloadKey)
+	if err != nil {
+		return nil, err
+	}
+
+	payload = payload[ageStreamNonceSize:]
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	plaintext := []byte{}
+	for counter := byte(0); ; counter++ {
+		n := ageChunkSize + aead.Overhead()
+		last := len(payload) <= n
+		if last {
+			n = len(payload)
+			nonce[len(nonce)-1] = 1
+		}
+		nonce[len(nonce)-2] = counter
+
+		chunk, err := aead.Open(nil, nonce, payload[:n], nil)
+		if err != nil {
+			return nil, err
+		}
+		plaintext = append(plaintext, chunk...)
+		payload = payload[n:]
+		if last {
+			return plaintext, nil
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+
+	"github.com/alecthomas/kingpin"
+)
+
+// Errors
+var (
+	ErrInvalidAliasName  = errMain.Code("invalid_alias_name").ErrorPref("invalid alias name %s: an alias name can only contain letters, digits, dashes and underscores and cannot start with a dash")
+	ErrAliasShadowsCmd   = errMain.Code("alias_shadows_command").ErrorPref("cannot use %s as alias name, because it is the name of a command")
+	ErrAliasNotFound     = errMain.Code("alias_not_found").ErrorPref("there is no alias named %s")
+	ErrInvalidAlias      = errMain.Code("invalid_alias").ErrorPref("invalid alias %s: %s")
+	ErrUnterminatedQuote = errMain.Code("unterminated_quote").Error("unterminated quote")
+)
+
+// aliasNamePattern matches valid alias names.
+var aliasNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_-]*$`)
+
+// expandAliases replaces the command in the args with the command-line of the alias with that
+// name in the configuration file. Global flags before the command are kept. An alias is only
+// expanded once, so an alias cannot refer to another alias. When the aliases cannot be read,
+// a warning is printed and the args are returned unchanged.
+func (app *App) expandAliases(args []string) []string {
+	dir, err := aliasConfigDir(args)
+	if err != nil {
+		return args
+	}
+
+	config, err := loadConfig(dir)
+	if err != nil {
+		warnConfig(err)
+		return args
+	}
+
+	expanded, err := expandAlias(args, config.Aliases, app.cli.Model().Flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s\n", colorize(colorRoleWarning, "Warning:"), err)
+		return args
+	}
+	return expanded
+}
+
+// aliasConfigDir returns the configuration directory that is configured with the --config-dir
+// flag or environment variable. The args are not parsed yet when aliases are expanded, so
+// the flag is looked up directly.
+func aliasConfigDir(args []string) (configdir.Dir, error) {
+	var dir ConfigDir
+
+	value := os.Getenv(strings.ToUpper(ApplicationName) + "_CONFIG_DIR")
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--config-dir" && i+1 < len(args) {
+			value = args[i+1]
+		} else if strings.HasPrefix(arg, "--config-dir=") {
+			value = strings.TrimPrefix(arg, "--config-dir=")
+		}
+	}
+
+	err := dir.Set(value)
+	if err != nil {
+		return configdir.Dir{}, err
+	}
+	return dir.Dir, nil
+}
+
+// expandAlias replaces the first argument that is not a flag with the command-line of the alias
+// with that name. The flags are used to skip the values of the global flags before the command.
+func expandAlias(args []string, aliases map[string]string, flags []*kingpin.FlagModel) ([]string, error) {
+	if len(aliases) == 0 {
+		return args, nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args, nil
+		}
+		if strings.HasPrefix(arg, "-") {
+			if takesValue(arg, flags) {
+				i++
+			}
+			continue
+		}
+
+		alias, ok := aliases[arg]
+		if !ok {
+			return args, nil
+		}
+
+		expansion, err := splitCommandLine(alias)
+		if err != nil {
+			return nil, ErrInvalidAlias(arg, err)
+		}
+
+		res := make([]string, 0, len(args)+len(expansion))
+		res = append(res, args[:i]...)
+		res = append(res, expansion...)
+		res = append(res, args[i+1:]...)
+		return res, nil
+	}
+	return args, nil
+}
+
+// takesValue returns whether the flag argument is followed by a separate argument with its value.
+func takesValue(arg string, flags []*kingpin.FlagModel) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+
+	for _, flag := range flags {
+		if arg == "--"+flag.Name || (flag.Short != 0 && arg == "-"+string(flag.Short)) {
+			return !flag.IsBoolFlag()
+		}
+	}
+	return false
+}
+
+// splitCommandLine splits a command-line into arguments like a shell does. Arguments are
+// separated by whitespace, which can be included in an argument with single or double quotes
+// or by escaping it with a backslash.
+func splitCommandLine(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, c := range s {
+		switch {
+		case escaped:
+			current.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, ErrUnterminatedQuote
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// AliasCommand handles the user-defined aliases of commands.
+type AliasCommand struct {
+	io              ui.IO
+	app             *cli.App
+	credentialStore CredentialConfig
+}
+
+// NewAliasCommand creates a new AliasCommand.
+func NewAliasCommand(io ui.IO, app *cli.App, credentialStore CredentialConfig) *AliasCommand {
+	return &AliasCommand{
+		io:              io,
+		app:             app,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *AliasCommand) Register(r command.Registerer) {
+	clause := r.Command("alias", "Manage aliases of commands. "+
+		"An alias is expanded to its command-line when it is used as command, e.g. `secrethub prodread` runs `secrethub read --no-newline company/prod` "+
+		"for an alias prodread of `read --no-newline company/prod`. Arguments and flags after the alias are appended to the command-line. "+
+		"The aliases are stored in the aliases section of the "+configFilename+" file in the configuration directory.")
+	NewAliasListCommand(cmd.io, cmd.credentialStore).Register(clause)
+	NewAliasSetCommand(cmd.io, cmd.app, cmd.credentialStore).Register(clause)
+	NewAliasRmCommand(cmd.io, cmd.credentialStore).Register(clause)
+}
+
+// AliasListCommand lists the aliases.
+type AliasListCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewAliasListCommand creates a new AliasListCommand.
+func NewAliasListCommand(io ui.IO, credentialStore CredentialConfig) *AliasListCommand {
+	return &AliasListCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AliasListCommand) Register(r command.Registerer) {
+	clause := r.Command("ls", "List the aliases.")
+	clause.Alias("list")
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the aliases in alphabetical order.
+func (cmd *AliasListCommand) Run() error {
+	var config cliConfig
+	err := configFile(cmd.credentialStore.ConfigDir()).read(&config)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(config.Aliases))
+	for name := range config.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\n", "ALIAS", "COMMAND")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, config.Aliases[name])
+	}
+	return w.Flush()
+}
+
+// AliasSetCommand creates or updates an alias.
+type AliasSetCommand struct {
+	io              ui.IO
+	app             *cli.App
+	credentialStore CredentialConfig
+	name            string
+	commandLine     string
+}
+
+// NewAliasSetCommand creates a new AliasSetCommand.
+func NewAliasSetCommand(io ui.IO, app *cli.App, credentialStore CredentialConfig) *AliasSetCommand {
+	return &AliasSetCommand{
+		io:              io,
+		app:             app,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AliasSetCommand) Register(r command.Registerer) {
+	clause := r.Command("set", "Create or update an alias.")
+	clause.Arg("name", "The name of the alias.").Required().StringVar(&cmd.name)
+	clause.Arg("command", "The command-line the alias expands to, in quotes, e.g. 'read --no-newline company/prod'.").Required().StringVar(&cmd.commandLine)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run stores the alias in the configuration file.
+func (cmd *AliasSetCommand) Run() error {
+	if !aliasNamePattern.MatchString(cmd.name) {
+		return ErrInvalidAliasName(cmd.name)
+	}
+
+	for _, c := range cmd.app.Model().Commands {
+		if c.Name == cmd.name {
+			return ErrAliasShadowsCmd(cmd.name)
+		}
+		for _, alias := range c.Aliases {
+			if alias == cmd.name {
+				return ErrAliasShadowsCmd(cmd.name)
+			}
+		}
+	}
+
+	args, err := splitCommandLine(cmd.commandLine)
+	if err != nil {
+		return ErrInvalidAlias(cmd.name, err)
+	}
+	if len(args) == 0 {
+		return ErrInvalidAlias(cmd.name, "the command cannot be empty")
+	}
+
+	var config cliConfig
+	err = configFile(cmd.credentialStore.ConfigDir()).update(&config, func() {
+		if config.Aliases == nil {
+			config.Aliases = map[string]string{}
+		}
+		config.Aliases[cmd.name] = cmd.commandLine
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Alias %s is set to `%s`.\n", cmd.name, cmd.commandLine)
+	return nil
+}
+
+// AliasRmCommand removes an alias.
+type AliasRmCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+	name            string
+}
+
+// NewAliasRmCommand creates a new AliasRmCommand.
+func NewAliasRmCommand(io ui.IO, credentialStore CredentialConfig) *AliasRmCommand {
+	return &AliasRmCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AliasRmCommand) Register(r command.Registerer) {
+	clause := r.Command("rm", "Remove an alias.")
+	clause.Alias("remove")
+	clause.Arg("name", "The name of the alias.").Required().StringVar(&cmd.name)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run removes the alias from the configuration file.
+func (cmd *AliasRmCommand) Run() error {
+	var config cliConfig
+	found := false
+	err := configFile(cmd.credentialStore.ConfigDir()).update(&config, func() {
+		_, found = config.Aliases[cmd.name]
+		delete(config.Aliases, cmd.name)
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrAliasNotFound(cmd.name)
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Alias %s is removed.\n", cmd.name)
+	return nil
+}
@@ -0,0 +1,152 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+
+	"github.com/alecthomas/kingpin"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	cases := map[string]struct {
+		in       string
+		expected []string
+		err      error
+	}{
+		"empty": {
+			in: "",
+		},
+		"words": {
+			in:       "read --no-newline company/prod",
+			expected: []string{"read", "--no-newline", "company/prod"},
+		},
+		"extra whitespace": {
+			in:       "  read\t company/prod ",
+			expected: []string{"read", "company/prod"},
+		},
+		"double quotes": {
+			in:       `write --note "rotated by ops" company/prod`,
+			expected: []string{"write", "--note", "rotated by ops", "company/prod"},
+		},
+		"single quotes": {
+			in:       `run --template 'a \b'`,
+			expected: []string{"run", "--template", `a \b`},
+		},
+		"escaped space": {
+			in:       `read my\ secret`,
+			expected: []string{"read", "my secret"},
+		},
+		"empty quotes": {
+			in:       `write ""`,
+			expected: []string{"write", ""},
+		},
+		"unterminated quote": {
+			in:  `read "company/prod`,
+			err: ErrUnterminatedQuote,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := splitCommandLine(tc.in)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	debug := quietFlag(false)
+	flags := []*kingpin.FlagModel{
+		{Name: "debug", Short: 'D', Value: &debug},
+		{Name: "config-dir", Value: &ConfigDir{}},
+	}
+	aliases := map[string]string{
+		"prodread": "read --no-newline company/prod",
+	}
+
+	cases := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"alias": {
+			args:     []string{"prodread"},
+			expected: []string{"read", "--no-newline", "company/prod"},
+		},
+		"extra args": {
+			args:     []string{"prodread", "--clip"},
+			expected: []string{"read", "--no-newline", "company/prod", "--clip"},
+		},
+		"global flags": {
+			args:     []string{"--debug", "--config-dir", "prodread", "prodread"},
+			expected: []string{"--debug", "--config-dir", "prodread", "read", "--no-newline", "company/prod"},
+		},
+		"short flag": {
+			args:     []string{"-D", "prodread"},
+			expected: []string{"-D", "read", "--no-newline", "company/prod"},
+		},
+		"flag with value": {
+			args:     []string{"--config-dir=/tmp", "prodread"},
+			expected: []string{"--config-dir=/tmp", "read", "--no-newline", "company/prod"},
+		},
+		"command": {
+			args:     []string{"read", "prodread"},
+			expected: []string{"read", "prodread"},
+		},
+		"after double dash": {
+			args:     []string{"--", "prodread"},
+			expected: []string{"--", "prodread"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := expandAlias(tc.args, aliases, flags)
+
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestAliasCommands(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	store := &credentialConfig{configDir: ConfigDir{Dir: configdir.New(dir)}}
+	app := cli.NewApp("secrethub", "")
+	app.Command("read", "Read a secret.")
+
+	io := fakeui.NewIO(t)
+	set := NewAliasSetCommand(io, app, store)
+
+	set.name = "read"
+	set.commandLine = "ls"
+	assert.Equal(t, set.Run(), ErrAliasShadowsCmd("read"))
+
+	set.name = "-x"
+	assert.Equal(t, set.Run(), ErrInvalidAliasName("-x"))
+
+	set.name = "prodread"
+	set.commandLine = "read --no-newline company/prod"
+	assert.OK(t, set.Run())
+
+	var config cliConfig
+	assert.OK(t, configFile(store.ConfigDir()).read(&config))
+	assert.Equal(t, config.Aliases, map[string]string{"prodread": "read --no-newline company/prod"})
+
+	io = fakeui.NewIO(t)
+	assert.OK(t, NewAliasListCommand(io, store).Run())
+	assert.Equal(t, io.Out.String(), "ALIAS     COMMAND\nprodread  read --no-newline company/prod\n")
+
+	rm := NewAliasRmCommand(io, store)
+	rm.name = "prodread"
+	assert.OK(t, rm.Run())
+	assert.Equal(t, rm.Run(), ErrAliasNotFound("prodread"))
+}
@@ -0,0 +1,66 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// The constants of the Ansible Vault 1.1 format with the AES256 cipher.
+const (
+	ansibleVaultHeader         = "$ANSIBLE_VAULT;1.1;AES256"
+	ansibleVaultSaltSize       = 32
+	ansibleVaultKeySize        = 32
+	ansibleVaultIterations     = 10000
+	ansibleVaultColumnsPerLine = 80
+)
+
+// ansibleVaultEncrypt encrypts the plaintext with the password in the format of `ansible-vault encrypt`,
+// so it can be decrypted by Ansible with the same vault password.
+func ansibleVaultEncrypt(plaintext []byte, password []byte) ([]byte, error) {
+	salt := make([]byte, ansibleVaultSaltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The derived key holds the key of the cipher, the key of the HMAC and the initial counter.
+	derived := pbkdf2.Key(password, salt, ansibleVaultIterations, 2*ansibleVaultKeySize+aes.BlockSize, sha256.New)
+	cipherKey := derived[:ansibleVaultKeySize]
+	hmacKey := derived[ansibleVaultKeySize : 2*ansibleVaultKeySize]
+	iv := derived[2*ansibleVaultKeySize:]
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ansible pads the plaintext with PKCS#7, even though CTR mode does not need it.
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, ciphertext)
+
+	mac := hmac.New(sha256.New, hmacKey)
+	_, _ = mac.Write(ciphertext)
+
+	payload := hex.EncodeToString(salt) + "\n" + hex.EncodeToString(mac.Sum(nil)) + "\n" + hex.EncodeToString(ciphertext)
+	encoded := hex.EncodeToString([]byte(payload))
+
+	var buf bytes.Buffer
+	buf.WriteString(ansibleVaultHeader)
+	for len(encoded) > 0 {
+		n := ansibleVaultColumnsPerLine
+		if len(encoded) < n {
+			n = len(encoded)
+		}
+		buf.WriteString("\n" + encoded[:n])
+		encoded = encoded[n:]
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ansibleVaultDecrypt decrypts the output of ansibleVaultEncrypt the way Ansible does.
+func ansibleVaultDecrypt(vaultText []byte, password []byte) ([]byte, error) {
+	lines := strings.Split(string(vaultText), "\n")
+	if lines[0] != ansibleVaultHeader {
+		return nil, errors.New("invalid header")
+	}
+	for _, line := range lines[1:] {
+		if len(line) > ansibleVaultColumnsPerLine {
+			return nil, errors.New("line too long")
+		}
+	}
+
+	payload, err := hex.DecodeString(strings.Join(lines[1:], ""))
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(string(payload), "\n")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid payload")
+	}
+	salt, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	expectedMAC, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	derived := pbkdf2.Key(password, salt, 10000, 80, sha256.New)
+	mac := hmac.New(sha256.New, derived[32:64])
+	_, _ = mac.Write(ciphertext)
+	if !hmac.Equal(mac.Sum(nil), expectedMAC) {
+		return nil, errors.New("invalid password")
+	}
+
+	block, err := aes.NewCipher(derived[:32])
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, derived[64:]).XORKeyStream(plaintext, ciphertext)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding < 1 || padding > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("invalid padding")
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func TestAnsibleVaultEncrypt(t *testing.T) {
+	cases := map[string][]byte{
+		"empty":       {},
+		"short":       []byte("hunter2"),
+		"block sized": []byte("0123456789abcdef"),
+		"multiline":   []byte(strings.Repeat("a long secret value\n", 20)),
+	}
+
+	for name, plaintext := range cases {
+		t.Run(name, func(t *testing.T) {
+			vaultText, err := ansibleVaultEncrypt(plaintext, []byte("vault password"))
+			assert.OK(t, err)
+
+			actual, err := ansibleVaultDecrypt(vaultText, []byte("vault password"))
+			assert.OK(t, err)
+			assert.Equal(t, actual, plaintext)
+
+			_, err = ansibleVaultDecrypt(vaultText, []byte("wrong password"))
+			assert.Equal(t, err, errors.New("invalid password"))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package secrethub
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"text/template"
 
@@ -69,6 +71,7 @@ type App struct {
 	cli             *cli.App
 	io              ui.IO
 	logger          cli.Logger
+	errorWriter     errorWriter
 }
 
 // newClientFunc creates a ClientAdapater.
@@ -76,8 +79,14 @@ type newClientFunc func() (secrethub.ClientInterface, error)
 
 // NewApp creates a new command-line application.
 func NewApp() *App {
-	io := ui.NewUserIO()
+	io := ciIO{IO: ui.NewUserIO()}
 	store := NewCredentialConfig(io)
+	return newApp(io, store, NewClientFactory(io, store), false)
+}
+
+// newApp creates a new command-line application that creates its clients with the client factory.
+// The applications that run the commands of a batch cannot run a batch themselves.
+func newApp(io ui.IO, store CredentialConfig, clientFactory ClientFactory, batched bool) *App {
 	help := "The SecretHub command-line interface is a unified tool to manage your infrastructure secrets with SecretHub.\n\n" +
 		"For a step-by-step introduction, check out:\n\n" +
 		"  https://secrethub.io/docs/getting-started/\n\n" +
@@ -85,7 +94,8 @@ func NewApp() *App {
 		"  https://secrethub.io/support/\n\n" +
 		"The CLI is configurable through command-line flags and environment variables. " +
 		"Options set on the command-line take precedence over those set in the environment. " +
-		"The format for environment variables is `SECRETHUB_[COMMAND_]FLAG_NAME`."
+		"The format for environment variables is `SECRETHUB_[COMMAND_]FLAG_NAME`.\n\n" +
+		exitCodesHelp
 
 	app := App{
 		cli: cli.NewApp(ApplicationName, help).ExtraEnvVarFunc(
@@ -94,7 +104,7 @@ func NewApp() *App {
 			},
 		),
 		credentialStore: store,
-		clientFactory:   NewClientFactory(store),
+		clientFactory:   clientFactory,
 		io:              io,
 		logger:          cli.NewLogger(),
 	}
@@ -102,9 +112,21 @@ func NewApp() *App {
 	RegisterDebugFlag(app.cli, app.logger)
 	RegisterMlockFlag(app.cli)
 	RegisterColorFlag(app.cli)
+	RegisterVerbosityFlags(app.cli)
+	RegisterLogFlags(app.cli)
+	RegisterPagerFlag(app.cli)
+	RegisterCIDetection(app.cli)
+	RegisterConcurrencyFlag(app.cli)
+	app.errorWriter.Register(app.cli)
 	app.credentialStore.Register(app.cli)
+	RegisterColorTheme(app.cli.Application, app.credentialStore)
+	RegisterPagerConfig(app.cli.Application, app.credentialStore)
 	app.clientFactory.Register(app.cli)
 	app.registerCommands()
+	if !batched {
+		NewBatchCommand(app.io, app.runBatched, app.HandleError).Register(app.cli)
+	}
+	newPathCompleter(app.clientFactory.NewNonInteractiveClient, app.credentialStore).Register(app.cli.Application)
 
 	app.cli.UsageTemplate(DefaultUsageTemplate)
 	app.cli.UsageFuncs(template.FuncMap{
@@ -149,9 +171,39 @@ func (app *App) Version(version string, commit string) *App {
 // Run builds the command-line application, parses the arguments,
 // configures global behavior and executes the command given by the args.
 func (app *App) Run(args []string) error {
+	err := app.run(args)
+	structuredLog.finish(err)
+	if summary := apiThrottle.summary(); summary != "" {
+		fmt.Fprintln(statusWriter(os.Stderr), summary)
+	}
+	return err
+}
+
+// run expands the aliases in the args, parses them and executes the command.
+func (app *App) run(args []string) error {
+	args = app.expandAliases(args)
+
 	// Parse also executes the command when parsing is successful.
 	_, err := app.cli.Parse(args)
-	return err
+	if err != nil {
+		return app.suggest(args, err)
+	}
+	return nil
+}
+
+// runBatched executes the command given by the args as a command of a batch. The command is
+// parsed by a new application, so the flags of earlier commands do not carry over, but it
+// shares the client of this application, so the connections to the API and the account key
+// are reused.
+func (app *App) runBatched(args []string) error {
+	store := NewCredentialConfig(app.io)
+	return newApp(app.io, store, batchClientFactory{app.clientFactory}, true).run(args)
+}
+
+// HandleError writes the error returned by Run to w in the format configured with
+// the --error-format flag and returns the exit code for the error.
+func (app *App) HandleError(w io.Writer, err error) int {
+	return app.errorWriter.Write(w, err)
 }
 
 // Model returns the CLI application model containing all the SecretHub CLI commands, flags, and args.
@@ -164,29 +216,69 @@ func (app *App) registerCommands() {
 
 	// Management commands
 	NewOrgCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewRepoCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewACLCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewRepoCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
+	NewNamespaceCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewBackupCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewACLCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
 	NewServiceCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewAccountCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
 	NewCredentialCommand(app.io, app.clientFactory, app.credentialStore).Register(app.cli)
 	NewConfigCommand(app.io, app.credentialStore).Register(app.cli)
+	NewCacheCommand(app.io, app.credentialStore).Register(app.cli)
+	NewDevCommand(app.io).Register(app.cli)
+	NewVaultCommand(app.io, app.clientFactory).Register(app.cli)
 	NewEnvCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewPolicyCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewImportCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewExportCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewSopsCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewSSHCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewX509Command(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewJWTCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewWebhookCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewEventsCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewNotifyCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewDocsCommand(app.io, app.cli).Register(app.cli)
+	NewAliasCommand(app.io, app.cli, app.credentialStore).Register(app.cli)
 
 	// Commands
 	NewInitCommand(app.io, app.clientFactory.NewUnauthenticatedClient, app.clientFactory.NewClientWithCredentials, app.credentialStore).Register(app.cli)
 	NewSignUpCommand(app.io, app.clientFactory.NewUnauthenticatedClient, app.credentialStore).Register(app.cli)
-	NewWriteCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewWriteCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
 	NewReadCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewTOTPCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewGenerateSecretCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewLsCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewLinkCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewSignCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
+	NewVerifyCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewMkDirCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewRmCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewGCCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
 	NewTreeCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewBrowseCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewPickCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewOpenCommand(app.io, app.clientFactory.APIRemote).Register(app.cli)
 	NewInspectCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewAuditCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewAccessReportCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewScanCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewInstallHooksCommand(app.io).Register(app.cli)
 	NewInjectCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewRunCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewShellCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewMountCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewSyncCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewPlanCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewApplyCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewDriftCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewComposeCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewBenchmarkCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewPrintEnvCommand(app.cli, app.io).Register(app.cli)
+	NewCompletionCommand(app.io).Register(app.cli)
+	NewSelfUpdateCommand(app.io).Register(app.cli)
+	NewDoctorCommand(app.io, app.clientFactory.NewClient, app.credentialStore, app.clientFactory.APIRemote).Register(app.cli)
+	NewCompatCommand(app.io, app.clientFactory.APIRemote, app.clientFactory.NewAPITransport).Register(app.cli)
 
 	// Hidden commands
 	NewClearCommand(app.io).Register(app.cli)
@@ -0,0 +1,89 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// ApplyCommand creates and updates directories, secrets and access rules to match a manifest.
+type ApplyCommand struct {
+	io        ui.IO
+	file      string
+	force     bool
+	newClient newClientFunc
+}
+
+// NewApplyCommand creates a new ApplyCommand.
+func NewApplyCommand(io ui.IO, newClient newClientFunc) *ApplyCommand {
+	return &ApplyCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ApplyCommand) Register(r command.Registerer) {
+	clause := r.Command("apply", "Create and update directories, secrets and access rules to match a manifest. "+
+		"The changes are shown and must be confirmed before they are made. Resources that are not declared in the manifest are left untouched. "+manifestHelp)
+	clause.Arg("manifest-file", "The path to the YAML manifest").Required().ExistingFileVar(&cmd.file)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run applies the manifest.
+func (cmd *ApplyCommand) Run() error {
+	m, err := readManifest(cmd.file)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := m.plan(client)
+	if err != nil {
+		return err
+	}
+
+	err = plan.print(cmd.io.Output())
+	if err != nil {
+		return err
+	}
+	if len(plan.changes) == 0 {
+		return nil
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.AskYesNo(cmd.io, "Do you want to make these changes?", ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return ErrAborted
+		}
+	}
+
+	for _, change := range plan.changes {
+		err = change.apply(client)
+		if err != nil {
+			return err
+		}
+
+		verb := "Created"
+		if change.action == planActionUpdate {
+			verb = "Updated"
+		}
+		fmt.Fprintf(cmd.io.Output(), "%s %s %s\n", verb, change.kind, change.resource)
+	}
+
+	created, updated := plan.counts()
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Apply complete! Resources: %d created, %d updated.\n", created, updated)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 
 	"github.com/secrethub/secrethub-go/internals/errio"
 
@@ -23,12 +24,16 @@ import (
 var (
 	errAudit        = errio.Namespace("audit")
 	errNoSuchFormat = errAudit.Code("invalid_format").ErrorPref("invalid format: %s")
+
+	ErrInvalidPollInterval = errAudit.Code("invalid_poll_interval").ErrorPref("poll-interval should be positive, got %s")
 )
 
 const (
 	defaultTerminalWidth = 80
 	formatTable          = "table"
 	formatJSON           = "json"
+	formatCSV            = "csv"
+	formatJSONLines      = "json-lines"
 	pipedOutputLineLimit = 1000
 )
 
@@ -43,15 +48,22 @@ type AuditCommand struct {
 	terminalWidth      func(int) (int, error)
 	perPage            int
 	maxResults         int
+	after              string
 	format             string
+	filter             auditFilter
+	follow             bool
+	versions           bool
+	pollInterval       time.Duration
+	sleep              func(time.Duration)
 }
 
 // NewAuditCommand creates a new audit command.
 func NewAuditCommand(io ui.IO, newClient newClientFunc) *AuditCommand {
 	return &AuditCommand{
 		io:                 io,
-		newPaginatedWriter: pager.NewWithFallback,
+		newPaginatedWriter: newPaginatedWriter,
 		newClient:          newClient,
+		sleep:              time.Sleep,
 		terminalWidth: func(fd int) (int, error) {
 			w, _, err := terminal.GetSize(fd)
 			return w, err
@@ -60,18 +72,33 @@ func NewAuditCommand(io ui.IO, newClient newClientFunc) *AuditCommand {
 }
 
 // Register registers the command, arguments and flags on the provided Registerer.
+// Showing the audit log is the default sub-command of audit, so that it can be
+// used without naming it next to the other audit sub-commands.
 func (cmd *AuditCommand) Register(r command.Registerer) {
 	defaultLimit := -1
 	if cmd.io.IsOutputPiped() {
 		defaultLimit = pipedOutputLineLimit
 	}
 
-	clause := r.Command("audit", "Show the audit log.")
+	auditClause := r.Command("audit", "Show, export or forward the audit log.")
+	NewAuditExportCommand(cmd.io, cmd.newClient).Register(auditClause)
+	NewAuditForwardCommand(cmd.io, cmd.newClient).Register(auditClause)
+
+	clause := auditClause.Command("show", "Show the audit log. This is the default when no sub-command is given.")
+	clause.Default()
 	clause.Arg("repo-path or secret-path", "Path to the repository or the secret to audit "+repoPathPlaceHolder+" or "+secretPathPlaceHolder).SetValue(&cmd.path)
 	clause.Flag("per-page", "Number of audit events shown per page").Default("20").Hidden().IntVar(&cmd.perPage)
-	clause.Flag("output-format", "Specify the format in which to output the log. Options are: table and json. If the output of the command is parsed by a script an alternative of the table format must be used.").HintOptions("table", "json").Default("table").StringVar(&cmd.format)
+	registerOutputFlag(clause, &cmd.format, formatTable, formatJSON, formatYAML)
+	// --output-format is the name of the --output flag from before the output flag was shared with other commands.
+	clause.Flag("output-format", "Specify the format in which to output the log. Options are: table, json, json-lines and yaml. The json format writes every event on its own line, json-lines is an alias for it.").Hidden().StringVar(&cmd.format)
 	clause.Flag("max-results", "Specify the number of entries to list. If maxResults < 0 all entries are displayed. If the output of the command is piped, maxResults defaults to 1000.").Default(strconv.Itoa(defaultLimit)).IntVar(&cmd.maxResults)
+	clause.Flag("after", "Only show the events that were logged before the event with this ID, e.g. the EventID of the last event of the previous page. "+
+		"Combine it with --max-results to page through the audit log. Only available with --output json or yaml.").StringVar(&cmd.after)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerAuditFilterFlags(clause, &cmd.filter)
+	clause.Flag("follow", "Keep polling for new events and print them as they arrive, until interrupted. Events that were already logged when the command started are not printed.").BoolVar(&cmd.follow)
+	clause.Flag("poll-interval", "The interval at which to poll for new events when --follow is set.").Default("5s").DurationVar(&cmd.pollInterval)
+	clause.Flag("versions", "Show the chain of custody of a secret: the events on its versions, grouped per version from newest to oldest and in the order in which they happened. Only available when auditing a secret.").BoolVar(&cmd.versions)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -79,12 +106,21 @@ func (cmd *AuditCommand) Register(r command.Registerer) {
 // Run prints all audit events for the given repository or secret.
 func (cmd *AuditCommand) Run() error {
 	cmd.beforeRun()
+	if cmd.follow && cmd.versions {
+		return ErrFlagsConflict("--follow and --versions")
+	}
+	if cmd.follow {
+		return cmd.runFollow()
+	}
+	if cmd.versions {
+		return cmd.runVersions()
+	}
 	return cmd.run()
 }
 
 // beforeRun configures the command using the flag values.
 func (cmd *AuditCommand) beforeRun() {
-	if cmd.format == formatJSON {
+	if isMachineReadable(cmd.format) {
 		cmd.timeFormatter = NewTimeFormatter(true)
 	} else {
 		cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
@@ -92,10 +128,14 @@ func (cmd *AuditCommand) beforeRun() {
 }
 
 // Run prints all audit events for the given repository or secret.
+// The events are printed as they are fetched, page by page.
 func (cmd *AuditCommand) run() error {
 	if cmd.perPage < 1 {
 		return fmt.Errorf("per-page should be positive, got %d", cmd.perPage)
 	}
+	if cmd.after != "" && !isMachineReadable(cmd.format) {
+		return ErrPagingWithoutOutput("--after")
+	}
 
 	iter, auditTable, err := cmd.iterAndAuditTable()
 	if err != nil {
@@ -108,22 +148,19 @@ func (cmd *AuditCommand) run() error {
 	}
 	defer paginatedWriter.Close()
 
-	var formatter listFormatter
-	if cmd.format == formatJSON {
-		formatter = newJSONFormatter(paginatedWriter, auditTable.header())
-	} else if cmd.format == formatTable && cmd.io.IsOutputPiped() {
-		formatter = newLineFormatter(paginatedWriter)
-	} else if cmd.format == formatTable {
-		terminalWidth, err := cmd.terminalWidth(int(cmd.io.Stdout().Fd()))
-		if err != nil {
-			terminalWidth = defaultTerminalWidth
-		}
-		formatter = newTableFormatter(paginatedWriter, terminalWidth, auditTable.columns())
-	} else {
-		return errNoSuchFormat(cmd.format)
+	// In machine readable formats, the ID of every event is included, so it can be passed to --after.
+	withEventID := isMachineReadable(cmd.format)
+	header := auditTable.header()
+	if withEventID {
+		header = append(header, "event ID")
+	}
+	formatter, err := newAuditFormatter(cmd.io, cmd.terminalWidth, cmd.format, paginatedWriter, header, auditTable.columns())
+	if err != nil {
+		return err
 	}
 
-	for lineCount := 0; lineCount != cmd.maxResults; lineCount++ {
+	skipping := cmd.after != ""
+	for lineCount := 0; lineCount != cmd.maxResults; {
 		event, err := iter.Next()
 		if err == iterator.Done {
 			break
@@ -131,10 +168,25 @@ func (cmd *AuditCommand) run() error {
 			return err
 		}
 
+		if cmd.filter.isBeforeRange(event) {
+			break
+		}
+		if skipping {
+			skipping = event.EventID.String() != cmd.after
+			continue
+		}
+		if !cmd.filter.matches(event) {
+			continue
+		}
+		lineCount++
+
 		row, err := auditTable.row(event)
 		if err != nil {
 			return err
 		}
+		if withEventID {
+			row = append(row, event.EventID.String())
+		}
 
 		err = formatter.Write(row)
 		if err == pager.ErrPagerClosed {
@@ -143,9 +195,37 @@ func (cmd *AuditCommand) run() error {
 			return err
 		}
 	}
+	if skipping {
+		return ErrCursorNotFound(cmd.after)
+	}
 	return nil
 }
 
+// newFormatter returns a formatter that writes audit events to the given writer in the configured format.
+func (cmd *AuditCommand) newFormatter(w io.Writer, auditTable auditTable) (listFormatter, error) {
+	return newAuditFormatter(cmd.io, cmd.terminalWidth, cmd.format, w, auditTable.header(), auditTable.columns())
+}
+
+// newAuditFormatter returns a formatter that writes audit events with the given columns to w in the given format.
+func newAuditFormatter(io ui.IO, terminalWidth func(int) (int, error), format string, w io.Writer, header []string, columns []tableColumn) (listFormatter, error) {
+	switch {
+	case format == formatJSON || format == formatJSONLines:
+		return newJSONFormatter(w, header), nil
+	case format == formatYAML:
+		return newYAMLFormatter(w, header), nil
+	case (format == formatTable || format == "") && io.IsOutputPiped():
+		return newLineFormatter(w), nil
+	case format == formatTable || format == "":
+		width, err := terminalWidth(int(io.Stdout().Fd()))
+		if err != nil {
+			width = defaultTerminalWidth
+		}
+		return newTableFormatter(w, width, columns), nil
+	default:
+		return nil, errNoSuchFormat(format)
+	}
+}
+
 func (cmd *AuditCommand) iterAndAuditTable() (secrethub.AuditEventIterator, auditTable, error) {
 	repoPath, err := cmd.path.ToRepoPath()
 	if err == nil {
@@ -0,0 +1,465 @@
+package secrethub
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// Errors
+var (
+	ErrInvalidAuditCursor = errAudit.Code("invalid_cursor").ErrorPref("cannot read cursor file %s: %s")
+)
+
+const (
+	formatCEF  = "cef"
+	formatLEEF = "leef"
+
+	// auditExportVendor and auditExportProduct identify the source of exported events in CEF and LEEF headers.
+	auditExportVendor  = "SecretHub"
+	auditExportProduct = "secrethub-cli"
+	// auditExportVersion is the version of the exported record layout.
+	// It should be incremented whenever fields are renamed or removed.
+	auditExportVersion = "1"
+)
+
+// AuditExportCommand exports audit events as records for log management systems.
+type AuditExportCommand struct {
+	io         ui.IO
+	path       api.Path
+	format     string
+	filter     auditFilter
+	cursorFile string
+	newClient  newClientFunc
+}
+
+// NewAuditExportCommand creates a new AuditExportCommand.
+func NewAuditExportCommand(io ui.IO, newClient newClientFunc) *AuditExportCommand {
+	return &AuditExportCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AuditExportCommand) Register(r command.Registerer) {
+	clause := r.Command("export", "Export the audit log of a repository or a secret for ingestion by log management systems. "+
+		"Every exported record has the same fields, for both repositories and secrets: "+
+		"EventID, Time (RFC3339 in UTC), Action (e.g. read.secret_version), Category (read, write, delete or acl), "+
+		"Severity (0-10), Actor (username or service ID), ActorType, Subject, SubjectType, Repo and IPAddress. "+
+		"Events are exported from oldest to newest.")
+	clause.Arg("repo-path or secret-path", "Path to the repository or the secret to export the audit log of "+repoPathPlaceHolder+" or "+secretPathPlaceHolder).Required().SetValue(&cmd.path)
+	clause.Flag("format", "The format of the exported records. Options are: json (one object per line), csv (starting with a header record), cef (ArcSight Common Event Format) and leef (QRadar Log Event Extended Format 1.0).").HintOptions(formatJSON, formatCSV, formatCEF, formatLEEF).Default(formatJSON).StringVar(&cmd.format)
+	registerAuditFilterFlags(clause, &cmd.filter)
+	clause.Flag("cursor-file", "Path to a file in which the position of the newest exported event is stored. When set, only events that have not been exported before are exported. Use this for scheduled, incremental exports.").StringVar(&cmd.cursorFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run exports the audit events matching the filters. When a cursor file is used,
+// the cursor is advanced past the events that have been written.
+func (cmd *AuditExportCommand) Run() error {
+	formatter, err := newAuditRecordFormatter(cmd.io.Output(), cmd.format)
+	if err != nil {
+		return err
+	}
+
+	var pos auditPosition
+	if cmd.cursorFile != "" {
+		pos, err = readAuditCursor(cmd.cursorFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	iter, newRecord, err := auditRecordSource(cmd.path, cmd.newClient)
+	if err != nil {
+		return err
+	}
+
+	var events []api.Audit
+	for {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if event.LoggedAt.Before(pos.at) || cmd.filter.isBeforeRange(event) {
+			break
+		}
+		if pos.isSeen(event) || !cmd.filter.matches(event) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	// Events are listed from newest to oldest, so they are written in reverse.
+	var writeErr error
+	for i := len(events) - 1; i >= 0; i-- {
+		record, err := newRecord(events[i])
+		if err != nil {
+			writeErr = err
+			break
+		}
+
+		err = formatter.Write(record)
+		if err != nil {
+			writeErr = err
+			break
+		}
+		pos.advance(events[i])
+	}
+
+	if cmd.cursorFile != "" {
+		err = writeAuditCursor(cmd.cursorFile, pos)
+		if err != nil {
+			return err
+		}
+	}
+	return writeErr
+}
+
+// auditRecordSource returns an iterator over the audit events of the path
+// and a function to convert those events to records.
+func auditRecordSource(path api.Path, newClient newClientFunc) (secrethub.AuditEventIterator, func(api.Audit) (auditRecord, error), error) {
+	repoPath, err := path.ToRepoPath()
+	if err == nil {
+		client, err := newClient()
+		if err != nil {
+			return nil, nil, err
+		}
+		tree, err := client.Dirs().GetTree(repoPath.GetDirPath().Value(), -1, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		iter := client.Repos().EventIterator(repoPath.Value(), &secrethub.AuditEventIteratorParams{})
+		newRecord := func(event api.Audit) (auditRecord, error) {
+			subject, err := getAuditSubject(event, tree)
+			if err != nil {
+				return auditRecord{}, err
+			}
+			return newAuditRecord(event, repoPath, subject)
+		}
+		return iter, newRecord, nil
+	}
+
+	secretPath, err := path.ToSecretPath()
+	if err == nil {
+		if path.HasVersion() {
+			return nil, nil, ErrCannotAuditSecretVersion
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		isDir, err := client.Dirs().Exists(secretPath.Value())
+		if err == nil && isDir {
+			return nil, nil, ErrCannotAuditDir
+		}
+
+		iter := client.Secrets().EventIterator(secretPath.Value(), &secrethub.AuditEventIteratorParams{})
+		newRecord := func(event api.Audit) (auditRecord, error) {
+			subject, err := getSecretAuditSubject(event, secretPath)
+			if err != nil {
+				return auditRecord{}, err
+			}
+			return newAuditRecord(event, secretPath.GetRepoPath(), subject)
+		}
+		return iter, newRecord, nil
+	}
+
+	return nil, nil, ErrNoValidRepoOrSecretPath
+}
+
+// getSecretAuditSubject returns the subject of an event of a secret's audit log.
+// Secret audit logs are not accompanied by a directory tree, so the path of
+// the audited secret is used for the secret and its versions.
+func getSecretAuditSubject(event api.Audit, secretPath api.SecretPath) (string, error) {
+	if !event.Subject.Deleted {
+		switch event.Subject.Type {
+		case api.AuditSubjectSecret:
+			return secretPath.String(), nil
+		case api.AuditSubjectSecretVersion:
+			return fmt.Sprintf("%s:%d", secretPath.String(), event.Subject.SecretVersion.Version), nil
+		}
+	}
+	return getAuditSubject(event, nil)
+}
+
+// auditRecord is an exported audit event. The record has the same fields for
+// repository and secret audit logs. Fields can be added, but renaming or
+// removing a field requires incrementing auditExportVersion.
+type auditRecord struct {
+	// EventID uniquely identifies the event.
+	EventID string
+	// Time is the time at which the event was logged, formatted as RFC3339 in UTC.
+	Time string
+	// Action is the action performed on the subject, e.g. read.secret_version.
+	Action string
+	// Category is the kind of action: read, write, delete or acl.
+	Category string
+	// Severity is the importance of the event on the 0-10 scale used by CEF and LEEF.
+	Severity int
+	// Actor is the username or service ID of the account that performed the action.
+	Actor string
+	// ActorType is user, service or account when the actor has been deleted.
+	ActorType string
+	// Subject is the name or path of the resource the action was performed on.
+	Subject string
+	// SubjectType is the type of the subject, e.g. secret_version.
+	SubjectType string
+	// Repo is the path of the audited repository.
+	Repo string
+	// IPAddress is the IP address from which the action was performed.
+	IPAddress string
+
+	loggedAt time.Time
+}
+
+// auditRecordFields are the names of the fields of an audit record, in the order of auditRecord.values.
+var auditRecordFields = []string{
+	"EventID", "Time", "Action", "Category", "Severity", "Actor", "ActorType", "Subject", "SubjectType", "Repo", "IPAddress",
+}
+
+// newAuditRecord converts an audit event to a record.
+func newAuditRecord(event api.Audit, repo api.RepoPath, subject string) (auditRecord, error) {
+	actor, err := getAuditActor(event)
+	if err != nil {
+		return auditRecord{}, err
+	}
+
+	return auditRecord{
+		EventID:     event.EventID.String(),
+		Time:        event.LoggedAt.UTC().Format(time.RFC3339),
+		Action:      getEventAction(event),
+		Category:    auditActionCategory(event),
+		Severity:    auditEventSeverity(event),
+		Actor:       actor,
+		ActorType:   event.Actor.Type,
+		Subject:     subject,
+		SubjectType: string(event.Subject.Type),
+		Repo:        repo.String(),
+		IPAddress:   event.IPAddress,
+		loggedAt:    event.LoggedAt,
+	}, nil
+}
+
+// values returns the values of the record's fields, in the order of auditRecordFields.
+func (r auditRecord) values() []string {
+	return []string{
+		r.EventID, r.Time, r.Action, r.Category, strconv.Itoa(r.Severity), r.Actor, r.ActorType, r.Subject, r.SubjectType, r.Repo, r.IPAddress,
+	}
+}
+
+// auditEventSeverity returns the severity of an event on the 0-10 scale used by CEF and LEEF.
+// Reads are informational, deletions and changes to access are considered most important.
+func auditEventSeverity(event api.Audit) int {
+	switch auditActionCategory(event) {
+	case auditActionRead:
+		return 3
+	case auditActionDelete, auditActionACL:
+		return 7
+	default:
+		return 5
+	}
+}
+
+// auditRecordFormatter writes audit records.
+type auditRecordFormatter interface {
+	Write(record auditRecord) error
+}
+
+// newAuditRecordFormatter returns a formatter that writes records in the given format.
+func newAuditRecordFormatter(w io.Writer, format string) (auditRecordFormatter, error) {
+	switch format {
+	case formatJSON:
+		return auditJSONFormatter{encoder: json.NewEncoder(w)}, nil
+	case formatCSV:
+		return &auditCSVFormatter{writer: csv.NewWriter(w)}, nil
+	case formatCEF:
+		return auditCEFFormatter{writer: w}, nil
+	case formatLEEF:
+		return auditLEEFFormatter{writer: w}, nil
+	default:
+		return nil, errNoSuchFormat(format)
+	}
+}
+
+// auditJSONFormatter writes every record as a JSON object on its own line.
+type auditJSONFormatter struct {
+	encoder *json.Encoder
+}
+
+// Write writes the record as a JSON object.
+func (f auditJSONFormatter) Write(record auditRecord) error {
+	return f.encoder.Encode(record)
+}
+
+// auditCSVFormatter writes records as CSV, preceded by a header record.
+type auditCSVFormatter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+// Write writes the record as a CSV record.
+// The header is written on the first call, before any other record.
+func (f *auditCSVFormatter) Write(record auditRecord) error {
+	if !f.headerWritten {
+		err := f.writer.Write(auditRecordFields)
+		if err != nil {
+			return err
+		}
+		f.headerWritten = true
+	}
+
+	err := f.writer.Write(record.values())
+	if err != nil {
+		return err
+	}
+	f.writer.Flush()
+	return f.writer.Error()
+}
+
+// auditCEFFormatter writes records in the ArcSight Common Event Format.
+type auditCEFFormatter struct {
+	writer io.Writer
+}
+
+// Write writes the record as a single CEF line. The time is given in milliseconds since
+// the epoch as the receipt time, the fields without a standard CEF key are written as
+// custom string fields.
+func (f auditCEFFormatter) Write(record auditRecord) error {
+	extensions := []string{
+		"rt=" + strconv.FormatInt(record.loggedAt.UnixNano()/int64(time.Millisecond), 10),
+		"externalId=" + escapeCEFExtension(record.EventID),
+		"act=" + escapeCEFExtension(record.Action),
+		"cat=" + escapeCEFExtension(record.Category),
+		"suser=" + escapeCEFExtension(record.Actor),
+		"src=" + escapeCEFExtension(record.IPAddress),
+		"fname=" + escapeCEFExtension(record.Subject),
+		"cs1Label=ActorType cs1=" + escapeCEFExtension(record.ActorType),
+		"cs2Label=SubjectType cs2=" + escapeCEFExtension(record.SubjectType),
+		"cs3Label=Repo cs3=" + escapeCEFExtension(record.Repo),
+	}
+
+	_, err := fmt.Fprintf(f.writer, "CEF:0|%s|%s|%s|%s|%s|%d|%s\n",
+		escapeCEFHeader(auditExportVendor),
+		escapeCEFHeader(auditExportProduct),
+		escapeCEFHeader(auditExportVersion),
+		escapeCEFHeader(record.Action),
+		escapeCEFHeader(record.Action),
+		record.Severity,
+		strings.Join(extensions, " "),
+	)
+	return err
+}
+
+// escapeCEFHeader escapes pipes and backslashes in CEF header fields.
+func escapeCEFHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ").Replace(s)
+}
+
+// escapeCEFExtension escapes equal signs, backslashes and newlines in CEF extension values.
+func escapeCEFExtension(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// auditLEEFFormatter writes records in the IBM QRadar Log Event Extended Format (LEEF 1.0).
+type auditLEEFFormatter struct {
+	writer io.Writer
+}
+
+// Write writes the record as a single LEEF line with tab separated attributes.
+func (f auditLEEFFormatter) Write(record auditRecord) error {
+	attributes := []string{
+		"devTime=" + escapeLEEFAttribute(record.Time),
+		"devTimeFormat=yyyy-MM-dd'T'HH:mm:ssXXX",
+		"eventId=" + escapeLEEFAttribute(record.EventID),
+		"cat=" + escapeLEEFAttribute(record.Category),
+		"sev=" + strconv.Itoa(record.Severity),
+		"usrName=" + escapeLEEFAttribute(record.Actor),
+		"src=" + escapeLEEFAttribute(record.IPAddress),
+		"resource=" + escapeLEEFAttribute(record.Subject),
+		"actorType=" + escapeLEEFAttribute(record.ActorType),
+		"subjectType=" + escapeLEEFAttribute(record.SubjectType),
+		"repo=" + escapeLEEFAttribute(record.Repo),
+	}
+
+	_, err := fmt.Fprintf(f.writer, "LEEF:1.0|%s|%s|%s|%s|%s\n",
+		escapeCEFHeader(auditExportVendor),
+		escapeCEFHeader(auditExportProduct),
+		escapeCEFHeader(auditExportVersion),
+		escapeCEFHeader(record.Action),
+		strings.Join(attributes, "\t"),
+	)
+	return err
+}
+
+// escapeLEEFAttribute replaces the characters that LEEF uses as delimiters.
+func escapeLEEFAttribute(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+// auditCursor is the contents of a cursor file. It stores the position of the
+// newest exported events, so that subsequent exports only include new events.
+type auditCursor struct {
+	LastLoggedAt time.Time   `json:"last_logged_at"`
+	EventIDs     []uuid.UUID `json:"event_ids"`
+}
+
+// readAuditCursor reads the position from the given cursor file.
+// A file that does not exist yet results in the zero position.
+func readAuditCursor(path string) (auditPosition, error) {
+	var cursor auditCursor
+	err := jsonFile{path: path}.read(&cursor)
+	if err != nil {
+		return auditPosition{}, ErrInvalidAuditCursor(path, err)
+	}
+
+	if cursor.LastLoggedAt.IsZero() {
+		return auditPosition{}, nil
+	}
+
+	pos := auditPosition{
+		at:   cursor.LastLoggedAt,
+		seen: make(map[uuid.UUID]bool, len(cursor.EventIDs)),
+	}
+	for _, id := range cursor.EventIDs {
+		pos.seen[id] = true
+	}
+	return pos, nil
+}
+
+// writeAuditCursor writes the position to the given cursor file.
+func writeAuditCursor(path string, pos auditPosition) error {
+	cursor := auditCursor{
+		LastLoggedAt: pos.at,
+		EventIDs:     []uuid.UUID{},
+	}
+	for id := range pos.seen {
+		cursor.EventIDs = append(cursor.EventIDs, id)
+	}
+	sort.Slice(cursor.EventIDs, func(i, j int) bool {
+		return cursor.EventIDs[i].String() < cursor.EventIDs[j].String()
+	})
+
+	return jsonFile{path: path}.write(cursor)
+}
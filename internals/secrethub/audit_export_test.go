@@ -0,0 +1,298 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestAuditRecordFormatters(t *testing.T) {
+	records := []auditRecord{
+		{
+			EventID:     "e1",
+			Time:        "2020-01-01T12:00:00Z",
+			Action:      "read.secret_version",
+			Category:    auditActionRead,
+			Severity:    3,
+			Actor:       "dev1",
+			ActorType:   "user",
+			Subject:     "dev1/repo/a=b:1",
+			SubjectType: "secret_version",
+			Repo:        "dev1/repo",
+			IPAddress:   "127.0.0.1",
+			loggedAt:    time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			EventID:     "e2",
+			Time:        "2020-01-01T12:01:00Z",
+			Action:      "invite.user",
+			Category:    auditActionACL,
+			Severity:    7,
+			Actor:       "s-abc",
+			ActorType:   "service",
+			Subject:     "Developer|One (dev2)",
+			SubjectType: "user",
+			Repo:        "dev1/repo",
+			IPAddress:   "10.0.0.1",
+			loggedAt:    time.Date(2020, 1, 1, 12, 1, 0, 0, time.UTC),
+		},
+	}
+
+	cases := map[string]struct {
+		format   string
+		expected string
+		err      error
+	}{
+		"json": {
+			format: formatJSON,
+			expected: `{"EventID":"e1","Time":"2020-01-01T12:00:00Z","Action":"read.secret_version","Category":"read","Severity":3,"Actor":"dev1","ActorType":"user","Subject":"dev1/repo/a=b:1","SubjectType":"secret_version","Repo":"dev1/repo","IPAddress":"127.0.0.1"}` + "\n" +
+				`{"EventID":"e2","Time":"2020-01-01T12:01:00Z","Action":"invite.user","Category":"acl","Severity":7,"Actor":"s-abc","ActorType":"service","Subject":"Developer|One (dev2)","SubjectType":"user","Repo":"dev1/repo","IPAddress":"10.0.0.1"}` + "\n",
+		},
+		"csv": {
+			format: formatCSV,
+			expected: "EventID,Time,Action,Category,Severity,Actor,ActorType,Subject,SubjectType,Repo,IPAddress\n" +
+				"e1,2020-01-01T12:00:00Z,read.secret_version,read,3,dev1,user,dev1/repo/a=b:1,secret_version,dev1/repo,127.0.0.1\n" +
+				"e2,2020-01-01T12:01:00Z,invite.user,acl,7,s-abc,service,Developer|One (dev2),user,dev1/repo,10.0.0.1\n",
+		},
+		"cef": {
+			format: formatCEF,
+			expected: "CEF:0|SecretHub|secrethub-cli|1|read.secret_version|read.secret_version|3|rt=1577880000000 externalId=e1 act=read.secret_version cat=read suser=dev1 src=127.0.0.1 fname=dev1/repo/a\\=b:1 cs1Label=ActorType cs1=user cs2Label=SubjectType cs2=secret_version cs3Label=Repo cs3=dev1/repo\n" +
+				"CEF:0|SecretHub|secrethub-cli|1|invite.user|invite.user|7|rt=1577880060000 externalId=e2 act=invite.user cat=acl suser=s-abc src=10.0.0.1 fname=Developer|One (dev2) cs1Label=ActorType cs1=service cs2Label=SubjectType cs2=user cs3Label=Repo cs3=dev1/repo\n",
+		},
+		"leef": {
+			format: formatLEEF,
+			expected: "LEEF:1.0|SecretHub|secrethub-cli|1|read.secret_version|devTime=2020-01-01T12:00:00Z\tdevTimeFormat=yyyy-MM-dd'T'HH:mm:ssXXX\teventId=e1\tcat=read\tsev=3\tusrName=dev1\tsrc=127.0.0.1\tresource=dev1/repo/a=b:1\tactorType=user\tsubjectType=secret_version\trepo=dev1/repo\n" +
+				"LEEF:1.0|SecretHub|secrethub-cli|1|invite.user|devTime=2020-01-01T12:01:00Z\tdevTimeFormat=yyyy-MM-dd'T'HH:mm:ssXXX\teventId=e2\tcat=acl\tsev=7\tusrName=s-abc\tsrc=10.0.0.1\tresource=Developer|One (dev2)\tactorType=service\tsubjectType=user\trepo=dev1/repo\n",
+		},
+		"invalid format": {
+			format: "xml",
+			err:    errNoSuchFormat("xml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			formatter, err := newAuditRecordFormatter(buf, tc.format)
+			assert.Equal(t, err, tc.err)
+
+			if err == nil {
+				for _, record := range records {
+					err := formatter.Write(record)
+					assert.OK(t, err)
+				}
+			}
+
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestAuditEventSeverity(t *testing.T) {
+	cases := map[string]struct {
+		event    api.Audit
+		expected int
+	}{
+		"read": {
+			event: api.Audit{
+				Action:  api.AuditActionRead,
+				Subject: api.AuditSubject{Type: api.AuditSubjectSecretVersion},
+			},
+			expected: 3,
+		},
+		"write": {
+			event: api.Audit{
+				Action:  api.AuditActionCreate,
+				Subject: api.AuditSubject{Type: api.AuditSubjectSecretVersion},
+			},
+			expected: 5,
+		},
+		"delete": {
+			event: api.Audit{
+				Action:  api.AuditActionDelete,
+				Subject: api.AuditSubject{Type: api.AuditSubjectSecret},
+			},
+			expected: 7,
+		},
+		"secret permission": {
+			event: api.Audit{
+				Action:  api.AuditActionCreate,
+				Subject: api.AuditSubject{Type: api.AuditSubjectSecretMember},
+			},
+			expected: 7,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, auditEventSeverity(tc.event), tc.expected)
+		})
+	}
+}
+
+func TestGetSecretAuditSubject(t *testing.T) {
+	secretPath := api.SecretPath("namespace/repo/dir/secret")
+
+	cases := map[string]struct {
+		event    api.Audit
+		expected string
+	}{
+		"secret": {
+			event: api.Audit{
+				Subject: api.AuditSubject{
+					Type:   api.AuditSubjectSecret,
+					Secret: &api.Secret{Name: "secret"},
+				},
+			},
+			expected: "namespace/repo/dir/secret",
+		},
+		"secret version": {
+			event: api.Audit{
+				Subject: api.AuditSubject{
+					Type:          api.AuditSubjectSecretVersion,
+					SecretVersion: &api.SecretVersion{Version: 3},
+				},
+			},
+			expected: "namespace/repo/dir/secret:3",
+		},
+		"secret permission": {
+			event: api.Audit{
+				Subject: api.AuditSubject{
+					Type:   api.AuditSubjectSecretMember,
+					User:   &api.User{Username: "dev1"},
+					Secret: &api.Secret{Name: "secret"},
+				},
+			},
+			expected: "dev1 => secret",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := getSecretAuditSubject(tc.event, secretPath)
+
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestAuditCursor(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "cursor.json")
+
+	pos, err := readAuditCursor(path)
+	assert.OK(t, err)
+	assert.Equal(t, pos, auditPosition{})
+
+	id := uuid.New()
+	expected := auditPosition{
+		at:   time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+		seen: map[uuid.UUID]bool{id: true},
+	}
+	err = writeAuditCursor(path, expected)
+	assert.OK(t, err)
+
+	pos, err = readAuditCursor(path)
+	assert.OK(t, err)
+	assert.Equal(t, pos.at.Equal(expected.at), true)
+	assert.Equal(t, pos.seen, expected.seen)
+}
+
+func TestAuditExportCommand_Run_CursorFile(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+	cursorFile := filepath.Join(dir, "cursor.json")
+
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(n int) api.Audit {
+		id, err := uuid.FromString(fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", n))
+		assert.OK(t, err)
+		return api.Audit{
+			EventID: id,
+			Action:  "create",
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{
+					Username: "developer",
+				},
+			},
+			LoggedAt: start.Add(time.Duration(n) * time.Minute),
+			Subject: api.AuditSubject{
+				Type: "repo",
+				Repo: &api.Repo{
+					Name: "repo",
+				},
+			},
+			IPAddress: "127.0.0.1",
+		}
+	}
+	record := func(n int) string {
+		return fmt.Sprintf(`{"EventID":"00000000-0000-0000-0000-00000000000%d","Time":"2020-01-01T12:0%d:00Z","Action":"create.repo","Category":"write","Severity":5,"Actor":"developer","ActorType":"user","Subject":"repo","SubjectType":"repo","Repo":"namespace/repo","IPAddress":"127.0.0.1"}`+"\n", n, n)
+	}
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			DirService: &fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return nil, nil
+				},
+			},
+			RepoService: &fakeclient.RepoService{
+				AuditEventIterator: &fakeclient.AuditEventIterator{
+					Events: []api.Audit{event(3), event(2), event(1)},
+				},
+			},
+		}, nil
+	}
+
+	// The first export excludes the newest event, which must not advance the cursor.
+	io := fakeui.NewIO(t)
+	cmd := AuditExportCommand{
+		io:         io,
+		path:       "namespace/repo",
+		format:     formatJSON,
+		cursorFile: cursorFile,
+		newClient:  newClient,
+		filter: auditFilter{
+			until: start.Add(150 * time.Second),
+		},
+	}
+
+	err := cmd.Run()
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), record(1)+record(2))
+
+	// The second export only includes the event that has not been exported yet.
+	io = fakeui.NewIO(t)
+	cmd = AuditExportCommand{
+		io:         io,
+		path:       "namespace/repo",
+		format:     formatJSON,
+		cursorFile: cursorFile,
+		newClient:  newClient,
+	}
+
+	err = cmd.Run()
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), record(3))
+
+	// Nothing is exported when there are no new events.
+	io = fakeui.NewIO(t)
+	cmd.io = io
+
+	err = cmd.Run()
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), "")
+}
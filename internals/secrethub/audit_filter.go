@@ -0,0 +1,249 @@
+package secrethub
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidAuditTime   = errAudit.Code("invalid_time").ErrorPref("invalid time %q: use an RFC3339 timestamp, a date (YYYY-MM-DD) or a duration relative to now (e.g. 24h or 7d)")
+	ErrInvalidAuditAction = errAudit.Code("invalid_action").ErrorPref("invalid action %q: must be one of read, write, delete or acl")
+	ErrInvalidAuditIP     = errAudit.Code("invalid_ip").ErrorPref("invalid IP address or CIDR range %q")
+)
+
+const (
+	auditActionRead   = "read"
+	auditActionWrite  = "write"
+	auditActionDelete = "delete"
+	auditActionACL    = "acl"
+
+	auditDateLayout = "2006-01-02"
+)
+
+// auditFilter selects the audit events to display.
+// The zero value matches all events.
+type auditFilter struct {
+	since   time.Time
+	until   time.Time
+	actors  []string
+	actions []string
+	ips     []*net.IPNet
+}
+
+// registerAuditFilterFlags registers the flags to filter audit events on the provided FlagRegisterer.
+func registerAuditFilterFlags(r FlagRegisterer, filter *auditFilter) {
+	r.Flag("since", "Only show events logged after this time. Accepts an RFC3339 timestamp, a date (YYYY-MM-DD) or a duration relative to now (e.g. 24h or 7d).").SetValue(&auditTimeValue{t: &filter.since})
+	r.Flag("until", "Only show events logged before this time. Accepts the same formats as --since. A date includes the whole day.").SetValue(&auditTimeValue{t: &filter.until, endOfDay: true})
+	r.Flag("actor", "Only show events performed by this account (username or service ID). Can be repeated.").StringsVar(&filter.actors)
+	r.Flag("action", "Only show events of this kind: read, write, delete or acl (membership changes). Can be repeated.").HintOptions(auditActionRead, auditActionWrite, auditActionDelete, auditActionACL).SetValue(&auditActionsValue{actions: &filter.actions})
+	r.Flag("ip", "Only show events originating from this IP address or CIDR range. Can be repeated.").SetValue(&auditIPsValue{ips: &filter.ips})
+}
+
+// isBeforeRange returns true when the event was logged before the start of the filtered time range.
+// As events are listed from newest to oldest, no events after this one can match either.
+func (f auditFilter) isBeforeRange(event api.Audit) bool {
+	return !f.since.IsZero() && event.LoggedAt.Before(f.since)
+}
+
+// matches returns whether the event passes all filters.
+func (f auditFilter) matches(event api.Audit) bool {
+	if !f.since.IsZero() && event.LoggedAt.Before(f.since) {
+		return false
+	}
+
+	if !f.until.IsZero() && event.LoggedAt.After(f.until) {
+		return false
+	}
+
+	if len(f.actors) > 0 {
+		actor, err := getAuditActor(event)
+		if err != nil || !containsString(f.actors, actor) {
+			return false
+		}
+	}
+
+	if len(f.actions) > 0 && !containsString(f.actions, auditActionCategory(event)) {
+		return false
+	}
+
+	if len(f.ips) > 0 {
+		ip := net.ParseIP(event.IPAddress)
+		if ip == nil {
+			return false
+		}
+
+		matched := false
+		for _, ipNet := range f.ips {
+			if ipNet.Contains(ip) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// auditActionCategory returns the kind of action (read, write, delete or acl) the event represents.
+func auditActionCategory(event api.Audit) string {
+	switch event.Subject.Type {
+	case api.AuditSubjectUser, api.AuditSubjectService, api.AuditSubjectRepoMember, api.AuditSubjectSecretMember:
+		return auditActionACL
+	}
+
+	switch event.Action {
+	case api.AuditActionRead:
+		return auditActionRead
+	case api.AuditActionDelete:
+		return auditActionDelete
+	default:
+		return auditActionWrite
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
+
+// auditTimeValue is a flag value for a point in time that can be given as an RFC3339 timestamp,
+// a date or a duration relative to now. When endOfDay is set, a date is interpreted as the last
+// moment of that day instead of its start, so that the whole day is included in the range.
+type auditTimeValue struct {
+	t        *time.Time
+	endOfDay bool
+}
+
+// Set parses the given time.
+func (v *auditTimeValue) Set(value string) error {
+	t, err := parseAuditTime(value, time.Now())
+	if err != nil {
+		return err
+	}
+	if v.endOfDay && isAuditDate(value) {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	*v.t = t
+	return nil
+}
+
+// String implements the flag.Value interface.
+func (v *auditTimeValue) String() string {
+	if v.t == nil || v.t.IsZero() {
+		return ""
+	}
+	return v.t.Format(time.RFC3339)
+}
+
+// parseAuditTime parses a timestamp, date or duration relative to now.
+func parseAuditTime(value string, now time.Time) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.ParseInLocation(auditDateLayout, value, time.Local)
+	if err == nil {
+		return t, nil
+	}
+
+	var d durationValue
+	err = d.Set(value)
+	if err == nil && d >= 0 {
+		return now.Add(-time.Duration(d)), nil
+	}
+
+	return time.Time{}, ErrInvalidAuditTime(value)
+}
+
+// isAuditDate returns whether the value is a date without a time.
+func isAuditDate(value string) bool {
+	_, err := time.Parse(auditDateLayout, value)
+	return err == nil
+}
+
+// auditActionsValue is a repeatable flag value for audit action categories.
+type auditActionsValue struct {
+	actions *[]string
+}
+
+// Set adds the given action category after validating it.
+func (v *auditActionsValue) Set(value string) error {
+	value = strings.ToLower(value)
+	switch value {
+	case auditActionRead, auditActionWrite, auditActionDelete, auditActionACL:
+		*v.actions = append(*v.actions, value)
+		return nil
+	}
+	return ErrInvalidAuditAction(value)
+}
+
+// String implements the flag.Value interface.
+func (v *auditActionsValue) String() string {
+	if v.actions == nil {
+		return ""
+	}
+	return strings.Join(*v.actions, ",")
+}
+
+// IsCumulative makes the flag repeatable when used in a Kingpin application.
+func (v *auditActionsValue) IsCumulative() bool {
+	return true
+}
+
+// auditIPsValue is a repeatable flag value for IP addresses and CIDR ranges.
+type auditIPsValue struct {
+	ips *[]*net.IPNet
+}
+
+// Set adds the given IP address or CIDR range. A single IP address is converted to a range containing only that address.
+func (v *auditIPsValue) Set(value string) error {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return ErrInvalidAuditIP(value)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		*v.ips = append(*v.ips, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		return nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return ErrInvalidAuditIP(value)
+	}
+	*v.ips = append(*v.ips, ipNet)
+	return nil
+}
+
+// String implements the flag.Value interface.
+func (v *auditIPsValue) String() string {
+	if v.ips == nil {
+		return ""
+	}
+	res := make([]string, len(*v.ips))
+	for i, ipNet := range *v.ips {
+		res[i] = ipNet.String()
+	}
+	return strings.Join(res, ",")
+}
+
+// IsCumulative makes the flag repeatable when used in a Kingpin application.
+func (v *auditIPsValue) IsCumulative() bool {
+	return true
+}
@@ -0,0 +1,237 @@
+package secrethub
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestAuditFilter_matches(t *testing.T) {
+	event := api.Audit{
+		Action: api.AuditActionRead,
+		Actor: api.AuditActor{
+			Type: "user",
+			User: &api.User{
+				Username: "developer",
+			},
+		},
+		IPAddress: "10.0.1.5",
+		LoggedAt:  time.Date(2020, 1, 15, 12, 0, 0, 0, time.UTC),
+		Subject: api.AuditSubject{
+			Type: api.AuditSubjectSecretVersion,
+		},
+	}
+
+	mustParseIPs := func(values ...string) []*net.IPNet {
+		var ips []*net.IPNet
+		v := auditIPsValue{ips: &ips}
+		for _, value := range values {
+			assert.OK(t, v.Set(value))
+		}
+		return ips
+	}
+
+	cases := map[string]struct {
+		filter   auditFilter
+		expected bool
+	}{
+		"no filters": {
+			expected: true,
+		},
+		"in time range": {
+			filter: auditFilter{
+				since: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				until: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expected: true,
+		},
+		"after until": {
+			filter: auditFilter{
+				until: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expected: false,
+		},
+		"actor match": {
+			filter: auditFilter{
+				actors: []string{"other", "developer"},
+			},
+			expected: true,
+		},
+		"actor mismatch": {
+			filter: auditFilter{
+				actors: []string{"other"},
+			},
+			expected: false,
+		},
+		"action match": {
+			filter: auditFilter{
+				actions: []string{auditActionRead},
+			},
+			expected: true,
+		},
+		"action mismatch": {
+			filter: auditFilter{
+				actions: []string{auditActionWrite, auditActionACL},
+			},
+			expected: false,
+		},
+		"ip match": {
+			filter: auditFilter{
+				ips: mustParseIPs("10.0.1.5"),
+			},
+			expected: true,
+		},
+		"cidr match": {
+			filter: auditFilter{
+				ips: mustParseIPs("192.168.0.1", "10.0.0.0/16"),
+			},
+			expected: true,
+		},
+		"ip mismatch": {
+			filter: auditFilter{
+				ips: mustParseIPs("10.0.2.0/24"),
+			},
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.filter.matches(event), tc.expected)
+		})
+	}
+}
+
+func TestParseAuditTime(t *testing.T) {
+	now := time.Date(2020, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		in       string
+		expected time.Time
+		err      error
+	}{
+		"rfc3339": {
+			in:       "2020-01-01T10:00:00Z",
+			expected: time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC),
+		},
+		"date": {
+			in:       "2020-01-01",
+			expected: time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local),
+		},
+		"relative hours": {
+			in:       "24h",
+			expected: time.Date(2020, 1, 14, 12, 0, 0, 0, time.UTC),
+		},
+		"relative days": {
+			in:       "7d",
+			expected: time.Date(2020, 1, 8, 12, 0, 0, 0, time.UTC),
+		},
+		"invalid": {
+			in:  "yesterday",
+			err: ErrInvalidAuditTime("yesterday"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseAuditTime(tc.in, now)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual.Equal(tc.expected), true)
+		})
+	}
+}
+
+func TestAuditTimeValue_Set(t *testing.T) {
+	cases := map[string]struct {
+		value    auditTimeValue
+		in       string
+		expected time.Time
+	}{
+		"date": {
+			in:       "2020-01-15",
+			expected: time.Date(2020, 1, 15, 0, 0, 0, 0, time.Local),
+		},
+		"date end of day": {
+			value:    auditTimeValue{endOfDay: true},
+			in:       "2020-01-15",
+			expected: time.Date(2020, 1, 15, 23, 59, 59, 999999999, time.Local),
+		},
+		"timestamp end of day": {
+			value:    auditTimeValue{endOfDay: true},
+			in:       "2020-01-15T10:00:00Z",
+			expected: time.Date(2020, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var actual time.Time
+			tc.value.t = &actual
+
+			err := tc.value.Set(tc.in)
+
+			assert.OK(t, err)
+			assert.Equal(t, actual.Equal(tc.expected), true)
+		})
+	}
+}
+
+func TestAuditActionCategory(t *testing.T) {
+	cases := map[string]struct {
+		event    api.Audit
+		expected string
+	}{
+		"read secret": {
+			event: api.Audit{
+				Action:  api.AuditActionRead,
+				Subject: api.AuditSubject{Type: api.AuditSubjectSecretVersion},
+			},
+			expected: auditActionRead,
+		},
+		"create secret": {
+			event: api.Audit{
+				Action:  api.AuditActionCreate,
+				Subject: api.AuditSubject{Type: api.AuditSubjectSecretVersion},
+			},
+			expected: auditActionWrite,
+		},
+		"delete secret": {
+			event: api.Audit{
+				Action:  api.AuditActionDelete,
+				Subject: api.AuditSubject{Type: api.AuditSubjectSecret},
+			},
+			expected: auditActionDelete,
+		},
+		"invite repo member": {
+			event: api.Audit{
+				Action:  api.AuditActionCreate,
+				Subject: api.AuditSubject{Type: api.AuditSubjectRepoMember},
+			},
+			expected: auditActionACL,
+		},
+		"revoke repo member": {
+			event: api.Audit{
+				Action:  api.AuditActionDelete,
+				Subject: api.AuditSubject{Type: api.AuditSubjectRepoMember},
+			},
+			expected: auditActionACL,
+		},
+		"create service": {
+			event: api.Audit{
+				Action:  api.AuditActionCreate,
+				Subject: api.AuditSubject{Type: api.AuditSubjectService},
+			},
+			expected: auditActionACL,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, auditActionCategory(tc.event), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package secrethub
+
+import (
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// runFollow polls for new audit events and prints them as they arrive, until the process is interrupted.
+// Events that were already logged when the command started are not printed.
+func (cmd *AuditCommand) runFollow() error {
+	if cmd.pollInterval <= 0 {
+		return ErrInvalidPollInterval(cmd.pollInterval)
+	}
+
+	iter, auditTable, err := cmd.iterAndAuditTable()
+	if err != nil {
+		return err
+	}
+
+	pos, err := newestAuditPosition(iter)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := cmd.newFormatter(cmd.io.Output(), auditTable)
+	if err != nil {
+		return err
+	}
+
+	for {
+		cmd.sleep(cmd.pollInterval)
+
+		iter, auditTable, err = cmd.iterAndAuditTable()
+		if err != nil {
+			return err
+		}
+
+		events, err := eventsAfter(iter, pos)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			pos.advance(event)
+			if !cmd.filter.matches(event) {
+				continue
+			}
+
+			row, err := auditTable.row(event)
+			if err != nil {
+				return err
+			}
+
+			err = formatter.Write(row)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// auditPosition marks up to which point audit events have been seen.
+// As multiple events can be logged at the same time, the IDs of the
+// events seen at that time are tracked too.
+type auditPosition struct {
+	at   time.Time
+	seen map[uuid.UUID]bool
+}
+
+// isSeen returns whether the event is at or before the position.
+func (p *auditPosition) isSeen(event api.Audit) bool {
+	if event.LoggedAt.Before(p.at) {
+		return true
+	}
+	return event.LoggedAt.Equal(p.at) && p.seen[event.EventID]
+}
+
+// advance moves the position to include the given event.
+func (p *auditPosition) advance(event api.Audit) {
+	if p.seen == nil || event.LoggedAt.After(p.at) {
+		p.at = event.LoggedAt
+		p.seen = make(map[uuid.UUID]bool)
+	}
+	if event.LoggedAt.Equal(p.at) {
+		p.seen[event.EventID] = true
+	}
+}
+
+// newestAuditPosition returns the position of the newest events.
+// The iterator is expected to return events from newest to oldest.
+func newestAuditPosition(iter secrethub.AuditEventIterator) (auditPosition, error) {
+	var pos auditPosition
+	for {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return auditPosition{}, err
+		}
+
+		if pos.seen != nil && event.LoggedAt.Before(pos.at) {
+			break
+		}
+		pos.advance(event)
+	}
+	return pos, nil
+}
+
+// eventsAfter returns all events that have not been seen at the given position, ordered from oldest
+// to newest. The position is not advanced, so that callers can advance it for the events they handled.
+// The iterator is expected to return events from newest to oldest.
+func eventsAfter(iter secrethub.AuditEventIterator, pos auditPosition) ([]api.Audit, error) {
+	var events []api.Audit
+	for {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if event.LoggedAt.Before(pos.at) {
+			break
+		}
+		if pos.isSeen(event) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events, nil
+}
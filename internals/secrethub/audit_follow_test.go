@@ -0,0 +1,196 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/fakes"
+)
+
+func TestEventsAfter(t *testing.T) {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	event := func(id int, offset time.Duration) api.Audit {
+		return api.Audit{EventID: ids[id], Action: "read", LoggedAt: start.Add(offset)}
+	}
+	seenAt := func(offset time.Duration, seen ...int) auditPosition {
+		pos := auditPosition{at: start.Add(offset), seen: make(map[uuid.UUID]bool)}
+		for _, id := range seen {
+			pos.seen[ids[id]] = true
+		}
+		return pos
+	}
+
+	cases := map[string]struct {
+		events      []api.Audit
+		pos         auditPosition
+		expected    []api.Audit
+		expectedPos auditPosition
+	}{
+		"no events": {
+			events:      []api.Audit{},
+			pos:         seenAt(0, 0),
+			expected:    nil,
+			expectedPos: seenAt(0, 0),
+		},
+		"no new events": {
+			events:      []api.Audit{event(0, 0), event(1, -time.Minute)},
+			pos:         seenAt(0, 0),
+			expected:    nil,
+			expectedPos: seenAt(0, 0),
+		},
+		"new events oldest first": {
+			events:      []api.Audit{event(3, 2*time.Minute), event(2, time.Minute), event(0, 0), event(1, -time.Minute)},
+			pos:         seenAt(0, 0),
+			expected:    []api.Audit{event(2, time.Minute), event(3, 2*time.Minute)},
+			expectedPos: seenAt(2*time.Minute, 3),
+		},
+		"new event at boundary": {
+			events:      []api.Audit{event(2, time.Minute), event(1, 0), event(0, 0)},
+			pos:         seenAt(0, 0),
+			expected:    []api.Audit{event(1, 0), event(2, time.Minute)},
+			expectedPos: seenAt(time.Minute, 2),
+		},
+		"new events at same time": {
+			events:      []api.Audit{event(2, time.Minute), event(1, time.Minute), event(0, 0)},
+			pos:         seenAt(0, 0),
+			expected:    []api.Audit{event(1, time.Minute), event(2, time.Minute)},
+			expectedPos: seenAt(time.Minute, 1, 2),
+		},
+		"all events new": {
+			events:      []api.Audit{event(1, time.Second), event(0, 0)},
+			pos:         auditPosition{},
+			expected:    []api.Audit{event(0, 0), event(1, time.Second)},
+			expectedPos: seenAt(time.Second, 1),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			iter := &fakeclient.AuditEventIterator{Events: tc.events}
+
+			actual, err := eventsAfter(iter, tc.pos)
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+
+			for _, event := range actual {
+				tc.pos.advance(event)
+			}
+			assert.Equal(t, tc.pos, tc.expectedPos)
+		})
+	}
+}
+
+func TestNewestAuditPosition(t *testing.T) {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	id1 := uuid.New()
+	id2 := uuid.New()
+
+	iter := &fakeclient.AuditEventIterator{
+		Events: []api.Audit{
+			{EventID: id1, LoggedAt: start},
+			{EventID: id2, LoggedAt: start},
+			{EventID: uuid.New(), LoggedAt: start.Add(-time.Minute)},
+		},
+	}
+
+	actual, err := newestAuditPosition(iter)
+
+	assert.OK(t, err)
+	assert.Equal(t, actual, auditPosition{at: start, seen: map[uuid.UUID]bool{id1: true, id2: true}})
+}
+
+func TestAuditCommand_runFollow(t *testing.T) {
+	testErr := errors.New("test error")
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	event := func(ip string, loggedAt time.Time) api.Audit {
+		return api.Audit{
+			EventID: uuid.New(),
+			Action:  "create",
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{
+					Username: "developer",
+				},
+			},
+			LoggedAt: loggedAt,
+			Subject: api.AuditSubject{
+				Type: "repo",
+				Repo: &api.Repo{
+					Name: "repo",
+				},
+			},
+			IPAddress: ip,
+		}
+	}
+	existing := event("127.0.0.1", start)
+	first := event("127.0.0.2", start)
+	second := event("127.0.0.3", start.Add(time.Minute))
+
+	polls := [][]api.Audit{
+		{existing},
+		{first, existing},
+		{second, first, existing},
+	}
+
+	var sleeps int
+	cmd := AuditCommand{
+		path:         "namespace/repo",
+		format:       formatJSON,
+		pollInterval: time.Second,
+		sleep: func(time.Duration) {
+			sleeps++
+		},
+		timeFormatter: &fakes.TimeFormatter{
+			Response: "2020-01-01T12:00:00Z",
+		},
+		newClient: func() (secrethub.ClientInterface, error) {
+			if len(polls) == 0 {
+				return nil, testErr
+			}
+			events := polls[0]
+			polls = polls[1:]
+			return fakeclient.Client{
+				DirService: &fakeclient.DirService{
+					GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+						return nil, nil
+					},
+				},
+				RepoService: &fakeclient.RepoService{
+					AuditEventIterator: &fakeclient.AuditEventIterator{
+						Events: events,
+					},
+				},
+			}, nil
+		},
+	}
+	io := fakeui.NewIO(t)
+	cmd.io = io
+
+	err := cmd.runFollow()
+
+	assert.Equal(t, err, testErr)
+	assert.Equal(t, sleeps, 3)
+	assert.Equal(t, io.Out.String(), ""+
+		"{\"Author\":\"developer\",\"Date\":\"2020-01-01T12:00:00Z\",\"Event\":\"create.repo\",\"EventSubject\":\"repo\",\"IPAddress\":\"127.0.0.2\"}\n"+
+		"{\"Author\":\"developer\",\"Date\":\"2020-01-01T12:00:00Z\",\"Event\":\"create.repo\",\"EventSubject\":\"repo\",\"IPAddress\":\"127.0.0.3\"}\n")
+}
+
+func TestAuditCommand_runFollow_InvalidInterval(t *testing.T) {
+	cmd := AuditCommand{
+		pollInterval: 0,
+	}
+
+	err := cmd.runFollow()
+
+	assert.Equal(t, err, ErrInvalidPollInterval(time.Duration(0)))
+}
@@ -0,0 +1,265 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidSyslogURL = errAudit.Code("invalid_syslog_url").ErrorPref("invalid syslog address %q: use udp://host:port or tcp://host:port")
+)
+
+const (
+	// syslogFacilityLogAudit is the log audit facility (13) defined in RFC5424.
+	syslogFacilityLogAudit = 13
+	// syslogDefaultPort is the port used when the syslog address does not contain one.
+	syslogDefaultPort = "514"
+	// syslogAppName identifies the CLI as the source of the messages.
+	syslogAppName = "secrethub"
+	// syslogStructuredDataID is the SD-ID of the structured data element containing the audit record.
+	// The enterprise number is the one reserved for documentation in RFC5612.
+	syslogStructuredDataID = "audit@32473"
+)
+
+// AuditForwardCommand forwards new audit events to a syslog collector.
+type AuditForwardCommand struct {
+	io         ui.IO
+	path       api.Path
+	syslogURL  string
+	interval   time.Duration
+	cursorFile string
+	newClient  newClientFunc
+	dial       func(network, address string) (io.WriteCloser, error)
+	hostname   func() (string, error)
+	sleep      func(time.Duration)
+}
+
+// NewAuditForwardCommand creates a new AuditForwardCommand.
+func NewAuditForwardCommand(io ui.IO, newClient newClientFunc) *AuditForwardCommand {
+	return &AuditForwardCommand{
+		io:        io,
+		newClient: newClient,
+		dial:      dialSyslog,
+		hostname:  os.Hostname,
+		sleep:     time.Sleep,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AuditForwardCommand) Register(r command.Registerer) {
+	clause := r.Command("forward", "Keep running and forward new audit events of a repository or a secret to a syslog collector. "+
+		"Every event is sent as an RFC5424 message with the fields of an exported audit record as structured data.")
+	clause.Arg("repo-path or secret-path", "Path to the repository or the secret to forward the audit log of "+repoPathPlaceHolder+" or "+secretPathPlaceHolder).Required().SetValue(&cmd.path)
+	clause.Flag("syslog", "The address of the syslog collector, as udp://host:port or tcp://host:port. The port defaults to 514.").Required().StringVar(&cmd.syslogURL)
+	clause.Flag("interval", "The interval at which to poll for new events.").Default("60s").DurationVar(&cmd.interval)
+	clause.Flag("cursor-file", "Path to a file in which the position of the newest forwarded event is stored. When set, forwarding resumes after the events that have been forwarded before. Otherwise, only events logged after the command started are forwarded.").StringVar(&cmd.cursorFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run polls for new audit events and forwards them to syslog, until the process is interrupted.
+func (cmd *AuditForwardCommand) Run() error {
+	if cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	network, address, err := parseSyslogURL(cmd.syslogURL)
+	if err != nil {
+		return err
+	}
+
+	hostname, err := cmd.hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	var pos auditPosition
+	if cmd.cursorFile != "" {
+		pos, err = readAuditCursor(cmd.cursorFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pos.at.IsZero() {
+		iter, _, err := auditRecordSource(cmd.path, cmd.newClient)
+		if err != nil {
+			return err
+		}
+
+		pos, err = newestAuditPosition(iter)
+		if err != nil {
+			return err
+		}
+	}
+
+	conn, err := cmd.dial(network, address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	w := syslogWriter{writer: conn, octetCounting: network == "tcp"}
+
+	fmt.Fprintf(cmd.io.Output(), "Forwarding audit events of %s to %s every %s.\n", cmd.path, cmd.syslogURL, cmd.interval)
+
+	for {
+		err = cmd.forward(w, hostname, &pos)
+		if err != nil {
+			return err
+		}
+
+		cmd.sleep(cmd.interval)
+	}
+}
+
+// forward sends the events that have not been forwarded yet and advances the position past them.
+func (cmd *AuditForwardCommand) forward(w syslogWriter, hostname string, pos *auditPosition) error {
+	iter, newRecord, err := auditRecordSource(cmd.path, cmd.newClient)
+	if err != nil {
+		return err
+	}
+
+	events, err := eventsAfter(iter, *pos)
+	if err != nil {
+		return err
+	}
+
+	var sendErr error
+	for _, event := range events {
+		record, err := newRecord(event)
+		if err != nil {
+			sendErr = err
+			break
+		}
+
+		err = w.Write(formatSyslogMessage(record, hostname))
+		if err != nil {
+			sendErr = err
+			break
+		}
+		pos.advance(event)
+	}
+
+	if cmd.cursorFile != "" && len(events) > 0 {
+		err = writeAuditCursor(cmd.cursorFile, *pos)
+		if err != nil {
+			return err
+		}
+	}
+	return sendErr
+}
+
+// dialSyslog connects to the syslog collector at the given address.
+func dialSyslog(network, address string) (io.WriteCloser, error) {
+	return net.Dial(network, address)
+}
+
+// parseSyslogURL returns the network and address of the given syslog URL.
+func parseSyslogURL(raw string) (string, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp") || u.Hostname() == "" {
+		return "", "", ErrInvalidSyslogURL(raw)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = syslogDefaultPort
+	}
+	return u.Scheme, net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// syslogWriter writes syslog messages to a connection. Messages sent over TCP
+// are framed with octet counting as described in RFC6587.
+type syslogWriter struct {
+	writer        io.Writer
+	octetCounting bool
+}
+
+// Write sends a single message.
+func (w syslogWriter) Write(msg string) error {
+	if w.octetCounting {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	_, err := io.WriteString(w.writer, msg)
+	return err
+}
+
+// formatSyslogMessage formats the record as an RFC5424 syslog message.
+func formatSyslogMessage(record auditRecord, hostname string) string {
+	priority := syslogFacilityLogAudit*8 + syslogSeverity(record.Severity)
+
+	params := []struct {
+		name  string
+		value string
+	}{
+		{"eventId", record.EventID},
+		{"action", record.Action},
+		{"category", record.Category},
+		{"severity", fmt.Sprint(record.Severity)},
+		{"actor", record.Actor},
+		{"actorType", record.ActorType},
+		{"subject", record.Subject},
+		{"subjectType", record.SubjectType},
+		{"repo", record.Repo},
+		{"ip", record.IPAddress},
+	}
+	structuredData := "[" + syslogStructuredDataID
+	for _, param := range params {
+		structuredData += fmt.Sprintf(` %s="%s"`, param.name, escapeSyslogParamValue(param.value))
+	}
+	structuredData += "]"
+
+	msg := fmt.Sprintf("%s performed %s on %s", record.Actor, record.Action, record.Subject)
+	if record.IPAddress != "" {
+		msg += " from " + record.IPAddress
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s",
+		priority,
+		record.Time,
+		hostname,
+		syslogAppName,
+		syslogMessageID(record.Action),
+		structuredData,
+		msg,
+	)
+}
+
+// syslogSeverity maps the 0-10 severity of an audit record to a syslog severity.
+func syslogSeverity(severity int) int {
+	switch {
+	case severity >= 7:
+		return 4 // warning
+	case severity >= 5:
+		return 5 // notice
+	default:
+		return 6 // informational
+	}
+}
+
+// syslogMessageID returns the action as MSGID, which can contain at most 32 printable ASCII characters.
+func syslogMessageID(action string) string {
+	if action == "" {
+		return "-"
+	}
+	if len(action) > 32 {
+		return action[:32]
+	}
+	return action
+}
+
+// escapeSyslogParamValue escapes the characters that must be escaped in structured data parameter values.
+func escapeSyslogParamValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,202 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestParseSyslogURL(t *testing.T) {
+	cases := map[string]struct {
+		in              string
+		expectedNetwork string
+		expectedAddress string
+		err             error
+	}{
+		"udp": {
+			in:              "udp://collector:514",
+			expectedNetwork: "udp",
+			expectedAddress: "collector:514",
+		},
+		"tcp default port": {
+			in:              "tcp://10.0.0.1",
+			expectedNetwork: "tcp",
+			expectedAddress: "10.0.0.1:514",
+		},
+		"ipv6": {
+			in:              "udp://[::1]:1514",
+			expectedNetwork: "udp",
+			expectedAddress: "[::1]:1514",
+		},
+		"unsupported scheme": {
+			in:  "http://collector:514",
+			err: ErrInvalidSyslogURL("http://collector:514"),
+		},
+		"no host": {
+			in:  "collector:514",
+			err: ErrInvalidSyslogURL("collector:514"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			network, address, err := parseSyslogURL(tc.in)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, network, tc.expectedNetwork)
+			assert.Equal(t, address, tc.expectedAddress)
+		})
+	}
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	record := auditRecord{
+		EventID:     "e1",
+		Time:        "2020-01-01T12:00:00Z",
+		Action:      "read.secret_version",
+		Category:    auditActionRead,
+		Severity:    3,
+		Actor:       "dev1",
+		ActorType:   "user",
+		Subject:     `dev1/repo/a"b]:1`,
+		SubjectType: "secret_version",
+		Repo:        "dev1/repo",
+		IPAddress:   "127.0.0.1",
+	}
+
+	actual := formatSyslogMessage(record, "host")
+
+	expected := `<110>1 2020-01-01T12:00:00Z host secrethub - read.secret_version ` +
+		`[audit@32473 eventId="e1" action="read.secret_version" category="read" severity="3" actor="dev1" actorType="user" subject="dev1/repo/a\"b\]:1" subjectType="secret_version" repo="dev1/repo" ip="127.0.0.1"] ` +
+		`dev1 performed read.secret_version on dev1/repo/a"b]:1 from 127.0.0.1`
+	assert.Equal(t, actual, expected)
+}
+
+func TestSyslogWriter_Write(t *testing.T) {
+	cases := map[string]struct {
+		octetCounting bool
+		expected      string
+	}{
+		"udp": {
+			expected: "<110>1 msg",
+		},
+		"tcp": {
+			octetCounting: true,
+			expected:      "10 <110>1 msg",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := syslogWriter{writer: buf, octetCounting: tc.octetCounting}
+
+			err := w.Write("<110>1 msg")
+
+			assert.OK(t, err)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestAuditForwardCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	event := func(ip string, loggedAt time.Time) api.Audit {
+		return api.Audit{
+			EventID: uuid.New(),
+			Action:  "create",
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{
+					Username: "developer",
+				},
+			},
+			LoggedAt: loggedAt,
+			Subject: api.AuditSubject{
+				Type: "repo",
+				Repo: &api.Repo{
+					Name: "repo",
+				},
+			},
+			IPAddress: ip,
+		}
+	}
+	existing := event("127.0.0.1", start)
+	first := event("127.0.0.2", start.Add(time.Minute))
+	second := event("127.0.0.3", start.Add(2*time.Minute))
+
+	polls := [][]api.Audit{
+		{existing},
+		{first, existing},
+		{second, first, existing},
+	}
+
+	buf := &bytes.Buffer{}
+	var dialed string
+	var sleeps int
+	fakeIO := fakeui.NewIO(t)
+	cmd := AuditForwardCommand{
+		io:        fakeIO,
+		path:      "namespace/repo",
+		syslogURL: "tcp://collector",
+		interval:  time.Minute,
+		dial: func(network, address string) (io.WriteCloser, error) {
+			dialed = network + " " + address
+			return nopWriteCloser{buf}, nil
+		},
+		hostname: func() (string, error) {
+			return "host", nil
+		},
+		sleep: func(time.Duration) {
+			sleeps++
+		},
+		newClient: func() (secrethub.ClientInterface, error) {
+			if len(polls) == 0 {
+				return nil, testErr
+			}
+			events := polls[0]
+			polls = polls[1:]
+			return fakeclient.Client{
+				DirService: &fakeclient.DirService{
+					GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+						return nil, nil
+					},
+				},
+				RepoService: &fakeclient.RepoService{
+					AuditEventIterator: &fakeclient.AuditEventIterator{
+						Events: events,
+					},
+				},
+			}, nil
+		},
+	}
+
+	err := cmd.Run()
+
+	assert.Equal(t, err, testErr)
+	assert.Equal(t, dialed, "tcp collector:514")
+	assert.Equal(t, sleeps, 2)
+	assert.Equal(t, fakeIO.Out.String(), "Forwarding audit events of namespace/repo to tcp://collector every 1m0s.\n")
+
+	expected := ""
+	for _, e := range []api.Audit{first, second} {
+		record, err := newAuditRecord(e, "namespace/repo", "repo")
+		assert.OK(t, err)
+		msg := formatSyslogMessage(record, "host")
+		expected += fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	assert.Equal(t, buf.String(), expected)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/assert"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
@@ -99,6 +100,59 @@ func TestAuditRepoCommand_run(t *testing.T) {
 				"developer        create.repo      repo             127.0.0.1        2018-01-01T01:0\n" +
 				"                                                                    1:01+01:00     \n",
 		},
+		"stop at first event before since": {
+			cmd: AuditCommand{
+				path: "namespace/repo",
+				newClient: func() (secrethub.ClientInterface, error) {
+					event := func(loggedAt time.Time, ip string) api.Audit {
+						return api.Audit{
+							Action: "create",
+							Actor: api.AuditActor{
+								Type: "user",
+								User: &api.User{
+									Username: "developer",
+								},
+							},
+							LoggedAt: loggedAt,
+							Subject: api.AuditSubject{
+								Type: "repo",
+								Repo: &api.Repo{
+									Name: "repo",
+								},
+							},
+							IPAddress: ip,
+						}
+					}
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return nil, nil
+							},
+						},
+						RepoService: &fakeclient.RepoService{
+							AuditEventIterator: &fakeclient.AuditEventIterator{
+								Events: []api.Audit{
+									event(time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC), "127.0.0.1"),
+									event(time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC), "127.0.0.2"),
+									// Out of order, so it is only printed when the listing does not stop at the previous event.
+									event(time.Date(2018, 1, 3, 0, 0, 0, 0, time.UTC), "127.0.0.3"),
+								},
+							},
+						},
+					}, nil
+				},
+				format:     formatJSON,
+				perPage:    20,
+				maxResults: -1,
+				filter: auditFilter{
+					since: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-02T00:00:00Z",
+				},
+			},
+			out: "{\"Author\":\"developer\",\"Date\":\"2018-01-02T00:00:00Z\",\"Event\":\"create.repo\",\"EventID\":\"00000000-0000-0000-0000-000000000000\",\"EventSubject\":\"repo\",\"IPAddress\":\"127.0.0.1\"}\n",
+		},
 		"client creation error": {
 			cmd: AuditCommand{
 				path: "namespace/repo",
@@ -251,3 +305,108 @@ func TestAuditRepoCommand_run(t *testing.T) {
 		})
 	}
 }
+
+func TestAuditRepoCommand_run_after(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	unknownID := uuid.New().String()
+	events := make([]api.Audit, len(ids))
+	for i, id := range ids {
+		events[i] = api.Audit{
+			EventID: id,
+			Action:  "create",
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{
+					Username: "developer",
+				},
+			},
+			Subject: api.AuditSubject{
+				Type: "repo",
+				Repo: &api.Repo{
+					Name: "repo",
+				},
+			},
+			IPAddress: "127.0.0.1",
+		}
+	}
+	line := func(id uuid.UUID) string {
+		return `{"Author":"developer","Date":"2018-01-01T00:00:00Z","Event":"create.repo","EventID":"` + id.String() + `","EventSubject":"repo","IPAddress":"127.0.0.1"}` + "\n"
+	}
+
+	cases := map[string]struct {
+		after      string
+		format     string
+		maxResults int
+		out        string
+		err        error
+	}{
+		"after first": {
+			after:      ids[0].String(),
+			format:     formatJSON,
+			maxResults: -1,
+			out:        line(ids[1]) + line(ids[2]),
+		},
+		"page": {
+			after:      ids[0].String(),
+			format:     formatJSON,
+			maxResults: 1,
+			out:        line(ids[1]),
+		},
+		"after last": {
+			after:      ids[2].String(),
+			format:     formatJSON,
+			maxResults: -1,
+		},
+		"unknown event": {
+			after:      unknownID,
+			format:     formatJSON,
+			maxResults: -1,
+			err:        ErrCursorNotFound(unknownID),
+		},
+		"table": {
+			after:      ids[0].String(),
+			format:     formatTable,
+			maxResults: -1,
+			err:        ErrPagingWithoutOutput("--after"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buffer := bytes.Buffer{}
+			cmd := AuditCommand{
+				path: "namespace/repo",
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return nil, nil
+							},
+						},
+						RepoService: &fakeclient.RepoService{
+							AuditEventIterator: &fakeclient.AuditEventIterator{
+								Events: events,
+							},
+						},
+					}, nil
+				},
+				newPaginatedWriter: func(_ io.Writer) (io.WriteCloser, error) {
+					return &fakes.Pager{Buffer: &buffer}, nil
+				},
+				io:         fakeui.NewIO(t),
+				format:     tc.format,
+				perPage:    20,
+				maxResults: tc.maxResults,
+				after:      tc.after,
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-01T00:00:00Z",
+				},
+			}
+
+			err := cmd.run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, buffer.String(), tc.out)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package secrethub
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+
+	"github.com/secrethub/secrethub-cli/internals/secrethub/pager"
+)
+
+// Errors
+var (
+	ErrVersionsRequireSecret = errAudit.Code("versions_require_secret").Error("--versions can only be used when auditing a secret")
+)
+
+// runVersions prints the events on the versions of a secret, grouped per version
+// from newest to oldest. Within a version, events are printed in the order in which
+// they happened, so that it shows who created the version and who read it afterwards.
+func (cmd *AuditCommand) runVersions() error {
+	_, err := cmd.path.ToSecretPath()
+	if err != nil {
+		return ErrVersionsRequireSecret
+	}
+
+	iter, _, err := cmd.iterAndAuditTable()
+	if err != nil {
+		return err
+	}
+
+	var events []api.Audit
+	for {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if cmd.filter.isBeforeRange(event) {
+			break
+		}
+		if event.Subject.Type != api.AuditSubjectSecretVersion || event.Subject.SecretVersion == nil {
+			continue
+		}
+		if !cmd.filter.matches(event) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		vi := events[i].Subject.SecretVersion.Version
+		vj := events[j].Subject.SecretVersion.Version
+		if vi != vj {
+			return vi > vj
+		}
+		return events[i].LoggedAt.Before(events[j].LoggedAt)
+	})
+
+	paginatedWriter, err := cmd.newPaginatedWriter(cmd.io.Output())
+	if err != nil {
+		return err
+	}
+	defer paginatedWriter.Close()
+
+	auditTable := newSecretVersionAuditTable(cmd.timeFormatter)
+	formatter, err := cmd.newFormatter(paginatedWriter, auditTable)
+	if err != nil {
+		return err
+	}
+
+	for i, event := range events {
+		if i == cmd.maxResults {
+			break
+		}
+
+		row, err := auditTable.row(event)
+		if err != nil {
+			return err
+		}
+
+		err = formatter.Write(row)
+		if err == pager.ErrPagerClosed {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newSecretVersionAuditTable(timeFormatter TimeFormatter) secretVersionAuditTable {
+	base := newBaseAuditTable(timeFormatter)
+	base.tableColumns = append([]tableColumn{{name: "version", maxWidth: 10}}, base.tableColumns...)
+	return secretVersionAuditTable{
+		baseAuditTable: base,
+	}
+}
+
+// secretVersionAuditTable is an audit table for events on secret versions,
+// starting with the version the event applies to.
+type secretVersionAuditTable struct {
+	baseAuditTable
+}
+
+func (table secretVersionAuditTable) row(event api.Audit) ([]string, error) {
+	row, err := table.baseAuditTable.row(event)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{strconv.Itoa(event.Subject.SecretVersion.Version)}, row...), nil
+}
@@ -0,0 +1,141 @@
+package secrethub
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/fakes"
+)
+
+func TestAuditCommand_runVersions(t *testing.T) {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(action api.AuditAction, username string, version int, offset time.Duration) api.Audit {
+		return api.Audit{
+			Action: action,
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{
+					Username: username,
+				},
+			},
+			LoggedAt: start.Add(offset),
+			Subject: api.AuditSubject{
+				Type:          api.AuditSubjectSecretVersion,
+				SecretVersion: &api.SecretVersion{Version: version},
+			},
+			IPAddress: "127.0.0.1",
+		}
+	}
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			DirService: &fakeclient.DirService{
+				ExistsFunc: func(_ string) (bool, error) {
+					return false, nil
+				},
+			},
+			SecretService: &fakeclient.SecretService{
+				AuditEventIterator: &fakeclient.AuditEventIterator{
+					Events: []api.Audit{
+						event(api.AuditActionRead, "dev2", 2, 4*time.Minute),
+						event(api.AuditActionCreate, "dev1", 2, 3*time.Minute),
+						event(api.AuditActionRead, "dev2", 1, 2*time.Minute),
+						{
+							Action: api.AuditActionCreate,
+							Actor: api.AuditActor{
+								Type: "user",
+								User: &api.User{Username: "dev1"},
+							},
+							LoggedAt: start.Add(time.Minute),
+							Subject: api.AuditSubject{
+								Type: api.AuditSubjectSecret,
+							},
+						},
+						event(api.AuditActionCreate, "dev1", 1, 0),
+					},
+				},
+			},
+		}, nil
+	}
+	row := func(version, author, event string) string {
+		return `{"Author":"` + author + `","Date":"2020-01-01T12:00:00Z","Event":"` + event + `","IPAddress":"127.0.0.1","Version":"` + version + `"}` + "\n"
+	}
+
+	cases := map[string]struct {
+		cmd AuditCommand
+		out string
+		err error
+	}{
+		"grouped per version": {
+			cmd: AuditCommand{
+				path:       "namespace/repo/secret",
+				newClient:  newClient,
+				format:     formatJSON,
+				maxResults: -1,
+			},
+			out: row("2", "dev1", "create.secret_version") +
+				row("2", "dev2", "read.secret_version") +
+				row("1", "dev1", "create.secret_version") +
+				row("1", "dev2", "read.secret_version"),
+		},
+		"max results": {
+			cmd: AuditCommand{
+				path:       "namespace/repo/secret",
+				newClient:  newClient,
+				format:     formatJSON,
+				maxResults: 1,
+			},
+			out: row("2", "dev1", "create.secret_version"),
+		},
+		"filtered": {
+			cmd: AuditCommand{
+				path:       "namespace/repo/secret",
+				newClient:  newClient,
+				format:     formatJSON,
+				maxResults: -1,
+				filter: auditFilter{
+					actors: []string{"dev2"},
+				},
+			},
+			out: row("2", "dev2", "read.secret_version") +
+				row("1", "dev2", "read.secret_version"),
+		},
+		"repo path": {
+			cmd: AuditCommand{
+				path:       "namespace/repo",
+				newClient:  newClient,
+				format:     formatJSON,
+				maxResults: -1,
+			},
+			err: ErrVersionsRequireSecret,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			buffer := bytes.Buffer{}
+			tc.cmd.newPaginatedWriter = func(_ io.Writer) (io.WriteCloser, error) {
+				return &fakes.Pager{Buffer: &buffer}, nil
+			}
+			tc.cmd.io = fakeui.NewIO(t)
+			tc.cmd.timeFormatter = &fakes.TimeFormatter{
+				Response: "2020-01-01T12:00:00Z",
+			}
+
+			// Act
+			err := tc.cmd.runVersions()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, buffer.String(), tc.out)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package secrethub
+
+import (
+	"net/http"
+
+	"github.com/secrethub/secrethub-go/internals/auth"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	sechttp "github.com/secrethub/secrethub-go/pkg/secrethub/internals/http"
+)
+
+// authTransport is a http.RoundTripper that authenticates the requests it passes on,
+// for requests to the API that are not made with a client.
+type authTransport struct {
+	next          http.RoundTripper
+	authenticator auth.Authenticator
+}
+
+// newAuthTransport returns a transport that authenticates the requests with the authenticator and passes them on to next.
+func newAuthTransport(next http.RoundTripper, authenticator auth.Authenticator) *authTransport {
+	return &authTransport{
+		next:          next,
+		authenticator: authenticator,
+	}
+}
+
+// RoundTrip authenticates a copy of the request and performs it.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	err := t.authenticator.Authenticate(req)
+	if err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// authenticatorProvider is a credentials.Provider that keeps the authenticator of the credential it provides.
+type authenticatorProvider struct {
+	credentials.Provider
+	authenticator auth.Authenticator
+}
+
+// Provide provides the credential of the wrapped provider.
+func (p *authenticatorProvider) Provide(httpClient *sechttp.Client) (auth.Authenticator, credentials.Decrypter, error) {
+	authenticator, decrypter, err := p.Provider.Provide(httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.authenticator = authenticator
+	return authenticator, decrypter, nil
+}
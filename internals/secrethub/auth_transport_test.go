@@ -0,0 +1,40 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// fakeAuthenticator sets a fixed Authorization header.
+type fakeAuthenticator struct{}
+
+// Authenticate sets the Authorization header of the request.
+func (fakeAuthenticator) Authenticate(r *http.Request) error {
+	r.Header.Set("Authorization", "test-auth")
+	return nil
+}
+
+func TestAuthTransport_RoundTrip(t *testing.T) {
+	var header string
+	transport := newAuthTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header = req.Header.Get("Authorization")
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}), fakeAuthenticator{})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.secrethub.io/", nil)
+	assert.OK(t, err)
+
+	_, err = transport.RoundTrip(req)
+
+	assert.OK(t, err)
+	assert.Equal(t, header, "test-auth")
+	// The request that is passed in is not changed.
+	assert.Equal(t, req.Header.Get("Authorization"), "")
+}
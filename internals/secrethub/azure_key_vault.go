@@ -0,0 +1,273 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.1"
+	azureKeyVaultResource   = "https://vault.azure.net"
+
+	// azureRecoverAttempts is the number of times a recovered secret is polled before giving up.
+	// Recovering a soft-deleted secret usually takes a few seconds.
+	azureRecoverAttempts = 30
+	azureRecoverInterval = time.Second
+)
+
+// Errors
+var (
+	ErrAzureNoCredentials = errMain.Code("azure_no_credentials").Error("could not find Azure credentials: set AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET or log in with `az login`")
+	ErrAzureRequestFailed = errMain.Code("azure_request_failed").ErrorPref("request to Azure Key Vault failed: %s")
+	ErrAzureRecoverFailed = errMain.Code("azure_recover_failed").ErrorPref("the secret %s was not recovered in time")
+)
+
+// errAzureSecretDeleted is returned when a secret is written that is soft-deleted in the vault.
+var errAzureSecretDeleted = errors.New("the Azure secret is deleted but recoverable")
+
+// azureSecret is a secret in Azure Key Vault.
+type azureSecret struct {
+	Name    string
+	Tags    map[string]string
+	Enabled bool
+	// Managed is set for the secrets that hold the private key of a certificate.
+	Managed     bool
+	ContentType string
+}
+
+// azureKeyVault is the part of the Azure Key Vault API used to import and export secrets.
+type azureKeyVault interface {
+	// ListSecrets returns all secrets in the vault, including the secrets of certificates.
+	ListSecrets() ([]azureSecret, error)
+	// GetSecret returns the value of the latest version of the secret.
+	GetSecret(name string) (string, error)
+	// SetSecret stores the value as a new version of the secret and creates the secret if it does not exist.
+	SetSecret(name string, value string, tags map[string]string) error
+	// RecoverSecret recovers a soft-deleted secret and waits until it can be used.
+	RecoverSecret(name string) error
+}
+
+// newAzureKeyVaultFunc creates a client for the given Azure Key Vault.
+type newAzureKeyVaultFunc func(vault string) (azureKeyVault, error)
+
+// newAzureKeyVault creates a client for the Azure Key Vault with the given name or URL.
+func newAzureKeyVault(vault string) (azureKeyVault, error) {
+	token, err := azureAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return azureKeyVaultClient{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		vaultURL: azureVaultURL(vault),
+		token:    token,
+	}, nil
+}
+
+// azureVaultURL returns the URL of the vault with the given name. URLs are returned as is.
+func azureVaultURL(vault string) string {
+	if strings.Contains(vault, "://") {
+		return strings.TrimSuffix(vault, "/")
+	}
+	return "https://" + vault + ".vault.azure.net"
+}
+
+// azureAccessToken returns an access token for Azure Key Vault. The credentials of a service principal
+// are read from the environment and when these are not set, the token of the Azure CLI is used.
+func azureAccessToken() (string, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
+	if tenantID != "" && clientID != "" && clientSecret != "" {
+		resp, err := http.PostForm("https://login.microsoftonline.com/"+url.PathEscape(tenantID)+"/oauth2/v2.0/token", url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {clientID},
+			"client_secret": {clientSecret},
+			"scope":         {azureKeyVaultResource + "/.default"},
+		})
+		if err != nil {
+			return "", ErrAzureRequestFailed(err)
+		}
+		defer resp.Body.Close()
+
+		var token struct {
+			AccessToken      string `json:"access_token"`
+			ErrorDescription string `json:"error_description"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&token)
+		if err != nil {
+			return "", ErrAzureRequestFailed(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", ErrAzureRequestFailed(token.ErrorDescription)
+		}
+		return token.AccessToken, nil
+	}
+
+	out, err := exec.Command("az", "account", "get-access-token", "--resource", azureKeyVaultResource, "--output", "json").Output()
+	if err != nil {
+		return "", ErrAzureNoCredentials
+	}
+
+	var token struct {
+		AccessToken string `json:"accessToken"`
+	}
+	err = json.Unmarshal(out, &token)
+	if err != nil || token.AccessToken == "" {
+		return "", ErrAzureNoCredentials
+	}
+	return token.AccessToken, nil
+}
+
+// azureKeyVaultClient implements azureKeyVault with the Azure Key Vault REST API.
+type azureKeyVaultClient struct {
+	client   *http.Client
+	vaultURL string
+	token    string
+}
+
+// azureSecretItem is the format in which Azure Key Vault returns secrets.
+type azureSecretItem struct {
+	ID          string            `json:"id"`
+	Value       string            `json:"value,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Managed     bool              `json:"managed,omitempty"`
+	Attributes  *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"attributes,omitempty"`
+}
+
+// azureError is the format in which Azure Key Vault returns errors.
+type azureError struct {
+	Error struct {
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+		InnerError *struct {
+			Code string `json:"code"`
+		} `json:"innererror"`
+	} `json:"error"`
+}
+
+// ListSecrets returns all secrets in the vault, following all pages of the list.
+func (c azureKeyVaultClient) ListSecrets() ([]azureSecret, error) {
+	var res []azureSecret
+	next := c.vaultURL + "/secrets?api-version=" + azureKeyVaultAPIVersion
+	for next != "" {
+		var page struct {
+			Value    []azureSecretItem `json:"value"`
+			NextLink string            `json:"nextLink"`
+		}
+		err := c.do(http.MethodGet, next, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Value {
+			res = append(res, azureSecret{
+				Name:        item.ID[strings.LastIndex(item.ID, "/")+1:],
+				Tags:        item.Tags,
+				Enabled:     item.Attributes == nil || item.Attributes.Enabled,
+				Managed:     item.Managed,
+				ContentType: item.ContentType,
+			})
+		}
+		next = page.NextLink
+	}
+	return res, nil
+}
+
+// GetSecret returns the value of the latest version of the secret.
+func (c azureKeyVaultClient) GetSecret(name string) (string, error) {
+	var item azureSecretItem
+	err := c.do(http.MethodGet, c.secretURL(name), nil, &item)
+	if err != nil {
+		return "", err
+	}
+	return item.Value, nil
+}
+
+// SetSecret stores the value as a new version of the secret.
+func (c azureKeyVaultClient) SetSecret(name string, value string, tags map[string]string) error {
+	return c.do(http.MethodPut, c.secretURL(name), azureSecretItem{Value: value, Tags: tags}, nil)
+}
+
+// RecoverSecret recovers a soft-deleted secret and polls the secret until it is available.
+func (c azureKeyVaultClient) RecoverSecret(name string) error {
+	err := c.do(http.MethodPost, c.vaultURL+"/deletedsecrets/"+url.PathEscape(name)+"/recover?api-version="+azureKeyVaultAPIVersion, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < azureRecoverAttempts; i++ {
+		_, err = c.GetSecret(name)
+		if err == nil {
+			return nil
+		}
+		time.Sleep(azureRecoverInterval)
+	}
+	return ErrAzureRecoverFailed(name)
+}
+
+// secretURL returns the URL of the latest version of the secret with the given name.
+func (c azureKeyVaultClient) secretURL(name string) string {
+	return c.vaultURL + "/secrets/" + url.PathEscape(name) + "?api-version=" + azureKeyVaultAPIVersion
+}
+
+// do sends a request to Azure Key Vault and decodes the response into out, when it is not nil.
+func (c azureKeyVaultClient) do(method string, rawURL string, in interface{}, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		body, err = json.Marshal(in)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ErrAzureRequestFailed(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ErrAzureRequestFailed(err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var errResp azureError
+		err = json.Unmarshal(respBody, &errResp)
+		if err != nil {
+			return ErrAzureRequestFailed(fmt.Sprintf("%s %s", resp.Status, respBody))
+		}
+		if errResp.Error.InnerError != nil && errResp.Error.InnerError.Code == "ObjectIsDeletedButRecoverable" {
+			return errAzureSecretDeleted
+		}
+		return ErrAzureRequestFailed(errResp.Error.Code + ": " + errResp.Error.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,180 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	errBackup              = errio.Namespace("backup")
+	ErrInvalidBackupConfig = errBackup.Code("invalid_config").ErrorPref("invalid backup configuration: %s")
+	ErrBackupFailed        = errBackup.Code("backup_failed").ErrorPref("%d repo(s) could not be backed up")
+	ErrBackupVerifyFailed  = errBackup.Code("verify_failed").ErrorPref("%d backup archive(s) failed verification")
+	ErrNoBackupArchives    = errBackup.Code("no_archives").Error("no backup archives to verify: give the archives as arguments or use --config")
+	ErrBackupArchiveRepo   = errBackup.Code("archive_repo").ErrorPref("the archive is not a backup of %s but of %s")
+)
+
+const (
+	// backupArchiveExtension is the extension of the archives written by the backup command.
+	backupArchiveExtension = ".shub"
+	// backupTimestampFormat is the format of the creation time in the file names of the archives.
+	backupTimestampFormat = "20060102_150405"
+)
+
+// BackupCommand handles scheduled backups of repositories.
+type BackupCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewBackupCommand creates a new BackupCommand.
+func NewBackupCommand(io ui.IO, newClient newClientFunc) *BackupCommand {
+	return &BackupCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *BackupCommand) Register(r command.Registerer) {
+	clause := r.Command("backup", "Back up repositories to client-side encrypted archives on a schedule.")
+	NewBackupRunCommand(cmd.io, cmd.newClient).Register(clause)
+	NewBackupVerifyCommand(cmd.io).Register(clause)
+}
+
+// backupConfigHelp describes the format of the backup configuration file.
+const backupConfigHelp = "The configuration is a YAML file with the repositories to back up, the directory to write the archives to, " +
+	"the file with the passphrase to encrypt the archives with and how many archives to keep, e.g.:\n\n" +
+	"  repos:\n" +
+	"    - company/app\n" +
+	"  destination: /var/backups/secrethub\n" +
+	"  passphrase_file: /etc/secrethub/backup-passphrase\n" +
+	"  retention:\n" +
+	"    keep_last: 7\n" +
+	"    keep_days: 30\n\n" +
+	"Relative paths are relative to the configuration file. The archives of a repository are written to <destination>/<namespace>/<repo>. " +
+	"Archives beyond the keep_last most recent ones and archives older than keep_days days are removed, but the most recent archive is always kept. " +
+	"Archives can be restored with `secrethub repo import`."
+
+// backupConfig is the configuration of the backups.
+type backupConfig struct {
+	Repos          []string        `yaml:"repos"`
+	Destination    string          `yaml:"destination"`
+	PassphraseFile string          `yaml:"passphrase_file"`
+	Retention      backupRetention `yaml:"retention"`
+}
+
+// backupRetention defines which archives are kept. Zero values keep all archives.
+type backupRetention struct {
+	KeepLast int `yaml:"keep_last"`
+	KeepDays int `yaml:"keep_days"`
+}
+
+// backupArchiveFile is an archive written by the backup command.
+type backupArchiveFile struct {
+	path      string
+	createdAt time.Time
+}
+
+// readBackupConfig reads and validates the backup configuration file at the given path.
+func readBackupConfig(path string) (backupConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return backupConfig{}, ErrReadFile(path, err)
+	}
+
+	var config backupConfig
+	err = yaml.UnmarshalStrict(data, &config)
+	if err != nil {
+		return backupConfig{}, ErrInvalidBackupConfig(err)
+	}
+
+	if len(config.Repos) == 0 {
+		return backupConfig{}, ErrInvalidBackupConfig("no repos to back up")
+	}
+	for _, repo := range config.Repos {
+		if api.ValidateRepoPath(repo) != nil {
+			return backupConfig{}, ErrInvalidBackupConfig(fmt.Sprintf("%q is not a valid repository path", repo))
+		}
+	}
+	if config.Destination == "" {
+		return backupConfig{}, ErrInvalidBackupConfig("no destination")
+	}
+	if config.PassphraseFile == "" {
+		return backupConfig{}, ErrInvalidBackupConfig("no passphrase_file")
+	}
+	if config.Retention.KeepLast < 0 || config.Retention.KeepDays < 0 {
+		return backupConfig{}, ErrInvalidBackupConfig("keep_last and keep_days cannot be negative")
+	}
+
+	baseDir := filepath.Dir(path)
+	if !filepath.IsAbs(config.Destination) {
+		config.Destination = filepath.Join(baseDir, config.Destination)
+	}
+	if !filepath.IsAbs(config.PassphraseFile) {
+		config.PassphraseFile = filepath.Join(baseDir, config.PassphraseFile)
+	}
+	return config, nil
+}
+
+// repoDir returns the directory the archives of the repository are written to.
+func (c backupConfig) repoDir(repo api.RepoPath) string {
+	return filepath.Join(c.Destination, repo.GetNamespace(), repo.GetRepo())
+}
+
+// archives returns the archives of the repository, most recent first.
+func (c backupConfig) archives(repo api.RepoPath) ([]backupArchiveFile, error) {
+	dir := c.repoDir(repo)
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var archives []backupArchiveFile
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || filepath.Ext(name) != backupArchiveExtension {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimestampFormat, name[:len(name)-len(backupArchiveExtension)])
+		if err != nil {
+			// Files that are not written by the backup command are left alone.
+			continue
+		}
+		archives = append(archives, backupArchiveFile{path: filepath.Join(dir, name), createdAt: createdAt})
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].createdAt.After(archives[j].createdAt)
+	})
+	return archives, nil
+}
+
+// expired returns the archives that are not kept by the retention policy at the given time.
+// The archives must be sorted most recent first. The most recent archive is always kept.
+func (r backupRetention) expired(archives []backupArchiveFile, now time.Time) []backupArchiveFile {
+	var expired []backupArchiveFile
+	for i, archive := range archives {
+		if i == 0 {
+			continue
+		}
+		if (r.KeepLast > 0 && i >= r.KeepLast) || (r.KeepDays > 0 && now.Sub(archive.createdAt) > time.Duration(r.KeepDays)*24*time.Hour) {
+			expired = append(expired, archive)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,184 @@
+// Package backup provides the client-side encrypted archive format used to back up repositories.
+//
+// An archive starts with a magic header, followed by the random salt used to derive the
+// encryption key from the passphrase, the nonce and the AES-256-GCM encrypted, gzipped JSON
+// representation of the archive.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// FormatVersion is the version of the archive contents written by this package.
+const FormatVersion = 1
+
+const (
+	saltLength = 16
+	keyLength  = 32
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var magic = []byte("SHUBBAK1")
+
+// Errors
+var (
+	ErrInvalidArchive  = errors.New("the file is not a valid SecretHub backup archive")
+	ErrWrongPassphrase = errors.New("cannot decrypt the backup archive: wrong passphrase or corrupted file")
+	ErrNoPassphrase    = errors.New("a passphrase is required to encrypt or decrypt a backup archive")
+)
+
+// Archive contains the directories, secrets and access rules of a repository.
+// All paths are relative to the root directory of the repository.
+type Archive struct {
+	FormatVersion int
+	Repo          string
+	CreatedAt     time.Time
+	Dirs          []string
+	Secrets       []Secret
+	AccessRules   []AccessRule
+}
+
+// Secret is a secret with all of its versions.
+type Secret struct {
+	Path     string
+	Versions []SecretVersion
+}
+
+// SecretVersion is a single version of a secret.
+type SecretVersion struct {
+	Version   int
+	Data      []byte
+	CreatedAt time.Time
+}
+
+// AccessRule is an access rule on a directory.
+type AccessRule struct {
+	Path       string
+	Account    string
+	Permission string
+}
+
+// Write encrypts the archive with a key derived from the passphrase and writes it to w.
+func Write(w io.Writer, archive *Archive, passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return ErrNoPassphrase
+	}
+
+	var plaintext bytes.Buffer
+	gz := gzip.NewWriter(&plaintext)
+	err := json.NewEncoder(gz).Encode(archive)
+	if err != nil {
+		return err
+	}
+	err = gz.Close()
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltLength)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	header := append(append(append([]byte{}, magic...), salt...), nonce...)
+	ciphertext := aead.Seal(nil, nonce, plaintext.Bytes(), header)
+
+	_, err = w.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(ciphertext)
+	return err
+}
+
+// Read reads an archive from r and decrypts it with a key derived from the passphrase.
+func Read(r io.Reader, passphrase []byte) (*Archive, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrNoPassphrase
+	}
+
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < len(magic)+saltLength || !bytes.Equal(raw[:len(magic)], magic) {
+		return nil, ErrInvalidArchive
+	}
+	salt := raw[len(magic) : len(magic)+saltLength]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	headerLength := len(magic) + saltLength + aead.NonceSize()
+	if len(raw) < headerLength {
+		return nil, ErrInvalidArchive
+	}
+	nonce := raw[len(magic)+saltLength : headerLength]
+
+	plaintext, err := aead.Open(nil, nonce, raw[headerLength:], raw[:headerLength])
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	defer gz.Close()
+
+	var archive Archive
+	err = json.NewDecoder(gz).Decode(&archive)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	if archive.FormatVersion > FormatVersion {
+		return nil, errors.New("the backup archive was created by a newer version of the CLI, please upgrade to read it")
+	}
+
+	return &archive, nil
+}
+
+// newAEAD derives an AES-256-GCM cipher from the passphrase and salt.
+func newAEAD(passphrase []byte, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
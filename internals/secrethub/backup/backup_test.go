@@ -0,0 +1,51 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestWriteRead(t *testing.T) {
+	archive := &Archive{
+		FormatVersion: FormatVersion,
+		Repo:          "namespace/repo",
+		CreatedAt:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Dirs:          []string{"dir"},
+		Secrets: []Secret{
+			{
+				Path: "dir/secret",
+				Versions: []SecretVersion{
+					{Version: 1, Data: []byte("foo"), CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{Version: 2, Data: []byte("bar"), CreatedAt: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+		AccessRules: []AccessRule{
+			{Path: "dir", Account: "dev1", Permission: "read"},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := Write(&buf, archive, []byte("correct horse battery staple"))
+	assert.OK(t, err)
+
+	assert.Equal(t, bytes.Contains(buf.Bytes(), []byte("dir/secret")), false)
+
+	actual, err := Read(bytes.NewReader(buf.Bytes()), []byte("correct horse battery staple"))
+	assert.OK(t, err)
+	assert.Equal(t, actual, archive)
+
+	_, err = Read(bytes.NewReader(buf.Bytes()), []byte("wrong"))
+	assert.Equal(t, err, ErrWrongPassphrase)
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte("PK\x03\x04 not a backup")), []byte("passphrase"))
+	assert.Equal(t, err, ErrInvalidArchive)
+
+	_, err = Read(bytes.NewReader(nil), nil)
+	assert.Equal(t, err, ErrNoPassphrase)
+}
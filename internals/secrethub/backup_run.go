@@ -0,0 +1,166 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/backup"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// BackupRunCommand backs up the repositories in a backup configuration.
+type BackupRunCommand struct {
+	io         ui.IO
+	configFile string
+	now        func() time.Time
+	newClient  newClientFunc
+}
+
+// NewBackupRunCommand creates a new BackupRunCommand.
+func NewBackupRunCommand(io ui.IO, newClient newClientFunc) *BackupRunCommand {
+	return &BackupRunCommand{
+		io:        io,
+		now:       time.Now,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BackupRunCommand) Register(r command.Registerer) {
+	clause := r.Command("run", "Back up the repositories in a backup configuration to encrypted archives that contain all secret versions, directories and access rules, "+
+		"and remove the archives that are no longer kept. Schedule it with cron or a systemd timer to make regular backups. "+
+		"A failing repository does not stop the other repositories from being backed up.")
+	clause.HelpLong(backupConfigHelp)
+	clause.Flag("config", "The backup configuration file.").Required().PlaceHolder("backup.yaml").ExistingFileVar(&cmd.configFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run backs up the repositories.
+func (cmd *BackupRunCommand) Run() error {
+	config, err := readBackupConfig(cmd.configFile)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := readBackupPassphrase(cmd.io, config.PassphraseFile, false)
+	if err != nil {
+		return ErrCannotReadFile(config.PassphraseFile, err)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	failed, removed := 0, 0
+	for _, repo := range config.Repos {
+		repoPath := api.RepoPath(repo)
+
+		err := cmd.backupRepo(client, config, repoPath, passphrase)
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "Failed to back up %s: %s\n", repo, err)
+			failed++
+			// Archives are only removed after a successful backup, so a failing backup never reduces the number of archives.
+			continue
+		}
+
+		n, err := cmd.prune(config, repoPath)
+		removed += n
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "Failed to remove old archives of %s: %s\n", repo, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return ErrBackupFailed(failed)
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Backup complete! %s backed up, %s removed.\n",
+		pluralize("repo", "repos", len(config.Repos)),
+		pluralize("archive", "archives", removed),
+	)
+	return nil
+}
+
+// backupRepo writes an archive of the repository to its directory in the destination.
+func (cmd *BackupRunCommand) backupRepo(client secrethub.ClientInterface, config backupConfig, repo api.RepoPath, passphrase []byte) error {
+	archive, err := newRepoBackup(client, repo)
+	if err != nil {
+		return err
+	}
+	now := cmd.now().UTC()
+	archive.CreatedAt = now
+
+	var buf bytes.Buffer
+	err = backup.Write(&buf, archive, passphrase)
+	if err != nil {
+		return err
+	}
+
+	dir := config.repoDir(repo)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, now.Format(backupTimestampFormat)+backupArchiveExtension)
+	_, err = os.Stat(path)
+	if err == nil {
+		return ErrExportAlreadyExists
+	}
+
+	// The archive is renamed once it is completely written, so an interrupted backup never leaves a partial archive behind.
+	tmp, err := ioutil.TempFile(dir, ".backup-")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(buf.Bytes())
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return ErrCannotWrite(path, err)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Backed up %s to %s: %s and %s.\n",
+		repo,
+		path,
+		pluralize("directory", "directories", len(archive.Dirs)),
+		pluralize("secret", "secrets", len(archive.Secrets)),
+	)
+	return nil
+}
+
+// prune removes the archives of the repository that are not kept by the retention policy and returns how many were removed.
+func (cmd *BackupRunCommand) prune(config backupConfig, repo api.RepoPath) (int, error) {
+	archives, err := config.archives(repo)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, archive := range config.Retention.expired(archives, cmd.now()) {
+		err := os.Remove(archive.path)
+		if err != nil {
+			return removed, err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Removed %s\n", archive.path)
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,175 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+func TestReadBackupConfig(t *testing.T) {
+	cases := map[string]struct {
+		config   string
+		expected backupConfig
+		err      error
+	}{
+		"relative paths": {
+			config: "repos: [company/app]\ndestination: backups\npassphrase_file: passphrase\nretention:\n  keep_last: 3\n",
+			expected: backupConfig{
+				Repos:          []string{"company/app"},
+				Destination:    "{dir}/backups",
+				PassphraseFile: "{dir}/passphrase",
+				Retention:      backupRetention{KeepLast: 3},
+			},
+		},
+		"absolute paths": {
+			config: "repos: [company/app]\ndestination: /var/backups\npassphrase_file: /etc/passphrase\n",
+			expected: backupConfig{
+				Repos:          []string{"company/app"},
+				Destination:    "/var/backups",
+				PassphraseFile: "/etc/passphrase",
+			},
+		},
+		"no repos": {
+			config: "destination: backups\npassphrase_file: passphrase\n",
+			err:    ErrInvalidBackupConfig("no repos to back up"),
+		},
+		"invalid repo": {
+			config: "repos: [company]\ndestination: backups\npassphrase_file: passphrase\n",
+			err:    ErrInvalidBackupConfig(`"company" is not a valid repository path`),
+		},
+		"no passphrase file": {
+			config: "repos: [company/app]\ndestination: backups\n",
+			err:    ErrInvalidBackupConfig("no passphrase_file"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			path := filepath.Join(dir, "backup.yaml")
+			assert.OK(t, ioutil.WriteFile(path, []byte(tc.config), 0600))
+
+			actual, err := readBackupConfig(path)
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				expected := tc.expected
+				expected.Destination = strings.Replace(expected.Destination, "{dir}", dir, 1)
+				expected.PassphraseFile = strings.Replace(expected.PassphraseFile, "{dir}", dir, 1)
+				assert.Equal(t, actual, expected)
+			}
+		})
+	}
+}
+
+func TestBackupRetention_expired(t *testing.T) {
+	now := time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC)
+	archives := []backupArchiveFile{
+		{path: "a", createdAt: now.Add(-1 * 24 * time.Hour)},
+		{path: "b", createdAt: now.Add(-2 * 24 * time.Hour)},
+		{path: "c", createdAt: now.Add(-10 * 24 * time.Hour)},
+		{path: "d", createdAt: now.Add(-40 * 24 * time.Hour)},
+	}
+
+	cases := map[string]struct {
+		retention backupRetention
+		archives  []backupArchiveFile
+		expected  []backupArchiveFile
+	}{
+		"keep all": {
+			archives: archives,
+		},
+		"keep last": {
+			retention: backupRetention{KeepLast: 2},
+			archives:  archives,
+			expected:  archives[2:],
+		},
+		"keep days": {
+			retention: backupRetention{KeepDays: 7},
+			archives:  archives,
+			expected:  archives[2:],
+		},
+		"keep last and days": {
+			retention: backupRetention{KeepLast: 3, KeepDays: 30},
+			archives:  archives,
+			expected:  archives[3:],
+		},
+		"most recent is always kept": {
+			retention: backupRetention{KeepDays: 1},
+			archives:  archives[3:],
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.retention.expired(tc.archives, now), tc.expected)
+		})
+	}
+}
+
+func TestBackupRunAndVerify(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	configFile := filepath.Join(dir, "backup.yaml")
+	assert.OK(t, ioutil.WriteFile(configFile, []byte("repos: [company/repo]\ndestination: backups\npassphrase_file: passphrase\nretention:\n  keep_last: 2\n"), 0600))
+	assert.OK(t, ioutil.WriteFile(filepath.Join(dir, "passphrase"), []byte("correct horse battery staple\n"), 0600))
+
+	repoDir := filepath.Join(dir, "backups", "company", "repo")
+	assert.OK(t, os.MkdirAll(repoDir, 0700))
+	for _, name := range []string{"20200601_000000.shub", "20200602_000000.shub", "notes.txt"} {
+		assert.OK(t, ioutil.WriteFile(filepath.Join(repoDir, name), []byte("not an archive"), 0600))
+	}
+
+	runIO := fakeui.NewIO(t)
+	run := BackupRunCommand{
+		io:         runIO,
+		configFile: configFile,
+		now: func() time.Time {
+			return time.Date(2020, 6, 3, 12, 30, 0, 0, time.UTC)
+		},
+		newClient: func() (secrethub.ClientInterface, error) {
+			return newRepoExportTestClient(), nil
+		},
+	}
+
+	err := run.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, runIO.Out.String(), fmt.Sprintf(
+		"Backed up company/repo to %[1]s/20200603_123000.shub: 1 directory and 2 secrets.\n"+
+			"Removed %[1]s/20200601_000000.shub\n"+
+			"Backup complete! 1 repo backed up, 1 archive removed.\n", repoDir))
+
+	files, err := ioutil.ReadDir(repoDir)
+	assert.OK(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	assert.Equal(t, names, []string{"20200602_000000.shub", "20200603_123000.shub", "notes.txt"})
+
+	verifyIO := fakeui.NewIO(t)
+	verify := BackupVerifyCommand{
+		io:         verifyIO,
+		configFile: configFile,
+	}
+
+	err = verify.Run()
+
+	assert.Equal(t, err, ErrBackupVerifyFailed(1))
+	assert.Equal(t, verifyIO.Out.String(), fmt.Sprintf(
+		"OK %[1]s/20200603_123000.shub: 2 secrets of company/repo, created at %[2]s\n"+
+			"FAILED %[1]s/20200602_000000.shub: the file is not a valid SecretHub backup archive\n", repoDir, "2020-06-03 12:30:00 UTC"))
+}
@@ -0,0 +1,130 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/backup"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// BackupVerifyCommand checks the integrity of backup archives.
+type BackupVerifyCommand struct {
+	io             ui.IO
+	configFile     string
+	passphraseFile string
+	archives       []string
+}
+
+// NewBackupVerifyCommand creates a new BackupVerifyCommand.
+func NewBackupVerifyCommand(io ui.IO) *BackupVerifyCommand {
+	return &BackupVerifyCommand{
+		io: io,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BackupVerifyCommand) Register(r command.Registerer) {
+	clause := r.Command("verify", "Check that backup archives are intact and can be decrypted with the passphrase, without restoring them. "+
+		"Verifies the given archives or all archives of the repositories in a backup configuration.")
+	clause.HelpLong(backupConfigHelp)
+	clause.Arg("archive", "The archives to verify").StringsVar(&cmd.archives)
+	clause.Flag("config", "Verify all archives of the repositories in this backup configuration file, with the passphrase in its passphrase_file.").PlaceHolder("backup.yaml").ExistingFileVar(&cmd.configFile)
+	clause.Flag("passphrase-file", "Read the passphrase of the archives from this file. When not set and no configuration is given, the passphrase is asked interactively.").ExistingFileVar(&cmd.passphraseFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// backupVerification is an archive to verify.
+type backupVerification struct {
+	path string
+	// repo is the repository the archive must be a backup of. It is empty when the archive can be a backup of any repository.
+	repo string
+}
+
+// Run verifies the archives.
+func (cmd *BackupVerifyCommand) Run() error {
+	var verifications []backupVerification
+	passphraseFile := cmd.passphraseFile
+	if cmd.configFile != "" {
+		config, err := readBackupConfig(cmd.configFile)
+		if err != nil {
+			return err
+		}
+		if passphraseFile == "" {
+			passphraseFile = config.PassphraseFile
+		}
+
+		for _, repo := range config.Repos {
+			archives, err := config.archives(api.RepoPath(repo))
+			if err != nil {
+				return err
+			}
+			for _, archive := range archives {
+				verifications = append(verifications, backupVerification{path: archive.path, repo: repo})
+			}
+		}
+	}
+	for _, archive := range cmd.archives {
+		verifications = append(verifications, backupVerification{path: archive})
+	}
+
+	if len(verifications) == 0 {
+		return ErrNoBackupArchives
+	}
+
+	passphrase, err := readBackupPassphrase(cmd.io, passphraseFile, false)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, verification := range verifications {
+		archive, err := verification.verify(passphrase)
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "FAILED %s: %s\n", verification.path, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(cmd.io.Output(), "OK %s: %s of %s, created at %s\n",
+			verification.path,
+			pluralize("secret", "secrets", len(archive.Secrets)),
+			archive.Repo,
+			archive.CreatedAt.Format("2006-01-02 15:04:05 MST"),
+		)
+	}
+
+	if failed > 0 {
+		return ErrBackupVerifyFailed(failed)
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Verification complete! %s verified.\n", pluralize("archive", "archives", len(verifications)))
+	return nil
+}
+
+// verify decrypts the archive and checks its contents.
+func (v backupVerification) verify(passphrase []byte) (*backup.Archive, error) {
+	file, err := os.Open(v.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	archive, err := backup.Read(file, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.repo != "" && archive.Repo != v.repo {
+		return nil, ErrBackupArchiveRepo(v.repo, archive.Repo)
+	}
+	for _, secret := range archive.Secrets {
+		if len(secret.Versions) == 0 {
+			return nil, backup.ErrInvalidArchive
+		}
+	}
+	return archive, nil
+}
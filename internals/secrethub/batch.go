@@ -0,0 +1,144 @@
+package secrethub
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrCannotReadBatch   = errMain.Code("cannot_read_batch").ErrorPref("cannot read the commands of the batch: %s")
+	ErrInvalidBatchLine  = errMain.Code("invalid_batch_line").ErrorPref("invalid command on line %d: %s")
+	ErrBatchCommandsFail = errMain.Code("batch_commands_failed").ErrorPref("%d of the %d commands of the batch failed")
+)
+
+// maxBatchLineLength is the maximum length of a line with a command in a batch.
+const maxBatchLineLength = 1024 * 1024
+
+// BatchCommand runs the commands read from a file or stdin one after the other in a single process.
+// Scripts that run many commands do not set up a connection to the API and load the account key
+// for every command, because the commands of a batch share the client.
+type BatchCommand struct {
+	io          ui.IO
+	runLine     func(args []string) error
+	handleError func(w io.Writer, err error) int
+	file        string
+	keepGoing   bool
+}
+
+// NewBatchCommand creates a new BatchCommand that runs every command with runLine and writes
+// the errors of failed commands with handleError.
+func NewBatchCommand(io ui.IO, runLine func(args []string) error, handleError func(w io.Writer, err error) int) *BatchCommand {
+	return &BatchCommand{
+		io:          io,
+		runLine:     runLine,
+		handleError: handleError,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BatchCommand) Register(r command.Registerer) {
+	clause := r.Command("batch", "Run the commands in a file or on stdin, one command per line, in a single process. "+
+		"This is much faster than running the commands one by one, as the connection to the API and the "+
+		"account key are set up once for all commands. Every line holds the arguments of a command like "+
+		"they are passed on the command-line, e.g. `read my-org/my-repo/secret`, optionally preceded by secrethub. "+
+		"Arguments are quoted like in a shell, but variables are not expanded. Empty lines and lines starting with # are skipped. "+
+		"The client flags, like --identity-provider and --timeout, are only used when set on the batch command. "+
+		"By default, the batch stops at the first command that fails and exits with the exit code of that command.")
+	clause.Arg("file", "The path to the file with the commands. Defaults to reading the commands from stdin, "+
+		"in which case the commands in the batch cannot read from stdin.").ExistingFileVar(&cmd.file)
+	clause.Flag("keep-going", "Keep running the next commands when a command fails. The errors of the failed commands are "+
+		"printed and the batch fails when any command failed.").BoolVar(&cmd.keepGoing)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run runs the commands of the batch.
+func (cmd *BatchCommand) Run() error {
+	input := cmd.io.Input()
+	if cmd.file != "" {
+		file, err := os.Open(cmd.file)
+		if err != nil {
+			return ErrCannotReadBatch(err)
+		}
+		defer file.Close()
+		input = file
+	}
+	return cmd.runLines(input)
+}
+
+// runLines runs the commands on the lines read from the input.
+func (cmd *BatchCommand) runLines(input io.Reader) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(nil, maxBatchLineLength)
+
+	lineNumber := 0
+	commands := 0
+	failed := 0
+	for scanner.Scan() {
+		lineNumber++
+		args, err := parseBatchLine(scanner.Text())
+		if err != nil {
+			return ErrInvalidBatchLine(lineNumber, err)
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		commands++
+		err = cmd.runLine(args)
+		if err != nil {
+			if !cmd.keepGoing {
+				fmt.Fprintf(statusWriter(os.Stderr), "The batch stopped at the command on line %d.\n", lineNumber)
+				return err
+			}
+			fmt.Fprintf(statusWriter(os.Stderr), "The command on line %d failed.\n", lineNumber)
+			cmd.handleError(os.Stderr, err)
+			failed++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ErrCannotReadBatch(err)
+	}
+
+	if failed > 0 {
+		return ErrBatchCommandsFail(failed, commands)
+	}
+	return nil
+}
+
+// batchClientFactory is the client factory of the commands in a batch, which creates their clients
+// with the client factory of the batch command, so all commands share the same client.
+type batchClientFactory struct {
+	ClientFactory
+}
+
+// Register registers the client flags on a factory that is not used, so the flags can be
+// passed to the commands in a batch, but the flags of the batch command are used.
+func (f batchClientFactory) Register(r FlagRegisterer) {
+	NewClientFactory(nil, nil).Register(r)
+}
+
+// parseBatchLine returns the arguments of the command on a line of a batch. The name of the
+// application before the arguments is optional. Empty lines and comments have no arguments.
+func parseBatchLine(line string) ([]string, error) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return nil, nil
+	}
+
+	args, err := splitCommandLine(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && args[0] == ApplicationName {
+		args = args[1:]
+	}
+	return args, nil
+}
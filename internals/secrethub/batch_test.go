@@ -0,0 +1,170 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestParseBatchLine(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected []string
+		err      error
+	}{
+		"command": {
+			line:     "read my-org/my-repo/secret",
+			expected: []string{"read", "my-org/my-repo/secret"},
+		},
+		"application name": {
+			line:     "secrethub read my-org/my-repo/secret",
+			expected: []string{"read", "my-org/my-repo/secret"},
+		},
+		"quoted argument": {
+			line:     `  write --in-file "my file.txt" my-org/my-repo/secret`,
+			expected: []string{"write", "--in-file", "my file.txt", "my-org/my-repo/secret"},
+		},
+		"empty line": {
+			line: "   ",
+		},
+		"comment": {
+			line: "# read my-org/my-repo/secret",
+		},
+		"unterminated quote": {
+			line: `read "my-org/my-repo/secret`,
+			err:  ErrUnterminatedQuote,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			args, err := parseBatchLine(tc.line)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, args, tc.expected)
+		})
+	}
+}
+
+func TestBatchCommand_Run(t *testing.T) {
+	errTest := errors.New("test")
+
+	cases := map[string]struct {
+		in        string
+		keepGoing bool
+		fail      map[string]bool
+		run       [][]string
+		failed    int
+		err       error
+	}{
+		"success": {
+			in:  "read a/b/c\n\n# comment\nsecrethub ls a/b\n",
+			run: [][]string{{"read", "a/b/c"}, {"ls", "a/b"}},
+		},
+		"stop at failure": {
+			in:   "read a/b/c\nread a/b/d\nread a/b/e\n",
+			fail: map[string]bool{"a/b/d": true},
+			run:  [][]string{{"read", "a/b/c"}, {"read", "a/b/d"}},
+			err:  errTest,
+		},
+		"keep going": {
+			in:        "read a/b/c\nread a/b/d\nread a/b/e\n",
+			keepGoing: true,
+			fail:      map[string]bool{"a/b/d": true},
+			run:       [][]string{{"read", "a/b/c"}, {"read", "a/b/d"}, {"read", "a/b/e"}},
+			failed:    1,
+			err:       ErrBatchCommandsFail(1, 3),
+		},
+		"invalid line": {
+			in:  "read a/b/c\nread 'a/b/d\nread a/b/e\n",
+			run: [][]string{{"read", "a/b/c"}},
+			err: ErrInvalidBatchLine(2, ErrUnterminatedQuote),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			testIO := fakeui.NewIO(t)
+			testIO.In.Buffer = bytes.NewBufferString(tc.in)
+
+			var run [][]string
+			failed := 0
+			cmd := NewBatchCommand(testIO, func(args []string) error {
+				run = append(run, args)
+				if tc.fail[args[len(args)-1]] {
+					return errTest
+				}
+				return nil
+			}, func(w io.Writer, err error) int {
+				failed++
+				return 1
+			})
+			cmd.keepGoing = tc.keepGoing
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, run, tc.run)
+			assert.Equal(t, failed, tc.failed)
+		})
+	}
+}
+
+// BenchmarkBatch compares running commands that each set up their own connection to the API,
+// like separate invocations of the CLI do, to running them in a batch that shares the connection.
+func BenchmarkBatch(b *testing.B) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	const commands = 100
+	lines := strings.Repeat("read my-org/my-repo/secret\n", commands)
+
+	get := func(client *http.Client) error {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+
+	benchmarks := map[string]func() func(args []string) error{
+		"separate invocations": func() func(args []string) error {
+			return func(args []string) error {
+				transport := server.Client().Transport.(*http.Transport).Clone()
+				defer transport.CloseIdleConnections()
+				return get(&http.Client{Transport: transport})
+			}
+		},
+		"batch": func() func(args []string) error {
+			client := &http.Client{Transport: server.Client().Transport.(*http.Transport).Clone()}
+			return func(args []string) error {
+				return get(client)
+			}
+		},
+	}
+
+	for name, newRunLine := range benchmarks {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cmd := NewBatchCommand(nil, newRunLine(), nil)
+				err := cmd.runLines(strings.NewReader(lines))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package secrethub
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrInvalidBenchmarkCount = errMain.Code("invalid_benchmark_count").ErrorPref("%s should be at least %d, got %d")
+)
+
+// benchmarkSecretSize is the size in bytes of the secrets that are written by the benchmark.
+const benchmarkSecretSize = 64
+
+// benchmarkPercentiles are the percentiles of the latencies that are printed for every operation.
+var benchmarkPercentiles = []float64{50, 90, 99}
+
+// BenchmarkCommand measures the latency and throughput of reading, writing and listing secrets.
+type BenchmarkCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+	path      api.DirPath
+	writes    int
+	reads     int
+	lists     int
+	now       func() time.Time
+}
+
+// NewBenchmarkCommand creates a new BenchmarkCommand.
+func NewBenchmarkCommand(io ui.IO, newClient newClientFunc) *BenchmarkCommand {
+	return &BenchmarkCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BenchmarkCommand) Register(r command.Registerer) {
+	clause := r.Command("benchmark", "Measure the latency and throughput of writing, reading and listing secrets against the configured API "+
+		"and print the percentiles of the latencies. The secrets are written to a new temporary directory in the given path, "+
+		"which is removed afterwards. The calls are made one after the other. Run it with --no-cache to measure the listings of "+
+		"the API instead of the local cache of directory trees.")
+	clause.Flag("path", "The repository or directory to create the temporary directory in ("+optionalDirPathPlaceHolder+").").Required().SetValue(&cmd.path)
+	clause.Flag("writes", "The number of secrets to write.").Default("10").IntVar(&cmd.writes)
+	clause.Flag("reads", "The number of secrets to read. The written secrets are read in turns.").Default("50").IntVar(&cmd.reads)
+	clause.Flag("lists", "The number of times to list the temporary directory.").Default("10").IntVar(&cmd.lists)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run writes, reads and lists the secrets in a temporary directory and prints the measured latencies.
+func (cmd *BenchmarkCommand) Run() error {
+	if cmd.writes < 1 {
+		return ErrInvalidBenchmarkCount("--writes", 1, cmd.writes)
+	}
+	if cmd.reads < 0 {
+		return ErrInvalidBenchmarkCount("--reads", 0, cmd.reads)
+	}
+	if cmd.lists < 0 {
+		return ErrInvalidBenchmarkCount("--lists", 0, cmd.lists)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return err
+	}
+	dir := cmd.path.JoinDir("secrethub-benchmark-" + hex.EncodeToString(suffix))
+
+	// Creating the directory also loads the account key and sets up the connection,
+	// so these are not included in the measured latencies.
+	_, err = client.Dirs().Create(dir.Value())
+	if err != nil {
+		return err
+	}
+
+	results, err := cmd.run(client, dir)
+	deleteErr := client.Dirs().Delete(dir.Value())
+	if err != nil {
+		return err
+	}
+	if deleteErr != nil {
+		return fmt.Errorf("cannot remove the temporary directory %s: %s", dir, deleteErr)
+	}
+
+	return cmd.print(results)
+}
+
+// benchmarkResult holds the latencies of the calls of an operation.
+type benchmarkResult struct {
+	operation string
+	latencies []time.Duration
+}
+
+// run performs the operations in the directory and returns their latencies.
+func (cmd *BenchmarkCommand) run(client secrethub.ClientInterface, dir api.DirPath) ([]benchmarkResult, error) {
+	counter := newProgressCounter("Benchmarking", cmd.writes+cmd.reads+cmd.lists)
+	defer counter.Finish()
+
+	data := make([]byte, benchmarkSecretSize)
+	_, err := rand.Read(data)
+	if err != nil {
+		return nil, err
+	}
+	value := []byte(hex.EncodeToString(data))
+
+	paths := make([]string, cmd.writes)
+	for i := range paths {
+		paths[i] = dir.JoinSecret(fmt.Sprintf("secret-%d", i)).Value()
+	}
+
+	results := []benchmarkResult{
+		{operation: "write", latencies: make([]time.Duration, cmd.writes)},
+		{operation: "read", latencies: make([]time.Duration, cmd.reads)},
+		{operation: "ls", latencies: make([]time.Duration, cmd.lists)},
+	}
+	for i := range results[0].latencies {
+		results[0].latencies[i], err = cmd.measure(func() error {
+			_, err := client.Secrets().Write(paths[i], value)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		counter.Done()
+	}
+	for i := range results[1].latencies {
+		results[1].latencies[i], err = cmd.measure(func() error {
+			_, err := client.Secrets().Versions().GetWithData(paths[i%len(paths)])
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		counter.Done()
+	}
+	for i := range results[2].latencies {
+		results[2].latencies[i], err = cmd.measure(func() error {
+			_, err := client.Dirs().GetTree(dir.Value(), 1, false)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		counter.Done()
+	}
+	return results, nil
+}
+
+// measure returns the time it takes to call fn.
+func (cmd *BenchmarkCommand) measure(fn func() error) (time.Duration, error) {
+	start := cmd.now()
+	err := fn()
+	return cmd.now().Sub(start), err
+}
+
+// print prints the number of calls, the throughput and the percentiles of the latencies of every operation.
+func (cmd *BenchmarkCommand) print(results []benchmarkResult) error {
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+
+	fmt.Fprint(w, "OPERATION\tCALLS\tCALLS/S")
+	for _, p := range benchmarkPercentiles {
+		fmt.Fprintf(w, "\tP%g", p)
+	}
+	fmt.Fprint(w, "\tMAX\n")
+
+	for _, result := range results {
+		if len(result.latencies) == 0 {
+			continue
+		}
+
+		latencies := make([]time.Duration, len(result.latencies))
+		copy(latencies, result.latencies)
+		sort.Slice(latencies, func(a, b int) bool {
+			return latencies[a] < latencies[b]
+		})
+
+		var total time.Duration
+		for _, latency := range latencies {
+			total += latency
+		}
+		throughput := 0.0
+		if total > 0 {
+			throughput = float64(len(latencies)) / total.Seconds()
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%.1f", result.operation, len(latencies), throughput)
+		for _, p := range benchmarkPercentiles {
+			fmt.Fprintf(w, "\t%s", formatLatency(percentile(latencies, p)))
+		}
+		fmt.Fprintf(w, "\t%s\n", formatLatency(latencies[len(latencies)-1]))
+	}
+
+	return w.Flush()
+}
+
+// percentile returns the p-th percentile of the sorted latencies, using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// formatLatency formats the latency in milliseconds with a precision of a tenth of a millisecond.
+func formatLatency(latency time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(latency)/float64(time.Millisecond))
+}
@@ -0,0 +1,150 @@
+package secrethub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestBenchmarkCommand_Run(t *testing.T) {
+	errTest := errors.New("test")
+
+	cases := map[string]struct {
+		writes   int
+		reads    int
+		lists    int
+		writeErr error
+		calls    []string
+		out      string
+		err      error
+	}{
+		"success": {
+			writes: 2,
+			reads:  3,
+			lists:  1,
+			calls: []string{
+				"create", "write secret-0", "write secret-1",
+				"read secret-0", "read secret-1", "read secret-0",
+				"ls", "delete",
+			},
+			out: "OPERATION  CALLS  CALLS/S  P50    P90    P99    MAX\n" +
+				"write      2      1000.0   1.0ms  1.0ms  1.0ms  1.0ms\n" +
+				"read       3      1000.0   1.0ms  1.0ms  1.0ms  1.0ms\n" +
+				"ls         1      1000.0   1.0ms  1.0ms  1.0ms  1.0ms\n",
+		},
+		"no reads and lists": {
+			writes: 1,
+			calls:  []string{"create", "write secret-0", "delete"},
+			out: "OPERATION  CALLS  CALLS/S  P50    P90    P99    MAX\n" +
+				"write      1      1000.0   1.0ms  1.0ms  1.0ms  1.0ms\n",
+		},
+		"write fails": {
+			writes:   2,
+			reads:    3,
+			writeErr: errTest,
+			calls:    []string{"create", "write secret-0", "delete"},
+			err:      errTest,
+		},
+		"no writes": {
+			writes: 0,
+			err:    ErrInvalidBenchmarkCount("--writes", 1, 0),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			secretName := func(path string) string {
+				return path[strings.LastIndex(path, "/")+1:]
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := NewBenchmarkCommand(io, func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					DirService: &fakeclient.DirService{
+						CreateFunc: func(path string) (*api.Dir, error) {
+							assert.Equal(t, strings.HasPrefix(path, "namespace/repo/secrethub-benchmark-"), true)
+							calls = append(calls, "create")
+							return &api.Dir{}, nil
+						},
+						GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+							calls = append(calls, "ls")
+							return &api.Tree{}, nil
+						},
+						DeleteFunc: func(path string) error {
+							calls = append(calls, "delete")
+							return nil
+						},
+					},
+					SecretService: &fakeclient.SecretService{
+						WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+							calls = append(calls, "write "+secretName(path))
+							return &api.SecretVersion{}, tc.writeErr
+						},
+						VersionService: &fakeclient.SecretVersionService{
+							GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+								calls = append(calls, "read "+secretName(path))
+								return &api.SecretVersion{}, nil
+							},
+						},
+					},
+				}, nil
+			})
+			_ = cmd.path.Set("namespace/repo")
+			cmd.writes = tc.writes
+			cmd.reads = tc.reads
+			cmd.lists = tc.lists
+			now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+			cmd.now = func() time.Time {
+				now = now.Add(time.Millisecond)
+				return now
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, calls, tc.calls)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	cases := map[string]struct {
+		p        float64
+		expected time.Duration
+	}{
+		"minimum": {
+			p:        0,
+			expected: 1,
+		},
+		"median": {
+			p:        50,
+			expected: 5,
+		},
+		"p90": {
+			p:        90,
+			expected: 9,
+		},
+		"p99": {
+			p:        99,
+			expected: 10,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, percentile(latencies, tc.p), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package secrethub
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors
+var (
+	ErrBreachedSecret    = errMain.Code("breached_secret").ErrorPref("the secret has appeared %s in data breaches and must not be used")
+	ErrBreachCheckFailed = errMain.Code("breach_check_failed").ErrorPref("could not check whether the secret has been breached: %s")
+)
+
+// pwnedPasswordsURL is the URL of the range API of Have I Been Pwned,
+// which returns the hashes of breached passwords that start with a prefix.
+const pwnedPasswordsURL = "https://api.pwnedpasswords.com/range/"
+
+// breachChecker returns how many times a value has appeared in data breaches.
+type breachChecker func(value []byte) (int, error)
+
+// newBreachChecker returns a breachChecker that looks up values with the range API at the given URL.
+// Only the first 5 characters of the SHA-1 hash of a value are sent, so the value itself and even
+// its full hash never leave this machine. This is known as k-anonymity.
+func newBreachChecker(client *http.Client, url string) breachChecker {
+	return func(value []byte) (int, error) {
+		sum := sha1.Sum(value)
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		prefix, suffix := hash[:5], hash[5:]
+
+		req, err := http.NewRequest("GET", url+prefix, nil)
+		if err != nil {
+			return 0, ErrBreachCheckFailed(err)
+		}
+		// Padding makes all responses about the same size, so the prefix cannot be derived from it.
+		req.Header.Set("Add-Padding", "true")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, ErrBreachCheckFailed(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return 0, ErrBreachCheckFailed(resp.Status)
+		}
+
+		count, err := findBreachCount(resp.Body, suffix)
+		if err != nil {
+			return 0, ErrBreachCheckFailed(err)
+		}
+		return count, nil
+	}
+}
+
+// findBreachCount returns the count of the given hash suffix in a response of the range API,
+// which consists of lines in the form <suffix>:<count>.
+func findBreachCount(r io.Reader, suffix string) (int, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], suffix) {
+			continue
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid count %q", parts[1])
+		}
+		return count, nil
+	}
+	return 0, scanner.Err()
+}
+
+// defaultBreachChecker returns a breachChecker that uses the Have I Been Pwned API.
+func defaultBreachChecker() breachChecker {
+	return newBreachChecker(&http.Client{Timeout: 30 * time.Second}, pwnedPasswordsURL)
+}
+
+// checkBreached writes a warning when the secret has appeared in data breaches,
+// or returns an error when the strength is enforced.
+func checkBreached(w io.Writer, check breachChecker, value []byte, enforce bool) error {
+	count, err := check(value)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	times := pluralize("time", "times", count)
+	if enforce {
+		return ErrBreachedSecret(times)
+	}
+	_, err = fmt.Fprintf(w, "%s the secret has appeared %s in data breaches and should not be used. Use --enforce-strength to refuse breached secrets.\n", colorize(colorRoleWarning, "Warning:"), times)
+	return err
+}
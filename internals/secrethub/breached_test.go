@@ -0,0 +1,66 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCheckBreached(t *testing.T) {
+	// The SHA-1 hash of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+	cases := map[string]struct {
+		response string
+		status   int
+		enforce  bool
+		out      string
+		err      error
+	}{
+		"breached": {
+			response: "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n",
+			status:   http.StatusOK,
+			out:      "Warning: the secret has appeared 9545824 times in data breaches and should not be used. Use --enforce-strength to refuse breached secrets.\n",
+		},
+		"breached enforced": {
+			response: "1E4C9B93F3F0682250B6CF8331B7EE68FD8:1\r\n",
+			status:   http.StatusOK,
+			enforce:  true,
+			err:      ErrBreachedSecret("1 time"),
+		},
+		"not breached": {
+			response: "003D68EB55068C33ACE09247EE4C639306B:3\r\n",
+			status:   http.StatusOK,
+		},
+		"padding": {
+			response: "1E4C9B93F3F0682250B6CF8331B7EE68FD8:0\r\n",
+			status:   http.StatusOK,
+		},
+		"request failed": {
+			status: http.StatusServiceUnavailable,
+			err:    ErrBreachCheckFailed("503 Service Unavailable"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Path, "/range/5BAA6")
+				assert.Equal(t, r.Header.Get("Add-Padding"), "true")
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.response)
+			}))
+			defer server.Close()
+
+			var out bytes.Buffer
+			check := newBreachChecker(server.Client(), server.URL+"/range/")
+
+			err := checkBreached(&out, check, []byte("password"), tc.enforce)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, out.String(), tc.out)
+		})
+	}
+}
@@ -0,0 +1,587 @@
+package secrethub
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"github.com/docker/go-units"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Errors
+var (
+	ErrBrowseRequiresTerminal = errMain.Code("browse_requires_terminal").Error("the browser can only be used in a terminal, so its input and output cannot be piped")
+	ErrInvalidAccessRuleInput = errMain.Code("invalid_access_rule_input").Error("enter the account name and the permission separated by a space, e.g. dev1 read")
+)
+
+// The keys the browser responds to, next to the printable characters.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyEnter     = "enter"
+	keyBackspace = "backspace"
+	keyEscape    = "esc"
+	keyInterrupt = "ctrl-c"
+)
+
+// browseHelp describes the keys of the browser.
+const browseHelp = "↑/↓ select  → open  ← back  c copy  d remove  m move  a access rule  r refresh  q quit"
+
+// browseFixedLines is the number of lines the browser uses next to the list of entries.
+const browseFixedLines = 10
+
+// BrowseCommand browses the namespaces, repositories, directories and secrets in a terminal interface.
+type BrowseCommand struct {
+	io                  ui.IO
+	newClient           newClientFunc
+	path                string
+	clipper             clip.Clipper
+	clearClipboardAfter time.Duration
+}
+
+// NewBrowseCommand creates a new BrowseCommand.
+func NewBrowseCommand(io ui.IO, newClient newClientFunc) *BrowseCommand {
+	return &BrowseCommand{
+		io:                  io,
+		newClient:           newClient,
+		clipper:             clip.NewClipboard(),
+		clearClipboardAfter: defaultClearClipboardAfter,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BrowseCommand) Register(r command.Registerer) {
+	clause := r.Command("browse", "Browse your namespaces, repositories, directories and secrets in an interactive terminal interface. "+
+		"Select a secret to see its metadata and copy its value to the clipboard. "+
+		"Secrets, directories and repositories can be removed, secrets can be moved and access rules can be set after a confirmation.")
+	clause.Arg("path", "The namespace, repository or directory to start browsing in. Defaults to the list of your namespaces.").StringVar(&cmd.path)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run starts the browser and handles the keys that are pressed until the browser is closed.
+func (cmd *BrowseCommand) Run() error {
+	if cmd.io.IsInputPiped() || cmd.io.IsOutputPiped() {
+		return ErrBrowseRequiresTerminal
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	b := newBrowser(client, cmd.clipper, cmd.clearClipboardAfter)
+
+	location := strings.Trim(cmd.path, "/")
+	if location != "" {
+		location += "/"
+	}
+	err = b.open(location)
+	if err != nil {
+		return err
+	}
+
+	in := cmd.io.Stdin()
+	out := cmd.io.Output()
+
+	state, err := terminal.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = terminal.Restore(int(in.Fd()), state)
+	}()
+
+	// Switch to the alternate screen and hide the cursor, so the terminal is left as it was when the browser is closed.
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	keys := bufio.NewReader(in)
+	for {
+		_, height, err := terminal.GetSize(int(cmd.io.Stdout().Fd()))
+		if err != nil {
+			height = 24
+		}
+
+		err = b.render(out, height)
+		if err != nil {
+			return err
+		}
+
+		key, err := readKey(keys)
+		if err != nil {
+			return err
+		}
+
+		if b.handleKey(key) {
+			return nil
+		}
+	}
+}
+
+// readKey reads a key press from a terminal in raw mode.
+// Arrow keys and control keys are returned by name, other keys as the typed character.
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+
+	switch c {
+	case '\x1b':
+		if r.Buffered() < 2 {
+			return keyEscape, nil
+		}
+		seq := make([]byte, 2)
+		_, err = io.ReadFull(r, seq)
+		if err != nil {
+			return "", err
+		}
+		if seq[0] != '[' && seq[0] != 'O' {
+			return keyEscape, nil
+		}
+		switch seq[1] {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		case 'C':
+			return keyRight, nil
+		case 'D':
+			return keyLeft, nil
+		}
+		return keyEscape, nil
+	case '\r', '\n':
+		return keyEnter, nil
+	case '\x7f', '\b':
+		return keyBackspace, nil
+	case '\x03':
+		return keyInterrupt, nil
+	}
+	return string(c), nil
+}
+
+// browseMode is the mode that determines how the browser handles keys.
+type browseMode int
+
+// The modes of the browser.
+const (
+	// browseModeList moves through the entries and starts operations.
+	browseModeList browseMode = iota
+	// browseModeConfirm asks for confirmation of an operation.
+	browseModeConfirm
+	// browseModePrompt asks for input for an operation.
+	browseModePrompt
+)
+
+// browser holds the state of the terminal interface.
+type browser struct {
+	client              secrethub.ClientInterface
+	clipper             clip.Clipper
+	clearClipboardAfter time.Duration
+	timeFormatter       TimeFormatter
+
+	// location is the path of which the entries are listed. The namespaces are listed at the empty location.
+	location string
+	entries  []string
+	cursor   int
+	secrets  map[string]*api.Secret
+	status   string
+
+	mode      browseMode
+	question  string
+	input     string
+	onConfirm func() error
+	onInput   func(string) error
+}
+
+// newBrowser creates a new browser.
+func newBrowser(client secrethub.ClientInterface, clipper clip.Clipper, clearClipboardAfter time.Duration) *browser {
+	return &browser{
+		client:              client,
+		clipper:             clipper,
+		clearClipboardAfter: clearClipboardAfter,
+		timeFormatter:       NewTimeFormatter(false),
+		secrets:             map[string]*api.Secret{},
+	}
+}
+
+// open lists the entries at the given location.
+func (b *browser) open(location string) error {
+	entries, err := listPathEntries(b.client, location)
+	if err != nil {
+		return err
+	}
+
+	b.location = location
+	b.entries = entries
+	b.cursor = 0
+	b.secrets = map[string]*api.Secret{}
+	return nil
+}
+
+// reopen lists the entries at the current location again, keeping the cursor at the same position when possible.
+func (b *browser) reopen() error {
+	cursor := b.cursor
+	err := b.open(b.location)
+	if err != nil {
+		return err
+	}
+	b.cursor = cursor
+	if b.cursor >= len(b.entries) {
+		b.cursor = len(b.entries) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+	return nil
+}
+
+// selected returns the path of the entry under the cursor.
+func (b *browser) selected() (string, bool) {
+	if len(b.entries) == 0 {
+		return "", false
+	}
+	return b.entries[b.cursor], true
+}
+
+// handleKey handles a key press and returns whether the browser should be closed.
+func (b *browser) handleKey(key string) bool {
+	switch b.mode {
+	case browseModeConfirm:
+		b.mode = browseModeList
+		if key == "y" || key == "Y" {
+			b.do(b.onConfirm)
+		} else {
+			b.status = "Aborted."
+		}
+		return false
+	case browseModePrompt:
+		switch key {
+		case keyEnter:
+			b.mode = browseModeList
+			input := b.input
+			b.do(func() error {
+				return b.onInput(input)
+			})
+		case keyEscape, keyInterrupt:
+			b.mode = browseModeList
+			b.status = "Aborted."
+		case keyBackspace:
+			runes := []rune(b.input)
+			if len(runes) > 0 {
+				b.input = string(runes[:len(runes)-1])
+			}
+		default:
+			if len([]rune(key)) == 1 {
+				b.input += key
+			}
+		}
+		return false
+	}
+
+	b.status = ""
+	switch key {
+	case "q", keyEscape, keyInterrupt:
+		return true
+	case keyUp, "k":
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case keyDown, "j":
+		if b.cursor < len(b.entries)-1 {
+			b.cursor++
+		}
+	case keyRight, keyEnter, "l":
+		path, ok := b.selected()
+		if ok && strings.HasSuffix(path, "/") {
+			b.do(func() error {
+				return b.open(path)
+			})
+		}
+	case keyLeft, keyBackspace, "h":
+		if b.location != "" {
+			previous := b.location
+			b.do(func() error {
+				err := b.open(completionParent(strings.TrimSuffix(previous, "/")))
+				if err != nil {
+					return err
+				}
+				for i, entry := range b.entries {
+					if entry == previous {
+						b.cursor = i
+					}
+				}
+				return nil
+			})
+		}
+	case "r":
+		b.do(b.reopen)
+	case "c":
+		b.copy()
+	case "d":
+		b.remove()
+	case "m":
+		b.move()
+	case "a":
+		b.setAccessRule()
+	}
+	return false
+}
+
+// do performs the operation and reports its error in the status line.
+func (b *browser) do(fn func() error) {
+	err := fn()
+	if err != nil {
+		b.status = fmt.Sprintf("Error: %s", err)
+	}
+}
+
+// confirm asks for confirmation before performing the operation.
+func (b *browser) confirm(question string, fn func() error) {
+	b.mode = browseModeConfirm
+	b.question = question + " [y/N]"
+	b.onConfirm = fn
+}
+
+// prompt asks for input, starting with the given value, before performing the operation.
+func (b *browser) prompt(question string, value string, fn func(string) error) {
+	b.mode = browseModePrompt
+	b.question = question
+	b.input = value
+	b.onInput = fn
+}
+
+// copy copies the value of the selected secret to the clipboard.
+func (b *browser) copy() {
+	path, ok := b.selected()
+	if !ok || strings.HasSuffix(path, "/") {
+		b.status = "Select a secret to copy its value."
+		return
+	}
+
+	b.do(func() error {
+		version, err := b.client.Secrets().Versions().GetWithData(path)
+		if err != nil {
+			return err
+		}
+
+		err = WriteClipboardAutoClear(version.Data, b.clearClipboardAfter, b.clipper)
+		if err != nil {
+			return err
+		}
+
+		b.status = fmt.Sprintf("Copied %s to the clipboard. It will be cleared after %s.", path, units.HumanDuration(b.clearClipboardAfter))
+		return nil
+	})
+}
+
+// remove removes the selected secret, directory or repository after confirmation.
+func (b *browser) remove() {
+	path, ok := b.selected()
+	if !ok || b.location == "" {
+		b.status = "Select a repository, directory or secret to remove."
+		return
+	}
+
+	b.confirm(fmt.Sprintf("Remove %s? This cannot be undone.", path), func() error {
+		var err error
+		switch {
+		case !strings.HasSuffix(path, "/"):
+			err = b.client.Secrets().Delete(path)
+		case strings.Count(path, "/") == 2:
+			err = b.client.Repos().Delete(strings.TrimSuffix(path, "/"))
+		default:
+			err = b.client.Dirs().Delete(strings.TrimSuffix(path, "/"))
+		}
+		if err != nil {
+			return err
+		}
+
+		err = b.reopen()
+		if err != nil {
+			return err
+		}
+		b.status = fmt.Sprintf("Removed %s.", path)
+		return nil
+	})
+}
+
+// move moves the selected secret to a path that is asked for, after confirmation.
+// The latest version of the secret is written to the new path, after which the secret is removed.
+func (b *browser) move() {
+	path, ok := b.selected()
+	if !ok || strings.HasSuffix(path, "/") {
+		b.status = "Select a secret to move."
+		return
+	}
+
+	b.prompt(fmt.Sprintf("Move %s to: ", path), path, func(destination string) error {
+		destination = strings.TrimSpace(destination)
+		if destination == "" || destination == path {
+			b.status = "Aborted."
+			return nil
+		}
+		err := api.ValidateSecretPath(destination)
+		if err != nil {
+			return err
+		}
+
+		b.confirm(fmt.Sprintf("Move %s to %s? Only the latest version is moved.", path, destination), func() error {
+			version, err := b.client.Secrets().Versions().GetWithData(path)
+			if err != nil {
+				return err
+			}
+
+			_, err = b.client.Secrets().Write(destination, version.Data)
+			if err != nil {
+				return err
+			}
+
+			err = b.client.Secrets().Delete(path)
+			if err != nil {
+				return err
+			}
+
+			err = b.reopen()
+			if err != nil {
+				return err
+			}
+			b.status = fmt.Sprintf("Moved %s to %s.", path, destination)
+			return nil
+		})
+		return nil
+	})
+}
+
+// setAccessRule sets an access rule on the selected directory or repository, or else on the
+// current directory, for the account and permission that are asked for, after confirmation.
+func (b *browser) setAccessRule() {
+	path, ok := b.selected()
+	if !ok || !strings.HasSuffix(path, "/") {
+		path = b.location
+	}
+	if strings.Count(path, "/") < 2 {
+		b.status = "Select a repository or directory to set an access rule on."
+		return
+	}
+	dirPath := strings.TrimSuffix(path, "/")
+
+	b.prompt(fmt.Sprintf("Access rule on %s (<account-name> <permission>): ", dirPath), "", func(input string) error {
+		fields := strings.Fields(input)
+		if len(fields) != 2 {
+			return ErrInvalidAccessRuleInput
+		}
+
+		accountName, err := api.NewAccountName(fields[0])
+		if err != nil {
+			return err
+		}
+
+		var permission api.Permission
+		err = permission.Set(fields[1])
+		if err != nil {
+			return err
+		}
+
+		b.confirm(fmt.Sprintf(
+			"[WARNING] This gives %s %s rights on all directories and secrets contained in %s. Set this access rule?",
+			accountName, permission, dirPath,
+		), func() error {
+			_, err := b.client.AccessRules().Set(dirPath, permission.String(), accountName.Value())
+			if err != nil {
+				return err
+			}
+			b.status = fmt.Sprintf("Access rule set for %s on %s with %s.", accountName, dirPath, permission)
+			return nil
+		})
+		return nil
+	})
+}
+
+// preview returns the lines that describe the selected entry. The value of a secret is never shown.
+func (b *browser) preview() []string {
+	path, ok := b.selected()
+	if !ok {
+		return nil
+	}
+	if strings.HasSuffix(path, "/") {
+		return []string{"Press → to open " + path}
+	}
+
+	secret, ok := b.secrets[path]
+	if !ok {
+		var err error
+		secret, err = b.client.Secrets().Get(path)
+		if err != nil {
+			return []string{fmt.Sprintf("Cannot retrieve %s: %s", path, err)}
+		}
+		b.secrets[path] = secret
+	}
+
+	return []string{
+		fmt.Sprintf("Secret:   %s", path),
+		fmt.Sprintf("Versions: %d (latest is %d)", secret.VersionCount, secret.LatestVersion),
+		fmt.Sprintf("Created:  %s", b.timeFormatter.Format(secret.CreatedAt.Local())),
+		"Value:    ******** (press c to copy)",
+	}
+}
+
+// render draws the browser on a screen of the given height.
+func (b *browser) render(w io.Writer, height int) error {
+	location := b.location
+	if location == "" {
+		location = "namespaces"
+	}
+	lines := []string{"SecretHub: " + location, ""}
+
+	listHeight := height - browseFixedLines
+	if listHeight < 3 {
+		listHeight = 3
+	}
+	start := 0
+	if b.cursor >= listHeight {
+		start = b.cursor - listHeight + 1
+	}
+	if len(b.entries) == 0 {
+		lines = append(lines, "  (empty)")
+	}
+	for i := start; i < len(b.entries) && i < start+listHeight; i++ {
+		marker := "  "
+		if i == b.cursor {
+			marker = "> "
+		}
+		lines = append(lines, marker+strings.TrimPrefix(b.entries[i], b.location))
+	}
+
+	lines = append(lines, "", strings.Repeat("─", 40))
+	lines = append(lines, b.preview()...)
+	lines = append(lines, "")
+
+	switch b.mode {
+	case browseModeConfirm:
+		lines = append(lines, b.question)
+	case browseModePrompt:
+		lines = append(lines, b.question+b.input+"_")
+	default:
+		lines = append(lines, b.status)
+	}
+	lines = append(lines, browseHelp)
+
+	// Clear the screen and write the lines from the top left corner. As the terminal
+	// is in raw mode, lines are ended with a carriage return and a line feed.
+	_, err := fmt.Fprint(w, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+	return err
+}
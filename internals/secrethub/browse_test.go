@@ -0,0 +1,124 @@
+package secrethub
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestReadKey(t *testing.T) {
+	cases := map[string]struct {
+		input    string
+		expected []string
+	}{
+		"arrows": {
+			input:    "\x1b[A\x1b[B\x1b[C\x1b[D",
+			expected: []string{keyUp, keyDown, keyRight, keyLeft},
+		},
+		"control keys": {
+			input:    "\r\x7f\x03\x1b",
+			expected: []string{keyEnter, keyBackspace, keyInterrupt, keyEscape},
+		},
+		"characters": {
+			input:    "dé",
+			expected: []string{"d", "é"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tc.input))
+
+			var actual []string
+			for range tc.expected {
+				key, err := readKey(r)
+				assert.OK(t, err)
+				actual = append(actual, key)
+			}
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestBrowser_handleKey(t *testing.T) {
+	var deleted []string
+	secrets := []*api.Secret{{Name: "api_key"}, {Name: "db_password"}}
+	client := fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return &api.Tree{
+					RootDir: &api.Dir{
+						Name:    "repo",
+						SubDirs: []*api.Dir{{Name: "prod"}},
+						Secrets: secrets,
+					},
+				}, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			DeleteFunc: func(path string) error {
+				deleted = append(deleted, path)
+				secrets = secrets[:1]
+				return nil
+			},
+			GetFunc: func(path string) (*api.Secret, error) {
+				return &api.Secret{Name: "db_password", VersionCount: 2, LatestVersion: 2}, nil
+			},
+		},
+	}
+
+	b := newBrowser(client, nil, defaultClearClipboardAfter)
+	err := b.open("company/repo/")
+	assert.OK(t, err)
+	assert.Equal(t, b.entries, []string{"company/repo/api_key", "company/repo/db_password", "company/repo/prod/"})
+
+	b.handleKey(keyDown)
+	path, _ := b.selected()
+	assert.Equal(t, path, "company/repo/db_password")
+
+	b.handleKey("d")
+	assert.Equal(t, b.mode, browseModeConfirm)
+	b.handleKey("n")
+	assert.Equal(t, b.status, "Aborted.")
+	assert.Equal(t, len(deleted), 0)
+
+	b.handleKey("d")
+	b.handleKey("y")
+	assert.Equal(t, deleted, []string{"company/repo/db_password"})
+	assert.Equal(t, b.status, "Removed company/repo/db_password.")
+	assert.Equal(t, b.entries, []string{"company/repo/api_key", "company/repo/prod/"})
+
+	quit := b.handleKey("q")
+	assert.Equal(t, quit, true)
+}
+
+func TestBrowser_render(t *testing.T) {
+	client := fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			GetFunc: func(path string) (*api.Secret, error) {
+				return &api.Secret{Name: "api_key", VersionCount: 3, LatestVersion: 3}, nil
+			},
+		},
+	}
+
+	b := newBrowser(client, nil, defaultClearClipboardAfter)
+	b.location = "company/repo/"
+	b.entries = []string{"company/repo/api_key", "company/repo/prod/"}
+
+	var buf bytes.Buffer
+	err := b.render(&buf, 24)
+	assert.OK(t, err)
+
+	lines := strings.Split(buf.String(), "\r\n")
+	assert.Equal(t, lines[0], "\x1b[H\x1b[2JSecretHub: company/repo/")
+	assert.Equal(t, lines[2], "> api_key")
+	assert.Equal(t, lines[3], "  prod/")
+	assert.Equal(t, lines[7], "Versions: 3 (latest is 3)")
+	assert.Equal(t, lines[9], "Value:    ******** (press c to copy)")
+}
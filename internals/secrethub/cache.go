@@ -0,0 +1,26 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// CacheCommand handles operations on the local cache of directory trees and secrets.
+type CacheCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewCacheCommand creates a new CacheCommand.
+func NewCacheCommand(io ui.IO, store CredentialConfig) *CacheCommand {
+	return &CacheCommand{
+		io:              io,
+		credentialStore: store,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *CacheCommand) Register(r command.Registerer) {
+	clause := r.Command("cache", "Manage the local cache of directory trees and secrets.")
+	NewCacheClearCommand(cmd.io, cmd.credentialStore).Register(clause)
+}
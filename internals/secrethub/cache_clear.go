@@ -0,0 +1,51 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrCannotClearCache = errMain.Code("cannot_clear_cache").ErrorPref("cannot clear the cache: %s")
+)
+
+// CacheClearCommand removes all cached directory trees and secrets.
+type CacheClearCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewCacheClearCommand creates a new CacheClearCommand.
+func NewCacheClearCommand(io ui.IO, store CredentialConfig) *CacheClearCommand {
+	return &CacheClearCommand{
+		io:              io,
+		credentialStore: store,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CacheClearCommand) Register(r command.Registerer) {
+	clause := r.Command("clear", "Remove all cached directory trees and secrets, so they are fetched again.")
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run removes all cached directory trees and secrets.
+func (cmd *CacheClearCommand) Run() error {
+	dir := cmd.credentialStore.ConfigDir()
+	if dir.Path() == "" {
+		// Without a configuration directory, nothing is cached.
+		return nil
+	}
+
+	err := clearCache(cacheDir(dir))
+	if err != nil {
+		return ErrCannotClearCache(err)
+	}
+
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Cache cleared.")
+	return nil
+}
@@ -0,0 +1,110 @@
+package secrethub
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/alecthomas/kingpin"
+	"github.com/fatih/color"
+)
+
+// ciEnvVars are the environment variables that are set by common CI services.
+var ciEnvVars = []string{
+	"CI",
+	"CONTINUOUS_INTEGRATION",
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"CIRCLECI",
+	"TRAVIS",
+	"JENKINS_URL",
+	"BUILDKITE",
+	"TF_BUILD",
+	"TEAMCITY_VERSION",
+	"BITBUCKET_BUILD_NUMBER",
+	"CODEBUILD_BUILD_ID",
+	"DRONE",
+	"APPVEYOR",
+	"SEMAPHORE",
+}
+
+var (
+	// detectedCIEnvVar is the environment variable by which a CI environment is detected.
+	// It is empty when the CLI does not run in a CI environment.
+	detectedCIEnvVar = detectCI(os.Getenv)
+	// noCIDetection is set with the --no-ci-detection flag to behave the same in a CI environment as anywhere else.
+	noCIDetection bool
+)
+
+// detectCI returns the first of the environment variables of the CI services that is set.
+// A variable that is set to false or 0 does not count, so CI=false disables the detection.
+func detectCI(getenv func(string) string) string {
+	for _, name := range ciEnvVars {
+		value := strings.ToLower(getenv(name))
+		if value != "" && value != "false" && value != "0" {
+			return name
+		}
+	}
+	return ""
+}
+
+// isCI returns whether the CLI runs in a CI environment and the detection is not disabled.
+func isCI() bool {
+	return detectedCIEnvVar != "" && !noCIDetection
+}
+
+// RegisterCIDetection registers the flag that disables the CI detection and disables colors
+// and the pager before a command is run in a CI environment.
+func RegisterCIDetection(app *cli.App) {
+	app.Flag("no-ci-detection", "Do not change the behavior of the CLI when it runs in a CI environment. "+
+		"A CI environment is detected with the environment variables of common CI services, such as CI, GITHUB_ACTIONS and JENKINS_URL. "+
+		"In a CI environment the CLI never prompts for input, so commands fail instead of asking for confirmation when --force is not set, "+
+		"and colors and the pager are disabled.").BoolVar(&noCIDetection)
+
+	app.PreAction(func(*kingpin.ParseContext) error {
+		if isCI() {
+			color.NoColor = true
+			noPager = true
+		}
+		return nil
+	})
+}
+
+// ciIO is an ui.IO that cannot prompt the user for input when the CLI runs in a CI environment.
+// Scripts then fail with an error instead of waiting forever on a prompt that nobody sees.
+type ciIO struct {
+	ui.IO
+}
+
+// Prompts returns ui.ErrCannotAsk in a CI environment.
+func (o ciIO) Prompts() (io.Reader, io.Writer, error) {
+	if isCI() {
+		return nil, nil, ui.ErrCannotAsk
+	}
+	return o.IO.Prompts()
+}
+
+// ReadSecret returns ui.ErrCannotAsk in a CI environment.
+func (o ciIO) ReadSecret() ([]byte, error) {
+	if isCI() {
+		return nil, ui.ErrCannotAsk
+	}
+	return o.IO.ReadSecret()
+}
+
+// ciPromptHint returns a hint for an error that occurs because the CLI cannot prompt for input in a CI environment.
+// It returns an empty string for any other error.
+func ciPromptHint(err error) string {
+	if !isCI() {
+		return ""
+	}
+	publicErr, ok := asPublicError(err)
+	if !ok || (publicErr.Code != "cannot_ask_for_input" && publicErr.Code != "cannot_do_without_force") {
+		return ""
+	}
+	return "The CLI does not prompt for input because a CI environment is detected by the " + detectedCIEnvVar + " environment variable. " +
+		"Use --force to skip confirmations and pass all required arguments as flags, or use --no-ci-detection to allow prompts."
+}
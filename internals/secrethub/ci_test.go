@@ -0,0 +1,108 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestDetectCI(t *testing.T) {
+	cases := map[string]struct {
+		env      map[string]string
+		expected string
+	}{
+		"no ci": {
+			env:      map[string]string{"HOME": "/home/dev1"},
+			expected: "",
+		},
+		"ci": {
+			env:      map[string]string{"CI": "true"},
+			expected: "CI",
+		},
+		"github actions": {
+			env:      map[string]string{"GITHUB_ACTIONS": "true"},
+			expected: "GITHUB_ACTIONS",
+		},
+		"jenkins": {
+			env:      map[string]string{"JENKINS_URL": "https://jenkins.example.com/"},
+			expected: "JENKINS_URL",
+		},
+		"ci false": {
+			env:      map[string]string{"CI": "false"},
+			expected: "",
+		},
+		"ci 0": {
+			env:      map[string]string{"CI": "0"},
+			expected: "",
+		},
+		"ci false on ci service": {
+			env:      map[string]string{"CI": "False", "GITLAB_CI": "true"},
+			expected: "GITLAB_CI",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := detectCI(func(name string) string {
+				return tc.env[name]
+			})
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestCIIO_Prompts(t *testing.T) {
+	cases := map[string]struct {
+		detected      string
+		noCIDetection bool
+		err           error
+	}{
+		"no ci": {
+			detected: "",
+		},
+		"ci": {
+			detected: "CI",
+			err:      ui.ErrCannotAsk,
+		},
+		"ci detection disabled": {
+			detected:      "CI",
+			noCIDetection: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			detected, disabled := detectedCIEnvVar, noCIDetection
+			defer func() {
+				detectedCIEnvVar, noCIDetection = detected, disabled
+			}()
+			detectedCIEnvVar, noCIDetection = tc.detected, tc.noCIDetection
+
+			io := ciIO{IO: fakeui.NewIO(t)}
+			_, _, err := io.Prompts()
+			assert.Equal(t, err, tc.err)
+
+			_, err = io.ReadSecret()
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func TestCIPromptHint(t *testing.T) {
+	detected, disabled := detectedCIEnvVar, noCIDetection
+	defer func() {
+		detectedCIEnvVar, noCIDetection = detected, disabled
+	}()
+	detectedCIEnvVar, noCIDetection = "GITHUB_ACTIONS", false
+
+	assert.Equal(t, ciPromptHint(ErrCannotDoWithoutForce) != "", true)
+	assert.Equal(t, ciPromptHint(ui.ErrCannotAsk) != "", true)
+	assert.Equal(t, ciPromptHint(ErrSecretAlreadyExists), "")
+
+	noCIDetection = true
+	assert.Equal(t, ciPromptHint(ErrCannotDoWithoutForce), "")
+}
@@ -44,7 +44,11 @@ func (cmd *ClearClipboardCommand) Run() error {
 	}
 
 	read, err := cmd.clipper.ReadAll()
-	if err != nil {
+	if err == clip.ErrReadUnsupported {
+		// The clipboard cannot be checked for the secret, so it is cleared regardless,
+		// as leaving the secret on the clipboard is worse than clearing other content.
+		return cmd.clipper.WriteAll(nil)
+	} else if err != nil {
 		return err
 	}
 
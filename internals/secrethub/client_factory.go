@@ -3,7 +3,14 @@ package secrethub
 import (
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/vault"
 
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
@@ -13,72 +20,301 @@ import (
 // Errors
 var (
 	ErrUnknownIdentityProvider = errMain.Code("unknown_identity_provider").ErrorPref("%s is not a supported identity provider. Valid options are `aws`, `gcp` and `key`.")
+	ErrUnknownBackend          = errMain.Code("unknown_backend").ErrorPref("%s is not a supported backend. Valid options are `api` and `local`.")
+	ErrVaultNotFound           = errMain.Code("vault_not_found").ErrorPref("there is no local vault at %s: create one with `vault init`")
+	ErrVaultExists             = errMain.Code("vault_exists").ErrorPref("a local vault already exists at %s")
+)
+
+// The backends that store repositories and secrets.
+const (
+	backendAPI   = "api"
+	backendLocal = "local"
 )
 
+// vaultServerURL is the address clients of the local vault send their requests to. The requests
+// never leave the process, as they are handled by the vault itself.
+const vaultServerURL = "http://vault.local"
+
 // ClientFactory handles creating a new client with the configured options.
 type ClientFactory interface {
 	// NewClient returns a new SecretHub client.
 	NewClient() (secrethub.ClientInterface, error)
+	// NewNonInteractiveClient returns a new SecretHub client that never prompts for input.
+	NewNonInteractiveClient() (secrethub.ClientInterface, error)
 	NewClientWithCredentials(credentials.Provider) (secrethub.ClientInterface, error)
 	NewUnauthenticatedClient() (secrethub.ClientInterface, error)
+	// NewAPIClient returns a new client for the SecretHub API, also when the local backend is configured.
+	NewAPIClient() (secrethub.ClientInterface, error)
+	// NewVaultClient returns a new client for the local vault, also when the API backend is configured.
+	NewVaultClient() (secrethub.ClientInterface, error)
+	// CreateVault creates a new local vault with an account with the given username and returns its path.
+	CreateVault(username string) (string, error)
+	// APIRemote returns the API address that is configured with the --api-remote flag,
+	// or nil when the default address is used.
+	APIRemote() *url.URL
+	// NewAPITransport returns the transport the clients for the SecretHub API send their requests with,
+	// which authenticates every request with the configured credential.
+	NewAPITransport() (http.RoundTripper, error)
 	Register(FlagRegisterer)
 }
 
 // NewClientFactory creates a new ClientFactory.
-func NewClientFactory(store CredentialConfig) ClientFactory {
+func NewClientFactory(io ui.IO, store CredentialConfig) ClientFactory {
 	return &clientFactory{
+		io:    io,
 		store: store,
 	}
 }
 
 type clientFactory struct {
+	io               ui.IO
 	client           *secrethub.Client
+	vaultClient      *secrethub.Client
+	backend          string
+	vaultFile        string
+	vaultPassphrase  string
 	ServerURL        *url.URL
 	identityProvider string
 	proxyAddress     *url.URL
+	retries          retriesFlag
+	timeout          time.Duration
+	noCache          bool
+	offline          bool
+	readOnly         bool
 	store            CredentialConfig
 }
 
 // Register the flags for configuration on a cli application.
 func (f *clientFactory) Register(r FlagRegisterer) {
 	r.Flag("api-remote", "The SecretHub API address, don't set this unless you know what you're doing.").Hidden().URLVar(&f.ServerURL)
+	r.Flag("remote", "The address of the SecretHub API to use instead of the default, such as a development server started with the dev server command.").URLVar(&f.ServerURL)
 	r.Flag("identity-provider", "Enable native authentication with a trusted identity provider. Options are `aws` (IAM + KMS), `gcp` (IAM + KMS) and `key`. When you run the CLI on one of the platforms, you can leverage their respective identity providers to do native keyless authentication. Defaults to key, which uses the default credential sourced from a file, command-line flag, or environment variable. ").Default("key").StringVar(&f.identityProvider)
+	r.Flag("retries", "The number of times to retry API calls that fail because of a network error or a temporary server error, "+
+		"waiting exponentially longer between the retries and as long as the server asks. Only calls that can safely be repeated are retried. "+
+		"Defaults to the retries set in the retry section of the "+configFilename+" file in the configuration directory, which also sets the "+
+		"initial_delay and max_delay between retries, or to "+strconv.Itoa(defaultRetries)+".").PlaceHolder(strconv.Itoa(defaultRetries)).SetValue(&f.retries)
+	r.Flag("timeout", "The maximum time an API call may take, from connecting to the API until the response has been read, before it fails. "+
+		"Every retry of a call gets the full time again. Defaults to the timeout set in the "+configFilename+" file in the configuration directory, or to "+defaultTimeout.String()+".").
+		PlaceHolder(defaultTimeout.String()).DurationVar(&f.timeout)
+	r.Flag("no-cache", "Do not use or update the cache of directory trees, which makes repeated listings and completions faster. "+
+		"The cache is enabled by setting cache_ttl in the "+configFilename+" file in the configuration directory to the time cached trees are used, e.g. 5m. "+
+		"Use the cache clear command to clear the cache.").BoolVar(&f.noCache)
+	r.Flag("offline", "Read secrets from the offline cache without calling the API. Only secrets that have been read within the max_age "+
+		"of the offline_cache setting in the "+configFilename+" file in the configuration directory can be read offline. "+
+		"When the offline cache is enabled, cached secrets are also read when the API is unavailable.").BoolVar(&f.offline)
+	r.Flag("read-only", "Refuse all commands that make changes, such as write, rm, acl set and service init, e.g. on shared terminals and bastion hosts. "+
+		"Read-only mode can also be enabled with the read_only setting in the "+configFilename+" file in the configuration directory.").BoolVar(&f.readOnly)
+	r.Flag("backend", "Where repositories and secrets are stored. Options are `api`, the SecretHub API, and `local`, a passphrase-encrypted vault file on this computer "+
+		"that is created with `vault init` and can be used without an account. The local vault supports managing repositories, directories and secrets, "+
+		"so commands like read, write, ls, run and inject work the same, and can later be pushed to an account with `vault push`.").
+		Default(backendAPI).HintOptions(backendAPI, backendLocal).StringVar(&f.backend)
+	r.Flag("vault-file", "The path of the local vault. Defaults to the file vault in the configuration directory.").StringVar(&f.vaultFile)
+	r.Flag("vault-passphrase", "The passphrase of the local vault. When not set, it is asked for. "+
+		"Please only use this if you know what you're doing and ensure your passphrase doesn't end up in bash history.").StringVar(&f.vaultPassphrase)
 	r.Flag("proxy-address", "Set to the address of a proxy to connect to the API through a proxy. The prepended scheme determines the proxy type (http, https and socks5 are supported). For example: `--proxy-address http://my-proxy:1234`").URLVar(&f.proxyAddress)
 }
 
-// NewClient returns a new client that is configured to use the remote that
-// is set with the flag.
+// NewClient returns a new client for the configured backend. For the API, the client
+// is configured to use the remote that is set with the flag.
 func (f *clientFactory) NewClient() (secrethub.ClientInterface, error) {
+	local, err := f.isLocal()
+	if err != nil {
+		return nil, err
+	}
+	if local {
+		return f.NewVaultClient()
+	}
+	return f.NewAPIClient()
+}
+
+// NewAPIClient returns a new client that is configured to use the remote that
+// is set with the flag, regardless of the configured backend.
+func (f *clientFactory) NewAPIClient() (secrethub.ClientInterface, error) {
 	if f.client == nil {
-		var credentialProvider credentials.Provider
-		switch strings.ToLower(f.identityProvider) {
-		case "aws":
-			credentialProvider = credentials.UseAWS()
-		case "gcp":
-			credentialProvider = credentials.UseGCPServiceAccount()
-		case "key":
-			credentialProvider = f.store.Provider()
-		default:
-			return nil, ErrUnknownIdentityProvider(f.identityProvider)
+		client, err := f.newClient(f.store.Provider())
+		if err != nil {
+			return nil, err
 		}
+		f.client = client
+	}
+	return f.client, nil
+}
+
+// NewNonInteractiveClient returns a new client like NewClient does, but that never
+// prompts for the passphrase of the credential.
+func (f *clientFactory) NewNonInteractiveClient() (secrethub.ClientInterface, error) {
+	local, err := f.isLocal()
+	if err != nil {
+		return nil, err
+	}
+	if local {
+		return f.newVaultClient(nonInteractiveIO{IO: f.io})
+	}
+	return f.newClient(f.store.NonInteractiveProvider())
+}
 
-		options := f.baseClientOptions()
-		options = append(options, secrethub.WithCredentials(credentialProvider))
+// isLocal returns whether the local vault is configured as the backend.
+func (f *clientFactory) isLocal() (bool, error) {
+	switch strings.ToLower(f.backend) {
+	case backendAPI, "":
+		return false, nil
+	case backendLocal:
+		return true, nil
+	default:
+		return false, ErrUnknownBackend(f.backend)
+	}
+}
 
-		client, err := secrethub.NewClient(options...)
-		if err == configdir.ErrCredentialNotFound {
-			return nil, ErrCredentialNotExist
-		} else if err != nil {
+// NewVaultClient returns a new client for the local vault, of which the passphrase is asked for when it is not set with the flag.
+func (f *clientFactory) NewVaultClient() (secrethub.ClientInterface, error) {
+	if f.vaultClient == nil {
+		client, err := f.newVaultClient(f.io)
+		if err != nil {
 			return nil, err
 		}
-		f.client = client
+		f.vaultClient = client
 	}
-	return f.client, nil
+	return f.vaultClient, nil
 }
 
-func (f *clientFactory) NewClientWithCredentials(provider credentials.Provider) (secrethub.ClientInterface, error) {
-	options := f.baseClientOptions()
+// newVaultClient unlocks the local vault and returns a client that uses it as its transport.
+// The passphrase is asked for with the given IO when it is not set with the flag.
+func (f *clientFactory) newVaultClient(io ui.IO) (*secrethub.Client, error) {
+	path := f.vaultPath()
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, ErrVaultNotFound(path)
+	}
+
+	passphrase := f.vaultPassphrase
+	if passphrase == "" {
+		passphrase, err = ui.AskSecret(io, "Please put in the passphrase to unlock your local vault:")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	v, err := vault.Open(path, []byte(passphrase))
+	if err == vault.ErrNotFound {
+		return nil, ErrVaultNotFound(path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var transport http.RoundTripper = v
+	if f.isReadOnly() {
+		transport = newReadOnlyTransport(transport)
+	}
+
+	return secrethub.NewClient(
+		secrethub.WithServerURL(vaultServerURL),
+		secrethub.WithTransport(transport),
+		secrethub.WithCredentials(credentials.UseKey(credentials.FromString(v.Credential()))),
+		secrethub.WithAppInfo(&secrethub.AppInfo{
+			Name:    "secrethub-cli",
+			Version: Version,
+		}),
+	)
+}
+
+// CreateVault creates a new local vault with an account with the given username. The passphrase
+// of the vault is asked for when it is not set with the flag.
+func (f *clientFactory) CreateVault(username string) (string, error) {
+	path := f.vaultPath()
+	_, err := os.Stat(path)
+	if err == nil {
+		return "", ErrVaultExists(path)
+	}
+
+	passphrase := f.vaultPassphrase
+	if passphrase == "" {
+		passphrase, err = ui.AskPassphrase(f.io, "Please enter a passphrase to encrypt the local vault with: ", "Enter the same passphrase again: ", 3)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err = vault.Create(path, username, []byte(passphrase))
+	if err == vault.ErrAlreadyExists {
+		return "", ErrVaultExists(path)
+	} else if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// vaultPath returns the path of the local vault.
+func (f *clientFactory) vaultPath() string {
+	if f.vaultFile != "" {
+		return f.vaultFile
+	}
+	return filepath.Join(f.store.ConfigDir().Path(), "vault")
+}
+
+// newClient returns a new client that authenticates with the configured identity provider.
+// The given key provider is used when the identity provider is key.
+func (f *clientFactory) newClient(keyProvider credentials.Provider) (*secrethub.Client, error) {
+	credentialProvider, err := f.credentialProvider(keyProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := newTreeCacheKey(treeCacheKeyPath(f.store.ConfigDir()))
+	options := f.baseClientOptions(cacheKey)
+	options = append(options, secrethub.WithCredentials(cacheKey.Provider(credentialProvider)))
+
+	client, err := secrethub.NewClient(options...)
+	if err == configdir.ErrCredentialNotFound {
+		return nil, ErrCredentialNotExist
+	} else if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// credentialProvider returns the provider of the credential of the configured identity provider.
+// The given key provider is used when the identity provider is key.
+func (f *clientFactory) credentialProvider(keyProvider credentials.Provider) (credentials.Provider, error) {
+	switch strings.ToLower(f.identityProvider) {
+	case "aws":
+		return credentials.UseAWS(), nil
+	case "gcp":
+		return credentials.UseGCPServiceAccount(), nil
+	case "key":
+		return keyProvider, nil
+	default:
+		return nil, ErrUnknownIdentityProvider(f.identityProvider)
+	}
+}
+
+// NewAPITransport returns the transport of the clients for the SecretHub API, which authenticates every
+// request with the credential of the configured identity provider. It is used for requests the client
+// has no method for, which still go through the proxy, timeouts and retries the clients use.
+func (f *clientFactory) NewAPITransport() (http.RoundTripper, error) {
+	credentialProvider, err := f.credentialProvider(f.store.Provider())
+	if err != nil {
+		return nil, err
+	}
+
+	// The credential is provided by creating a client, so identity providers that exchange
+	// their credential for a session do so through the configured transport.
+	provider := &authenticatorProvider{Provider: credentialProvider}
+	options := f.baseClientOptions(nil)
 	options = append(options, secrethub.WithCredentials(provider))
+	_, err = secrethub.NewClient(options...)
+	if err == configdir.ErrCredentialNotFound {
+		return nil, ErrCredentialNotExist
+	} else if err != nil {
+		return nil, err
+	}
+
+	return newAuthTransport(f.transport(nil), provider.authenticator), nil
+}
+
+func (f *clientFactory) NewClientWithCredentials(provider credentials.Provider) (secrethub.ClientInterface, error) {
+	cacheKey := newTreeCacheKey(treeCacheKeyPath(f.store.ConfigDir()))
+	options := f.baseClientOptions(cacheKey)
+	options = append(options, secrethub.WithCredentials(cacheKey.Provider(provider)))
 
 	client, err := secrethub.NewClient(options...)
 	if err != nil {
@@ -88,8 +324,12 @@ func (f *clientFactory) NewClientWithCredentials(provider credentials.Provider)
 	return client, nil
 }
 
+func (f *clientFactory) APIRemote() *url.URL {
+	return f.ServerURL
+}
+
 func (f *clientFactory) NewUnauthenticatedClient() (secrethub.ClientInterface, error) {
-	options := f.baseClientOptions()
+	options := f.baseClientOptions(nil)
 
 	client, err := secrethub.NewClient(options...)
 	if err != nil {
@@ -99,7 +339,8 @@ func (f *clientFactory) NewUnauthenticatedClient() (secrethub.ClientInterface, e
 	return client, nil
 }
 
-func (f *clientFactory) baseClientOptions() []secrethub.ClientOption {
+// baseClientOptions returns the options all clients are created with.
+func (f *clientFactory) baseClientOptions(cacheKey *treeCacheKey) []secrethub.ClientOption {
 	options := []secrethub.ClientOption{
 		secrethub.WithConfigDir(f.store.ConfigDir()),
 		secrethub.WithAppInfo(&secrethub.AppInfo{
@@ -108,17 +349,53 @@ func (f *clientFactory) baseClientOptions() []secrethub.ClientOption {
 		}),
 	}
 
+	options = append(options, secrethub.WithTransport(f.transport(cacheKey)), secrethub.WithTimeout(0))
+
+	if f.ServerURL != nil {
+		options = append(options, secrethub.WithServerURL(f.ServerURL.String()))
+	}
+
+	return options
+}
+
+// transport returns the transport that the requests of all clients go through. Directory trees are
+// only cached when a key to encrypt them with is given.
+func (f *clientFactory) transport(cacheKey *treeCacheKey) http.RoundTripper {
+	var transport http.RoundTripper = http.DefaultTransport
 	if f.proxyAddress != nil {
-		transport := http.DefaultTransport.(*http.Transport)
-		transport.Proxy = func(request *http.Request) (*url.URL, error) {
+		proxyTransport := http.DefaultTransport.(*http.Transport)
+		proxyTransport.Proxy = func(request *http.Request) (*url.URL, error) {
 			return f.proxyAddress, nil
 		}
-		options = append(options, secrethub.WithTransport(transport))
+		transport = proxyTransport
 	}
 
-	if f.ServerURL != nil {
-		options = append(options, secrethub.WithServerURL(f.ServerURL.String()))
+	if verbosity >= verbosityAPICalls {
+		transport = newLoggingTransport(transport, os.Stderr, verbosity >= verbosityAPIBodies)
+	}
+	if structuredLog.enabled(logLevelDebug) {
+		transport = newStructuredLogTransport(transport, structuredLog)
 	}
 
-	return options
+	// Every attempt of a retried call is logged and gets the full timeout, so the retries wrap the
+	// timeout and the logging. The timeout of the client would limit the total time of all attempts,
+	// so it is disabled.
+	transport = newTimeoutTransport(transport, f.requestTimeout())
+	// Waiting for the rate limit is done outside of the timeout, so it does not count towards it.
+	transport = newThrottleTransport(transport, &apiThrottle)
+	transport = newRetryTransport(transport, f.retryPolicy())
+
+	// A cached secret is only returned when all retries have failed, so the offline cache wraps the retries.
+	if maxAge, enabled := f.offlineCacheMaxAge(); enabled || f.offline {
+		transport = newOfflineCacheTransport(transport, offlineCacheDir(f.store.ConfigDir()), maxAge, f.offline)
+	}
+	// A cached directory tree is returned without calling the API at all, so the cache wraps all other transports.
+	if ttl := f.cacheTTL(); ttl > 0 && cacheKey != nil {
+		transport = newTreeCacheTransport(transport, treeCacheDir(f.store.ConfigDir()), ttl, cacheKey.Get)
+	}
+	// Changes are refused before they reach any other transport, so they do not clear the cache either.
+	if f.isReadOnly() {
+		transport = newReadOnlyTransport(transport)
+	}
+	return transport
 }
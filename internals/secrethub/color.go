@@ -1,8 +1,11 @@
 package secrethub
 
 import (
+	"fmt"
+	"os"
 	"strconv"
 
+	"github.com/alecthomas/kingpin"
 	"github.com/fatih/color"
 )
 
@@ -15,9 +18,23 @@ func (f noColorFlag) init() {
 }
 
 // RegisterColorFlag registers a color flag that configures whether colored output is used.
+// Colored output is also disabled when the NO_COLOR environment variable is set.
 func RegisterColorFlag(r FlagRegisterer) {
-	flag := noColorFlag(false)
-	r.Flag("no-color", "Disable colored output.").SetValue(&flag)
+	flag := noColorFlag(os.Getenv("NO_COLOR") != "")
+	flag.init()
+	r.Flag("no-color", "Disable colored output. Colored output is also disabled when the NO_COLOR environment variable is set.").SetValue(&flag)
+}
+
+// RegisterColorTheme loads the color theme configured in the configuration directory before a command is run.
+// An invalid theme configuration does not stop the command: a warning is printed and the default theme is used.
+func RegisterColorTheme(app *kingpin.Application, store CredentialConfig) {
+	app.PreAction(func(*kingpin.ParseContext) error {
+		err := loadColorTheme(store.ConfigDir())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s\n", colorize(colorRoleWarning, "Warning:"), err)
+		}
+		return nil
+	})
 }
 
 // String implements the flag.Value interface.
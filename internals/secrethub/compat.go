@@ -0,0 +1,185 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrUnsupportedFeatures = errMain.Code("unsupported_features").ErrorPref("the API at %s does not support %d of the features of the CLI")
+)
+
+// The statuses of the features reported by the compat command.
+const (
+	compatSupported   = "supported"
+	compatUnsupported = "unsupported"
+	compatUnknown     = "unknown"
+)
+
+// compatColorRoles are the roles in the color theme of the statuses of features.
+var compatColorRoles = map[string]string{
+	compatSupported:   colorRoleAdded,
+	compatUnknown:     colorRoleWarning,
+	compatUnsupported: colorRoleRemoved,
+}
+
+// compatProbeID is used for the parameters in the paths of the endpoints that are probed.
+// No resource has this ID, so the probes never read an actual resource.
+const compatProbeID = "00000000-0000-0000-0000-000000000000"
+
+// compatFeature is a feature of the CLI that needs an endpoint of the API that not every server has.
+type compatFeature struct {
+	name     string
+	commands string
+	// path is the path of the endpoint relative to the base path of the API that is probed with a GET request.
+	path string
+}
+
+// compatFeatures are the features of the CLI of which the endpoints are probed.
+var compatFeatures = []compatFeature{
+	{name: "repositories", commands: "repo, ls, tree", path: "/namespaces/compat/repos"},
+	{name: "secrets", commands: "read, write, rm, run, inject", path: "/secrets/" + compatProbeID + "/versions"},
+	{name: "access rules", commands: "acl", path: "/dirs/" + compatProbeID + "/rules"},
+	{name: "audit log", commands: "audit, org audit", path: "/namespaces/compat/repos/compat/events"},
+	{name: "service accounts", commands: "service", path: "/namespaces/compat/repos/compat/services"},
+	{name: "organizations", commands: "org, namespaces ls", path: "/orgs"},
+	{name: "credentials", commands: "credential ls, credential disable", path: "/me/credentials"},
+	{name: "GCP identity provider", commands: "service gcp init", path: "/identity-providers/gcp/config/oauth2"},
+	{name: "identity provider links", commands: "service gcp link, service gcp list-links", path: "/namespaces/compat/identity-providers/gcp/links"},
+}
+
+// compatResult is the machine readable result of the probe of a feature.
+type compatResult struct {
+	Feature  string
+	Commands string
+	Status   string
+	Message  string `json:",omitempty"`
+}
+
+// CompatCommand checks which features of the CLI are supported by the API it connects to.
+type CompatCommand struct {
+	io           ui.IO
+	output       string
+	apiRemote    func() *url.URL
+	newTransport func() (http.RoundTripper, error)
+}
+
+// NewCompatCommand creates a new CompatCommand.
+func NewCompatCommand(io ui.IO, apiRemote func() *url.URL, newTransport func() (http.RoundTripper, error)) *CompatCommand {
+	return &CompatCommand{
+		io:           io,
+		apiRemote:    apiRemote,
+		newTransport: newTransport,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CompatCommand) Register(r command.Registerer) {
+	clause := r.Command("compat", "Check which features of the CLI are supported by the API it connects to, "+
+		"e.g. an older self-hosted server set with --api-remote. Fails when a feature is not supported.")
+	clause.HelpLong("compat probes the endpoints of the API that the features of the CLI need with authenticated requests. " +
+		"An endpoint that the server does not know is reported as unsupported, so you know which commands will not work " +
+		"before they fail halfway with an error of the server. An endpoint that refuses the request, e.g. because your " +
+		"account has no access, is reported as unknown.")
+	registerOutputFlag(clause, &cmd.output, formatTable, formatJSON, formatYAML)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run probes the features and prints which are supported.
+func (cmd *CompatCommand) Run() error {
+	remote := defaultAPIRemote
+	if u := cmd.apiRemote(); u != nil {
+		remote = strings.TrimSuffix(u.String(), "/")
+	}
+
+	transport, err := cmd.newTransport()
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Transport: transport}
+
+	results := make([]compatResult, len(compatFeatures))
+	unsupported := 0
+	for i, feature := range compatFeatures {
+		status, message := probeCompat(client, remote+"/v1"+feature.path)
+		results[i] = compatResult{
+			Feature:  feature.name,
+			Commands: feature.commands,
+			Status:   status,
+			Message:  message,
+		}
+		if status == compatUnsupported {
+			unsupported++
+		}
+	}
+
+	if cmd.output != formatTable && cmd.output != "" {
+		err = writeOutput(cmd.io.Output(), cmd.output, results)
+		if err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", "FEATURE", "COMMANDS", "STATUS")
+		for _, result := range results {
+			status := result.Status
+			if result.Message != "" {
+				status += ": " + result.Message
+			}
+			// Only the last column is colored, as the escape codes would break the alignment of the other columns.
+			fmt.Fprintf(w, "%s\t%s\t%s\n", result.Feature, result.Commands, colorize(compatColorRoles[result.Status], status))
+		}
+		err = w.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	if unsupported > 0 {
+		return ErrUnsupportedFeatures(remote, unsupported)
+	}
+	return nil
+}
+
+// probeCompat requests the endpoint and returns whether the server supports it, with a message when that cannot be determined.
+// Only a successful response or an error about the requested resource shows that the endpoint exists.
+func probeCompat(client *http.Client, endpoint string) (string, string) {
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return compatUnknown, err.Error()
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		return compatUnsupported, ""
+	case http.StatusNotFound:
+		// A resource that does not exist is reported with the code of its type of
+		// resource, but an endpoint that does not exist is reported as not found.
+		var body struct {
+			Error errio.PublicError `json:"error"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		if err != nil || body.Error.Code == api.ErrNotFound.Code {
+			return compatUnsupported, ""
+		}
+		return compatSupported, ""
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return compatSupported, ""
+	}
+	return compatUnknown, fmt.Sprintf("the server responded with %s", resp.Status)
+}
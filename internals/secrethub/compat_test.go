@@ -0,0 +1,111 @@
+package secrethub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCompatCommand_probe(t *testing.T) {
+	cases := map[string]struct {
+		status   int
+		body     string
+		expected string
+	}{
+		"success": {
+			status:   http.StatusOK,
+			body:     `[]`,
+			expected: compatSupported,
+		},
+		"unauthenticated": {
+			status:   http.StatusUnauthorized,
+			body:     `{"error":{"namespace":"auth","code":"missing_header","message":"Missing Authorization header"}}`,
+			expected: compatUnknown,
+		},
+		"forbidden": {
+			status:   http.StatusForbidden,
+			body:     `{"error":{"namespace":"server","code":"forbidden","message":"Access denied"}}`,
+			expected: compatUnknown,
+		},
+		"resource not found": {
+			status:   http.StatusNotFound,
+			body:     `{"error":{"namespace":"server","code":"repo_not_found","message":"Repository not found"}}`,
+			expected: compatSupported,
+		},
+		"endpoint not found": {
+			status:   http.StatusNotFound,
+			body:     `{"error":{"namespace":"server","code":"not_found","message":"Not found"}}`,
+			expected: compatUnsupported,
+		},
+		"not found without error": {
+			status:   http.StatusNotFound,
+			body:     "404 page not found",
+			expected: compatUnsupported,
+		},
+		"method not supported": {
+			status:   http.StatusNotImplemented,
+			expected: compatUnsupported,
+		},
+		"method not allowed": {
+			status:   http.StatusMethodNotAllowed,
+			expected: compatUnsupported,
+		},
+		"server error": {
+			status:   http.StatusBadGateway,
+			expected: compatUnknown,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			status, _ := probeCompat(server.Client(), server.URL+"/v1/orgs")
+
+			assert.Equal(t, status, tc.expected)
+		})
+	}
+}
+
+func TestCompatCommand_Run(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/identity-providers/") {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		if r.Header.Get("Authorization") != "test-auth" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	remote, err := url.Parse(server.URL)
+	assert.OK(t, err)
+
+	io := fakeui.NewIO(t)
+	cmd := CompatCommand{
+		io:        io,
+		output:    formatJSON,
+		apiRemote: func() *url.URL { return remote },
+		newTransport: func() (http.RoundTripper, error) {
+			return newAuthTransport(server.Client().Transport, fakeAuthenticator{}), nil
+		},
+	}
+
+	err = cmd.Run()
+
+	assert.Equal(t, err, ErrUnsupportedFeatures(server.URL, 2))
+	assert.Equal(t, strings.Count(io.Out.String(), `"Status": "unsupported"`), 2)
+	assert.Equal(t, strings.Count(io.Out.String(), `"Status": "supported"`), len(compatFeatures)-2)
+}
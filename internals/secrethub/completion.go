@@ -0,0 +1,100 @@
+package secrethub
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/alecthomas/kingpin"
+)
+
+// The shells for which a completion script can be generated.
+const (
+	shellBash       = "bash"
+	shellZsh        = "zsh"
+	shellFish       = "fish"
+	shellPowerShell = "powershell"
+)
+
+// fishCompletionTemplate is the completion script for fish. Like the scripts for bash and zsh,
+// it passes the words on the command-line to the hidden --completion-bash flag. The current
+// token is passed quoted, so that an empty token is passed as an empty argument.
+const fishCompletionTemplate = `function __{{.App.Name}}_complete
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    set -l current (commandline -ct)
+    {{.App.Name}} --completion-bash $tokens "$current"
+end
+
+complete -c {{.App.Name}} -f -a '(__{{.App.Name}}_complete)'
+`
+
+// powerShellCompletionTemplate is the completion script for PowerShell. Only the words before
+// the cursor are passed to the hidden --completion-bash flag, followed by an empty word when a new
+// word is completed.
+const powerShellCompletionTemplate = `Register-ArgumentCompleter -Native -CommandName {{.App.Name}} -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @($commandAst.CommandElements |
+        Where-Object { $_.Extent.StartOffset -lt $cursorPosition } |
+        Select-Object -Skip 1 |
+        ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') {
+        $words += ''
+    }
+
+    & {{.App.Name}} --completion-bash @words |
+        Where-Object { $_ -like "$wordToComplete*" } |
+        ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_) }
+}
+`
+
+// completionTemplates maps the shells to the templates of their completion scripts.
+var completionTemplates = map[string]string{
+	shellBash:       kingpin.BashCompletionTemplate,
+	shellZsh:        kingpin.ZshCompletionTemplate,
+	shellFish:       fishCompletionTemplate,
+	shellPowerShell: powerShellCompletionTemplate,
+}
+
+// CompletionCommand prints the shell completion script for a shell.
+type CompletionCommand struct {
+	io    ui.IO
+	shell string
+}
+
+// NewCompletionCommand creates a new CompletionCommand.
+func NewCompletionCommand(io ui.IO) *CompletionCommand {
+	return &CompletionCommand{
+		io: io,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CompletionCommand) Register(r command.Registerer) {
+	shells := []string{shellBash, shellZsh, shellFish, shellPowerShell}
+
+	clause := r.Command("completion", "Print the script that enables completion of commands, flags and paths in your shell. "+
+		"For example, add `source <(secrethub completion bash)` to your ~/.bashrc, "+
+		"run `secrethub completion fish > ~/.config/fish/completions/secrethub.fish` "+
+		"or add `secrethub completion powershell | Out-String | Invoke-Expression` to your PowerShell profile.")
+	clause.Arg("shell", "The shell to print the completion script for: "+strings.Join(shells, ", ")).Required().EnumVar(&cmd.shell, shells...)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the completion script of the shell.
+func (cmd *CompletionCommand) Run() error {
+	tpl, err := template.New(cmd.shell).Parse(completionTemplates[cmd.shell])
+	if err != nil {
+		return err
+	}
+
+	return tpl.Execute(cmd.io.Output(), map[string]interface{}{
+		"App": map[string]string{
+			"Name": ApplicationName,
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCompletionCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		shell    string
+		contains string
+	}{
+		"bash": {
+			shell:    shellBash,
+			contains: "complete -F _secrethub_bash_autocomplete -o default secrethub",
+		},
+		"zsh": {
+			shell:    shellZsh,
+			contains: "compdef _secrethub secrethub",
+		},
+		"fish": {
+			shell:    shellFish,
+			contains: "secrethub --completion-bash $tokens \"$current\"",
+		},
+		"powershell": {
+			shell:    shellPowerShell,
+			contains: "Register-ArgumentCompleter -Native -CommandName secrethub",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := CompletionCommand{
+				io:    io,
+				shell: tc.shell,
+			}
+
+			err := cmd.Run()
+			assert.OK(t, err)
+
+			assert.Equal(t, strings.Contains(io.Out.String(), tc.contains), true)
+		})
+	}
+}
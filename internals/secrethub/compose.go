@@ -0,0 +1,215 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrInvalidComposeFile = errMain.Code("invalid_compose_file").ErrorPref("invalid compose file %s: %s")
+)
+
+// composeSecretFileMode is the mode of the files the secrets are written to. Compose mounts
+// the files into the containers as they are, so they must be readable for the user a container
+// runs as. The files are protected by the mode of the temporary directory they are written to.
+const composeSecretFileMode = 0444
+
+// ComposeCommand runs Docker Compose with the secrets referenced in a compose file.
+type ComposeCommand struct {
+	io        ui.IO
+	file      string
+	args      []string
+	newClient newClientFunc
+}
+
+// NewComposeCommand creates a new ComposeCommand.
+func NewComposeCommand(io ui.IO, newClient newClientFunc) *ComposeCommand {
+	return &ComposeCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ComposeCommand) Register(r command.Registerer) {
+	clause := r.Command("compose", "Run `docker compose` with the secrets referenced in a compose file, so no plaintext .env files are needed for local development. "+
+		"Every file-based secret in the top-level secrets section of the compose file whose file is a reference, "+
+		"such as `file: secrethub://company/app/dev/db_password`, is written to a temporary directory and the compose file is rewritten to use it. "+
+		"The temporary directory is removed when docker compose exits. "+
+		"Separate the arguments of docker compose with --, e.g. `secrethub compose -- up --build`.")
+	clause.Arg("args", "The arguments to pass to docker compose").Required().StringsVar(&cmd.args)
+	clause.Flag("file", "The compose file that references the secrets.").Short('f').Default("docker-compose.yml").ExistingFileVar(&cmd.file)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run writes the secrets to a temporary directory and runs docker compose with the rewritten compose file.
+func (cmd *ComposeCommand) Run() error {
+	data, err := ioutil.ReadFile(cmd.file)
+	if err != nil {
+		return ErrReadFile(cmd.file, err)
+	}
+
+	projectDir, err := filepath.Abs(filepath.Dir(cmd.file))
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "secrethub-compose-")
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := cmd.run(client, data, dir, projectDir)
+	removeErr := os.RemoveAll(dir)
+	if err != nil {
+		return err
+	}
+	if removeErr != nil {
+		return removeErr
+	}
+
+	if exitCode != 0 {
+		// Return the status code returned by docker compose
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
+// run materializes the secrets in dir and runs docker compose, returning its exit code.
+func (cmd *ComposeCommand) run(client secrethub.ClientInterface, data []byte, dir string, projectDir string) (int, error) {
+	rewritten, err := materializeComposeSecrets(client, cmd.file, data, dir)
+	if err != nil {
+		return 0, err
+	}
+
+	composeFile := filepath.Join(dir, "docker-compose.yml")
+	err = ioutil.WriteFile(composeFile, rewritten, configFileMode)
+	if err != nil {
+		return 0, ErrCannotWrite(composeFile, err)
+	}
+
+	// The project directory makes relative paths in the compose file resolve as they would without the rewrite.
+	args := append([]string{"compose", "--file", composeFile, "--project-directory", projectDir}, cmd.args...)
+	docker := exec.Command("docker", args...)
+	docker.Stdin = os.Stdin
+	docker.Stdout = cmd.io.Stdout()
+	docker.Stderr = os.Stderr
+
+	err = docker.Start()
+	if err != nil {
+		return 0, ErrStartFailed(err)
+	}
+
+	done := make(chan bool, 1)
+
+	// Pass all signals to docker compose, so it can stop the containers before the secrets are removed.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals)
+
+	go func() {
+		for {
+			select {
+			case s := <-signals:
+				err := docker.Process.Signal(s)
+				if err != nil && !strings.Contains(err.Error(), "process already finished") {
+					fmt.Fprintln(os.Stderr, ErrSignalFailed(err))
+				}
+			case <-done:
+				signal.Stop(signals)
+				return
+			}
+		}
+	}()
+
+	err = docker.Wait()
+	done <- true
+
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if ok {
+			waitStatus, ok := exitErr.Sys().(syscall.WaitStatus)
+			if ok {
+				return waitStatus.ExitStatus(), nil
+			}
+		}
+		return 0, err
+	}
+	return 0, nil
+}
+
+// materializeComposeSecrets writes the secrets that are referenced by the file-based secrets of the
+// compose file to dir and returns the compose file with the references replaced by the written files.
+func materializeComposeSecrets(client secrethub.ClientInterface, file string, data []byte, dir string) ([]byte, error) {
+	var compose yaml.MapSlice
+	err := yaml.Unmarshal(data, &compose)
+	if err != nil {
+		return nil, ErrInvalidComposeFile(file, err)
+	}
+
+	written := 0
+	for _, item := range compose {
+		if item.Key != "secrets" {
+			continue
+		}
+
+		secrets, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			return nil, ErrInvalidComposeFile(file, "secrets must be a mapping")
+		}
+
+		for _, secret := range secrets {
+			definition, ok := secret.Value.(yaml.MapSlice)
+			if !ok {
+				// A secret without a definition has no file to rewrite.
+				continue
+			}
+
+			for i, field := range definition {
+				ref, ok := field.Value.(string)
+				if field.Key != "file" || !ok || !strings.HasPrefix(ref, secretReferencePrefix) {
+					continue
+				}
+
+				path := strings.TrimPrefix(ref, secretReferencePrefix)
+				version, err := client.Secrets().Versions().GetWithData(path)
+				if api.IsErrNotFound(err) {
+					return nil, ErrResourceNotFound(path)
+				} else if err != nil {
+					return nil, err
+				}
+
+				// Secrets are numbered instead of named, as a name in the compose file is not always a valid file name.
+				written++
+				secretFile := filepath.Join(dir, fmt.Sprintf("secret-%d", written))
+				err = ioutil.WriteFile(secretFile, version.Data, composeSecretFileMode)
+				if err != nil {
+					return nil, ErrCannotWrite(secretFile, err)
+				}
+				definition[i].Value = secretFile
+			}
+		}
+	}
+
+	return yaml.Marshal(compose)
+}
@@ -0,0 +1,108 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestMaterializeComposeSecrets(t *testing.T) {
+	client := fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					if path == "company/app/missing" {
+						return nil, api.ErrSecretNotFound
+					}
+					return &api.SecretVersion{Data: []byte("value of " + path)}, nil
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		in       string
+		expected string
+		files    map[string]string
+		err      error
+	}{
+		"references": {
+			in: "services:\n" +
+				"  app:\n" +
+				"    image: app\n" +
+				"    secrets: [db_password, api_key, tls_cert]\n" +
+				"secrets:\n" +
+				"  db_password:\n" +
+				"    file: secrethub://company/app/db_password\n" +
+				"  api_key:\n" +
+				"    file: secrethub://company/app/api_key:2\n" +
+				"  tls_cert:\n" +
+				"    file: ./cert.pem\n" +
+				"  external_secret:\n" +
+				"    external: true\n",
+			expected: "services:\n" +
+				"  app:\n" +
+				"    image: app\n" +
+				"    secrets:\n" +
+				"    - db_password\n" +
+				"    - api_key\n" +
+				"    - tls_cert\n" +
+				"secrets:\n" +
+				"  db_password:\n" +
+				"    file: {dir}/secret-1\n" +
+				"  api_key:\n" +
+				"    file: {dir}/secret-2\n" +
+				"  tls_cert:\n" +
+				"    file: ./cert.pem\n" +
+				"  external_secret:\n" +
+				"    external: true\n",
+			files: map[string]string{
+				"secret-1": "value of company/app/db_password",
+				"secret-2": "value of company/app/api_key:2",
+			},
+		},
+		"no secrets": {
+			in:       "services:\n  app:\n    image: app\n",
+			expected: "services:\n  app:\n    image: app\n",
+			files:    map[string]string{},
+		},
+		"missing secret": {
+			in:  "secrets:\n  missing:\n    file: secrethub://company/app/missing\n",
+			err: ErrResourceNotFound("company/app/missing"),
+		},
+		"invalid secrets": {
+			in:  "secrets: [a, b]\n",
+			err: ErrInvalidComposeFile("docker-compose.yml", "secrets must be a mapping"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			actual, err := materializeComposeSecrets(client, "docker-compose.yml", []byte(tc.in), dir)
+
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+			assert.Equal(t, string(actual), strings.Replace(tc.expected, "{dir}", dir, -1))
+
+			files, err := ioutil.ReadDir(dir)
+			assert.OK(t, err)
+			written := map[string]string{}
+			for _, file := range files {
+				data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+				assert.OK(t, err)
+				written[file.Name()] = string(data)
+			}
+			assert.Equal(t, written, tc.files)
+		})
+	}
+}
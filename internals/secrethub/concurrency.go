@@ -0,0 +1,96 @@
+package secrethub
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// defaultConcurrency is the number of secrets that are read at the same time when --concurrency is not set.
+const defaultConcurrency = 8
+
+// concurrency is set with the --concurrency flag to the maximum number of secrets
+// that are read at the same time by operations on a directory tree.
+var concurrency = concurrencyFlag(defaultConcurrency)
+
+// RegisterConcurrencyFlag registers the flag that sets the number of secrets that are read at the same time.
+func RegisterConcurrencyFlag(r FlagRegisterer) {
+	r.Flag("concurrency", "The maximum number of secrets that are fetched and decrypted at the same time "+
+		"when exporting or backing up a directory tree. Defaults to "+strconv.Itoa(defaultConcurrency)+".").
+		PlaceHolder(strconv.Itoa(defaultConcurrency)).SetValue(&concurrency)
+}
+
+// concurrencyFlag is the value of the --concurrency flag, which must be at least 1.
+type concurrencyFlag int
+
+// String implements the flag.Value interface.
+func (f concurrencyFlag) String() string {
+	return strconv.Itoa(int(f))
+}
+
+// Set sets the concurrency.
+func (f *concurrencyFlag) Set(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return fmt.Errorf("the concurrency must be at least 1")
+	}
+	*f = concurrencyFlag(n)
+	return nil
+}
+
+// forEachConcurrently calls fn for the indices 0 to n-1, with at most the given number of workers
+// calling fn at the same time. When a call fails, no new calls are started and the first error is
+// returned once the running calls have finished. Callers write their results to a slice by index,
+// so the order of the results does not depend on the order in which the calls finish.
+func forEachConcurrently(workers int, n int, fn func(i int) error) error {
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	var firstErr error
+	var once sync.Once
+	failed := make(chan struct{})
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				err := fn(i)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						close(failed)
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case <-failed:
+			break feed
+		default:
+		}
+
+		select {
+		case indices <- i:
+		case <-failed:
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,203 @@
+package secrethub
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestForEachConcurrently(t *testing.T) {
+	errTest := errors.New("test")
+
+	cases := map[string]struct {
+		workers int
+		n       int
+		failAt  int
+		err     error
+	}{
+		"no items": {
+			workers: 4,
+			n:       0,
+			failAt:  -1,
+		},
+		"fewer items than workers": {
+			workers: 8,
+			n:       3,
+			failAt:  -1,
+		},
+		"more items than workers": {
+			workers: 4,
+			n:       50,
+			failAt:  -1,
+		},
+		"single worker": {
+			workers: 1,
+			n:       10,
+			failAt:  -1,
+		},
+		"error": {
+			workers: 4,
+			n:       50,
+			failAt:  10,
+			err:     errTest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var mutex sync.Mutex
+			called := make([]bool, tc.n)
+			running := 0
+			maxRunning := 0
+
+			err := forEachConcurrently(tc.workers, tc.n, func(i int) error {
+				mutex.Lock()
+				called[i] = true
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mutex.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+
+				if i == tc.failAt {
+					return errTest
+				}
+				return nil
+			})
+
+			assert.Equal(t, err, tc.err)
+			if maxRunning > tc.workers {
+				t.Errorf("%d calls ran at the same time, expected at most %d", maxRunning, tc.workers)
+			}
+			if tc.err == nil {
+				for i, ok := range called {
+					if !ok {
+						t.Errorf("fn was not called for index %d", i)
+					}
+				}
+			} else if called[tc.n-1] {
+				t.Errorf("fn was called for the last index after an error")
+			}
+		})
+	}
+}
+
+func TestReadDirSecrets_Concurrent(t *testing.T) {
+	// The secrets are read by concurrent workers that share the client, so this test
+	// is also meant to be run with -race to check the collection of their results.
+	defer func(c concurrencyFlag) { concurrency = c }(concurrency)
+	concurrency = 8
+
+	rootDirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace/repo",
+		RootDir:    &api.Dir{DirID: rootDirID, Name: "dir"},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {DirID: rootDirID, Name: "dir"},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{},
+	}
+	n := 50
+	for i := 0; i < n; i++ {
+		secretID := uuid.New()
+		tree.Secrets[secretID] = &api.Secret{SecretID: secretID, DirID: rootDirID, Name: fmt.Sprintf("secret-%02d", i)}
+	}
+
+	var mutex sync.Mutex
+	treeFetched := false
+	running := 0
+	maxRunning := 0
+
+	client := fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				treeFetched = true
+				return tree, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					mutex.Lock()
+					if !treeFetched {
+						t.Errorf("%s was read before the tree was fetched", path)
+					}
+					running++
+					if running > maxRunning {
+						maxRunning = running
+					}
+					mutex.Unlock()
+
+					time.Sleep(time.Millisecond)
+
+					mutex.Lock()
+					running--
+					mutex.Unlock()
+					return &api.SecretVersion{Data: []byte(path)}, nil
+				},
+			},
+		},
+	}
+
+	secrets, err := readDirSecrets(client, api.DirPath("namespace/repo/dir"), false)
+	assert.OK(t, err)
+
+	assert.Equal(t, len(secrets), n)
+	for i, secret := range secrets {
+		name := fmt.Sprintf("secret-%02d", i)
+		assert.Equal(t, secret.Name, name)
+		assert.Equal(t, string(secret.Value), "namespace/repo/dir/"+name)
+	}
+	if maxRunning < 2 || maxRunning > int(concurrency) {
+		t.Errorf("%d secrets were read at the same time, expected between 2 and %d", maxRunning, concurrency)
+	}
+}
+
+func TestConcurrencyFlag_Set(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected concurrencyFlag
+		err      bool
+	}{
+		"valid": {
+			value:    "16",
+			expected: 16,
+		},
+		"zero": {
+			value:    "0",
+			expected: defaultConcurrency,
+			err:      true,
+		},
+		"not a number": {
+			value:    "many",
+			expected: defaultConcurrency,
+			err:      true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			flag := concurrencyFlag(defaultConcurrency)
+
+			err := flag.Set(tc.value)
+
+			assert.Equal(t, err != nil, tc.err)
+			assert.Equal(t, flag, tc.expected)
+		})
+	}
+}
@@ -24,4 +24,5 @@ func (cmd *ConfigCommand) Register(r command.Registerer) {
 	clause := r.Command("config", "Manage your local configuration.")
 	NewConfigUpdatePassphraseCommand(cmd.io, cmd.credentialStore).Register(clause)
 	NewConfigUpgradeCommand().Register(clause)
+	NewConfigThemeCommand(cmd.io, cmd.credentialStore).Register(clause)
 }
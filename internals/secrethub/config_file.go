@@ -0,0 +1,155 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// Errors
+var (
+	ErrInvalidConfigFile = errMain.Code("invalid_config_file").ErrorPref("cannot read the settings in %s: %s")
+)
+
+// configFileMode is the filemode to assign to files the CLI stores in the configuration directory.
+const configFileMode = os.FileMode(0600)
+
+// configFilename is the name of the file in the configuration directory with the settings of the CLI.
+const configFilename = "config.json"
+
+// cliConfig is the content of the configuration file.
+type cliConfig struct {
+	// Aliases maps the names of aliases to the command-line they expand to.
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Pager is the command that long listings are piped through, or off to not use a pager.
+	Pager string `json:"pager,omitempty"`
+	// Timeout is the maximum time an API call may take, e.g. 10s.
+	Timeout string `json:"timeout,omitempty"`
+	// CacheTTL is the time cached directory trees are used, e.g. 1m. Zero disables the cache.
+	CacheTTL string `json:"cache_ttl,omitempty"`
+	// OfflineCache enables the cache of secrets that can be read when the API is unavailable.
+	OfflineCache *offlineCacheConfig `json:"offline_cache,omitempty"`
+	// Retry is the policy for retrying API calls that fail because of a transient error.
+	Retry *retryConfig `json:"retry,omitempty"`
+	// ReadOnly refuses all commands that make changes.
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// configFile returns the configuration file in the configuration directory.
+func configFile(dir configdir.Dir) jsonFile {
+	return jsonFile{path: filepath.Join(dir.Path(), configFilename)}
+}
+
+// loadedConfig is the result of reading a configuration file.
+type loadedConfig struct {
+	config cliConfig
+	err    error
+}
+
+var (
+	configMutex sync.Mutex
+	// loadedConfigs are the configuration files that have been read, by path.
+	loadedConfigs = map[string]loadedConfig{}
+	// configWarnings are the warnings about the configuration that have been printed.
+	configWarnings = map[string]bool{}
+)
+
+// loadConfig returns the content of the configuration file in the configuration directory.
+// The file is read only once, so all settings are loaded from the same content, even when
+// clients are created multiple times during a command.
+func loadConfig(dir configdir.Dir) (cliConfig, error) {
+	if dir.Path() == "" {
+		return cliConfig{}, nil
+	}
+	file := configFile(dir)
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	loaded, ok := loadedConfigs[file.path]
+	if !ok {
+		err := file.read(&loaded.config)
+		if err != nil {
+			loaded = loadedConfig{err: ErrInvalidConfigFile(file.path, err)}
+		}
+		loadedConfigs[file.path] = loaded
+	}
+	return loaded.config, loaded.err
+}
+
+// warnConfig prints a warning about an invalid configuration. Every warning is printed only once,
+// so a configuration file that cannot be read results in a single warning instead of one for every setting.
+func warnConfig(err error) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	if configWarnings[err.Error()] {
+		return
+	}
+	configWarnings[err.Error()] = true
+	fmt.Fprintf(os.Stderr, "%s %s\n", colorize(colorRoleWarning, "Warning:"), err)
+}
+
+// readJSONFile decodes the JSON file at the given path into v.
+// It returns false when the file does not exist.
+func readJSONFile(path string, v interface{}) (bool, error) {
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	err = json.Unmarshal(raw, v)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeJSONFile encodes v as JSON and writes it to the given path with the given file mode,
+// creating the parent directory when it does not exist yet.
+func writeJSONFile(path string, v interface{}, mode os.FileMode) error {
+	raw, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), defaultProfileDirFileMode)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, raw, mode)
+}
+
+// jsonFile is a file in the configuration directory that holds a JSON encoded value.
+type jsonFile struct {
+	path string
+}
+
+// read decodes the file into v. When the file does not exist, v is left untouched.
+func (f jsonFile) read(v interface{}) error {
+	_, err := readJSONFile(f.path, v)
+	return err
+}
+
+// write encodes v and writes it to the file.
+func (f jsonFile) write(v interface{}) error {
+	return writeJSONFile(f.path, v, configFileMode)
+}
+
+// update decodes the file into v, calls fn to modify v and writes the result back to the file.
+func (f jsonFile) update(v interface{}, fn func()) error {
+	err := f.read(v)
+	if err != nil {
+		return err
+	}
+	fn()
+	return f.write(v)
+}
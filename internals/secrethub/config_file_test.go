@@ -0,0 +1,34 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+	configDir := configdir.New(dir)
+	file := configFile(configDir)
+	assert.OK(t, ioutil.WriteFile(file.path, []byte("{"), configFileMode))
+
+	_, err := loadConfig(configDir)
+	expected := ErrInvalidConfigFile(file.path, "unexpected end of JSON input")
+	assert.Equal(t, err, expected)
+
+	// Every setting reports the same error, so it results in a single warning.
+	_, err = loadRetryPolicy(configDir)
+	assert.Equal(t, err, expected)
+	_, err = loadTimeout(configDir)
+	assert.Equal(t, err, expected)
+	_, err = loadReadOnly(configDir)
+	assert.Equal(t, err, expected)
+
+	// The file is only read once.
+	assert.OK(t, file.write(cliConfig{ReadOnly: true}))
+	_, err = loadReadOnly(configDir)
+	assert.Equal(t, err, expected)
+}
@@ -50,7 +50,7 @@ func (cmd *ConfigUpdatePassphraseCommand) Run() error {
 
 	if !confirmed {
 		fmt.Fprintln(cmd.io.Output(), "Aborting.")
-		return nil
+		return ErrAborted
 	}
 
 	credential, err := cmd.credentialStore.Import()
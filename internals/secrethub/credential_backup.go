@@ -51,7 +51,7 @@ func (cmd *CredentialBackupCommand) Run() error {
 	}
 	if !ok {
 		fmt.Fprintln(cmd.io.Output(), "Aborting")
-		return nil
+		return ErrAborted
 	}
 
 	backupCode := credentials.CreateBackupCode()
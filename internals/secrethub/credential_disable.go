@@ -71,7 +71,7 @@ func (cmd *CredentialDisableCommand) Run() error {
 		}
 		if !ok {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 	}
 
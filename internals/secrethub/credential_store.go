@@ -1,6 +1,7 @@
 package secrethub
 
 import (
+	"io"
 	"time"
 
 	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
@@ -18,6 +19,7 @@ var (
 type CredentialConfig interface {
 	IsPassphraseSet() bool
 	Provider() credentials.Provider
+	NonInteractiveProvider() credentials.Provider
 	Import() (credentials.Key, error)
 	ConfigDir() configdir.Dir
 	PassphraseReader() credentials.Reader
@@ -64,6 +66,14 @@ func (store *credentialConfig) Provider() credentials.Provider {
 	return credentials.UseKey(store.getCredentialReader()).Passphrase(store.PassphraseReader())
 }
 
+// NonInteractiveProvider retrieves a credential from the store like Provider does,
+// but never prompts for the passphrase of the credential. A passphrase protected
+// credential can only be used when the passphrase is set with a flag or cached.
+func (store *credentialConfig) NonInteractiveProvider() credentials.Provider {
+	passphraseReader := NewPassphraseReader(nonInteractiveIO{IO: store.io}, store.credentialPassphrase, store.CredentialPassphraseCacheTTL)
+	return credentials.UseKey(store.getCredentialReader()).Passphrase(passphraseReader)
+}
+
 func (store *credentialConfig) Import() (credentials.Key, error) {
 	return credentials.ImportKey(store.getCredentialReader(), store.PassphraseReader())
 }
@@ -79,3 +89,18 @@ func (store *credentialConfig) getCredentialReader() credentials.Reader {
 func (store *credentialConfig) PassphraseReader() credentials.Reader {
 	return NewPassphraseReader(store.io, store.credentialPassphrase, store.CredentialPassphraseCacheTTL)
 }
+
+// nonInteractiveIO is an ui.IO that cannot prompt the user for input.
+type nonInteractiveIO struct {
+	ui.IO
+}
+
+// Prompts always returns ui.ErrCannotAsk.
+func (nonInteractiveIO) Prompts() (io.Reader, io.Writer, error) {
+	return nil, nil, ui.ErrCannotAsk
+}
+
+// ReadSecret always returns ui.ErrCannotAsk.
+func (nonInteractiveIO) ReadSecret() ([]byte, error) {
+	return nil, ui.ErrCannotAsk
+}
@@ -0,0 +1,24 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// DevCommand handles tools for developing and testing with SecretHub.
+type DevCommand struct {
+	io ui.IO
+}
+
+// NewDevCommand creates a new DevCommand.
+func NewDevCommand(io ui.IO) *DevCommand {
+	return &DevCommand{
+		io: io,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *DevCommand) Register(r command.Registerer) {
+	clause := r.Command("dev", "Tools for developing and testing with SecretHub without touching real accounts.")
+	NewDevServerCommand(cmd.io).Register(clause)
+}
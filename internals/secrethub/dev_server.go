@@ -0,0 +1,183 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/devserver"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// Errors
+var (
+	ErrInvalidFixtures = errMain.Code("invalid_fixtures").ErrorPref("invalid fixtures file %s: %s")
+	ErrCannotSeed      = errMain.Code("cannot_seed").ErrorPref("cannot write fixture %s: %s")
+)
+
+// DevServerCommand runs an in-memory implementation of the SecretHub API with seeded fixtures.
+type DevServerCommand struct {
+	io             ui.IO
+	address        string
+	username       string
+	fixturesFile   string
+	credentialFile string
+}
+
+// NewDevServerCommand creates a new DevServerCommand.
+func NewDevServerCommand(io ui.IO) *DevServerCommand {
+	return &DevServerCommand{
+		io: io,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *DevServerCommand) Register(r command.Registerer) {
+	clause := r.Command("server", "Run an in-memory implementation of the SecretHub API, so integration tests and demos work offline "+
+		"without touching real accounts. The server has a single user account, of which a new credential is generated every time the server starts. "+
+		"Point the CLI at the server with the --remote flag or the SECRETHUB_REMOTE environment variable and use the printed credential. "+
+		"All data is kept in memory and is gone when the server stops. Audit logs, access rules, organizations and services are not available.")
+	clause.Flag("address", "The address to listen on.").Default("127.0.0.1:8080").StringVar(&cmd.address)
+	clause.Flag("username", "The username of the account on the server.").Default("dev").StringVar(&cmd.username)
+	clause.Flag("fixtures", "A JSON file with an object of secret paths and their values, which are written when the server starts. "+
+		"The repositories and directories of the secrets are created and must be in the namespace of the username. "+
+		"Defaults to a demo repository with a few secrets.").ExistingFileVar(&cmd.fixturesFile)
+	clause.Flag("credential-file", "Also write the credential of the account to this file, so it can be used with the --credential flag of other commands.").StringVar(&cmd.credentialFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run starts the server, seeds the fixtures and serves until the command is interrupted.
+func (cmd *DevServerCommand) Run() error {
+	fixtures, err := cmd.fixtures()
+	if err != nil {
+		return err
+	}
+
+	server, err := devserver.New(cmd.username)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", cmd.address)
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{Handler: server}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	remote := "http://" + listener.Addr().String()
+	client, err := secrethub.NewClient(
+		secrethub.WithServerURL(remote),
+		secrethub.WithCredentials(credentials.UseKey(credentials.FromString(server.Credential()))),
+	)
+	if err != nil {
+		return err
+	}
+	err = seedFixtures(client, fixtures)
+	if err != nil {
+		return err
+	}
+
+	if cmd.credentialFile != "" {
+		err = ioutil.WriteFile(cmd.credentialFile, []byte(server.Credential()), 0600)
+		if err != nil {
+			return err
+		}
+	}
+
+	w := cmd.io.Output()
+	fmt.Fprintf(statusWriter(w), "Serving the SecretHub API for user %s at %s with %d secrets. Press Ctrl+C to stop.\n", cmd.username, remote, len(fixtures))
+	fmt.Fprintln(statusWriter(w), "Point the CLI at the server with:")
+	fmt.Fprintf(w, "export SECRETHUB_REMOTE=%s\n", remote)
+	fmt.Fprintf(w, "export SECRETHUB_CREDENTIAL=%s\n", server.Credential())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-signals:
+		return httpServer.Shutdown(context.Background())
+	}
+}
+
+// fixtures returns the secrets in the fixtures file or the default fixtures when no file is given.
+func (cmd *DevServerCommand) fixtures() (map[string]string, error) {
+	if cmd.fixturesFile == "" {
+		return map[string]string{
+			cmd.username + "/demo/api-key":           "demo-api-key",
+			cmd.username + "/demo/database/username": "demo",
+			cmd.username + "/demo/database/password": "correct horse battery staple",
+		}, nil
+	}
+
+	data, err := ioutil.ReadFile(cmd.fixturesFile)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures map[string]string
+	err = json.Unmarshal(data, &fixtures)
+	if err != nil {
+		return nil, ErrInvalidFixtures(cmd.fixturesFile, err)
+	}
+	return fixtures, nil
+}
+
+// seedFixtures writes the secrets and creates their repositories and directories.
+func seedFixtures(client secrethub.ClientInterface, fixtures map[string]string) error {
+	paths := make([]string, 0, len(fixtures))
+	for path := range fixtures {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	created := map[string]bool{}
+	for _, path := range paths {
+		secretPath, err := api.NewSecretPath(path)
+		if err != nil {
+			return ErrCannotSeed(path, err)
+		}
+
+		elements := strings.Split(secretPath.Value(), "/")
+		for i := 2; i < len(elements); i++ {
+			dir := strings.Join(elements[:i], "/")
+			if created[dir] {
+				continue
+			}
+			if i == 2 {
+				_, err = client.Repos().Create(dir)
+			} else {
+				_, err = client.Dirs().Create(dir)
+			}
+			if err != nil {
+				return ErrCannotSeed(path, err)
+			}
+			created[dir] = true
+		}
+
+		_, err = client.Secrets().Write(secretPath.Value(), []byte(fixtures[path]))
+		if err != nil {
+			return ErrCannotSeed(path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestSeedFixtures(t *testing.T) {
+	var calls []string
+	client := fakeclient.Client{
+		RepoService: &fakeclient.RepoService{
+			CreateFunc: func(path string) (*api.Repo, error) {
+				calls = append(calls, "repo "+path)
+				return &api.Repo{}, nil
+			},
+		},
+		DirService: &fakeclient.DirService{
+			CreateFunc: func(path string) (*api.Dir, error) {
+				calls = append(calls, "dir "+path)
+				return &api.Dir{}, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				calls = append(calls, "secret "+path+"="+string(data))
+				return &api.SecretVersion{}, nil
+			},
+		},
+	}
+
+	err := seedFixtures(client, map[string]string{
+		"dev/demo/database/password": "password",
+		"dev/demo/database/username": "username",
+		"dev/demo/api-key":           "key",
+		"dev/other/a/b/secret":       "value",
+	})
+	assert.OK(t, err)
+	assert.Equal(t, calls, []string{
+		"repo dev/demo",
+		"secret dev/demo/api-key=key",
+		"dir dev/demo/database",
+		"secret dev/demo/database/password=password",
+		"secret dev/demo/database/username=username",
+		"repo dev/other",
+		"dir dev/other/a",
+		"dir dev/other/a/b",
+		"secret dev/other/a/b/secret=value",
+	})
+
+	err = seedFixtures(client, map[string]string{"dev/repo": "value"})
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,972 @@
+// Package devserver provides an in-memory implementation of the SecretHub API for
+// development and testing, so the CLI can be used without touching real accounts.
+//
+// The server stores what clients send as they send it, so names, keys and secrets are
+// encrypted end-to-end like they are with the real API. It serves a single user account,
+// of which the credential is generated when the server is created, and keeps all data in
+// memory, so everything is gone when the server stops unless its state is saved with
+// Snapshot. Routes that are not needed to manage repositories, directories and secrets
+// are not implemented.
+package devserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/auth"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// basePath is the path under which the routes of the API are served.
+const basePath = "/v1/"
+
+// Server is an in-memory SecretHub API. It is safe for concurrent use.
+type Server struct {
+	mutex sync.Mutex
+	now   func() time.Time
+
+	account       *api.Account
+	user          *api.User
+	credential    *credentials.RSACredential
+	apiCredential *api.Credential
+	accountKey    *api.EncryptedAccountKey
+	exported      string
+
+	repos   map[string]*repo
+	dirs    map[string]*dir
+	secrets map[string]*secret
+}
+
+// repo is a repository with its keys, which are encrypted for the account of the server.
+type repo struct {
+	repo *api.Repo
+	keys *api.RepoKeys
+	root *dir
+}
+
+// dir is a directory with its contents.
+type dir struct {
+	repo    *repo
+	parent  *dir
+	dir     *api.EncryptedDir
+	dirs    []*dir
+	secrets []*secret
+}
+
+// secret is a secret with its keys and versions.
+type secret struct {
+	dir      *dir
+	secret   *api.EncryptedSecret
+	keys     []*api.EncryptedSecretKey
+	versions []*api.EncryptedSecretVersion
+}
+
+// New creates a server with a user account with the given username and generates
+// the credential and the account key of the account.
+func New(username string) (*Server, error) {
+	return newServer(username, crypto.RSAKeyLength)
+}
+
+// newServer creates a server of which the credential and account key have the given length in bits.
+func newServer(username string, keyLength int) (*Server, error) {
+	err := api.ValidateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := credentials.GenerateRSACredential(keyLength)
+	if err != nil {
+		return nil, err
+	}
+	verifier, fingerprint, err := credential.Export()
+	if err != nil {
+		return nil, err
+	}
+	exported, err := credentials.EncodeCredential(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	accountKey, err := crypto.GenerateRSAPrivateKey(keyLength)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := accountKey.Public().Encode()
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := accountKey.ExportPEM()
+	if err != nil {
+		return nil, err
+	}
+	encryptedPrivateKey, err := credential.Wrap(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	account := &api.Account{
+		AccountID:   uuid.New(),
+		Name:        api.AccountName(username),
+		PublicKey:   publicKey,
+		AccountType: "user",
+		CreatedAt:   now,
+	}
+	apiCredential := &api.Credential{
+		AccountID:   account.AccountID,
+		Type:        credential.Type(),
+		CreatedAt:   now,
+		Fingerprint: fingerprint,
+		Description: "Development server",
+		Verifier:    verifier,
+		Enabled:     true,
+	}
+
+	return &Server{
+		now:     time.Now,
+		account: account,
+		user: &api.User{
+			AccountID: account.AccountID,
+			PublicKey: publicKey,
+			Username:  username,
+			FullName:  username,
+			CreatedAt: &now,
+		},
+		credential:    credential,
+		apiCredential: apiCredential,
+		accountKey: &api.EncryptedAccountKey{
+			Account:             account,
+			PublicKey:           publicKey,
+			EncryptedPrivateKey: encryptedPrivateKey,
+			Credential:          apiCredential,
+		},
+		exported: string(exported),
+		repos:    map[string]*repo{},
+		dirs:     map[string]*dir{},
+		secrets:  map[string]*secret{},
+	}, nil
+}
+
+// Credential returns the exported credential of the account of the server, which can
+// be passed to clients, e.g. with the SECRETHUB_CREDENTIAL environment variable.
+func (s *Server) Credential() string {
+	return s.exported
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	out, status, err := s.serve(r)
+	if err != nil {
+		statusErr := toStatusError(err)
+		out, status = statusErr, statusErr.StatusCode
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// serve authenticates the request and passes it to the handler of its route.
+func (s *Server) serve(r *http.Request) (interface{}, int, error) {
+	if !strings.HasPrefix(r.URL.Path, basePath) {
+		return nil, 0, api.ErrNotFound
+	}
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, basePath), "/")
+
+	for _, route := range s.routes() {
+		params, ok := route.match(r.Method, segments)
+		if !ok {
+			continue
+		}
+
+		err := s.authenticate(r)
+		if err != nil {
+			return nil, 0, err
+		}
+		return route.handle(r, params)
+	}
+	return nil, 0, api.ErrUnknownMethod
+}
+
+// handlerFunc handles a request with the parameters of its path. It returns the
+// response and its status code.
+type handlerFunc func(r *http.Request, params []string) (interface{}, int, error)
+
+// route is a method and a path of which the segments that are * are parameters.
+type route struct {
+	method string
+	path   string
+	handle handlerFunc
+}
+
+// match returns the parameters of the path segments when the route matches.
+func (rt route) match(method string, segments []string) ([]string, bool) {
+	if method != rt.method {
+		return nil, false
+	}
+	pattern := strings.Split(rt.path, "/")
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+
+	var params []string
+	for i, segment := range pattern {
+		switch segment {
+		case "*":
+			params = append(params, segments[i])
+		case segments[i]:
+		default:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *Server) routes() []route {
+	return []route{
+		{"GET", "me/user", s.getMyUser},
+		{"GET", "me/key", s.getMyAccountKey},
+		{"GET", "me/repos", s.listMyRepos},
+		{"GET", "me/credentials", s.listMyCredentials},
+		{"GET", "account/*", s.getAccount},
+		{"GET", "users/*", s.getUser},
+		{"GET", "namespaces/*/repos", s.listRepos},
+		{"POST", "namespaces/*/repos", s.createRepo},
+		{"GET", "namespaces/*/repos/*", s.getRepo},
+		{"DELETE", "namespaces/*/repos/*", s.deleteRepo},
+		{"GET", "namespaces/*/repos/*/keys", s.getRepoKeys},
+		{"GET", "namespaces/*/repos/*/accounts", s.listRepoAccounts},
+		{"GET", "namespaces/*/repos/*/users", s.listRepoUsers},
+		{"GET", "namespaces/*/repos/*/events", s.listRepoEvents},
+		{"POST", "namespaces/*/repos/*/dirs", s.createDir},
+		{"POST", "namespaces/*/repos/*/dirs/*/secrets", s.createSecret},
+		{"GET", "dirs/*", s.getDir},
+		{"DELETE", "dirs/*", s.deleteDir},
+		{"GET", "dirs/*/accounts", s.listDirAccounts},
+		{"GET", "dirs/*/rules", s.listDirRules},
+		{"GET", "secrets/*", s.getSecret},
+		{"DELETE", "secrets/*", s.deleteSecret},
+		{"GET", "secrets/*/events", s.listSecretEvents},
+		{"GET", "secrets/*/versions", s.listSecretVersions},
+		{"POST", "secrets/*/versions", s.createSecretVersion},
+		{"GET", "secrets/*/versions/*", s.getSecretVersion},
+		{"DELETE", "secrets/*/versions/*", s.deleteSecretVersion},
+		{"GET", "secrets/*/key", s.getCurrentSecretKey},
+		{"GET", "secrets/*/keys", s.listSecretKeys},
+		{"POST", "secrets/*/keys", s.createSecretKey},
+	}
+}
+
+// authenticate verifies that the request is signed by the credential of the server.
+func (s *Server) authenticate(r *http.Request) error {
+	header := r.Header.Get("Authorization")
+	prefix := auth.AuthHeaderVersionV1 + "-" + s.credential.SignMethod() + " "
+	if !strings.HasPrefix(header, prefix) {
+		return api.ErrRequestNotAuthenticated
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(header, prefix), ":", 2)
+	if len(parts) != 2 || parts[0] != s.apiCredential.Fingerprint {
+		return api.ErrSignatureNotVerified
+	}
+	signature, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return auth.ErrInvalidAuthorizationHeader
+	}
+
+	message, err := signedMessage(r)
+	if err != nil {
+		return err
+	}
+	err = s.credential.Public().Verify(message, signature)
+	if err != nil {
+		return api.ErrSignatureNotVerified
+	}
+	return nil
+}
+
+// signedMessage returns the message that clients sign to authenticate a request, which
+// consists of the method, the hash of the body, the date and the path of the request.
+func signedMessage(r *http.Request) ([]byte, error) {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "%s\n", r.Method)
+	if r.ContentLength == 0 {
+		message.WriteString("\n")
+	} else {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(body)
+		fmt.Fprintf(&message, "%s\n", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+
+	date, err := time.Parse(time.RFC1123, r.Header.Get("Date"))
+	if err != nil {
+		return nil, auth.ErrCannotParseDateHeader
+	}
+	fmt.Fprintf(&message, "%s\n%s;", date, r.URL.Path)
+	return message.Bytes(), nil
+}
+
+// toStatusError returns the error as it is returned to clients.
+func toStatusError(err error) errio.PublicStatusError {
+	var statusErr errio.PublicStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr
+	}
+	var publicErr errio.PublicError
+	if errors.As(err, &publicErr) {
+		return errio.PublicStatusError{PublicError: publicErr, StatusCode: http.StatusBadRequest}
+	}
+	return errio.UnexpectedStatusError(err)
+}
+
+// decode decodes the JSON body of the request and validates it.
+func decode(r *http.Request, in interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		return api.ErrBadRequest
+	}
+
+	validator, ok := in.(interface{ Validate() error })
+	if ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+func (s *Server) getMyUser(r *http.Request, params []string) (interface{}, int, error) {
+	return s.user, http.StatusOK, nil
+}
+
+func (s *Server) getMyAccountKey(r *http.Request, params []string) (interface{}, int, error) {
+	return s.accountKey, http.StatusOK, nil
+}
+
+func (s *Server) listMyRepos(r *http.Request, params []string) (interface{}, int, error) {
+	return s.listRepos(r, []string{s.user.Username})
+}
+
+func (s *Server) listMyCredentials(r *http.Request, params []string) (interface{}, int, error) {
+	return []*api.Credential{s.apiCredential}, http.StatusOK, nil
+}
+
+func (s *Server) getAccount(r *http.Request, params []string) (interface{}, int, error) {
+	if !strings.EqualFold(params[0], s.user.Username) {
+		return nil, 0, api.ErrAccountNotFound
+	}
+	return s.account, http.StatusOK, nil
+}
+
+func (s *Server) getUser(r *http.Request, params []string) (interface{}, int, error) {
+	if !strings.EqualFold(params[0], s.user.Username) {
+		return nil, 0, api.ErrUserNotFound
+	}
+	return s.user, http.StatusOK, nil
+}
+
+func (s *Server) listRepos(r *http.Request, params []string) (interface{}, int, error) {
+	if !strings.EqualFold(params[0], s.user.Username) {
+		return nil, 0, api.ErrNamespaceNotFound
+	}
+
+	repos := make([]*api.Repo, 0, len(s.repos))
+	for _, repo := range s.repos {
+		repos = append(repos, repo.repo)
+	}
+	sort.Sort(api.SortRepoByName(repos))
+	return repos, http.StatusOK, nil
+}
+
+func (s *Server) createRepo(r *http.Request, params []string) (interface{}, int, error) {
+	if !strings.EqualFold(params[0], s.user.Username) {
+		return nil, 0, api.ErrNamespaceNotFound
+	}
+
+	var in api.CreateRepoRequest
+	err := decode(r, &in)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	key := repoKey(params[0], in.Name)
+	if _, exists := s.repos[key]; exists {
+		return nil, 0, api.ErrRepoAlreadyExists
+	}
+	if s.exists(in.RootDir.BlindName) {
+		return nil, 0, api.ErrDirAlreadyExists
+	}
+	name, err := s.nameFor(in.RootDir.EncryptedNames)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	repo := &repo{
+		repo: &api.Repo{
+			RepoID:         uuid.New(),
+			Owner:          s.user.Username,
+			Name:           in.Name,
+			CreatedAt:      now,
+			LastModifiedAt: now,
+			Status:         api.StatusOK,
+			MemberCount:    1,
+		},
+		keys: &api.RepoKeys{
+			RepoEncryptionKey: in.RepoMember.RepoEncryptionKey,
+			RepoIndexKey:      in.RepoMember.RepoIndexKey,
+		},
+	}
+	repo.root = &dir{
+		repo: repo,
+		dir: &api.EncryptedDir{
+			DirID:          uuid.New(),
+			BlindName:      in.RootDir.BlindName,
+			EncryptedName:  name,
+			Status:         api.StatusOK,
+			CreatedAt:      now,
+			LastModifiedAt: now,
+		},
+	}
+	s.repos[key] = repo
+	s.dirs[in.RootDir.BlindName] = repo.root
+	return repo.repo, http.StatusCreated, nil
+}
+
+func (s *Server) getRepo(r *http.Request, params []string) (interface{}, int, error) {
+	repo, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+	return repo.repo, http.StatusOK, nil
+}
+
+func (s *Server) deleteRepo(r *http.Request, params []string) (interface{}, int, error) {
+	repo, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.removeDir(repo.root)
+	delete(s.repos, repoKey(params[0], params[1]))
+	return nil, http.StatusOK, nil
+}
+
+func (s *Server) getRepoKeys(r *http.Request, params []string) (interface{}, int, error) {
+	repo, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+	return repo.keys, http.StatusOK, nil
+}
+
+func (s *Server) listRepoAccounts(r *http.Request, params []string) (interface{}, int, error) {
+	_, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+	return []*api.Account{s.account}, http.StatusOK, nil
+}
+
+func (s *Server) listRepoUsers(r *http.Request, params []string) (interface{}, int, error) {
+	_, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+	return []*api.User{s.user}, http.StatusOK, nil
+}
+
+// listRepoEvents returns no events, as the server keeps no audit log.
+func (s *Server) listRepoEvents(r *http.Request, params []string) (interface{}, int, error) {
+	_, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+	return []*api.Audit{}, http.StatusOK, nil
+}
+
+func (s *Server) createDir(r *http.Request, params []string) (interface{}, int, error) {
+	repo, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var in api.CreateDirRequest
+	err = decode(r, &in)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parent, ok := s.dirs[in.ParentBlindName]
+	if !ok || parent.repo != repo {
+		return nil, 0, api.ErrParentDirNotFound
+	}
+	if s.exists(in.BlindName) {
+		return nil, 0, api.ErrDirAlreadyExists
+	}
+	name, err := s.nameFor(in.EncryptedNames)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	parentID := parent.dir.DirID
+	dir := &dir{
+		repo:   repo,
+		parent: parent,
+		dir: &api.EncryptedDir{
+			DirID:          uuid.New(),
+			BlindName:      in.BlindName,
+			EncryptedName:  name,
+			ParentID:       &parentID,
+			Status:         api.StatusOK,
+			CreatedAt:      now,
+			LastModifiedAt: now,
+		},
+	}
+	parent.dirs = append(parent.dirs, dir)
+	parent.touch(now)
+	s.dirs[in.BlindName] = dir
+	return dir.dir, http.StatusCreated, nil
+}
+
+// getDir returns the tree of the directory when a depth is given and the directory itself otherwise,
+// which can also be requested by its ID.
+func (s *Server) getDir(r *http.Request, params []string) (interface{}, int, error) {
+	query := r.URL.Query()
+	if query.Get("depth") == "" {
+		dir, ok := s.dirs[params[0]]
+		if !ok {
+			dir, ok = s.dirByID(params[0])
+		}
+		if !ok {
+			return nil, 0, api.ErrDirNotFound
+		}
+		return dir.dir, http.StatusOK, nil
+	}
+
+	dir, ok := s.dirs[params[0]]
+	if !ok {
+		return nil, 0, api.ErrDirNotFound
+	}
+	depth, err := strconv.Atoi(query.Get("depth"))
+	if err != nil {
+		return nil, 0, api.ErrBadRequest
+	}
+	ancestors := query.Get("ancestors") == "true"
+
+	tree := &api.EncryptedTree{
+		Directories: map[uuid.UUID]*api.EncryptedDir{},
+		Secrets:     []*api.EncryptedSecret{},
+	}
+	root := *dir.dir
+	if ancestors {
+		for parent := dir.parent; parent != nil; parent = parent.parent {
+			tree.Directories[parent.dir.DirID] = parent.dir
+		}
+	} else {
+		root.ParentID = nil
+	}
+	tree.Directories[root.DirID] = &root
+	addContents(tree, dir, 0, depth)
+	return tree, http.StatusOK, nil
+}
+
+// addContents adds the contents of the directory at the level to the tree, up to the depth.
+// A depth of 0 or less adds all contents.
+func addContents(tree *api.EncryptedTree, dir *dir, level int, depth int) {
+	if depth > 0 && level >= depth {
+		return
+	}
+	for _, secret := range dir.secrets {
+		tree.Secrets = append(tree.Secrets, secret.secret)
+	}
+	for _, sub := range dir.dirs {
+		tree.Directories[sub.dir.DirID] = sub.dir
+		addContents(tree, sub, level+1, depth)
+	}
+}
+
+func (s *Server) deleteDir(r *http.Request, params []string) (interface{}, int, error) {
+	dir, ok := s.dirs[params[0]]
+	if !ok {
+		return nil, 0, api.ErrDirNotFound
+	}
+	if dir.parent == nil {
+		return nil, 0, api.ErrCannotRemoveRootDir
+	}
+
+	for i, sub := range dir.parent.dirs {
+		if sub == dir {
+			dir.parent.dirs = append(dir.parent.dirs[:i], dir.parent.dirs[i+1:]...)
+			break
+		}
+	}
+	dir.parent.touch(s.now().UTC())
+	s.removeDir(dir)
+	return nil, http.StatusOK, nil
+}
+
+func (s *Server) listDirAccounts(r *http.Request, params []string) (interface{}, int, error) {
+	if _, ok := s.dirs[params[0]]; !ok {
+		return nil, 0, api.ErrDirNotFound
+	}
+	return []*api.Account{s.account}, http.StatusOK, nil
+}
+
+// listDirRules returns no access rules, as the server has a single account.
+func (s *Server) listDirRules(r *http.Request, params []string) (interface{}, int, error) {
+	if _, ok := s.dirs[params[0]]; !ok {
+		return nil, 0, api.ErrDirNotFound
+	}
+	return []*api.AccessRule{}, http.StatusOK, nil
+}
+
+func (s *Server) createSecret(r *http.Request, params []string) (interface{}, int, error) {
+	repo, err := s.repo(params[0], params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+	parent, ok := s.dirs[params[2]]
+	if !ok || parent.repo != repo {
+		return nil, 0, api.ErrParentDirNotFound
+	}
+
+	var in api.CreateSecretRequest
+	err = decode(r, &in)
+	if err != nil {
+		return nil, 0, err
+	}
+	if s.exists(in.BlindName) {
+		return nil, 0, api.ErrSecretAlreadyExists
+	}
+	name, err := s.nameFor(in.EncryptedNames)
+	if err != nil {
+		return nil, 0, err
+	}
+	key, err := s.keyFor(in.EncryptedKeys)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	secret := &secret{
+		dir: parent,
+		secret: &api.EncryptedSecret{
+			SecretID:      uuid.New(),
+			DirID:         parent.dir.DirID,
+			RepoID:        repo.repo.RepoID,
+			EncryptedName: name,
+			BlindName:     in.BlindName,
+			Status:        api.StatusOK,
+			CreatedAt:     now,
+		},
+		keys: []*api.EncryptedSecretKey{key},
+	}
+	version := secret.addVersion(key, in.EncryptedData, now)
+
+	parent.secrets = append(parent.secrets, secret)
+	parent.touch(now)
+	repo.repo.SecretCount++
+	s.secrets[in.BlindName] = secret
+	return version, http.StatusCreated, nil
+}
+
+func (s *Server) getSecret(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+	return secret.secret, http.StatusOK, nil
+}
+
+func (s *Server) deleteSecret(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+	s.removeSecret(secret)
+	return nil, http.StatusOK, nil
+}
+
+// listSecretEvents returns no events, as the server keeps no audit log.
+func (s *Server) listSecretEvents(r *http.Request, params []string) (interface{}, int, error) {
+	if _, ok := s.secrets[params[0]]; !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+	return []*api.Audit{}, http.StatusOK, nil
+}
+
+func (s *Server) listSecretVersions(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+
+	withData := r.URL.Query().Get("encrypted_blob") == "true"
+	versions := make([]*api.EncryptedSecretVersion, len(secret.versions))
+	for i, version := range secret.versions {
+		versions[i] = withoutData(version, withData)
+	}
+	return versions, http.StatusOK, nil
+}
+
+func (s *Server) createSecretVersion(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+
+	var in api.CreateSecretVersionRequest
+	err := decode(r, &in)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var key *api.EncryptedSecretKey
+	for _, k := range secret.keys {
+		if uuid.Equal(k.SecretKeyID, in.SecretKeyID) {
+			key = k
+		}
+	}
+	if key == nil {
+		return nil, 0, api.ErrSecretKeyNotFound
+	}
+
+	now := s.now().UTC()
+	version := secret.addVersion(key, in.EncryptedData, now)
+	secret.dir.touch(now)
+	return version, http.StatusCreated, nil
+}
+
+func (s *Server) getSecretVersion(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+	i, err := secret.version(params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	withData := r.URL.Query().Get("encrypted_blob") == "true"
+	return withoutData(secret.versions[i], withData), http.StatusOK, nil
+}
+
+// deleteSecretVersion removes a version of a secret. The secret is removed with its last version.
+func (s *Server) deleteSecretVersion(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+	i, err := secret.version(params[1])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	secret.versions = append(secret.versions[:i], secret.versions[i+1:]...)
+	if len(secret.versions) == 0 {
+		s.removeSecret(secret)
+		return nil, http.StatusOK, nil
+	}
+	secret.secret.VersionCount = len(secret.versions)
+	secret.secret.LatestVersion = secret.versions[len(secret.versions)-1].Version
+	secret.dir.touch(s.now().UTC())
+	return nil, http.StatusOK, nil
+}
+
+// getCurrentSecretKey returns the latest key of the secret. Keys are never flagged, as
+// the server has a single account.
+func (s *Server) getCurrentSecretKey(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+	return secret.keys[len(secret.keys)-1], http.StatusOK, nil
+}
+
+func (s *Server) listSecretKeys(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+	return secret.keys, http.StatusOK, nil
+}
+
+func (s *Server) createSecretKey(r *http.Request, params []string) (interface{}, int, error) {
+	secret, ok := s.secrets[params[0]]
+	if !ok {
+		return nil, 0, api.ErrSecretNotFound
+	}
+
+	var in api.CreateSecretKeyRequest
+	err := decode(r, &in)
+	if err != nil {
+		return nil, 0, err
+	}
+	key, err := s.keyFor(in.EncryptedFor)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	secret.keys = append(secret.keys, key)
+	return key, http.StatusCreated, nil
+}
+
+// repo returns the repository in the namespace with the name.
+func (s *Server) repo(namespace string, name string) (*repo, error) {
+	repo, ok := s.repos[repoKey(namespace, name)]
+	if !ok {
+		return nil, api.ErrRepoNotFound(namespace + "/" + name)
+	}
+	return repo, nil
+}
+
+// repoKey returns the key of a repository in the map of repositories, as paths are case insensitive.
+func repoKey(namespace string, name string) string {
+	return strings.ToLower(namespace + "/" + name)
+}
+
+// dirByID returns the directory with the ID.
+func (s *Server) dirByID(id string) (*dir, bool) {
+	dirID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, false
+	}
+	for _, dir := range s.dirs {
+		if uuid.Equal(dir.dir.DirID, dirID) {
+			return dir, true
+		}
+	}
+	return nil, false
+}
+
+// exists returns whether a directory or secret has the blind name, i.e. exists at the same path.
+func (s *Server) exists(blindName string) bool {
+	_, isDir := s.dirs[blindName]
+	_, isSecret := s.secrets[blindName]
+	return isDir || isSecret
+}
+
+// removeDir removes the directory and its contents from the maps of the server.
+func (s *Server) removeDir(dir *dir) {
+	for _, sub := range dir.dirs {
+		s.removeDir(sub)
+	}
+	for _, secret := range dir.secrets {
+		delete(s.secrets, secret.secret.BlindName)
+		dir.repo.repo.SecretCount--
+	}
+	delete(s.dirs, dir.dir.BlindName)
+}
+
+// removeSecret removes the secret from its directory and the map of the server.
+func (s *Server) removeSecret(secret *secret) {
+	dir := secret.dir
+	for i, sibling := range dir.secrets {
+		if sibling == secret {
+			dir.secrets = append(dir.secrets[:i], dir.secrets[i+1:]...)
+			break
+		}
+	}
+	dir.touch(s.now().UTC())
+	dir.repo.repo.SecretCount--
+	delete(s.secrets, secret.secret.BlindName)
+}
+
+// nameFor returns the name that is encrypted for the account of the server.
+func (s *Server) nameFor(names []api.EncryptedNameRequest) (crypto.CiphertextRSA, error) {
+	for _, name := range names {
+		if uuid.Equal(name.AccountID, s.account.AccountID) {
+			return name.EncryptedName, nil
+		}
+	}
+	return crypto.CiphertextRSA{}, api.ErrNotEncryptedForAccounts
+}
+
+// keyFor returns a new secret key of the key that is encrypted for the account of the server.
+func (s *Server) keyFor(keys []api.EncryptedKeyRequest) (*api.EncryptedSecretKey, error) {
+	for _, key := range keys {
+		if uuid.Equal(key.AccountID, s.account.AccountID) {
+			return &api.EncryptedSecretKey{
+				SecretKeyID:  uuid.New(),
+				AccountID:    key.AccountID,
+				EncryptedKey: key.EncryptedKey,
+			}, nil
+		}
+	}
+	return nil, api.ErrNotEncryptedForAccounts
+}
+
+// touch updates the modification time of the directory and its repository.
+func (d *dir) touch(now time.Time) {
+	d.dir.LastModifiedAt = now
+	d.repo.repo.LastModifiedAt = now
+}
+
+// addVersion adds a version with the data that is encrypted with the key.
+func (s *secret) addVersion(key *api.EncryptedSecretKey, data crypto.CiphertextAES, now time.Time) *api.EncryptedSecretVersion {
+	s.secret.LatestVersion++
+	s.secret.VersionCount++
+	version := &api.EncryptedSecretVersion{
+		SecretVersionID: uuid.New(),
+		Secret:          s.secret,
+		Version:         s.secret.LatestVersion,
+		SecretKey:       key,
+		EncryptedData:   &data,
+		CreatedAt:       now,
+		Status:          api.StatusOK,
+	}
+	s.versions = append(s.versions, version)
+	return version
+}
+
+// version returns the index of the version, which is a number or latest.
+func (s *secret) version(param string) (int, error) {
+	if param == "latest" {
+		return len(s.versions) - 1, nil
+	}
+
+	number, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, api.ErrSecretVersionNotFound
+	}
+	for i, version := range s.versions {
+		if version.Version == number {
+			return i, nil
+		}
+	}
+	return 0, api.ErrSecretVersionNotFound
+}
+
+// withoutData returns the version without its key and encrypted data, unless withData is true.
+func withoutData(version *api.EncryptedSecretVersion, withData bool) *api.EncryptedSecretVersion {
+	if withData {
+		return version
+	}
+	result := *version
+	result.SecretKey = nil
+	result.EncryptedData = nil
+	return &result
+}
@@ -0,0 +1,139 @@
+package devserver
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// newTestClient starts a server with small keys and returns a client that uses the given credential
+// to connect to it. An empty credential is replaced by the credential of the server.
+func newTestClient(t *testing.T, credential string) (*Server, secrethub.ClientInterface) {
+	server, err := newServer("dev", 1024)
+	assert.OK(t, err)
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	if credential == "" {
+		credential = server.Credential()
+	}
+	client, err := secrethub.NewClient(
+		secrethub.WithServerURL(httpServer.URL),
+		secrethub.WithCredentials(credentials.UseKey(credentials.FromString(credential))),
+	)
+	assert.OK(t, err)
+	return server, client
+}
+
+func TestServer(t *testing.T) {
+	_, client := newTestClient(t, "")
+
+	_, err := client.Repos().Create("dev/repo")
+	assert.OK(t, err)
+	_, err = client.Repos().Create("dev/repo")
+	assert.Equal(t, err, api.ErrRepoAlreadyExists)
+	_, err = client.Repos().Create("other/repo")
+	assert.Equal(t, err, api.ErrNamespaceNotFound)
+
+	_, err = client.Dirs().Create("dev/repo/dir")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev/repo/dir/secret", []byte("first"))
+	assert.OK(t, err)
+	version, err := client.Secrets().Write("dev/repo/dir/secret", []byte("second"))
+	assert.OK(t, err)
+	assert.Equal(t, version.Version, 2)
+	_, err = client.Secrets().Write("dev/repo/dir", []byte("value"))
+	assert.Equal(t, err, api.ErrSecretAlreadyExists)
+
+	value, err := client.Secrets().ReadString("dev/repo/dir/secret")
+	assert.OK(t, err)
+	assert.Equal(t, value, "second")
+	value, err = client.Secrets().ReadString("dev/repo/dir/secret:1")
+	assert.OK(t, err)
+	assert.Equal(t, value, "first")
+
+	versions, err := client.Secrets().Versions().ListWithoutData("dev/repo/dir/secret")
+	assert.OK(t, err)
+	assert.Equal(t, len(versions), 2)
+
+	tree, err := client.Dirs().GetTree("dev/repo", -1, false)
+	assert.OK(t, err)
+	assert.Equal(t, tree.RootDir.Name, "repo")
+	assert.Equal(t, len(tree.RootDir.SubDirs), 1)
+	assert.Equal(t, tree.RootDir.SubDirs[0].Name, "dir")
+	assert.Equal(t, tree.RootDir.SubDirs[0].Secrets[0].Name, "secret")
+
+	repo, err := client.Repos().Get("dev/repo")
+	assert.OK(t, err)
+	assert.Equal(t, repo.SecretCount, 1)
+
+	err = client.Secrets().Delete("dev/repo/dir/secret")
+	assert.OK(t, err)
+	exists, err := client.Secrets().Exists("dev/repo/dir/secret")
+	assert.OK(t, err)
+	assert.Equal(t, exists, false)
+
+	err = client.Dirs().Delete("dev/repo/dir")
+	assert.OK(t, err)
+	err = client.Dirs().Delete("dev/repo")
+	assert.Equal(t, err, api.ErrCannotRemoveRootDir)
+
+	err = client.Repos().Delete("dev/repo")
+	assert.OK(t, err)
+	repos, err := client.Repos().List("dev")
+	assert.OK(t, err)
+	assert.Equal(t, len(repos), 0)
+}
+
+func TestServer_otherCredential(t *testing.T) {
+	other, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+	exported, err := credentials.EncodeCredential(other)
+	assert.OK(t, err)
+
+	_, client := newTestClient(t, string(exported))
+
+	_, err = client.Repos().List("dev")
+	assert.Equal(t, err, api.ErrSignatureNotVerified)
+}
+
+func TestServer_Restore(t *testing.T) {
+	server, client := newTestClient(t, "")
+
+	_, err := client.Repos().Create("dev/repo")
+	assert.OK(t, err)
+	_, err = client.Dirs().Create("dev/repo/dir")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev/repo/dir/secret", []byte("first"))
+	assert.OK(t, err)
+
+	snapshot, err := server.Snapshot()
+	assert.OK(t, err)
+	restored, err := Restore(snapshot)
+	assert.OK(t, err)
+	assert.Equal(t, restored.Credential(), server.Credential())
+
+	httpServer := httptest.NewServer(restored)
+	t.Cleanup(httpServer.Close)
+	client, err = secrethub.NewClient(
+		secrethub.WithServerURL(httpServer.URL),
+		secrethub.WithCredentials(credentials.UseKey(credentials.FromString(restored.Credential()))),
+	)
+	assert.OK(t, err)
+
+	value, err := client.Secrets().ReadString("dev/repo/dir/secret")
+	assert.OK(t, err)
+	assert.Equal(t, value, "first")
+
+	version, err := client.Secrets().Write("dev/repo/dir/secret", []byte("second"))
+	assert.OK(t, err)
+	assert.Equal(t, version.Version, 2)
+
+	_, err = Restore([]byte("{}"))
+	assert.Equal(t, err, ErrInvalidSnapshot)
+}
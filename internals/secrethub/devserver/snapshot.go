@@ -0,0 +1,182 @@
+package devserver
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// ErrInvalidSnapshot is returned when a snapshot cannot be restored.
+var ErrInvalidSnapshot = errors.New("invalid snapshot of a development server")
+
+// snapshot is the state of a server, which contains the contents of the repositories
+// as a tree instead of the maps that index them.
+type snapshot struct {
+	PrivateKey    []byte                   `json:"private_key"`
+	Account       *api.Account             `json:"account"`
+	User          *api.User                `json:"user"`
+	APICredential *api.Credential          `json:"credential"`
+	AccountKey    *api.EncryptedAccountKey `json:"account_key"`
+	Repos         []snapshotRepo           `json:"repos"`
+}
+
+type snapshotRepo struct {
+	Repo *api.Repo     `json:"repo"`
+	Keys *api.RepoKeys `json:"keys"`
+	Root snapshotDir   `json:"root"`
+}
+
+type snapshotDir struct {
+	Dir     *api.EncryptedDir `json:"dir"`
+	Dirs    []snapshotDir     `json:"dirs"`
+	Secrets []snapshotSecret  `json:"secrets"`
+}
+
+type snapshotSecret struct {
+	Secret   *api.EncryptedSecret          `json:"secret"`
+	Keys     []*api.EncryptedSecretKey     `json:"keys"`
+	Versions []*api.EncryptedSecretVersion `json:"versions"`
+}
+
+// Snapshot returns the state of the server, from which an identical server can be created with Restore.
+// Names, keys and secrets are encrypted end-to-end, but the snapshot contains the unencrypted credential
+// of the account of the server, so it should only be stored encrypted.
+func (s *Server) Snapshot() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	privateKey, err := s.credential.RSAPrivateKey.ExportPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	out := snapshot{
+		PrivateKey:    privateKey,
+		Account:       s.account,
+		User:          s.user,
+		APICredential: s.apiCredential,
+		AccountKey:    s.accountKey,
+		Repos:         make([]snapshotRepo, 0, len(s.repos)),
+	}
+	for _, repo := range s.repos {
+		out.Repos = append(out.Repos, snapshotRepo{
+			Repo: repo.repo,
+			Keys: repo.keys,
+			Root: snapshotOf(repo.root),
+		})
+	}
+	return json.Marshal(out)
+}
+
+// snapshotOf returns the snapshot of the directory and its contents.
+func snapshotOf(d *dir) snapshotDir {
+	out := snapshotDir{
+		Dir:     d.dir,
+		Dirs:    make([]snapshotDir, len(d.dirs)),
+		Secrets: make([]snapshotSecret, len(d.secrets)),
+	}
+	for i, sub := range d.dirs {
+		out.Dirs[i] = snapshotOf(sub)
+	}
+	for i, secret := range d.secrets {
+		out.Secrets[i] = snapshotSecret{
+			Secret:   secret.secret,
+			Keys:     secret.keys,
+			Versions: secret.versions,
+		}
+	}
+	return out
+}
+
+// Restore creates a server from a snapshot that is created with Snapshot.
+func Restore(data []byte) (*Server, error) {
+	var in snapshot
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return nil, ErrInvalidSnapshot
+	}
+	if in.Account == nil || in.User == nil || in.APICredential == nil || in.AccountKey == nil {
+		return nil, ErrInvalidSnapshot
+	}
+
+	privateKey, err := crypto.ImportRSAPrivateKeyPEM(in.PrivateKey)
+	if err != nil {
+		return nil, ErrInvalidSnapshot
+	}
+	credential := &credentials.RSACredential{RSAPrivateKey: privateKey}
+	exported, err := credentials.EncodeCredential(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		now:           time.Now,
+		account:       in.Account,
+		user:          in.User,
+		credential:    credential,
+		apiCredential: in.APICredential,
+		accountKey:    in.AccountKey,
+		exported:      string(exported),
+		repos:         map[string]*repo{},
+		dirs:          map[string]*dir{},
+		secrets:       map[string]*secret{},
+	}
+	for _, r := range in.Repos {
+		if r.Repo == nil || r.Root.Dir == nil {
+			return nil, ErrInvalidSnapshot
+		}
+		repo := &repo{
+			repo: r.Repo,
+			keys: r.Keys,
+		}
+		repo.root, err = s.restoreDir(repo, nil, r.Root)
+		if err != nil {
+			return nil, err
+		}
+		s.repos[repoKey(repo.repo.Owner, repo.repo.Name)] = repo
+	}
+	return s, nil
+}
+
+// restoreDir restores the directory of the snapshot and its contents and adds them to the maps of the server.
+func (s *Server) restoreDir(repo *repo, parent *dir, in snapshotDir) (*dir, error) {
+	if in.Dir == nil {
+		return nil, ErrInvalidSnapshot
+	}
+
+	d := &dir{
+		repo:   repo,
+		parent: parent,
+		dir:    in.Dir,
+	}
+	for _, sub := range in.Dirs {
+		restored, err := s.restoreDir(repo, d, sub)
+		if err != nil {
+			return nil, err
+		}
+		d.dirs = append(d.dirs, restored)
+	}
+	for _, sec := range in.Secrets {
+		if sec.Secret == nil || len(sec.Keys) == 0 || len(sec.Versions) == 0 {
+			return nil, ErrInvalidSnapshot
+		}
+		restored := &secret{
+			dir:      d,
+			secret:   sec.Secret,
+			keys:     sec.Keys,
+			versions: sec.Versions,
+		}
+		// The versions share the secret, so its latest version is updated when a version is added.
+		for _, version := range restored.versions {
+			version.Secret = restored.secret
+		}
+		d.secrets = append(d.secrets, restored)
+		s.secrets[sec.Secret.BlindName] = restored
+	}
+	s.dirs[in.Dir.BlindName] = d
+	return d, nil
+}
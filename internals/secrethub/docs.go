@@ -0,0 +1,379 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/alecthomas/kingpin"
+)
+
+// The formats in which the reference documentation can be generated.
+const (
+	docsFormatMan      = "man"
+	docsFormatMarkdown = "markdown"
+)
+
+// docsFileMode is the filemode of the generated documentation files.
+const docsFileMode = os.FileMode(0644)
+
+// DocsCommand handles the documentation of the command-line interface.
+type DocsCommand struct {
+	io  ui.IO
+	app *cli.App
+}
+
+// NewDocsCommand creates a new DocsCommand.
+func NewDocsCommand(io ui.IO, app *cli.App) *DocsCommand {
+	return &DocsCommand{
+		io:  io,
+		app: app,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *DocsCommand) Register(r command.Registerer) {
+	clause := r.Command("docs", "Manage the reference documentation of the command-line interface.")
+	NewDocsGenerateCommand(cmd.io, cmd.app).Register(clause)
+}
+
+// DocsGenerateCommand generates the reference documentation from the registered commands.
+type DocsGenerateCommand struct {
+	io        ui.IO
+	app       *cli.App
+	format    string
+	outputDir string
+}
+
+// NewDocsGenerateCommand creates a new DocsGenerateCommand.
+func NewDocsGenerateCommand(io ui.IO, app *cli.App) *DocsGenerateCommand {
+	return &DocsGenerateCommand{
+		io:  io,
+		app: app,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *DocsGenerateCommand) Register(r command.Registerer) {
+	clause := r.Command("generate", "Generate a man page or markdown reference page for every command. "+
+		"The pages are named after the command, e.g. secrethub-acl-set.1 or secrethub-acl-set.md, and are written to the output directory.")
+	clause.Flag("format", "The format of the pages: man or markdown.").Default(docsFormatMarkdown).EnumVar(&cmd.format, docsFormatMan, docsFormatMarkdown)
+	clause.Flag("output-dir", "The directory to write the pages to. It is created when it does not exist yet.").Default(".").StringVar(&cmd.outputDir)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run writes the pages of all visible commands to the output directory.
+func (cmd *DocsGenerateCommand) Run() error {
+	err := os.MkdirAll(cmd.outputDir, os.FileMode(0755))
+	if err != nil {
+		return ErrCannotWrite(cmd.outputDir, err)
+	}
+
+	pages := docsPages(cmd.app.Model())
+	for _, page := range pages {
+		var extension string
+		var write func(io.Writer, docsPage) error
+		switch cmd.format {
+		case docsFormatMan:
+			extension = ".1"
+			write = writeManPage
+		default:
+			extension = ".md"
+			write = writeMarkdownPage
+		}
+
+		var buf strings.Builder
+		err = write(&buf, page)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(cmd.outputDir, page.name+extension)
+		err = ioutil.WriteFile(path, []byte(buf.String()), docsFileMode)
+		if err != nil {
+			return ErrCannotWrite(path, err)
+		}
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Generated %d pages in %s.\n", len(pages), cmd.outputDir)
+	return nil
+}
+
+// docsPage is the reference documentation of a single command.
+type docsPage struct {
+	// name is the name of the page, e.g. secrethub-acl-set.
+	name string
+	// command is the full command, e.g. secrethub acl set.
+	command     string
+	help        string
+	helpLong    string
+	usage       string
+	args        []*kingpin.ArgModel
+	flags       []*kingpin.FlagModel
+	subcommands []docsPage
+	// parent is the page of the parent command, which is nil for the page of the application.
+	parent *docsPageRef
+	// root is the page of the application, which is nil for the page of the application.
+	root *docsPageRef
+}
+
+// docsPageRef refers to another page.
+type docsPageRef struct {
+	name    string
+	command string
+}
+
+// docsPages returns the pages of the application and all of its visible commands.
+// The first page documents the application itself and its global flags.
+func docsPages(model *kingpin.ApplicationModel) []docsPage {
+	root := docsPage{
+		name:    model.Name,
+		command: model.Name,
+		help:    model.Help,
+		usage:   model.Name + " [<flags>] <command> [<args> ...]",
+		flags:   visibleFlags(model.FlagGroupModel),
+	}
+
+	ref := &docsPageRef{name: root.name, command: root.command}
+	root.subcommands = commandPages(ref, ref, model.CmdGroupModel)
+
+	pages := []docsPage{root}
+	return appendSubcommandPages(pages, root.subcommands)
+}
+
+// commandPages returns the pages of the visible commands in the group of the parent command.
+func commandPages(parent, root *docsPageRef, group *kingpin.CmdGroupModel) []docsPage {
+	var pages []docsPage
+	for _, cmd := range group.Commands {
+		if cmd.Hidden {
+			continue
+		}
+
+		page := docsPage{
+			name:     parent.name + "-" + cmd.Name,
+			command:  parent.command + " " + cmd.Name,
+			help:     cmd.Help,
+			helpLong: cmd.HelpLong,
+			args:     visibleArgs(cmd.ArgGroupModel),
+			flags:    visibleFlags(cmd.FlagGroupModel),
+			parent:   parent,
+			root:     root,
+		}
+		ref := &docsPageRef{name: page.name, command: page.command}
+		page.subcommands = commandPages(ref, root, cmd.CmdGroupModel)
+		page.usage = commandUsage(page)
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// appendSubcommandPages appends the pages of the commands and all of their descendants to pages.
+func appendSubcommandPages(pages []docsPage, commands []docsPage) []docsPage {
+	for _, page := range commands {
+		pages = append(pages, page)
+		pages = appendSubcommandPages(pages, page.subcommands)
+	}
+	return pages
+}
+
+// commandUsage returns the usage line of the command on the page.
+func commandUsage(page docsPage) string {
+	usage := []string{page.command}
+	if len(page.flags) > 0 {
+		usage = append(usage, "[<flags>]")
+	}
+	if len(page.subcommands) > 0 {
+		usage = append(usage, "<command>")
+	}
+
+	optional := 0
+	for _, arg := range page.args {
+		placeHolder := arg.PlaceHolder
+		if placeHolder == "" {
+			placeHolder = "<" + arg.Name + ">"
+		}
+		if !arg.Required {
+			placeHolder = "[" + placeHolder
+			optional++
+		}
+		usage = append(usage, placeHolder)
+	}
+	return strings.Join(usage, " ") + strings.Repeat("]", optional)
+}
+
+// visibleArgs returns the arguments that are not hidden.
+func visibleArgs(group *kingpin.ArgGroupModel) []*kingpin.ArgModel {
+	var args []*kingpin.ArgModel
+	for _, arg := range group.Args {
+		if !arg.Hidden {
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// visibleFlags returns the flags that are not hidden.
+func visibleFlags(group *kingpin.FlagGroupModel) []*kingpin.FlagModel {
+	var flags []*kingpin.FlagModel
+	for _, flag := range group.Flags {
+		if !flag.Hidden {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// flagSynopsis returns how the flag is used, e.g. --output-dir=OUTPUT-DIR, -o.
+func flagSynopsis(flag *kingpin.FlagModel) string {
+	synopsis := "--" + flag.Name
+	if !flag.IsBoolFlag() {
+		synopsis += "=" + flag.FormatPlaceHolder()
+	}
+	if flag.Short != 0 {
+		synopsis += fmt.Sprintf(", -%c", flag.Short)
+	}
+	return synopsis
+}
+
+// writeManPage writes the page in the roff format of man pages to w.
+func writeManPage(w io.Writer, page docsPage) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, ".TH \"%s\" \"1\" \"\" \"%s %s\" \"SecretHub Manual\"\n", strings.ToUpper(page.name), ApplicationName, Version)
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", escapeRoff(page.name), escapeRoff(firstSentence(page.help)))
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n\\fB%s\\fR\n", escapeRoff(page.usage))
+	fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", escapeRoff(page.help))
+	if page.helpLong != "" {
+		fmt.Fprintf(&b, ".PP\n%s\n", escapeRoff(page.helpLong))
+	}
+
+	if len(page.args) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, arg := range page.args {
+			fmt.Fprintf(&b, ".TP\n\\fB<%s>\\fR\n%s\n", escapeRoff(arg.Name), escapeRoff(arg.Help))
+		}
+	}
+
+	if len(page.flags) > 0 {
+		if page.parent == nil {
+			b.WriteString(".SH GLOBAL OPTIONS\n")
+		} else {
+			b.WriteString(".SH OPTIONS\n")
+		}
+		for _, flag := range page.flags {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", escapeRoff(flagSynopsis(flag)), escapeRoff(flag.Help))
+			if flag.Envar != "" {
+				fmt.Fprintf(&b, "Environment variable: \\fB%s\\fR\n", escapeRoff(flag.Envar))
+			}
+		}
+	}
+
+	if len(page.subcommands) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, cmd := range page.subcommands {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR(1)\n%s\n", escapeRoff(cmd.name), escapeRoff(firstSentence(cmd.help)))
+		}
+	}
+
+	if page.parent != nil {
+		b.WriteString(".SH SEE ALSO\n")
+		fmt.Fprintf(&b, "\\fB%s\\fR(1)", escapeRoff(page.parent.name))
+		if page.parent != page.root {
+			fmt.Fprintf(&b, ", \\fB%s\\fR(1)", escapeRoff(page.root.name))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeRoff escapes the text so that it is printed literally in a man page.
+// Empty lines are turned into paragraph breaks.
+func escapeRoff(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			line = ".PP"
+		} else if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			line = `\&` + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeMarkdownPage writes the page in markdown to w. Commands link to the pages
+// of their subcommands and parent, which are expected in the same directory.
+func writeMarkdownPage(w io.Writer, page docsPage) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", page.command)
+	fmt.Fprintf(&b, "%s\n\n", page.help)
+	if page.helpLong != "" {
+		fmt.Fprintf(&b, "%s\n\n", page.helpLong)
+	}
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n", page.usage)
+
+	if len(page.args) > 0 {
+		b.WriteString("\n## Arguments\n\n")
+		for _, arg := range page.args {
+			required := ""
+			if arg.Required {
+				required = " (required)"
+			}
+			fmt.Fprintf(&b, "- `<%s>`%s: %s\n", arg.Name, required, arg.Help)
+		}
+	}
+
+	if len(page.flags) > 0 {
+		if page.parent == nil {
+			b.WriteString("\n## Global flags\n\n")
+		} else {
+			b.WriteString("\n## Flags\n\n")
+		}
+		for _, flag := range page.flags {
+			fmt.Fprintf(&b, "- `%s`: %s", flagSynopsis(flag), flag.Help)
+			if flag.Envar != "" {
+				fmt.Fprintf(&b, " Environment variable: `%s`.", flag.Envar)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(page.subcommands) > 0 {
+		b.WriteString("\n## Commands\n\n")
+		for _, cmd := range page.subcommands {
+			fmt.Fprintf(&b, "- [%s](%s.md): %s\n", cmd.command, cmd.name, firstSentence(cmd.help))
+		}
+	}
+
+	if page.parent != nil {
+		fmt.Fprintf(&b, "\n## See also\n\n- [%s](%s.md)\n", page.parent.command, page.parent.name)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// firstSentence returns the first sentence or line of the text, which summarizes the help text of a command.
+func firstSentence(text string) string {
+	if i := strings.Index(text, "\n"); i >= 0 {
+		text = text[:i]
+	}
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,162 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"github.com/alecthomas/kingpin"
+)
+
+func testDocsModel() *kingpin.ApplicationModel {
+	force := quietFlag(false)
+	debug := quietFlag(false)
+
+	return &kingpin.ApplicationModel{
+		Name: "secrethub",
+		Help: "The SecretHub command-line interface.",
+		FlagGroupModel: &kingpin.FlagGroupModel{
+			Flags: []*kingpin.FlagModel{
+				{Name: "debug", Help: "Enable debug mode.", Envar: "SECRETHUB_DEBUG", Value: &debug},
+				{Name: "completion-bash", Hidden: true},
+			},
+		},
+		ArgGroupModel: &kingpin.ArgGroupModel{},
+		CmdGroupModel: &kingpin.CmdGroupModel{
+			Commands: []*kingpin.CmdModel{
+				{
+					Name:           "acl",
+					Help:           "Manage access rules.",
+					FlagGroupModel: &kingpin.FlagGroupModel{},
+					ArgGroupModel:  &kingpin.ArgGroupModel{},
+					CmdGroupModel: &kingpin.CmdGroupModel{
+						Commands: []*kingpin.CmdModel{
+							{
+								Name: "set",
+								Help: "Set an access rule. Existing rules are overwritten.",
+								FlagGroupModel: &kingpin.FlagGroupModel{
+									Flags: []*kingpin.FlagModel{
+										{Name: "force", Short: 'f', Help: "Do not prompt for confirmation.", Value: &force},
+									},
+								},
+								ArgGroupModel: &kingpin.ArgGroupModel{
+									Args: []*kingpin.ArgModel{
+										{Name: "dir-path", Help: "The path of the directory.", Required: true},
+										{Name: "permission", Help: "The permission to set."},
+									},
+								},
+								CmdGroupModel: &kingpin.CmdGroupModel{},
+							},
+						},
+					},
+				},
+				{
+					Name:           "clear",
+					Help:           "Clear the secrets.",
+					Hidden:         true,
+					FlagGroupModel: &kingpin.FlagGroupModel{},
+					ArgGroupModel:  &kingpin.ArgGroupModel{},
+					CmdGroupModel:  &kingpin.CmdGroupModel{},
+				},
+			},
+		},
+	}
+}
+
+func TestDocsPages(t *testing.T) {
+	pages := docsPages(testDocsModel())
+
+	var names []string
+	for _, page := range pages {
+		names = append(names, page.name)
+	}
+	assert.Equal(t, names, []string{"secrethub", "secrethub-acl", "secrethub-acl-set"})
+	assert.Equal(t, pages[2].usage, "secrethub acl set [<flags>] <dir-path> [<permission>]")
+}
+
+func TestWriteMarkdownPage(t *testing.T) {
+	pages := docsPages(testDocsModel())
+
+	cases := map[string]struct {
+		page     docsPage
+		expected string
+	}{
+		"application": {
+			page: pages[0],
+			expected: "# secrethub\n\n" +
+				"The SecretHub command-line interface.\n\n" +
+				"## Usage\n\n```\nsecrethub [<flags>] <command> [<args> ...]\n```\n\n" +
+				"## Global flags\n\n" +
+				"- `--debug`: Enable debug mode. Environment variable: `SECRETHUB_DEBUG`.\n\n" +
+				"## Commands\n\n" +
+				"- [secrethub acl](secrethub-acl.md): Manage access rules.\n",
+		},
+		"command": {
+			page: pages[2],
+			expected: "# secrethub acl set\n\n" +
+				"Set an access rule. Existing rules are overwritten.\n\n" +
+				"## Usage\n\n```\nsecrethub acl set [<flags>] <dir-path> [<permission>]\n```\n\n" +
+				"## Arguments\n\n" +
+				"- `<dir-path>` (required): The path of the directory.\n" +
+				"- `<permission>`: The permission to set.\n\n" +
+				"## Flags\n\n" +
+				"- `--force, -f`: Do not prompt for confirmation.\n\n" +
+				"## See also\n\n" +
+				"- [secrethub acl](secrethub-acl.md)\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+
+			err := writeMarkdownPage(&b, tc.page)
+
+			assert.OK(t, err)
+			assert.Equal(t, b.String(), tc.expected)
+		})
+	}
+}
+
+func TestWriteManPage(t *testing.T) {
+	pages := docsPages(testDocsModel())
+
+	var b strings.Builder
+	err := writeManPage(&b, pages[2])
+	assert.OK(t, err)
+
+	assert.Equal(t, strings.Contains(b.String(), ".SH NAME\nsecrethub\\-acl\\-set \\- Set an access rule.\n"), true)
+	assert.Equal(t, strings.Contains(b.String(), ".TP\n\\fB\\-\\-force, \\-f\\fR\nDo not prompt for confirmation.\n"), true)
+	assert.Equal(t, strings.Contains(b.String(), ".SH SEE ALSO\n\\fBsecrethub\\-acl\\fR(1), \\fBsecrethub\\fR(1)\n"), true)
+}
+
+func TestEscapeRoff(t *testing.T) {
+	cases := map[string]struct {
+		text     string
+		expected string
+	}{
+		"dashes": {
+			text:     "--force",
+			expected: `\-\-force`,
+		},
+		"backslash": {
+			text:     `C:\secrets`,
+			expected: `C:\esecrets`,
+		},
+		"leading dot": {
+			text:     "first line\n.secrethub",
+			expected: "first line\n\\&.secrethub",
+		},
+		"paragraphs": {
+			text:     "first\n\nsecond",
+			expected: "first\n.PP\nsecond",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, escapeRoff(tc.text), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package secrethub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+const (
+	doctorOK      = "ok"
+	doctorWarning = "warning"
+	doctorFailed  = "failed"
+
+	// defaultAPIRemote is the address of the API when it is not set with --api-remote or --remote.
+	defaultAPIRemote = "https://api.secrethub.io"
+	// maxClockSkew is the largest difference between the local clock and the clock of the API that is not reported.
+	maxClockSkew = time.Minute
+	// redacted replaces secret material in the diagnostics report.
+	redacted = "[REDACTED]"
+	// minRedactedLength is the minimum length of the values of environment variables that are redacted in the whole report.
+	minRedactedLength = 8
+)
+
+// Errors
+var (
+	ErrDoctorChecksFailed = errMain.Code("doctor_checks_failed").ErrorPref("%d of the checks failed")
+)
+
+// doctorColorRoles are the roles in the color theme of the statuses of checks.
+var doctorColorRoles = map[string]string{
+	doctorOK:      colorRoleAdded,
+	doctorWarning: colorRoleWarning,
+	doctorFailed:  colorRoleRemoved,
+}
+
+// doctorCheck is the result of a check of the environment of the CLI.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// DoctorCommand checks the platform, configuration, connectivity, clock, authentication and permissions
+// of the CLI and optionally bundles the results in a diagnostics report without secret material.
+type DoctorCommand struct {
+	io              ui.IO
+	newClient       newClientFunc
+	credentialStore CredentialConfig
+	apiRemote       func() *url.URL
+	paths           []string
+	bundle          string
+	httpClient      *http.Client
+	now             func() time.Time
+}
+
+// NewDoctorCommand creates a new DoctorCommand.
+func NewDoctorCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig, apiRemote func() *url.URL) *DoctorCommand {
+	return &DoctorCommand{
+		io:              io,
+		newClient:       newClient,
+		credentialStore: credentialStore,
+		apiRemote:       apiRemote,
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		now:             time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *DoctorCommand) Register(r command.Registerer) {
+	clause := r.Command("doctor", "Check the platform, configuration, connection to the API, clock, authentication and read access "+
+		"to the given paths, to find out why the CLI does not work as expected. Fails when one of the checks fails.")
+	clause.Arg("paths", "Secrets or directories of which to check that they can be read.").PlaceHolder(optionalDirPathPlaceHolder + "[/<secret>]").StringsVar(&cmd.paths)
+	clause.Flag("bundle", "Write a zip archive with the results of the checks, the platform, the SECRETHUB_ and proxy environment variables "+
+		"and the configuration file, which can be shared with support. The values of environment variables, aliases and all other secret "+
+		"material are redacted, and the credential and secrets are never included.").PlaceHolder("report.zip").StringVar(&cmd.bundle)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run runs the checks, prints the results and writes the diagnostics report.
+func (cmd *DoctorCommand) Run() error {
+	checks := []doctorCheck{
+		cmd.checkPlatform(),
+		cmd.checkConfig(),
+	}
+	checks = append(checks, cmd.checkConnectivity()...)
+
+	authCheck, client := cmd.checkAuthentication()
+	checks = append(checks, authCheck)
+	for _, path := range cmd.paths {
+		checks = append(checks, checkReadAccess(client, path))
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\n", "CHECK", "STATUS", "RESULT")
+	failed := 0
+	for _, check := range checks {
+		// Only the last column is colored, as the escape codes would break the alignment of the other columns.
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, check.Status, colorize(doctorColorRoles[check.Status], check.Message))
+		if check.Status == doctorFailed {
+			failed++
+		}
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if cmd.bundle != "" {
+		err = cmd.writeBundle(checks)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(statusWriter(cmd.io.Output()), "Wrote the diagnostics report to %s.\n", cmd.bundle)
+	}
+
+	if failed > 0 {
+		return ErrDoctorChecksFailed(failed)
+	}
+	return nil
+}
+
+// checkPlatform reports the platform and the version of the CLI.
+func (cmd *DoctorCommand) checkPlatform() doctorCheck {
+	version := Version
+	if version == "" {
+		version = "development build"
+	}
+	return doctorCheck{
+		Name:    "platform",
+		Status:  doctorOK,
+		Message: fmt.Sprintf("%s %s/%s, %s", ApplicationName, runtime.GOOS, runtime.GOARCH, version),
+	}
+}
+
+// checkConfig checks the configuration file and that the credential file cannot be read by other users.
+func (cmd *DoctorCommand) checkConfig() doctorCheck {
+	check := doctorCheck{Name: "configuration", Status: doctorOK}
+	dir := cmd.credentialStore.ConfigDir()
+
+	var config cliConfig
+	err := configFile(dir).read(&config)
+	if err != nil {
+		check.Status = doctorFailed
+		check.Message = fmt.Sprintf("cannot read %s: %s", configFile(dir).path, err)
+		return check
+	}
+
+	credentialFile := dir.Credential()
+	info, err := os.Stat(credentialFile.Path())
+	switch {
+	case os.IsNotExist(err):
+		check.Status = doctorWarning
+		check.Message = fmt.Sprintf("no credential file at %s, so a credential must be set with --credential or SECRETHUB_CREDENTIAL", credentialFile.Path())
+	case err != nil:
+		check.Status = doctorFailed
+		check.Message = err.Error()
+	case runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0:
+		check.Status = doctorWarning
+		check.Message = fmt.Sprintf("the credential file %s can be read by other users (mode %04o)", credentialFile.Path(), info.Mode().Perm())
+	default:
+		check.Message = "credential file found in " + dir.Path()
+	}
+	return check
+}
+
+// checkConnectivity checks that the API can be reached and compares the local clock with the clock of the API.
+func (cmd *DoctorCommand) checkConnectivity() []doctorCheck {
+	remote := defaultAPIRemote
+	if u := cmd.apiRemote(); u != nil {
+		remote = u.String()
+	}
+
+	start := cmd.now()
+	resp, err := cmd.httpClient.Get(remote)
+	if err != nil {
+		return []doctorCheck{{Name: "connectivity", Status: doctorFailed, Message: err.Error()}}
+	}
+	_ = resp.Body.Close()
+	received := cmd.now()
+
+	checks := []doctorCheck{{
+		Name:    "connectivity",
+		Status:  doctorOK,
+		Message: fmt.Sprintf("reached %s in %s", remote, received.Sub(start).Round(time.Millisecond)),
+	}}
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return append(checks, doctorCheck{Name: "clock", Status: doctorWarning, Message: "the API did not return its time"})
+	}
+	skew := received.Sub(date).Round(time.Second)
+	switch {
+	case skew > maxClockSkew:
+		checks = append(checks, doctorCheck{Name: "clock", Status: doctorFailed, Message: fmt.Sprintf("the local clock is %s ahead, which can make authentication fail", skew)})
+	case skew < -maxClockSkew:
+		checks = append(checks, doctorCheck{Name: "clock", Status: doctorFailed, Message: fmt.Sprintf("the local clock is %s behind, which can make authentication fail", -skew)})
+	default:
+		checks = append(checks, doctorCheck{Name: "clock", Status: doctorOK, Message: "the local clock matches the clock of the API"})
+	}
+	return checks
+}
+
+// checkAuthentication checks that the client can authenticate and returns the client when it can.
+func (cmd *DoctorCommand) checkAuthentication() (doctorCheck, secrethub.ClientInterface) {
+	check := doctorCheck{Name: "authentication", Status: doctorFailed}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		check.Message = err.Error()
+		return check, nil
+	}
+
+	user, err := client.Users().Me()
+	if err == api.ErrNotAUser {
+		check.Status = doctorOK
+		check.Message = "authenticated as a service account"
+		return check, client
+	} else if err != nil {
+		check.Message = err.Error()
+		return check, nil
+	}
+
+	check.Status = doctorOK
+	check.Message = "authenticated as " + user.Username
+	return check, client
+}
+
+// checkReadAccess checks that the secret or directory at the path exists and can be read.
+func checkReadAccess(client secrethub.ClientInterface, path string) doctorCheck {
+	check := doctorCheck{Name: "read " + path, Status: doctorFailed}
+	if client == nil {
+		check.Message = "skipped, as the CLI cannot authenticate"
+		return check
+	}
+
+	if api.ValidateSecretPath(path) == nil {
+		_, err := client.Secrets().Get(path)
+		if err == nil {
+			check.Status = doctorOK
+			check.Message = "secret can be read"
+			return check
+		} else if !api.IsErrNotFound(err) {
+			check.Message = err.Error()
+			return check
+		}
+	}
+
+	exists, err := client.Dirs().Exists(path)
+	switch {
+	case err != nil:
+		check.Message = err.Error()
+	case !exists:
+		check.Message = "does not exist or cannot be read"
+	default:
+		check.Status = doctorOK
+		check.Message = "directory can be read"
+	}
+	return check
+}
+
+// writeBundle writes the diagnostics report to a zip archive.
+func (cmd *DoctorCommand) writeBundle(checks []doctorCheck) error {
+	files, err := cmd.bundleFiles(checks)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(cmd.bundle, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	err = writeZip(f, files)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// bundleFiles returns the files of the diagnostics report by their names. The values of the
+// SECRETHUB_ environment variables are removed from all files, in case they appear in error messages.
+func (cmd *DoctorCommand) bundleFiles(checks []doctorCheck) (map[string][]byte, error) {
+	var report bytes.Buffer
+	w := tabwriter.NewWriter(&report, 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\n", "CHECK", "STATUS", "RESULT")
+	for _, check := range checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, check.Status, check.Message)
+	}
+	err := w.Flush()
+	if err != nil {
+		return nil, err
+	}
+
+	checksJSON, err := json.MarshalIndent(map[string]interface{}{
+		"version":    Version,
+		"commit":     Commit,
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"go_version": runtime.Version(),
+		"created_at": cmd.now().UTC(),
+		"checks":     checks,
+	}, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	var config cliConfig
+	err = configFile(cmd.credentialStore.ConfigDir()).read(&config)
+	if err != nil {
+		return nil, err
+	}
+	// Aliases can contain flags with secret material, such as a passphrase.
+	for name := range config.Aliases {
+		config.Aliases[name] = redacted
+	}
+	configJSON, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	environment, secrets := doctorEnvironment(os.Environ())
+	files := map[string][]byte{
+		"report.txt":      report.Bytes(),
+		"report.json":     checksJSON,
+		"environment.txt": []byte(environment),
+		configFilename:    configJSON,
+	}
+	for name, data := range files {
+		files[name] = []byte(redact(string(data), secrets))
+	}
+	return files, nil
+}
+
+// doctorEnvironment returns the SECRETHUB_ and proxy environment variables for the diagnostics report with
+// their values redacted, except for the proxies of which only the username and password are removed. It also returns
+// the redacted values.
+func doctorEnvironment(environ []string) (string, []string) {
+	var lines []string
+	var secrets []string
+	for _, variable := range environ {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name, value := parts[0], parts[1]
+
+		switch strings.ToUpper(name) {
+		case "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY":
+			u, err := url.Parse(value)
+			if err == nil && u.User != nil {
+				u.User = nil
+				value = u.String()
+			}
+			lines = append(lines, name+"="+value)
+			continue
+		}
+
+		if strings.HasPrefix(name, "SECRETHUB_") {
+			lines = append(lines, name+"="+redacted)
+			// Short values, such as true or 1, are not secret and would redact too much.
+			if len(value) >= minRedactedLength {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n", secrets
+}
+
+// redact replaces all occurrences of the secrets in the text.
+func redact(text string, secrets []string) string {
+	for _, secret := range secrets {
+		text = strings.Replace(text, secret, redacted, -1)
+	}
+	return text
+}
+
+// writeZip writes a zip archive with the files, sorted by name.
+func writeZip(w io.Writer, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(files[name])
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
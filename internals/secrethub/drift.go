@@ -0,0 +1,224 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrDriftDetected = errManifest.Code("drift_detected").ErrorPref("found %d resource(s) that do not match the manifest")
+)
+
+// The statuses of resources that do not match a manifest.
+const (
+	driftMissing   = "missing"
+	driftModified  = "modified"
+	driftUnmanaged = "unmanaged"
+)
+
+// driftColorRoles are the roles in the color theme of the statuses of resources that do not match a manifest.
+var driftColorRoles = map[string]string{
+	driftMissing:   colorRoleAdded,
+	driftModified:  colorRoleChanged,
+	driftUnmanaged: colorRoleRemoved,
+}
+
+// driftItem is a resource that does not match the manifest.
+type driftItem struct {
+	status   string
+	kind     string
+	resource string
+}
+
+// DriftCommand reports the differences between a manifest and the remote state.
+type DriftCommand struct {
+	io        ui.IO
+	file      string
+	newClient newClientFunc
+}
+
+// NewDriftCommand creates a new DriftCommand.
+func NewDriftCommand(io ui.IO, newClient newClientFunc) *DriftCommand {
+	return &DriftCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *DriftCommand) Register(r command.Registerer) {
+	clause := r.Command("drift", "Report the differences between a manifest and SecretHub: declared resources that are missing or modified, "+
+		"and directories, secrets and access rules that exist but are not declared. "+
+		"Only the directories the manifest declares resources in are checked for undeclared resources. "+
+		"Exits with an error when drift is found, so it can be used in CI. The manifest has the format used by `secrethub apply`.")
+	clause.Arg("manifest-file", "The path to the YAML manifest").Required().ExistingFileVar(&cmd.file)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run reports the drift.
+func (cmd *DriftCommand) Run() error {
+	m, err := readManifest(cmd.file)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := m.plan(client)
+	if err != nil {
+		return err
+	}
+
+	var items []driftItem
+	for _, change := range plan.changes {
+		status := driftMissing
+		if change.action == planActionUpdate {
+			status = driftModified
+		}
+		items = append(items, driftItem{status: status, kind: change.kind, resource: change.resource})
+	}
+
+	unmanaged, err := m.unmanaged(client)
+	if err != nil {
+		return err
+	}
+	items = append(items, unmanaged...)
+
+	if len(items) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "No drift. All %d declared resources match the manifest and no undeclared resources were found in %s.\n",
+			plan.unchanged, strings.Join(m.roots(), ", "))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\n", "STATUS", "KIND", "RESOURCE")
+	for _, item := range items {
+		// Only the last column is colored, as the escape codes would break the alignment of the other columns.
+		fmt.Fprintf(w, "%s\t%s\t%s\n", item.status, item.kind, colorize(driftColorRoles[item.status], item.resource))
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	return ErrDriftDetected(len(items))
+}
+
+// roots returns the topmost directories the manifest declares resources in, which are the
+// declared directories, the directories of declared secrets and the directories of declared access rules.
+func (m manifest) roots() []string {
+	var candidates []string
+	candidates = append(candidates, m.Dirs...)
+	for _, secret := range m.Secrets {
+		candidates = append(candidates, secret.Path[:strings.LastIndex(secret.Path, "/")])
+	}
+	for _, rule := range m.Access {
+		candidates = append(candidates, rule.Path)
+	}
+
+	var roots []string
+	for _, candidate := range candidates {
+		topmost := true
+		for _, other := range candidates {
+			if strings.HasPrefix(candidate, other+"/") {
+				topmost = false
+				break
+			}
+		}
+		if topmost && !containsString(roots, candidate) {
+			roots = append(roots, candidate)
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// unmanaged returns the directories, secrets and access rules below the roots of the manifest that are not declared in it.
+func (m manifest) unmanaged(client secrethub.ClientInterface) ([]driftItem, error) {
+	dirs := map[string]bool{}
+	for _, dir := range m.dirs() {
+		dirs[dir] = true
+	}
+	secrets := map[string]bool{}
+	for _, secret := range m.Secrets {
+		secrets[secret.Path] = true
+	}
+	rules := map[string]bool{}
+	for _, rule := range m.Access {
+		rules[rule.Path+" "+rule.Account] = true
+	}
+
+	var items []driftItem
+	for _, root := range m.roots() {
+		tree, err := client.Dirs().GetTree(root, -1, false)
+		if api.IsErrNotFound(err) {
+			// The root is reported as missing.
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		var paths []string
+		for dirID := range tree.Dirs {
+			path, err := tree.AbsDirPath(dirID)
+			if err != nil {
+				return nil, err
+			}
+			if path.Value() != root && !dirs[path.Value()] {
+				paths = append(paths, path.Value())
+			}
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			items = append(items, driftItem{status: driftUnmanaged, kind: manifestKindDir, resource: path})
+		}
+
+		paths = nil
+		for secretID := range tree.Secrets {
+			path, err := tree.AbsSecretPath(secretID)
+			if err != nil {
+				return nil, err
+			}
+			if !secrets[path.Value()] {
+				paths = append(paths, path.Value())
+			}
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			items = append(items, driftItem{status: driftUnmanaged, kind: manifestKindSecret, resource: path})
+		}
+
+		accessRules, err := client.AccessRules().List(root, -1, false)
+		if err != nil {
+			return nil, err
+		}
+		var resources []string
+		for _, rule := range accessRules {
+			path, err := tree.AbsDirPath(rule.DirID)
+			if err != nil {
+				return nil, err
+			}
+			if !rules[path.Value()+" "+rule.Account.Name.Value()] {
+				resources = append(resources, fmt.Sprintf("%s for %s (%s)", path, rule.Account.Name, rule.Permission))
+			}
+		}
+		sort.Strings(resources)
+		for _, resource := range resources {
+			items = append(items, driftItem{status: driftUnmanaged, kind: manifestKindAccessRule, resource: resource})
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,136 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestDriftCommand_Run(t *testing.T) {
+	rootDirID := uuid.New()
+	dbDirID := uuid.New()
+	oldDirID := uuid.New()
+
+	cases := map[string]struct {
+		secrets  map[string]string
+		rules    map[string]string
+		extra    bool
+		expected string
+		err      error
+	}{
+		"no drift": {
+			secrets: map[string]string{
+				"namespace/repo/prod/api-url":     "https://api.example.com",
+				"namespace/repo/prod/db/host":     "db.internal",
+				"namespace/repo/prod/db/password": "existing",
+				"namespace/repo/prod/db/user":     "admin",
+				"namespace/repo/prod/token":       "generated",
+				"namespace/repo/prod/config":      `{"debug":false}`,
+				"namespace/repo/shared/db-user":   "admin",
+			},
+			rules: map[string]string{
+				"namespace/repo/prod deploy-bot": "read",
+				"namespace/repo/prod ci":         "write",
+				"namespace/repo/prod/db dba":     "admin",
+			},
+			expected: "No drift. All 11 declared resources match the manifest and no undeclared resources were found in namespace/repo/prod.\n",
+		},
+		"drift": {
+			secrets: map[string]string{
+				"namespace/repo/prod/api-url":     "https://api.example.com",
+				"namespace/repo/prod/db/host":     "localhost",
+				"namespace/repo/prod/db/password": "existing",
+				"namespace/repo/prod/db/user":     "admin",
+				"namespace/repo/prod/config":      `{"debug":false}`,
+				"namespace/repo/prod/legacy":      "unmanaged",
+				"namespace/repo/shared/db-user":   "admin",
+			},
+			rules: map[string]string{
+				"namespace/repo/prod deploy-bot": "read",
+				"namespace/repo/prod ci":         "read",
+				"namespace/repo/prod/db dba":     "admin",
+				"namespace/repo/prod intern":     "read",
+			},
+			extra: true,
+			expected: "STATUS     KIND         RESOURCE\n" +
+				"modified   secret       namespace/repo/prod/db/host\n" +
+				"missing    secret       namespace/repo/prod/token\n" +
+				"modified   access rule  namespace/repo/prod for ci (read -> write)\n" +
+				"unmanaged  dir          namespace/repo/prod/old\n" +
+				"unmanaged  secret       namespace/repo/prod/legacy\n" +
+				"unmanaged  access rule  namespace/repo/prod for intern (read)\n",
+			err: ErrDriftDetected(6),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path, cleanup := writeTestManifest(t)
+			defer cleanup()
+
+			tree := &api.Tree{
+				ParentPath: "namespace/repo",
+				RootDir:    &api.Dir{DirID: rootDirID, Name: "prod"},
+				Dirs: map[uuid.UUID]*api.Dir{
+					rootDirID: {DirID: rootDirID, Name: "prod"},
+					dbDirID:   {DirID: dbDirID, Name: "db", ParentID: &rootDirID},
+				},
+				Secrets: map[uuid.UUID]*api.Secret{},
+			}
+			if tc.extra {
+				tree.Dirs[oldDirID] = &api.Dir{DirID: oldDirID, Name: "old", ParentID: &rootDirID}
+			}
+			dirIDs := map[string]uuid.UUID{
+				"namespace/repo/prod":    rootDirID,
+				"namespace/repo/prod/db": dbDirID,
+			}
+			for path := range tc.secrets {
+				dirID, ok := dirIDs[path[:strings.LastIndex(path, "/")]]
+				if !ok {
+					continue
+				}
+				secretID := uuid.New()
+				tree.Secrets[secretID] = &api.Secret{SecretID: secretID, DirID: dirID, Name: api.SecretPath(path).GetSecret()}
+			}
+
+			client := manifestTestClient(tc.secrets, map[string]bool{"namespace/repo/prod": true, "namespace/repo/prod/db": true}, tc.rules).(fakeclient.Client)
+			client.DirService.GetTreeFunc = func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return tree, nil
+			}
+			client.AccessRuleService.ListFunc = func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+				var res []*api.AccessRule
+				for key, permission := range tc.rules {
+					i := strings.LastIndex(key, " ")
+					dirPath, account := key[:i], key[i+1:]
+					rule := &api.AccessRule{
+						Account: &api.Account{Name: api.AccountName(account)},
+						DirID:   dirIDs[dirPath],
+					}
+					assert.OK(t, rule.Permission.Set(permission))
+					res = append(res, rule)
+				}
+				return res, nil
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := DriftCommand{
+				io:   io,
+				file: path,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return client, nil
+				},
+			}
+
+			err := cmd.Run()
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package secrethub
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationValue is a flag value for durations that, in addition to the units
+// supported by time.ParseDuration, accepts a whole number of days (e.g. 7d).
+type durationValue time.Duration
+
+// Set parses the given duration.
+func (d *durationValue) Set(value string) error {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil {
+			*d = durationValue(time.Duration(days) * 24 * time.Hour)
+			return nil
+		}
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = durationValue(parsed)
+	return nil
+}
+
+// String implements the flag.Value interface.
+func (d durationValue) String() string {
+	return time.Duration(d).String()
+}
@@ -0,0 +1,43 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestDurationValue_Set(t *testing.T) {
+	cases := map[string]struct {
+		in       string
+		expected time.Duration
+		err      bool
+	}{
+		"hours": {
+			in:       "4h",
+			expected: 4 * time.Hour,
+		},
+		"days": {
+			in:       "7d",
+			expected: 7 * 24 * time.Hour,
+		},
+		"combined": {
+			in:       "1h30m",
+			expected: 90 * time.Minute,
+		},
+		"invalid": {
+			in:  "a week",
+			err: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var d durationValue
+			err := d.Set(tc.in)
+
+			assert.Equal(t, err != nil, tc.err)
+			assert.Equal(t, time.Duration(d), tc.expected)
+		})
+	}
+}
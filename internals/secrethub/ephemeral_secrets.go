@@ -0,0 +1,72 @@
+package secrethub
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// ephemeralSecretsFilename is the name of the file in the configuration directory
+// that keeps track of secrets that should be removed after some time.
+const ephemeralSecretsFilename = "ephemeral_secrets.json"
+
+// ephemeralSecret is a secret written with `write --delete-after` that should be removed once it expires.
+type ephemeralSecret struct {
+	Path      string    `json:"path"`
+	Version   int       `json:"version"`
+	WrittenAt time.Time `json:"written_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// isExpired returns whether the secret has expired at the given time.
+func (s ephemeralSecret) isExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// versionPath returns the path of the version of the secret that was written as ephemeral secret.
+func (s ephemeralSecret) versionPath() string {
+	return fmt.Sprintf("%s:%d", s.Path, s.Version)
+}
+
+// ephemeralSecretStore keeps track of ephemeral secrets in a file in the configuration directory.
+type ephemeralSecretStore struct {
+	jsonFile
+}
+
+// newEphemeralSecretStore creates an ephemeralSecretStore that stores its secrets in the given configuration directory.
+func newEphemeralSecretStore(dir configdir.Dir) ephemeralSecretStore {
+	return ephemeralSecretStore{
+		jsonFile{path: filepath.Join(dir.Path(), ephemeralSecretsFilename)},
+	}
+}
+
+// List returns all tracked secrets. When no secrets have been stored yet, an empty list is returned.
+func (s ephemeralSecretStore) List() ([]ephemeralSecret, error) {
+	secrets := []ephemeralSecret{}
+	err := s.read(&secrets)
+	if err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// Add starts tracking the given secret, replacing any secret tracked on the same path.
+func (s ephemeralSecretStore) Add(secret ephemeralSecret) error {
+	secrets := []ephemeralSecret{}
+	return s.update(&secrets, func() {
+		res := []ephemeralSecret{secret}
+		for _, existing := range secrets {
+			if existing.Path != secret.Path {
+				res = append(res, existing)
+			}
+		}
+		secrets = res
+	})
+}
+
+// Save overwrites the tracked secrets with the given secrets.
+func (s ephemeralSecretStore) Save(secrets []ephemeralSecret) error {
+	return s.write(secrets)
+}
@@ -0,0 +1,231 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+
+	"github.com/alecthomas/kingpin"
+)
+
+// The exit codes of the application for each class of errors. They are stable across versions.
+const (
+	exitCodeError            = 1
+	exitCodeValidation       = 2
+	exitCodeNotFound         = 3
+	exitCodePermissionDenied = 4
+	exitCodeNetwork          = 5
+	exitCodeConflict         = 6
+	exitCodeAborted          = 7
+)
+
+// The values of the --error-format flag.
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// exitCodesHelp documents the exit codes in the help text of the application.
+const exitCodesHelp = "The CLI exits with status 2 for invalid usage, 3 when a resource is not found, 4 when permission is denied, " +
+	"5 for network errors and timeouts, 6 when a resource already exists or conflicts with another change, " +
+	"7 when a confirmation is declined and 1 for any other error."
+
+// Errors
+var (
+	// ErrAborted is returned when a confirmation is declined. The command has already reported that it is
+	// aborted, so the error is not written in the text format, but it exits with a distinct exit code.
+	ErrAborted = errMain.Code("aborted").Error("the command was aborted because the confirmation was declined")
+)
+
+// errorOutput is the JSON representation of an error. Its fields are stable across versions.
+type errorOutput struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// errorWriter writes the errors returned by commands in the configured format.
+type errorWriter struct {
+	format string
+	// parsed is set when the arguments have been parsed and validated,
+	// so every error that is returned before then is a usage error.
+	parsed bool
+}
+
+// Register registers the --error-format flag and the actions that track the parsing of the arguments on the app.
+func (e *errorWriter) Register(app *cli.App) {
+	app.Flag("error-format", "The format to write errors to stderr in. Options are: text and json. "+
+		"JSON errors have a code, a message and a hint. Defaults to json when --output json is used and to text otherwise. "+
+		exitCodesHelp).HintOptions(errorFormatText, errorFormatJSON).EnumVar(&e.format, errorFormatText, errorFormatJSON)
+
+	app.PreAction(func(context *kingpin.ParseContext) error {
+		if e.format == "" && isJSONOutputRequested(context) {
+			e.format = errorFormatJSON
+		}
+		return nil
+	})
+	app.Action(func(*kingpin.ParseContext) error {
+		e.parsed = true
+		return nil
+	})
+}
+
+// isJSONOutputRequested returns whether --output json is given for the parsed command.
+func isJSONOutputRequested(context *kingpin.ParseContext) bool {
+	for _, element := range context.Elements {
+		flag, ok := element.Clause.(*kingpin.FlagClause)
+		if ok && element.Value != nil && flag.Model().Name == "output" && *element.Value == formatJSON {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the error to w and returns the exit code for the error.
+func (e *errorWriter) Write(w io.Writer, err error) int {
+	exitCode, hint := classifyError(err, e.parsed)
+
+	if e.format != errorFormatJSON {
+		if exitCode == exitCodeAborted {
+			return exitCode
+		}
+		fmt.Fprintf(w, "Encountered an error: %s\n", err)
+		if hint := ciPromptHint(err); hint != "" {
+			fmt.Fprintln(w, hint)
+		}
+		return exitCode
+	}
+
+	var suggestionErr suggestionError
+	if errors.As(err, &suggestionErr) {
+		hint = suggestionErr.hint()
+		err = suggestionErr.err
+	}
+
+	output := errorOutput{
+		Code:    errorCode(err, e.parsed),
+		Message: errorMessage(err),
+		Hint:    hint,
+	}
+	out, jsonErr := json.Marshal(output)
+	if jsonErr != nil {
+		fmt.Fprintf(w, "Encountered an error: %s\n", err)
+		return exitCode
+	}
+	fmt.Fprintln(w, string(out))
+	return exitCode
+}
+
+// classifyError returns the exit code of the class of the error and a hint on how to resolve it.
+func classifyError(err error, parsed bool) (int, string) {
+	if !parsed {
+		return exitCodeValidation, usageHint
+	}
+
+	var statusErr errio.PublicStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusNotFound:
+			return exitCodeNotFound, notFoundHint
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return exitCodePermissionDenied, permissionDeniedHint
+		case http.StatusConflict:
+			return exitCodeConflict, conflictHint
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return exitCodeValidation, ""
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return exitCodeNetwork, networkHint
+	}
+	if errors.Is(err, os.ErrPermission) {
+		return exitCodePermissionDenied, "Check the permissions of the files the command reads and writes."
+	}
+
+	publicErr, ok := asPublicError(err)
+	if !ok {
+		return exitCodeError, ""
+	}
+	if hint := ciPromptHint(publicErr); hint != "" {
+		return exitCodeError, hint
+	}
+
+	switch {
+	case publicErr.Namespace == "http" && publicErr.Code == "timeout":
+		return exitCodeNetwork, timeoutHint
+	case publicErr.Code == "not_available_offline":
+		return exitCodeNetwork, offlineHint
+	case publicErr.Namespace == "http" && publicErr.Code == "request_failed":
+		return exitCodeNetwork, networkHint
+	case publicErr.Code == "aborted":
+		return exitCodeAborted, abortedHint
+	case strings.HasSuffix(publicErr.Code, "not_found"):
+		return exitCodeNotFound, notFoundHint
+	case strings.HasPrefix(publicErr.Code, "invalid") || publicErr.Code == "flags_conflict" || publicErr.Code == "missing_flags":
+		return exitCodeValidation, usageHint
+	case strings.HasSuffix(publicErr.Code, "already_exists") || strings.HasSuffix(publicErr.Code, "conflict"):
+		return exitCodeConflict, conflictHint
+	}
+	return exitCodeError, ""
+}
+
+// Hints for the classes of errors.
+const (
+	usageHint            = "Run the command with --help to see its usage."
+	notFoundHint         = "Check that the path is correct. Resources you do not have access to are also reported as not found."
+	permissionDeniedHint = "Check that you have the required permissions, e.g. with `secrethub acl check`."
+	timeoutHint          = "The API did not respond in time. Check your network connection and proxy settings, or allow more time with --timeout."
+	offlineHint          = "Run the command without --offline when the API can be reached."
+	networkHint          = "Check your network connection and proxy settings. See https://status.secrethub.io for the status of SecretHub."
+	conflictHint         = "Check whether the resource already exists, or retry when it was changed at the same time."
+	abortedHint          = "Confirm the prompt, or use --force to skip it in scripts when the command supports it."
+)
+
+// errorCode returns the code of the error, prefixed with its namespace.
+func errorCode(err error, parsed bool) string {
+	publicErr, ok := asPublicError(err)
+	if !ok {
+		if !parsed {
+			publicErr = ErrParseError.Error("")
+		} else {
+			return "unexpected"
+		}
+	}
+	if publicErr.Namespace == "" {
+		return publicErr.Code
+	}
+	return publicErr.Type()
+}
+
+// errorMessage returns the message of the error without its code.
+func errorMessage(err error) string {
+	publicErr, ok := asPublicError(err)
+	if !ok {
+		return err.Error()
+	}
+	return publicErr.Message
+}
+
+// asPublicError returns the public error that err is or wraps, if any.
+func asPublicError(err error) (errio.PublicError, bool) {
+	var statusErr errio.PublicStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.PublicError, true
+	}
+	var publicErr errio.PublicError
+	if errors.As(err, &publicErr) {
+		return publicErr, true
+	}
+	return errio.PublicError{}, false
+}
@@ -0,0 +1,146 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		parsed   bool
+		expected int
+	}{
+		"usage error": {
+			err:      errors.New("required argument 'path' not provided"),
+			parsed:   false,
+			expected: exitCodeValidation,
+		},
+		"api not found": {
+			err:      api.ErrSecretNotFound,
+			parsed:   true,
+			expected: exitCodeNotFound,
+		},
+		"cli not found": {
+			err:      ErrResourceNotFound("company/repo/secret"),
+			parsed:   true,
+			expected: exitCodeNotFound,
+		},
+		"forbidden": {
+			err:      api.ErrForbidden,
+			parsed:   true,
+			expected: exitCodePermissionDenied,
+		},
+		"not authenticated": {
+			err:      api.ErrRequestNotAuthenticated,
+			parsed:   true,
+			expected: exitCodePermissionDenied,
+		},
+		"file permission": {
+			err:      fmt.Errorf("cannot open file: %w", os.ErrPermission),
+			parsed:   true,
+			expected: exitCodePermissionDenied,
+		},
+		"network": {
+			err:      &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			parsed:   true,
+			expected: exitCodeNetwork,
+		},
+		"api conflict": {
+			err:      api.ErrRepoAlreadyExists,
+			parsed:   true,
+			expected: exitCodeConflict,
+		},
+		"cli conflict": {
+			err:      ErrFileAlreadyExists,
+			parsed:   true,
+			expected: exitCodeConflict,
+		},
+		"aborted": {
+			err:      ErrAborted,
+			parsed:   true,
+			expected: exitCodeAborted,
+		},
+		"invalid value": {
+			err:      ErrFlagsConflict("--force and --dry-run"),
+			parsed:   true,
+			expected: exitCodeValidation,
+		},
+		"other": {
+			err:      ErrCannotDoWithoutForce,
+			parsed:   true,
+			expected: exitCodeError,
+		},
+		"unexpected": {
+			err:      errors.New("unexpected"),
+			parsed:   true,
+			expected: exitCodeError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, _ := classifyError(tc.err, tc.parsed)
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestErrorWriter_Write(t *testing.T) {
+	cases := map[string]struct {
+		writer   errorWriter
+		err      error
+		expected string
+		exitCode int
+	}{
+		"text": {
+			writer:   errorWriter{parsed: true},
+			err:      errors.New("something went wrong"),
+			expected: "Encountered an error: something went wrong\n",
+			exitCode: exitCodeError,
+		},
+		"text aborted": {
+			writer:   errorWriter{parsed: true},
+			err:      ErrAborted,
+			expected: "",
+			exitCode: exitCodeAborted,
+		},
+		"json": {
+			writer:   errorWriter{format: errorFormatJSON, parsed: true},
+			err:      ErrResourceNotFound("company/repo"),
+			expected: `{"code":"secrethub.resource_not_found","message":"the resource at path company/repo does not exist","hint":"` + notFoundHint + `"}` + "\n",
+			exitCode: exitCodeNotFound,
+		},
+		"json unexpected": {
+			writer:   errorWriter{format: errorFormatJSON, parsed: true},
+			err:      errors.New("something went wrong"),
+			expected: `{"code":"unexpected","message":"something went wrong"}` + "\n",
+			exitCode: exitCodeError,
+		},
+		"json usage error": {
+			writer:   errorWriter{format: errorFormatJSON},
+			err:      errors.New("required argument 'path' not provided"),
+			expected: `{"code":"cli.parse_error","message":"required argument 'path' not provided","hint":"` + usageHint + `"}` + "\n",
+			exitCode: exitCodeValidation,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			exitCode := tc.writer.Write(&buf, tc.err)
+
+			assert.Equal(t, exitCode, tc.exitCode)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// EventsCommand handles streams of the changes to secrets.
+type EventsCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewEventsCommand creates a new EventsCommand.
+func NewEventsCommand(io ui.IO, newClient newClientFunc) *EventsCommand {
+	return &EventsCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *EventsCommand) Register(r command.Registerer) {
+	clause := r.Command("events", "Stream the changes to secrets, for consumption by pipelines and other tools.")
+	NewEventsSubscribeCommand(cmd.io, cmd.newClient).Register(clause)
+}
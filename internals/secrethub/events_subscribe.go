@@ -0,0 +1,150 @@
+package secrethub
+
+import (
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// EventsSubscribeCommand writes a continuous stream of the changes to the secrets of one or more repositories.
+type EventsSubscribeCommand struct {
+	io        ui.IO
+	path      api.Path
+	format    string
+	events    []string
+	interval  time.Duration
+	newClient newClientFunc
+	sleep     func(time.Duration)
+}
+
+// NewEventsSubscribeCommand creates a new EventsSubscribeCommand.
+func NewEventsSubscribeCommand(io ui.IO, newClient newClientFunc) *EventsSubscribeCommand {
+	return &EventsSubscribeCommand{
+		io:        io,
+		newClient: newClient,
+		sleep:     time.Sleep,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *EventsSubscribeCommand) Register(r command.Registerer) {
+	clause := r.Command("subscribe", "Keep running and write every new event of a repository or a secret as soon as it is logged. "+
+		"Without a path, the events of all repositories you have access to are written. "+
+		"As SecretHub has no push channel for events, the audit log is polled for new events.")
+	clause.Arg("repo-path or secret-path", "Path to the repository or the secret to subscribe to "+repoPathPlaceHolder+" or "+secretPathPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("output", "The format to write the events in. Options are: json-lines (the fields of an exported audit record as a JSON object on every line), csv, cef and leef.").
+		HintOptions(formatJSONLines, formatCSV, formatCEF, formatLEEF).Default(formatJSONLines).StringVar(&cmd.format)
+	clause.Flag("events", "The events to write, as a comma separated list of write (new versions), delete, acl (membership changes) and read.").
+		Default(auditActionWrite+","+auditActionDelete+","+auditActionACL).
+		HintOptions(auditActionRead, auditActionWrite, auditActionDelete, auditActionACL).SetValue(&webhookEventsValue{events: &cmd.events})
+	clause.Flag("interval", "The interval at which to poll for new events.").Default("10s").DurationVar(&cmd.interval)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// eventSource is a repository or secret of which the events are streamed, with the position up to which they have been written.
+type eventSource struct {
+	path api.Path
+	pos  auditPosition
+}
+
+// Run polls for new events and writes them, until the process is interrupted.
+func (cmd *EventsSubscribeCommand) Run() error {
+	if cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	format := cmd.format
+	if format == formatJSONLines {
+		format = formatJSON
+	}
+	formatter, err := newAuditRecordFormatter(cmd.io.Output(), format)
+	if err != nil {
+		return err
+	}
+
+	paths, err := cmd.paths()
+	if err != nil {
+		return err
+	}
+
+	// Events that were already logged when the command started are not written.
+	sources := make([]*eventSource, len(paths))
+	for i, path := range paths {
+		iter, _, err := auditRecordSource(path, cmd.newClient)
+		if err != nil {
+			return err
+		}
+		pos, err := newestAuditPosition(iter)
+		if err != nil {
+			return err
+		}
+		sources[i] = &eventSource{path: path, pos: pos}
+	}
+
+	for {
+		cmd.sleep(cmd.interval)
+
+		for _, source := range sources {
+			err = cmd.poll(formatter, source)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// paths returns the paths of the repositories or secret to stream the events of.
+func (cmd *EventsSubscribeCommand) paths() ([]api.Path, error) {
+	if cmd.path != "" {
+		return []api.Path{cmd.path}, nil
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return nil, err
+	}
+	repos, err := client.Repos().ListMine()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]api.Path, len(repos))
+	for i, repo := range repos {
+		paths[i] = api.Path(repo.Path().Value())
+	}
+	return paths, nil
+}
+
+// poll writes the new events of the source that are subscribed to and advances its position past them.
+func (cmd *EventsSubscribeCommand) poll(formatter auditRecordFormatter, source *eventSource) error {
+	iter, newRecord, err := auditRecordSource(source.path, cmd.newClient)
+	if err != nil {
+		return err
+	}
+
+	events, err := eventsAfter(iter, source.pos)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		source.pos.advance(event)
+		if !containsString(cmd.events, auditActionCategory(event)) {
+			continue
+		}
+
+		record, err := newRecord(event)
+		if err != nil {
+			return err
+		}
+		err = formatter.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestEventsSubscribeCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	event := func(action api.AuditAction, loggedAt time.Time) api.Audit {
+		return api.Audit{
+			EventID: uuid.New(),
+			Action:  action,
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{
+					Username: "developer",
+				},
+			},
+			LoggedAt: loggedAt,
+			Subject: api.AuditSubject{
+				Type: "repo",
+				Repo: &api.Repo{
+					Name: "repo",
+				},
+			},
+		}
+	}
+	existing := event(api.AuditActionCreate, start)
+	created := event(api.AuditActionCreate, start.Add(time.Minute))
+	read := event(api.AuditActionRead, start.Add(2*time.Minute))
+	deleted := event(api.AuditActionDelete, start.Add(3*time.Minute))
+
+	cases := map[string]struct {
+		path     api.Path
+		events   []string
+		expected []api.Audit
+	}{
+		"changes": {
+			path:     "namespace/repo",
+			events:   []string{auditActionWrite, auditActionDelete, auditActionACL},
+			expected: []api.Audit{created, deleted},
+		},
+		"reads": {
+			path:     "namespace/repo",
+			events:   []string{auditActionRead},
+			expected: []api.Audit{read},
+		},
+		"all repositories": {
+			events:   []string{auditActionDelete},
+			expected: []api.Audit{deleted},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			polls := [][]api.Audit{
+				{existing},
+				{created, existing},
+				{deleted, read, created, existing},
+			}
+			if tc.path == "" {
+				// The repositories are listed with a client of its own.
+				polls = append([][]api.Audit{nil}, polls...)
+			}
+
+			var sleeps int
+			fakeIO := fakeui.NewIO(t)
+			cmd := EventsSubscribeCommand{
+				io:       fakeIO,
+				path:     tc.path,
+				format:   formatJSONLines,
+				events:   tc.events,
+				interval: 10 * time.Second,
+				sleep: func(time.Duration) {
+					sleeps++
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					if len(polls) == 0 {
+						return nil, testErr
+					}
+					events := polls[0]
+					polls = polls[1:]
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return nil, nil
+							},
+						},
+						RepoService: &fakeclient.RepoService{
+							ListMineFunc: func() ([]*api.Repo, error) {
+								return []*api.Repo{{Owner: "namespace", Name: "repo"}}, nil
+							},
+							AuditEventIterator: &fakeclient.AuditEventIterator{
+								Events: events,
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, testErr)
+			assert.Equal(t, sleeps, 3)
+
+			expected := &bytes.Buffer{}
+			encoder := json.NewEncoder(expected)
+			for _, e := range tc.expected {
+				record, err := newAuditRecord(e, "namespace/repo", "repo")
+				assert.OK(t, err)
+				assert.OK(t, encoder.Encode(record))
+			}
+			assert.Equal(t, fakeIO.Out.String(), expected.String())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// expirySweeper removes the expired entries of a list that is kept in the configuration directory,
+// such as the ephemeral secrets removed by gc and the temporary access rules removed by acl expire-sweep.
+// The entries that have not expired or could not be removed are kept, so they are tried again on the next sweep.
+type expirySweeper struct {
+	io        ui.IO
+	newClient newClientFunc
+	dryRun    bool
+	// noneExpired is printed when no entry has expired.
+	noneExpired string
+	// isExpired returns whether the entry with the given index has expired.
+	isExpired func(i int) bool
+	// describe returns the line that is printed for an expired entry in a dry run.
+	describe func(i int) string
+	// remove removes an expired entry and prints the result. It returns false when the entry could not be removed.
+	remove func(client secrethub.ClientInterface, i int) bool
+	// save stores the entries with the given indexes as the remaining entries.
+	save func(remaining []int) error
+}
+
+// sweep removes the expired entries of the n entries and returns the number of entries that could not be removed.
+func (s expirySweeper) sweep(n int) (int, error) {
+	var expired, remaining []int
+	for i := 0; i < n; i++ {
+		if s.isExpired(i) {
+			expired = append(expired, i)
+		} else {
+			remaining = append(remaining, i)
+		}
+	}
+
+	if len(expired) == 0 {
+		fmt.Fprintln(s.io.Output(), s.noneExpired)
+		return 0, nil
+	}
+
+	if s.dryRun {
+		for _, i := range expired {
+			fmt.Fprintln(s.io.Output(), s.describe(i))
+		}
+		return 0, nil
+	}
+
+	client, err := s.newClient()
+	if err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, i := range expired {
+		if !s.remove(client, i) {
+			remaining = append(remaining, i)
+			failed++
+		}
+	}
+
+	err = s.save(remaining)
+	if err != nil {
+		return 0, err
+	}
+	return failed, nil
+}
@@ -0,0 +1,106 @@
+package secrethub
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// ExportCommand handles exporting secrets to other destinations.
+type ExportCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewExportCommand creates a new ExportCommand.
+func NewExportCommand(io ui.IO, newClient newClientFunc) *ExportCommand {
+	return &ExportCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *ExportCommand) Register(r command.Registerer) {
+	clause := r.Command("export", "Export secrets to other destinations.")
+	NewExportAWSSMCommand(cmd.io, cmd.newClient).Register(clause)
+	NewExportGCPSMCommand(cmd.io, cmd.newClient).Register(clause)
+	NewExportAzureKVCommand(cmd.io, cmd.newClient).Register(clause)
+	NewExportAgeCommand(cmd.io, cmd.newClient).Register(clause)
+	NewExportConfigCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// readDirSecrets returns the secrets in the directory and its subdirectories, named by their path
+// relative to the directory and sorted by name. Only the latest version of every secret is returned,
+// unless allVersions is set, in which case every version is returned, oldest first.
+// The secrets are read concurrently, as set with the --concurrency flag. This is safe because fetching
+// the tree first loads the account key and the key of the repository into the client, which it does
+// not synchronize.
+func readDirSecrets(client secrethub.ClientInterface, dirPath api.DirPath, allVersions bool) ([]namedSecret, error) {
+	tree, err := client.Dirs().GetTree(dirPath.Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	secretPaths := make([]api.SecretPath, 0, len(tree.Secrets))
+	for secretID := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secretID)
+		if err != nil {
+			return nil, err
+		}
+		if isRepoLinksPath(*secretPath) {
+			continue
+		}
+		secretPaths = append(secretPaths, *secretPath)
+	}
+
+	counter := newProgressCounter("Reading secrets", len(secretPaths))
+	defer counter.Finish()
+
+	versions := make([][]*api.SecretVersion, len(secretPaths))
+	err = forEachConcurrently(int(concurrency), len(secretPaths), func(i int) error {
+		if allVersions {
+			secretVersions, err := client.Secrets().Versions().ListWithData(secretPaths[i].Value())
+			if err != nil {
+				return err
+			}
+			sort.Slice(secretVersions, func(a, b int) bool {
+				return secretVersions[a].Version < secretVersions[b].Version
+			})
+			versions[i] = secretVersions
+		} else {
+			version, err := client.Secrets().Versions().GetWithData(secretPaths[i].Value())
+			if err != nil {
+				return err
+			}
+			versions[i] = []*api.SecretVersion{version}
+		}
+		counter.Done()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []namedSecret
+	for i, secretPath := range secretPaths {
+		name := strings.TrimPrefix(secretPath.Value(), dirPath.Value()+"/")
+		for _, version := range versions[i] {
+			secrets = append(secrets, namedSecret{
+				Name:  name,
+				Value: version.Data,
+			})
+		}
+	}
+
+	// The sort is stable to keep the versions of a secret in order.
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	return secrets, nil
+}
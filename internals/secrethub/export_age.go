@@ -0,0 +1,198 @@
+package secrethub
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrNoAgeRecipients = errMain.Code("no_age_recipients").Error("no age recipients given: use --age-recipient or --age-recipients-file")
+)
+
+// ExportAgeCommand exports secrets to a zip archive that is encrypted to age recipients.
+type ExportAgeCommand struct {
+	io             ui.IO
+	path           api.Path
+	out            string
+	recipients     []string
+	recipientsFile string
+	newClient      newClientFunc
+}
+
+// NewExportAgeCommand creates a new ExportAgeCommand.
+func NewExportAgeCommand(io ui.IO, newClient newClientFunc) *ExportAgeCommand {
+	return &ExportAgeCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExportAgeCommand) Register(r command.Registerer) {
+	clause := r.Command("age", "Export a secret or the latest version of all secrets in a directory to a zip archive that is encrypted to one or more age recipients (https://age-encryption.org). "+
+		"The secrets are encrypted while they are exported, so no unencrypted files are written. "+
+		"The recipients decrypt the archive with `age --decrypt --identity <key-file> <file>`.")
+	clause.Arg("path", "The secret or directory to export ("+secretPathOptionalVersionPlaceHolder+" or "+dirPathPlaceHolder+")").Required().SetValue(&cmd.path)
+	clause.Flag("out", "The file to write the encrypted archive to.").Required().PlaceHolder("secrets.zip.age").StringVar(&cmd.out)
+	clause.Flag("age-recipient", "An age recipient (age1...) to encrypt the archive to. Can be repeated to encrypt to multiple recipients.").StringsVar(&cmd.recipients)
+	clause.Flag("age-recipients-file", "A file with an age recipient on every line to encrypt the archive to. Empty lines and lines starting with # are ignored.").ExistingFileVar(&cmd.recipientsFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run exports the secrets to the encrypted archive.
+func (cmd *ExportAgeCommand) Run() error {
+	recipients, err := cmd.parseRecipients()
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(cmd.out)
+	if err == nil {
+		return ErrExportAlreadyExists
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := cmd.readSecrets(client)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(cmd.out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, configFileMode)
+	if err != nil {
+		return ErrCannotWrite(cmd.out, err)
+	}
+
+	err = writeAgeArchive(file, secrets, recipients)
+	closeErr := file.Close()
+	if err == nil && closeErr != nil {
+		err = ErrCannotWrite(cmd.out, closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(cmd.out)
+		return err
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Export complete! %s encrypted to %s written to %s.\n",
+		pluralize("secret", "secrets", len(secrets)),
+		pluralize("recipient", "recipients", len(recipients)),
+		cmd.out,
+	)
+	return nil
+}
+
+// parseRecipients returns the recipients given with the flags.
+func (cmd *ExportAgeCommand) parseRecipients() ([]ageRecipient, error) {
+	values := cmd.recipients
+	if cmd.recipientsFile != "" {
+		file, err := os.Open(cmd.recipientsFile)
+		if err != nil {
+			return nil, ErrCannotReadFile(cmd.recipientsFile, err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			values = append(values, line)
+		}
+		err = scanner.Err()
+		if err != nil {
+			return nil, ErrCannotReadFile(cmd.recipientsFile, err)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, ErrNoAgeRecipients
+	}
+
+	recipients := make([]ageRecipient, len(values))
+	for i, value := range values {
+		recipient, err := parseAgeRecipient(value)
+		if err != nil {
+			return nil, err
+		}
+		recipients[i] = recipient
+	}
+	return recipients, nil
+}
+
+// readSecrets reads the secret or the secrets in the directory at the path. The secrets in a
+// directory are named by their path relative to the parent of the directory, so the archive
+// contains the directory itself.
+func (cmd *ExportAgeCommand) readSecrets(client secrethub.ClientInterface) ([]namedSecret, error) {
+	if !cmd.path.HasVersion() {
+		dirPath, err := cmd.path.ToDirPath()
+		if err != nil {
+			return nil, err
+		}
+
+		secrets, err := readDirSecrets(client, dirPath, false)
+		if err == nil {
+			for i := range secrets {
+				secrets[i].Name = dirPath.GetDirName() + "/" + secrets[i].Name
+			}
+			return secrets, nil
+		} else if !api.IsErrNotFound(err) {
+			return nil, err
+		}
+	}
+
+	secretPath, err := cmd.path.ToSecretPath()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := client.Secrets().Versions().GetWithData(secretPath.Value())
+	if api.IsErrNotFound(err) {
+		return nil, ErrResourceNotFound(cmd.path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return []namedSecret{{Name: secretPath.GetSecret(), Value: version.Data}}, nil
+}
+
+// writeAgeArchive writes the secrets as a zip archive, encrypted to the recipients, to w.
+func writeAgeArchive(w io.Writer, secrets []namedSecret, recipients []ageRecipient) error {
+	encrypted, err := ageEncrypt(w, recipients...)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(encrypted)
+	for _, secret := range secrets {
+		entry, err := archive.Create(secret.Name)
+		if err != nil {
+			return err
+		}
+		_, err = entry.Write(secret.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return err
+	}
+	return encrypted.Close()
+}
@@ -0,0 +1,171 @@
+package secrethub
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestExportAgeCommand_Run(t *testing.T) {
+	rootDirID := uuid.New()
+	subDirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace/repo",
+		RootDir:    &api.Dir{DirID: rootDirID, Name: "prod"},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {DirID: rootDirID, Name: "prod"},
+			subDirID:  {DirID: subDirID, Name: "db", ParentID: &rootDirID},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{},
+	}
+	for _, secret := range []*api.Secret{
+		{SecretID: uuid.New(), DirID: rootDirID, Name: "token"},
+		{SecretID: uuid.New(), DirID: subDirID, Name: "password"},
+	} {
+		tree.Secrets[secret.SecretID] = secret
+	}
+
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			DirService: &fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					if path != "namespace/repo/prod" {
+						return nil, api.ErrDirNotFound
+					}
+					return tree, nil
+				},
+			},
+			SecretService: &fakeclient.SecretService{
+				VersionService: &fakeclient.SecretVersionService{
+					GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+						if path == "namespace/repo/prod/missing" {
+							return nil, api.ErrSecretNotFound
+						}
+						return &api.SecretVersion{Data: []byte("value of " + path)}, nil
+					},
+				},
+			},
+		}, nil
+	}
+
+	alice, err := generateAgeIdentity()
+	assert.OK(t, err)
+	bob, err := generateAgeIdentity()
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		path           api.Path
+		recipients     []string
+		recipientsFile string
+		out            string
+		expected       map[string]string
+		err            error
+	}{
+		"directory": {
+			path:       "namespace/repo/prod",
+			recipients: []string{alice.Recipient().String()},
+			out:        "Export complete! 2 secrets encrypted to 1 recipient written to %s.\n",
+			expected: map[string]string{
+				"prod/db/password": "value of namespace/repo/prod/db/password",
+				"prod/token":       "value of namespace/repo/prod/token",
+			},
+		},
+		"secret": {
+			path:       "namespace/repo/prod/token",
+			recipients: []string{alice.Recipient().String(), bob.Recipient().String()},
+			out:        "Export complete! 1 secret encrypted to 2 recipients written to %s.\n",
+			expected: map[string]string{
+				"token": "value of namespace/repo/prod/token",
+			},
+		},
+		"secret version": {
+			path:       "namespace/repo/prod/token:1",
+			recipients: []string{alice.Recipient().String()},
+			out:        "Export complete! 1 secret encrypted to 1 recipient written to %s.\n",
+			expected: map[string]string{
+				"token": "value of namespace/repo/prod/token:1",
+			},
+		},
+		"recipients file": {
+			path:           "namespace/repo/prod/token",
+			recipientsFile: "# Alice\n" + alice.Recipient().String() + "\n\n",
+			out:            "Export complete! 1 secret encrypted to 1 recipient written to %s.\n",
+			expected: map[string]string{
+				"token": "value of namespace/repo/prod/token",
+			},
+		},
+		"not found": {
+			path:       "namespace/repo/prod/missing",
+			recipients: []string{alice.Recipient().String()},
+			err:        ErrResourceNotFound("namespace/repo/prod/missing"),
+		},
+		"no recipients": {
+			path: "namespace/repo/prod",
+			err:  ErrNoAgeRecipients,
+		},
+		"invalid recipient": {
+			path:       "namespace/repo/prod",
+			recipients: []string{"age1invalid"},
+			err:        ErrInvalidAgeRecipient("age1invalid"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			io := fakeui.NewIO(t)
+			cmd := ExportAgeCommand{
+				io:         io,
+				path:       tc.path,
+				out:        filepath.Join(dir, "secrets.zip.age"),
+				recipients: tc.recipients,
+				newClient:  newClient,
+			}
+			if tc.recipientsFile != "" {
+				cmd.recipientsFile = filepath.Join(dir, "recipients.txt")
+				assert.OK(t, ioutil.WriteFile(cmd.recipientsFile, []byte(tc.recipientsFile), 0600))
+			}
+
+			err := cmd.Run()
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				_, err = os.Stat(cmd.out)
+				assert.Equal(t, os.IsNotExist(err), true)
+				return
+			}
+			assert.Equal(t, io.Out.String(), fmt.Sprintf(tc.out, cmd.out))
+
+			encrypted, err := ioutil.ReadFile(cmd.out)
+			assert.OK(t, err)
+			decrypted, err := ageDecrypt(bytes.NewReader(encrypted), alice)
+			assert.OK(t, err)
+
+			archive, err := zip.NewReader(bytes.NewReader(decrypted), int64(len(decrypted)))
+			assert.OK(t, err)
+
+			actual := make(map[string]string)
+			for _, file := range archive.File {
+				r, err := file.Open()
+				assert.OK(t, err)
+				data, err := ioutil.ReadAll(r)
+				assert.OK(t, err)
+				actual[file.Name] = string(data)
+			}
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package secrethub
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// ExportAWSSMCommand exports secrets to AWS Secrets Manager.
+type ExportAWSSMCommand struct {
+	io                ui.IO
+	dirPath           api.DirPath
+	prefix            string
+	region            string
+	kmsKeyID          string
+	onConflict        string
+	newClient         newClientFunc
+	newSecretsManager newSecretsManagerFunc
+}
+
+// NewExportAWSSMCommand creates a new ExportAWSSMCommand.
+func NewExportAWSSMCommand(io ui.IO, newClient newClientFunc) *ExportAWSSMCommand {
+	return &ExportAWSSMCommand{
+		io:                io,
+		newClient:         newClient,
+		newSecretsManager: newAWSSecretsManager,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExportAWSSMCommand) Register(r command.Registerer) {
+	clause := r.Command("aws-sm", "Export the latest version of all secrets in a directory to AWS Secrets Manager, using the AWS credentials and configuration of the environment. "+
+		"Every secret is stored in an AWS secret named after the prefix followed by the path of the secret relative to the directory.")
+	clause.Arg("dir-path", "The directory to export the secrets from").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.dirPath)
+	clause.Flag("prefix", "The prefix for the names of the AWS secrets, e.g. /prod/.").StringVar(&cmd.prefix)
+	clause.Flag("region", "The AWS region to export the secrets to. Defaults to the region in the AWS configuration.").StringVar(&cmd.region)
+	clause.Flag("kms-key-id", "The KMS key to encrypt new AWS secrets with. Defaults to the AWS managed key of Secrets Manager.").StringVar(&cmd.kmsKeyID)
+	clause.Flag("on-conflict", "What to do with AWS secrets that already exist: skip them or overwrite them by storing the exported value as a new version.").HintOptions(conflictSkip, conflictOverwrite).Default(conflictSkip).StringVar(&cmd.onConflict)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run exports the secrets to AWS Secrets Manager.
+func (cmd *ExportAWSSMCommand) Run() error {
+	if cmd.onConflict != conflictSkip && cmd.onConflict != conflictOverwrite {
+		return ErrInvalidImportConflictStrategy(cmd.onConflict)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := readDirSecrets(client, cmd.dirPath, false)
+	if err != nil {
+		return err
+	}
+
+	sm, err := cmd.newSecretsManager(cmd.region)
+	if err != nil {
+		return err
+	}
+
+	counter := newProgressCounter("Exporting secrets", len(secrets))
+	defer counter.Finish()
+
+	var written, skipped int
+	for _, secret := range secrets {
+		name := cmd.prefix + secret.Name
+
+		input := &secretsmanager.CreateSecretInput{
+			Name: aws.String(name),
+		}
+		if cmd.kmsKeyID != "" {
+			input.KmsKeyId = aws.String(cmd.kmsKeyID)
+		}
+		// Secrets Manager only shows string values in the console, so binary values are only used when needed.
+		if utf8.Valid(secret.Value) {
+			input.SecretString = aws.String(string(secret.Value))
+		} else {
+			input.SecretBinary = secret.Value
+		}
+
+		_, err := sm.CreateSecret(input)
+		if errAWS, ok := err.(awserr.Error); ok && errAWS.Code() == secretsmanager.ErrCodeResourceExistsException {
+			if cmd.onConflict == conflictSkip {
+				fmt.Fprintf(cmd.io.Output(), "Skipped %s: the AWS secret already exists\n", name)
+				skipped++
+				counter.Done()
+				continue
+			}
+
+			_, err = sm.PutSecretValue(&secretsmanager.PutSecretValueInput{
+				SecretId:     input.Name,
+				SecretString: input.SecretString,
+				SecretBinary: input.SecretBinary,
+			})
+		}
+		if err != nil {
+			return handleAWSSecretsManagerErr(err)
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "Wrote %s\n", name)
+		written++
+		counter.Done()
+	}
+	counter.Finish()
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Export complete! Secrets: %d written, %d skipped.\n", written, skipped)
+	return nil
+}
@@ -0,0 +1,130 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+func TestExportAWSSMCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	rootDirID := uuid.New()
+	subDirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace/repo",
+		RootDir:    &api.Dir{DirID: rootDirID, Name: "prod"},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {DirID: rootDirID, Name: "prod"},
+			subDirID:  {DirID: subDirID, Name: "db", ParentID: &rootDirID},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{},
+	}
+	for _, secret := range []*api.Secret{
+		{SecretID: uuid.New(), DirID: rootDirID, Name: "token"},
+		{SecretID: uuid.New(), DirID: subDirID, Name: "password"},
+	} {
+		tree.Secrets[secret.SecretID] = secret
+	}
+
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			DirService: &fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return tree, nil
+				},
+			},
+			SecretService: &fakeclient.SecretService{
+				VersionService: &fakeclient.SecretVersionService{
+					GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+						return &api.SecretVersion{Data: []byte("value of " + path)}, nil
+					},
+				},
+			},
+		}, nil
+	}
+
+	cases := map[string]struct {
+		onConflict string
+		newClient  newClientFunc
+		out        string
+		expected   map[string]string
+		err        error
+	}{
+		"skip existing": {
+			onConflict: conflictSkip,
+			newClient:  newClient,
+			out: "Skipped /prod/db/password: the AWS secret already exists\n" +
+				"Wrote /prod/token\n" +
+				"Export complete! Secrets: 1 written, 1 skipped.\n",
+			expected: map[string]string{
+				"/prod/db/password": "old",
+				"/prod/token":       "value of namespace/repo/prod/token",
+			},
+		},
+		"overwrite existing": {
+			onConflict: conflictOverwrite,
+			newClient:  newClient,
+			out: "Wrote /prod/db/password\n" +
+				"Wrote /prod/token\n" +
+				"Export complete! Secrets: 2 written, 0 skipped.\n",
+			expected: map[string]string{
+				"/prod/db/password": "value of namespace/repo/prod/db/password",
+				"/prod/token":       "value of namespace/repo/prod/token",
+			},
+		},
+		"invalid conflict strategy": {
+			onConflict: "merge",
+			err:        ErrInvalidImportConflictStrategy("merge"),
+			expected: map[string]string{
+				"/prod/db/password": "old",
+			},
+		},
+		"client error": {
+			onConflict: conflictSkip,
+			newClient: func() (secrethub.ClientInterface, error) {
+				return nil, testErr
+			},
+			err: testErr,
+			expected: map[string]string{
+				"/prod/db/password": "old",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sm := &fakeSecretsManager{
+				names:   []string{"/prod/db/password"},
+				secrets: map[string]string{"/prod/db/password": "old"},
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := ExportAWSSMCommand{
+				io:         io,
+				dirPath:    api.DirPath("namespace/repo/prod"),
+				prefix:     "/prod/",
+				onConflict: tc.onConflict,
+				newClient:  tc.newClient,
+				newSecretsManager: func(region string) (secretsmanageriface.SecretsManagerAPI, error) {
+					return sm, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, sm.secrets, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package secrethub
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidAzureSecretName = errMain.Code("invalid_azure_secret_name").ErrorPref("cannot export %s: Azure secret names can only contain letters, numbers and dashes")
+	ErrAzureBinarySecret      = errMain.Code("azure_binary_secret").ErrorPref("cannot export %s: Azure Key Vault only stores text values")
+)
+
+var azureSecretNamePattern = regexp.MustCompile("^[0-9a-zA-Z-]{1,127}$")
+
+// ExportAzureKVCommand exports secrets to Azure Key Vault.
+type ExportAzureKVCommand struct {
+	io             ui.IO
+	dirPath        api.DirPath
+	vault          string
+	tags           map[string]string
+	recoverDeleted bool
+	onConflict     string
+	newClient      newClientFunc
+	newKeyVault    newAzureKeyVaultFunc
+}
+
+// NewExportAzureKVCommand creates a new ExportAzureKVCommand.
+func NewExportAzureKVCommand(io ui.IO, newClient newClientFunc) *ExportAzureKVCommand {
+	return &ExportAzureKVCommand{
+		io:          io,
+		tags:        make(map[string]string),
+		newClient:   newClient,
+		newKeyVault: newAzureKeyVault,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExportAzureKVCommand) Register(r command.Registerer) {
+	clause := r.Command("azure-kv", "Export the latest version of all secrets in a directory to an Azure Key Vault. "+
+		"Authenticates with the service principal in AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET or else with the Azure CLI. "+
+		"The name of every Azure secret is the path of the secret relative to the directory, with every slash replaced by "+azurePathSeparator+".")
+	clause.Arg("dir-path", "The directory to export the secrets from").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.dirPath)
+	clause.Flag("vault", "The name or URL of the Azure Key Vault to export the secrets to.").Required().StringVar(&cmd.vault)
+	clause.Flag("tag", "Add this tag, given as `KEY=VALUE`, to the exported Azure secrets. Can be repeated.").StringMapVar(&cmd.tags)
+	clause.Flag("recover-deleted", "Recover Azure secrets that are soft-deleted in the vault and write the exported value as a new version. By default, these secrets are skipped.").BoolVar(&cmd.recoverDeleted)
+	clause.Flag("on-conflict", "What to do with Azure secrets that already exist: skip them or overwrite them by writing the exported value as a new version.").HintOptions(conflictSkip, conflictOverwrite).Default(conflictSkip).StringVar(&cmd.onConflict)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run exports the secrets to Azure Key Vault.
+func (cmd *ExportAzureKVCommand) Run() error {
+	if cmd.onConflict != conflictSkip && cmd.onConflict != conflictOverwrite {
+		return ErrInvalidImportConflictStrategy(cmd.onConflict)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := readDirSecrets(client, cmd.dirPath, false)
+	if err != nil {
+		return err
+	}
+
+	// All names are checked before anything is written, so that an export never fails halfway on invalid input.
+	for _, secret := range secrets {
+		if !azureSecretNamePattern.MatchString(strings.Replace(secret.Name, "/", azurePathSeparator, -1)) {
+			return ErrInvalidAzureSecretName(secret.Name)
+		}
+		if !utf8.Valid(secret.Value) {
+			return ErrAzureBinarySecret(secret.Name)
+		}
+	}
+
+	kv, err := cmd.newKeyVault(cmd.vault)
+	if err != nil {
+		return err
+	}
+
+	azureSecrets, err := kv.ListSecrets()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(azureSecrets))
+	for _, azureSecret := range azureSecrets {
+		// Azure secret names are case-insensitive.
+		existing[strings.ToLower(azureSecret.Name)] = true
+	}
+
+	counter := newProgressCounter("Exporting secrets", len(secrets))
+	defer counter.Finish()
+
+	var written, skipped int
+	for _, secret := range secrets {
+		name := strings.Replace(secret.Name, "/", azurePathSeparator, -1)
+		if existing[strings.ToLower(name)] && cmd.onConflict == conflictSkip {
+			fmt.Fprintf(cmd.io.Output(), "Skipped %s: the Azure secret already exists\n", name)
+			skipped++
+			counter.Done()
+			continue
+		}
+
+		err = kv.SetSecret(name, string(secret.Value), cmd.tags)
+		if err == errAzureSecretDeleted {
+			if !cmd.recoverDeleted {
+				fmt.Fprintf(cmd.io.Output(), "Skipped %s: the Azure secret is deleted but recoverable, use --recover-deleted to recover it\n", name)
+				skipped++
+				counter.Done()
+				continue
+			}
+
+			err = kv.RecoverSecret(name)
+			if err != nil {
+				return err
+			}
+			err = kv.SetSecret(name, string(secret.Value), cmd.tags)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "Wrote %s\n", name)
+		written++
+		counter.Done()
+	}
+	counter.Finish()
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Export complete! Secrets: %d written, %d skipped.\n", written, skipped)
+	return nil
+}
@@ -0,0 +1,174 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestExportAzureKVCommand_Run(t *testing.T) {
+	newClient := func(names ...string) newClientFunc {
+		rootDirID := uuid.New()
+		tree := &api.Tree{
+			ParentPath: "namespace",
+			RootDir:    &api.Dir{DirID: rootDirID, Name: "repo"},
+			Dirs: map[uuid.UUID]*api.Dir{
+				rootDirID: {DirID: rootDirID, Name: "repo"},
+			},
+			Secrets: map[uuid.UUID]*api.Secret{},
+		}
+		for _, name := range names {
+			secretID := uuid.New()
+			tree.Secrets[secretID] = &api.Secret{SecretID: secretID, DirID: rootDirID, Name: name}
+		}
+
+		return func() (secrethub.ClientInterface, error) {
+			return fakeclient.Client{
+				DirService: &fakeclient.DirService{
+					GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+						return tree, nil
+					},
+				},
+				SecretService: &fakeclient.SecretService{
+					VersionService: &fakeclient.SecretVersionService{
+						GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+							return &api.SecretVersion{Data: []byte("new")}, nil
+						},
+					},
+				},
+			}, nil
+		}
+	}
+
+	cases := map[string]struct {
+		recoverDeleted bool
+		onConflict     string
+		newClient      newClientFunc
+		out            string
+		values         map[string]string
+		recovered      []string
+		err            error
+	}{
+		"skip existing and deleted": {
+			onConflict: conflictSkip,
+			newClient:  newClient("existing", "deleted", "token"),
+			out: "Skipped deleted: the Azure secret is deleted but recoverable, use --recover-deleted to recover it\n" +
+				"Skipped existing: the Azure secret already exists\n" +
+				"Wrote token\n" +
+				"Export complete! Secrets: 1 written, 2 skipped.\n",
+			values: map[string]string{"existing": "old", "token": "new"},
+		},
+		"overwrite and recover": {
+			recoverDeleted: true,
+			onConflict:     conflictOverwrite,
+			newClient:      newClient("existing", "deleted"),
+			out: "Wrote deleted\n" +
+				"Wrote existing\n" +
+				"Export complete! Secrets: 2 written, 0 skipped.\n",
+			values:    map[string]string{"existing": "new", "deleted": "new"},
+			recovered: []string{"deleted"},
+		},
+		"invalid name": {
+			onConflict: conflictSkip,
+			newClient:  newClient("db_password"),
+			values:     map[string]string{"existing": "old"},
+			err:        ErrInvalidAzureSecretName("db_password"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kv := &fakeAzureKeyVault{
+				secrets: []azureSecret{{Name: "Existing", Enabled: true}},
+				values:  map[string]string{"existing": "old"},
+				deleted: map[string]bool{"deleted": true},
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := ExportAzureKVCommand{
+				io:             io,
+				dirPath:        api.DirPath("namespace/repo"),
+				vault:          "myvault",
+				recoverDeleted: tc.recoverDeleted,
+				onConflict:     tc.onConflict,
+				newClient:      tc.newClient,
+				newKeyVault: func(vault string) (azureKeyVault, error) {
+					return kv, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, kv.values, tc.values)
+			assert.Equal(t, kv.recovered, tc.recovered)
+		})
+	}
+}
+
+func TestAzureKeyVaultClient(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Authorization"), "Bearer token")
+		assert.Equal(t, r.URL.Query().Get("api-version"), azureKeyVaultAPIVersion)
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/secrets" && r.URL.Query().Get("page") == "":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"value":    []map[string]interface{}{{"id": server.URL + "/secrets/a", "attributes": map[string]bool{"enabled": true}}},
+				"nextLink": server.URL + "/secrets?api-version=" + azureKeyVaultAPIVersion + "&page=2",
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/secrets":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"value": []map[string]interface{}{{"id": server.URL + "/secrets/b", "managed": true, "tags": map[string]string{"k": "v"}, "attributes": map[string]bool{"enabled": false}}},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/secrets/a":
+			_ = json.NewEncoder(w).Encode(map[string]string{"value": "secret"})
+		case r.Method == http.MethodPut && r.URL.Path == "/secrets/deleted":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"code":"Conflict","message":"deleted","innererror":{"code":"ObjectIsDeletedButRecoverable"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":"SecretNotFound","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	kv := azureKeyVaultClient{
+		client:   server.Client(),
+		vaultURL: server.URL,
+		token:    "token",
+	}
+
+	secrets, err := kv.ListSecrets()
+	assert.OK(t, err)
+	assert.Equal(t, secrets, []azureSecret{
+		{Name: "a", Enabled: true},
+		{Name: "b", Enabled: false, Managed: true, Tags: map[string]string{"k": "v"}},
+	})
+
+	value, err := kv.GetSecret("a")
+	assert.OK(t, err)
+	assert.Equal(t, value, "secret")
+
+	_, err = kv.GetSecret("missing")
+	assert.Equal(t, err, ErrAzureRequestFailed("SecretNotFound: not found"))
+
+	err = kv.SetSecret("deleted", "value", nil)
+	assert.Equal(t, err, errAzureSecretDeleted)
+}
+
+func TestAzureVaultURL(t *testing.T) {
+	assert.Equal(t, azureVaultURL("myvault"), "https://myvault.vault.azure.net")
+	assert.Equal(t, azureVaultURL("https://myvault.vault.azure.cn/"), "https://myvault.vault.azure.cn")
+}
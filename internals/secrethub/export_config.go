@@ -0,0 +1,386 @@
+package secrethub
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrInvalidConfigFormat  = errMain.Code("invalid_config_format").ErrorPref("invalid format %q: must be one of properties, toml or ini")
+	ErrNoConfigKeys         = errMain.Code("no_config_keys").Error("no keys given: use --key or --mapping-file")
+	ErrInvalidConfigMapping = errMain.Code("invalid_config_mapping").ErrorPref("invalid mapping %q: must be of the form key=<secret-path>")
+	ErrInvalidConfigKey     = errMain.Code("invalid_config_key").ErrorPref("key %q cannot be used in the %s format: %s")
+	ErrConfigBinarySecret   = errMain.Code("config_binary_secret").ErrorPref("cannot export %s: the secret is not valid UTF-8 text")
+)
+
+// The formats of configuration files that secrets can be exported to.
+const (
+	configFormatProperties = "properties"
+	configFormatTOML       = "toml"
+	configFormatINI        = "ini"
+)
+
+// configWriters write configuration files in the supported formats.
+var configWriters = map[string]func(w io.Writer, entries []configEntry) error{
+	configFormatProperties: writeProperties,
+	configFormatTOML:       writeTOML,
+	configFormatINI:        writeINI,
+}
+
+// configEntry is a key of a configuration file with the value of the secret it is mapped to.
+// A key can contain dots to place it in a section, e.g. database.password is the key password in
+// the section database. Everything before the last dot is the section.
+type configEntry struct {
+	Key   string
+	Value string
+}
+
+// section returns the section of the key and the name of the key within the section.
+func (e configEntry) section() (string, string) {
+	i := strings.LastIndex(e.Key, ".")
+	if i < 0 {
+		return "", e.Key
+	}
+	return e.Key[:i], e.Key[i+1:]
+}
+
+// ExportConfigCommand exports secrets to a Java properties, TOML or INI configuration file.
+type ExportConfigCommand struct {
+	io          ui.IO
+	format      string
+	keys        map[string]string
+	mappingFile string
+	out         string
+	newClient   newClientFunc
+}
+
+// NewExportConfigCommand creates a new ExportConfigCommand.
+func NewExportConfigCommand(io ui.IO, newClient newClientFunc) *ExportConfigCommand {
+	return &ExportConfigCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExportConfigCommand) Register(r command.Registerer) {
+	clause := r.Command("config", "Export secrets to a Java .properties, TOML or INI configuration file, with every key set to the value of the secret it is mapped to. "+
+		"Keys with dots are placed in sections: everything before the last dot is the section, e.g. database.password is password in the section database. "+
+		"Values are escaped as required by the format.")
+	clause.Flag("format", "The format of the configuration file: properties, toml or ini.").Required().HintOptions(configFormatProperties, configFormatTOML, configFormatINI).StringVar(&cmd.format)
+	clause.Flag("key", "Set a key to the value of a secret with `key=<path>`. Can be repeated.").StringMapVar(&cmd.keys)
+	clause.Flag("mapping-file", "A file with a `key=<path>` mapping on every line. Empty lines and lines starting with # are ignored.").ExistingFileVar(&cmd.mappingFile)
+	clause.Flag("out", "Write the configuration file to this path instead of printing it.").StringVar(&cmd.out)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run exports the secrets.
+func (cmd *ExportConfigCommand) Run() error {
+	write, ok := configWriters[cmd.format]
+	if !ok {
+		return ErrInvalidConfigFormat(cmd.format)
+	}
+
+	mapping, err := cmd.mapping()
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	entries, err := readConfigEntries(client, mapping)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = write(&buf, entries)
+	if err != nil {
+		return err
+	}
+
+	if cmd.out == "" {
+		_, err = cmd.io.Output().Write(buf.Bytes())
+		return err
+	}
+
+	err = ioutil.WriteFile(cmd.out, buf.Bytes(), configFileMode)
+	if err != nil {
+		return ErrCannotWrite(cmd.out, err)
+	}
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Export complete! %s written to %s.\n", pluralize("key", "keys", len(entries)), cmd.out)
+	return nil
+}
+
+// mapping returns the keys mapped to secret paths with the flags and the mapping file.
+func (cmd *ExportConfigCommand) mapping() (map[string]string, error) {
+	mapping := map[string]string{}
+	if cmd.mappingFile != "" {
+		file, err := os.Open(cmd.mappingFile)
+		if err != nil {
+			return nil, ErrCannotReadFile(cmd.mappingFile, err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				return nil, ErrInvalidConfigMapping(line)
+			}
+			mapping[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+		err = scanner.Err()
+		if err != nil {
+			return nil, ErrCannotReadFile(cmd.mappingFile, err)
+		}
+	}
+
+	for key, path := range cmd.keys {
+		mapping[key] = path
+	}
+
+	if len(mapping) == 0 {
+		return nil, ErrNoConfigKeys
+	}
+	for key, path := range mapping {
+		if key == "" || api.ValidateSecretPath(path) != nil {
+			return nil, ErrInvalidConfigMapping(key + "=" + path)
+		}
+	}
+	return mapping, nil
+}
+
+// readConfigEntries reads the secrets of the mapping and returns the entries sorted by key.
+func readConfigEntries(client secrethub.ClientInterface, mapping map[string]string) ([]configEntry, error) {
+	values := map[string]string{}
+	entries := make([]configEntry, 0, len(mapping))
+
+	counter := newProgressCounter("Reading secrets", len(mapping))
+	defer counter.Finish()
+
+	for key, path := range mapping {
+		value, ok := values[path]
+		if !ok {
+			version, err := client.Secrets().Versions().GetWithData(path)
+			if err != nil {
+				return nil, err
+			}
+			if !utf8.Valid(version.Data) {
+				return nil, ErrConfigBinarySecret(path)
+			}
+			value = string(version.Data)
+			values[path] = value
+		}
+		entries = append(entries, configEntry{Key: key, Value: value})
+		counter.Done()
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
+
+// writeProperties writes the entries as a Java .properties file, escaped like
+// java.util.Properties does, so the file is valid in the ISO 8859-1 encoding.
+func writeProperties(w io.Writer, entries []configEntry) error {
+	for _, entry := range entries {
+		_, err := fmt.Fprintf(w, "%s=%s\n", escapeProperty(entry.Key, true), escapeProperty(entry.Value, false))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeProperty escapes a key or value of a .properties file. All spaces of keys are escaped,
+// but only the leading space of values, as other spaces in values are preserved when reading.
+func escapeProperty(s string, isKey bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r == ' ' && (isKey || i == 0):
+			sb.WriteString(`\ `)
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case r == '=' || r == ':' || r == '#' || r == '!':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&sb, `\u%04X`, unit)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// writeTOML writes the entries as a TOML file, with the keys without a section first,
+// followed by a table for every section.
+func writeTOML(w io.Writer, entries []configEntry) error {
+	keys := map[string]bool{}
+	for _, entry := range entries {
+		keys[entry.Key] = true
+	}
+	for _, entry := range entries {
+		for _, part := range strings.Split(entry.Key, ".") {
+			if part == "" {
+				return ErrInvalidConfigKey(entry.Key, configFormatTOML, "keys and sections cannot be empty")
+			}
+		}
+		// A key cannot be both a value and a table.
+		for prefix := entry.Key; strings.Contains(prefix, "."); {
+			prefix = prefix[:strings.LastIndex(prefix, ".")]
+			if keys[prefix] {
+				return ErrInvalidConfigKey(prefix, configFormatTOML, "it is also used as the section of "+entry.Key)
+			}
+		}
+	}
+
+	return writeSections(w, entries, func(section string) string {
+		parts := strings.Split(section, ".")
+		for i, part := range parts {
+			parts[i] = tomlKey(part)
+		}
+		return "[" + strings.Join(parts, ".") + "]"
+	}, func(key string, value string) string {
+		return tomlKey(key) + " = " + tomlString(value)
+	})
+}
+
+// tomlKey returns the key as a bare key when possible and as a quoted key otherwise.
+func tomlKey(key string) string {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+// tomlString returns s as a TOML basic string.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			sb.WriteString(`\"`)
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\b':
+			sb.WriteString(`\b`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&sb, `\u%04X`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// writeINI writes the entries as an INI file, with the keys without a section first,
+// followed by the sections. Values that would otherwise be read differently are quoted.
+func writeINI(w io.Writer, entries []configEntry) error {
+	for _, entry := range entries {
+		section, key := entry.section()
+		if key == "" || strings.ContainsAny(key, "=:;#[]\"\\\n\r") || strings.TrimSpace(key) != key {
+			return ErrInvalidConfigKey(entry.Key, configFormatINI, "keys cannot be empty, contain any of =:;#[]\"\\, line breaks or leading or trailing spaces")
+		}
+		if strings.ContainsAny(section, "[]\n\r") || strings.TrimSpace(section) != section {
+			return ErrInvalidConfigKey(entry.Key, configFormatINI, "sections cannot contain [, ], line breaks or leading or trailing spaces")
+		}
+	}
+
+	return writeSections(w, entries, func(section string) string {
+		return "[" + section + "]"
+	}, func(key string, value string) string {
+		return key + " = " + iniValue(value)
+	})
+}
+
+// iniValue returns the value as is when INI parsers read it unchanged and quoted otherwise.
+func iniValue(s string) string {
+	if s == "" || (strings.TrimSpace(s) == s && !strings.ContainsAny(s, ";#\"\\\n\r\t")) {
+		return s
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// writeSections writes the entries without a section, followed by every section
+// with a header and its entries, separated by empty lines.
+func writeSections(w io.Writer, entries []configEntry, header func(section string) string, line func(key string, value string) string) error {
+	var sections []string
+	bySection := map[string][]string{}
+	for _, entry := range entries {
+		section, key := entry.section()
+		if _, ok := bySection[section]; !ok && section != "" {
+			sections = append(sections, section)
+		}
+		bySection[section] = append(bySection[section], line(key, entry.Value))
+	}
+	sort.Strings(sections)
+
+	var sb strings.Builder
+	for _, l := range bySection[""] {
+		sb.WriteString(l + "\n")
+	}
+	for _, section := range sections {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(header(section) + "\n")
+		for _, l := range bySection[section] {
+			sb.WriteString(l + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,218 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestWriteConfig(t *testing.T) {
+	entries := []configEntry{
+		{Key: "app.name", Value: "My App"},
+		{Key: "database.password", Value: ` p@ss="word"#1\n`},
+		{Key: "database.url", Value: "jdbc:postgresql://db:5432/app"},
+		{Key: "greeting", Value: "Grüße\tdir 😀\n"},
+	}
+
+	cases := map[string]struct {
+		format   string
+		entries  []configEntry
+		expected string
+		err      error
+	}{
+		"properties": {
+			format:  configFormatProperties,
+			entries: entries,
+			expected: "app.name=My App\n" +
+				`database.password=\ p@ss\="word"\#1\\n` + "\n" +
+				`database.url=jdbc\:postgresql\://db\:5432/app` + "\n" +
+				`greeting=Gr\u00FC\u00DFe\tdir \uD83D\uDE00\n` + "\n",
+		},
+		"properties key with spaces": {
+			format:   configFormatProperties,
+			entries:  []configEntry{{Key: "my key", Value: "a b"}},
+			expected: `my\ key=a b` + "\n",
+		},
+		"toml": {
+			format:  configFormatTOML,
+			entries: entries,
+			expected: "greeting = \"Grüße\\tdir 😀\\n\"\n" +
+				"\n" +
+				"[app]\n" +
+				"name = \"My App\"\n" +
+				"\n" +
+				"[database]\n" +
+				`password = " p@ss=\"word\"#1\\n"` + "\n" +
+				`url = "jdbc:postgresql://db:5432/app"` + "\n",
+		},
+		"toml quoted keys": {
+			format:   configFormatTOML,
+			entries:  []configEntry{{Key: "my app.api key", Value: "\x00"}},
+			expected: "[\"my app\"]\n\"api key\" = \"\\u0000\"\n",
+		},
+		"toml key used as section": {
+			format:  configFormatTOML,
+			entries: []configEntry{{Key: "database", Value: "a"}, {Key: "database.url", Value: "b"}},
+			err:     ErrInvalidConfigKey("database", configFormatTOML, "it is also used as the section of database.url"),
+		},
+		"toml empty section": {
+			format:  configFormatTOML,
+			entries: []configEntry{{Key: "database..url", Value: "a"}},
+			err:     ErrInvalidConfigKey("database..url", configFormatTOML, "keys and sections cannot be empty"),
+		},
+		"ini": {
+			format:  configFormatINI,
+			entries: entries,
+			expected: "greeting = \"Grüße\\tdir 😀\\n\"\n" +
+				"\n" +
+				"[app]\n" +
+				"name = My App\n" +
+				"\n" +
+				"[database]\n" +
+				`password = " p@ss=\"word\"#1\\n"` + "\n" +
+				"url = jdbc:postgresql://db:5432/app\n",
+		},
+		"ini invalid key": {
+			format:  configFormatINI,
+			entries: []configEntry{{Key: "a=b", Value: "a"}},
+			err:     ErrInvalidConfigKey("a=b", configFormatINI, "keys cannot be empty, contain any of =:;#[]\"\\, line breaks or leading or trailing spaces"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := configWriters[tc.format](&buf, tc.entries)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestExportConfigCommand_Run(t *testing.T) {
+	secrets := map[string]string{
+		"namespace/repo/db/password": "secret",
+		"namespace/repo/db/url":      "jdbc:postgresql://db/app",
+		"namespace/repo/binary":      "\xff\xfe",
+	}
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			SecretService: &fakeclient.SecretService{
+				VersionService: &fakeclient.SecretVersionService{
+					GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+						value, ok := secrets[path]
+						if !ok {
+							return nil, api.ErrSecretNotFound
+						}
+						return &api.SecretVersion{Data: []byte(value)}, nil
+					},
+				},
+			},
+		}, nil
+	}
+
+	cases := map[string]struct {
+		format   string
+		keys     map[string]string
+		mapping  string
+		toFile   bool
+		out      string
+		expected string
+		err      error
+	}{
+		"keys": {
+			format: configFormatProperties,
+			keys: map[string]string{
+				"db.url":      "namespace/repo/db/url",
+				"db.password": "namespace/repo/db/password",
+			},
+			out: "db.password=secret\n" +
+				`db.url=jdbc\:postgresql\://db/app` + "\n",
+		},
+		"mapping file": {
+			format: configFormatINI,
+			mapping: "# Database\n" +
+				"db.url = namespace/repo/db/url\n" +
+				"\n" +
+				"db.password=namespace/repo/db/password\n",
+			keys: map[string]string{
+				"db.password": "namespace/repo/db/url",
+			},
+			toFile: true,
+			out:    "Export complete! 2 keys written to %s.\n",
+			expected: "[db]\n" +
+				"password = jdbc:postgresql://db/app\n" +
+				"url = jdbc:postgresql://db/app\n",
+		},
+		"invalid format": {
+			format: "yaml",
+			err:    ErrInvalidConfigFormat("yaml"),
+		},
+		"no keys": {
+			format: configFormatTOML,
+			err:    ErrNoConfigKeys,
+		},
+		"invalid mapping": {
+			format:  configFormatTOML,
+			mapping: "db.url\n",
+			err:     ErrInvalidConfigMapping("db.url"),
+		},
+		"binary secret": {
+			format: configFormatTOML,
+			keys:   map[string]string{"binary": "namespace/repo/binary"},
+			err:    ErrConfigBinarySecret("namespace/repo/binary"),
+		},
+		"secret not found": {
+			format: configFormatTOML,
+			keys:   map[string]string{"missing": "namespace/repo/missing"},
+			err:    api.ErrSecretNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			io := fakeui.NewIO(t)
+			cmd := ExportConfigCommand{
+				io:        io,
+				format:    tc.format,
+				keys:      tc.keys,
+				newClient: newClient,
+			}
+			if tc.mapping != "" {
+				cmd.mappingFile = filepath.Join(dir, "mapping.txt")
+				assert.OK(t, ioutil.WriteFile(cmd.mappingFile, []byte(tc.mapping), 0600))
+			}
+			if tc.toFile {
+				cmd.out = filepath.Join(dir, "app.ini")
+			}
+
+			err := cmd.Run()
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+
+			if !tc.toFile {
+				assert.Equal(t, io.Out.String(), tc.out)
+				return
+			}
+			assert.Equal(t, io.Out.String(), fmt.Sprintf(tc.out, cmd.out))
+			data, err := ioutil.ReadFile(cmd.out)
+			assert.OK(t, err)
+			assert.Equal(t, string(data), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// ExportGCPSMCommand exports secrets to GCP Secret Manager.
+type ExportGCPSMCommand struct {
+	io               ui.IO
+	dirPath          api.DirPath
+	project          string
+	labels           map[string]string
+	allVersions      bool
+	onConflict       string
+	newClient        newClientFunc
+	newSecretManager newGCPSecretManagerFunc
+}
+
+// NewExportGCPSMCommand creates a new ExportGCPSMCommand.
+func NewExportGCPSMCommand(io ui.IO, newClient newClientFunc) *ExportGCPSMCommand {
+	return &ExportGCPSMCommand{
+		io:               io,
+		labels:           make(map[string]string),
+		newClient:        newClient,
+		newSecretManager: newGCPSecretManager,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExportGCPSMCommand) Register(r command.Registerer) {
+	clause := r.Command("gcp-sm", "Export all secrets in a directory to GCP Secret Manager, authenticating with the application default credentials. "+
+		"The ID of every GCP secret is the path of the secret relative to the directory, with every slash replaced by "+gcpPathSeparator+".")
+	clause.Arg("dir-path", "The directory to export the secrets from").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.dirPath)
+	clause.Flag("project", "The ID of the GCP project to export the secrets to.").Required().StringVar(&cmd.project)
+	clause.Flag("label", "Add this label, given as `KEY=VALUE`, to the GCP secrets that are created. Can be repeated.").StringMapVar(&cmd.labels)
+	clause.Flag("all-versions", "Export all versions of the secrets, oldest first, instead of only the latest version.").BoolVar(&cmd.allVersions)
+	clause.Flag("on-conflict", "What to do with GCP secrets that already exist: skip them or overwrite them by adding the exported values as new versions.").HintOptions(conflictSkip, conflictOverwrite).Default(conflictSkip).StringVar(&cmd.onConflict)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run exports the secrets to GCP Secret Manager.
+func (cmd *ExportGCPSMCommand) Run() error {
+	if cmd.onConflict != conflictSkip && cmd.onConflict != conflictOverwrite {
+		return ErrInvalidImportConflictStrategy(cmd.onConflict)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := readDirSecrets(client, cmd.dirPath, cmd.allVersions)
+	if err != nil {
+		return err
+	}
+
+	sm, err := cmd.newSecretManager()
+	if err != nil {
+		return err
+	}
+
+	// The versions of a secret follow each other, so the GCP secret is only created for the first one.
+	written := map[string]bool{}
+	skipped := map[string]bool{}
+
+	counter := newProgressCounter("Exporting secrets", len(secrets))
+	defer counter.Finish()
+
+	for _, secret := range secrets {
+		secretID := strings.Replace(secret.Name, "/", gcpPathSeparator, -1)
+		if skipped[secretID] {
+			counter.Done()
+			continue
+		}
+
+		if !written[secretID] {
+			err = sm.CreateSecret(cmd.project, secretID, cmd.labels)
+			if err == errGCPSecretExists && cmd.onConflict == conflictSkip {
+				fmt.Fprintf(cmd.io.Output(), "Skipped %s: the GCP secret already exists\n", secretID)
+				skipped[secretID] = true
+				counter.Done()
+				continue
+			}
+			if err != nil && err != errGCPSecretExists {
+				return err
+			}
+		}
+
+		err = sm.AddVersion(cmd.project, secretID, secret.Value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Wrote %s\n", secretID)
+		written[secretID] = true
+		counter.Done()
+	}
+	counter.Finish()
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Export complete! Secrets: %d written, %d skipped.\n", len(written), len(skipped))
+	return nil
+}
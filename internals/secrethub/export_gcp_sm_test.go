@@ -0,0 +1,139 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestExportGCPSMCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	rootDirID := uuid.New()
+	subDirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		RootDir:    &api.Dir{DirID: rootDirID, Name: "repo"},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {DirID: rootDirID, Name: "repo"},
+			subDirID:  {DirID: subDirID, Name: "db", ParentID: &rootDirID},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{},
+	}
+	for _, secret := range []*api.Secret{
+		{SecretID: uuid.New(), DirID: rootDirID, Name: "token"},
+		{SecretID: uuid.New(), DirID: subDirID, Name: "password"},
+	} {
+		tree.Secrets[secret.SecretID] = secret
+	}
+
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			DirService: &fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return tree, nil
+				},
+			},
+			SecretService: &fakeclient.SecretService{
+				VersionService: &fakeclient.SecretVersionService{
+					GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+						return &api.SecretVersion{Version: 2, Data: []byte(path + " v2")}, nil
+					},
+					ListWithDataFunc: func(path string) ([]*api.SecretVersion, error) {
+						return []*api.SecretVersion{
+							{Version: 2, Data: []byte(path + " v2")},
+							{Version: 1, Data: []byte(path + " v1")},
+						}, nil
+					},
+				},
+			},
+		}, nil
+	}
+
+	cases := map[string]struct {
+		allVersions bool
+		onConflict  string
+		newClient   newClientFunc
+		out         string
+		expected    map[string][]string
+		err         error
+	}{
+		"skip existing": {
+			onConflict: conflictSkip,
+			newClient:  newClient,
+			out: "Skipped db__password: the GCP secret already exists\n" +
+				"Wrote token\n" +
+				"Export complete! Secrets: 1 written, 1 skipped.\n",
+			expected: map[string][]string{
+				"db__password": {"old"},
+				"token":        {"namespace/repo/token v2"},
+			},
+		},
+		"overwrite all versions": {
+			allVersions: true,
+			onConflict:  conflictOverwrite,
+			newClient:   newClient,
+			out: "Wrote db__password\n" +
+				"Wrote db__password\n" +
+				"Wrote token\n" +
+				"Wrote token\n" +
+				"Export complete! Secrets: 2 written, 0 skipped.\n",
+			expected: map[string][]string{
+				"db__password": {"old", "namespace/repo/db/password v1", "namespace/repo/db/password v2"},
+				"token":        {"namespace/repo/token v1", "namespace/repo/token v2"},
+			},
+		},
+		"invalid conflict strategy": {
+			onConflict: "merge",
+			err:        ErrInvalidImportConflictStrategy("merge"),
+			expected: map[string][]string{
+				"db__password": {"old"},
+			},
+		},
+		"client error": {
+			onConflict: conflictSkip,
+			newClient: func() (secrethub.ClientInterface, error) {
+				return nil, testErr
+			},
+			err: testErr,
+			expected: map[string][]string{
+				"db__password": {"old"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sm := &fakeGCPSecretManager{
+				labels:   map[string]map[string]string{},
+				versions: map[string][]string{"db__password": {"old"}},
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := ExportGCPSMCommand{
+				io:          io,
+				dirPath:     api.DirPath("namespace/repo"),
+				project:     "my-project",
+				allVersions: tc.allVersions,
+				onConflict:  tc.onConflict,
+				newClient:   tc.newClient,
+				newSecretManager: func() (gcpSecretManager, error) {
+					return sm, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, sm.versions, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package secrethub
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrSecretGCFailed = errMain.Code("secret_gc_failed").ErrorPref("could not remove %d expired secret(s)")
+)
+
+// GCCommand removes ephemeral secrets that have expired.
+type GCCommand struct {
+	dryRun    bool
+	io        ui.IO
+	secrets   func() ephemeralSecretStore
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewGCCommand creates a new GCCommand.
+func NewGCCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *GCCommand {
+	return &GCCommand{
+		io:        io,
+		newClient: newClient,
+		secrets: func() ephemeralSecretStore {
+			return newEphemeralSecretStore(credentialStore.ConfigDir())
+		},
+		now: time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *GCCommand) Register(r command.Registerer) {
+	clause := r.Command("gc", "Remove all ephemeral secrets written with `write --delete-after` that have expired. Run this periodically, e.g. from a cron job, to make sure temporary credentials are removed in time.")
+	clause.HelpLong("The secrets written with `write --delete-after` are tracked in the configuration directory, " +
+		"so gc removes the expired secrets that were written on this machine. " +
+		"Only the version that was written with --delete-after is removed, so the versions written before or since are kept.")
+	clause.Flag("dry-run", "Only print the expired secrets, without removing them.").BoolVar(&cmd.dryRun)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run removes all expired ephemeral secrets.
+func (cmd *GCCommand) Run() error {
+	store := cmd.secrets()
+	secrets, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	sweeper := expirySweeper{
+		io:          cmd.io,
+		newClient:   cmd.newClient,
+		dryRun:      cmd.dryRun,
+		noneExpired: "No expired secrets found.",
+		isExpired: func(i int) bool {
+			return secrets[i].isExpired(now)
+		},
+		describe: func(i int) string {
+			return fmt.Sprintf("%s expired at %s", secrets[i].versionPath(), secrets[i].ExpiresAt.Local().Format(time.RFC3339))
+		},
+		remove: func(client secrethub.ClientInterface, i int) bool {
+			return cmd.remove(client, secrets[i])
+		},
+		save: func(remaining []int) error {
+			kept := make([]ephemeralSecret, len(remaining))
+			for j, i := range remaining {
+				kept[j] = secrets[i]
+			}
+			return store.Save(kept)
+		},
+	}
+
+	failed, err := sweeper.sweep(len(secrets))
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return ErrSecretGCFailed(failed)
+	}
+	return nil
+}
+
+// remove removes the version of the secret that was written as ephemeral secret. The versions written
+// before or since are kept, so a secret that has been written again keeps its current value.
+// It returns false when the version could not be removed.
+func (cmd *GCCommand) remove(client secrethub.ClientInterface, secret ephemeralSecret) bool {
+	current, err := client.Secrets().Get(secret.Path)
+	if api.IsErrNotFound(err) {
+		fmt.Fprintf(cmd.io.Output(), "The secret %s has already been removed.\n", secret.Path)
+		return true
+	} else if err != nil {
+		fmt.Fprintf(cmd.io.Output(), "Could not get the secret %s: %s\n", secret.Path, err)
+		return false
+	}
+
+	// A secret that only has the ephemeral version was created by write --delete-after, so it is removed as a whole.
+	if current.VersionCount == 1 && current.LatestVersion == secret.Version {
+		err = client.Secrets().Delete(secret.Path)
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "Could not remove the secret %s: %s\n", secret.Path, err)
+			return false
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "Removed the expired secret %s.\n", secret.Path)
+		return true
+	}
+
+	err = client.Secrets().Versions().Delete(secret.versionPath())
+	if api.IsErrNotFound(err) {
+		fmt.Fprintf(cmd.io.Output(), "The secret version %s has already been removed.\n", secret.versionPath())
+		return true
+	} else if err != nil {
+		fmt.Fprintf(cmd.io.Output(), "Could not remove the secret version %s: %s\n", secret.versionPath(), err)
+		return false
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Removed the expired secret version %s.\n", secret.versionPath())
+	return true
+}
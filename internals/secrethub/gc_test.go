@@ -0,0 +1,165 @@
+package secrethub
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestGCCommand_Run(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	writtenAt := now.Add(-25 * time.Hour)
+
+	expiredSecret := ephemeralSecret{
+		Path:      "namespace/repo/handoff/password",
+		Version:   1,
+		WrittenAt: writtenAt,
+		ExpiresAt: now.Add(-time.Hour),
+	}
+	activeSecret := ephemeralSecret{
+		Path:      "namespace/repo/handoff/token",
+		Version:   3,
+		WrittenAt: writtenAt,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	cases := map[string]struct {
+		dryRun          bool
+		secrets         []ephemeralSecret
+		getSecret       func(path string) (*api.Secret, error)
+		deleteErr       error
+		deleted         []string
+		deletedVersions []string
+		remaining       []ephemeralSecret
+		out             string
+		err             error
+	}{
+		"nothing expired": {
+			secrets:   []ephemeralSecret{activeSecret},
+			remaining: []ephemeralSecret{activeSecret},
+			out:       "No expired secrets found.\n",
+		},
+		"dry run": {
+			dryRun:    true,
+			secrets:   []ephemeralSecret{expiredSecret, activeSecret},
+			remaining: []ephemeralSecret{expiredSecret, activeSecret},
+			out:       "namespace/repo/handoff/password:1 expired at " + expiredSecret.ExpiresAt.Local().Format(time.RFC3339) + "\n",
+		},
+		"remove expired": {
+			secrets: []ephemeralSecret{expiredSecret, activeSecret},
+			getSecret: func(path string) (*api.Secret, error) {
+				return &api.Secret{VersionCount: 1, LatestVersion: 1}, nil
+			},
+			deleted:   []string{"namespace/repo/handoff/password"},
+			remaining: []ephemeralSecret{activeSecret},
+			out:       "Removed the expired secret namespace/repo/handoff/password.\n",
+		},
+		"written again since": {
+			secrets: []ephemeralSecret{expiredSecret},
+			getSecret: func(path string) (*api.Secret, error) {
+				return &api.Secret{VersionCount: 2, LatestVersion: 2}, nil
+			},
+			deletedVersions: []string{"namespace/repo/handoff/password:1"},
+			remaining:       []ephemeralSecret{},
+			out:             "Removed the expired secret version namespace/repo/handoff/password:1.\n",
+		},
+		"written before": {
+			secrets: []ephemeralSecret{expiredSecret},
+			getSecret: func(path string) (*api.Secret, error) {
+				return &api.Secret{VersionCount: 2, LatestVersion: 1}, nil
+			},
+			deletedVersions: []string{"namespace/repo/handoff/password:1"},
+			remaining:       []ephemeralSecret{},
+			out:             "Removed the expired secret version namespace/repo/handoff/password:1.\n",
+		},
+		"version fails": {
+			secrets: []ephemeralSecret{expiredSecret},
+			getSecret: func(path string) (*api.Secret, error) {
+				return &api.Secret{VersionCount: 2, LatestVersion: 2}, nil
+			},
+			deleteErr:       api.ErrForbidden,
+			deletedVersions: []string{"namespace/repo/handoff/password:1"},
+			remaining:       []ephemeralSecret{expiredSecret},
+			out:             "Could not remove the secret version namespace/repo/handoff/password:1: " + api.ErrForbidden.Error() + "\n",
+			err:             ErrSecretGCFailed(1),
+		},
+		"already removed": {
+			secrets: []ephemeralSecret{expiredSecret},
+			getSecret: func(path string) (*api.Secret, error) {
+				return nil, api.ErrSecretNotFound
+			},
+			remaining: []ephemeralSecret{},
+			out:       "The secret namespace/repo/handoff/password has already been removed.\n",
+		},
+		"remove fails": {
+			secrets: []ephemeralSecret{expiredSecret},
+			getSecret: func(path string) (*api.Secret, error) {
+				return &api.Secret{VersionCount: 1, LatestVersion: 1}, nil
+			},
+			deleteErr: api.ErrForbidden,
+			deleted:   []string{"namespace/repo/handoff/password"},
+			remaining: []ephemeralSecret{expiredSecret},
+			out:       "Could not remove the secret namespace/repo/handoff/password: " + api.ErrForbidden.Error() + "\n",
+			err:       ErrSecretGCFailed(1),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			store := ephemeralSecretStore{jsonFile{path: filepath.Join(dir, ephemeralSecretsFilename)}}
+			err := store.Save(tc.secrets)
+			assert.OK(t, err)
+
+			var deleted []string
+			var deletedVersions []string
+			io := fakeui.NewIO(t)
+			cmd := GCCommand{
+				dryRun:  tc.dryRun,
+				io:      io,
+				secrets: func() ephemeralSecretStore { return store },
+				now:     func() time.Time { return now },
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							GetFunc: tc.getSecret,
+							DeleteFunc: func(path string) error {
+								deleted = append(deleted, path)
+								return tc.deleteErr
+							},
+							VersionService: &fakeclient.SecretVersionService{
+								DeleteFunc: func(path string) error {
+									deletedVersions = append(deletedVersions, path)
+									return tc.deleteErr
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err = cmd.Run()
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, deleted, tc.deleted)
+			assert.Equal(t, deletedVersions, tc.deletedVersions)
+
+			remaining, err := store.List()
+			assert.OK(t, err)
+			if len(tc.remaining) == 0 {
+				assert.Equal(t, len(remaining), 0)
+			} else {
+				assert.Equal(t, remaining, tc.remaining)
+			}
+		})
+	}
+}
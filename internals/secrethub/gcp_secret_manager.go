@@ -0,0 +1,148 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/secretmanager/v1"
+)
+
+// gcpPathSeparator replaces the slashes of secret paths in the IDs of GCP secrets,
+// as GCP secret IDs can only contain letters, numbers, dashes and underscores.
+const gcpPathSeparator = "__"
+
+// errGCPSecretExists is returned when a GCP secret is created that already exists.
+var errGCPSecretExists = errors.New("the GCP secret already exists")
+
+// gcpSecret is a secret in GCP Secret Manager.
+type gcpSecret struct {
+	ID     string
+	Labels map[string]string
+}
+
+// gcpSecretManager is the part of the GCP Secret Manager API used to import and export secrets.
+type gcpSecretManager interface {
+	// ListSecrets returns all secrets of the project.
+	ListSecrets(project string) ([]gcpSecret, error)
+	// AccessVersions returns the data of the enabled versions of the secret, oldest first.
+	// When all is false, only the latest version is returned.
+	AccessVersions(project string, secretID string, all bool) ([][]byte, error)
+	// CreateSecret creates a secret without versions with automatic replication.
+	CreateSecret(project string, secretID string, labels map[string]string) error
+	// AddVersion adds a version to the secret.
+	AddVersion(project string, secretID string, data []byte) error
+}
+
+// newGCPSecretManagerFunc creates a client for GCP Secret Manager.
+type newGCPSecretManagerFunc func() (gcpSecretManager, error)
+
+// newGCPSecretManager creates a client for GCP Secret Manager that authenticates with the application default credentials.
+func newGCPSecretManager() (gcpSecretManager, error) {
+	service, err := secretmanager.NewService(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return gcpSecretManagerService{service: service}, nil
+}
+
+// gcpSecretManagerService implements gcpSecretManager with the GCP Secret Manager REST API.
+type gcpSecretManagerService struct {
+	service *secretmanager.Service
+}
+
+// ListSecrets returns all secrets of the project, following all pages of the list.
+func (s gcpSecretManagerService) ListSecrets(project string) ([]gcpSecret, error) {
+	var res []gcpSecret
+	err := s.service.Projects.Secrets.List("projects/"+project).Pages(context.Background(), func(resp *secretmanager.ListSecretsResponse) error {
+		for _, secret := range resp.Secrets {
+			res = append(res, gcpSecret{
+				ID:     secret.Name[strings.LastIndex(secret.Name, "/")+1:],
+				Labels: secret.Labels,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AccessVersions returns the data of the enabled versions of the secret, oldest first.
+func (s gcpSecretManagerService) AccessVersions(project string, secretID string, all bool) ([][]byte, error) {
+	secretName := "projects/" + project + "/secrets/" + secretID
+	if !all {
+		data, err := s.access(secretName + "/versions/latest")
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{data}, nil
+	}
+
+	var versions []*secretmanager.SecretVersion
+	err := s.service.Projects.Secrets.Versions.List(secretName).Pages(context.Background(), func(resp *secretmanager.ListSecretVersionsResponse) error {
+		for _, version := range resp.Versions {
+			if version.State == "ENABLED" {
+				versions = append(versions, version)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The create times are RFC3339 timestamps in UTC, so they sort chronologically as strings.
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].CreateTime < versions[j].CreateTime
+	})
+
+	res := make([][]byte, len(versions))
+	for i, version := range versions {
+		res[i], err = s.access(version.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// access returns the data of the secret version with the given resource name.
+func (s gcpSecretManagerService) access(name string) ([]byte, error) {
+	resp, err := s.service.Projects.Secrets.Versions.Access(name).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Payload.Data)
+}
+
+// CreateSecret creates a secret without versions with automatic replication.
+func (s gcpSecretManagerService) CreateSecret(project string, secretID string, labels map[string]string) error {
+	secret := &secretmanager.Secret{
+		Labels: labels,
+		Replication: &secretmanager.Replication{
+			Automatic: &secretmanager.Automatic{},
+		},
+	}
+	_, err := s.service.Projects.Secrets.Create("projects/"+project, secret).SecretId(secretID).Do()
+	if errGoogle, ok := err.(*googleapi.Error); ok && errGoogle.Code == http.StatusConflict {
+		return errGCPSecretExists
+	}
+	return err
+}
+
+// AddVersion adds a version to the secret.
+func (s gcpSecretManagerService) AddVersion(project string, secretID string, data []byte) error {
+	req := &secretmanager.AddSecretVersionRequest{
+		Payload: &secretmanager.SecretPayload{
+			Data: base64.StdEncoding.EncodeToString(data),
+		},
+	}
+	_, err := s.service.Projects.Secrets.AddVersion("projects/"+project+"/secrets/"+secretID, req).Do()
+	return err
+}
@@ -2,12 +2,15 @@ package secrethub
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+	"github.com/secrethub/secrethub-cli/internals/cli/diceware"
+	"github.com/secrethub/secrethub-cli/internals/cli/passgen"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
@@ -27,9 +30,17 @@ var (
 	ErrCouldNotFindCharSet       = errGenerate.Code("charset_not_found").ErrorPref("could not find charset: %s")
 	ErrMinFlagInvalidInteger     = errGenerate.Code("min_flag_invalid_int").ErrorPref("second part of --min flag is not an integer: %s")
 	ErrInvalidMinFlag            = errGenerate.Code("min_flag_invalid").ErrorPref("min flag must be of the form <charset name>:<minimum count>, invalid min flag: %s")
+	ErrPassphraseLength          = errGenerate.Code("passphrase_length").Error("the length and --min cannot be used with --passphrase: use --words to set the number of words")
+	ErrPatternLength             = errGenerate.Code("pattern_length").Error("the length and --min cannot be used with --pattern: the length follows from the pattern")
+	ErrPronounceableCharset      = errGenerate.Code("pronounceable_charset").Error("--charset and --min cannot be used with --pronounceable")
+	ErrInvalidPattern            = errGenerate.Code("invalid_pattern").ErrorPref("invalid pattern: %s")
+	ErrMultipleGenerateModes     = errGenerate.Code("multiple_modes").Error("only one of --passphrase, --pronounceable and --pattern can be used")
 )
 
-const defaultLength = 22
+const (
+	defaultLength          = 22
+	defaultPassphraseWords = 6
+)
 
 // GenerateSecretCommand generates a new secret and writes to the output path.
 type GenerateSecretCommand struct {
@@ -42,6 +53,17 @@ type GenerateSecretCommand struct {
 	lengthArg           intValue
 	charsetFlag         charsetValue
 	mins                minRuleValue
+	passphrase          bool
+	words               int
+	separator           string
+	pronounceable       bool
+	pattern             string
+	charsetSet          bool
+	impliedLength       int
+	enforce             bool
+	entropy             func(length int) float64
+	breached            bool
+	checkBreach         breachChecker
 	copyToClipboard     bool
 	clearClipboardAfter time.Duration
 	clipper             clip.Clipper
@@ -55,17 +77,29 @@ func NewGenerateSecretCommand(io ui.IO, newClient newClientFunc) *GenerateSecret
 		newClient:           newClient,
 		clearClipboardAfter: defaultClearClipboardAfter,
 		clipper:             clip.NewClipboard(),
+		checkBreach:         defaultBreachChecker(),
 	}
 }
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *GenerateSecretCommand) Register(r command.Registerer) {
-	clause := r.Command("generate", "Generate a random secret.")
+	clause := r.Command("generate", "Generate a random secret. When a generate rule of the repository or of the applied policy of the organization "+
+		"matches the path, the secret is generated following that rule, so it meets the password rules of the system it is used in. "+
+		"The rules of a repository are set in the secret "+repoGeneratePolicySecretName+" in its root, in the same format as in `secrethub policy apply`. "+
+		"The length, --charset and --min flags take precedence over the rule, but the characters it excludes are never used.")
 	clause.Arg("secret-path", "The path to write the generated secret to").Required().PlaceHolder(secretPathPlaceHolder).StringVar(&cmd.firstArg)
 	clause.Flag("length", "The length of the generated secret. Defaults to "+strconv.Itoa(defaultLength)).PlaceHolder(strconv.Itoa(defaultLength)).Short('l').SetValue(&cmd.lengthFlag)
 	clause.Flag("min", "<charset>:<n> Ensure that the resulting password contains at least n characters from the given character set. Note that adding constraints reduces the strength of the secret. When possible, avoid any constraints.").SetValue(&cmd.mins)
 	clause.Flag("clip", "Copy the generated value to the clipboard. The clipboard is automatically cleared after "+units.HumanDuration(cmd.clearClipboardAfter)+".").Short('c').BoolVar(&cmd.copyToClipboard)
-	clause.Flag("charset", "Define the set of characters to randomly generate a password from. Options are all, alphanumeric, numeric, lowercase, uppercase, letters, symbols and human-readable. Multiple character sets can be combined by supplying them in a comma separated list. Defaults to alphanumeric.").Default("alphanumeric").HintOptions("all", "alphanumeric", "numeric", "lowercase", "uppercase", "letters", "symbols", "human-readable").SetValue(&cmd.charsetFlag)
+	clause.Flag("charset", "Define the set of characters to randomly generate a password from. Options are all, alphanumeric, numeric, lowercase, uppercase, letters, symbols and human-readable. Multiple character sets can be combined by supplying them in a comma separated list. Defaults to alphanumeric.").Default("alphanumeric").IsSetByUser(&cmd.charsetSet).HintOptions("all", "alphanumeric", "numeric", "lowercase", "uppercase", "letters", "symbols", "human-readable").SetValue(&cmd.charsetFlag)
+	clause.Flag("passphrase", "Generate a passphrase of random words from the EFF diceware wordlist instead of a string of random characters.").BoolVar(&cmd.passphrase)
+	clause.Flag("words", "The number of words in the passphrase. Defaults to "+strconv.Itoa(defaultPassphraseWords)+".").Default(strconv.Itoa(defaultPassphraseWords)).IntVar(&cmd.words)
+	clause.Flag("separator", "The separator between the words in the passphrase.").Default(diceware.DefaultSeparator).StringVar(&cmd.separator)
+	clause.Flag("pronounceable", "Generate a password of lowercase letters that alternate between consonants and vowels, so it can be read out loud. Use a longer length, as every character adds less entropy.").BoolVar(&cmd.pronounceable)
+	clause.Flag("pattern", "Generate a password that follows a template, e.g. Aaaa-9999-aaaa. In the template, a is replaced with a random lowercase letter, A with an uppercase letter, 9 with a digit, * with a letter or digit and # with a symbol. Other characters are kept as is, as are placeholders preceded by a backslash.").StringVar(&cmd.pattern)
+	clause.Flag("enforce-strength", "Refuse to generate the secret when the length and characters make it weak or when it has been breached, instead of only warning about it.").BoolVar(&cmd.enforce)
+	clause.Flag("check-breached", "Check whether the generated secret has appeared in a data breach with the Have I Been Pwned API. "+
+		"Only the first 5 characters of the SHA-1 hash of the secret are sent.").BoolVar(&cmd.breached)
 	clause.Flag("symbols", "Include symbols in secret.").Short('s').Hidden().SetValue(&cmd.symbolsFlag)
 	clause.Arg("rand-command", "").Hidden().StringVar(&cmd.secondArg)
 	clause.Arg("length", "").Hidden().SetValue(&cmd.lengthArg)
@@ -75,20 +109,83 @@ func (cmd *GenerateSecretCommand) Register(r command.Registerer) {
 
 // before configures the command using the flag values.
 func (cmd *GenerateSecretCommand) before() error {
+	modes := 0
+	for _, mode := range []bool{cmd.passphrase, cmd.pronounceable, cmd.pattern != ""} {
+		if mode {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return ErrMultipleGenerateModes
+	}
+
+	if cmd.pattern != "" {
+		if cmd.lengthFlag.IsSet() || cmd.lengthArg.IsSet() || len(cmd.mins.v) > 0 {
+			return ErrPatternLength
+		}
+		pattern, err := passgen.NewPattern(cmd.pattern)
+		if err != nil {
+			return ErrInvalidPattern(err)
+		}
+		cmd.generator = pattern
+		cmd.impliedLength = pattern.Len()
+		cmd.entropy = func(int) float64 { return pattern.Entropy() }
+		return nil
+	}
+
+	if cmd.pronounceable {
+		if cmd.charsetSet || len(cmd.mins.v) > 0 {
+			return ErrPronounceableCharset
+		}
+		pronounceable := passgen.NewPronounceable()
+		cmd.generator = pronounceable
+		cmd.entropy = pronounceable.Entropy
+		return nil
+	}
+
+	if cmd.passphrase {
+		if cmd.lengthFlag.IsSet() || cmd.lengthArg.IsSet() || len(cmd.mins.v) > 0 {
+			return ErrPassphraseLength
+		}
+		cmd.generator = diceware.NewGenerator(cmd.separator)
+		cmd.entropy = diceware.Entropy
+		return nil
+	}
+
 	useSymbols, err := cmd.useSymbols()
 	if err != nil {
 		return err
 	}
 
 	charset := cmd.charsetFlag.v
+	mins := cmd.mins.v
+	rule, source, ok, err := cmd.generateRule()
+	if err != nil {
+		return err
+	}
+	if ok && !cmd.charsetSet {
+		charset = rule.charset()
+	}
 	if useSymbols {
 		charset = charset.Add(randchar.Symbols)
 	}
+	if ok {
+		var required []randchar.Option
+		charset, required = rule.options(charset)
+		if len(mins) == 0 {
+			mins = required
+		}
+		cmd.impliedLength = rule.Length
+		fmt.Fprintf(statusWriter(cmd.io.Output()), "Generating the secret following the rule for %s in %s.\n", rule.Path, source)
+	}
 
-	cmd.generator, err = randchar.NewRand(charset, cmd.mins.v...)
+	cmd.generator, err = randchar.NewRand(charset, mins...)
 	if err != nil {
 		return err
 	}
+	cmd.entropy = func(length int) float64 {
+		return float64(length) * math.Log2(float64(charset.Size()))
+	}
 
 	return nil
 }
@@ -118,11 +215,25 @@ func (cmd *GenerateSecretCommand) run() error {
 		return ErrInvalidRandLength
 	}
 
+	if cmd.entropy != nil {
+		err = checkStrength(statusWriter(cmd.io.Output()), generatedWeakness(cmd.entropy(length)), cmd.enforce)
+		if err != nil {
+			return err
+		}
+	}
+
 	data, err := cmd.generator.Generate(length)
 	if err != nil {
 		return err
 	}
 
+	if cmd.breached {
+		err = checkBreached(statusWriter(cmd.io.Output()), cmd.checkBreach, data, cmd.enforce)
+		if err != nil {
+			return err
+		}
+	}
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
@@ -152,6 +263,9 @@ func (cmd *GenerateSecretCommand) run() error {
 }
 
 func (cmd *GenerateSecretCommand) length() (int, error) {
+	if cmd.passphrase {
+		return cmd.words, nil
+	}
 	if cmd.lengthArg.IsSet() && cmd.lengthFlag.IsSet() {
 		return 0, ErrCannotUseLengthArgAndFlag
 	}
@@ -161,9 +275,27 @@ func (cmd *GenerateSecretCommand) length() (int, error) {
 	if cmd.lengthArg.IsSet() {
 		return cmd.lengthArg.Get(), nil
 	}
+	if cmd.impliedLength > 0 {
+		return cmd.impliedLength, nil
+	}
 	return defaultLength, nil
 }
 
+// generateRule returns the generate rule that applies to the path of the secret, if any.
+func (cmd *GenerateSecretCommand) generateRule() (generateRule, string, bool, error) {
+	path, err := cmd.path()
+	if err != nil {
+		return generateRule{}, "", false, err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return generateRule{}, "", false, err
+	}
+
+	return findGenerateRule(client, api.SecretPath(path))
+}
+
 func (cmd *GenerateSecretCommand) path() (string, error) {
 	if cmd.firstArg == "rand" {
 		return cmd.secondArg, api.ValidateSecretPath(cmd.secondArg)
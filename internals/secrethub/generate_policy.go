@@ -0,0 +1,176 @@
+package secrethub
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"gopkg.in/yaml.v2"
+)
+
+// repoGeneratePolicySecretName is the name of the secret in the root of a repository
+// that contains the generate rules of the repository.
+const repoGeneratePolicySecretName = ".generate-policy"
+
+// generateRule defines how the secrets at the paths that match a pattern are generated,
+// so the generated values meet the password rules of the systems they are used in.
+type generateRule struct {
+	// Path is a pattern of the paths of the secrets the rule applies to, as used by path.Match.
+	// In an organization policy, the path is relative to the organization, e.g. */prod/*.
+	// In the policy of a repository, it is relative to the repository, e.g. prod/db_password.
+	Path string `yaml:"path" json:"path"`
+	// Length is the number of characters of generated secrets.
+	Length int `yaml:"length" json:"length,omitempty"`
+	// Charsets are the names of the character sets generated secrets are generated from.
+	Charsets []string `yaml:"charsets" json:"charsets,omitempty"`
+	// Require are the character sets of which generated secrets must contain at least one character,
+	// or the given number of characters when written as <charset>:<n>.
+	Require []string `yaml:"require" json:"require,omitempty"`
+	// Exclude are the characters generated secrets must not contain.
+	Exclude string `yaml:"exclude" json:"exclude,omitempty"`
+	// ExcludeSimilar excludes characters that look similar, such as 1 and l.
+	ExcludeSimilar bool `yaml:"exclude_similar" json:"exclude_similar,omitempty"`
+}
+
+// validate returns an error when the rule cannot be applied.
+func (r generateRule) validate() error {
+	_, err := path.Match(r.Path, "")
+	if r.Path == "" || err != nil {
+		return ErrInvalidPolicy(fmt.Sprintf("generate path %q is not a valid pattern", r.Path))
+	}
+	if r.Length < 0 {
+		return ErrInvalidPolicy(fmt.Sprintf("generate length of %s must be larger than 0", r.Path))
+	}
+	for _, name := range r.Charsets {
+		_, ok := randchar.CharsetByName(name)
+		if !ok {
+			return ErrInvalidPolicy(fmt.Sprintf("generate charset %q of %s does not exist", name, r.Path))
+		}
+	}
+	for _, requirement := range r.Require {
+		_, _, err := parseCharsetRequirement(requirement)
+		if err != nil {
+			return ErrInvalidPolicy(fmt.Sprintf("generate requirement %q of %s: %s", requirement, r.Path, err))
+		}
+	}
+	return nil
+}
+
+// parseCharsetRequirement parses a requirement in the form <charset> or <charset>:<n>.
+func parseCharsetRequirement(requirement string) (randchar.Charset, int, error) {
+	name, count := requirement, 1
+	if i := strings.Index(requirement, ":"); i >= 0 {
+		var err error
+		name = requirement[:i]
+		count, err = strconv.Atoi(requirement[i+1:])
+		if err != nil || count < 1 {
+			return randchar.Charset{}, 0, fmt.Errorf("%s is not a positive integer", requirement[i+1:])
+		}
+	}
+	charset, ok := randchar.CharsetByName(name)
+	if !ok {
+		return randchar.Charset{}, 0, fmt.Errorf("charset %s does not exist", name)
+	}
+	return charset, count, nil
+}
+
+// excluded returns the characters the rule excludes.
+func (r generateRule) excluded() randchar.Charset {
+	excluded := randchar.NewCharset(r.Exclude)
+	if r.ExcludeSimilar {
+		excluded = excluded.Add(randchar.Similar)
+	}
+	return excluded
+}
+
+// charset returns the characters secrets are generated from, when no charset is given.
+func (r generateRule) charset() randchar.Charset {
+	if len(r.Charsets) == 0 {
+		return randchar.Alphanumeric
+	}
+	var charset randchar.Charset
+	for _, name := range r.Charsets {
+		set, _ := randchar.CharsetByName(name)
+		charset = charset.Add(set)
+	}
+	return charset
+}
+
+// options returns the options that make generated secrets meet the requirements of the rule.
+// The required character sets are also added to the charset, so they can always be met.
+func (r generateRule) options(charset randchar.Charset) (randchar.Charset, []randchar.Option) {
+	excluded := r.excluded()
+	var options []randchar.Option
+	for _, requirement := range r.Require {
+		set, count, _ := parseCharsetRequirement(requirement)
+		set = set.Subtract(excluded)
+		charset = charset.Add(set)
+		options = append(options, randchar.Min(count, set))
+	}
+	return charset.Subtract(excluded), options
+}
+
+// parseRepoGeneratePolicy parses the generate rules in the policy secret of a repository.
+func parseRepoGeneratePolicy(data []byte) ([]generateRule, error) {
+	var policy struct {
+		Generate []generateRule `yaml:"generate"`
+	}
+	err := yaml.UnmarshalStrict(data, &policy)
+	if err != nil {
+		return nil, ErrInvalidPolicy(err)
+	}
+	for _, rule := range policy.Generate {
+		err = rule.validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return policy.Generate, nil
+}
+
+// matchGenerateRule returns the first rule of which the pattern matches the given relative path.
+func matchGenerateRule(rules []generateRule, relPath string) (generateRule, bool) {
+	for _, rule := range rules {
+		matched, _ := path.Match(rule.Path, relPath)
+		if matched {
+			return rule, true
+		}
+	}
+	return generateRule{}, false
+}
+
+// findGenerateRule returns the rule that applies to a secret and where it is defined.
+// The policy secret of the repository takes precedence over the applied organization policy.
+func findGenerateRule(client secrethub.ClientInterface, secretPath api.SecretPath) (generateRule, string, bool, error) {
+	repoPath := secretPath.GetRepoPath()
+	policyPath := repoPath.GetDirPath().JoinSecret(repoGeneratePolicySecretName)
+	secret, err := client.Secrets().Versions().GetWithData(policyPath.Value())
+	if err != nil && !api.IsErrNotFound(err) {
+		return generateRule{}, "", false, err
+	}
+	if err == nil {
+		rules, err := parseRepoGeneratePolicy(secret.Data)
+		if err != nil {
+			return generateRule{}, "", false, err
+		}
+		relPath := strings.TrimPrefix(secretPath.Value(), repoPath.Value()+"/")
+		if rule, ok := matchGenerateRule(rules, relPath); ok {
+			return rule, policyPath.String(), true, nil
+		}
+	}
+
+	policy, ok, err := newPolicyStore(client).Get(secretPath.GetNamespace())
+	if err != nil || !ok {
+		return generateRule{}, "", false, err
+	}
+	relPath := strings.TrimPrefix(secretPath.Value(), secretPath.GetNamespace()+"/")
+	if rule, ok := matchGenerateRule(policy.Generate, relPath); ok {
+		return rule, "the policy of " + policy.Org, true, nil
+	}
+	return generateRule{}, "", false, nil
+}
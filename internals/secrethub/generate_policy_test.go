@@ -0,0 +1,120 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestGenerateSecretCommand_Run_Policy(t *testing.T) {
+	policy := "org: namespace\n" +
+		"generate:\n" +
+		"  - path: repo/prod/*\n" +
+		"    length: 30\n" +
+		"    require: ['symbols:2', numeric]\n" +
+		"    exclude_similar: true\n" +
+		"    exclude: '^$'\n"
+
+	cases := map[string]struct {
+		path       string
+		repoPolicy string
+		orgPolicy  string
+		lengthFlag intValue
+		check      func(t *testing.T, data string)
+		err        error
+	}{
+		"no rule": {
+			path: "namespace/repo/prod/password",
+			check: func(t *testing.T, data string) {
+				assert.Equal(t, len(data), defaultLength)
+			},
+		},
+		"repo rule": {
+			path:       "namespace/repo/prod/pin",
+			repoPolicy: "generate:\n  - path: prod/pin\n    length: 8\n    charsets: [numeric]\n",
+			orgPolicy:  policy,
+			check: func(t *testing.T, data string) {
+				assert.Equal(t, len(data), 8)
+				assert.Equal(t, strings.Trim(data, "0123456789"), "")
+			},
+		},
+		"org rule": {
+			path:       "namespace/repo/prod/password",
+			repoPolicy: "generate:\n  - path: prod/pin\n    length: 8\n",
+			orgPolicy:  policy,
+			check: func(t *testing.T, data string) {
+				assert.Equal(t, len(data), 30)
+				assert.Equal(t, strings.ContainsAny(data, "iIlL1oO0^$"), false)
+				symbols := 0
+				for _, c := range data {
+					if strings.ContainsRune("!@#%*-_+=.,?", c) {
+						symbols++
+					}
+				}
+				assert.Equal(t, symbols >= 2, true)
+				assert.Equal(t, strings.ContainsAny(data, "23456789"), true)
+			},
+		},
+		"length flag": {
+			path:       "namespace/repo/prod/password",
+			orgPolicy:  policy,
+			lengthFlag: newIntValue(40),
+			check: func(t *testing.T, data string) {
+				assert.Equal(t, len(data), 40)
+			},
+		},
+		"invalid repo policy": {
+			path:       "namespace/repo/prod/password",
+			repoPolicy: "generate:\n  - path: prod/*\n    charsets: [emoji]\n",
+			err:        ErrInvalidPolicy(`generate charset "emoji" of prod/* does not exist`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written string
+			cmd := GenerateSecretCommand{
+				io:          fakeui.NewIO(t),
+				firstArg:    tc.path,
+				lengthFlag:  tc.lengthFlag,
+				charsetFlag: charsetValue{v: randchar.Alphanumeric},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									if path == orgPolicyPath("namespace").Value() {
+										return fakePolicyVersionService(tc.orgPolicy).GetWithData(path)
+									}
+									assert.Equal(t, path, "namespace/repo/"+repoGeneratePolicySecretName)
+									if tc.repoPolicy == "" {
+										return nil, api.ErrSecretNotFound
+									}
+									return &api.SecretVersion{Data: []byte(tc.repoPolicy)}, nil
+								},
+							},
+							WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+								written = string(data)
+								return &api.SecretVersion{Version: 1}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				tc.check(t, written)
+			}
+		})
+	}
+}
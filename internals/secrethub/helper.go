@@ -3,7 +3,6 @@ package secrethub
 import (
 	"fmt"
 
-	"github.com/fatih/color"
 	"github.com/secrethub/secrethub-go/internals/api"
 )
 
@@ -15,15 +14,11 @@ func pluralize(single string, plural string, items int) string {
 	return fmt.Sprintf("%d %s", items, plural)
 }
 
-var (
-	red = color.New(color.FgRed, color.Bold)
-)
-
 // colorizeByStatus adds optional color to a given message based on status.
 func colorizeByStatus(status string, msg interface{}) interface{} {
 	switch status {
 	case api.StatusFlagged:
-		return red.Sprint(msg)
+		return colorize(colorRoleFlagged, msg)
 	default:
 		return msg
 	}
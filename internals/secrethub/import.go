@@ -0,0 +1,270 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrInvalidImportConflictStrategy = errMain.Code("invalid_import_conflict_strategy").ErrorPref("invalid conflict strategy %q: must be one of skip or overwrite")
+	ErrInvalidImportedSecretName     = errMain.Code("invalid_imported_secret_name").ErrorPref("cannot import %q: %s is not a valid secret path")
+	ErrImportFailed                  = errMain.Code("import_failed").ErrorPref("%d secret(s) could not be imported")
+)
+
+// The actions shown by a dry run of an import.
+const (
+	importActionCreate    = "create"
+	importActionUpdate    = "update"
+	importActionSkip      = "skip"
+	importActionUnchanged = "unchanged"
+)
+
+// ImportCommand handles importing secrets from other sources.
+type ImportCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewImportCommand creates a new ImportCommand.
+func NewImportCommand(io ui.IO, newClient newClientFunc) *ImportCommand {
+	return &ImportCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *ImportCommand) Register(r command.Registerer) {
+	clause := r.Command("import", "Import secrets from other sources.")
+	NewImportDotEnvCommand(cmd.io, cmd.newClient).Register(clause)
+	NewImportEnvCommand(cmd.io, cmd.newClient).Register(clause)
+	NewImportAWSSMCommand(cmd.io, cmd.newClient).Register(clause)
+	NewImportGCPSMCommand(cmd.io, cmd.newClient).Register(clause)
+	NewImportAzureKVCommand(cmd.io, cmd.newClient).Register(clause)
+	NewImportVaultCommand(cmd.io, cmd.newClient).Register(clause)
+	NewImportK8sCommand(cmd.io, cmd.newClient).Register(clause)
+	NewImportPasswordManagerCommand(cmd.io, cmd.newClient, onePasswordFormat).Register(clause)
+	NewImportPasswordManagerCommand(cmd.io, cmd.newClient, bitwardenFormat).Register(clause)
+	NewImportPasswordManagerCommand(cmd.io, cmd.newClient, lastPassFormat).Register(clause)
+	NewImportCSVCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// namedSecret is a secret that is imported from or exported to another source.
+type namedSecret struct {
+	// Name is the path of the secret relative to the directory it is imported into or exported from.
+	Name  string
+	Value []byte
+}
+
+// secretImporter writes imported secrets into a directory.
+type secretImporter struct {
+	dirPath    api.DirPath
+	onConflict string
+	dryRun     bool
+	// progress prefixes every reported secret with its position in the import.
+	progress bool
+	// continueOnError makes the import continue when a secret cannot be written and report the failures at the end.
+	continueOnError bool
+}
+
+// registerImportFlags registers the flags shared by all importers on the provided FlagRegisterer.
+func registerImportFlags(r FlagRegisterer, importer *secretImporter) {
+	r.Flag("on-conflict", "What to do with secrets that already exist: skip them or overwrite them by writing the imported value as a new version.").HintOptions(conflictSkip, conflictOverwrite).Default(conflictSkip).StringVar(&importer.onConflict)
+	r.Flag("dry-run", "Only show which secrets would be created, updated, skipped or left unchanged, without writing anything.").BoolVar(&importer.dryRun)
+}
+
+// secretPath returns the path the secret with the given name is imported to.
+func (imp secretImporter) secretPath(name string) string {
+	return imp.dirPath.String() + "/" + name
+}
+
+// validate returns an error when the conflict strategy is unknown or when a secret cannot be written to its path.
+// This is checked before anything is written, so that an import never fails halfway on invalid input.
+func (imp secretImporter) validate(secrets []namedSecret) error {
+	if imp.onConflict != conflictSkip && imp.onConflict != conflictOverwrite {
+		return ErrInvalidImportConflictStrategy(imp.onConflict)
+	}
+
+	for _, secret := range secrets {
+		path := imp.secretPath(secret.Name)
+		if api.ValidateSecretPath(path) != nil {
+			return ErrInvalidImportedSecretName(secret.Name, path)
+		}
+	}
+	return nil
+}
+
+// importSecrets creates the directories the secrets are imported into and writes the secrets.
+func (imp secretImporter) importSecrets(io ui.IO, client secrethub.ClientInterface, secrets []namedSecret) error {
+	err := imp.validate(secrets)
+	if err != nil {
+		return err
+	}
+
+	if imp.dryRun {
+		return imp.diff(io, client, secrets)
+	}
+
+	for _, dir := range imp.dirs(secrets) {
+		exists, err := client.Dirs().Exists(dir)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		_, err = client.Dirs().Create(dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A secret can occur more than once to import multiple versions, oldest first.
+	// Only the first occurrence is checked for conflicts.
+	written := map[string]bool{}
+	skipped := map[string]bool{}
+	failed := map[string]bool{}
+
+	counter := newProgressCounter("Importing secrets", len(secrets))
+	defer counter.Finish()
+
+	for i, secret := range secrets {
+		path := imp.secretPath(secret.Name)
+		if skipped[path] || failed[path] {
+			counter.Done()
+			continue
+		}
+
+		progress := ""
+		if imp.progress {
+			progress = fmt.Sprintf("[%d/%d] ", i+1, len(secrets))
+		}
+
+		err := imp.writeSecret(client, path, secret.Value, written[path])
+		if err == errImportSkipped {
+			fmt.Fprintf(io.Output(), "%sSkipped %s: the secret already exists\n", progress, path)
+			skipped[path] = true
+			counter.Done()
+			continue
+		} else if err != nil {
+			if !imp.continueOnError {
+				return err
+			}
+			fmt.Fprintf(io.Output(), "%sFailed %s: %s\n", progress, path, err)
+			failed[path] = true
+			counter.Fail()
+			continue
+		}
+		fmt.Fprintf(io.Output(), "%sWrote %s\n", progress, path)
+		written[path] = true
+		counter.Done()
+	}
+	counter.Finish()
+
+	if len(failed) > 0 {
+		fmt.Fprintf(io.Output(), "Import finished with errors! Secrets: %d written, %d skipped, %d failed.\n", len(written), len(skipped), len(failed))
+		return ErrImportFailed(len(failed))
+	}
+
+	fmt.Fprintf(statusWriter(io.Output()), "Import complete! Secrets: %d written, %d skipped.\n", len(written), len(skipped))
+	return nil
+}
+
+// errImportSkipped is returned by writeSecret when a secret is not written because it already exists.
+var errImportSkipped = errors.New("skipped")
+
+// writeSecret writes the secret, unless it already exists and conflicting secrets are skipped.
+// When the secret has already been written in this import, it is not checked for conflicts.
+func (imp secretImporter) writeSecret(client secrethub.ClientInterface, path string, value []byte, written bool) error {
+	if !written {
+		exists, err := client.Secrets().Exists(path)
+		if err != nil {
+			return err
+		}
+
+		if exists && imp.onConflict == conflictSkip {
+			return errImportSkipped
+		}
+	}
+
+	_, err := client.Secrets().Write(path, value)
+	return err
+}
+
+// diff prints what importing the secrets would do, comparing the last imported version
+// of every secret with the secret's current value.
+func (imp secretImporter) diff(io ui.IO, client secrethub.ClientInterface, secrets []namedSecret) error {
+	var paths []string
+	latest := map[string][]byte{}
+	for _, secret := range secrets {
+		path := imp.secretPath(secret.Name)
+		if _, ok := latest[path]; !ok {
+			paths = append(paths, path)
+		}
+		latest[path] = secret.Value
+	}
+
+	counts := map[string]int{}
+	w := tabwriter.NewWriter(io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\n", "ACTION", "PATH")
+	for _, path := range paths {
+		action := importActionCreate
+		current, err := client.Secrets().Versions().GetWithData(path)
+		if err != nil && err != api.ErrSecretNotFound {
+			return err
+		}
+		if err == nil {
+			switch {
+			case bytes.Equal(current.Data, latest[path]):
+				action = importActionUnchanged
+			case imp.onConflict == conflictSkip:
+				action = importActionSkip
+			default:
+				action = importActionUpdate
+			}
+		}
+
+		counts[action]++
+		fmt.Fprintf(w, "%s\t%s\n", action, path)
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Output(), "Dry run: %d to create, %d to update, %d to skip, %d unchanged. Nothing was written.\n",
+		counts[importActionCreate], counts[importActionUpdate], counts[importActionSkip], counts[importActionUnchanged])
+	return nil
+}
+
+// dirs returns the directories below the repository root that must exist to write the secrets, parents first.
+func (imp secretImporter) dirs(secrets []namedSecret) []string {
+	repoPath := imp.dirPath.GetRepoPath().String()
+
+	set := map[string]bool{}
+	for _, secret := range secrets {
+		path := imp.secretPath(secret.Name)
+		for dir := path[:strings.LastIndex(path, "/")]; dir != repoPath; dir = dir[:strings.LastIndex(dir, "/")] {
+			set[dir] = true
+		}
+	}
+
+	res := make([]string, 0, len(set))
+	for dir := range set {
+		res = append(res, dir)
+	}
+	sort.Strings(res)
+	return res
+}
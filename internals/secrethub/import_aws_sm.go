@@ -0,0 +1,205 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	shaws "github.com/secrethub/secrethub-go/internals/aws"
+	"github.com/secrethub/secrethub-go/internals/errio"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+// Errors
+var (
+	ErrInvalidJSONValuesMode = errMain.Code("invalid_json_values_mode").ErrorPref("invalid json values mode %q: must be one of flatten or blob")
+)
+
+const (
+	jsonValuesFlatten = "flatten"
+	jsonValuesBlob    = "blob"
+
+	// awsSMMaxRetries is the number of times a request to AWS Secrets Manager is retried.
+	// The AWS SDK retries throttled requests with an exponential backoff, so a high number
+	// of retries keeps large migrations from failing on the rate limits of Secrets Manager.
+	awsSMMaxRetries = 10
+)
+
+// newSecretsManagerFunc creates a client for AWS Secrets Manager in the given region.
+// When the region is empty, the region from the AWS configuration is used.
+type newSecretsManagerFunc func(region string) (secretsmanageriface.SecretsManagerAPI, error)
+
+// newAWSSecretsManager creates a client for AWS Secrets Manager using the AWS credentials and configuration of the environment.
+func newAWSSecretsManager(region string) (secretsmanageriface.SecretsManagerAPI, error) {
+	cfg := aws.NewConfig().WithMaxRetries(awsSMMaxRetries)
+	if region != "" {
+		_, ok := endpoints.AwsPartition().Regions()[region]
+		if !ok {
+			return nil, ErrInvalidAWSRegion
+		}
+		cfg = cfg.WithRegion(region)
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, handleAWSSecretsManagerErr(err)
+	}
+	return secretsmanager.New(sess), nil
+}
+
+// handleAWSSecretsManagerErr converts errors returned by AWS into errors that can be shown to the user.
+func handleAWSSecretsManagerErr(err error) error {
+	errAWS, ok := err.(awserr.Error)
+	if !ok {
+		return err
+	}
+
+	switch errAWS.Code() {
+	case "NoCredentialProviders":
+		return shaws.ErrNoAWSCredentials
+	case "MissingRegion":
+		return ErrMissingRegion
+	}
+	return errio.Namespace("aws").Code(errAWS.Code()).Error(errAWS.Message())
+}
+
+// ImportAWSSMCommand imports secrets from AWS Secrets Manager.
+type ImportAWSSMCommand struct {
+	io                ui.IO
+	prefix            string
+	region            string
+	jsonValues        string
+	importer          secretImporter
+	newClient         newClientFunc
+	newSecretsManager newSecretsManagerFunc
+}
+
+// NewImportAWSSMCommand creates a new ImportAWSSMCommand.
+func NewImportAWSSMCommand(io ui.IO, newClient newClientFunc) *ImportAWSSMCommand {
+	return &ImportAWSSMCommand{
+		io:                io,
+		newClient:         newClient,
+		newSecretsManager: newAWSSecretsManager,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportAWSSMCommand) Register(r command.Registerer) {
+	clause := r.Command("aws-sm", "Import secrets from AWS Secrets Manager, using the AWS credentials and configuration of the environment. "+
+		"The prefix is stripped from the names of the AWS secrets and every remaining slash in a name creates a subdirectory.")
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("prefix", "Only import the AWS secrets of which the name starts with this prefix, e.g. /prod/.").StringVar(&cmd.prefix)
+	clause.Flag("region", "The AWS region to import the secrets from. Defaults to the region in the AWS configuration.").StringVar(&cmd.region)
+	clause.Flag("json-values", "How to import AWS secrets with a JSON object as value: flatten them into a directory with a secret per key or keep the JSON as a single secret.").HintOptions(jsonValuesFlatten, jsonValuesBlob).Default(jsonValuesBlob).StringVar(&cmd.jsonValues)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the secrets from AWS Secrets Manager.
+func (cmd *ImportAWSSMCommand) Run() error {
+	if cmd.jsonValues != jsonValuesFlatten && cmd.jsonValues != jsonValuesBlob {
+		return ErrInvalidJSONValuesMode(cmd.jsonValues)
+	}
+
+	sm, err := cmd.newSecretsManager(cmd.region)
+	if err != nil {
+		return err
+	}
+
+	names, err := listAWSSecretNames(sm, cmd.prefix)
+	if err != nil {
+		return err
+	}
+
+	var secrets []namedSecret
+	for _, name := range names {
+		output, err := sm.GetSecretValue(&secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(name),
+		})
+		if err != nil {
+			return handleAWSSecretsManagerErr(err)
+		}
+
+		value := output.SecretBinary
+		if output.SecretString != nil {
+			value = []byte(aws.StringValue(output.SecretString))
+		}
+
+		secretName := strings.TrimPrefix(strings.TrimPrefix(name, cmd.prefix), "/")
+		if cmd.jsonValues == jsonValuesFlatten {
+			fields, ok := flattenJSONObject(value)
+			if ok {
+				for _, field := range fields {
+					secrets = append(secrets, namedSecret{Name: secretName + "/" + field.Name, Value: field.Value})
+				}
+				continue
+			}
+		}
+		secrets = append(secrets, namedSecret{Name: secretName, Value: value})
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
+
+// listAWSSecretNames returns the sorted names of all AWS secrets that start with the given prefix.
+func listAWSSecretNames(sm secretsmanageriface.SecretsManagerAPI, prefix string) ([]string, error) {
+	var names []string
+	err := sm.ListSecretsPages(&secretsmanager.ListSecretsInput{
+		MaxResults: aws.Int64(100),
+	}, func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+		for _, entry := range page.SecretList {
+			name := aws.StringValue(entry.Name)
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, handleAWSSecretsManagerErr(err)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// flattenJSONObject returns a secret for every key of the given JSON object, sorted by key.
+// String values are unquoted and all other values are kept as JSON.
+// When the data is not a JSON object, false is returned.
+func flattenJSONObject(data []byte) ([]namedSecret, bool) {
+	var object map[string]json.RawMessage
+	err := json.Unmarshal(data, &object)
+	if err != nil || object == nil {
+		return nil, false
+	}
+
+	res := make([]namedSecret, 0, len(object))
+	for key, raw := range object {
+		value := []byte(raw)
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			value = []byte(s)
+		}
+		res = append(res, namedSecret{Name: key, Value: value})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, true
+}
@@ -0,0 +1,197 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+// fakeSecretsManager is a fake AWS Secrets Manager that stores string secrets in memory
+// and lists them in pages of two secrets.
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+	names   []string
+	secrets map[string]string
+}
+
+func (sm *fakeSecretsManager) ListSecretsPages(input *secretsmanager.ListSecretsInput, fn func(*secretsmanager.ListSecretsOutput, bool) bool) error {
+	for i := 0; i < len(sm.names); i += 2 {
+		page := &secretsmanager.ListSecretsOutput{}
+		for j := i; j < i+2 && j < len(sm.names); j++ {
+			page.SecretList = append(page.SecretList, &secretsmanager.SecretListEntry{Name: aws.String(sm.names[j])})
+		}
+		if !fn(page, i+2 >= len(sm.names)) {
+			break
+		}
+	}
+	return nil
+}
+
+func (sm *fakeSecretsManager) GetSecretValue(input *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+	value, ok := sm.secrets[aws.StringValue(input.SecretId)]
+	if !ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "not found", nil)
+	}
+	return &secretsmanager.GetSecretValueOutput{Name: input.SecretId, SecretString: aws.String(value)}, nil
+}
+
+func (sm *fakeSecretsManager) CreateSecret(input *secretsmanager.CreateSecretInput) (*secretsmanager.CreateSecretOutput, error) {
+	name := aws.StringValue(input.Name)
+	if _, ok := sm.secrets[name]; ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceExistsException, "exists", nil)
+	}
+	sm.names = append(sm.names, name)
+	sm.secrets[name] = aws.StringValue(input.SecretString)
+	return &secretsmanager.CreateSecretOutput{Name: input.Name}, nil
+}
+
+func (sm *fakeSecretsManager) PutSecretValue(input *secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error) {
+	sm.secrets[aws.StringValue(input.SecretId)] = aws.StringValue(input.SecretString)
+	return &secretsmanager.PutSecretValueOutput{Name: input.SecretId}, nil
+}
+
+func TestImportAWSSMCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	sm := &fakeSecretsManager{
+		names: []string{"/prod/db", "/prod/api/key", "/dev/db"},
+		secrets: map[string]string{
+			"/prod/db":      `{"user":"admin","password":"secret","port":5432}`,
+			"/prod/api/key": "abc",
+			"/dev/db":       `{"user":"dev"}`,
+		},
+	}
+
+	cases := map[string]struct {
+		jsonValues string
+		smErr      error
+		written    []string
+		out        string
+		err        error
+	}{
+		"blob": {
+			jsonValues: jsonValuesBlob,
+			written: []string{
+				"namespace/repo/api/key:abc",
+				`namespace/repo/db:{"user":"admin","password":"secret","port":5432}`,
+			},
+			out: "Wrote namespace/repo/api/key\n" +
+				"Wrote namespace/repo/db\n" +
+				"Import complete! Secrets: 2 written, 0 skipped.\n",
+		},
+		"flatten": {
+			jsonValues: jsonValuesFlatten,
+			written: []string{
+				"namespace/repo/api/key:abc",
+				"namespace/repo/db/password:secret",
+				"namespace/repo/db/port:5432",
+				"namespace/repo/db/user:admin",
+			},
+			out: "Wrote namespace/repo/api/key\n" +
+				"Wrote namespace/repo/db/password\n" +
+				"Wrote namespace/repo/db/port\n" +
+				"Wrote namespace/repo/db/user\n" +
+				"Import complete! Secrets: 4 written, 0 skipped.\n",
+		},
+		"invalid json values mode": {
+			jsonValues: "merge",
+			err:        ErrInvalidJSONValuesMode("merge"),
+		},
+		"secrets manager error": {
+			jsonValues: jsonValuesBlob,
+			smErr:      testErr,
+			err:        testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written []string
+			io := fakeui.NewIO(t)
+			cmd := ImportAWSSMCommand{
+				io:         io,
+				prefix:     "/prod/",
+				jsonValues: tc.jsonValues,
+				importer: secretImporter{
+					dirPath:    api.DirPath("namespace/repo"),
+					onConflict: conflictSkip,
+				},
+				newSecretsManager: func(region string) (secretsmanageriface.SecretsManagerAPI, error) {
+					return sm, tc.smErr
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{
+						Client: fakeclient.Client{
+							DirService: &fakeclient.DirService{
+								ExistsFunc: func(path string) (bool, error) {
+									return true, nil
+								},
+							},
+						},
+						secrets: importTestSecretService{
+							SecretService: &fakeclient.SecretService{
+								WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+									written = append(written, path+":"+string(data))
+									return nil, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, written, tc.written)
+		})
+	}
+}
+
+func TestFlattenJSONObject(t *testing.T) {
+	cases := map[string]struct {
+		in       string
+		expected []namedSecret
+		ok       bool
+	}{
+		"object": {
+			in: `{"b":"x","a":{"nested":true},"c":null}`,
+			expected: []namedSecret{
+				{Name: "a", Value: []byte(`{"nested":true}`)},
+				{Name: "b", Value: []byte("x")},
+				{Name: "c", Value: []byte("null")},
+			},
+			ok: true,
+		},
+		"array": {
+			in: `["a","b"]`,
+		},
+		"null": {
+			in: "null",
+		},
+		"plain text": {
+			in: "secret",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := flattenJSONObject([]byte(tc.in))
+
+			assert.Equal(t, ok, tc.ok)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
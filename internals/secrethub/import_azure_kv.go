@@ -0,0 +1,111 @@
+package secrethub
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+const (
+	// azurePathSeparator replaces the slashes of secret paths in the names of Azure secrets,
+	// as Azure secret names can only contain letters, numbers and dashes.
+	azurePathSeparator = "--"
+
+	// azurePKCS12ContentType is the content type of the secrets of certificates that are stored
+	// in the PKCS #12 format. Their value is base64 encoded.
+	azurePKCS12ContentType = "application/x-pkcs12"
+)
+
+// ImportAzureKVCommand imports secrets from Azure Key Vault.
+type ImportAzureKVCommand struct {
+	io                  ui.IO
+	vault               string
+	tags                map[string]string
+	includeCertificates bool
+	importer            secretImporter
+	newClient           newClientFunc
+	newKeyVault         newAzureKeyVaultFunc
+}
+
+// NewImportAzureKVCommand creates a new ImportAzureKVCommand.
+func NewImportAzureKVCommand(io ui.IO, newClient newClientFunc) *ImportAzureKVCommand {
+	return &ImportAzureKVCommand{
+		io:          io,
+		tags:        make(map[string]string),
+		newClient:   newClient,
+		newKeyVault: newAzureKeyVault,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportAzureKVCommand) Register(r command.Registerer) {
+	clause := r.Command("azure-kv", "Import the latest version of the secrets in an Azure Key Vault. "+
+		"Authenticates with the service principal in AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET or else with the Azure CLI. "+
+		"Every "+azurePathSeparator+" in the name of an Azure secret creates a subdirectory. Disabled secrets are skipped.")
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("vault", "The name or URL of the Azure Key Vault to import the secrets from.").Required().StringVar(&cmd.vault)
+	clause.Flag("tag", "Only import Azure secrets that have this tag, given as `KEY=VALUE`. Can be repeated.").StringMapVar(&cmd.tags)
+	clause.Flag("include-certificates", "Also import the private keys of the certificates in the vault. PKCS #12 certificates are imported in their binary format.").BoolVar(&cmd.includeCertificates)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the secrets from Azure Key Vault.
+func (cmd *ImportAzureKVCommand) Run() error {
+	kv, err := cmd.newKeyVault(cmd.vault)
+	if err != nil {
+		return err
+	}
+
+	azureSecrets, err := kv.ListSecrets()
+	if err != nil {
+		return err
+	}
+	sort.Slice(azureSecrets, func(i, j int) bool {
+		return azureSecrets[i].Name < azureSecrets[j].Name
+	})
+
+	var secrets []namedSecret
+	for _, azureSecret := range azureSecrets {
+		if azureSecret.Managed && !cmd.includeCertificates {
+			continue
+		}
+		if !hasLabels(azureSecret.Tags, cmd.tags) {
+			continue
+		}
+		if !azureSecret.Enabled {
+			fmt.Fprintf(cmd.io.Output(), "Skipped %s: the Azure secret is disabled\n", azureSecret.Name)
+			continue
+		}
+
+		value, err := kv.GetSecret(azureSecret.Name)
+		if err != nil {
+			return err
+		}
+
+		data := []byte(value)
+		if azureSecret.Managed && azureSecret.ContentType == azurePKCS12ContentType {
+			data, err = base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return err
+			}
+		}
+
+		secrets = append(secrets, namedSecret{
+			Name:  strings.Replace(azureSecret.Name, azurePathSeparator, "/", -1),
+			Value: data,
+		})
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
@@ -0,0 +1,151 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+// fakeAzureKeyVault is a fake Azure Key Vault that stores secrets in memory.
+type fakeAzureKeyVault struct {
+	secrets   []azureSecret
+	values    map[string]string
+	deleted   map[string]bool
+	recovered []string
+}
+
+func (kv *fakeAzureKeyVault) ListSecrets() ([]azureSecret, error) {
+	return kv.secrets, nil
+}
+
+func (kv *fakeAzureKeyVault) GetSecret(name string) (string, error) {
+	return kv.values[name], nil
+}
+
+func (kv *fakeAzureKeyVault) SetSecret(name string, value string, tags map[string]string) error {
+	if kv.deleted[name] {
+		return errAzureSecretDeleted
+	}
+	kv.values[name] = value
+	return nil
+}
+
+func (kv *fakeAzureKeyVault) RecoverSecret(name string) error {
+	delete(kv.deleted, name)
+	kv.recovered = append(kv.recovered, name)
+	return nil
+}
+
+func TestImportAzureKVCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	cases := map[string]struct {
+		tags                map[string]string
+		includeCertificates bool
+		kvErr               error
+		written             []string
+		out                 string
+		err                 error
+	}{
+		"secrets": {
+			written: []string{
+				"namespace/repo/api-key:abc",
+				"namespace/repo/db/password:secret",
+			},
+			out: "Skipped old: the Azure secret is disabled\n" +
+				"Wrote namespace/repo/api-key\n" +
+				"Wrote namespace/repo/db/password\n" +
+				"Import complete! Secrets: 2 written, 0 skipped.\n",
+		},
+		"include certificates": {
+			includeCertificates: true,
+			written: []string{
+				"namespace/repo/api-key:abc",
+				"namespace/repo/cert:pfx",
+				"namespace/repo/db/password:secret",
+			},
+			out: "Skipped old: the Azure secret is disabled\n" +
+				"Wrote namespace/repo/api-key\n" +
+				"Wrote namespace/repo/cert\n" +
+				"Wrote namespace/repo/db/password\n" +
+				"Import complete! Secrets: 3 written, 0 skipped.\n",
+		},
+		"tag filter": {
+			tags: map[string]string{"env": "prod"},
+			written: []string{
+				"namespace/repo/db/password:secret",
+			},
+			out: "Wrote namespace/repo/db/password\n" +
+				"Import complete! Secrets: 1 written, 0 skipped.\n",
+		},
+		"key vault error": {
+			kvErr: testErr,
+			err:   testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kv := &fakeAzureKeyVault{
+				secrets: []azureSecret{
+					{Name: "db--password", Enabled: true, Tags: map[string]string{"env": "prod"}},
+					{Name: "old", Enabled: false},
+					{Name: "cert", Enabled: true, Managed: true, ContentType: azurePKCS12ContentType},
+					{Name: "api-key", Enabled: true},
+				},
+				values: map[string]string{
+					"db--password": "secret",
+					"cert":         "cGZ4",
+					"api-key":      "abc",
+				},
+			}
+
+			var written []string
+			io := fakeui.NewIO(t)
+			cmd := ImportAzureKVCommand{
+				io:                  io,
+				vault:               "myvault",
+				tags:                tc.tags,
+				includeCertificates: tc.includeCertificates,
+				importer: secretImporter{
+					dirPath:    api.DirPath("namespace/repo"),
+					onConflict: conflictSkip,
+				},
+				newKeyVault: func(vault string) (azureKeyVault, error) {
+					return kv, tc.kvErr
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{
+						Client: fakeclient.Client{
+							DirService: &fakeclient.DirService{
+								ExistsFunc: func(path string) (bool, error) {
+									return true, nil
+								},
+							},
+						},
+						secrets: importTestSecretService{
+							SecretService: &fakeclient.SecretService{
+								WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+									written = append(written, path+":"+string(data))
+									return nil, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, written, tc.written)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrInvalidCSV          = errMain.Code("invalid_csv").ErrorPref("invalid CSV file: %s")
+	ErrInvalidCSVColumn    = errMain.Code("invalid_csv_column").ErrorPref("the %s column %q does not exist")
+	ErrInvalidCSVDelimiter = errMain.Code("invalid_csv_delimiter").ErrorPref("invalid delimiter %q: must be a single character")
+)
+
+// ImportCSVCommand imports the rows of a CSV file as secrets.
+type ImportCSVCommand struct {
+	io          ui.IO
+	file        string
+	pathColumn  string
+	valueColumn string
+	noHeader    bool
+	delimiter   string
+	importer    secretImporter
+	newClient   newClientFunc
+}
+
+// NewImportCSVCommand creates a new ImportCSVCommand.
+func NewImportCSVCommand(io ui.IO, newClient newClientFunc) *ImportCSVCommand {
+	return &ImportCSVCommand{
+		io:        io,
+		newClient: newClient,
+		importer: secretImporter{
+			progress:        true,
+			continueOnError: true,
+		},
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportCSVCommand) Register(r command.Registerer) {
+	clause := r.Command("csv", "Import every row of a CSV file as a secret. One column holds the path of the secret relative to the directory "+
+		"it is imported into, which can contain subdirectories, and another column holds its value. "+
+		"Secrets that cannot be written do not stop the import: they are reported at the end.")
+	clause.Arg("csv-file", "The path to the CSV file").Required().ExistingFileVar(&cmd.file)
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("path-column", "The name of the column with the secret paths or its position, starting at 1.").Default("path").StringVar(&cmd.pathColumn)
+	clause.Flag("value-column", "The name of the column with the secret values or its position, starting at 1.").Default("value").StringVar(&cmd.valueColumn)
+	clause.Flag("no-header", "The first row of the file is not a header but a secret. Columns can then only be given by position and default to 1 for the path and 2 for the value.").BoolVar(&cmd.noHeader)
+	clause.Flag("delimiter", "The character that separates the columns.").Default(",").StringVar(&cmd.delimiter)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the CSV file.
+func (cmd *ImportCSVCommand) Run() error {
+	data, err := ioutil.ReadFile(cmd.file)
+	if err != nil {
+		return ErrReadFile(cmd.file, err)
+	}
+
+	secrets, err := cmd.parse(data)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
+
+// parse returns the secrets in the rows of the CSV file.
+func (cmd *ImportCSVCommand) parse(data []byte) ([]namedSecret, error) {
+	delimiter, size := utf8.DecodeRuneInString(cmd.delimiter)
+	if size == 0 || size != len(cmd.delimiter) {
+		return nil, ErrInvalidCSVDelimiter(cmd.delimiter)
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, ErrInvalidCSV(err)
+	}
+
+	var header []string
+	if !cmd.noHeader {
+		if len(rows) == 0 {
+			return nil, ErrInvalidCSV("the file is empty")
+		}
+		header = rows[0]
+		rows = rows[1:]
+	}
+
+	pathColumn, valueColumn := cmd.pathColumn, cmd.valueColumn
+	if cmd.noHeader {
+		// The defaults are column names, which cannot be used without a header.
+		if pathColumn == "path" {
+			pathColumn = "1"
+		}
+		if valueColumn == "value" {
+			valueColumn = "2"
+		}
+	}
+
+	pathIndex, err := csvColumnIndex(header, "path", pathColumn)
+	if err != nil {
+		return nil, err
+	}
+	valueIndex, err := csvColumnIndex(header, "value", valueColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	line := 1
+	if !cmd.noHeader {
+		line++
+	}
+
+	secrets := make([]namedSecret, 0, len(rows))
+	for i, row := range rows {
+		if pathIndex >= len(row) || valueIndex >= len(row) {
+			return nil, ErrInvalidCSV(fmt.Sprintf("row %d has too few columns", line+i))
+		}
+		path := strings.Trim(strings.TrimSpace(row[pathIndex]), "/")
+		if path == "" {
+			return nil, ErrInvalidCSV(fmt.Sprintf("row %d has no path", line+i))
+		}
+		secrets = append(secrets, namedSecret{Name: path, Value: []byte(row[valueIndex])})
+	}
+	return secrets, nil
+}
+
+// csvColumnIndex returns the index of the column that is given by its name in the header or by its position, starting at 1.
+func csvColumnIndex(header []string, kind string, column string) (int, error) {
+	for i, name := range header {
+		// Excel prepends a byte order mark to CSV files.
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+
+	position, err := strconv.Atoi(column)
+	if err != nil || position < 1 || (header != nil && position > len(header)) {
+		return 0, ErrInvalidCSVColumn(kind, column)
+	}
+	return position - 1, nil
+}
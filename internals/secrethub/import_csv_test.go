@@ -0,0 +1,116 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestImportCSVCommand_parse(t *testing.T) {
+	cases := map[string]struct {
+		cmd      ImportCSVCommand
+		in       string
+		expected []namedSecret
+		err      error
+	}{
+		"header": {
+			cmd: ImportCSVCommand{pathColumn: "path", valueColumn: "value", delimiter: ","},
+			in:  "\ufeffPath,description,Value\ndb/password,The password,hunter2\n/api/key/,,\"a,b\"\n",
+			expected: []namedSecret{
+				{Name: "db/password", Value: []byte("hunter2")},
+				{Name: "api/key", Value: []byte("a,b")},
+			},
+		},
+		"columns by position": {
+			cmd: ImportCSVCommand{pathColumn: "3", valueColumn: "1", delimiter: ";"},
+			in:  "value;x;name\nsecret;;key\n",
+			expected: []namedSecret{
+				{Name: "key", Value: []byte("secret")},
+			},
+		},
+		"no header": {
+			cmd: ImportCSVCommand{pathColumn: "path", valueColumn: "value", delimiter: ",", noHeader: true},
+			in:  "key,secret\n",
+			expected: []namedSecret{
+				{Name: "key", Value: []byte("secret")},
+			},
+		},
+		"unknown column": {
+			cmd: ImportCSVCommand{pathColumn: "name", valueColumn: "value", delimiter: ","},
+			in:  "path,value\n",
+			err: ErrInvalidCSVColumn("path", "name"),
+		},
+		"too few columns": {
+			cmd: ImportCSVCommand{pathColumn: "path", valueColumn: "value", delimiter: ","},
+			in:  "path,value\nkey\n",
+			err: ErrInvalidCSV("row 2 has too few columns"),
+		},
+		"no path": {
+			cmd: ImportCSVCommand{pathColumn: "path", valueColumn: "value", delimiter: ","},
+			in:  "path,value\n,secret\n",
+			err: ErrInvalidCSV("row 2 has no path"),
+		},
+		"invalid delimiter": {
+			cmd: ImportCSVCommand{pathColumn: "path", valueColumn: "value", delimiter: "::"},
+			err: ErrInvalidCSVDelimiter("::"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tc.cmd.parse([]byte(tc.in))
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSecretImporter_importSecrets_continueOnError(t *testing.T) {
+	io := fakeui.NewIO(t)
+	importer := secretImporter{
+		dirPath:         api.DirPath("namespace/repo"),
+		onConflict:      conflictSkip,
+		progress:        true,
+		continueOnError: true,
+	}
+	client := importTestClient{
+		Client: fakeclient.Client{
+			DirService: &fakeclient.DirService{
+				ExistsFunc: func(path string) (bool, error) {
+					return true, nil
+				},
+			},
+		},
+		secrets: importTestSecretService{
+			SecretService: &fakeclient.SecretService{
+				WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+					if path == "namespace/repo/broken" {
+						return nil, errors.New("write failed")
+					}
+					return nil, nil
+				},
+			},
+			existing: map[string]bool{"namespace/repo/existing": true},
+		},
+	}
+
+	err := importer.importSecrets(io, client, []namedSecret{
+		{Name: "new", Value: []byte("v")},
+		{Name: "broken", Value: []byte("v")},
+		{Name: "existing", Value: []byte("v")},
+	})
+
+	assert.Equal(t, err, ErrImportFailed(1))
+	assert.Equal(t, io.Out.String(), "[1/3] Wrote namespace/repo/new\n"+
+		"[2/3] Failed namespace/repo/broken: write failed\n"+
+		"[3/3] Skipped namespace/repo/existing: the secret already exists\n"+
+		"Import finished with errors! Secrets: 1 written, 1 skipped, 1 failed.\n")
+}
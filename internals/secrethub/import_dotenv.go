@@ -0,0 +1,208 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/validation"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrDotEnvSyntax = errMain.Code("dotenv_syntax").ErrorPref("line %d: %s")
+)
+
+// ImportDotEnvCommand imports the variables of a .env file as secrets.
+type ImportDotEnvCommand struct {
+	io            ui.IO
+	file          string
+	importer      secretImporter
+	outputEnvFile string
+	newClient     newClientFunc
+}
+
+// NewImportDotEnvCommand creates a new ImportDotEnvCommand.
+func NewImportDotEnvCommand(io ui.IO, newClient newClientFunc) *ImportDotEnvCommand {
+	return &ImportDotEnvCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportDotEnvCommand) Register(r command.Registerer) {
+	clause := r.Command("dotenv", "Import every variable of a .env file as a secret with the name of the variable. "+
+		"Values can be unquoted, single quoted or double quoted and quoted values can span multiple lines. "+
+		"Double quoted values support the escape sequences \\n, \\r, \\t, \\\" and \\\\.")
+	clause.Arg("env-file", "The path to the .env file").Required().ExistingFileVar(&cmd.file)
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("output-env-file", "Write a copy of the .env file in which every value is replaced with a secrethub:// reference to its secret to this path. It can be used with `secrethub run`.").StringVar(&cmd.outputEnvFile)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the .env file.
+func (cmd *ImportDotEnvCommand) Run() error {
+	data, err := ioutil.ReadFile(cmd.file)
+	if err != nil {
+		return ErrReadFile(cmd.file, err)
+	}
+
+	vars, err := parseDotEnvFile(data)
+	if err != nil {
+		return err
+	}
+
+	secrets := make([]namedSecret, len(vars))
+	for i, v := range vars {
+		secrets[i] = namedSecret{Name: v.key, Value: []byte(v.value)}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.importer.importSecrets(cmd.io, client, secrets)
+	if err != nil {
+		return err
+	}
+
+	if cmd.outputEnvFile != "" {
+		var buf bytes.Buffer
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "%s=%s%s\n", v.key, secretReferencePrefix, cmd.importer.secretPath(v.key))
+		}
+
+		err = ioutil.WriteFile(cmd.outputEnvFile, buf.Bytes(), 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Wrote the env file with references to %s\n", cmd.outputEnvFile)
+	}
+
+	return nil
+}
+
+// dotEnvVar is a variable defined in a .env file.
+type dotEnvVar struct {
+	key   string
+	value string
+}
+
+// parseDotEnvFile parses the variables of a .env file, in the order in which they are first defined.
+// When a variable is defined more than once, the last value is used.
+func parseDotEnvFile(data []byte) ([]dotEnvVar, error) {
+	var vars []dotEnvVar
+	index := map[string]int{}
+
+	lines := strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, ErrDotEnvSyntax(lineNo, "expected KEY=value")
+		}
+
+		key := strings.TrimSpace(parts[0])
+		err := validation.ValidateEnvarName(key)
+		if err != nil {
+			return nil, ErrDotEnvSyntax(lineNo, err)
+		}
+
+		raw := strings.TrimSpace(parts[1])
+		var value string
+		if strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, `'`) {
+			quote := raw[0]
+			rest := raw[1:]
+			// Quoted values continue on the next lines until the closing quote.
+			for {
+				end := closingQuote(rest, quote)
+				if end >= 0 {
+					trailing := strings.TrimSpace(rest[end+1:])
+					if trailing != "" && !strings.HasPrefix(trailing, "#") {
+						return nil, ErrDotEnvSyntax(lineNo, "unexpected characters after the closing quote")
+					}
+					rest = rest[:end]
+					break
+				}
+				i++
+				if i >= len(lines) {
+					return nil, ErrDotEnvSyntax(lineNo, "missing closing quote")
+				}
+				rest += "\n" + lines[i]
+			}
+
+			value = rest
+			if quote == '"' {
+				value = unescapeDotEnvValue(value)
+			}
+		} else {
+			value = raw
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = strings.TrimSpace(value[:idx])
+			}
+		}
+
+		if j, ok := index[key]; ok {
+			vars[j].value = value
+			continue
+		}
+		index[key] = len(vars)
+		vars = append(vars, dotEnvVar{key: key, value: value})
+	}
+	return vars, nil
+}
+
+// closingQuote returns the index of the first unescaped quote in s or -1 when there is none.
+// Backslashes only escape characters in double quoted values.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDotEnvValue replaces the escape sequences in a double quoted value.
+func unescapeDotEnvValue(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case '"', '\\':
+			buf.WriteByte(s[i])
+		default:
+			buf.WriteByte('\\')
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.String()
+}
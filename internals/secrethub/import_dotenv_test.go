@@ -0,0 +1,218 @@
+package secrethub
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestParseDotEnvFile(t *testing.T) {
+	cases := map[string]struct {
+		in       string
+		expected []dotEnvVar
+		err      error
+	}{
+		"unquoted": {
+			in:       "FOO=bar\nBAZ = qux \n",
+			expected: []dotEnvVar{{key: "FOO", value: "bar"}, {key: "BAZ", value: "qux"}},
+		},
+		"comments": {
+			in:       "# comment\n\nFOO=bar # inline comment\nBAZ=a#b\n",
+			expected: []dotEnvVar{{key: "FOO", value: "bar"}, {key: "BAZ", value: "a#b"}},
+		},
+		"export": {
+			in:       "export FOO=bar\n",
+			expected: []dotEnvVar{{key: "FOO", value: "bar"}},
+		},
+		"single quotes": {
+			in:       `FOO='bar # \n baz'`,
+			expected: []dotEnvVar{{key: "FOO", value: `bar # \n baz`}},
+		},
+		"double quotes with escapes": {
+			in:       `FOO="a\nb\t\"c\"\\" # comment`,
+			expected: []dotEnvVar{{key: "FOO", value: "a\nb\t\"c\"\\"}},
+		},
+		"multiline": {
+			in: "KEY=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\nFOO=bar\n",
+			expected: []dotEnvVar{
+				{key: "KEY", value: "-----BEGIN KEY-----\nabc\n-----END KEY-----"},
+				{key: "FOO", value: "bar"},
+			},
+		},
+		"windows line endings": {
+			in:       "FOO=bar\r\nBAZ=qux\r\n",
+			expected: []dotEnvVar{{key: "FOO", value: "bar"}, {key: "BAZ", value: "qux"}},
+		},
+		"duplicate keys": {
+			in:       "FOO=1\nBAR=2\nFOO=3\n",
+			expected: []dotEnvVar{{key: "FOO", value: "3"}, {key: "BAR", value: "2"}},
+		},
+		"missing equals sign": {
+			in:  "FOO=bar\nBAZ\n",
+			err: ErrDotEnvSyntax(2, "expected KEY=value"),
+		},
+		"missing closing quote": {
+			in:  "FOO=bar\nBAZ=\"qux\n",
+			err: ErrDotEnvSyntax(2, "missing closing quote"),
+		},
+		"characters after closing quote": {
+			in:  "FOO='bar'baz\n",
+			err: ErrDotEnvSyntax(1, "unexpected characters after the closing quote"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseDotEnvFile([]byte(tc.in))
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestImportDotEnvCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	cases := map[string]struct {
+		in            string
+		onConflict    string
+		existing      map[string]bool
+		outputEnvFile bool
+		clientErr     error
+		written       []string
+		created       []string
+		out           string
+		envFile       string
+		err           error
+	}{
+		"success": {
+			in:         "FOO=bar\nBAZ=\"qux\"\n",
+			onConflict: conflictSkip,
+			written:    []string{"namespace/repo/dir/FOO:bar", "namespace/repo/dir/BAZ:qux"},
+			created:    []string{"namespace/repo/dir"},
+			out: "Wrote namespace/repo/dir/FOO\n" +
+				"Wrote namespace/repo/dir/BAZ\n" +
+				"Import complete! Secrets: 2 written, 0 skipped.\n",
+		},
+		"skip existing": {
+			in:         "FOO=bar\nBAZ=qux\n",
+			onConflict: conflictSkip,
+			existing:   map[string]bool{"namespace/repo/dir/FOO": true},
+			written:    []string{"namespace/repo/dir/BAZ:qux"},
+			created:    []string{"namespace/repo/dir"},
+			out: "Skipped namespace/repo/dir/FOO: the secret already exists\n" +
+				"Wrote namespace/repo/dir/BAZ\n" +
+				"Import complete! Secrets: 1 written, 1 skipped.\n",
+		},
+		"overwrite existing": {
+			in:         "FOO=bar\n",
+			onConflict: conflictOverwrite,
+			existing:   map[string]bool{"namespace/repo/dir/FOO": true},
+			written:    []string{"namespace/repo/dir/FOO:bar"},
+			created:    []string{"namespace/repo/dir"},
+			out: "Wrote namespace/repo/dir/FOO\n" +
+				"Import complete! Secrets: 1 written, 0 skipped.\n",
+		},
+		"output env file": {
+			in:            "FOO=bar\n",
+			onConflict:    conflictSkip,
+			outputEnvFile: true,
+			written:       []string{"namespace/repo/dir/FOO:bar"},
+			created:       []string{"namespace/repo/dir"},
+			out: "Wrote namespace/repo/dir/FOO\n" +
+				"Import complete! Secrets: 1 written, 0 skipped.\n" +
+				"Wrote the env file with references to ",
+			envFile: "FOO=secrethub://namespace/repo/dir/FOO\n",
+		},
+		"invalid conflict strategy": {
+			in:         "FOO=bar\n",
+			onConflict: "merge",
+			err:        ErrInvalidImportConflictStrategy("merge"),
+		},
+		"syntax error": {
+			in:         "FOO\n",
+			onConflict: conflictSkip,
+			err:        ErrDotEnvSyntax(1, "expected KEY=value"),
+		},
+		"client error": {
+			in:         "FOO=bar\n",
+			onConflict: conflictSkip,
+			clientErr:  testErr,
+			err:        testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			file := filepath.Join(dir, ".env")
+			assert.OK(t, ioutil.WriteFile(file, []byte(tc.in), 0600))
+
+			var written, created []string
+			io := fakeui.NewIO(t)
+			cmd := ImportDotEnvCommand{
+				io:   io,
+				file: file,
+				importer: secretImporter{
+					dirPath:    api.DirPath("namespace/repo/dir"),
+					onConflict: tc.onConflict,
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					if tc.clientErr != nil {
+						return nil, tc.clientErr
+					}
+					return importTestClient{
+						Client: fakeclient.Client{
+							DirService: &fakeclient.DirService{
+								ExistsFunc: func(path string) (bool, error) {
+									return false, nil
+								},
+								CreateFunc: func(path string) (*api.Dir, error) {
+									created = append(created, path)
+									return nil, nil
+								},
+							},
+						},
+						secrets: importTestSecretService{
+							SecretService: &fakeclient.SecretService{
+								WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+									written = append(written, path+":"+string(data))
+									return nil, nil
+								},
+							},
+							existing: tc.existing,
+						},
+					}, nil
+				},
+			}
+			out := tc.out
+			if tc.outputEnvFile {
+				cmd.outputEnvFile = filepath.Join(dir, ".env.secrethub")
+				out += cmd.outputEnvFile + "\n"
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), out)
+			assert.Equal(t, written, tc.written)
+			assert.Equal(t, created, tc.created)
+			if tc.outputEnvFile {
+				envFile, err := ioutil.ReadFile(cmd.outputEnvFile)
+				assert.OK(t, err)
+				assert.Equal(t, string(envFile), tc.envFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package secrethub
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrNoEnvVarsMatched = errMain.Code("no_env_vars_matched").ErrorPref("no environment variables match %s")
+)
+
+// ImportEnvCommand imports variables of the current environment as secrets.
+type ImportEnvCommand struct {
+	io        ui.IO
+	only      []string
+	osEnv     func() []string
+	importer  secretImporter
+	newClient newClientFunc
+}
+
+// NewImportEnvCommand creates a new ImportEnvCommand.
+func NewImportEnvCommand(io ui.IO, newClient newClientFunc) *ImportEnvCommand {
+	return &ImportEnvCommand{
+		io:        io,
+		osEnv:     os.Environ,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportEnvCommand) Register(r command.Registerer) {
+	clause := r.Command("env", "Import variables of the environment the command is run in as secrets with the name of the variable. "+
+		"This is the quickest way to move an app that is configured with exported shell variables to SecretHub.")
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("only", "A comma-separated list of patterns of the variables to import, e.g. 'API_*,DB_*'. "+
+		"A * matches any sequence of characters and a ? matches a single character. Can be repeated.").Required().StringsVar(&cmd.only)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the environment variables.
+func (cmd *ImportEnvCommand) Run() error {
+	secrets, err := cmd.envSecrets()
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
+
+// envSecrets returns the environment variables that match the patterns as secrets, sorted by name.
+func (cmd *ImportEnvCommand) envSecrets() ([]namedSecret, error) {
+	var patterns []string
+	for _, value := range cmd.only {
+		for _, pattern := range strings.Split(value, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			_, err := path.Match(pattern, "")
+			if err != nil {
+				return nil, ErrInvalidFilter(pattern, err)
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	var secrets []namedSecret
+	for _, variable := range cmd.osEnv() {
+		split := strings.SplitN(variable, "=", 2)
+		if len(split) != 2 {
+			continue
+		}
+		for _, pattern := range patterns {
+			matched, _ := path.Match(pattern, split[0])
+			if matched {
+				secrets = append(secrets, namedSecret{Name: split[0], Value: []byte(split[1])})
+				break
+			}
+		}
+	}
+
+	if len(secrets) == 0 {
+		return nil, ErrNoEnvVarsMatched(strings.Join(patterns, ","))
+	}
+
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	return secrets, nil
+}
@@ -0,0 +1,57 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestImportEnvCommand_envSecrets(t *testing.T) {
+	osEnv := func() []string {
+		return []string{"PATH=/usr/bin", "DB_PASSWORD=a=b", "API_KEY=key", "API_URL=", "HOME=/root"}
+	}
+
+	cases := map[string]struct {
+		only     []string
+		expected []namedSecret
+		err      error
+	}{
+		"patterns": {
+			only: []string{"API_*, DB_*"},
+			expected: []namedSecret{
+				{Name: "API_KEY", Value: []byte("key")},
+				{Name: "API_URL", Value: []byte("")},
+				{Name: "DB_PASSWORD", Value: []byte("a=b")},
+			},
+		},
+		"repeated flag": {
+			only: []string{"HOME", "API_?EY"},
+			expected: []namedSecret{
+				{Name: "API_KEY", Value: []byte("key")},
+				{Name: "HOME", Value: []byte("/root")},
+			},
+		},
+		"no match": {
+			only: []string{"AWS_*"},
+			err:  ErrNoEnvVarsMatched("AWS_*"),
+		},
+		"invalid pattern": {
+			only: []string{"API_["},
+			err:  ErrInvalidFilter("API_[", "syntax error in pattern"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := ImportEnvCommand{
+				only:  tc.only,
+				osEnv: osEnv,
+			}
+
+			actual, err := cmd.envSecrets()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package secrethub
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// ImportGCPSMCommand imports secrets from GCP Secret Manager.
+type ImportGCPSMCommand struct {
+	io               ui.IO
+	project          string
+	labels           map[string]string
+	allVersions      bool
+	importer         secretImporter
+	newClient        newClientFunc
+	newSecretManager newGCPSecretManagerFunc
+}
+
+// NewImportGCPSMCommand creates a new ImportGCPSMCommand.
+func NewImportGCPSMCommand(io ui.IO, newClient newClientFunc) *ImportGCPSMCommand {
+	return &ImportGCPSMCommand{
+		io:               io,
+		labels:           make(map[string]string),
+		newClient:        newClient,
+		newSecretManager: newGCPSecretManager,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportGCPSMCommand) Register(r command.Registerer) {
+	clause := r.Command("gcp-sm", "Import secrets from GCP Secret Manager, authenticating with the application default credentials. "+
+		"Every "+gcpPathSeparator+" in the ID of a GCP secret creates a subdirectory.")
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("project", "The ID of the GCP project to import the secrets from.").Required().StringVar(&cmd.project)
+	clause.Flag("label", "Only import GCP secrets that have this label, given as `KEY=VALUE`. Can be repeated.").StringMapVar(&cmd.labels)
+	clause.Flag("all-versions", "Import all enabled versions of the GCP secrets, oldest first, instead of only the latest version.").BoolVar(&cmd.allVersions)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the secrets from GCP Secret Manager.
+func (cmd *ImportGCPSMCommand) Run() error {
+	sm, err := cmd.newSecretManager()
+	if err != nil {
+		return err
+	}
+
+	gcpSecrets, err := sm.ListSecrets(cmd.project)
+	if err != nil {
+		return err
+	}
+	sort.Slice(gcpSecrets, func(i, j int) bool {
+		return gcpSecrets[i].ID < gcpSecrets[j].ID
+	})
+
+	var secrets []namedSecret
+	for _, gcpSecret := range gcpSecrets {
+		if !hasLabels(gcpSecret.Labels, cmd.labels) {
+			continue
+		}
+
+		versions, err := sm.AccessVersions(cmd.project, gcpSecret.ID, cmd.allVersions)
+		if err != nil {
+			return err
+		}
+
+		name := strings.Replace(gcpSecret.ID, gcpPathSeparator, "/", -1)
+		for _, version := range versions {
+			secrets = append(secrets, namedSecret{Name: name, Value: version})
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
+
+// hasLabels returns whether all the expected labels are set to the expected value.
+func hasLabels(labels map[string]string, expected map[string]string) bool {
+	for key, value := range expected {
+		actual, ok := labels[key]
+		if !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
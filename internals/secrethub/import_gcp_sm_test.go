@@ -0,0 +1,160 @@
+package secrethub
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+// fakeGCPSecretManager is a fake GCP Secret Manager that stores the versions of secrets in memory.
+type fakeGCPSecretManager struct {
+	labels   map[string]map[string]string
+	versions map[string][]string
+}
+
+func (sm *fakeGCPSecretManager) ListSecrets(project string) ([]gcpSecret, error) {
+	var res []gcpSecret
+	for id := range sm.versions {
+		res = append(res, gcpSecret{ID: id, Labels: sm.labels[id]})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID > res[j].ID
+	})
+	return res, nil
+}
+
+func (sm *fakeGCPSecretManager) AccessVersions(project string, secretID string, all bool) ([][]byte, error) {
+	versions := sm.versions[secretID]
+	if !all {
+		versions = versions[len(versions)-1:]
+	}
+	res := make([][]byte, len(versions))
+	for i, version := range versions {
+		res[i] = []byte(version)
+	}
+	return res, nil
+}
+
+func (sm *fakeGCPSecretManager) CreateSecret(project string, secretID string, labels map[string]string) error {
+	if _, ok := sm.versions[secretID]; ok {
+		return errGCPSecretExists
+	}
+	sm.labels[secretID] = labels
+	sm.versions[secretID] = []string{}
+	return nil
+}
+
+func (sm *fakeGCPSecretManager) AddVersion(project string, secretID string, data []byte) error {
+	sm.versions[secretID] = append(sm.versions[secretID], string(data))
+	return nil
+}
+
+func TestImportGCPSMCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	cases := map[string]struct {
+		labels      map[string]string
+		allVersions bool
+		smErr       error
+		written     []string
+		out         string
+		err         error
+	}{
+		"latest versions": {
+			written: []string{
+				"namespace/repo/api_key:v2",
+				"namespace/repo/db/password:secret",
+			},
+			out: "Wrote namespace/repo/api_key\n" +
+				"Wrote namespace/repo/db/password\n" +
+				"Import complete! Secrets: 2 written, 0 skipped.\n",
+		},
+		"all versions": {
+			allVersions: true,
+			written: []string{
+				"namespace/repo/api_key:v1",
+				"namespace/repo/api_key:v2",
+				"namespace/repo/db/password:secret",
+			},
+			out: "Wrote namespace/repo/api_key\n" +
+				"Wrote namespace/repo/api_key\n" +
+				"Wrote namespace/repo/db/password\n" +
+				"Import complete! Secrets: 2 written, 0 skipped.\n",
+		},
+		"label filter": {
+			labels: map[string]string{"env": "prod"},
+			written: []string{
+				"namespace/repo/db/password:secret",
+			},
+			out: "Wrote namespace/repo/db/password\n" +
+				"Import complete! Secrets: 1 written, 0 skipped.\n",
+		},
+		"secret manager error": {
+			smErr: testErr,
+			err:   testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sm := &fakeGCPSecretManager{
+				labels: map[string]map[string]string{
+					"db__password": {"env": "prod"},
+					"api_key":      {"env": "dev"},
+				},
+				versions: map[string][]string{
+					"db__password": {"secret"},
+					"api_key":      {"v1", "v2"},
+				},
+			}
+
+			var written []string
+			io := fakeui.NewIO(t)
+			cmd := ImportGCPSMCommand{
+				io:          io,
+				project:     "my-project",
+				labels:      tc.labels,
+				allVersions: tc.allVersions,
+				importer: secretImporter{
+					dirPath:    api.DirPath("namespace/repo"),
+					onConflict: conflictSkip,
+				},
+				newSecretManager: func() (gcpSecretManager, error) {
+					return sm, tc.smErr
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{
+						Client: fakeclient.Client{
+							DirService: &fakeclient.DirService{
+								ExistsFunc: func(path string) (bool, error) {
+									return true, nil
+								},
+							},
+						},
+						secrets: importTestSecretService{
+							SecretService: &fakeclient.SecretService{
+								WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+									written = append(written, path+":"+string(data))
+									return nil, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, written, tc.written)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrKubectlFailed      = errMain.Code("kubectl_failed").ErrorPref("kubectl failed: %s")
+	ErrInvalidK8sSecret   = errMain.Code("invalid_k8s_secret").ErrorPref("cannot decode key %s of Kubernetes secret %s: %s")
+	ErrInvalidK8sResponse = errMain.Code("invalid_k8s_response").ErrorPref("cannot parse the secrets returned by kubectl: %s")
+)
+
+// k8sSkippedSecretTypes are the types of Kubernetes secrets that are managed by Kubernetes or other
+// tools and are therefore not imported.
+var k8sSkippedSecretTypes = map[string]bool{
+	"kubernetes.io/service-account-token": true,
+	"helm.sh/release.v1":                  true,
+}
+
+// kubectlFunc runs kubectl with the given arguments and returns its output.
+type kubectlFunc func(args ...string) ([]byte, error)
+
+// runKubectl runs kubectl, which uses the kubeconfig of the environment.
+func runKubectl(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	kubectl := exec.Command("kubectl", args...)
+	kubectl.Stderr = &stderr
+
+	out, err := kubectl.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, ErrKubectlFailed(msg)
+	}
+	return out, nil
+}
+
+// ImportK8sCommand imports Kubernetes secrets.
+type ImportK8sCommand struct {
+	io          ui.IO
+	namespace   string
+	selector    string
+	kubeContext string
+	kubeconfig  string
+	importer    secretImporter
+	newClient   newClientFunc
+	kubectl     kubectlFunc
+}
+
+// NewImportK8sCommand creates a new ImportK8sCommand.
+func NewImportK8sCommand(io ui.IO, newClient newClientFunc) *ImportK8sCommand {
+	return &ImportK8sCommand{
+		io:        io,
+		newClient: newClient,
+		kubectl:   runKubectl,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportK8sCommand) Register(r command.Registerer) {
+	clause := r.Command("k8s", "Import Kubernetes secrets, read with kubectl using the kubeconfig of the environment. "+
+		"Every Kubernetes secret becomes a directory with a secret for every key. Service account tokens and Helm releases are skipped. "+
+		"Use --dry-run to see what will be created.")
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("namespace", "The Kubernetes namespace to import the secrets from. Defaults to the namespace of the current context.").StringVar(&cmd.namespace)
+	clause.Flag("selector", "Only import the Kubernetes secrets that match this label selector, e.g. app=payments.").StringVar(&cmd.selector)
+	clause.Flag("context", "The kubeconfig context to use.").StringVar(&cmd.kubeContext)
+	clause.Flag("kubeconfig", "The path to the kubeconfig file to use.").StringVar(&cmd.kubeconfig)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the Kubernetes secrets.
+func (cmd *ImportK8sCommand) Run() error {
+	args := []string{"get", "secrets", "--output", "json"}
+	if cmd.namespace != "" {
+		args = append(args, "--namespace", cmd.namespace)
+	}
+	if cmd.selector != "" {
+		args = append(args, "--selector", cmd.selector)
+	}
+	if cmd.kubeContext != "" {
+		args = append(args, "--context", cmd.kubeContext)
+	}
+	if cmd.kubeconfig != "" {
+		args = append(args, "--kubeconfig", cmd.kubeconfig)
+	}
+
+	out, err := cmd.kubectl(args...)
+	if err != nil {
+		return err
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Type string            `json:"type"`
+			Data map[string]string `json:"data"`
+		} `json:"items"`
+	}
+	err = json.Unmarshal(out, &list)
+	if err != nil {
+		return ErrInvalidK8sResponse(err)
+	}
+
+	var secrets []namedSecret
+	for _, item := range list.Items {
+		if k8sSkippedSecretTypes[item.Type] {
+			fmt.Fprintf(cmd.io.Output(), "Skipped %s: secrets of type %s are managed by Kubernetes or Helm\n", item.Metadata.Name, item.Type)
+			continue
+		}
+
+		keys := make([]string, 0, len(item.Data))
+		for key := range item.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			// The values of Kubernetes secrets are base64 encoded.
+			value, err := base64.StdEncoding.DecodeString(item.Data[key])
+			if err != nil {
+				return ErrInvalidK8sSecret(key, item.Metadata.Name, err)
+			}
+			secrets = append(secrets, namedSecret{Name: item.Metadata.Name + "/" + key, Value: value})
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
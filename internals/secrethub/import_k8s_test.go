@@ -0,0 +1,113 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestImportK8sCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	secrets := `{"items":[
+		{"metadata":{"name":"payments-db"},"type":"Opaque","data":{"password":"c2VjcmV0","user":"YWRtaW4="}},
+		{"metadata":{"name":"default-token-abcde"},"type":"kubernetes.io/service-account-token","data":{"token":"dG9rZW4="}}
+	]}`
+
+	cases := map[string]struct {
+		out        string
+		kubectlOut string
+		kubectlErr error
+		dryRun     bool
+		written    []string
+		err        error
+	}{
+		"success": {
+			kubectlOut: secrets,
+			written: []string{
+				"namespace/repo/payments-db/password:secret",
+				"namespace/repo/payments-db/user:admin",
+			},
+			out: "Skipped default-token-abcde: secrets of type kubernetes.io/service-account-token are managed by Kubernetes or Helm\n" +
+				"Wrote namespace/repo/payments-db/password\n" +
+				"Wrote namespace/repo/payments-db/user\n" +
+				"Import complete! Secrets: 2 written, 0 skipped.\n",
+		},
+		"dry run": {
+			kubectlOut: secrets,
+			dryRun:     true,
+			out: "Skipped default-token-abcde: secrets of type kubernetes.io/service-account-token are managed by Kubernetes or Helm\n" +
+				"ACTION  PATH\n" +
+				"create  namespace/repo/payments-db/password\n" +
+				"create  namespace/repo/payments-db/user\n" +
+				"Dry run: 2 to create, 0 to update, 0 to skip, 0 unchanged. Nothing was written.\n",
+		},
+		"invalid base64": {
+			kubectlOut: `{"items":[{"metadata":{"name":"app"},"type":"Opaque","data":{"key":"%%%"}}]}`,
+			err:        ErrInvalidK8sSecret("key", "app", "illegal base64 data at input byte 0"),
+		},
+		"kubectl error": {
+			kubectlErr: testErr,
+			err:        testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var args []string
+			var written []string
+			io := fakeui.NewIO(t)
+			cmd := ImportK8sCommand{
+				io:        io,
+				namespace: "prod",
+				selector:  "app=payments",
+				importer: secretImporter{
+					dirPath:    api.DirPath("namespace/repo"),
+					onConflict: conflictSkip,
+					dryRun:     tc.dryRun,
+				},
+				kubectl: func(a ...string) ([]byte, error) {
+					args = a
+					return []byte(tc.kubectlOut), tc.kubectlErr
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{
+						Client: fakeclient.Client{
+							DirService: &fakeclient.DirService{
+								ExistsFunc: func(path string) (bool, error) {
+									return true, nil
+								},
+							},
+						},
+						secrets: importTestSecretService{
+							SecretService: &fakeclient.SecretService{
+								WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+									written = append(written, path+":"+string(data))
+									return nil, nil
+								},
+								VersionService: &fakeclient.SecretVersionService{
+									GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+										return nil, api.ErrSecretNotFound
+									},
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, written, tc.written)
+			assert.Equal(t, args, []string{"get", "secrets", "--output", "json", "--namespace", "prod", "--selector", "app=payments"})
+		})
+	}
+}
@@ -0,0 +1,499 @@
+package secrethub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrInvalidPasswordManagerExport = errMain.Code("invalid_password_manager_export").ErrorPref("invalid %s export: %s")
+)
+
+// The standard fields of password manager items.
+const (
+	passwordFieldUsername = "username"
+	passwordFieldPassword = "password"
+	passwordFieldURL      = "url"
+	passwordFieldTOTP     = "totp"
+	passwordFieldNotes    = "notes"
+)
+
+// maxSecretNameLength is the maximum length of a secret or directory name.
+const maxSecretNameLength = 32
+
+var invalidSecretNameCharacters = regexp.MustCompile(`[^_\-.a-zA-Z0-9]+`)
+
+// passwordItem is an item exported from a password manager.
+type passwordItem struct {
+	// Folders are the vault and folders the item is stored in, outermost first.
+	Folders []string
+	Title   string
+	Fields  []passwordField
+}
+
+// passwordField is a field of a password manager item.
+type passwordField struct {
+	Name  string
+	Value string
+}
+
+// field returns the value of the field with the given name or an empty string when the item does not have the field.
+func (item passwordItem) field(name string) string {
+	for _, field := range item.Fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// passwordManagerFormat is an export format of a password manager.
+type passwordManagerFormat struct {
+	command     string
+	description string
+	parse       func(data []byte) ([]passwordItem, error)
+}
+
+var (
+	onePasswordFormat = passwordManagerFormat{
+		command:     "1password",
+		description: "a 1Password export in the 1PUX format. Every vault becomes a directory",
+		parse:       parse1PUX,
+	}
+	bitwardenFormat = passwordManagerFormat{
+		command:     "bitwarden",
+		description: "an unencrypted Bitwarden export in the JSON or CSV format. Every folder becomes a directory",
+		parse:       parseBitwarden,
+	}
+	lastPassFormat = passwordManagerFormat{
+		command:     "lastpass",
+		description: "a LastPass CSV export. Every folder becomes a directory",
+		parse:       parseLastPassCSV,
+	}
+)
+
+// ImportPasswordManagerCommand imports the items of a password manager export.
+type ImportPasswordManagerCommand struct {
+	io        ui.IO
+	format    passwordManagerFormat
+	file      string
+	fields    bool
+	importer  secretImporter
+	newClient newClientFunc
+}
+
+// NewImportPasswordManagerCommand creates a new ImportPasswordManagerCommand for the given export format.
+func NewImportPasswordManagerCommand(io ui.IO, newClient newClientFunc, format passwordManagerFormat) *ImportPasswordManagerCommand {
+	return &ImportPasswordManagerCommand{
+		io:        io,
+		format:    format,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportPasswordManagerCommand) Register(r command.Registerer) {
+	clause := r.Command(cmd.format.command, "Import the items of "+cmd.format.description+". "+
+		"Every item becomes a secret named after the item, holding its password or, when it has no password, its notes. "+
+		"Names are stripped of characters that cannot be used in secret names.")
+	clause.Arg("export-file", "The path to the export file").Required().ExistingFileVar(&cmd.file)
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("fields", "Import every item as a directory with a secret for each of its fields, such as username, password, url, totp, notes and custom fields.").BoolVar(&cmd.fields)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the items of the export.
+func (cmd *ImportPasswordManagerCommand) Run() error {
+	data, err := ioutil.ReadFile(cmd.file)
+	if err != nil {
+		return ErrReadFile(cmd.file, err)
+	}
+
+	items, err := cmd.format.parse(data)
+	if err != nil {
+		return err
+	}
+
+	secrets := passwordItemSecrets(cmd.io.Output(), items, cmd.fields)
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
+
+// passwordItemSecrets converts password manager items to secrets. When fields is set, every item
+// becomes a directory with a secret per field. Otherwise every item becomes a secret with its password
+// or notes and items without either are skipped. Names that occur twice in a directory are numbered.
+func passwordItemSecrets(w io.Writer, items []passwordItem, fields bool) []namedSecret {
+	used := map[string]bool{}
+	uniqueName := func(dir string, name string) string {
+		unique := name
+		for i := 2; used[dir+unique]; i++ {
+			suffix := "-" + strconv.Itoa(i)
+			unique = name
+			if len(unique)+len(suffix) > maxSecretNameLength {
+				unique = unique[:maxSecretNameLength-len(suffix)]
+			}
+			unique += suffix
+		}
+		used[dir+unique] = true
+		return unique
+	}
+
+	var secrets []namedSecret
+	for _, item := range items {
+		dir := ""
+		for _, folder := range item.Folders {
+			dir += sanitizeSecretName(folder, "folder") + "/"
+		}
+		name := uniqueName(dir, sanitizeSecretName(item.Title, "item"))
+
+		if !fields {
+			value := item.field(passwordFieldPassword)
+			if value == "" {
+				value = item.field(passwordFieldNotes)
+			}
+			if value == "" {
+				fmt.Fprintf(w, "Skipped %s: the item has no password or notes\n", dir+name)
+				continue
+			}
+			secrets = append(secrets, namedSecret{Name: dir + name, Value: []byte(value)})
+			continue
+		}
+
+		for _, field := range item.Fields {
+			if field.Value == "" {
+				continue
+			}
+			fieldName := uniqueName(dir+name+"/", sanitizeSecretName(field.Name, "field"))
+			secrets = append(secrets, namedSecret{Name: dir + name + "/" + fieldName, Value: []byte(field.Value)})
+		}
+	}
+	return secrets
+}
+
+// sanitizeSecretName converts a name to a valid secret or directory name by replacing every sequence of
+// invalid characters with a dash and shortening it. When nothing remains, the fallback is returned.
+func sanitizeSecretName(name string, fallback string) string {
+	name = strings.Trim(invalidSecretNameCharacters.ReplaceAllString(name, "-"), "-")
+	if len(name) > maxSecretNameLength {
+		name = strings.TrimRight(name[:maxSecretNameLength], "-")
+	}
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// parse1PUX parses the items of a 1PUX export, which is a zip archive with the items in export.data.
+// Archived items are skipped.
+func parse1PUX(data []byte) ([]passwordItem, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidPasswordManagerExport("1Password", err)
+	}
+
+	var exportData []byte
+	for _, file := range archive.File {
+		if file.Name != "export.data" {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return nil, ErrInvalidPasswordManagerExport("1Password", err)
+		}
+		exportData, err = ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, ErrInvalidPasswordManagerExport("1Password", err)
+		}
+	}
+	if exportData == nil {
+		return nil, ErrInvalidPasswordManagerExport("1Password", "export.data is missing")
+	}
+
+	var export struct {
+		Accounts []struct {
+			Vaults []struct {
+				Attrs struct {
+					Name string `json:"name"`
+				} `json:"attrs"`
+				Items []struct {
+					State    string `json:"state"`
+					Overview struct {
+						Title string `json:"title"`
+						URL   string `json:"url"`
+					} `json:"overview"`
+					Details struct {
+						LoginFields []struct {
+							Designation string `json:"designation"`
+							Value       string `json:"value"`
+						} `json:"loginFields"`
+						NotesPlain string `json:"notesPlain"`
+						Password   string `json:"password"`
+						Sections   []struct {
+							Fields []struct {
+								Title string                     `json:"title"`
+								Value map[string]json.RawMessage `json:"value"`
+							} `json:"fields"`
+						} `json:"sections"`
+					} `json:"details"`
+				} `json:"items"`
+			} `json:"vaults"`
+		} `json:"accounts"`
+	}
+	err = json.Unmarshal(exportData, &export)
+	if err != nil {
+		return nil, ErrInvalidPasswordManagerExport("1Password", err)
+	}
+
+	var items []passwordItem
+	for _, account := range export.Accounts {
+		for _, vault := range account.Vaults {
+			for _, exported := range vault.Items {
+				if exported.State == "archived" {
+					continue
+				}
+
+				item := passwordItem{
+					Folders: []string{vault.Attrs.Name},
+					Title:   exported.Overview.Title,
+				}
+				for _, field := range exported.Details.LoginFields {
+					if field.Designation == passwordFieldUsername || field.Designation == passwordFieldPassword {
+						item.Fields = append(item.Fields, passwordField{Name: field.Designation, Value: field.Value})
+					}
+				}
+				if exported.Details.Password != "" {
+					item.Fields = append(item.Fields, passwordField{Name: passwordFieldPassword, Value: exported.Details.Password})
+				}
+				item.Fields = append(item.Fields,
+					passwordField{Name: passwordFieldURL, Value: exported.Overview.URL},
+					passwordField{Name: passwordFieldNotes, Value: exported.Details.NotesPlain},
+				)
+				for _, section := range exported.Details.Sections {
+					for _, field := range section.Fields {
+						item.Fields = append(item.Fields, passwordField{Name: field.Title, Value: onePasswordFieldValue(field.Value)})
+					}
+				}
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+// onePasswordFieldValue returns the value of a 1PUX section field. The value is an object with
+// a single key for the type of the field, such as concealed, string or totp. String values are
+// returned as is and all other values are returned as JSON.
+func onePasswordFieldValue(value map[string]json.RawMessage) string {
+	for _, raw := range value {
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			return s
+		}
+		return string(raw)
+	}
+	return ""
+}
+
+// parseBitwarden parses the items of a Bitwarden export in the JSON or CSV format.
+func parseBitwarden(data []byte) ([]passwordItem, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return parseBitwardenJSON(data)
+	}
+	return parseBitwardenCSV(data)
+}
+
+// parseBitwardenJSON parses the items of an unencrypted Bitwarden JSON export.
+func parseBitwardenJSON(data []byte) ([]passwordItem, error) {
+	var export struct {
+		Encrypted bool `json:"encrypted"`
+		Folders   []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"folders"`
+		Items []struct {
+			FolderID *string `json:"folderId"`
+			Name     string  `json:"name"`
+			Notes    string  `json:"notes"`
+			Login    *struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+				TOTP     string `json:"totp"`
+				URIs     []struct {
+					URI string `json:"uri"`
+				} `json:"uris"`
+			} `json:"login"`
+			Fields []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"items"`
+	}
+	err := json.Unmarshal(data, &export)
+	if err != nil {
+		return nil, ErrInvalidPasswordManagerExport("Bitwarden", err)
+	}
+	if export.Encrypted {
+		return nil, ErrInvalidPasswordManagerExport("Bitwarden", "encrypted exports are not supported, export the vault in the unencrypted JSON format")
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, folder := range export.Folders {
+		folders[folder.ID] = folder.Name
+	}
+
+	items := make([]passwordItem, 0, len(export.Items))
+	for _, exported := range export.Items {
+		item := passwordItem{Title: exported.Name}
+		if exported.FolderID != nil {
+			item.Folders = bitwardenFolders(folders[*exported.FolderID])
+		}
+		if exported.Login != nil {
+			item.Fields = append(item.Fields,
+				passwordField{Name: passwordFieldUsername, Value: exported.Login.Username},
+				passwordField{Name: passwordFieldPassword, Value: exported.Login.Password},
+				passwordField{Name: passwordFieldTOTP, Value: exported.Login.TOTP},
+			)
+			if len(exported.Login.URIs) > 0 {
+				item.Fields = append(item.Fields, passwordField{Name: passwordFieldURL, Value: exported.Login.URIs[0].URI})
+			}
+		}
+		item.Fields = append(item.Fields, passwordField{Name: passwordFieldNotes, Value: exported.Notes})
+		for _, field := range exported.Fields {
+			item.Fields = append(item.Fields, passwordField{Name: field.Name, Value: field.Value})
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseBitwardenCSV parses the items of a Bitwarden CSV export. Custom fields are stored
+// in the fields column as lines of `name: value`.
+func parseBitwardenCSV(data []byte) ([]passwordItem, error) {
+	records, err := readCSVRecords(data, "Bitwarden", "name")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]passwordItem, 0, len(records))
+	for _, record := range records {
+		item := passwordItem{
+			Folders: bitwardenFolders(record["folder"]),
+			Title:   record["name"],
+			Fields: []passwordField{
+				{Name: passwordFieldUsername, Value: record["login_username"]},
+				{Name: passwordFieldPassword, Value: record["login_password"]},
+				{Name: passwordFieldTOTP, Value: record["login_totp"]},
+				{Name: passwordFieldURL, Value: record["login_uri"]},
+				{Name: passwordFieldNotes, Value: record["notes"]},
+			},
+		}
+		for _, line := range strings.Split(record["fields"], "\n") {
+			parts := strings.SplitN(line, ": ", 2)
+			if len(parts) == 2 {
+				item.Fields = append(item.Fields, passwordField{Name: parts[0], Value: parts[1]})
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// bitwardenFolders splits a Bitwarden folder name into nested folders. Bitwarden nests folders with slashes.
+func bitwardenFolders(folder string) []string {
+	if folder == "" {
+		return nil
+	}
+	return strings.Split(folder, "/")
+}
+
+// parseLastPassCSV parses the items of a LastPass CSV export. LastPass nests folders with backslashes
+// in the grouping column and stores secure notes with the url http://sn.
+func parseLastPassCSV(data []byte) ([]passwordItem, error) {
+	records, err := readCSVRecords(data, "LastPass", "name")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]passwordItem, 0, len(records))
+	for _, record := range records {
+		item := passwordItem{
+			Title: record["name"],
+			Fields: []passwordField{
+				{Name: passwordFieldUsername, Value: record["username"]},
+				{Name: passwordFieldPassword, Value: record["password"]},
+				{Name: passwordFieldTOTP, Value: record["totp"]},
+				{Name: passwordFieldNotes, Value: record["extra"]},
+			},
+		}
+		if record["url"] != "http://sn" {
+			item.Fields = append(item.Fields, passwordField{Name: passwordFieldURL, Value: record["url"]})
+		}
+		if record["grouping"] != "" {
+			item.Folders = strings.Split(record["grouping"], "\\")
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// readCSVRecords reads a CSV file with a header row and returns every row as a map from column name to value.
+// The required column must be present in the header.
+func readCSVRecords(data []byte, format string, required string) ([]map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, ErrInvalidPasswordManagerExport(format, err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrInvalidPasswordManagerExport(format, "the file is empty")
+	}
+
+	header := rows[0]
+	columns := make([]string, len(header))
+	found := false
+	for i, column := range header {
+		// Excel prepends a byte order mark to CSV files.
+		columns[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if columns[i] == required {
+			found = true
+		}
+	}
+	if !found {
+		return nil, ErrInvalidPasswordManagerExport(format, fmt.Sprintf("the %s column is missing", required))
+	}
+
+	records := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := make(map[string]string, len(columns))
+		for i, value := range row {
+			if i < len(columns) {
+				record[columns[i]] = value
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,195 @@
+package secrethub
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestParse1PUX(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create("export.data")
+	assert.OK(t, err)
+	_, err = w.Write([]byte(`{"accounts":[{"vaults":[{"attrs":{"name":"Private"},"items":[
+		{"state":"active","overview":{"title":"GitHub","url":"https://github.com"},"details":{
+			"loginFields":[{"designation":"username","value":"dev"},{"designation":"password","value":"hunter2"},{"designation":"","value":"other"}],
+			"notesPlain":"note",
+			"sections":[{"fields":[{"title":"API token","value":{"concealed":"token"}},{"title":"Expires","value":{"date":1600000000}}]}]}},
+		{"state":"archived","overview":{"title":"Old"},"details":{}}
+	]}]}]}`))
+	assert.OK(t, err)
+	assert.OK(t, archive.Close())
+
+	actual, err := parse1PUX(buf.Bytes())
+
+	assert.OK(t, err)
+	assert.Equal(t, actual, []passwordItem{
+		{
+			Folders: []string{"Private"},
+			Title:   "GitHub",
+			Fields: []passwordField{
+				{Name: "username", Value: "dev"},
+				{Name: "password", Value: "hunter2"},
+				{Name: "url", Value: "https://github.com"},
+				{Name: "notes", Value: "note"},
+				{Name: "API token", Value: "token"},
+				{Name: "Expires", Value: "1600000000"},
+			},
+		},
+	})
+}
+
+func TestParseBitwarden(t *testing.T) {
+	cases := map[string]struct {
+		in       string
+		expected []passwordItem
+		err      error
+	}{
+		"json": {
+			in: `{"encrypted":false,"folders":[{"id":"f1","name":"Work/Dev"}],"items":[
+				{"folderId":"f1","name":"DB","notes":null,"login":{"username":"admin","password":"secret","totp":null,"uris":[{"uri":"db.local"}]},"fields":[{"name":"port","value":"5432"}]},
+				{"folderId":null,"name":"Note","notes":"text"}
+			]}`,
+			expected: []passwordItem{
+				{
+					Folders: []string{"Work", "Dev"},
+					Title:   "DB",
+					Fields: []passwordField{
+						{Name: "username", Value: "admin"},
+						{Name: "password", Value: "secret"},
+						{Name: "totp", Value: ""},
+						{Name: "url", Value: "db.local"},
+						{Name: "notes", Value: ""},
+						{Name: "port", Value: "5432"},
+					},
+				},
+				{
+					Title:  "Note",
+					Fields: []passwordField{{Name: "notes", Value: "text"}},
+				},
+			},
+		},
+		"encrypted json": {
+			in:  `{"encrypted":true}`,
+			err: ErrInvalidPasswordManagerExport("Bitwarden", "encrypted exports are not supported, export the vault in the unencrypted JSON format"),
+		},
+		"csv": {
+			in: "folder,favorite,type,name,notes,fields,login_uri,login_username,login_password,login_totp\n" +
+				"Work,,login,DB,,\"port: 5432\nhost: db.local\",,admin,secret,\n",
+			expected: []passwordItem{
+				{
+					Folders: []string{"Work"},
+					Title:   "DB",
+					Fields: []passwordField{
+						{Name: "username", Value: "admin"},
+						{Name: "password", Value: "secret"},
+						{Name: "totp", Value: ""},
+						{Name: "url", Value: ""},
+						{Name: "notes", Value: ""},
+						{Name: "port", Value: "5432"},
+						{Name: "host", Value: "db.local"},
+					},
+				},
+			},
+		},
+		"csv without name column": {
+			in:  "folder,login_password\nWork,secret\n",
+			err: ErrInvalidPasswordManagerExport("Bitwarden", "the name column is missing"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseBitwarden([]byte(tc.in))
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestParseLastPassCSV(t *testing.T) {
+	in := "url,username,password,totp,extra,name,grouping,fav\n" +
+		"https://example.com,dev,secret,,,Example,Shared-Work\\Dev,0\n" +
+		"http://sn,,,,the note,Server note,,0\n"
+
+	actual, err := parseLastPassCSV([]byte(in))
+
+	assert.OK(t, err)
+	assert.Equal(t, actual, []passwordItem{
+		{
+			Folders: []string{"Shared-Work", "Dev"},
+			Title:   "Example",
+			Fields: []passwordField{
+				{Name: "username", Value: "dev"},
+				{Name: "password", Value: "secret"},
+				{Name: "totp", Value: ""},
+				{Name: "notes", Value: ""},
+				{Name: "url", Value: "https://example.com"},
+			},
+		},
+		{
+			Title: "Server note",
+			Fields: []passwordField{
+				{Name: "username", Value: ""},
+				{Name: "password", Value: ""},
+				{Name: "totp", Value: ""},
+				{Name: "notes", Value: "the note"},
+			},
+		},
+	})
+}
+
+func TestPasswordItemSecrets(t *testing.T) {
+	items := []passwordItem{
+		{Folders: []string{"My Vault"}, Title: "Bank (work)", Fields: []passwordField{{Name: "username", Value: "me"}, {Name: "password", Value: "p1"}}},
+		{Folders: []string{"My Vault"}, Title: "Bank [work]", Fields: []passwordField{{Name: "password", Value: "p2"}}},
+		{Title: "Note", Fields: []passwordField{{Name: "notes", Value: "text"}}},
+		{Title: "Empty", Fields: []passwordField{{Name: "username", Value: "me"}}},
+	}
+
+	cases := map[string]struct {
+		fields   bool
+		expected []namedSecret
+		out      string
+	}{
+		"items": {
+			expected: []namedSecret{
+				{Name: "My-Vault/Bank-work", Value: []byte("p1")},
+				{Name: "My-Vault/Bank-work-2", Value: []byte("p2")},
+				{Name: "Note", Value: []byte("text")},
+			},
+			out: "Skipped Empty: the item has no password or notes\n",
+		},
+		"fields": {
+			fields: true,
+			expected: []namedSecret{
+				{Name: "My-Vault/Bank-work/username", Value: []byte("me")},
+				{Name: "My-Vault/Bank-work/password", Value: []byte("p1")},
+				{Name: "My-Vault/Bank-work-2/password", Value: []byte("p2")},
+				{Name: "Note/notes", Value: []byte("text")},
+				{Name: "Empty/username", Value: []byte("me")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			actual := passwordItemSecrets(&out, items, tc.fields)
+
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, out.String(), tc.out)
+		})
+	}
+}
+
+func TestSanitizeSecretName(t *testing.T) {
+	assert.Equal(t, sanitizeSecretName("AWS (prod) / root", "item"), "AWS-prod-root")
+	assert.Equal(t, sanitizeSecretName("日本", "item"), "item")
+	assert.Equal(t, sanitizeSecretName("a very long item title that does not fit", "item"), "a-very-long-item-title-that-does")
+}
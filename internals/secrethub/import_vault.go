@@ -0,0 +1,148 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// ImportVaultCommand imports secrets from a KV secrets engine of HashiCorp Vault.
+type ImportVaultCommand struct {
+	io          ui.IO
+	mount       string
+	path        string
+	kvVersion   int
+	allVersions bool
+	importer    secretImporter
+	newClient   newClientFunc
+	newVaultKV  newVaultKVFunc
+}
+
+// NewImportVaultCommand creates a new ImportVaultCommand.
+func NewImportVaultCommand(io ui.IO, newClient newClientFunc) *ImportVaultCommand {
+	return &ImportVaultCommand{
+		io:         io,
+		newClient:  newClient,
+		newVaultKV: newVaultKV,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ImportVaultCommand) Register(r command.Registerer) {
+	clause := r.Command("vault", "Import the secrets of a KV version 1 or 2 secrets engine of HashiCorp Vault, using VAULT_ADDR, VAULT_TOKEN (or the token of `vault login`) and VAULT_NAMESPACE. "+
+		"Every Vault secret below the path becomes a directory with a secret for every key, preserving the nested structure of the path.")
+	clause.Arg("dir-path", "The directory to import the secrets into").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.importer.dirPath)
+	clause.Flag("mount", "The path where the KV secrets engine is mounted.").Default("secret/").StringVar(&cmd.mount)
+	clause.Flag("path", "Only import the secrets below this path of the mount, e.g. apps/.").StringVar(&cmd.path)
+	clause.Flag("kv-version", "The version of the KV secrets engine, 1 or 2. Detected automatically by default.").IntVar(&cmd.kvVersion)
+	clause.Flag("all-versions", "Import the version history of KV version 2 secrets, oldest first, instead of only the latest version. Deleted and destroyed versions are skipped.").BoolVar(&cmd.allVersions)
+	registerImportFlags(clause, &cmd.importer)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the secrets from Vault.
+func (cmd *ImportVaultCommand) Run() error {
+	kv, err := cmd.newVaultKV(cmd.mount, cmd.kvVersion)
+	if err != nil {
+		return err
+	}
+
+	base := strings.Trim(cmd.path, "/")
+	if base != "" {
+		base += "/"
+	}
+
+	paths, err := listVaultSecrets(kv, base)
+	if err != nil {
+		return err
+	}
+
+	var secrets []namedSecret
+	for _, path := range paths {
+		versions := []int{0}
+		if cmd.allVersions {
+			versions, err = kv.Versions(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		dir := strings.TrimPrefix(path, base)
+		var previous map[string]interface{}
+		for _, version := range versions {
+			data, err := kv.Read(path, version)
+			if err != nil {
+				return err
+			}
+
+			keys := make([]string, 0, len(data))
+			for key := range data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				// Only keys that changed since the previous version get a new version.
+				if prev, ok := previous[key]; ok && reflect.DeepEqual(prev, data[key]) {
+					continue
+				}
+
+				value, err := vaultValue(data[key])
+				if err != nil {
+					return err
+				}
+				secrets = append(secrets, namedSecret{Name: dir + "/" + key, Value: value})
+			}
+			previous = data
+		}
+	}
+
+	// Keep the versions of a secret together and in order, as the importer expects.
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importer.importSecrets(cmd.io, client, secrets)
+}
+
+// listVaultSecrets returns the paths of all secrets below the path, walking the folders recursively.
+func listVaultSecrets(kv vaultKV, path string) ([]string, error) {
+	keys, err := kv.List(path)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	var res []string
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+			children, err := listVaultSecrets(kv, path+key)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, children...)
+			continue
+		}
+		res = append(res, path+key)
+	}
+	return res, nil
+}
+
+// vaultValue returns the value of a key of a Vault secret. Strings are returned as is and
+// all other values, such as numbers and nested objects, are encoded as JSON.
+func vaultValue(value interface{}) ([]byte, error) {
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(value)
+}
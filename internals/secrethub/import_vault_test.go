@@ -0,0 +1,246 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+// fakeVaultKV is a fake KV version 2 secrets engine. The versions of a secret are stored oldest first.
+type fakeVaultKV struct {
+	secrets map[string][]map[string]interface{}
+}
+
+func (kv fakeVaultKV) List(path string) ([]string, error) {
+	seen := map[string]bool{}
+	var keys []string
+	for secretPath := range kv.secrets {
+		if !strings.HasPrefix(secretPath, path) {
+			continue
+		}
+		key := strings.TrimPrefix(secretPath, path)
+		if i := strings.Index(key, "/"); i >= 0 {
+			key = key[:i+1]
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (kv fakeVaultKV) Read(path string, version int) (map[string]interface{}, error) {
+	versions := kv.secrets[path]
+	if version == 0 {
+		return versions[len(versions)-1], nil
+	}
+	return versions[version-1], nil
+}
+
+func (kv fakeVaultKV) Versions(path string) ([]int, error) {
+	var res []int
+	for i := range kv.secrets[path] {
+		res = append(res, i+1)
+	}
+	return res, nil
+}
+
+func TestImportVaultCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	kv := fakeVaultKV{
+		secrets: map[string][]map[string]interface{}{
+			"apps/web": {
+				{"user": "admin", "password": "v1"},
+				{"user": "admin", "password": "v2", "port": 8080.0},
+			},
+			"apps/workers/queue": {
+				{"url": "amqp://queue"},
+			},
+			"other/db": {
+				{"password": "other"},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		allVersions bool
+		dryRun      bool
+		vaultErr    error
+		written     []string
+		out         string
+		err         error
+	}{
+		"latest versions": {
+			written: []string{
+				"namespace/repo/web/password:v2",
+				"namespace/repo/web/port:8080",
+				"namespace/repo/web/user:admin",
+				"namespace/repo/workers/queue/url:amqp://queue",
+			},
+			out: "Wrote namespace/repo/web/password\n" +
+				"Wrote namespace/repo/web/port\n" +
+				"Wrote namespace/repo/web/user\n" +
+				"Wrote namespace/repo/workers/queue/url\n" +
+				"Import complete! Secrets: 4 written, 0 skipped.\n",
+		},
+		"all versions": {
+			allVersions: true,
+			written: []string{
+				"namespace/repo/web/password:v1",
+				"namespace/repo/web/password:v2",
+				"namespace/repo/web/port:8080",
+				"namespace/repo/web/user:admin",
+				"namespace/repo/workers/queue/url:amqp://queue",
+			},
+			out: "Wrote namespace/repo/web/password\n" +
+				"Wrote namespace/repo/web/password\n" +
+				"Wrote namespace/repo/web/port\n" +
+				"Wrote namespace/repo/web/user\n" +
+				"Wrote namespace/repo/workers/queue/url\n" +
+				"Import complete! Secrets: 4 written, 0 skipped.\n",
+		},
+		"dry run": {
+			dryRun: true,
+			out: "ACTION     PATH\n" +
+				"skip       namespace/repo/web/password\n" +
+				"create     namespace/repo/web/port\n" +
+				"unchanged  namespace/repo/web/user\n" +
+				"create     namespace/repo/workers/queue/url\n" +
+				"Dry run: 2 to create, 0 to update, 1 to skip, 1 unchanged. Nothing was written.\n",
+		},
+		"vault error": {
+			vaultErr: testErr,
+			err:      testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written []string
+			io := fakeui.NewIO(t)
+			cmd := ImportVaultCommand{
+				io:          io,
+				mount:       "secret/",
+				path:        "/apps/",
+				allVersions: tc.allVersions,
+				importer: secretImporter{
+					dirPath:    api.DirPath("namespace/repo"),
+					onConflict: conflictSkip,
+					dryRun:     tc.dryRun,
+				},
+				newVaultKV: func(mount string, kvVersion int) (vaultKV, error) {
+					return kv, tc.vaultErr
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{
+						Client: fakeclient.Client{
+							DirService: &fakeclient.DirService{
+								ExistsFunc: func(path string) (bool, error) {
+									return true, nil
+								},
+							},
+						},
+						secrets: importTestSecretService{
+							SecretService: &fakeclient.SecretService{
+								WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+									written = append(written, path+":"+string(data))
+									return nil, nil
+								},
+								VersionService: &fakeclient.SecretVersionService{
+									GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+										switch path {
+										case "namespace/repo/web/password":
+											return &api.SecretVersion{Data: []byte("v1")}, nil
+										case "namespace/repo/web/user":
+											return &api.SecretVersion{Data: []byte("admin")}, nil
+										}
+										return nil, api.ErrSecretNotFound
+									},
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, written, tc.written)
+		})
+	}
+}
+
+func TestVaultKVClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("X-Vault-Token"), "token")
+
+		var resp interface{}
+		switch r.Method + " " + r.URL.RequestURI() {
+		case "GET /v1/sys/internal/ui/mounts/secret":
+			resp = map[string]interface{}{"data": map[string]interface{}{"options": map[string]string{"version": "2"}}}
+		case "LIST /v1/secret/metadata/apps/":
+			resp = map[string]interface{}{"data": map[string]interface{}{"keys": []string{"web", "workers/"}}}
+		case "GET /v1/secret/metadata/apps/web":
+			resp = map[string]interface{}{"data": map[string]interface{}{"versions": map[string]interface{}{
+				"1": map[string]interface{}{"deletion_time": "", "destroyed": false},
+				"2": map[string]interface{}{"deletion_time": "", "destroyed": true},
+				"3": map[string]interface{}{"deletion_time": "", "destroyed": false},
+			}}}
+		case "GET /v1/secret/data/apps/web?version=3":
+			resp = map[string]interface{}{"data": map[string]interface{}{"data": map[string]string{"user": "admin"}}}
+		case "GET /v1/secret/data/apps/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			resp = map[string]interface{}{"errors": []string{"permission denied"}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			resp = map[string]interface{}{"errors": []string{}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	kv := &vaultKVClient{
+		client: server.Client(),
+		addr:   server.URL,
+		token:  "token",
+		mount:  "secret",
+	}
+
+	version, err := kv.detectVersion()
+	assert.OK(t, err)
+	assert.Equal(t, version, 2)
+	kv.version = version
+
+	keys, err := kv.List("apps/")
+	assert.OK(t, err)
+	assert.Equal(t, keys, []string{"web", "workers/"})
+
+	keys, err = kv.List("missing/")
+	assert.OK(t, err)
+	assert.Equal(t, len(keys), 0)
+
+	versions, err := kv.Versions("apps/web")
+	assert.OK(t, err)
+	assert.Equal(t, versions, []int{1, 3})
+
+	data, err := kv.Read("apps/web", 3)
+	assert.OK(t, err)
+	assert.Equal(t, data, map[string]interface{}{"user": "admin"})
+
+	_, err = kv.Read("apps/forbidden", 0)
+	assert.Equal(t, err, ErrVaultRequestFailed("permission denied"))
+}
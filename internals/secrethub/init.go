@@ -71,7 +71,7 @@ func (cmd *InitCommand) Run() error {
 
 		if !confirmed {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 	}
 
@@ -143,7 +143,7 @@ func (cmd *InitCommand) Run() error {
 		}
 		if !ok {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 
 		deviceName := ""
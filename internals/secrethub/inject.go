@@ -157,7 +157,7 @@ func (cmd *InjectCommand) Run() error {
 
 			if !confirmed {
 				fmt.Fprintln(cmd.io.Output(), "Aborting.")
-				return nil
+				return ErrAborted
 			}
 		}
 
@@ -14,6 +14,7 @@ var ErrInspectResourceNotSupported = errMain.Code("inspect_resource_not_supporte
 // InspectCommand prints information about a repository or a secret.
 type InspectCommand struct {
 	path          api.Path
+	output        string
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
@@ -32,6 +33,7 @@ func NewInspectCommand(io ui.IO, newClient newClientFunc) *InspectCommand {
 func (cmd *InspectCommand) Register(r command.Registerer) {
 	clause := r.Command("inspect", "Print details of a resource.")
 	clause.Arg("repo or secret-path", "Path to the repository or the secret to inspect "+repoPathPlaceHolder+" or "+secretPathOptionalVersionPlaceHolder).Required().SetValue(&cmd.path)
+	registerOutputFlag(clause, &cmd.output, formatJSON, formatYAML)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -45,24 +47,29 @@ func (cmd *InspectCommand) Run() error {
 			cmd.newClient,
 		)
 		repoInspectCmd.path = repoPath
+		repoInspectCmd.output = cmd.output
 		return repoInspectCmd.Run()
 	}
 
 	secretPath, err := cmd.path.ToSecretPath()
 	if err == nil {
 		if secretPath.HasVersion() {
-			return NewInspectSecretVersionCommand(
+			inspectSecretVersionCmd := NewInspectSecretVersionCommand(
 				secretPath,
 				cmd.io,
 				cmd.newClient,
-			).Run()
+			)
+			inspectSecretVersionCmd.output = cmd.output
+			return inspectSecretVersionCmd.Run()
 		}
 
-		return NewInspectSecretCommand(
+		inspectSecretCmd := NewInspectSecretCommand(
 			secretPath,
 			cmd.io,
 			cmd.newClient,
-		).Run()
+		)
+		inspectSecretCmd.output = cmd.output
+		return inspectSecretCmd.Run()
 	}
 
 	return ErrInspectResourceNotSupported
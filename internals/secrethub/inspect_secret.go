@@ -1,9 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-
-	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 
 	"github.com/secrethub/secrethub-go/internals/api"
@@ -12,6 +9,7 @@ import (
 // InspectSecretCommand prints out a secret's details.
 type InspectSecretCommand struct {
 	path          api.SecretPath
+	output        string
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
@@ -44,14 +42,7 @@ func (cmd *InspectSecretCommand) Run() error {
 		return err
 	}
 
-	output, err := cli.PrettyJSON(newSecretOutput(secret.Secret, versions, cmd.timeFormatter))
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.io.Output(), output)
-
-	return nil
+	return writeOutput(cmd.io.Output(), cmd.output, newSecretOutput(secret.Secret, versions, cmd.timeFormatter))
 }
 
 // newSecretOutput returns the JSON output of a secret.
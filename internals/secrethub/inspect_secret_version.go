@@ -1,9 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-
-	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 
 	"github.com/secrethub/secrethub-go/internals/api"
@@ -12,6 +9,7 @@ import (
 // InspectSecretVersionCommand prints out the details of a secret version in JSON format.
 type InspectSecretVersionCommand struct {
 	path          api.SecretPath
+	output        string
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
@@ -39,14 +37,7 @@ func (cmd *InspectSecretVersionCommand) Run() error {
 		return err
 	}
 
-	output, err := cli.PrettyJSON(newSecretVersionOutput(version, cmd.timeFormatter))
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.io.Output(), output)
-
-	return nil
+	return writeOutput(cmd.io.Output(), cmd.output, newSecretVersionOutput(version, cmd.timeFormatter))
 }
 
 func newSecretVersionOutput(secret *api.SecretVersion, timeFormatter TimeFormatter) secretVersionOutput {
@@ -0,0 +1,177 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrNotAGitRepository = errMain.Code("not_a_git_repository").ErrorPref("cannot find the git repository of %s: %s")
+	ErrHookExists        = errMain.Code("hook_exists").ErrorPref("%s already exists and was not installed by secrethub: use --force to replace it")
+)
+
+// The git hooks that can be installed.
+const (
+	hookPreCommit = "pre-commit"
+	hookPrePush   = "pre-push"
+)
+
+// hookMarker identifies the hooks that are installed by install-hooks, so they can be replaced
+// by a newer version without --force.
+const hookMarker = "# Installed by secrethub install-hooks."
+
+// hookSkipEnv is the environment variable that skips the scan of a hook when it is set to 1.
+const hookSkipEnv = "SECRETHUB_SKIP_SCAN"
+
+// hookScripts are the scripts of the hooks, with a %s for the flags that are passed to secrethub scan.
+// The pre-push hook scans the commits that are pushed, which for a new branch are the commits
+// that are not on any remote yet.
+var hookScripts = map[string]string{
+	hookPreCommit: `#!/bin/sh
+` + hookMarker + `
+# Commit anyway with ` + hookSkipEnv + `=1 git commit or git commit --no-verify.
+if [ "$` + hookSkipEnv + `" = "1" ]; then
+	exit 0
+fi
+exec secrethub scan --staged%s .
+`,
+	hookPrePush: `#!/bin/sh
+` + hookMarker + `
+# Push anyway with ` + hookSkipEnv + `=1 git push or git push --no-verify.
+if [ "$` + hookSkipEnv + `" = "1" ]; then
+	exit 0
+fi
+zero=0000000000000000000000000000000000000000
+while read -r local_ref local_sha remote_ref remote_sha; do
+	if [ "$local_sha" = "$zero" ]; then
+		continue
+	fi
+	if [ "$remote_sha" = "$zero" ]; then
+		set -- --range "$local_sha"
+		for ref in $(git for-each-ref --format='%%(objectname)' refs/remotes); do
+			set -- "$@" --range "^$ref"
+		done
+	else
+		set -- --range "$remote_sha..$local_sha"
+	fi
+	secrethub scan "$@"%s . </dev/null || exit 1
+done
+`,
+}
+
+// gitHooksDirFunc returns the directory with the hooks of the git repository in a directory.
+type gitHooksDirFunc func(dir string) (string, error)
+
+// gitHooksDir asks git for the directory with the hooks, which respects core.hooksPath and worktrees.
+func gitHooksDir(dir string) (string, error) {
+	var stderr bytes.Buffer
+	git := exec.Command("git", "rev-parse", "--git-path", "hooks")
+	git.Dir = dir
+	git.Stderr = &stderr
+
+	out, err := git.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", ErrNotAGitRepository(dir, msg)
+	}
+
+	hooksDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(dir, hooksDir)
+	}
+	return hooksDir, nil
+}
+
+// InstallHooksCommand installs git hooks that block commits and pushes containing secrets.
+type InstallHooksCommand struct {
+	io          ui.IO
+	dir         string
+	hooks       []string
+	crossCheck  []string
+	force       bool
+	gitHooksDir gitHooksDirFunc
+}
+
+// NewInstallHooksCommand creates a new InstallHooksCommand.
+func NewInstallHooksCommand(io ui.IO) *InstallHooksCommand {
+	return &InstallHooksCommand{
+		io:          io,
+		gitHooksDir: gitHooksDir,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *InstallHooksCommand) Register(r command.Registerer) {
+	clause := r.Command("install-hooks", "Install git hooks that run secrethub scan and block commits or pushes when secrets are found in them. "+
+		"The pre-commit hook scans the staged changes and the pre-push hook scans the commits that are pushed. "+
+		"To commit or push anyway, set "+hookSkipEnv+"=1 or pass --no-verify to git. Hooks that were installed before are updated.")
+	clause.Arg("dir", "A directory in the git repository to install the hooks in. Defaults to the current directory.").Default(".").StringVar(&cmd.dir)
+	clause.Flag("hook", "The hook to install: pre-commit or pre-push. Can be repeated. Defaults to pre-commit.").Default(hookPreCommit).EnumsVar(&cmd.hooks, hookPreCommit, hookPrePush)
+	clause.Flag("cross-check", "A directory in SecretHub of which the values of the secrets are looked for by the hooks. Can be repeated.").PlaceHolder(dirPathPlaceHolder).StringsVar(&cmd.crossCheck)
+	clause.Flag("force", "Replace hooks that were not installed by secrethub.").Short('f').BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run installs the hooks.
+func (cmd *InstallHooksCommand) Run() error {
+	hooksDir, err := cmd.gitHooksDir(cmd.dir)
+	if err != nil {
+		return err
+	}
+
+	var flags string
+	for _, dirPath := range cmd.crossCheck {
+		flags += " --cross-check " + shellQuote(dirPath)
+	}
+
+	// Check all hooks before writing any, so that nothing is installed when one of them cannot be.
+	for _, hook := range cmd.hooks {
+		path := filepath.Join(hooksDir, hook)
+		existing, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !cmd.force && !bytes.Contains(existing, []byte(hookMarker)) {
+			return ErrHookExists(path)
+		}
+	}
+
+	err = os.MkdirAll(hooksDir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, hook := range cmd.hooks {
+		path := filepath.Join(hooksDir, hook)
+		err = ioutil.WriteFile(path, []byte(fmt.Sprintf(hookScripts[hook], flags)), 0755)
+		if err != nil {
+			return err
+		}
+		// WriteFile does not change the mode of an existing file.
+		err = os.Chmod(path, 0755)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Installed the %s hook in %s.\n", hook, path)
+	}
+	return nil
+}
+
+// shellQuote quotes a value for a POSIX shell.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,110 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestInstallHooksCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		existing   map[string]string
+		hooks      []string
+		crossCheck []string
+		force      bool
+		contains   map[string]string
+		conflict   string
+	}{
+		"pre-commit": {
+			hooks: []string{hookPreCommit},
+			contains: map[string]string{
+				hookPreCommit: "exec secrethub scan --staged .\n",
+			},
+		},
+		"both": {
+			hooks: []string{hookPreCommit, hookPrePush},
+			contains: map[string]string{
+				hookPreCommit: "exec secrethub scan --staged .\n",
+				hookPrePush:   "--format='%(objectname)' refs/remotes",
+			},
+		},
+		"cross-check": {
+			hooks:      []string{hookPreCommit, hookPrePush},
+			crossCheck: []string{"namespace/repo/prod", "namespace/repo/it's"},
+			contains: map[string]string{
+				hookPreCommit: `exec secrethub scan --staged --cross-check 'namespace/repo/prod' --cross-check 'namespace/repo/it'"'"'s' .`,
+				hookPrePush:   `secrethub scan "$@" --cross-check 'namespace/repo/prod' --cross-check 'namespace/repo/it'"'"'s' . </dev/null || exit 1`,
+			},
+		},
+		"update": {
+			existing: map[string]string{hookPreCommit: "#!/bin/sh\n" + hookMarker + "\nexec secrethub scan\n"},
+			hooks:    []string{hookPreCommit},
+			contains: map[string]string{
+				hookPreCommit: "exec secrethub scan --staged .\n",
+			},
+		},
+		"other hook": {
+			existing: map[string]string{hookPrePush: "#!/bin/sh\nmake test\n"},
+			hooks:    []string{hookPreCommit, hookPrePush},
+			conflict: hookPrePush,
+		},
+		"force": {
+			existing: map[string]string{hookPreCommit: "#!/bin/sh\nmake test\n"},
+			hooks:    []string{hookPreCommit},
+			force:    true,
+			contains: map[string]string{
+				hookPreCommit: "exec secrethub scan --staged .\n",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			hooksDir := filepath.Join(dir, ".git", "hooks")
+			for hook, content := range tc.existing {
+				assert.OK(t, os.MkdirAll(hooksDir, 0755))
+				assert.OK(t, ioutil.WriteFile(filepath.Join(hooksDir, hook), []byte(content), 0644))
+			}
+
+			cmd := InstallHooksCommand{
+				io:         fakeui.NewIO(t),
+				dir:        dir,
+				hooks:      tc.hooks,
+				crossCheck: tc.crossCheck,
+				force:      tc.force,
+				gitHooksDir: func(string) (string, error) {
+					return hooksDir, nil
+				},
+			}
+
+			err := cmd.Run()
+			if tc.conflict != "" {
+				assert.Equal(t, err, ErrHookExists(filepath.Join(hooksDir, tc.conflict)))
+				_, err = os.Stat(filepath.Join(hooksDir, hookPreCommit))
+				assert.Equal(t, os.IsNotExist(err), true)
+				return
+			}
+			assert.OK(t, err)
+
+			for hook, expected := range tc.contains {
+				path := filepath.Join(hooksDir, hook)
+				content, err := ioutil.ReadFile(path)
+				assert.OK(t, err)
+				assert.Equal(t, strings.Contains(string(content), hookMarker), true)
+				assert.Equal(t, strings.Contains(string(content), expected), true)
+
+				info, err := os.Stat(path)
+				assert.OK(t, err)
+				assert.Equal(t, info.Mode().Perm()&0100 != 0, true)
+			}
+		})
+	}
+}
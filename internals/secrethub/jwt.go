@@ -0,0 +1,208 @@
+package secrethub
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	// Register the hash functions the signing algorithms use.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// Errors
+var (
+	ErrInvalidJWTKey           = errMain.Code("invalid_jwt_key").ErrorPref("%s is not a supported signing key: %s")
+	ErrJWTAlgorithmMismatch    = errMain.Code("jwt_algorithm_mismatch").ErrorPref("algorithm %s cannot be used with a key of type %s")
+	ErrUnsupportedJWTAlgorithm = errMain.Code("unsupported_jwt_algorithm").ErrorPref("unsupported algorithm %s")
+)
+
+// JWTCommand handles minting JSON Web Tokens with keys that are stored in SecretHub.
+type JWTCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewJWTCommand creates a new JWTCommand.
+func NewJWTCommand(io ui.IO, newClient newClientFunc) *JWTCommand {
+	return &JWTCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *JWTCommand) Register(r command.Registerer) {
+	clause := r.Command("jwt", "Mint JSON Web Tokens (JWT) with signing keys that are stored in SecretHub, without exporting the keys.")
+	NewJWTSignCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// jwtAlgorithms are the supported signing algorithms, as specified in RFC 7518 and RFC 8037.
+var jwtAlgorithms = map[string]struct {
+	keyType string
+	hash    crypto.Hash
+}{
+	"HS256": {keyType: jwtKeyTypeHMAC, hash: crypto.SHA256},
+	"HS384": {keyType: jwtKeyTypeHMAC, hash: crypto.SHA384},
+	"HS512": {keyType: jwtKeyTypeHMAC, hash: crypto.SHA512},
+	"RS256": {keyType: jwtKeyTypeRSA, hash: crypto.SHA256},
+	"RS384": {keyType: jwtKeyTypeRSA, hash: crypto.SHA384},
+	"RS512": {keyType: jwtKeyTypeRSA, hash: crypto.SHA512},
+	"PS256": {keyType: jwtKeyTypeRSA, hash: crypto.SHA256},
+	"PS384": {keyType: jwtKeyTypeRSA, hash: crypto.SHA384},
+	"PS512": {keyType: jwtKeyTypeRSA, hash: crypto.SHA512},
+	"ES256": {keyType: jwtKeyTypeEC, hash: crypto.SHA256},
+	"ES384": {keyType: jwtKeyTypeEC, hash: crypto.SHA384},
+	"ES512": {keyType: jwtKeyTypeEC, hash: crypto.SHA512},
+	"EdDSA": {keyType: jwtKeyTypeEd25519},
+}
+
+// The types of keys tokens can be signed with.
+const (
+	jwtKeyTypeHMAC    = "hmac"
+	jwtKeyTypeRSA     = "rsa"
+	jwtKeyTypeEC      = "ec"
+	jwtKeyTypeEd25519 = "ed25519"
+)
+
+// jwtSigningKey is a key to sign tokens with.
+type jwtSigningKey struct {
+	keyType string
+	key     interface{}
+}
+
+// parseJWTSigningKey parses a PEM encoded RSA, EC or Ed25519 private key. Any other value is used as a shared HMAC secret.
+func parseJWTSigningKey(name string, data []byte) (*jwtSigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return &jwtSigningKey{keyType: jwtKeyTypeHMAC, key: data}, nil
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, ErrInvalidJWTKey(name, fmt.Sprintf("unsupported PEM block %s", block.Type))
+	}
+	if err != nil {
+		return nil, ErrInvalidJWTKey(name, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &jwtSigningKey{keyType: jwtKeyTypeRSA, key: k}, nil
+	case *ecdsa.PrivateKey:
+		return &jwtSigningKey{keyType: jwtKeyTypeEC, key: k}, nil
+	case ed25519.PrivateKey:
+		return &jwtSigningKey{keyType: jwtKeyTypeEd25519, key: k}, nil
+	default:
+		return nil, ErrInvalidJWTKey(name, fmt.Sprintf("unsupported key type %T", key))
+	}
+}
+
+// defaultAlgorithm returns the algorithm that is used when no algorithm is given.
+// For EC keys, the algorithm matches the curve of the key, as RFC 7518 requires.
+func (k *jwtSigningKey) defaultAlgorithm() string {
+	switch k.keyType {
+	case jwtKeyTypeRSA:
+		return "RS256"
+	case jwtKeyTypeEC:
+		switch k.key.(*ecdsa.PrivateKey).Curve {
+		case elliptic.P384():
+			return "ES384"
+		case elliptic.P521():
+			return "ES512"
+		}
+		return "ES256"
+	case jwtKeyTypeEd25519:
+		return "EdDSA"
+	default:
+		return "HS256"
+	}
+}
+
+// sign returns the signature of the signing input with the given algorithm.
+func (k *jwtSigningKey) sign(algorithm string, input []byte) ([]byte, error) {
+	alg, ok := jwtAlgorithms[algorithm]
+	if !ok {
+		return nil, ErrUnsupportedJWTAlgorithm(algorithm)
+	}
+	if alg.keyType != k.keyType {
+		return nil, ErrJWTAlgorithmMismatch(algorithm, k.keyType)
+	}
+
+	var digest []byte
+	if alg.hash != 0 {
+		h := alg.hash.New()
+		h.Write(input)
+		digest = h.Sum(nil)
+	}
+
+	switch key := k.key.(type) {
+	case []byte:
+		mac := hmac.New(alg.hash.New, key)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case *rsa.PrivateKey:
+		if algorithm[0] == 'P' {
+			return rsa.SignPSS(rand.Reader, key, alg.hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.SignPKCS1v15(rand.Reader, key, alg.hash, digest)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+		if err != nil {
+			return nil, err
+		}
+		// The signature is the concatenation of r and s, each padded to the size of the curve.
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		rBytes, sBytes := r.Bytes(), s.Bytes()
+		copy(signature[size-len(rBytes):size], rBytes)
+		copy(signature[2*size-len(sBytes):], sBytes)
+		return signature, nil
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, input), nil
+	default:
+		return nil, ErrJWTAlgorithmMismatch(algorithm, k.keyType)
+	}
+}
+
+// signJWT encodes the header and claims and signs them with the key.
+func signJWT(key *jwtSigningKey, algorithm string, header map[string]interface{}, claims map[string]interface{}) (string, error) {
+	header["alg"] = algorithm
+	header["typ"] = "JWT"
+
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	input := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+	signature, err := key.sign(algorithm, []byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,114 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidClaims = errMain.Code("invalid_claims").ErrorPref("%s does not contain a JSON object with claims: %s")
+)
+
+// JWTSignCommand signs a JSON Web Token with a key that is stored in a secret.
+type JWTSignCommand struct {
+	io         ui.IO
+	keyPath    api.SecretPath
+	claimsFile string
+	algorithm  string
+	keyID      string
+	ttl        time.Duration
+	readFile   func(filename string) ([]byte, error)
+	newClient  newClientFunc
+	now        func() time.Time
+}
+
+// NewJWTSignCommand creates a new JWTSignCommand.
+func NewJWTSignCommand(io ui.IO, newClient newClientFunc) *JWTSignCommand {
+	return &JWTSignCommand{
+		io:        io,
+		readFile:  ioutil.ReadFile,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *JWTSignCommand) Register(r command.Registerer) {
+	clause := r.Command("sign", "Sign a JSON Web Token with a key that is stored in a secret and print it. "+
+		"The key is only decrypted in memory. A PEM encoded RSA, EC or Ed25519 private key is used for asymmetric signing, "+
+		"any other value as the shared secret of HMAC signing. "+
+		"The iat and exp claims are set from the current time and the --ttl flag.")
+	clause.Flag("key", "The path to the secret with the signing key.").Required().PlaceHolder(secretPathOptionalVersionPlaceHolder).SetValue(&cmd.keyPath)
+	clause.Flag("claims", "A JSON file with the claims of the token, e.g. the subject and audience.").StringVar(&cmd.claimsFile)
+	clause.Flag("ttl", "How long the token is valid. Set it to 0 to leave out the exp claim.").Default("15m").DurationVar(&cmd.ttl)
+	clause.Flag("alg", "The signing algorithm, e.g. RS256 or HS512. Defaults to the algorithm that matches the type of the key.").StringVar(&cmd.algorithm)
+	clause.Flag("kid", "The ID of the key, set in the kid header so the receiver can find the key to verify the token with.").StringVar(&cmd.keyID)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run signs the token and prints it.
+func (cmd *JWTSignCommand) Run() error {
+	claims := map[string]interface{}{}
+	if cmd.claimsFile != "" {
+		data, err := cmd.readFile(cmd.claimsFile)
+		if err != nil {
+			return ErrReadFile(cmd.claimsFile, err)
+		}
+		// Numbers are decoded as json.Number, so large numeric claims are not rounded.
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&claims)
+		if err != nil {
+			return ErrInvalidClaims(cmd.claimsFile, err)
+		}
+	}
+
+	now := cmd.now()
+	claims["iat"] = now.Unix()
+	if cmd.ttl > 0 {
+		claims["exp"] = now.Add(cmd.ttl).Unix()
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Secrets().Versions().GetWithData(cmd.keyPath.Value())
+	if err != nil {
+		return err
+	}
+
+	key, err := parseJWTSigningKey(cmd.keyPath.String(), secret.Data)
+	if err != nil {
+		return err
+	}
+
+	algorithm := cmd.algorithm
+	if algorithm == "" {
+		algorithm = key.defaultAlgorithm()
+	}
+
+	header := map[string]interface{}{}
+	if cmd.keyID != "" {
+		header["kid"] = cmd.keyID
+	}
+
+	token, err := signJWT(key, algorithm, header, claims)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.io.Output(), token)
+	return nil
+}
@@ -0,0 +1,154 @@
+package secrethub
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestJWTSignCommand_Run(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.OK(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.OK(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	assert.OK(t, err)
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.OK(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.OK(t, err)
+
+	hmacKey := []byte("a shared secret of at least 32 bytes")
+
+	cases := map[string]struct {
+		key       []byte
+		algorithm string
+		expected  string
+		verify    func(input, signature []byte) bool
+		err       error
+	}{
+		"hmac": {
+			key:      hmacKey,
+			expected: "HS256",
+			verify: func(input, signature []byte) bool {
+				mac := hmac.New(sha256.New, hmacKey)
+				mac.Write(input)
+				return hmac.Equal(mac.Sum(nil), signature)
+			},
+		},
+		"rsa": {
+			key:      pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			expected: "RS256",
+			verify: func(input, signature []byte) bool {
+				digest := sha256.Sum256(input)
+				return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], signature) == nil
+			},
+		},
+		"rsa pss": {
+			key:       pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			algorithm: "PS256",
+			expected:  "PS256",
+			verify: func(input, signature []byte) bool {
+				digest := sha256.Sum256(input)
+				return rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA256, digest[:], signature, nil) == nil
+			},
+		},
+		"ec": {
+			key:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}),
+			expected: "ES256",
+			verify: func(input, signature []byte) bool {
+				digest := sha256.Sum256(input)
+				r := new(big.Int).SetBytes(signature[:32])
+				s := new(big.Int).SetBytes(signature[32:])
+				return len(signature) == 64 && ecdsa.Verify(&ecKey.PublicKey, digest[:], r, s)
+			},
+		},
+		"ed25519": {
+			key:      pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+			expected: "EdDSA",
+			verify: func(input, signature []byte) bool {
+				return ed25519.Verify(edPublicKey, input, signature)
+			},
+		},
+		"algorithm mismatch": {
+			key:       hmacKey,
+			algorithm: "RS256",
+			err:       ErrJWTAlgorithmMismatch("RS256", "hmac"),
+		},
+		"unsupported algorithm": {
+			key:       hmacKey,
+			algorithm: "none",
+			err:       ErrUnsupportedJWTAlgorithm("none"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := JWTSignCommand{
+				io:         io,
+				keyPath:    "namespace/repo/signing-key",
+				claimsFile: "claims.json",
+				algorithm:  tc.algorithm,
+				keyID:      "key-1",
+				ttl:        15 * time.Minute,
+				readFile: func(filename string) ([]byte, error) {
+					return []byte(`{"sub": "deploy-bot", "id": 12345678901234567890}`), nil
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									return &api.SecretVersion{Data: tc.key}, nil
+								},
+							},
+						},
+					}, nil
+				},
+				now: func() time.Time { return time.Unix(1600000000, 0) },
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+
+			parts := strings.Split(strings.TrimSuffix(io.Out.String(), "\n"), ".")
+			assert.Equal(t, len(parts), 3)
+
+			header, err := base64.RawURLEncoding.DecodeString(parts[0])
+			assert.OK(t, err)
+			assert.Equal(t, string(header), `{"alg":"`+tc.expected+`","kid":"key-1","typ":"JWT"}`)
+
+			claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+			assert.OK(t, err)
+			assert.Equal(t, string(claims), `{"exp":1600000900,"iat":1600000000,"id":12345678901234567890,"sub":"deploy-bot"}`)
+
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			assert.OK(t, err)
+			assert.Equal(t, tc.verify([]byte(parts[0]+"."+parts[1]), signature), true)
+		})
+	}
+}
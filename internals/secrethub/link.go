@@ -0,0 +1,204 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrLinkVersion     = errMain.Code("link_version").Error("links cannot point to or be created at a specific version of a secret")
+	ErrLinkAliasExists = errMain.Code("link_alias_exists").ErrorPref("cannot create a link at %s: a secret already exists at that path")
+	ErrLinkToItself    = errMain.Code("link_to_itself").Error("a link cannot point to itself")
+	ErrLinkLoop        = errMain.Code("link_loop").ErrorPref("cannot resolve the link %s: the links form a loop or are nested too deeply")
+	ErrInvalidLinks    = errMain.Code("invalid_links").ErrorPref("the links of the repository in %s are invalid: %s")
+	ErrLinkNotFound    = errMain.Code("link_not_found").ErrorPref("there is no link at %s")
+)
+
+// repoLinksSecretName is the name of the secret in the root directory of a repository that
+// contains the links of the repository. Links are not stored as secrets themselves, so commands
+// that do not resolve links fail with a not found error instead of using the link as a value.
+const repoLinksSecretName = ".links"
+
+// maxLinkDepth is the number of links that are followed before a link is considered to be part of a loop.
+const maxLinkDepth = 8
+
+// repoLinks maps the paths of links, relative to the root of the repository, to the secrets they point to.
+type repoLinks map[string]string
+
+// repoLinksPath returns the path of the secret that contains the links of the repository.
+func repoLinksPath(repoPath api.RepoPath) api.SecretPath {
+	return repoPath.GetDirPath().JoinSecret(repoLinksSecretName)
+}
+
+// isRepoLinksPath returns whether the path is the path of the secret that contains the links of its repository.
+func isRepoLinksPath(secretPath api.SecretPath) bool {
+	return secretPath == repoLinksPath(secretPath.GetRepoPath())
+}
+
+// removeRepoLinksSecret removes the secret that contains the links of the repository from the secrets in
+// the root directory of the repository, as it is not a secret of its own. It returns whether the secret was there.
+func removeRepoLinksSecret(rootDir *api.Dir) bool {
+	for i, secret := range rootDir.Secrets {
+		if secret.Name == repoLinksSecretName {
+			rootDir.Secrets = append(rootDir.Secrets[:i], rootDir.Secrets[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// relativeToRepo returns the path relative to the root of its repository.
+func relativeToRepo(p string) string {
+	return strings.Join(strings.SplitN(p, "/", 3)[2:], "/")
+}
+
+// getRepoLinks returns the links of the repository, which are empty when it has no links.
+// Links the account is not allowed to read are treated as no links, so an account with access
+// to only a part of the repository can still read and list the secrets it has access to.
+func getRepoLinks(client secrethub.ClientInterface, repoPath api.RepoPath) (repoLinks, error) {
+	linksPath := repoLinksPath(repoPath)
+	secret, err := client.Secrets().Versions().GetWithData(linksPath.Value())
+	if api.IsErrNotFound(err) || isErrForbidden(err) {
+		return repoLinks{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	links := repoLinks{}
+	err = json.Unmarshal(secret.Data, &links)
+	if err != nil {
+		return nil, ErrInvalidLinks(repoPath, err)
+	}
+	return links, nil
+}
+
+// setRepoLinks stores the links of the repository. The secret with the links is removed when there are no links left.
+func setRepoLinks(client secrethub.ClientInterface, repoPath api.RepoPath, links repoLinks) error {
+	linksPath := repoLinksPath(repoPath)
+	if len(links) == 0 {
+		return client.Secrets().Delete(linksPath.Value())
+	}
+
+	data, err := json.Marshal(links)
+	if err != nil {
+		return err
+	}
+	_, err = client.Secrets().Write(linksPath.Value(), data)
+	return err
+}
+
+// isErrForbidden returns whether the error is caused by the account not being allowed to access a resource.
+func isErrForbidden(err error) bool {
+	var statusErr errio.PublicStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden
+}
+
+// repoLinkCache fetches the links of a repository when they are first needed and keeps them,
+// so resolving a chain of links fetches the links of every repository only once.
+type repoLinkCache struct {
+	client secrethub.ClientInterface
+	links  map[api.RepoPath]repoLinks
+}
+
+// newRepoLinkCache returns a cache that fetches the links with the client.
+func newRepoLinkCache(client secrethub.ClientInterface) *repoLinkCache {
+	return &repoLinkCache{
+		client: client,
+		links:  make(map[api.RepoPath]repoLinks),
+	}
+}
+
+// get returns the links of the repository.
+func (c *repoLinkCache) get(repoPath api.RepoPath) (repoLinks, error) {
+	links, ok := c.links[repoPath]
+	if ok {
+		return links, nil
+	}
+	links, err := getRepoLinks(c.client, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	c.links[repoPath] = links
+	return links, nil
+}
+
+// inDir returns the links that are directly in the directory at the given path relative to the repository,
+// mapped by their name.
+func (l repoLinks) inDir(relDir string) map[string]string {
+	links := make(map[string]string)
+	for alias, target := range l {
+		dir, name := path.Split(alias)
+		if strings.TrimSuffix(dir, "/") == relDir {
+			links[name] = target
+		}
+	}
+	return links
+}
+
+// resolveLink returns the path of the secret the link at the given path points to, following links to links.
+// It returns false when there is no link at the path.
+func resolveLink(client secrethub.ClientInterface, secretPath api.SecretPath) (api.SecretPath, bool, error) {
+	chain, err := linkChain(client, secretPath)
+	if err != nil {
+		return "", false, err
+	}
+	return chain[len(chain)-1], len(chain) > 1, nil
+}
+
+// linkChain returns the given path followed by the paths of the links it resolves through,
+// ending with the path that is not a link.
+func linkChain(client secrethub.ClientInterface, secretPath api.SecretPath) ([]api.SecretPath, error) {
+	cache := newRepoLinkCache(client)
+	chain := []api.SecretPath{secretPath}
+	for len(chain) <= maxLinkDepth {
+		current := chain[len(chain)-1]
+		links, err := cache.get(current.GetRepoPath())
+		if err != nil {
+			return nil, err
+		}
+		target, ok := links[relativeToRepo(current.Value())]
+		if !ok {
+			return chain, nil
+		}
+		next, err := api.NewSecretPath(target)
+		if err != nil {
+			return nil, ErrInvalidLinks(current.GetRepoPath(), err)
+		}
+		chain = append(chain, next)
+	}
+	return nil, ErrLinkLoop(secretPath)
+}
+
+// LinkCommand handles operations on links to secrets, so the same secret can be read at multiple paths.
+type LinkCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewLinkCommand creates a new LinkCommand.
+func NewLinkCommand(io ui.IO, newClient newClientFunc) *LinkCommand {
+	return &LinkCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *LinkCommand) Register(r command.Registerer) {
+	clause := r.Command("link", "Manage links to secrets, so the same secret can be read at another path without duplicating its value. "+
+		"`read`, `run`, `inject` and templates resolve links and `ls` shows them with the secret they point to. "+
+		"The links of a repository are stored in the secret "+repoLinksSecretName+" in its root.")
+	NewLinkCreateCommand(cmd.io, cmd.newClient).Register(clause)
+	NewLinkRmCommand(cmd.io, cmd.newClient).Register(clause)
+}
@@ -0,0 +1,92 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// LinkCreateCommand creates a link to a secret.
+type LinkCreateCommand struct {
+	io        ui.IO
+	target    api.SecretPath
+	alias     api.SecretPath
+	newClient newClientFunc
+}
+
+// NewLinkCreateCommand creates a new LinkCreateCommand.
+func NewLinkCreateCommand(io ui.IO, newClient newClientFunc) *LinkCreateCommand {
+	return &LinkCreateCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *LinkCreateCommand) Register(r command.Registerer) {
+	clause := r.Command("create", "Create a link to a secret. A secret at the path of the link takes precedence over the link.")
+	clause.Arg("target", "The path of the secret the link points to.").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.target)
+	clause.Arg("alias", "The path of the link.").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.alias)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run creates the link.
+func (cmd *LinkCreateCommand) Run() error {
+	if cmd.target.HasVersion() || cmd.alias.HasVersion() {
+		return ErrLinkVersion
+	}
+	if cmd.target == cmd.alias {
+		return ErrLinkToItself
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.Secrets().Exists(cmd.alias.Value())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrLinkAliasExists(cmd.alias)
+	}
+
+	// The target can be a link itself, as long as it eventually points to a secret
+	// and does not resolve through the alias, as that would form a loop.
+	chain, err := linkChain(client, cmd.target)
+	if err != nil {
+		return err
+	}
+	for _, p := range chain {
+		if p == cmd.alias {
+			return ErrLinkLoop(cmd.alias)
+		}
+	}
+	exists, err = client.Secrets().Exists(chain[len(chain)-1].Value())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return api.ErrSecretNotFound
+	}
+
+	repoPath := cmd.alias.GetRepoPath()
+	links, err := getRepoLinks(client, repoPath)
+	if err != nil {
+		return err
+	}
+	links[relativeToRepo(cmd.alias.Value())] = cmd.target.Value()
+
+	err = setRepoLinks(client, repoPath, links)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Linked %s to %s.\n", cmd.alias, cmd.target)
+	return nil
+}
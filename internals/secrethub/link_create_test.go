@@ -0,0 +1,97 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+func TestLinkCreateCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		target   api.SecretPath
+		alias    api.SecretPath
+		secrets  map[string]string
+		expected map[string]string
+		out      string
+		err      error
+	}{
+		"link": {
+			target: "namespace/infra/db_password",
+			alias:  "namespace/app/prod/db_password",
+			secrets: map[string]string{
+				"namespace/infra/db_password": "secret",
+				"namespace/app/.links":        `{"dev/db_password":"namespace/infra/dev_db_password"}`,
+			},
+			expected: map[string]string{
+				"namespace/app/.links": `{"dev/db_password":"namespace/infra/dev_db_password","prod/db_password":"namespace/infra/db_password"}`,
+			},
+			out: "Linked namespace/app/prod/db_password to namespace/infra/db_password.\n",
+		},
+		"link to link": {
+			target: "namespace/app/prod/db_password",
+			alias:  "namespace/app/db_password",
+			secrets: map[string]string{
+				"namespace/infra/db_password": "secret",
+				"namespace/app/.links":        `{"prod/db_password":"namespace/infra/db_password"}`,
+			},
+			expected: map[string]string{
+				"namespace/app/.links": `{"db_password":"namespace/app/prod/db_password","prod/db_password":"namespace/infra/db_password"}`,
+			},
+			out: "Linked namespace/app/db_password to namespace/app/prod/db_password.\n",
+		},
+		"alias exists": {
+			target: "namespace/infra/db_password",
+			alias:  "namespace/app/db_password",
+			secrets: map[string]string{
+				"namespace/infra/db_password": "secret",
+				"namespace/app/db_password":   "copy",
+			},
+			err: ErrLinkAliasExists("namespace/app/db_password"),
+		},
+		"target not found": {
+			target: "namespace/infra/db_password",
+			alias:  "namespace/app/db_password",
+			err:    api.ErrSecretNotFound,
+		},
+		"loop": {
+			target: "namespace/app/a",
+			alias:  "namespace/app/b",
+			secrets: map[string]string{
+				"namespace/app/.links": `{"a":"namespace/app/b","b":"namespace/infra/db_password"}`,
+			},
+			err: ErrLinkLoop("namespace/app/b"),
+		},
+		"itself": {
+			target: "namespace/app/a",
+			alias:  "namespace/app/a",
+			err:    ErrLinkToItself,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			written := map[string]string{}
+			io := fakeui.NewIO(t)
+			cmd := LinkCreateCommand{
+				io:     io,
+				target: tc.target,
+				alias:  tc.alias,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{secrets: linkTestSecrets(tc.secrets, written)}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, written, tc.expected)
+				assert.Equal(t, io.Out.String(), tc.out)
+			}
+		})
+	}
+}
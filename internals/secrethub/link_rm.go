@@ -0,0 +1,65 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// LinkRmCommand removes a link to a secret.
+type LinkRmCommand struct {
+	io        ui.IO
+	alias     api.SecretPath
+	newClient newClientFunc
+}
+
+// NewLinkRmCommand creates a new LinkRmCommand.
+func NewLinkRmCommand(io ui.IO, newClient newClientFunc) *LinkRmCommand {
+	return &LinkRmCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *LinkRmCommand) Register(r command.Registerer) {
+	clause := r.Command("rm", "Remove a link. The secret the link points to is left untouched.")
+	clause.Alias("remove")
+	clause.Arg("alias", "The path of the link.").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.alias)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run removes the link.
+func (cmd *LinkRmCommand) Run() error {
+	if cmd.alias.HasVersion() {
+		return ErrLinkVersion
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	repoPath := cmd.alias.GetRepoPath()
+	links, err := getRepoLinks(client, repoPath)
+	if err != nil {
+		return err
+	}
+	target, ok := links[relativeToRepo(cmd.alias.Value())]
+	if !ok {
+		return ErrLinkNotFound(cmd.alias)
+	}
+	delete(links, relativeToRepo(cmd.alias.Value()))
+
+	err = setRepoLinks(client, repoPath, links)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Removed the link from %s to %s.\n", cmd.alias, target)
+	return nil
+}
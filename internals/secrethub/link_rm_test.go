@@ -0,0 +1,82 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+func TestLinkRmCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		alias    api.SecretPath
+		secrets  map[string]string
+		expected map[string]string
+		deleted  []string
+		out      string
+		err      error
+	}{
+		"remove": {
+			alias: "namespace/app/prod/db_password",
+			secrets: map[string]string{
+				"namespace/app/.links": `{"dev/db_password":"namespace/infra/dev_db_password","prod/db_password":"namespace/infra/db_password"}`,
+			},
+			expected: map[string]string{
+				"namespace/app/.links": `{"dev/db_password":"namespace/infra/dev_db_password"}`,
+			},
+			out: "Removed the link from namespace/app/prod/db_password to namespace/infra/db_password.\n",
+		},
+		"remove last": {
+			alias: "namespace/app/db_password",
+			secrets: map[string]string{
+				"namespace/app/.links": `{"db_password":"namespace/infra/db_password"}`,
+			},
+			expected: map[string]string{},
+			deleted:  []string{"namespace/app/.links"},
+			out:      "Removed the link from namespace/app/db_password to namespace/infra/db_password.\n",
+		},
+		"not a link": {
+			alias: "namespace/app/db_password",
+			secrets: map[string]string{
+				"namespace/app/db_password": "secret",
+			},
+			err: ErrLinkNotFound("namespace/app/db_password"),
+		},
+		"version": {
+			alias: "namespace/app/db_password:1",
+			err:   ErrLinkVersion,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			written := map[string]string{}
+			var deleted []string
+			secrets := linkTestSecrets(tc.secrets, written)
+			secrets.DeleteFunc = func(path string) error {
+				deleted = append(deleted, path)
+				return nil
+			}
+			io := fakeui.NewIO(t)
+			cmd := LinkRmCommand{
+				io:    io,
+				alias: tc.alias,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{secrets: secrets}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, written, tc.expected)
+				assert.Equal(t, deleted, tc.deleted)
+				assert.Equal(t, io.Out.String(), tc.out)
+			}
+		})
+	}
+}
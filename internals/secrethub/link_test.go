@@ -0,0 +1,218 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// linkTestSecrets returns a fake secret service with the given secret values, of which the paths exist.
+func linkTestSecrets(values map[string]string, written map[string]string) importTestSecretService {
+	existing := make(map[string]bool)
+	for p := range values {
+		existing[p] = true
+	}
+	return importTestSecretService{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					value, ok := values[path]
+					if !ok {
+						return nil, api.ErrSecretNotFound
+					}
+					return &api.SecretVersion{Data: []byte(value)}, nil
+				},
+			},
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				written[path] = string(data)
+				return &api.SecretVersion{Version: 1}, nil
+			},
+		},
+		existing: existing,
+	}
+}
+
+func TestReadSecretOrLink(t *testing.T) {
+	secrets := map[string]string{
+		"namespace/infra/db_password":   "secret",
+		"namespace/infra/db_password:2": "old secret",
+		"namespace/app/local":           "local",
+		"namespace/app/.links":          `{"db_password":"namespace/app/prod/db_password","prod/db_password":"namespace/infra/db_password","local":"namespace/infra/db_password"}`,
+	}
+	client := importTestClient{secrets: linkTestSecrets(secrets, nil)}
+
+	cases := map[string]struct {
+		path     api.SecretPath
+		expected string
+		err      error
+	}{
+		"secret": {
+			path:     "namespace/infra/db_password",
+			expected: "secret",
+		},
+		"link": {
+			path:     "namespace/app/prod/db_password",
+			expected: "secret",
+		},
+		"link to link": {
+			path:     "namespace/app/db_password",
+			expected: "secret",
+		},
+		"version": {
+			path:     "namespace/app/db_password:2",
+			expected: "old secret",
+		},
+		"secret takes precedence": {
+			path:     "namespace/app/local",
+			expected: "local",
+		},
+		"not found": {
+			path: "namespace/app/missing",
+			err:  api.ErrSecretNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			secret, err := readSecretOrLink(client, tc.path)
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, string(secret.Data), tc.expected)
+			}
+		})
+	}
+}
+
+func TestSecretReader_ReadSecret_Link(t *testing.T) {
+	secrets := map[string]string{
+		"namespace/infra/db_password": "secret",
+		"namespace/app/.links":        `{"db_password":"namespace/infra/db_password"}`,
+	}
+	reader := newSecretReader(func() (secrethub.ClientInterface, error) {
+		return importTestClient{secrets: linkTestSecrets(secrets, nil)}, nil
+	})
+
+	value, err := reader.ReadSecret("namespace/app/db_password")
+
+	assert.OK(t, err)
+	assert.Equal(t, value, "secret")
+}
+
+func TestGetRepoLinks(t *testing.T) {
+	networkErr := errors.New("connection refused")
+
+	cases := map[string]struct {
+		data     string
+		err      error
+		expected repoLinks
+		expErr   error
+	}{
+		"links": {
+			data:     `{"db_password":"namespace/infra/db_password"}`,
+			expected: repoLinks{"db_password": "namespace/infra/db_password"},
+		},
+		"no links": {
+			err:      api.ErrSecretNotFound,
+			expected: repoLinks{},
+		},
+		"forbidden": {
+			err:      api.ErrForbidden,
+			expected: repoLinks{},
+		},
+		"other error": {
+			err:    networkErr,
+			expErr: networkErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := fakeclient.Client{
+				SecretService: &fakeclient.SecretService{
+					VersionService: &fakeclient.SecretVersionService{
+						GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+							assert.Equal(t, path, "namespace/app/.links")
+							if tc.err != nil {
+								return nil, tc.err
+							}
+							return &api.SecretVersion{Data: []byte(tc.data)}, nil
+						},
+					},
+				},
+			}
+
+			actual, err := getRepoLinks(client, "namespace/app")
+
+			assert.Equal(t, err, tc.expErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestDirLinks(t *testing.T) {
+	cases := map[string]struct {
+		path     api.DirPath
+		secrets  []string
+		expected map[string]string
+		fetched  bool
+		listed   []string
+	}{
+		"root without links": {
+			path:    "namespace/app",
+			secrets: []string{"secret"},
+			listed:  []string{"secret"},
+		},
+		"root with links": {
+			path:     "namespace/app",
+			secrets:  []string{".links", "secret"},
+			expected: map[string]string{"db_password": "namespace/infra/db_password"},
+			fetched:  true,
+			listed:   []string{"secret"},
+		},
+		"subdirectory": {
+			path:     "namespace/app/prod",
+			secrets:  []string{".links"},
+			expected: map[string]string{"db_password": "namespace/infra/prod_db_password"},
+			fetched:  true,
+			listed:   []string{".links"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fetched := false
+			client := fakeclient.Client{
+				SecretService: &fakeclient.SecretService{
+					VersionService: &fakeclient.SecretVersionService{
+						GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+							fetched = true
+							return &api.SecretVersion{
+								Data: []byte(`{"db_password":"namespace/infra/db_password","prod/db_password":"namespace/infra/prod_db_password"}`),
+							}, nil
+						},
+					},
+				},
+			}
+			dir := &api.Dir{}
+			for _, name := range tc.secrets {
+				dir.Secrets = append(dir.Secrets, &api.Secret{Name: name})
+			}
+
+			actual, err := dirLinks(client, tc.path, dir)
+
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, fetched, tc.fetched)
+			var listed []string
+			for _, secret := range dir.Secrets {
+				listed = append(listed, secret.Name)
+			}
+			assert.Equal(t, listed, tc.listed)
+		})
+	}
+}
@@ -6,28 +6,41 @@ import (
 	"io"
 	"sort"
 	"text/tabwriter"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrFastListNotDir = errMain.Code("fast_list_not_dir").ErrorPref("cannot list %s with --fast: only directories can be listed with --fast, as listing a secret fetches its versions")
 )
 
 // LsCommand lists a repo, secret or namespace.
 type LsCommand struct {
-	path          api.Path
-	quiet         bool
-	useTimestamps bool
-	io            ui.IO
-	newClient     newClientFunc
+	path               api.Path
+	quiet              bool
+	useTimestamps      bool
+	output             string
+	limit              int
+	after              string
+	fast               bool
+	io                 ui.IO
+	newClient          newClientFunc
+	newPaginatedWriter func(io.Writer) (io.WriteCloser, error)
 }
 
 // NewLsCommand creates a new LsCommand.
 func NewLsCommand(io ui.IO, newClient newClientFunc) *LsCommand {
 	return &LsCommand{
-		io:        io,
-		newClient: newClient,
+		io:                 io,
+		newClient:          newClient,
+		newPaginatedWriter: newPaginatedWriter,
 	}
 }
 
@@ -36,20 +49,36 @@ func (cmd *LsCommand) Register(r command.Registerer) {
 	clause := r.Command("ls", "List contents of a path.")
 	clause.Alias("list")
 	clause.Arg("path", "The path to list contents of").SetValue(&cmd.path)
-	clause.Flag("quiet", "Only print paths.").Short('q').BoolVar(&cmd.quiet)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFlag(clause, &cmd.output, formatTable, formatJSON, formatYAML)
+	clause.Flag("limit", "The maximum number of entries of a directory to list. Only available with --output json or yaml.").IntVar(&cmd.limit)
+	clause.Flag("after", "Only list the entries of a directory after the entry with this name, "+
+		"e.g. the name of the last entry of the previous page. Only available with --output json or yaml.").StringVar(&cmd.after)
+	clause.Flag("fast", "Only list namespaces and directories and fail for secrets, so the listing takes a single call for the metadata of the directory. "+
+		"Without --fast, a path that is not a directory is looked up again as a secret, to list its versions.").BoolVar(&cmd.fast)
 
 	command.BindAction(clause, cmd.Run)
 }
 
-// Run lists a repo, secret or namespace.
+// Run lists a repo, secret or namespace. Long listings are piped through the terminal pager.
 func (cmd *LsCommand) Run() error {
+	return withPager(&cmd.io, cmd.newPaginatedWriter, cmd.run)
+}
+
+// run lists a repo, secret or namespace.
+func (cmd *LsCommand) run() error {
 	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	cmd.quiet = cmd.quiet || quietOutput
+
+	if (cmd.limit != 0 || cmd.after != "") && !isMachineReadable(cmd.output) {
+		return ErrPagingWithoutOutput("--limit and --after")
+	}
 
 	if cmd.path == "" {
 		repoLSCommand := NewRepoLSCommand(cmd.io, cmd.newClient)
 		repoLSCommand.quiet = cmd.quiet
 		repoLSCommand.useTimestamps = cmd.useTimestamps
+		repoLSCommand.output = cmd.output
 		return repoLSCommand.Run()
 	}
 
@@ -58,6 +87,13 @@ func (cmd *LsCommand) Run() error {
 		return err
 	}
 
+	// Listings never fetch the encrypted values of secrets: directories are listed from their tree,
+	// which only holds the names of secrets, and the versions of secrets are listed without data.
+	// With --fast, namespaces are still listed below, as listing their repositories is a single call too.
+	if _, err := cmd.path.ToNamespace(); cmd.fast && err != nil {
+		return cmd.listDirOnly(client, timeFormatter)
+	}
+
 	// It must be a SecretPath as only SecretPaths has versions.
 	if cmd.path.HasVersion() {
 		secretPath, err := cmd.path.ToSecretPath()
@@ -71,7 +107,7 @@ func (cmd *LsCommand) Run() error {
 			return err
 		}
 
-		err = printVersions(cmd.io.Output(), cmd.quiet, timeFormatter, version)
+		err = cmd.printVersions(timeFormatter, version)
 		if err != nil {
 			return err
 		}
@@ -88,7 +124,11 @@ func (cmd *LsCommand) Run() error {
 		} else if err != nil && !api.IsErrNotFound(err) {
 			return err
 		} else if err == nil {
-			err = printDir(cmd.io.Output(), cmd.quiet, dirFS.RootDir, timeFormatter)
+			links, err := dirLinks(client, dirPath, dirFS.RootDir)
+			if err != nil {
+				return err
+			}
+			err = cmd.printDir(dirFS.RootDir, links, timeFormatter)
 			if err != nil {
 				return err
 			}
@@ -106,7 +146,7 @@ func (cmd *LsCommand) Run() error {
 			return err
 		}
 
-		err = printVersions(cmd.io.Output(), cmd.quiet, timeFormatter, versions...)
+		err = cmd.printVersions(timeFormatter, versions...)
 		if err != nil {
 			return err
 		}
@@ -120,6 +160,7 @@ func (cmd *LsCommand) Run() error {
 			workspace:     workspace,
 			useTimestamps: cmd.useTimestamps,
 			quiet:         cmd.quiet,
+			output:        cmd.output,
 			io:            cmd.io,
 			newClient:     cmd.newClient,
 		}
@@ -132,6 +173,149 @@ func (cmd *LsCommand) Run() error {
 	return errio.UnexpectedError(errors.New("invalid path argument"))
 }
 
+// listDirOnly lists the directory at the path with a single call for the tree of the directory,
+// without looking up the path as a secret when it is not a directory.
+func (cmd *LsCommand) listDirOnly(client secrethub.ClientInterface, timeFormatter TimeFormatter) error {
+	dirPath, err := cmd.path.ToDirPath()
+	if err != nil || cmd.path.HasVersion() {
+		return ErrFastListNotDir(cmd.path)
+	}
+
+	dirFS, err := client.Dirs().GetTree(dirPath.Value(), 1, false)
+	if api.IsErrNotFound(err) && !dirPath.IsRepoPath() {
+		return ErrFastListNotDir(cmd.path)
+	} else if err != nil {
+		return err
+	}
+	if dirPath.IsRepoPath() {
+		removeRepoLinksSecret(dirFS.RootDir)
+	}
+	return cmd.printDir(dirFS.RootDir, nil, timeFormatter)
+}
+
+// dirLinks returns the links in the listed directory, mapped by their name, and removes the secret with
+// the links from the listing of the root directory of a repository. As that listing shows whether the
+// repository has links, they are only fetched for the root directory when the repository has links.
+func dirLinks(client secrethub.ClientInterface, dirPath api.DirPath, dir *api.Dir) (map[string]string, error) {
+	if dirPath.IsRepoPath() && !removeRepoLinksSecret(dir) {
+		return nil, nil
+	}
+
+	links, err := getRepoLinks(client, dirPath.GetRepoPath())
+	if err != nil {
+		return nil, err
+	}
+	return links.inDir(relativeToRepo(dirPath.Value())), nil
+}
+
+// printVersions prints out secret versions in the output format.
+func (cmd *LsCommand) printVersions(timeFormatter TimeFormatter, versions ...*api.SecretVersion) error {
+	switch cmd.output {
+	case formatTable, "":
+		return printVersions(cmd.io.Output(), cmd.quiet, timeFormatter, versions...)
+	default:
+		out := make([]lsVersionOutput, len(versions))
+		for i, version := range versions {
+			out[i] = lsVersionOutput{
+				Version:   version.Version,
+				Status:    version.Status,
+				CreatedAt: version.CreatedAt.UTC().Format(time.RFC3339),
+			}
+		}
+		return writeOutput(cmd.io.Output(), cmd.output, out)
+	}
+}
+
+// printDir prints out directory contents and the links in the directory, mapped by name, in the output format.
+func (cmd *LsCommand) printDir(dir *api.Dir, links map[string]string, timeFormatter TimeFormatter) error {
+	links = linksNotInDir(dir, links)
+	switch cmd.output {
+	case formatTable, "":
+		return printDir(cmd.io.Output(), cmd.quiet, dir, links, timeFormatter)
+	default:
+		sort.Sort(api.SortDirByName(dir.SubDirs))
+		sort.Sort(api.SortSecretByName(dir.Secrets))
+
+		out := make([]lsEntryOutput, 0, len(dir.SubDirs)+len(dir.Secrets))
+		for _, sub := range dir.SubDirs {
+			out = append(out, lsEntryOutput{
+				Name:      sub.Name,
+				Type:      lsTypeDir,
+				Status:    sub.Status,
+				CreatedAt: sub.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		for _, secret := range dir.Secrets {
+			out = append(out, lsEntryOutput{
+				Name:      secret.Name,
+				Type:      lsTypeSecret,
+				Status:    secret.Status,
+				CreatedAt: secret.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		for _, name := range sortedLinkNames(links) {
+			out = append(out, lsEntryOutput{
+				Name:   name,
+				Type:   lsTypeLink,
+				Target: links[name],
+			})
+		}
+
+		out, err := pageLsEntries(out, cmd.after, cmd.limit)
+		if err != nil {
+			return err
+		}
+		return writeOutput(cmd.io.Output(), cmd.output, out)
+	}
+}
+
+// The types of the entries in the machine readable output of a listed directory.
+const (
+	lsTypeDir    = "dir"
+	lsTypeSecret = "secret"
+	lsTypeLink   = "link"
+)
+
+// lsEntryOutput is the machine readable format of a directory or secret in a listed directory.
+type lsEntryOutput struct {
+	Name      string
+	Type      string
+	Status    string
+	CreatedAt string
+	// Target is the path of the secret a link points to.
+	Target string `json:",omitempty"`
+}
+
+// pageLsEntries returns the page of the entries that starts after the entry with the given name,
+// or at the first entry when no name is given. A limit of zero returns all remaining entries.
+func pageLsEntries(entries []lsEntryOutput, after string, limit int) ([]lsEntryOutput, error) {
+	if after != "" {
+		found := false
+		for i, entry := range entries {
+			if entry.Name == after {
+				entries = entries[i+1:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrCursorNotFound(after)
+		}
+	}
+
+	if limit > 0 && limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// lsVersionOutput is the machine readable format of a listed secret version.
+type lsVersionOutput struct {
+	Version   int
+	Status    string
+	CreatedAt string
+}
+
 // printVersions prints out secret versions in long or short format.
 func printVersions(w io.Writer, quiet bool, timeFormatter TimeFormatter, versions ...*api.SecretVersion) error {
 	if quiet {
@@ -152,8 +336,31 @@ func printVersions(w io.Writer, quiet bool, timeFormatter TimeFormatter, version
 	return nil
 }
 
-// printDir prints out directory contents in long or short format.
-func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter) error {
+// linksNotInDir returns the links of which the name is not used by a secret in the directory,
+// as a secret takes precedence over a link at the same path.
+func linksNotInDir(dir *api.Dir, links map[string]string) map[string]string {
+	result := make(map[string]string, len(links))
+	for name, target := range links {
+		result[name] = target
+	}
+	for _, secret := range dir.Secrets {
+		delete(result, secret.Name)
+	}
+	return result
+}
+
+// sortedLinkNames returns the names of the links in alphabetical order.
+func sortedLinkNames(links map[string]string) []string {
+	names := make([]string, 0, len(links))
+	for name := range links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printDir prints out directory contents and links in long or short format.
+func printDir(w io.Writer, quiet bool, dir *api.Dir, links map[string]string, timeFormatter TimeFormatter) error {
 	sort.Sort(api.SortDirByName(dir.SubDirs))
 	sort.Sort(api.SortSecretByName(dir.Secrets))
 
@@ -164,6 +371,9 @@ func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter
 		for _, secret := range dir.Secrets {
 			fmt.Fprintf(w, "%s\n", secret.Name)
 		}
+		for _, name := range sortedLinkNames(links) {
+			fmt.Fprintf(w, "%s\n", name)
+		}
 	} else {
 		tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
 		fmt.Fprintf(tw, "%s\t%s\t%s\n", "NAME", "STATUS", "CREATED")
@@ -173,6 +383,9 @@ func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter
 		for _, secret := range dir.Secrets {
 			fmt.Fprintf(tw, "%s\t%s\t%s\n", secret.Name, secret.Status, timeFormatter.Format(secret.CreatedAt.Local()))
 		}
+		for _, name := range sortedLinkNames(links) {
+			fmt.Fprintf(tw, "%s -> %s\t%s\t\n", name, links[name], lsTypeLink)
+		}
 		err := tw.Flush()
 		if err != nil {
 			return err
@@ -0,0 +1,134 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestPageLsEntries(t *testing.T) {
+	entries := []lsEntryOutput{
+		{Name: "dir", Type: lsTypeDir},
+		{Name: "a", Type: lsTypeSecret},
+		{Name: "b", Type: lsTypeSecret},
+		{Name: "c", Type: lsTypeSecret},
+	}
+
+	cases := map[string]struct {
+		after    string
+		limit    int
+		expected []lsEntryOutput
+		err      error
+	}{
+		"all": {
+			expected: entries,
+		},
+		"limit": {
+			limit:    2,
+			expected: entries[:2],
+		},
+		"after": {
+			after:    "dir",
+			expected: entries[1:],
+		},
+		"after with limit": {
+			after:    "a",
+			limit:    1,
+			expected: entries[2:3],
+		},
+		"after last": {
+			after:    "c",
+			expected: []lsEntryOutput{},
+		},
+		"limit larger than entries": {
+			limit:    10,
+			expected: entries,
+		},
+		"unknown entry": {
+			after: "d",
+			err:   ErrCursorNotFound("d"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := pageLsEntries(entries, tc.after, tc.limit)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestLsCommand_run_fast(t *testing.T) {
+	tree := &api.Tree{
+		RootDir: &api.Dir{
+			Name:    "dir",
+			SubDirs: []*api.Dir{{Name: "sub"}},
+			Secrets: []*api.Secret{{Name: "secret"}},
+		},
+	}
+
+	cases := map[string]struct {
+		path    api.Path
+		tree    *api.Tree
+		treeErr error
+		out     string
+		err     error
+	}{
+		"directory": {
+			path: "namespace/repo/dir",
+			tree: tree,
+			out:  "sub/\nsecret\n",
+		},
+		"repository": {
+			path: "namespace/repo",
+			tree: tree,
+			out:  "sub/\nsecret\n",
+		},
+		"secret": {
+			path:    "namespace/repo/secret",
+			treeErr: api.ErrDirNotFound,
+			err:     ErrFastListNotDir("namespace/repo/secret"),
+		},
+		"secret version": {
+			path: "namespace/repo/secret:1",
+			err:  ErrFastListNotDir("namespace/repo/secret:1"),
+		},
+		"repository not found": {
+			path:    "namespace/repo",
+			treeErr: api.ErrRepoNotFound("namespace/repo"),
+			err:     api.ErrRepoNotFound("namespace/repo"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			// The client has no secret service, so listing fails when a secret would be fetched.
+			cmd := NewLsCommand(io, func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					DirService: &fakeclient.DirService{
+						GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+							assert.Equal(t, depth, 1)
+							return tc.tree, tc.treeErr
+						},
+					},
+				}, nil
+			})
+			cmd.path = tc.path
+			cmd.fast = true
+			cmd.quiet = true
+
+			err := cmd.run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
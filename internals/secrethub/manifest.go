@@ -0,0 +1,462 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	errManifest        = errio.Namespace("manifest")
+	ErrInvalidManifest = errManifest.Code("invalid_manifest").ErrorPref("invalid manifest: %s")
+	ErrManifestRef     = errManifest.Code("ref_not_found").ErrorPref("cannot resolve the value of %s: the referenced secret %s does not exist")
+)
+
+// The actions of a change in a plan.
+const (
+	planActionCreate = "create"
+	planActionUpdate = "update"
+)
+
+// planActionColorRoles are the roles in the color theme of the actions of a change.
+var planActionColorRoles = map[string]string{
+	planActionCreate: colorRoleAdded,
+	planActionUpdate: colorRoleChanged,
+}
+
+// The kinds of resources that are declared in a manifest.
+const (
+	manifestKindDir        = "dir"
+	manifestKindSecret     = "secret"
+	manifestKindAccessRule = "access rule"
+)
+
+// defaultManifestGeneratorLength is the length of generated secrets when no length is declared.
+const defaultManifestGeneratorLength = 32
+
+// manifest declares the directories, secrets and access rules that should exist.
+// All paths are full paths, starting with the namespace and repository.
+type manifest struct {
+	// Dirs are the directories that should exist. The parent directories of all
+	// declared directories, secrets and access rules are created as well.
+	Dirs []string `yaml:"dirs"`
+	// Secrets are the secrets that should exist and where their values come from.
+	Secrets []manifestSecret `yaml:"secrets"`
+	// Access are the access rules that should exist.
+	Access []manifestAccessRule `yaml:"access"`
+
+	// baseDir is the directory the files of secrets are read relative to.
+	baseDir string
+}
+
+// manifestSecret declares a secret. Exactly one source of the value must be set.
+type manifestSecret struct {
+	Path string `yaml:"path"`
+	// Value is the literal value of the secret.
+	Value *string `yaml:"value"`
+	// File is the path to a file that contains the value of the secret.
+	File string `yaml:"file"`
+	// Ref is the path to another secret of which the latest value is copied.
+	Ref string `yaml:"ref"`
+	// Generate generates a random value when the secret does not exist yet.
+	// The value of a generated secret that exists is never changed.
+	Generate *manifestGenerator `yaml:"generate"`
+}
+
+// manifestGenerator declares how the value of a secret is generated.
+type manifestGenerator struct {
+	Length int `yaml:"length"`
+	// Charset is a comma separated list of character sets, as accepted by `secrethub generate --charset`.
+	Charset string `yaml:"charset"`
+}
+
+// manifestAccessRule declares an access rule.
+type manifestAccessRule struct {
+	Path       string `yaml:"path"`
+	Account    string `yaml:"account"`
+	Permission string `yaml:"permission"`
+}
+
+// readManifest reads and validates the manifest in the YAML file at the given path.
+func readManifest(path string) (manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return manifest{}, ErrReadFile(path, err)
+	}
+
+	m, err := parseManifest(data)
+	if err != nil {
+		return manifest{}, err
+	}
+	m.baseDir = filepath.Dir(path)
+	return m, nil
+}
+
+// parseManifest parses and validates a manifest in YAML.
+func parseManifest(data []byte) (manifest, error) {
+	var m manifest
+	err := yaml.UnmarshalStrict(data, &m)
+	if err != nil {
+		return manifest{}, ErrInvalidManifest(err)
+	}
+
+	err = m.validate()
+	if err != nil {
+		return manifest{}, err
+	}
+	return m, nil
+}
+
+// validate returns an error when the manifest cannot be applied.
+func (m manifest) validate() error {
+	for _, dir := range m.Dirs {
+		if api.ValidateDirPath(dir) != nil {
+			return ErrInvalidManifest(fmt.Sprintf("%q is not a valid directory path", dir))
+		}
+	}
+
+	declared := map[string]bool{}
+	for _, secret := range m.Secrets {
+		if api.ValidateSecretPath(secret.Path) != nil {
+			return ErrInvalidManifest(fmt.Sprintf("%q is not a valid secret path", secret.Path))
+		}
+		if declared[secret.Path] {
+			return ErrInvalidManifest(fmt.Sprintf("secret %s is declared more than once", secret.Path))
+		}
+		declared[secret.Path] = true
+
+		sources := 0
+		for _, set := range []bool{secret.Value != nil, secret.File != "", secret.Ref != "", secret.Generate != nil} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return ErrInvalidManifest(fmt.Sprintf("secret %s must have exactly one of value, file, ref or generate", secret.Path))
+		}
+
+		if secret.Ref != "" && api.ValidateSecretPath(secret.Ref) != nil {
+			return ErrInvalidManifest(fmt.Sprintf("secret %s references %q, which is not a valid secret path", secret.Path, secret.Ref))
+		}
+
+		if secret.Generate != nil {
+			if secret.Generate.Length < 0 {
+				return ErrInvalidManifest(fmt.Sprintf("secret %s has a negative length", secret.Path))
+			}
+			_, err := secret.Generate.generator()
+			if err != nil {
+				return ErrInvalidManifest(fmt.Sprintf("secret %s: %s", secret.Path, err))
+			}
+		}
+	}
+
+	for _, rule := range m.Access {
+		if api.ValidateDirPath(rule.Path) != nil {
+			return ErrInvalidManifest(fmt.Sprintf("%q is not a valid directory path", rule.Path))
+		}
+		if api.ValidateAccountName(rule.Account) != nil {
+			return ErrInvalidManifest(fmt.Sprintf("%q is not a valid account name", rule.Account))
+		}
+		var permission api.Permission
+		if permission.Set(rule.Permission) != nil || permission == api.PermissionNone {
+			return ErrInvalidManifest(fmt.Sprintf("invalid permission %q for %s on %s: must be one of read, write or admin", rule.Permission, rule.Account, rule.Path))
+		}
+	}
+	return nil
+}
+
+// generator returns the generator of the random values.
+func (g manifestGenerator) generator() (randchar.Generator, error) {
+	charset := "alphanumeric"
+	if g.Charset != "" {
+		charset = g.Charset
+	}
+
+	var value charsetValue
+	err := value.Set(charset)
+	if err != nil {
+		return nil, err
+	}
+	return randchar.NewRand(value.v)
+}
+
+// length returns the length of the generated values.
+func (g manifestGenerator) length() int {
+	if g.Length == 0 {
+		return defaultManifestGeneratorLength
+	}
+	return g.Length
+}
+
+// dirs returns the declared directories and the directories below the repository root that
+// must exist for the declared secrets and access rules, parents first.
+func (m manifest) dirs() []string {
+	set := map[string]bool{}
+	add := func(dir string) {
+		for strings.Count(dir, "/") > 1 {
+			set[dir] = true
+			dir = dir[:strings.LastIndex(dir, "/")]
+		}
+	}
+
+	for _, dir := range m.Dirs {
+		add(dir)
+	}
+	for _, secret := range m.Secrets {
+		path := secret.Path
+		add(path[:strings.LastIndex(path, "/")])
+	}
+	for _, rule := range m.Access {
+		add(rule.Path)
+	}
+
+	res := make([]string, 0, len(set))
+	for dir := range set {
+		res = append(res, dir)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// planChange is a change that must be made to make the remote state match the manifest.
+type planChange struct {
+	action   string
+	kind     string
+	resource string
+	apply    func(client secrethub.ClientInterface) error
+}
+
+// manifestPlan contains the changes to make and the number of declared resources that already match the manifest.
+type manifestPlan struct {
+	changes   []planChange
+	unchanged int
+}
+
+// plan compares the manifest to the remote state and returns the changes that must be made.
+// Nothing is written. The values of generated secrets that do not exist yet are generated
+// when planning, so that secrets referencing them get the same value.
+func (m manifest) plan(client secrethub.ClientInterface) (manifestPlan, error) {
+	var plan manifestPlan
+
+	for _, dir := range m.dirs() {
+		exists, err := client.Dirs().Exists(dir)
+		if err != nil {
+			return manifestPlan{}, err
+		}
+		if exists {
+			plan.unchanged++
+			continue
+		}
+
+		path := dir
+		plan.changes = append(plan.changes, planChange{
+			action:   planActionCreate,
+			kind:     manifestKindDir,
+			resource: path,
+			apply: func(client secrethub.ClientInterface) error {
+				_, err := client.Dirs().Create(path)
+				return err
+			},
+		})
+	}
+
+	secretChanges, unchanged, err := m.planSecrets(client)
+	if err != nil {
+		return manifestPlan{}, err
+	}
+	plan.changes = append(plan.changes, secretChanges...)
+	plan.unchanged += unchanged
+
+	for _, rule := range m.Access {
+		current, err := client.AccessRules().Get(rule.Path, rule.Account)
+		if err != nil && !api.IsErrNotFound(err) {
+			return manifestPlan{}, err
+		}
+
+		action := planActionCreate
+		permission := rule.Permission
+		if err == nil {
+			if current.Permission.String() == rule.Permission {
+				plan.unchanged++
+				continue
+			}
+			action = planActionUpdate
+			permission = current.Permission.String() + " -> " + rule.Permission
+		}
+
+		r := rule
+		plan.changes = append(plan.changes, planChange{
+			action:   action,
+			kind:     manifestKindAccessRule,
+			resource: fmt.Sprintf("%s for %s (%s)", r.Path, r.Account, permission),
+			apply: func(client secrethub.ClientInterface) error {
+				_, err := client.AccessRules().Set(r.Path, r.Permission, r.Account)
+				return err
+			},
+		})
+	}
+
+	return plan, nil
+}
+
+// planSecrets returns the changes to the declared secrets and the number of secrets that already match.
+func (m manifest) planSecrets(client secrethub.ClientInterface) ([]planChange, int, error) {
+	current := map[string][]byte{}
+	exists := map[string]bool{}
+	for _, secret := range m.Secrets {
+		version, err := client.Secrets().Versions().GetWithData(secret.Path)
+		if api.IsErrNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, 0, err
+		}
+		current[secret.Path] = version.Data
+		exists[secret.Path] = true
+	}
+
+	// The values of secrets without references are resolved first,
+	// so that references to other declared secrets can use them.
+	desired := map[string][]byte{}
+	for _, secret := range m.Secrets {
+		switch {
+		case secret.Value != nil:
+			desired[secret.Path] = []byte(*secret.Value)
+		case secret.File != "":
+			path := secret.File
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(m.baseDir, path)
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, 0, ErrReadFile(path, err)
+			}
+			desired[secret.Path] = data
+		case secret.Generate != nil:
+			if exists[secret.Path] {
+				desired[secret.Path] = current[secret.Path]
+				continue
+			}
+			generator, err := secret.Generate.generator()
+			if err != nil {
+				return nil, 0, err
+			}
+			data, err := generator.Generate(secret.Generate.length())
+			if err != nil {
+				return nil, 0, err
+			}
+			desired[secret.Path] = data
+		}
+	}
+
+	for _, secret := range m.Secrets {
+		if secret.Ref == "" {
+			continue
+		}
+		value, err := m.resolveRef(client, secret, desired, map[string]bool{})
+		if err != nil {
+			return nil, 0, err
+		}
+		desired[secret.Path] = value
+	}
+
+	var changes []planChange
+	unchanged := 0
+	for _, secret := range m.Secrets {
+		action := planActionCreate
+		if exists[secret.Path] {
+			if bytes.Equal(current[secret.Path], desired[secret.Path]) {
+				unchanged++
+				continue
+			}
+			action = planActionUpdate
+		}
+
+		path, value := secret.Path, desired[secret.Path]
+		changes = append(changes, planChange{
+			action:   action,
+			kind:     manifestKindSecret,
+			resource: path,
+			apply: func(client secrethub.ClientInterface) error {
+				_, err := client.Secrets().Write(path, value)
+				return err
+			},
+		})
+	}
+	return changes, unchanged, nil
+}
+
+// resolveRef returns the value of the secret the declared secret references. When the referenced
+// secret is declared in the manifest, its declared value is used. Otherwise, the latest version is read.
+func (m manifest) resolveRef(client secrethub.ClientInterface, secret manifestSecret, desired map[string][]byte, seen map[string]bool) ([]byte, error) {
+	if seen[secret.Path] {
+		return nil, ErrInvalidManifest(fmt.Sprintf("secret %s references itself", secret.Path))
+	}
+	seen[secret.Path] = true
+
+	for _, other := range m.Secrets {
+		if other.Path != secret.Ref {
+			continue
+		}
+		if other.Ref != "" {
+			return m.resolveRef(client, other, desired, seen)
+		}
+		return desired[other.Path], nil
+	}
+
+	version, err := client.Secrets().Versions().GetWithData(secret.Ref)
+	if api.IsErrNotFound(err) {
+		return nil, ErrManifestRef(secret.Path, secret.Ref)
+	} else if err != nil {
+		return nil, err
+	}
+	return version.Data, nil
+}
+
+// counts returns the number of resources to create and to update.
+func (p manifestPlan) counts() (int, int) {
+	created, updated := 0, 0
+	for _, change := range p.changes {
+		if change.action == planActionCreate {
+			created++
+		} else {
+			updated++
+		}
+	}
+	return created, updated
+}
+
+// print writes the changes of the plan as a table, followed by a summary.
+func (p manifestPlan) print(w io.Writer) error {
+	if len(p.changes) == 0 {
+		fmt.Fprintf(w, "No changes. All %d declared resources match the manifest.\n", p.unchanged)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\t%s\n", "ACTION", "KIND", "RESOURCE")
+	for _, change := range p.changes {
+		// Only the last column is colored, as the escape codes would break the alignment of the other columns.
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", change.action, change.kind, colorize(planActionColorRoles[change.action], change.resource))
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	created, updated := p.counts()
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d unchanged.\n", created, updated, p.unchanged)
+	return nil
+}
@@ -0,0 +1,290 @@
+package secrethub
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestParseManifest(t *testing.T) {
+	cases := map[string]struct {
+		in  string
+		err error
+	}{
+		"valid": {
+			in: "dirs:\n" +
+				"  - namespace/repo/prod\n" +
+				"secrets:\n" +
+				"  - path: namespace/repo/prod/token\n" +
+				"    generate:\n" +
+				"      length: 16\n" +
+				"      charset: numeric,symbols\n" +
+				"  - path: namespace/repo/prod/empty\n" +
+				"    value: ''\n" +
+				"access:\n" +
+				"  - path: namespace/repo/prod\n" +
+				"    account: deploy-bot\n" +
+				"    permission: read\n",
+		},
+		"unknown field": {
+			in:  "secrets:\n  - path: namespace/repo/token\n    values: abc\n",
+			err: ErrInvalidManifest("yaml: unmarshal errors:\n  line 3: field values not found in type secrethub.manifestSecret"),
+		},
+		"invalid dir": {
+			in:  "dirs:\n  - namespace\n",
+			err: ErrInvalidManifest(`"namespace" is not a valid directory path`),
+		},
+		"no source": {
+			in:  "secrets:\n  - path: namespace/repo/token\n",
+			err: ErrInvalidManifest("secret namespace/repo/token must have exactly one of value, file, ref or generate"),
+		},
+		"multiple sources": {
+			in:  "secrets:\n  - path: namespace/repo/token\n    value: abc\n    file: token.txt\n",
+			err: ErrInvalidManifest("secret namespace/repo/token must have exactly one of value, file, ref or generate"),
+		},
+		"duplicate secret": {
+			in:  "secrets:\n  - path: namespace/repo/token\n    value: a\n  - path: namespace/repo/token\n    value: b\n",
+			err: ErrInvalidManifest("secret namespace/repo/token is declared more than once"),
+		},
+		"unknown charset": {
+			in:  "secrets:\n  - path: namespace/repo/token\n    generate:\n      charset: emoji\n",
+			err: ErrInvalidManifest("secret namespace/repo/token: " + ErrCouldNotFindCharSet("emoji").Error()),
+		},
+		"invalid permission": {
+			in:  "access:\n  - path: namespace/repo\n    account: deploy-bot\n    permission: owner\n",
+			err: ErrInvalidManifest(`invalid permission "owner" for deploy-bot on namespace/repo: must be one of read, write or admin`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseManifest([]byte(tc.in))
+
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+const testManifest = "dirs:\n" +
+	"  - namespace/repo/prod\n" +
+	"secrets:\n" +
+	"  - path: namespace/repo/prod/api-url\n" +
+	"    value: https://api.example.com\n" +
+	"  - path: namespace/repo/prod/db/host\n" +
+	"    value: db.internal\n" +
+	"  - path: namespace/repo/prod/db/password\n" +
+	"    generate:\n" +
+	"      length: 16\n" +
+	"  - path: namespace/repo/prod/db/user\n" +
+	"    ref: namespace/repo/shared/db-user\n" +
+	"  - path: namespace/repo/prod/token\n" +
+	"    generate: {}\n" +
+	"  - path: namespace/repo/prod/config\n" +
+	"    file: config.json\n" +
+	"access:\n" +
+	"  - path: namespace/repo/prod\n" +
+	"    account: deploy-bot\n" +
+	"    permission: read\n" +
+	"  - path: namespace/repo/prod\n" +
+	"    account: ci\n" +
+	"    permission: write\n" +
+	"  - path: namespace/repo/prod/db\n" +
+	"    account: dba\n" +
+	"    permission: admin\n"
+
+const testManifestPlan = "ACTION  KIND         RESOURCE\n" +
+	"create  dir          namespace/repo/prod/db\n" +
+	"update  secret       namespace/repo/prod/db/host\n" +
+	"create  secret       namespace/repo/prod/db/user\n" +
+	"create  secret       namespace/repo/prod/token\n" +
+	"create  secret       namespace/repo/prod/config\n" +
+	"update  access rule  namespace/repo/prod for ci (read -> write)\n" +
+	"create  access rule  namespace/repo/prod/db for dba (admin)\n" +
+	"Plan: 5 to create, 2 to update, 4 unchanged.\n"
+
+// manifestTestClient returns a client with the remote state the test manifest is planned against.
+// Written secrets, created directories and set access rules are recorded in the given maps.
+func manifestTestClient(secrets map[string]string, dirs map[string]bool, rules map[string]string) secrethub.ClientInterface {
+	return fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			ExistsFunc: func(path string) (bool, error) {
+				return dirs[path], nil
+			},
+			CreateFunc: func(path string) (*api.Dir, error) {
+				dirs[path] = true
+				return &api.Dir{}, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				secrets[path] = string(data)
+				return &api.SecretVersion{}, nil
+			},
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					value, ok := secrets[path]
+					if !ok {
+						return nil, api.ErrSecretNotFound
+					}
+					return &api.SecretVersion{Data: []byte(value)}, nil
+				},
+			},
+		},
+		AccessRuleService: &fakeclient.AccessRuleService{
+			GetFunc: func(path string, accountName string) (*api.AccessRule, error) {
+				permission, ok := rules[path+" "+accountName]
+				if !ok {
+					return nil, api.ErrAccessRuleNotFound
+				}
+				rule := &api.AccessRule{}
+				err := rule.Permission.Set(permission)
+				return rule, err
+			},
+			SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+				rules[path+" "+accountName] = permission
+				return &api.AccessRule{}, nil
+			},
+		},
+	}
+}
+
+// writeTestManifest writes the test manifest and the file it reads to a temporary directory.
+func writeTestManifest(t *testing.T) (string, func()) {
+	dir, cleanup := testdata.tempDir(t)
+
+	path := filepath.Join(dir, "manifest.yml")
+	assert.OK(t, ioutil.WriteFile(path, []byte(testManifest), 0600))
+	assert.OK(t, ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"debug":false}`), 0600))
+	return path, cleanup
+}
+
+func TestPlanCommand_Run(t *testing.T) {
+	path, cleanup := writeTestManifest(t)
+	defer cleanup()
+
+	secrets := map[string]string{
+		"namespace/repo/prod/api-url":     "https://api.example.com",
+		"namespace/repo/prod/db/host":     "localhost",
+		"namespace/repo/prod/db/password": "existing",
+		"namespace/repo/shared/db-user":   "admin",
+	}
+	rules := map[string]string{
+		"namespace/repo/prod deploy-bot": "read",
+		"namespace/repo/prod ci":         "read",
+	}
+
+	io := fakeui.NewIO(t)
+	cmd := PlanCommand{
+		io:   io,
+		file: path,
+		newClient: func() (secrethub.ClientInterface, error) {
+			return manifestTestClient(secrets, map[string]bool{"namespace/repo/prod": true}, rules), nil
+		},
+	}
+
+	err := cmd.Run()
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), testManifestPlan)
+	assert.Equal(t, len(secrets), 4)
+}
+
+func TestApplyCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		force    bool
+		in       string
+		applied  bool
+		expected string
+		err      error
+	}{
+		"force": {
+			force:   true,
+			applied: true,
+			expected: testManifestPlan +
+				"Created dir namespace/repo/prod/db\n" +
+				"Updated secret namespace/repo/prod/db/host\n" +
+				"Created secret namespace/repo/prod/db/user\n" +
+				"Created secret namespace/repo/prod/token\n" +
+				"Created secret namespace/repo/prod/config\n" +
+				"Updated access rule namespace/repo/prod for ci (read -> write)\n" +
+				"Created access rule namespace/repo/prod/db for dba (admin)\n" +
+				"Apply complete! Resources: 5 created, 2 updated.\n",
+		},
+		"confirmed": {
+			in:      "y\n",
+			applied: true,
+		},
+		"declined": {
+			in:       "n\n",
+			expected: testManifestPlan + "Aborting.\n",
+			err:      ErrAborted,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path, cleanup := writeTestManifest(t)
+			defer cleanup()
+
+			secrets := map[string]string{
+				"namespace/repo/prod/api-url":     "https://api.example.com",
+				"namespace/repo/prod/db/host":     "localhost",
+				"namespace/repo/prod/db/password": "existing",
+				"namespace/repo/shared/db-user":   "admin",
+			}
+			dirs := map[string]bool{"namespace/repo/prod": true}
+			rules := map[string]string{
+				"namespace/repo/prod deploy-bot": "read",
+				"namespace/repo/prod ci":         "read",
+			}
+
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.in)
+			cmd := ApplyCommand{
+				io:    io,
+				file:  path,
+				force: tc.force,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return manifestTestClient(secrets, dirs, rules), nil
+				},
+			}
+
+			err := cmd.Run()
+			assert.Equal(t, err, tc.err)
+			if tc.expected != "" {
+				assert.Equal(t, io.Out.String(), tc.expected)
+			}
+
+			if !tc.applied {
+				assert.Equal(t, len(secrets), 4)
+				return
+			}
+
+			assert.Equal(t, dirs["namespace/repo/prod/db"], true)
+			assert.Equal(t, secrets["namespace/repo/prod/db/host"], "db.internal")
+			assert.Equal(t, secrets["namespace/repo/prod/db/password"], "existing")
+			assert.Equal(t, secrets["namespace/repo/prod/db/user"], "admin")
+			assert.Equal(t, len(secrets["namespace/repo/prod/token"]), defaultManifestGeneratorLength)
+			assert.Equal(t, secrets["namespace/repo/prod/config"], `{"debug":false}`)
+			assert.Equal(t, rules, map[string]string{
+				"namespace/repo/prod deploy-bot": "read",
+				"namespace/repo/prod ci":         "write",
+				"namespace/repo/prod/db dba":     "admin",
+			})
+
+			// Applying the manifest again changes nothing.
+			io = fakeui.NewIO(t)
+			cmd.io = io
+			err = cmd.Run()
+			assert.OK(t, err)
+			assert.Equal(t, io.Out.String(), "No changes. All 11 declared resources match the manifest.\n")
+		})
+	}
+}
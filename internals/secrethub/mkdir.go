@@ -68,6 +68,10 @@ func (cmd *MkDirCommand) createDirectory(client secrethub.ClientInterface, path
 	if dirPath.IsRepoPath() {
 		return ErrMkDirOnRootDir
 	}
+	err = checkDirPolicy(newPolicyStore(client), dirPath)
+	if err != nil {
+		return err
+	}
 	if cmd.parents {
 		return client.Dirs().CreateAll(dirPath.Value())
 	}
@@ -140,9 +140,19 @@ func TestMkDirCommand(t *testing.T) {
 				_ = dirPaths.Set(path)
 			}
 			cmd := MkDirCommand{
-				io:        io,
-				paths:     dirPaths,
-				newClient: tc.newClient,
+				io:    io,
+				paths: dirPaths,
+				newClient: func() (secrethub.ClientInterface, error) {
+					client, err := tc.newClient()
+					if err != nil {
+						return nil, err
+					}
+					fakeClient := client.(fakeclient.Client)
+					fakeClient.SecretService = &fakeclient.SecretService{
+						VersionService: fakePolicyVersionService(""),
+					}
+					return fakeClient, nil
+				},
 			}
 
 			err := cmd.Run()
@@ -155,8 +165,9 @@ func TestMkDirCommand(t *testing.T) {
 
 func TestCreateDirectory(t *testing.T) {
 	cases := map[string]struct {
-		client secrethub.ClientInterface
+		client fakeclient.Client
 		path   string
+		policy string
 		err    error
 	}{
 		"success": {
@@ -194,6 +205,11 @@ func TestCreateDirectory(t *testing.T) {
 			path: "namespace/repo",
 			err:  ErrMkDirOnRootDir,
 		},
+		"policy violation": {
+			path:   "namespace/repo/dir",
+			policy: "org: namespace\nenvironments: [dev, prod]\n",
+			err:    ErrPolicyViolation("namespace/repo/dir", "namespace", "top level directories must be an environment (dev, prod) or a required directory"),
+		},
 		"create dir fails": {
 			client: fakeclient.Client{
 				DirService: &fakeclient.DirService{
@@ -209,6 +225,10 @@ func TestCreateDirectory(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
+			tc.client.SecretService = &fakeclient.SecretService{
+				VersionService: fakePolicyVersionService(tc.policy),
+			}
+
 			cmd := MkDirCommand{}
 			err := cmd.createDirectory(tc.client, tc.path)
 			assert.Equal(t, err, tc.err)
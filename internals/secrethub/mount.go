@@ -0,0 +1,297 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/filemode"
+	"github.com/secrethub/secrethub-cli/internals/cli/fuse"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrCannotMount = errMain.Code("cannot_mount").ErrorPref("cannot mount %s: %s")
+)
+
+// MountCommand mounts a directory as a filesystem, so applications can read the secrets as files.
+type MountCommand struct {
+	io         ui.IO
+	newClient  newClientFunc
+	path       api.DirPath
+	mountpoint string
+	readWrite  bool
+	fileMode   filemode.FileMode
+	cacheTTL   time.Duration
+}
+
+// NewMountCommand creates a new MountCommand.
+func NewMountCommand(io ui.IO, newClient newClientFunc) *MountCommand {
+	return &MountCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *MountCommand) Register(r command.Registerer) {
+	clause := r.Command("mount", "Mount a repository or directory as a FUSE filesystem, in which the directories and secrets are "+
+		"directories and files, so applications that read configuration files can read the secrets without changes. "+
+		"The filesystem is read-only, unless --read-write is set. Only the user that mounts the filesystem can access it and "+
+		"the file mode is checked on every access, regardless of the file mode that is shown. "+
+		"The filesystem is served until the command is interrupted, which unmounts it. Only available on Linux.")
+	clause.Arg("dir-path", "The repository or directory to mount").Required().PlaceHolder(optionalDirPathPlaceHolder).SetValue(&cmd.path)
+	clause.Arg("mountpoint", "The existing directory to mount the filesystem at").Required().ExistingDirVar(&cmd.mountpoint)
+	clause.Flag("read-write", "Allow writing secrets by writing files and creating directories. Files and directories cannot be removed or renamed.").BoolVar(&cmd.readWrite)
+	clause.Flag("file-mode", "The file mode of the secrets. Directories get the execute permission where the file mode has the read permission.").Default("0400").SetValue(&cmd.fileMode)
+	clause.Flag("cache-ttl", "The time the directory tree and the values of secrets are kept in memory before they are fetched again.").Default("1m").DurationVar(&cmd.cacheTTL)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run mounts the directory and serves the filesystem until the command is interrupted.
+func (cmd *MountCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	fs := newSecretFS(client, cmd.path, cmd.fileMode.FileMode(), cmd.readWrite, cmd.cacheTTL)
+	// Fetch the tree before mounting, so a directory that does not exist is not mounted.
+	err = fs.refresh()
+	if err != nil {
+		return err
+	}
+
+	server, err := fuse.Mount(cmd.mountpoint, fs, fuse.MountOptions{
+		Name:      ApplicationName,
+		ReadOnly:  !cmd.readWrite,
+		AttrValid: time.Second,
+		ErrorLog:  os.Stderr,
+	})
+	if err != nil {
+		return ErrCannotMount(cmd.path, err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		err := server.Unmount()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s\n", colorize(colorRoleWarning, "Warning:"), err)
+		}
+	}()
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Mounted %s at %s. Press Ctrl+C to unmount.\n", cmd.path, cmd.mountpoint)
+	return server.Serve()
+}
+
+// secretFS is a filesystem of the directories and secrets in a directory. The directory tree
+// and the values of secrets are kept in memory for the TTL. The filesystem is served one
+// request at a time, so it is not safe for concurrent use.
+type secretFS struct {
+	client    secrethub.ClientInterface
+	root      api.DirPath
+	fileMode  os.FileMode
+	readWrite bool
+	ttl       time.Duration
+	now       func() time.Time
+
+	fetchedAt time.Time
+	dirs      map[string]*api.Dir
+	secrets   map[string]*api.Secret
+	versions  map[string]cachedVersion
+}
+
+// cachedVersion is the latest version of a secret with the time it was fetched.
+type cachedVersion struct {
+	version   *api.SecretVersion
+	fetchedAt time.Time
+}
+
+// newSecretFS returns a filesystem of the directory at the root.
+func newSecretFS(client secrethub.ClientInterface, root api.DirPath, fileMode os.FileMode, readWrite bool, ttl time.Duration) *secretFS {
+	return &secretFS{
+		client:    client,
+		root:      root,
+		fileMode:  fileMode.Perm(),
+		readWrite: readWrite,
+		ttl:       ttl,
+		now:       time.Now,
+		versions:  map[string]cachedVersion{},
+	}
+}
+
+// Stat implements fuse.FS. Stating a secret reads it, as its size is the size of its value.
+func (fs *secretFS) Stat(path string) (fuse.Attr, error) {
+	err := fs.refresh()
+	if err != nil {
+		return fuse.Attr{}, err
+	}
+
+	if dir, ok := fs.dirs[path]; ok {
+		return fuse.Attr{
+			Dir:     true,
+			Mode:    fs.dirMode(),
+			ModTime: dir.LastModifiedAt,
+		}, nil
+	}
+
+	version, err := fs.version(path)
+	if err != nil {
+		return fuse.Attr{}, err
+	}
+	return fuse.Attr{
+		Size:    uint64(len(version.Data)),
+		Mode:    fs.fileMode,
+		ModTime: version.CreatedAt,
+	}, nil
+}
+
+// ReadDir implements fuse.FS.
+func (fs *secretFS) ReadDir(path string) ([]fuse.Dirent, error) {
+	err := fs.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, ok := fs.dirs[path]
+	if !ok {
+		return nil, fuse.ErrNotExist
+	}
+
+	entries := make([]fuse.Dirent, 0, len(dir.SubDirs)+len(dir.Secrets))
+	for _, sub := range dir.SubDirs {
+		entries = append(entries, fuse.Dirent{Name: sub.Name, Dir: true})
+	}
+	for _, secret := range dir.Secrets {
+		entries = append(entries, fuse.Dirent{Name: secret.Name})
+	}
+	return entries, nil
+}
+
+// ReadFile implements fuse.FS.
+func (fs *secretFS) ReadFile(path string) ([]byte, error) {
+	err := fs.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := fs.version(path)
+	if err != nil {
+		return nil, err
+	}
+	return version.Data, nil
+}
+
+// WriteFile implements fuse.FS by writing a new version of the secret.
+func (fs *secretFS) WriteFile(path string, data []byte) error {
+	if !fs.readWrite {
+		return fuse.ErrReadOnly
+	}
+
+	version, err := fs.client.Secrets().Write(fs.fullPath(path), data)
+	if err != nil {
+		return err
+	}
+	version.Data = data
+	fs.versions[path] = cachedVersion{version: version, fetchedAt: fs.now()}
+	fs.invalidate()
+	return nil
+}
+
+// Mkdir implements fuse.FS.
+func (fs *secretFS) Mkdir(path string) error {
+	if !fs.readWrite {
+		return fuse.ErrReadOnly
+	}
+
+	_, err := fs.client.Dirs().Create(fs.fullPath(path))
+	if err != nil {
+		return err
+	}
+	fs.invalidate()
+	return nil
+}
+
+// refresh fetches the directory tree when it has not been fetched within the TTL.
+func (fs *secretFS) refresh() error {
+	if fs.dirs != nil && fs.now().Sub(fs.fetchedAt) < fs.ttl {
+		return nil
+	}
+
+	tree, err := fs.client.Dirs().GetTree(fs.root.Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	fs.dirs = map[string]*api.Dir{}
+	fs.secrets = map[string]*api.Secret{}
+	fs.addDir("", tree.RootDir)
+	fs.fetchedAt = fs.now()
+	return nil
+}
+
+// addDir adds the directory at the path and its contents to the paths of the filesystem.
+func (fs *secretFS) addDir(path string, dir *api.Dir) {
+	fs.dirs[path] = dir
+	for _, sub := range dir.SubDirs {
+		fs.addDir(joinFSPath(path, sub.Name), sub)
+	}
+	for _, secret := range dir.Secrets {
+		fs.secrets[joinFSPath(path, secret.Name)] = secret
+	}
+}
+
+// invalidate makes the directory tree be fetched again on the next call.
+func (fs *secretFS) invalidate() {
+	fs.dirs = nil
+}
+
+// version returns the latest version of the secret at the path, which is fetched
+// when it has not been fetched within the TTL.
+func (fs *secretFS) version(path string) (*api.SecretVersion, error) {
+	if _, ok := fs.secrets[path]; !ok {
+		return nil, fuse.ErrNotExist
+	}
+
+	cached, ok := fs.versions[path]
+	if ok && fs.now().Sub(cached.fetchedAt) < fs.ttl {
+		return cached.version, nil
+	}
+
+	version, err := fs.client.Secrets().Versions().GetWithData(fs.fullPath(path))
+	if err != nil {
+		return nil, err
+	}
+	fs.versions[path] = cachedVersion{version: version, fetchedAt: fs.now()}
+	return version, nil
+}
+
+// fullPath returns the SecretHub path of the path in the filesystem.
+func (fs *secretFS) fullPath(path string) string {
+	return strings.TrimSuffix(fs.root.Value()+"/"+path, "/")
+}
+
+// dirMode returns the file mode of directories, which can be traversed by those that can read the secrets.
+func (fs *secretFS) dirMode() os.FileMode {
+	return fs.fileMode | (fs.fileMode&0444)>>2
+}
+
+// joinFSPath returns the path of the name in the directory at the path in the filesystem.
+func joinFSPath(dir string, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
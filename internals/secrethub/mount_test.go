@@ -0,0 +1,105 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/fuse"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestSecretFS(t *testing.T) {
+	modTime := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	tree := &api.Tree{
+		RootDir: &api.Dir{
+			Name:           "repo",
+			LastModifiedAt: modTime,
+			SubDirs: []*api.Dir{
+				{
+					Name:           "dir",
+					LastModifiedAt: modTime,
+					Secrets:        []*api.Secret{{Name: "nested"}},
+				},
+			},
+			Secrets: []*api.Secret{{Name: "secret"}},
+		},
+	}
+
+	now := modTime
+	treeFetches := 0
+	var reads []string
+	var writes []string
+	client := fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				assert.Equal(t, path, "namespace/repo")
+				treeFetches++
+				return tree, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					reads = append(reads, path)
+					return &api.SecretVersion{Data: []byte("value of " + path), CreatedAt: modTime}, nil
+				},
+			},
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				writes = append(writes, path+"="+string(data))
+				return &api.SecretVersion{CreatedAt: modTime}, nil
+			},
+		},
+	}
+
+	fs := newSecretFS(client, "namespace/repo", 0400, false, time.Minute)
+	fs.now = func() time.Time {
+		return now
+	}
+
+	attr, err := fs.Stat("")
+	assert.OK(t, err)
+	assert.Equal(t, attr, fuse.Attr{Dir: true, Mode: 0500, ModTime: modTime})
+
+	entries, err := fs.ReadDir("")
+	assert.OK(t, err)
+	assert.Equal(t, entries, []fuse.Dirent{{Name: "dir", Dir: true}, {Name: "secret"}})
+
+	attr, err = fs.Stat("dir/nested")
+	assert.OK(t, err)
+	assert.Equal(t, attr, fuse.Attr{Size: uint64(len("value of namespace/repo/dir/nested")), Mode: 0400, ModTime: modTime})
+
+	data, err := fs.ReadFile("dir/nested")
+	assert.OK(t, err)
+	assert.Equal(t, string(data), "value of namespace/repo/dir/nested")
+
+	_, err = fs.Stat("missing")
+	assert.Equal(t, err, fuse.ErrNotExist)
+
+	err = fs.WriteFile("secret", []byte("new"))
+	assert.Equal(t, err, fuse.ErrReadOnly)
+
+	// The tree and the values are cached for the TTL.
+	assert.Equal(t, treeFetches, 1)
+	assert.Equal(t, reads, []string{"namespace/repo/dir/nested"})
+
+	now = now.Add(time.Minute)
+	_, err = fs.ReadFile("dir/nested")
+	assert.OK(t, err)
+	assert.Equal(t, treeFetches, 2)
+	assert.Equal(t, reads, []string{"namespace/repo/dir/nested", "namespace/repo/dir/nested"})
+
+	fs.readWrite = true
+	err = fs.WriteFile("secret", []byte("new"))
+	assert.OK(t, err)
+	assert.Equal(t, writes, []string{"namespace/repo/secret=new"})
+
+	// The written value is read from memory, but the tree is fetched again.
+	data, err = fs.ReadFile("secret")
+	assert.OK(t, err)
+	assert.Equal(t, string(data), "new")
+	assert.Equal(t, treeFetches, 3)
+	assert.Equal(t, len(reads), 2)
+}
@@ -0,0 +1,134 @@
+package secrethub
+
+import (
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// The types of namespaces.
+const (
+	namespaceTypeUser = "user"
+	namespaceTypeOrg  = "org"
+)
+
+// The roles of the account in a namespace that are not organization roles.
+const (
+	// namespaceRoleOwner is the role of a user in their personal namespace.
+	namespaceRoleOwner = "owner"
+	// namespaceRoleCollaborator is the role of a user in the personal namespace of another
+	// user, which they can only access through the repositories that are shared with them.
+	namespaceRoleCollaborator = "collaborator"
+)
+
+// NamespaceCommand handles operations on namespaces.
+type NamespaceCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewNamespaceCommand creates a new NamespaceCommand.
+func NewNamespaceCommand(io ui.IO, newClient newClientFunc) *NamespaceCommand {
+	return &NamespaceCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *NamespaceCommand) Register(r command.Registerer) {
+	clause := r.Command("namespaces", "Discover the personal and organization namespaces you have access to.")
+	clause.Alias("namespace")
+	NewNamespaceLsCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// accessibleNamespace is a namespace the account has access to, with the role of the account in
+// the namespace and the repositories in it that the account has access to.
+type accessibleNamespace struct {
+	name  string
+	typ   string
+	role  string
+	repos []*api.Repo
+}
+
+// listAccessibleNamespaces returns the personal namespace of the user, the namespaces of the organizations
+// the user is a member of and the personal namespaces of other users that share a repository with the user,
+// sorted by name.
+func listAccessibleNamespaces(client secrethub.ClientInterface) ([]*accessibleNamespace, error) {
+	me, err := client.Me().GetUser()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := map[string]*accessibleNamespace{
+		me.Username: {
+			name: me.Username,
+			typ:  namespaceTypeUser,
+			role: namespaceRoleOwner,
+		},
+	}
+	listed := map[string]bool{}
+
+	orgs, err := client.Orgs().ListMine()
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		member, err := client.Orgs().Members().Get(org.Name, me.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		// Admins can access all repositories of the organization, which are not all listed as their repositories.
+		repos, err := client.Repos().List(org.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			listed[repo.Path().String()] = true
+		}
+
+		namespaces[org.Name] = &accessibleNamespace{
+			name:  org.Name,
+			typ:   namespaceTypeOrg,
+			role:  member.Role,
+			repos: repos,
+		}
+	}
+
+	repos, err := client.Repos().ListMine()
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range repos {
+		if listed[repo.Path().String()] {
+			continue
+		}
+		listed[repo.Path().String()] = true
+
+		namespace, ok := namespaces[repo.Owner]
+		if !ok {
+			namespace = &accessibleNamespace{
+				name: repo.Owner,
+				typ:  namespaceTypeUser,
+				role: namespaceRoleCollaborator,
+			}
+			namespaces[repo.Owner] = namespace
+		}
+		namespace.repos = append(namespace.repos, repo)
+	}
+
+	res := make([]*accessibleNamespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		sort.Sort(api.SortRepoByName(namespace.repos))
+		res = append(res, namespace)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].name < res[j].name
+	})
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package secrethub
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// NamespaceLsCommand lists the namespaces the account has access to.
+type NamespaceLsCommand struct {
+	quiet     bool
+	output    string
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewNamespaceLsCommand creates a new NamespaceLsCommand.
+func NewNamespaceLsCommand(io ui.IO, newClient newClientFunc) *NamespaceLsCommand {
+	return &NamespaceLsCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *NamespaceLsCommand) Register(r command.Registerer) {
+	clause := r.Command("ls", "List your personal namespace, the namespaces of the organizations you are a member of "+
+		"and the namespaces of other users that share a repository with you, together with your role in them.")
+	clause.Alias("list")
+	registerOutputFlag(clause, &cmd.output, formatTable, formatJSON, formatYAML)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run lists the namespaces the account has access to.
+func (cmd *NamespaceLsCommand) Run() error {
+	cmd.beforeRun()
+	return cmd.run()
+}
+
+// beforeRun configures the command using the flag values.
+func (cmd *NamespaceLsCommand) beforeRun() {
+	cmd.quiet = cmd.quiet || quietOutput
+}
+
+// run lists the namespaces the account has access to.
+func (cmd *NamespaceLsCommand) run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	namespaces, err := listAccessibleNamespaces(client)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cmd.output != formatTable && cmd.output != "":
+		out := make([]namespaceLsOutput, len(namespaces))
+		for i, namespace := range namespaces {
+			out[i] = namespaceLsOutput{
+				Name:  namespace.name,
+				Type:  namespace.typ,
+				Role:  namespace.role,
+				Repos: len(namespace.repos),
+			}
+		}
+		return writeOutput(cmd.io.Output(), cmd.output, out)
+	case cmd.quiet:
+		for _, namespace := range namespaces {
+			fmt.Fprintf(cmd.io.Output(), "%s\n", namespace.name)
+		}
+	default:
+		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "NAME", "TYPE", "ROLE", "REPOS")
+		for _, namespace := range namespaces {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", namespace.name, namespace.typ, namespace.role, len(namespace.repos))
+		}
+		err = w.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// namespaceLsOutput is the machine readable format of a listed namespace.
+type namespaceLsOutput struct {
+	Name  string
+	Type  string
+	Role  string
+	Repos int
+}
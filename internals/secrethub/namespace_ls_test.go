@@ -0,0 +1,159 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// fakeMeClient is a fake client of which the Me service returns the given user.
+type fakeMeClient struct {
+	fakeclient.Client
+	user *api.User
+	// getUser is called to get the user instead when it is set.
+	getUser func() (*api.User, error)
+}
+
+// Me returns a MeService of which GetUser returns the user of the client.
+func (c fakeMeClient) Me() secrethub.MeService {
+	return fakeMeService{user: c.user, getUser: c.getUser}
+}
+
+// fakeMeService is a MeService of which only GetUser is implemented.
+type fakeMeService struct {
+	secrethub.MeService
+	user    *api.User
+	getUser func() (*api.User, error)
+}
+
+// GetUser returns the user of the service.
+func (s fakeMeService) GetUser() (*api.User, error) {
+	if s.getUser != nil {
+		return s.getUser()
+	}
+	return s.user, nil
+}
+
+func TestNamespaceLsCommand_run(t *testing.T) {
+	testErr := errio.Namespace("test").Code("test").Error("test error")
+
+	orgService := &fakeclient.OrgService{
+		ListMineFunc: func() ([]*api.Org, error) {
+			return []*api.Org{{Name: "company"}}, nil
+		},
+		MembersService: &fakeclient.OrgMemberService{
+			GetFunc: func(org string, username string) (*api.OrgMember, error) {
+				return &api.OrgMember{Role: api.OrgRoleAdmin}, nil
+			},
+		},
+	}
+	repoService := &fakeclient.RepoService{
+		ListFunc: func(namespace string) ([]*api.Repo, error) {
+			return []*api.Repo{
+				{Owner: "company", Name: "backend"},
+				{Owner: "company", Name: "frontend"},
+			}, nil
+		},
+		ListMineFunc: func() ([]*api.Repo, error) {
+			return []*api.Repo{
+				{Owner: "dev1", Name: "repo"},
+				{Owner: "company", Name: "backend"},
+				{Owner: "dev2", Name: "shared"},
+			}, nil
+		},
+	}
+
+	cases := map[string]struct {
+		cmd         NamespaceLsCommand
+		orgService  *fakeclient.OrgService
+		repoService *fakeclient.RepoService
+		out         string
+		err         error
+	}{
+		"success": {
+			orgService: orgService,
+			out: "NAME     TYPE  ROLE          REPOS\n" +
+				"company  org   admin         2\n" +
+				"dev1     user  owner         1\n" +
+				"dev2     user  collaborator  1\n",
+		},
+		"quiet": {
+			cmd: NamespaceLsCommand{
+				quiet: true,
+			},
+			orgService: orgService,
+			out:        "company\ndev1\ndev2\n",
+		},
+		"json": {
+			cmd: NamespaceLsCommand{
+				output: formatJSON,
+			},
+			orgService: &fakeclient.OrgService{
+				ListMineFunc: func() ([]*api.Org, error) {
+					return nil, nil
+				},
+			},
+			repoService: &fakeclient.RepoService{
+				ListMineFunc: func() ([]*api.Repo, error) {
+					return []*api.Repo{
+						{Owner: "dev1", Name: "repo"},
+						{Owner: "dev2", Name: "shared"},
+					}, nil
+				},
+			},
+			out: "[\n" +
+				"    {\n" +
+				"        \"Name\": \"dev1\",\n" +
+				"        \"Type\": \"user\",\n" +
+				"        \"Role\": \"owner\",\n" +
+				"        \"Repos\": 1\n" +
+				"    },\n" +
+				"    {\n" +
+				"        \"Name\": \"dev2\",\n" +
+				"        \"Type\": \"user\",\n" +
+				"        \"Role\": \"collaborator\",\n" +
+				"        \"Repos\": 1\n" +
+				"    }\n" +
+				"]\n",
+		},
+		"list orgs error": {
+			orgService: &fakeclient.OrgService{
+				ListMineFunc: func() ([]*api.Org, error) {
+					return nil, testErr
+				},
+			},
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if tc.repoService == nil {
+				tc.repoService = repoService
+			}
+
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeMeClient{
+					Client: fakeclient.Client{
+						OrgService:  tc.orgService,
+						RepoService: tc.repoService,
+					},
+					user: &api.User{Username: "dev1"},
+				}, nil
+			}
+
+			err := tc.cmd.run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrNoNotifyTarget            = errMain.Code("no_notify_target").Error("nothing to notify: use --slack-webhook, --desktop or both")
+	ErrInvalidSlackWebhook       = errMain.Code("invalid_slack_webhook").ErrorPref("the secret %s does not contain a Slack webhook URL")
+	ErrSlackNotificationFailed   = errMain.Code("slack_notification_failed").ErrorPref("could not post the notification to Slack: %s")
+	ErrDesktopNotifyNotSupported = errMain.Code("desktop_notify_not_supported").ErrorPref("desktop notifications are not supported on %s")
+)
+
+// NotifyCommand posts notifications when the secrets in a directory change.
+type NotifyCommand struct {
+	io            ui.IO
+	watch         api.DirPath
+	slackWebhook  api.SecretPath
+	desktop       bool
+	interval      time.Duration
+	newClient     newClientFunc
+	httpClient    *http.Client
+	notifyDesktop func(title string, message string) error
+	sleep         func(time.Duration)
+}
+
+// NewNotifyCommand creates a new NotifyCommand.
+func NewNotifyCommand(io ui.IO, newClient newClientFunc) *NotifyCommand {
+	return &NotifyCommand{
+		io:            io,
+		newClient:     newClient,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		notifyDesktop: notifyDesktop,
+		sleep:         time.Sleep,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *NotifyCommand) Register(r command.Registerer) {
+	clause := r.Command("notify", "Keep running and post a notification when a new version of a secret in a directory is written or a secret is deleted, "+
+		"so rotations are visible to the team. As SecretHub has no push channel for events, the audit log of the repository is polled. "+
+		"Secrets that have been deleted can no longer be traced to their directory, so their deletion is only reported when the watched directory is the root of the repository.")
+	clause.Flag("watch", "The directory of which to watch the secrets, including those in its subdirectories.").Required().PlaceHolder(optionalDirPathPlaceHolder).SetValue(&cmd.watch)
+	clause.Flag("slack-webhook", "The path to a secret containing the URL of a Slack incoming webhook to post the notifications to.").PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.slackWebhook)
+	clause.Flag("desktop", "Show the notifications on this computer, for personal watches.").BoolVar(&cmd.desktop)
+	clause.Flag("interval", "The interval at which to poll for changes.").Default("60s").DurationVar(&cmd.interval)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run polls for changes and posts notifications, until the process is interrupted.
+func (cmd *NotifyCommand) Run() error {
+	if cmd.slackWebhook == "" && !cmd.desktop {
+		return ErrNoNotifyTarget
+	}
+	if cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	var slackURL string
+	if cmd.slackWebhook != "" {
+		client, err := cmd.newClient()
+		if err != nil {
+			return err
+		}
+		secret, err := client.Secrets().Versions().GetWithData(cmd.slackWebhook.Value())
+		if err != nil {
+			return err
+		}
+		slackURL = strings.TrimSpace(string(secret.Data))
+		if !strings.HasPrefix(slackURL, "https://") {
+			return ErrInvalidSlackWebhook(cmd.slackWebhook)
+		}
+	}
+
+	repoPath := api.Path(cmd.watch.GetRepoPath().Value())
+	iter, _, err := auditRecordSource(repoPath, cmd.newClient)
+	if err != nil {
+		return err
+	}
+	pos, err := newestAuditPosition(iter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Watching %s for changes every %s.\n", cmd.watch, cmd.interval)
+
+	for {
+		cmd.sleep(cmd.interval)
+
+		iter, newRecord, err := auditRecordSource(repoPath, cmd.newClient)
+		if err != nil {
+			return err
+		}
+
+		events, err := eventsAfter(iter, pos)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			pos.advance(event)
+			if !isSecretChange(event) {
+				continue
+			}
+
+			record, err := newRecord(event)
+			if err != nil {
+				return err
+			}
+			if !cmd.isWatched(event, record) {
+				continue
+			}
+
+			cmd.notify(slackURL, record)
+		}
+	}
+}
+
+// isSecretChange returns whether the event is a new version of a secret or the deletion of a secret.
+// The creation of a secret is not a change of its own, as it is always followed by its first version.
+func isSecretChange(event api.Audit) bool {
+	switch event.Action {
+	case api.AuditActionCreate:
+		return event.Subject.Type == api.AuditSubjectSecretVersion
+	case api.AuditActionDelete:
+		return event.Subject.Type == api.AuditSubjectSecret || event.Subject.Type == api.AuditSubjectSecretVersion
+	}
+	return false
+}
+
+// isWatched returns whether the subject of the event is in the watched directory.
+func (cmd *NotifyCommand) isWatched(event api.Audit, record auditRecord) bool {
+	if cmd.watch.IsRepoPath() {
+		return true
+	}
+	return !event.Subject.Deleted && strings.HasPrefix(record.Subject, cmd.watch.Value()+"/")
+}
+
+// notify posts the notification of the change. Notifications that cannot be posted are reported,
+// so a temporary failure does not stop the watch.
+func (cmd *NotifyCommand) notify(slackURL string, record auditRecord) {
+	if slackURL != "" {
+		err := postSlackMessage(cmd.httpClient, slackURL, formatChangeNotification(record, "`"))
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "%s %s\n", colorize(colorRoleWarning, "Warning:"), err)
+		}
+	}
+	if cmd.desktop {
+		err := cmd.notifyDesktop("SecretHub", formatChangeNotification(record, ""))
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "%s %s\n", colorize(colorRoleWarning, "Warning:"), err)
+		}
+	}
+}
+
+// formatChangeNotification returns the message that describes the change, with the subject between the given quotes.
+func formatChangeNotification(record auditRecord, quote string) string {
+	if record.Category == auditActionDelete {
+		return fmt.Sprintf("%s deleted %s%s%s", record.Actor, quote, record.Subject, quote)
+	}
+	return fmt.Sprintf("%s wrote a new version of %s%s%s", record.Actor, quote, record.Subject, quote)
+}
+
+// postSlackMessage posts the message to a Slack incoming webhook.
+func postSlackMessage(client *http.Client, url string, message string) error {
+	payload, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{
+		Text: message,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return ErrSlackNotificationFailed(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ErrSlackNotificationFailed(resp.Status)
+	}
+	return nil
+}
+
+// notifyDesktop shows a notification with the tools that come with the operating system.
+func notifyDesktop(title string, message string) error {
+	switch runtime.GOOS {
+	case "linux":
+		return exec.Command("notify-send", title, message).Run()
+	case "darwin":
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`, quote.Replace(message), quote.Replace(title))
+		return exec.Command("osascript", "-e", script).Run()
+	default:
+		return ErrDesktopNotifyNotSupported(runtime.GOOS)
+	}
+}
@@ -0,0 +1,147 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestNotifyCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	rootDirID := uuid.New()
+	prodDirID := uuid.New()
+	passwordID := uuid.New()
+	tokenID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		RootDir:    &api.Dir{Name: "repo", DirID: rootDirID},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {Name: "repo", DirID: rootDirID},
+			prodDirID: {Name: "prod", DirID: prodDirID, ParentID: &rootDirID},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{
+			passwordID: {Name: "db_password", DirID: prodDirID},
+			tokenID:    {Name: "token", DirID: rootDirID},
+		},
+	}
+
+	event := func(action api.AuditAction, secretID uuid.UUID, version int, loggedAt time.Time) api.Audit {
+		return api.Audit{
+			EventID: uuid.New(),
+			Action:  action,
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{Username: "developer"},
+			},
+			LoggedAt: loggedAt,
+			Subject: api.AuditSubject{
+				Type: api.AuditSubjectSecretVersion,
+				SecretVersion: &api.SecretVersion{
+					Version: version,
+					Secret:  &api.Secret{SecretID: secretID},
+				},
+			},
+		}
+	}
+	existing := event(api.AuditActionCreate, passwordID, 1, start)
+	rotated := event(api.AuditActionCreate, passwordID, 2, start.Add(time.Minute))
+	read := event(api.AuditActionRead, passwordID, 2, start.Add(2*time.Minute))
+	outside := event(api.AuditActionCreate, tokenID, 3, start.Add(3*time.Minute))
+	deleted := event(api.AuditActionDelete, passwordID, 1, start.Add(4*time.Minute))
+
+	var slackMessages []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Text string `json:"text"`
+		}
+		assert.OK(t, json.NewDecoder(r.Body).Decode(&msg))
+		slackMessages = append(slackMessages, msg.Text)
+	}))
+	defer server.Close()
+
+	polls := [][]api.Audit{
+		nil,
+		{existing},
+		{rotated, existing},
+		{deleted, outside, read, rotated, existing},
+	}
+
+	var desktopMessages []string
+	fakeIO := fakeui.NewIO(t)
+	cmd := NotifyCommand{
+		io:           fakeIO,
+		watch:        "namespace/repo/prod",
+		slackWebhook: "namespace/repo/slack_webhook",
+		desktop:      true,
+		interval:     time.Minute,
+		httpClient:   server.Client(),
+		notifyDesktop: func(title string, message string) error {
+			desktopMessages = append(desktopMessages, message)
+			return nil
+		},
+		sleep: func(time.Duration) {},
+		newClient: func() (secrethub.ClientInterface, error) {
+			if len(polls) == 0 {
+				return nil, testErr
+			}
+			events := polls[0]
+			polls = polls[1:]
+			return fakeclient.Client{
+				DirService: &fakeclient.DirService{
+					GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+						return tree, nil
+					},
+				},
+				RepoService: &fakeclient.RepoService{
+					AuditEventIterator: &fakeclient.AuditEventIterator{
+						Events: events,
+					},
+				},
+				SecretService: &fakeclient.SecretService{
+					VersionService: &fakeclient.SecretVersionService{
+						GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+							return &api.SecretVersion{Data: []byte(server.URL + "\n")}, nil
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	err := cmd.Run()
+
+	assert.Equal(t, err, testErr)
+	assert.Equal(t, fakeIO.Out.String(), "Watching namespace/repo/prod for changes every 1m0s.\n")
+	assert.Equal(t, slackMessages, []string{
+		"developer wrote a new version of `namespace/repo/prod/db_password:2`",
+		"developer deleted `namespace/repo/prod/db_password:1`",
+	})
+	assert.Equal(t, desktopMessages, []string{
+		"developer wrote a new version of namespace/repo/prod/db_password:2",
+		"developer deleted namespace/repo/prod/db_password:1",
+	})
+}
+
+func TestNotifyCommand_Run_NoTarget(t *testing.T) {
+	cmd := NotifyCommand{
+		watch:    "namespace/repo",
+		interval: time.Minute,
+	}
+
+	err := cmd.Run()
+
+	assert.Equal(t, err, ErrNoNotifyTarget)
+}
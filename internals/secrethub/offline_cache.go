@@ -0,0 +1,181 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// Errors
+var (
+	ErrInvalidOfflineCacheConfig = errMain.Code("invalid_offline_cache_config").ErrorPref("invalid offline_cache configured in %s: %s")
+	ErrNotAvailableOffline       = errMain.Code("not_available_offline").StatusErrorPref(
+		"the secret is not in the offline cache. Secrets can only be read offline when they have been read before "+
+			"within the max age of the offline cache (%s), with the offline_cache setting enabled in the "+configFilename+" file",
+		http.StatusServiceUnavailable,
+	)
+)
+
+// defaultOfflineCacheMaxAge is the time secrets are kept available offline when the offline cache
+// is enabled without a max_age.
+const defaultOfflineCacheMaxAge = 24 * time.Hour
+
+// offlineCacheConfig is the offline cache in the configuration file. The cache is enabled when it is set.
+type offlineCacheConfig struct {
+	// MaxAge is the time a secret that has been read can be read offline, e.g. 12h.
+	MaxAge string `json:"max_age,omitempty"`
+}
+
+// offlineCacheDir returns the directory the secrets that can be read offline are cached in.
+func offlineCacheDir(dir configdir.Dir) string {
+	return filepath.Join(cacheDir(dir), "offline")
+}
+
+// loadOfflineCacheMaxAge returns the max age of the offline cache configured in the configuration
+// directory and whether the offline cache is enabled.
+func loadOfflineCacheMaxAge(dir configdir.Dir) (time.Duration, bool, error) {
+	config, err := loadConfig(dir)
+	if err != nil {
+		return defaultOfflineCacheMaxAge, false, err
+	}
+	file := configFile(dir)
+	if config.OfflineCache == nil {
+		return defaultOfflineCacheMaxAge, false, nil
+	}
+	if config.OfflineCache.MaxAge == "" {
+		return defaultOfflineCacheMaxAge, true, nil
+	}
+
+	maxAge, err := time.ParseDuration(config.OfflineCache.MaxAge)
+	if err != nil {
+		return defaultOfflineCacheMaxAge, false, ErrInvalidOfflineCacheConfig(file.path, err)
+	}
+	if maxAge <= 0 {
+		return defaultOfflineCacheMaxAge, false, ErrInvalidOfflineCacheConfig(file.path, "the max age must be positive")
+	}
+	return maxAge, true, nil
+}
+
+// offlineCacheMaxAge returns the max age of the offline cache and whether it is enabled.
+// An invalid configuration does not stop the command: a warning is printed and the cache is not used.
+func (f *clientFactory) offlineCacheMaxAge() (time.Duration, bool) {
+	maxAge, enabled, err := loadOfflineCacheMaxAge(f.store.ConfigDir())
+	if err != nil {
+		warnConfig(err)
+	}
+	return maxAge, enabled
+}
+
+// offlineCacheTransport is a http.RoundTripper that caches the secrets that are read, together with the
+// account key and repository keys that are needed to decrypt them, so they can still be read when the
+// API cannot be reached. The cached responses are the end-to-end encrypted responses of the API, so the
+// secrets can only be decrypted with the credential of the account that read them.
+//
+// When the API cannot be reached or fails with a server error, a cached response that is not older than
+// the max age is returned instead. In offline mode, the API is not called at all.
+type offlineCacheTransport struct {
+	next    http.RoundTripper
+	dir     string
+	maxAge  time.Duration
+	offline bool
+	now     func() time.Time
+	stderr  io.Writer
+	warned  sync.Once
+}
+
+// newOfflineCacheTransport returns a transport that passes the API calls on to next, unless offline is set,
+// and that serves the cached secrets that are not older than maxAge when the API cannot be reached.
+func newOfflineCacheTransport(next http.RoundTripper, dir string, maxAge time.Duration, offline bool) *offlineCacheTransport {
+	return &offlineCacheTransport{
+		next:    next,
+		dir:     dir,
+		maxAge:  maxAge,
+		offline: offline,
+		now:     time.Now,
+		stderr:  os.Stderr,
+	}
+}
+
+// RoundTrip performs the request and caches the response, or returns the cached response when the request
+// fails or when in offline mode.
+func (t *offlineCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, cacheable := "", false
+	if req.Method == http.MethodGet && isOfflineCacheable(req) {
+		path, cacheable = cacheEntryPath(t.dir, req)
+	}
+
+	if t.offline {
+		if cacheable {
+			body, ok := readCacheEntry(path, t.maxAge, t.now())
+			if ok {
+				structuredLog.debug("cache_hit", logFields{"cache": "offline", "url": req.URL.String()})
+				return cachedResponse(req, body), nil
+			}
+		}
+		return errorResponse(req, ErrNotAvailableOffline(t.maxAge))
+	}
+
+	if !cacheable {
+		return t.next.RoundTrip(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode < 500 {
+		return cacheResponse(t.dir, path, resp)
+	}
+
+	body, ok := readCacheEntry(path, t.maxAge, t.now())
+	if !ok {
+		return resp, err
+	}
+	if resp != nil {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	structuredLog.debug("cache_hit", logFields{"cache": "offline", "url": req.URL.String(), "reason": "api_unavailable"})
+	t.warned.Do(func() {
+		fmt.Fprintf(t.stderr, "%s the SecretHub API is unavailable, so secrets are read from the offline cache.\n", colorize(colorRoleWarning, "Warning:"))
+	})
+	return cachedResponse(req, body), nil
+}
+
+// isOfflineCacheable returns whether the request fetches data that is needed to read a secret:
+// the account key, the keys of a repository or a version of a secret.
+func isOfflineCacheable(req *http.Request) bool {
+	elements := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	n := len(elements)
+	switch {
+	case n >= 2 && elements[n-2] == "me" && elements[n-1] == "key":
+		return true
+	case n >= 5 && elements[n-5] == "namespaces" && elements[n-3] == "repos" && elements[n-1] == "keys":
+		return true
+	case n >= 2 && elements[n-2] == "secrets":
+		return true
+	case n >= 4 && elements[n-4] == "secrets" && elements[n-2] == "versions":
+		return true
+	}
+	return false
+}
+
+// errorResponse returns an error response to the request, which the client returns as the given error.
+func errorResponse(req *http.Request, err errio.PublicStatusError) (*http.Response, error) {
+	body, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		return nil, err
+	}
+
+	resp := cachedResponse(req, body)
+	resp.StatusCode = err.StatusCode
+	resp.Status = fmt.Sprintf("%d %s", err.StatusCode, http.StatusText(err.StatusCode))
+	return resp, nil
+}
@@ -0,0 +1,165 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestOfflineCacheTransport_RoundTrip(t *testing.T) {
+	const secretURL = "https://api.secrethub.io/v1/secrets/blindname/versions/1?encrypted_blob=true"
+
+	networkErr := errors.New("connection refused")
+
+	type call struct {
+		offline bool
+		after   time.Duration
+		status  int
+		err     error
+		// expected
+		fetched bool
+		body    string
+		warning bool
+	}
+
+	cases := map[string]struct {
+		url   string
+		calls []call
+	}{
+		"online": {
+			url: secretURL,
+			calls: []call{
+				{status: 200, fetched: true, body: "fresh"},
+			},
+		},
+		"unavailable": {
+			url: secretURL,
+			calls: []call{
+				{status: 200, fetched: true, body: "fresh"},
+				{err: networkErr, fetched: true, body: "fresh", warning: true},
+			},
+		},
+		"server error": {
+			url: secretURL,
+			calls: []call{
+				{status: 200, fetched: true, body: "fresh"},
+				{status: 502, fetched: true, body: "fresh", warning: true},
+			},
+		},
+		"not found": {
+			url: secretURL,
+			calls: []call{
+				{status: 200, fetched: true, body: "fresh"},
+				{status: 404, fetched: true, body: "error"},
+			},
+		},
+		"offline": {
+			url: secretURL,
+			calls: []call{
+				{status: 200, fetched: true, body: "fresh"},
+				{offline: true, body: "fresh"},
+			},
+		},
+		"offline not cached": {
+			url: secretURL,
+			calls: []call{
+				{offline: true, status: 503},
+			},
+		},
+		"offline expired": {
+			url: secretURL,
+			calls: []call{
+				{status: 200, fetched: true, body: "fresh"},
+				{offline: true, after: 48 * time.Hour, status: 503},
+			},
+		},
+		"not cacheable": {
+			url: "https://api.secrethub.io/v1/me/user",
+			calls: []call{
+				{status: 200, fetched: true, body: "fresh"},
+				{err: networkErr, fetched: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			now := time.Now()
+			for _, c := range tc.calls {
+				fetched := false
+				transport := newOfflineCacheTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					fetched = true
+					if c.err != nil {
+						return nil, c.err
+					}
+					body := "fresh"
+					if c.status != 200 {
+						body = "error"
+					}
+					return &http.Response{
+						StatusCode: c.status,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       ioutil.NopCloser(strings.NewReader(body)),
+					}, nil
+				}), filepath.Join(dir, "cache", "offline"), 24*time.Hour, c.offline)
+				transport.now = func() time.Time {
+					return now.Add(c.after)
+				}
+				var stderr bytes.Buffer
+				transport.stderr = &stderr
+
+				req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+				assert.OK(t, err)
+				req.Header.Set("Authorization", "SH1-x alice:sig")
+
+				resp, err := transport.RoundTrip(req)
+
+				assert.Equal(t, fetched, c.fetched)
+				assert.Equal(t, stderr.Len() > 0, c.warning)
+				if c.err != nil && c.body == "" {
+					assert.Equal(t, err, c.err)
+					continue
+				}
+				assert.OK(t, err)
+				if c.body != "" {
+					body, err := ioutil.ReadAll(resp.Body)
+					assert.OK(t, err)
+					assert.Equal(t, string(body), c.body)
+				} else {
+					assert.Equal(t, resp.StatusCode, c.status)
+				}
+			}
+		})
+	}
+}
+
+func TestIsOfflineCacheable(t *testing.T) {
+	cases := map[string]bool{
+		"https://api.secrethub.io/v1/me/key?key_version=v2":              true,
+		"https://api.secrethub.io/v1/namespaces/alice/repos/repo/keys":   true,
+		"https://api.secrethub.io/v1/secrets/blindname?encrypted_blob=1": true,
+		"https://api.secrethub.io/v1/secrets/blindname/versions/3":       true,
+		"https://api.secrethub.io/v1/me/user":                            false,
+		"https://api.secrethub.io/v1/dirs/blindname":                     false,
+		"https://api.secrethub.io/v1/secrets/blindname/keys":             false,
+	}
+
+	for rawURL, expected := range cases {
+		t.Run(rawURL, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+			assert.OK(t, err)
+
+			assert.Equal(t, isOfflineCacheable(req), expected)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package secrethub
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidOpenPath = errMain.Code("invalid_open_path").ErrorPref("%s is not a valid path to a namespace, repository, directory or secret")
+)
+
+// defaultDashboardURL is the address of the dashboard of the SecretHub API at its default address.
+const defaultDashboardURL = "https://dashboard.secrethub.io"
+
+// The views of a resource in the dashboard that can be opened.
+const (
+	openViewOverview = "overview"
+	openViewAudit    = "audit"
+	openViewACL      = "acl"
+)
+
+// OpenCommand opens the page of a resource in the dashboard.
+type OpenCommand struct {
+	io           ui.IO
+	path         string
+	view         string
+	dashboardURL *url.URL
+	printOnly    bool
+	apiRemote    func() *url.URL
+	openBrowser  func(string) error
+}
+
+// NewOpenCommand creates a new OpenCommand.
+func NewOpenCommand(io ui.IO, apiRemote func() *url.URL) *OpenCommand {
+	return &OpenCommand{
+		io:          io,
+		apiRemote:   apiRemote,
+		openBrowser: openBrowser,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OpenCommand) Register(r command.Registerer) {
+	clause := r.Command("open", "Open the page of a namespace, repository, directory or secret in the web dashboard in your default browser. "+
+		"When the API address is set with --api-remote, the dashboard is expected at the same address with the api subdomain replaced by dashboard.")
+	clause.Arg("path", "The path to the namespace, repository, directory or secret to open.").Required().StringVar(&cmd.path)
+	clause.Flag("view", "The view of the resource to open: overview, audit or acl.").Default(openViewOverview).EnumVar(&cmd.view, openViewOverview, openViewAudit, openViewACL)
+	clause.Flag("dashboard-url", "The address of the dashboard, for when it cannot be derived from the API address.").URLVar(&cmd.dashboardURL)
+	clause.Flag("print", "Only print the address of the page instead of opening it in the browser.").BoolVar(&cmd.printOnly)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run opens the page of the resource in the browser. The address is printed as well,
+// so it can be opened manually when no browser can be started.
+func (cmd *OpenCommand) Run() error {
+	pageURL, err := cmd.pageURL()
+	if err != nil {
+		return err
+	}
+
+	if cmd.printOnly {
+		fmt.Fprintln(cmd.io.Output(), pageURL)
+		return nil
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Opening %s in your browser. If it does not open, go to the address manually.\n", pageURL)
+	return cmd.openBrowser(pageURL)
+}
+
+// pageURL returns the address of the page of the resource in the dashboard.
+func (cmd *OpenCommand) pageURL() (string, error) {
+	path := strings.Trim(cmd.path, "/")
+	err := validateOpenPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	dashboard := cmd.dashboardURL
+	if dashboard == nil {
+		dashboard = dashboardURL(cmd.apiRemote())
+	}
+
+	page := *dashboard
+	page.Path = strings.TrimSuffix(page.Path, "/") + "/" + path
+	page.RawQuery = ""
+	if cmd.view != "" && cmd.view != openViewOverview {
+		page.RawQuery = url.Values{"view": []string{cmd.view}}.Encode()
+	}
+	return page.String(), nil
+}
+
+// dashboardURL returns the address of the dashboard that belongs to the API at the given address.
+// The dashboard of a self-hosted API is expected at the dashboard subdomain when the API is
+// served at the api subdomain, and at the same address otherwise.
+func dashboardURL(apiRemote *url.URL) *url.URL {
+	if apiRemote == nil {
+		u, _ := url.Parse(defaultDashboardURL)
+		return u
+	}
+
+	dashboard := &url.URL{
+		Scheme: apiRemote.Scheme,
+		Host:   apiRemote.Host,
+	}
+	if strings.HasPrefix(dashboard.Host, "api.") {
+		dashboard.Host = "dashboard." + strings.TrimPrefix(dashboard.Host, "api.")
+	}
+	return dashboard
+}
+
+// validateOpenPath checks that the path refers to a namespace, repository, directory or secret.
+func validateOpenPath(path string) error {
+	var err error
+	switch strings.Count(path, "/") {
+	case 0:
+		err = api.ValidateNamespace(path)
+	case 1:
+		err = api.ValidateRepoPath(path)
+	default:
+		err = api.ValidateDirPath(path)
+		if err != nil {
+			err = api.ValidateSecretPath(path)
+		}
+	}
+	if err != nil {
+		return ErrInvalidOpenPath(path)
+	}
+	return nil
+}
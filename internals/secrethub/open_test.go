@@ -0,0 +1,79 @@
+package secrethub
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestOpenCommand_Run(t *testing.T) {
+	selfHosted, err := url.Parse("https://api.secrethub.example.com")
+	assert.OK(t, err)
+	custom, err := url.Parse("https://secrets.example.com/dashboard/")
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		cmd      OpenCommand
+		expected string
+		err      error
+	}{
+		"namespace": {
+			cmd: OpenCommand{
+				path: "company",
+			},
+			expected: "https://dashboard.secrethub.io/company",
+		},
+		"secret audit": {
+			cmd: OpenCommand{
+				path: "company/repo/dir/secret",
+				view: openViewAudit,
+			},
+			expected: "https://dashboard.secrethub.io/company/repo/dir/secret?view=audit",
+		},
+		"self-hosted": {
+			cmd: OpenCommand{
+				path:      "company/repo/",
+				view:      openViewACL,
+				apiRemote: func() *url.URL { return selfHosted },
+			},
+			expected: "https://dashboard.secrethub.example.com/company/repo?view=acl",
+		},
+		"dashboard url flag": {
+			cmd: OpenCommand{
+				path:         "company/repo",
+				view:         openViewOverview,
+				dashboardURL: custom,
+			},
+			expected: "https://secrets.example.com/dashboard/company/repo",
+		},
+		"invalid path": {
+			cmd: OpenCommand{
+				path: "company/re po",
+			},
+			err: ErrInvalidOpenPath("company/re po"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			var opened string
+			tc.cmd.io = io
+			tc.cmd.openBrowser = func(url string) error {
+				opened = url
+				return nil
+			}
+			if tc.cmd.apiRemote == nil {
+				tc.cmd.apiRemote = func() *url.URL { return nil }
+			}
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, opened, tc.expected)
+		})
+	}
+}
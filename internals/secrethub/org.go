@@ -32,8 +32,11 @@ func (cmd *OrgCommand) Register(r command.Registerer) {
 	NewOrgInviteCommand(cmd.io, cmd.newClient).Register(clause)
 	NewOrgPurchaseCommand(cmd.io).Register(clause)
 	NewOrgListUsersCommand(cmd.io, cmd.newClient).Register(clause)
+	NewOrgMembersCommand(cmd.io, cmd.newClient).Register(clause)
 	NewOrgLsCommand(cmd.io, cmd.newClient).Register(clause)
 	NewOrgRevokeCommand(cmd.io, cmd.newClient).Register(clause)
 	NewOrgRmCommand(cmd.io, cmd.newClient).Register(clause)
+	NewOrgAuditCommand(cmd.io, cmd.newClient).Register(clause)
 	NewOrgSetRoleCommand(cmd.io, cmd.newClient).Register(clause)
+	NewOrgUsageCommand(cmd.io, cmd.newClient).Register(clause)
 }
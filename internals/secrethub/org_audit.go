@@ -0,0 +1,220 @@
+package secrethub
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/pager"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// OrgAuditCommand prints the audit events of all repositories of an organization.
+type OrgAuditCommand struct {
+	io                 ui.IO
+	newPaginatedWriter func(io.Writer) (io.WriteCloser, error)
+	name               api.OrgName
+	useTimestamps      bool
+	newClient          newClientFunc
+	terminalWidth      func(int) (int, error)
+	maxResults         int
+	format             string
+	filter             auditFilter
+}
+
+// NewOrgAuditCommand creates a new OrgAuditCommand.
+func NewOrgAuditCommand(io ui.IO, newClient newClientFunc) *OrgAuditCommand {
+	return &OrgAuditCommand{
+		io:                 io,
+		newPaginatedWriter: newPaginatedWriter,
+		newClient:          newClient,
+		terminalWidth: func(fd int) (int, error) {
+			w, _, err := terminal.GetSize(fd)
+			return w, err
+		},
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgAuditCommand) Register(r command.Registerer) {
+	defaultLimit := -1
+	if cmd.io.IsOutputPiped() {
+		defaultLimit = pipedOutputLineLimit
+	}
+
+	clause := r.Command("audit", "Show the audit events of all repositories of an organization, from newest to oldest, with the repository each event belongs to. "+
+		"The repositories are listed when the command runs, so newly created repositories are always included.")
+	clause.Arg("org-name", "The organization name").Required().SetValue(&cmd.name)
+	clause.Flag("output-format", "Specify the format in which to output the log. Options are: table, json and json-lines. The json format writes every event on its own line, json-lines is an alias for it.").HintOptions(formatTable, formatJSON, formatJSONLines).Default(formatTable).StringVar(&cmd.format)
+	clause.Flag("max-results", "Specify the number of entries to list. If maxResults < 0 all entries are displayed. If the output of the command is piped, maxResults defaults to 1000.").Default(strconv.Itoa(defaultLimit)).IntVar(&cmd.maxResults)
+	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerAuditFilterFlags(clause, &cmd.filter)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the merged audit log of the repositories of the organization.
+func (cmd *OrgAuditCommand) Run() error {
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	if cmd.format == formatJSON || cmd.format == formatJSONLines {
+		timeFormatter = NewTimeFormatter(true)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	repos, err := client.Repos().List(cmd.name.Value())
+	if err != nil {
+		return err
+	}
+
+	table := newOrgAuditTable(timeFormatter)
+	sources := make([]*orgAuditSource, len(repos))
+	for i, repo := range repos {
+		repoPath := repo.Path()
+		tree, err := client.Dirs().GetTree(repoPath.GetDirPath().Value(), -1, false)
+		if err != nil {
+			return err
+		}
+		table.trees[repoPath] = tree
+		sources[i] = &orgAuditSource{
+			repo: repoPath,
+			iter: client.Repos().EventIterator(repoPath.Value(), &secrethub.AuditEventIteratorParams{}),
+		}
+	}
+
+	paginatedWriter, err := cmd.newPaginatedWriter(cmd.io.Output())
+	if err != nil {
+		return err
+	}
+	defer paginatedWriter.Close()
+
+	formatter, err := newAuditFormatter(cmd.io, cmd.terminalWidth, cmd.format, paginatedWriter, table.header(), table.columns())
+	if err != nil {
+		return err
+	}
+
+	iter := orgAuditIterator{sources: sources}
+	for lineCount := 0; lineCount != cmd.maxResults; {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if cmd.filter.isBeforeRange(event.Audit) {
+			break
+		}
+		if !cmd.filter.matches(event.Audit) {
+			continue
+		}
+		lineCount++
+
+		row, err := table.row(event)
+		if err != nil {
+			return err
+		}
+
+		err = formatter.Write(row)
+		if err == pager.ErrPagerClosed {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orgAuditEvent is an audit event together with the repository it was logged in.
+type orgAuditEvent struct {
+	api.Audit
+	repo api.RepoPath
+}
+
+// orgAuditSource is the audit log of a single repository, from which the next event can be peeked.
+type orgAuditSource struct {
+	repo api.RepoPath
+	iter secrethub.AuditEventIterator
+	next *api.Audit
+	done bool
+}
+
+// peek returns the next event of the source without consuming it, or nil when the source is exhausted.
+func (s *orgAuditSource) peek() (*api.Audit, error) {
+	if s.next != nil || s.done {
+		return s.next, nil
+	}
+
+	event, err := s.iter.Next()
+	if err == iterator.Done {
+		s.done = true
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	s.next = &event
+	return s.next, nil
+}
+
+// orgAuditIterator merges the audit logs of multiple repositories into a single log.
+// As every log is ordered from newest to oldest, the merged log is ordered the same.
+type orgAuditIterator struct {
+	sources []*orgAuditSource
+}
+
+// Next returns the newest event that has not been returned yet.
+// Events logged at the same time are returned in the order of the sources.
+func (it *orgAuditIterator) Next() (orgAuditEvent, error) {
+	var newest *orgAuditSource
+	for _, source := range it.sources {
+		event, err := source.peek()
+		if err != nil {
+			return orgAuditEvent{}, err
+		}
+		if event == nil {
+			continue
+		}
+		if newest == nil || event.LoggedAt.After(newest.next.LoggedAt) {
+			newest = source
+		}
+	}
+
+	if newest == nil {
+		return orgAuditEvent{}, iterator.Done
+	}
+
+	event := orgAuditEvent{Audit: *newest.next, repo: newest.repo}
+	newest.next = nil
+	return event, nil
+}
+
+// orgAuditTable formats audit events of multiple repositories.
+type orgAuditTable struct {
+	baseAuditTable
+	trees map[api.RepoPath]*api.Tree
+}
+
+func newOrgAuditTable(timeFormatter TimeFormatter) orgAuditTable {
+	return orgAuditTable{
+		baseAuditTable: newBaseAuditTable(timeFormatter, tableColumn{name: "repo", maxWidth: 32}, tableColumn{name: "event subject"}),
+		trees:          make(map[api.RepoPath]*api.Tree),
+	}
+}
+
+func (table orgAuditTable) row(event orgAuditEvent) ([]string, error) {
+	subject, err := getAuditSubject(event.Audit, table.trees[event.repo])
+	if err != nil {
+		return nil, err
+	}
+
+	return table.baseAuditTable.row(event.Audit, event.repo.String(), subject)
+}
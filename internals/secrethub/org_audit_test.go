@@ -0,0 +1,216 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/fakes"
+)
+
+func TestOrgAuditIterator_Next(t *testing.T) {
+	testErr := errors.New("test error")
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(offset time.Duration) api.Audit {
+		return api.Audit{LoggedAt: start.Add(offset)}
+	}
+
+	cases := map[string]struct {
+		sources  []*orgAuditSource
+		expected []orgAuditEvent
+		err      error
+	}{
+		"no sources": {
+			sources:  nil,
+			expected: nil,
+		},
+		"merged newest first": {
+			sources: []*orgAuditSource{
+				{
+					repo: "org/repo1",
+					iter: &fakeclient.AuditEventIterator{Events: []api.Audit{event(3 * time.Minute), event(time.Minute)}},
+				},
+				{
+					repo: "org/repo2",
+					iter: &fakeclient.AuditEventIterator{Events: []api.Audit{event(2 * time.Minute), event(0)}},
+				},
+				{
+					repo: "org/repo3",
+					iter: &fakeclient.AuditEventIterator{Events: []api.Audit{}},
+				},
+			},
+			expected: []orgAuditEvent{
+				{Audit: event(3 * time.Minute), repo: "org/repo1"},
+				{Audit: event(2 * time.Minute), repo: "org/repo2"},
+				{Audit: event(time.Minute), repo: "org/repo1"},
+				{Audit: event(0), repo: "org/repo2"},
+			},
+		},
+		"same time in source order": {
+			sources: []*orgAuditSource{
+				{
+					repo: "org/repo1",
+					iter: &fakeclient.AuditEventIterator{Events: []api.Audit{event(0)}},
+				},
+				{
+					repo: "org/repo2",
+					iter: &fakeclient.AuditEventIterator{Events: []api.Audit{event(0)}},
+				},
+			},
+			expected: []orgAuditEvent{
+				{Audit: event(0), repo: "org/repo1"},
+				{Audit: event(0), repo: "org/repo2"},
+			},
+		},
+		"iterator error": {
+			sources: []*orgAuditSource{
+				{
+					repo: "org/repo1",
+					iter: &fakeclient.AuditEventIterator{Err: testErr},
+				},
+			},
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			iter := orgAuditIterator{sources: tc.sources}
+
+			var actual []orgAuditEvent
+			var err error
+			for {
+				var event orgAuditEvent
+				event, err = iter.Next()
+				if err != nil {
+					break
+				}
+				actual = append(actual, event)
+			}
+			if err == iterator.Done {
+				err = nil
+			}
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestOrgAuditCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	event := func(ip string, loggedAt time.Time) api.Audit {
+		return api.Audit{
+			Action: "create",
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{
+					Username: "developer",
+				},
+			},
+			LoggedAt: loggedAt,
+			Subject: api.AuditSubject{
+				Type: "repo",
+				Repo: &api.Repo{
+					Name: "repo",
+				},
+			},
+			IPAddress: ip,
+		}
+	}
+
+	// Every client returns a new iterator, so that the events can be read by every case.
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			RepoService: &fakeclient.RepoService{
+				ListFunc: func(namespace string) ([]*api.Repo, error) {
+					return []*api.Repo{{Owner: "org", Name: "repo"}}, nil
+				},
+				AuditEventIterator: &fakeclient.AuditEventIterator{
+					Events: []api.Audit{
+						event("127.0.0.1", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+						event("127.0.0.2", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+			DirService: &fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return nil, nil
+				},
+			},
+		}, nil
+	}
+
+	cases := map[string]struct {
+		cmd       OrgAuditCommand
+		newClient newClientFunc
+		out       string
+		err       error
+	}{
+		"json": {
+			cmd: OrgAuditCommand{
+				format:     formatJSON,
+				maxResults: -1,
+			},
+			newClient: newClient,
+			out: "{\"Author\":\"developer\",\"Date\":\"2020-01-02T00:00:00Z\",\"Event\":\"create.repo\",\"EventSubject\":\"repo\",\"IPAddress\":\"127.0.0.1\",\"Repo\":\"org/repo\"}\n" +
+				"{\"Author\":\"developer\",\"Date\":\"2020-01-01T00:00:00Z\",\"Event\":\"create.repo\",\"EventSubject\":\"repo\",\"IPAddress\":\"127.0.0.2\",\"Repo\":\"org/repo\"}\n",
+		},
+		"max results": {
+			cmd: OrgAuditCommand{
+				format:     formatJSON,
+				maxResults: 1,
+			},
+			newClient: newClient,
+			out:       "{\"Author\":\"developer\",\"Date\":\"2020-01-02T00:00:00Z\",\"Event\":\"create.repo\",\"EventSubject\":\"repo\",\"IPAddress\":\"127.0.0.1\",\"Repo\":\"org/repo\"}\n",
+		},
+		"filtered": {
+			cmd: OrgAuditCommand{
+				format:     formatJSON,
+				maxResults: -1,
+				filter: auditFilter{
+					until: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+				},
+			},
+			newClient: newClient,
+			out:       "{\"Author\":\"developer\",\"Date\":\"2020-01-01T00:00:00Z\",\"Event\":\"create.repo\",\"EventSubject\":\"repo\",\"IPAddress\":\"127.0.0.2\",\"Repo\":\"org/repo\"}\n",
+		},
+		"client error": {
+			cmd: OrgAuditCommand{
+				format:     formatJSON,
+				maxResults: -1,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return nil, testErr
+			},
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buffer := bytes.Buffer{}
+			tc.cmd.newPaginatedWriter = func(_ io.Writer) (io.WriteCloser, error) {
+				return &fakes.Pager{Buffer: &buffer}, nil
+			}
+			tc.cmd.io = fakeui.NewIO(t)
+			tc.cmd.name = "org"
+			tc.cmd.newClient = tc.newClient
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, buffer.String(), tc.out)
+		})
+	}
+}
@@ -76,14 +76,14 @@ func (cmd *OrgInitCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Creating organization...\n")
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Creating organization...\n")
 
 	resp, err := client.Orgs().Create(cmd.name.Value(), cmd.description)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Creation complete! The organization %s is now ready to use.\n", resp.Name)
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Creation complete! The organization %s is now ready to use.\n", resp.Name)
 
 	return nil
 }
@@ -52,7 +52,7 @@ func (cmd *OrgInviteCommand) Run() error {
 
 		if !confirmed {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 	}
 
@@ -61,14 +61,14 @@ func (cmd *OrgInviteCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Inviting user...")
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Inviting user...")
 
 	resp, err := client.Orgs().Members().Invite(cmd.orgName.Value(), cmd.username, cmd.role)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Invite complete! The user %s is now %s of the %s organization.\n", resp.User.Username, resp.Role, cmd.orgName)
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Invite complete! The user %s is now %s of the %s organization.\n", resp.User.Username, resp.Role, cmd.orgName)
 
 	return nil
 }
@@ -75,6 +75,7 @@ func TestOrgInviteCommand_Run(t *testing.T) {
 			in:        "n",
 			promptOut: "Are you sure you want to invite dev1 to the company organization? [y/N]: ",
 			out:       "Aborting.\n",
+			err:       ErrAborted,
 		},
 		"new client error": {
 			cmd: OrgInviteCommand{
@@ -32,7 +32,6 @@ func NewOrgLsCommand(io ui.IO, newClient newClientFunc) *OrgLsCommand {
 func (cmd *OrgLsCommand) Register(r command.Registerer) {
 	clause := r.Command("ls", "List all organizations you are a member of.")
 	clause.Alias("list")
-	clause.Flag("quiet", "Only print organization names.").Short('q').BoolVar(&cmd.quiet)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
 
 	command.BindAction(clause, cmd.Run)
@@ -47,6 +46,7 @@ func (cmd *OrgLsCommand) Run() error {
 // beforeRun configures the command using the flag values.
 func (cmd *OrgLsCommand) beforeRun() {
 	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+	cmd.quiet = cmd.quiet || quietOutput
 }
 
 // Run lists all organizations a user is a member of.
@@ -0,0 +1,27 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// OrgMembersCommand handles operations on the members of an organization.
+type OrgMembersCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewOrgMembersCommand creates a new OrgMembersCommand.
+func NewOrgMembersCommand(io ui.IO, newClient newClientFunc) *OrgMembersCommand {
+	return &OrgMembersCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *OrgMembersCommand) Register(r command.Registerer) {
+	clause := r.Command("members", "Manage the members of an organization in bulk.")
+	NewOrgMembersImportCommand(cmd.io, cmd.newClient).Register(clause)
+	NewOrgMembersRevokeReposCommand(cmd.io, cmd.newClient).Register(clause)
+}
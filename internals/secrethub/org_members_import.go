@@ -0,0 +1,243 @@
+package secrethub
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidMembersFile = errMain.Code("invalid_members_file").ErrorPref("invalid members file: %s")
+	ErrMemberImportFailed = errMain.Code("member_import_failed").ErrorPref("%d row(s) could not be imported")
+)
+
+const (
+	orgRoleAdmin  = "admin"
+	orgRoleMember = "member"
+)
+
+// OrgMembersImportCommand invites users to an organization and assigns their roles in bulk.
+type OrgMembersImportCommand struct {
+	orgName   api.OrgName
+	file      string
+	dryRun    bool
+	force     bool
+	io        ui.IO
+	openFile  func(name string) (io.ReadCloser, error)
+	newClient newClientFunc
+}
+
+// NewOrgMembersImportCommand creates a new OrgMembersImportCommand.
+func NewOrgMembersImportCommand(io ui.IO, newClient newClientFunc) *OrgMembersImportCommand {
+	return &OrgMembersImportCommand{
+		io:        io,
+		newClient: newClient,
+		openFile:  openFileForReading,
+	}
+}
+
+// openFileForReading opens the named file for reading.
+func openFileForReading(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgMembersImportCommand) Register(r command.Registerer) {
+	clause := r.Command("import", "Invite users to an organization and assign their roles in bulk from a CSV file. "+
+		"The file must start with a header containing a `username` column and optionally a `role` column (`admin` or `member`, defaults to `member`). "+
+		"Users that already are a member of the organization get the role from the file assigned.")
+	clause.Arg("org-name", "The organization name").Required().SetValue(&cmd.orgName)
+	clause.Arg("csv-file", "The path to the CSV file with the members to import").Required().ExistingFileVar(&cmd.file)
+	clause.Flag("dry-run", "Only print what would be changed, without inviting any users or changing any roles.").BoolVar(&cmd.dryRun)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// memberImportRow is a single row of a members CSV file.
+type memberImportRow struct {
+	line     int
+	username string
+	role     string
+	err      error
+}
+
+// Run imports the members from the CSV file.
+func (cmd *OrgMembersImportCommand) Run() error {
+	rows, err := cmd.readRows()
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	members, err := client.Orgs().Members().List(cmd.orgName.Value())
+	if err != nil {
+		return err
+	}
+
+	currentRoles := make(map[string]string, len(members))
+	for _, member := range members {
+		currentRoles[member.User.Username] = member.Role
+	}
+
+	if !cmd.dryRun && !cmd.force {
+		confirmed, err := ui.AskYesNo(
+			cmd.io,
+			fmt.Sprintf("Are you sure you want to import %s into the %s organization?", pluralize("member", "members", len(rows)), cmd.orgName),
+			ui.DefaultNo,
+		)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return ErrAborted
+		}
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "LINE", "USERNAME", "ROLE", "RESULT")
+
+	failed := 0
+	for _, row := range rows {
+		result, ok := cmd.importRow(client.Orgs().Members(), row, currentRoles)
+		if !ok {
+			failed++
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", row.line, row.username, row.role, result)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return ErrMemberImportFailed(failed)
+	}
+	return nil
+}
+
+// orgMemberService is the part of the organization member service used to import members.
+type orgMemberService interface {
+	Invite(org string, username string, role string) (*api.OrgMember, error)
+	Update(org string, username string, role string) (*api.OrgMember, error)
+}
+
+// importRow invites or updates the member in the given row and returns a description
+// of the result and whether the row was imported successfully.
+func (cmd *OrgMembersImportCommand) importRow(service orgMemberService, row memberImportRow, currentRoles map[string]string) (string, bool) {
+	if row.err != nil {
+		return fmt.Sprintf("error: %s", row.err), false
+	}
+
+	currentRole, isMember := currentRoles[row.username]
+	if isMember && currentRole == row.role {
+		return "unchanged", true
+	}
+
+	action := "invited"
+	if isMember {
+		action = "role updated"
+	}
+
+	if cmd.dryRun {
+		return fmt.Sprintf("would be %s", action), true
+	}
+
+	var err error
+	if isMember {
+		_, err = service.Update(cmd.orgName.Value(), row.username, row.role)
+	} else {
+		_, err = service.Invite(cmd.orgName.Value(), row.username, row.role)
+	}
+	if err != nil {
+		return fmt.Sprintf("error: %s", err), false
+	}
+
+	currentRoles[row.username] = row.role
+	return action, true
+}
+
+// readRows parses the CSV file. Rows that cannot be imported are returned
+// with an error, so that they can be reported without aborting the import.
+func (cmd *OrgMembersImportCommand) readRows() ([]memberImportRow, error) {
+	f, err := cmd.openFile(cmd.file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, ErrInvalidMembersFile("the file is empty")
+	} else if err != nil {
+		return nil, ErrInvalidMembersFile(err)
+	}
+
+	usernameColumn, roleColumn := -1, -1
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "username":
+			usernameColumn = i
+		case "role":
+			roleColumn = i
+		}
+	}
+	if usernameColumn == -1 {
+		return nil, ErrInvalidMembersFile("the header does not contain a username column")
+	}
+
+	var rows []memberImportRow
+	line := 1
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, ErrInvalidMembersFile(err)
+		}
+		line++
+
+		row := memberImportRow{
+			line: line,
+			role: orgRoleMember,
+		}
+		if usernameColumn < len(record) {
+			row.username = strings.TrimSpace(record[usernameColumn])
+		}
+		if roleColumn != -1 && roleColumn < len(record) && strings.TrimSpace(record[roleColumn]) != "" {
+			row.role = strings.ToLower(strings.TrimSpace(record[roleColumn]))
+		}
+
+		if row.username == "" {
+			row.err = fmt.Errorf("missing username")
+		} else if row.role != orgRoleAdmin && row.role != orgRoleMember {
+			row.err = fmt.Errorf("invalid role %q", row.role)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,104 @@
+package secrethub
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestOrgMembersImportCommand_Run(t *testing.T) {
+	members := []*api.OrgMember{
+		{
+			User: &api.User{Username: "dev1"},
+			Role: "member",
+		},
+		{
+			User: &api.User{Username: "dev2"},
+			Role: "member",
+		},
+	}
+
+	cases := map[string]struct {
+		dryRun  bool
+		csv     string
+		invited []string
+		updated []string
+		out     string
+		err     error
+	}{
+		"missing username column": {
+			csv: "name,role\ndev1,admin\n",
+			err: ErrInvalidMembersFile("the header does not contain a username column"),
+		},
+		"dry run": {
+			dryRun: true,
+			csv:    "username,role\ndev1,admin\ndev2,member\ndev3,\n",
+			out: "LINE  USERNAME  ROLE    RESULT\n" +
+				"2     dev1      admin   would be role updated\n" +
+				"3     dev2      member  unchanged\n" +
+				"4     dev3      member  would be invited\n",
+		},
+		"import with invalid rows": {
+			csv:     "username,role\ndev1,admin\ndev3,member\n,admin\ndev4,owner\n",
+			invited: []string{"dev3:member"},
+			updated: []string{"dev1:admin"},
+			out: "LINE  USERNAME  ROLE    RESULT\n" +
+				"2     dev1      admin   role updated\n" +
+				"3     dev3      member  invited\n" +
+				"4               admin   error: missing username\n" +
+				"5     dev4      owner   error: invalid role \"owner\"\n",
+			err: ErrMemberImportFailed(2),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var invited, updated []string
+			fakeIO := fakeui.NewIO(t)
+			cmd := OrgMembersImportCommand{
+				orgName: "company",
+				file:    "members.csv",
+				dryRun:  tc.dryRun,
+				force:   true,
+				io:      fakeIO,
+				openFile: func(name string) (io.ReadCloser, error) {
+					return ioutil.NopCloser(strings.NewReader(tc.csv)), nil
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						OrgService: &fakeclient.OrgService{
+							MembersService: &fakeclient.OrgMemberService{
+								ListFunc: func(org string) ([]*api.OrgMember, error) {
+									return members, nil
+								},
+								InviteFunc: func(org string, username string, role string) (*api.OrgMember, error) {
+									invited = append(invited, username+":"+role)
+									return nil, nil
+								},
+								UpdateFunc: func(org string, username string, role string) (*api.OrgMember, error) {
+									updated = append(updated, username+":"+role)
+									return nil, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, fakeIO.Out.String(), tc.out)
+			assert.Equal(t, invited, tc.invited)
+			assert.Equal(t, updated, tc.updated)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrBulkRevokeFailed = errMain.Code("bulk_revoke_failed").ErrorPref("could not revoke the account from %d repositories")
+)
+
+// OrgMembersRevokeReposCommand revokes an account from all repositories of an organization.
+// Users keep their organization membership, services are deleted.
+type OrgMembersRevokeReposCommand struct {
+	orgName     api.OrgName
+	accountName api.AccountName
+	dryRun      bool
+	force       bool
+	io          ui.IO
+	newClient   newClientFunc
+}
+
+// NewOrgMembersRevokeReposCommand creates a new OrgMembersRevokeReposCommand.
+func NewOrgMembersRevokeReposCommand(io ui.IO, newClient newClientFunc) *OrgMembersRevokeReposCommand {
+	return &OrgMembersRevokeReposCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgMembersRevokeReposCommand) Register(r command.Registerer) {
+	clause := r.Command("revoke-repos", "Revoke an account from all repositories of an organization. "+
+		"Users are not removed from the organization, use `org revoke` to remove a user from the organization altogether. "+
+		"Services are deleted.")
+	clause.Arg("org-name", "The organization name").Required().SetValue(&cmd.orgName)
+	clause.Arg("account-name", "The account name (username or service name) to revoke").Required().SetValue(&cmd.accountName)
+	clause.Flag("dry-run", "Only print the repositories the account would be revoked from.").BoolVar(&cmd.dryRun)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run revokes the account from all repositories of the organization.
+func (cmd *OrgMembersRevokeReposCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	memberOf, err := cmd.listMemberRepos(client)
+	if err != nil {
+		return err
+	}
+
+	if len(memberOf) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "The account %s is not a member of any of %s's repositories.\n", cmd.accountName, cmd.orgName)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "The account %s is a member of the following repositories:\n\n", cmd.accountName)
+	for _, repoPath := range memberOf {
+		fmt.Fprintf(cmd.io.Output(), "\t%s\n", repoPath)
+	}
+	fmt.Fprintln(cmd.io.Output())
+
+	if cmd.dryRun {
+		return nil
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.ConfirmCaseInsensitive(
+			cmd.io,
+			fmt.Sprintf("Please type in the name of the account to confirm and revoke it from %s", pluralize("repository", "repositories", len(memberOf))),
+			cmd.accountName.String(),
+		)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Name does not match. Aborting.")
+			return ErrAborted
+		}
+	}
+
+	var revoked []*api.RevokeRepoResponse
+	failed := 0
+	for _, repoPath := range memberOf {
+		var resp *api.RevokeRepoResponse
+		if cmd.accountName.IsService() {
+			resp, err = client.Services().Delete(cmd.accountName.Value())
+		} else {
+			resp, err = client.Repos().Users().Revoke(repoPath.Value(), cmd.accountName.Value())
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "Could not revoke %s from %s: %s\n", cmd.accountName, repoPath, err)
+			failed++
+			continue
+		}
+		if resp.Status == api.StatusFailed {
+			failed++
+		}
+		revoked = append(revoked, resp)
+	}
+
+	fmt.Fprintln(cmd.io.Output())
+	err = writeOrgRevokeRepoList(cmd.io.Output(), revoked...)
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return ErrBulkRevokeFailed(failed)
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Revoke complete! Make sure you rotate all flagged secrets.\n")
+	return nil
+}
+
+// listMemberRepos returns the repositories of the organization the account is a member of.
+// A service is always a member of exactly one repository.
+func (cmd *OrgMembersRevokeReposCommand) listMemberRepos(client secrethub.ClientInterface) ([]api.RepoPath, error) {
+	if cmd.accountName.IsService() {
+		service, err := client.Services().Get(cmd.accountName.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		if service.Repo == nil || service.Repo.Owner != cmd.orgName.Value() {
+			return nil, nil
+		}
+		return []api.RepoPath{service.Repo.Path()}, nil
+	}
+
+	repos, err := client.Repos().List(cmd.orgName.Namespace().Value())
+	if err != nil {
+		return nil, err
+	}
+
+	var memberOf []api.RepoPath
+	for _, repo := range repos {
+		repoPath := repo.Path()
+		users, err := client.Repos().Users().List(repoPath.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			if user.Username == cmd.accountName.Value() {
+				memberOf = append(memberOf, repoPath)
+				break
+			}
+		}
+	}
+	return memberOf, nil
+}
@@ -0,0 +1,183 @@
+package secrethub
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestOrgMembersRevokeReposCommand_Run(t *testing.T) {
+	repos := []*api.Repo{
+		{Owner: "company", Name: "backend"},
+		{Owner: "company", Name: "frontend"},
+		{Owner: "company", Name: "infra"},
+	}
+	repoUsers := map[string][]*api.User{
+		"company/backend":  {{Username: "dev1"}, {Username: "dev2"}},
+		"company/frontend": {{Username: "dev2"}},
+		"company/infra":    {{Username: "dev1"}},
+	}
+	services := map[string]*api.Service{
+		"s-backend": {ServiceID: "s-backend", Repo: &api.Repo{Owner: "company", Name: "backend"}},
+		"s-other":   {ServiceID: "s-other", Repo: &api.Repo{Owner: "other", Name: "repo"}},
+	}
+
+	cases := map[string]struct {
+		cmd       OrgMembersRevokeReposCommand
+		in        string
+		promptErr error
+		revoked   []string
+		out       string
+		err       error
+	}{
+		"dry run": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "dev1",
+				dryRun:      true,
+			},
+			out: "The account dev1 is a member of the following repositories:\n\n" +
+				"\tcompany/backend\n" +
+				"\tcompany/infra\n\n",
+		},
+		"not a member": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "dev3",
+			},
+			out: "The account dev3 is not a member of any of company's repositories.\n",
+		},
+		"user force": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "dev1",
+				force:       true,
+			},
+			revoked: []string{"company/backend:dev1", "company/infra:dev1"},
+			out: "The account dev1 is a member of the following repositories:\n\n" +
+				"\tcompany/backend\n" +
+				"\tcompany/infra\n\n" +
+				"\n" +
+				"  company/backend  => ok\n" +
+				"  company/infra    => ok\n" +
+				"\n" +
+				"Revoke complete! Make sure you rotate all flagged secrets.\n",
+		},
+		"user confirmed": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "dev2",
+			},
+			in:      "DEV2",
+			revoked: []string{"company/backend:dev2", "company/frontend:dev2"},
+			out: "The account dev2 is a member of the following repositories:\n\n" +
+				"\tcompany/backend\n" +
+				"\tcompany/frontend\n\n" +
+				"\n" +
+				"  company/backend   => ok\n" +
+				"  company/frontend  => ok\n" +
+				"\n" +
+				"Revoke complete! Make sure you rotate all flagged secrets.\n",
+		},
+		"name does not match": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "dev2",
+			},
+			in: "dev1",
+			out: "The account dev2 is a member of the following repositories:\n\n" +
+				"\tcompany/backend\n" +
+				"\tcompany/frontend\n\n" +
+				"Name does not match. Aborting.\n",
+			err: ErrAborted,
+		},
+		"cannot ask": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "dev2",
+			},
+			promptErr: ui.ErrCannotAsk,
+			out: "The account dev2 is a member of the following repositories:\n\n" +
+				"\tcompany/backend\n" +
+				"\tcompany/frontend\n\n",
+			err: ErrCannotDoWithoutForce,
+		},
+		"service": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "s-backend",
+				force:       true,
+			},
+			revoked: []string{"s-backend"},
+			out: "The account s-backend is a member of the following repositories:\n\n" +
+				"\tcompany/backend\n\n" +
+				"\n" +
+				"  company/backend  => ok\n" +
+				"\n" +
+				"Revoke complete! Make sure you rotate all flagged secrets.\n",
+		},
+		"service of other organization": {
+			cmd: OrgMembersRevokeReposCommand{
+				accountName: "s-other",
+				force:       true,
+			},
+			out: "The account s-other is not a member of any of company's repositories.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			var revoked []string
+			fakeIO := fakeui.NewIO(t)
+			fakeIO.PromptIn.Buffer = bytes.NewBufferString(tc.in)
+			fakeIO.PromptErr = tc.promptErr
+			tc.cmd.io = fakeIO
+			tc.cmd.orgName = "company"
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					RepoService: &fakeclient.RepoService{
+						ListFunc: func(namespace string) ([]*api.Repo, error) {
+							return repos, nil
+						},
+						UserService: &fakeclient.RepoUserService{
+							ListFunc: func(path string) ([]*api.User, error) {
+								return repoUsers[path], nil
+							},
+							RevokeFunc: func(path string, username string) (*api.RevokeRepoResponse, error) {
+								revoked = append(revoked, path+":"+username)
+								repoPath := api.RepoPath(path)
+								return &api.RevokeRepoResponse{
+									Namespace: repoPath.GetNamespace(),
+									Name:      repoPath.GetRepo(),
+									Status:    api.StatusOK,
+								}, nil
+							},
+						},
+					},
+					ServiceService: &fakeclient.ServiceService{
+						GetFunc: func(id string) (*api.Service, error) {
+							return services[id], nil
+						},
+						DeleteFunc: func(id string) (*api.RevokeRepoResponse, error) {
+							revoked = append(revoked, id)
+							return &api.RevokeRepoResponse{
+								Namespace: services[id].Repo.Owner,
+								Name:      services[id].Repo.Name,
+								Status:    api.StatusOK,
+							}, nil
+						},
+					},
+				}, nil
+			}
+
+			// Act
+			err := tc.cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, fakeIO.Out.String(), tc.out)
+			assert.Equal(t, revoked, tc.revoked)
+		})
+	}
+}
@@ -95,10 +95,10 @@ func (cmd *OrgRevokeCommand) Run() error {
 
 	if !confirmed {
 		fmt.Fprintln(cmd.io.Output(), "Name does not match. Aborting.")
-		return nil
+		return ErrAborted
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "\nRevoking user...\n")
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "\nRevoking user...\n")
 
 	revoked, err := client.Orgs().Members().Revoke(cmd.orgName.Value(), cmd.username, nil)
 	if err != nil {
@@ -117,14 +117,14 @@ func (cmd *OrgRevokeCommand) Run() error {
 		unaffected := revoked.StatusCounts[api.StatusOK]
 
 		fmt.Fprintf(
-			cmd.io.Output(),
+			statusWriter(cmd.io.Output()),
 			"Revoke complete! Repositories: %d flagged, %d failed, %d OK.\n",
 			flagged,
 			failed,
 			unaffected,
 		)
 	} else {
-		fmt.Fprintln(cmd.io.Output(), "Revoke complete!")
+		fmt.Fprintln(statusWriter(cmd.io.Output()), "Revoke complete!")
 	}
 
 	return nil
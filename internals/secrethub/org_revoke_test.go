@@ -112,6 +112,7 @@ func TestOrgRevokeCommand_Run(t *testing.T) {
 			out: "The user dev1 has no memberships to any of company's repos and can be safely removed.\n" +
 				"\n" +
 				"Name does not match. Aborting.\n",
+			err: ErrAborted,
 		},
 		"new client error": {
 			newClientErr: testErr,
@@ -52,7 +52,7 @@ func (cmd *OrgRmCommand) Run() error {
 
 	if !confirmed {
 		fmt.Fprintln(cmd.io.Output(), "Name does not match. Aborting.")
-		return nil
+		return ErrAborted
 	}
 
 	client, err := cmd.newClient()
@@ -60,14 +60,14 @@ func (cmd *OrgRmCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Deleting organization...")
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Deleting organization...")
 
 	err = client.Orgs().Delete(cmd.name.Value())
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Delete complete! The organization %s has been permanently deleted.\n", cmd.name)
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Delete complete! The organization %s has been permanently deleted.\n", cmd.name)
 
 	return nil
 }
@@ -56,6 +56,7 @@ func TestOrgRmCommand_Run(t *testing.T) {
 			promptIn:  "",
 			promptOut: "[DANGER ZONE] This action cannot be undone. This will permanently delete the organization organization, repositories, and remove all team associations. Please type in the name of the organization to confirm: ",
 			out:       "Name does not match. Aborting.\n",
+			err:       ErrAborted,
 		},
 		"success": {
 			cmd: OrgRmCommand{
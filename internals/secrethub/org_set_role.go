@@ -2,6 +2,9 @@ package secrethub
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -9,11 +12,26 @@ import (
 	"github.com/secrethub/secrethub-go/internals/api"
 )
 
-// OrgSetRoleCommand handles updating the role of an organization member.
+// Errors
+var (
+	ErrInvalidRole         = errMain.Code("invalid_role").ErrorPref("invalid role %q: the role can be either `admin` or `member`")
+	ErrInvalidFilter       = errMain.Code("invalid_filter").ErrorPref("invalid filter %q: %s")
+	ErrMissingRole         = errMain.Code("missing_role").Error("no role given: the role can be either `admin` or `member`")
+	ErrFilterWithUsername  = errMain.Code("filter_with_username").Error("a username cannot be given together with --filter")
+	ErrBulkFlagsNeedFilter = errMain.Code("bulk_flags_need_filter").Error("--from-role and --dry-run can only be used together with --filter")
+	ErrBulkRoleFailed      = errMain.Code("bulk_role_failed").ErrorPref("could not set the role of %d member(s)")
+)
+
+// OrgSetRoleCommand handles updating the role of an organization member,
+// or of all organization members matching a filter.
 type OrgSetRoleCommand struct {
 	orgName   api.OrgName
 	username  string
 	role      string
+	filter    string
+	fromRole  string
+	dryRun    bool
+	force     bool
 	io        ui.IO
 	newClient newClientFunc
 }
@@ -28,29 +46,144 @@ func NewOrgSetRoleCommand(io ui.IO, newClient newClientFunc) *OrgSetRoleCommand
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *OrgSetRoleCommand) Register(r command.Registerer) {
-	clause := r.Command("set-role", "Set a user's organization role.")
+	clause := r.Command("set-role", "Set a user's organization role. Use --filter to set the role of all members whose username matches a pattern at once.")
 	clause.Arg("org-name", "The organization name").Required().SetValue(&cmd.orgName)
-	clause.Arg("username", "The username of the user").Required().StringVar(&cmd.username)
-	clause.Arg("role", "The role to assign to the user. Can be either `admin` or `member`.").Required().StringVar(&cmd.role)
+	clause.Arg("username", "The username of the user. Omit when using --filter.").Required().StringVar(&cmd.username)
+	clause.Arg("role", "The role to assign to the user. Can be either `admin` or `member`.").StringVar(&cmd.role)
+	clause.Flag("filter", "Set the role of all members whose username matches this glob pattern, e.g. `ops-*`.").StringVar(&cmd.filter)
+	clause.Flag("from-role", "Only change the role of members that currently have this role. Can only be used with --filter.").StringVar(&cmd.fromRole)
+	clause.Flag("dry-run", "Only print the members whose role would be changed. Can only be used with --filter.").BoolVar(&cmd.dryRun)
+	registerForceFlag(clause).BoolVar(&cmd.force)
 
 	command.BindAction(clause, cmd.Run)
 }
 
-// Run updates the role of an organization member.
+// Run updates the role of an organization member or of all members matching the filter.
 func (cmd *OrgSetRoleCommand) Run() error {
+	cmd.beforeRun()
+	return cmd.run()
+}
+
+// beforeRun configures the command using the flag values.
+func (cmd *OrgSetRoleCommand) beforeRun() {
+	// When filtering, the username is omitted, so the role is passed in as the second argument.
+	if cmd.filter != "" && cmd.role == "" {
+		cmd.role = cmd.username
+		cmd.username = ""
+	}
+}
+
+func (cmd *OrgSetRoleCommand) run() error {
+	if cmd.filter != "" {
+		if cmd.username != "" {
+			return ErrFilterWithUsername
+		}
+		return cmd.setRoles()
+	}
+
+	if cmd.fromRole != "" || cmd.dryRun {
+		return ErrBulkFlagsNeedFilter
+	}
+	if cmd.role == "" {
+		return ErrMissingRole
+	}
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Setting role...\n")
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Setting role...\n")
 
 	resp, err := client.Orgs().Members().Update(cmd.orgName.Value(), cmd.username, cmd.role)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Set complete! The user %s is %s of the %s organization.\n", resp.User.Username, resp.Role, cmd.orgName)
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Set complete! The user %s is %s of the %s organization.\n", resp.User.Username, resp.Role, cmd.orgName)
+
+	return nil
+}
+
+// setRoles sets the role of all organization members matching the filter.
+func (cmd *OrgSetRoleCommand) setRoles() error {
+	if cmd.role != orgRoleAdmin && cmd.role != orgRoleMember {
+		return ErrInvalidRole(cmd.role)
+	}
+
+	_, err := path.Match(cmd.filter, "")
+	if err != nil {
+		return ErrInvalidFilter(cmd.filter, err)
+	}
 
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	members, err := client.Orgs().Members().List(cmd.orgName.Value())
+	if err != nil {
+		return err
+	}
+	sort.Sort(api.SortOrgMemberByUsername(members))
+
+	var selected []*api.OrgMember
+	for _, member := range members {
+		matched, _ := path.Match(cmd.filter, member.User.Username)
+		if !matched || member.Role == cmd.role {
+			continue
+		}
+		if cmd.fromRole != "" && member.Role != cmd.fromRole {
+			continue
+		}
+		selected = append(selected, member)
+	}
+
+	if len(selected) == 0 {
+		fmt.Fprintln(cmd.io.Output(), "No members to update.")
+		return nil
+	}
+
+	if cmd.dryRun {
+		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", "USER", "CURRENT ROLE", "NEW ROLE")
+		for _, member := range selected {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", member.User.Username, member.Role, cmd.role)
+		}
+		return w.Flush()
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.AskYesNo(
+			cmd.io,
+			fmt.Sprintf("Are you sure you want to make %s %s of the %s organization?", pluralize("member", "members", len(selected)), cmd.role, cmd.orgName),
+			ui.DefaultNo,
+		)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return ErrAborted
+		}
+	}
+
+	failed := 0
+	for _, member := range selected {
+		_, err := client.Orgs().Members().Update(cmd.orgName.Value(), member.User.Username, cmd.role)
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "Could not set the role of %s: %s\n", member.User.Username, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(cmd.io.Output(), "The user %s is now %s of the %s organization.\n", member.User.Username, cmd.role, cmd.orgName)
+	}
+
+	if failed > 0 {
+		return ErrBulkRoleFailed(failed)
+	}
 	return nil
 }
@@ -95,3 +95,106 @@ func TestOrgSetRoleCommand_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestOrgSetRoleCommand_run_Filter(t *testing.T) {
+	members := []*api.OrgMember{
+		{User: &api.User{Username: "ops-alice"}, Role: "member"},
+		{User: &api.User{Username: "ops-bob"}, Role: "admin"},
+		{User: &api.User{Username: "dev-carol"}, Role: "member"},
+	}
+
+	cases := map[string]struct {
+		cmd     OrgSetRoleCommand
+		updated []string
+		out     string
+		err     error
+	}{
+		"invalid role": {
+			cmd: OrgSetRoleCommand{
+				role:   "owner",
+				filter: "*",
+			},
+			err: ErrInvalidRole("owner"),
+		},
+		"dry run": {
+			cmd: OrgSetRoleCommand{
+				orgName: "company",
+				role:    "admin",
+				filter:  "ops-*",
+				dryRun:  true,
+			},
+			out: "USER       CURRENT ROLE  NEW ROLE\n" +
+				"ops-alice  member        admin\n",
+		},
+		"from role": {
+			cmd: OrgSetRoleCommand{
+				orgName:  "company",
+				role:     "admin",
+				filter:   "*",
+				fromRole: "member",
+				force:    true,
+			},
+			updated: []string{"dev-carol", "ops-alice"},
+			out: "The user dev-carol is now admin of the company organization.\n" +
+				"The user ops-alice is now admin of the company organization.\n",
+		},
+		"username with filter": {
+			cmd: OrgSetRoleCommand{
+				orgName:  "company",
+				username: "dev1",
+				role:     "member",
+				filter:   "ops-*",
+			},
+			err: ErrFilterWithUsername,
+		},
+		"no matches": {
+			cmd: OrgSetRoleCommand{
+				orgName: "company",
+				role:    "member",
+				filter:  "qa-*",
+			},
+			out: "No members to update.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated []string
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					OrgService: &fakeclient.OrgService{
+						MembersService: &fakeclient.OrgMemberService{
+							ListFunc: func(org string) ([]*api.OrgMember, error) {
+								return members, nil
+							},
+							UpdateFunc: func(org string, username string, role string) (*api.OrgMember, error) {
+								updated = append(updated, username)
+								return nil, nil
+							},
+						},
+					},
+				}, nil
+			}
+
+			err := tc.cmd.run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, updated, tc.updated)
+		})
+	}
+}
+
+func TestOrgSetRoleCommand_beforeRun(t *testing.T) {
+	cmd := OrgSetRoleCommand{
+		username: "admin",
+		filter:   "ops-*",
+	}
+
+	cmd.beforeRun()
+
+	assert.Equal(t, cmd.username, "")
+	assert.Equal(t, cmd.role, "admin")
+}
@@ -0,0 +1,137 @@
+package secrethub
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// OrgUsageCommand reports the resource usage of an organization.
+type OrgUsageCommand struct {
+	name      api.OrgName
+	format    string
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewOrgUsageCommand creates a new OrgUsageCommand.
+func NewOrgUsageCommand(io ui.IO, newClient newClientFunc) *OrgUsageCommand {
+	return &OrgUsageCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgUsageCommand) Register(r command.Registerer) {
+	clause := r.Command("usage", "Show the number of members, repositories, secrets and service accounts of an organization. "+
+		"Plan limits and API usage are not exposed by the API; see the organization's billing page for those.")
+	clause.Arg("org-name", "The organization name").Required().SetValue(&cmd.name)
+	clause.Flag("output", "Specify the format in which to output the usage. Options are: table and json.").HintOptions(formatTable, formatJSON).Default(formatTable).StringVar(&cmd.format)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// orgUsageOutput is the json format of the usage of an organization.
+type orgUsageOutput struct {
+	Name         string
+	MemberCount  int
+	AdminCount   int
+	RepoCount    int
+	SecretCount  int
+	ServiceCount int
+	Repos        []repoUsageOutput
+}
+
+// repoUsageOutput is the json format of the usage of a single repository.
+type repoUsageOutput struct {
+	Path         string
+	SecretCount  int
+	ServiceCount int
+}
+
+// Run prints the usage of the organization.
+func (cmd *OrgUsageCommand) Run() error {
+	if cmd.format != formatTable && cmd.format != formatJSON {
+		return errNoSuchFormat(cmd.format)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	members, err := client.Orgs().Members().List(cmd.name.Value())
+	if err != nil {
+		return err
+	}
+
+	repos, err := client.Repos().List(cmd.name.Namespace().Value())
+	if err != nil {
+		return err
+	}
+
+	usage := orgUsageOutput{
+		Name:        cmd.name.Value(),
+		MemberCount: len(members),
+		RepoCount:   len(repos),
+		Repos:       make([]repoUsageOutput, len(repos)),
+	}
+
+	for _, member := range members {
+		if member.Role == orgRoleAdmin {
+			usage.AdminCount++
+		}
+	}
+
+	for i, repo := range repos {
+		services, err := client.Services().List(repo.Path().Value())
+		if err != nil {
+			return err
+		}
+
+		usage.Repos[i] = repoUsageOutput{
+			Path:         repo.Path().String(),
+			SecretCount:  repo.SecretCount,
+			ServiceCount: len(services),
+		}
+		usage.SecretCount += repo.SecretCount
+		usage.ServiceCount += len(services)
+	}
+
+	if cmd.format == formatJSON {
+		output, err := cli.PrettyJSON(usage)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.io.Output(), output)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "Members:\t%d (%d admin)\n", usage.MemberCount, usage.AdminCount)
+	fmt.Fprintf(w, "Repositories:\t%d\n", usage.RepoCount)
+	fmt.Fprintf(w, "Secrets:\t%d\n", usage.SecretCount)
+	fmt.Fprintf(w, "Service accounts:\t%d\n", usage.ServiceCount)
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if len(usage.Repos) > 0 {
+		fmt.Fprintln(cmd.io.Output())
+		w = tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", "REPOSITORY", "SECRETS", "SERVICES")
+		for _, repo := range usage.Repos {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", repo.Path, repo.SecretCount, repo.ServiceCount)
+		}
+		return w.Flush()
+	}
+
+	return nil
+}
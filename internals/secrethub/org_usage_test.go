@@ -0,0 +1,79 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestOrgUsageCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		format string
+		out    string
+		err    error
+	}{
+		"table": {
+			format: formatTable,
+			out: "Members:           2 (1 admin)\n" +
+				"Repositories:      2\n" +
+				"Secrets:           7\n" +
+				"Service accounts:  2\n" +
+				"\n" +
+				"REPOSITORY      SECRETS  SERVICES\n" +
+				"company/repo1   3        1\n" +
+				"company/repo22  4        1\n",
+		},
+		"invalid format": {
+			format: "xml",
+			err:    errNoSuchFormat("xml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := OrgUsageCommand{
+				name:   "company",
+				format: tc.format,
+				io:     io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						OrgService: &fakeclient.OrgService{
+							MembersService: &fakeclient.OrgMemberService{
+								ListFunc: func(org string) ([]*api.OrgMember, error) {
+									return []*api.OrgMember{
+										{User: &api.User{Username: "dev1"}, Role: "admin"},
+										{User: &api.User{Username: "dev2"}, Role: "member"},
+									}, nil
+								},
+							},
+						},
+						RepoService: &fakeclient.RepoService{
+							ListFunc: func(namespace string) ([]*api.Repo, error) {
+								return []*api.Repo{
+									{Name: "repo1", Owner: "company", SecretCount: 3},
+									{Name: "repo22", Owner: "company", SecretCount: 4},
+								}, nil
+							},
+						},
+						ServiceService: &fakeclient.ServiceService{
+							ListFunc: func(path string) ([]*api.Service, error) {
+								return []*api.Service{{ServiceID: "s-1"}}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
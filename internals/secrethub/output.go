@@ -0,0 +1,136 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrPagingWithoutOutput = errMain.Code("invalid_paging").ErrorPref("%s can only be used with --output json or yaml")
+	ErrCursorNotFound      = errMain.Code("cursor_not_found").ErrorPref("cannot list after %s: it is not in the listing")
+)
+
+// formatYAML is the value of the --output flag to output YAML.
+const formatYAML = "yaml"
+
+// isMachineReadable returns whether the output format is meant to be read by other programs.
+func isMachineReadable(format string) bool {
+	return format == formatJSON || format == formatJSONLines || format == formatYAML
+}
+
+// registerOutputFlag registers the --output flag, which selects the format to write the output of a command in.
+// The first of the formats is the default.
+func registerOutputFlag(r FlagRegisterer, format *string, formats ...string) {
+	help := fmt.Sprintf("The format to output in. Options are: %s and %s. "+
+		"The json and yaml formats have the same field names, which are stable across versions.",
+		strings.Join(formats[:len(formats)-1], ", "), formats[len(formats)-1])
+	r.Flag("output", help).HintOptions(formats...).Default(formats[0]).StringVar(format)
+}
+
+// writeOutput writes v to w in the json or yaml format. When no format is given, v is written as JSON.
+func writeOutput(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case formatJSON, "":
+		out, err := cli.PrettyJSON(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, out)
+		return nil
+	case formatYAML:
+		out, err := toYAML(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	default:
+		return errNoSuchFormat(format)
+	}
+}
+
+// toYAML returns v in YAML with the same field names and field order as its JSON representation.
+// It is converted from JSON, which is valid YAML, so the output structs only need to define the
+// JSON representation to have stable field names in both formats.
+func toYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects are decoded into yaml.MapSlice to keep the order of their fields.
+	var decoded interface{}
+	switch {
+	case len(data) > 0 && data[0] == '{':
+		var object yaml.MapSlice
+		err = yaml.Unmarshal(data, &object)
+		decoded = object
+	case len(data) > 0 && data[0] == '[':
+		var list []yaml.MapSlice
+		err = yaml.Unmarshal(data, &list)
+		decoded = list
+	default:
+		err = yaml.Unmarshal(data, &decoded)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(decoded)
+}
+
+// yamlFormatter is a list formatter that writes every row as an item of a YAML list,
+// with the field names as keys.
+type yamlFormatter struct {
+	writer io.Writer
+	fields []string
+}
+
+// newYAMLFormatter returns a list formatter that formats the rows as items of a YAML list,
+// with the same field names as the json formatter.
+func newYAMLFormatter(writer io.Writer, fieldNames []string) *yamlFormatter {
+	fields := make([]string, len(fieldNames))
+	for i := range fieldNames {
+		fields[i] = toPascalCase(fieldNames[i])
+	}
+	return &yamlFormatter{
+		writer: writer,
+		fields: fields,
+	}
+}
+
+// Write writes the given row as a list item with the configured field names as keys.
+func (f *yamlFormatter) Write(values []string) error {
+	if len(f.fields) != len(values) {
+		return fmt.Errorf("unexpected number of yaml fields")
+	}
+
+	item := make(yaml.MapSlice, len(values))
+	for i, value := range values {
+		item[i] = yaml.MapItem{Key: f.fields[i], Value: value}
+	}
+
+	out, err := yaml.Marshal([]yaml.MapSlice{item})
+	if err != nil {
+		return err
+	}
+	_, err = f.writer.Write(out)
+	return err
+}
+
+// nopWriteCloser is a writer with a Close method that does nothing.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing.
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,73 @@
+package secrethub
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestWriteOutput(t *testing.T) {
+	type item struct {
+		Name  string
+		Count int
+		Tags  []string
+	}
+
+	cases := map[string]struct {
+		format   string
+		value    interface{}
+		expected string
+		err      error
+	}{
+		"json": {
+			format:   formatJSON,
+			value:    item{Name: "a", Count: 1, Tags: []string{"x"}},
+			expected: "{\n    \"Name\": \"a\",\n    \"Count\": 1,\n    \"Tags\": [\n        \"x\"\n    ]\n}\n",
+		},
+		"yaml object": {
+			format:   formatYAML,
+			value:    item{Name: "a", Count: 1, Tags: []string{"x"}},
+			expected: "Name: a\nCount: 1\nTags:\n- x\n",
+		},
+		"yaml list": {
+			format: formatYAML,
+			value: []item{
+				{Name: "b", Count: 2, Tags: []string{}},
+				{Name: "a", Count: 1},
+			},
+			expected: "- Name: b\n  Count: 2\n  Tags: []\n- Name: a\n  Count: 1\n  Tags: null\n",
+		},
+		"yaml empty list": {
+			format:   formatYAML,
+			value:    []item{},
+			expected: "[]\n",
+		},
+		"unknown format": {
+			format: "xml",
+			value:  item{},
+			err:    errNoSuchFormat("xml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := writeOutput(&buf, tc.format, tc.value)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestYAMLFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := newYAMLFormatter(&buf, []string{"author", "event type"})
+
+	assert.OK(t, formatter.Write([]string{"dev1", "create.secret"}))
+	assert.OK(t, formatter.Write([]string{"dev2", "read.secret"}))
+
+	assert.Equal(t, buf.String(), "- Author: dev1\n  EventType: create.secret\n- Author: dev2\n  EventType: read.secret\n")
+}
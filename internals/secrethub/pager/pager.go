@@ -13,6 +13,10 @@ const (
 	secrethubPagerEnvvar   = "$SECRETHUB_PAGER"
 	pagerEnvvar            = "$PAGER"
 	fallbackPagerLineCount = 100
+	lessEnvvar             = "LESS"
+	// defaultLessOptions makes less exit when the output fits on one screen (F),
+	// pass through colors (R) and not clear the screen when it exits (X).
+	defaultLessOptions = "FRX"
 )
 
 var ErrPagerClosed = errors.New("cannot write to closed terminal pager")
@@ -43,8 +47,18 @@ func New(outputWriter io.Writer) (io.WriteCloser, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewCommand(outputWriter, pagerCommand)
+}
 
-	cmd := exec.Command(pagerCommand)
+// NewCommand runs the given terminal pager command with the given arguments
+// and returns a writer that is piped to the standard input of the pager command.
+// When less is used and $LESS is not set, it is configured to exit when the output
+// fits on one screen, so short output is printed without paging.
+func NewCommand(outputWriter io.Writer, name string, args ...string) (io.WriteCloser, error) {
+	cmd := exec.Command(name, args...)
+	if _, ok := os.LookupEnv(lessEnvvar); !ok {
+		cmd.Env = append(os.Environ(), lessEnvvar+"="+defaultLessOptions)
+	}
 
 	writer, err := cmd.StdinPipe()
 	if err != nil {
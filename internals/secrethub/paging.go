@@ -0,0 +1,131 @@
+package secrethub
+
+import (
+	"io"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/pager"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+
+	"github.com/alecthomas/kingpin"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Errors
+var (
+	ErrInvalidPagerConfig = errMain.Code("invalid_pager_config").ErrorPref("invalid pager configured in %s: %s")
+)
+
+// pagerOff is the value of the pager setting in the configuration file that disables the pager.
+const pagerOff = "off"
+
+var (
+	// noPager is set with the --no-pager flag to never pipe output through a pager.
+	noPager bool
+	// pagerDisabled is set when the pager is disabled in the configuration file.
+	pagerDisabled bool
+	// configuredPager is the command and arguments of the pager configured in the configuration file.
+	configuredPager []string
+)
+
+// RegisterPagerFlag registers the no-pager flag that disables piping long listings through a pager.
+func RegisterPagerFlag(r FlagRegisterer) {
+	r.Flag("no-pager", "Do not pipe the output of ls, tree, acl ls and audit through a pager when it is written to a terminal. "+
+		"The pager is set with $SECRETHUB_PAGER, the pager setting in the "+configFilename+" file in the configuration directory or $PAGER. "+
+		"Set the pager setting to "+pagerOff+" to always disable the pager.").BoolVar(&noPager)
+}
+
+// RegisterPagerConfig loads the pager configured in the configuration directory before a command is run.
+// An invalid pager configuration does not stop the command: a warning is printed and the default pager is used.
+func RegisterPagerConfig(app *kingpin.Application, store CredentialConfig) {
+	app.PreAction(func(*kingpin.ParseContext) error {
+		err := loadPagerConfig(store.ConfigDir())
+		if err != nil {
+			warnConfig(err)
+		}
+		return nil
+	})
+}
+
+// loadPagerConfig sets the pager to the pager configured in the configuration directory.
+func loadPagerConfig(dir configdir.Dir) error {
+	pagerDisabled = false
+	configuredPager = nil
+	config, err := loadConfig(dir)
+	if err != nil {
+		return err
+	}
+	file := configFile(dir)
+
+	switch config.Pager {
+	case "":
+		return nil
+	case pagerOff:
+		pagerDisabled = true
+		return nil
+	}
+
+	args, err := splitCommandLine(config.Pager)
+	if err != nil {
+		return ErrInvalidPagerConfig(file.path, err)
+	}
+	configuredPager = args
+	return nil
+}
+
+// newPaginatedWriter returns a writer that pipes the output written to w through a terminal pager.
+// The output is written to w directly when w is not a terminal or when the pager is disabled.
+// The pager set in $SECRETHUB_PAGER takes precedence over the configured pager, which takes
+// precedence over $PAGER.
+func newPaginatedWriter(w io.Writer) (io.WriteCloser, error) {
+	if noPager || pagerDisabled || !isTerminal(w) {
+		return nopWriteCloser{Writer: w}, nil
+	}
+	if len(configuredPager) > 0 && os.Getenv("SECRETHUB_PAGER") == "" {
+		return pager.NewCommand(w, configuredPager[0], configuredPager[1:]...)
+	}
+	return pager.NewWithFallback(w)
+}
+
+// isTerminal returns whether w writes to a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && terminal.IsTerminal(int(file.Fd()))
+}
+
+// pagedIO is an IO of which the output is piped through a terminal pager.
+type pagedIO struct {
+	ui.IO
+	output io.Writer
+}
+
+// Output returns the writer to the pager.
+func (p pagedIO) Output() io.Writer {
+	return p.output
+}
+
+// withPager runs fn with the output of the IO piped through a terminal pager.
+// The IO is restored when fn returns. Closing the pager before all output is
+// written is not an error, as the user has seen what they wanted to see.
+func withPager(io *ui.IO, newPaginatedWriter func(io.Writer) (io.WriteCloser, error), fn func() error) error {
+	original := *io
+	paginatedWriter, err := newPaginatedWriter(original.Output())
+	if err != nil {
+		return err
+	}
+	*io = pagedIO{IO: original, output: paginatedWriter}
+	defer func() {
+		*io = original
+	}()
+
+	err = fn()
+	closeErr := paginatedWriter.Close()
+	if err == pager.ErrPagerClosed {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,92 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/pager"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+func TestLoadPagerConfig(t *testing.T) {
+	defer func() {
+		pagerDisabled = false
+		configuredPager = nil
+	}()
+
+	cases := map[string]struct {
+		config           cliConfig
+		expectedPager    []string
+		expectedDisabled bool
+	}{
+		"not set": {},
+		"command": {
+			config:        cliConfig{Pager: "less -R"},
+			expectedPager: []string{"less", "-R"},
+		},
+		"off": {
+			config:           cliConfig{Pager: pagerOff},
+			expectedDisabled: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			assert.OK(t, configFile(configdir.New(dir)).write(tc.config))
+
+			err := loadPagerConfig(configdir.New(dir))
+
+			assert.OK(t, err)
+			assert.Equal(t, configuredPager, tc.expectedPager)
+			assert.Equal(t, pagerDisabled, tc.expectedDisabled)
+		})
+	}
+}
+
+func TestWithPager(t *testing.T) {
+	testErr := errors.New("test error")
+
+	cases := map[string]struct {
+		runErr   error
+		expected error
+	}{
+		"success": {},
+		"pager closed": {
+			runErr: pager.ErrPagerClosed,
+		},
+		"error": {
+			runErr:   testErr,
+			expected: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fakeIO := fakeui.NewIO(t)
+			var cmdIO ui.IO = fakeIO
+			var paged bytes.Buffer
+
+			err := withPager(&cmdIO, func(io.Writer) (io.WriteCloser, error) {
+				return nopWriteCloser{Writer: &paged}, nil
+			}, func() error {
+				fmt.Fprint(cmdIO.Output(), "output")
+				return tc.runErr
+			})
+
+			assert.Equal(t, err, tc.expected)
+			assert.Equal(t, paged.String(), "output")
+			assert.Equal(t, fakeIO.Out.String(), "")
+			assert.Equal(t, cmdIO, ui.IO(fakeIO))
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"github.com/alecthomas/kingpin"
+)
+
+const (
+	// pathCompletionCacheFilename is the name of the file in the configuration directory
+	// that caches the completions of paths.
+	pathCompletionCacheFilename = "completion-cache.json"
+	// pathCompletionCacheTTL is the duration for which the completions of a path are cached.
+	pathCompletionCacheTTL = time.Minute
+	// pathCompletionTimeout is the maximum duration of the API calls to complete a path.
+	// When the API does not respond in time, no completions are offered.
+	pathCompletionTimeout = 2 * time.Second
+)
+
+// pathCompletionCache maps the parent paths of completed words to their completions.
+type pathCompletionCache map[string]pathCompletionCacheEntry
+
+// pathCompletionCacheEntry holds the completions of a path and when they were retrieved.
+type pathCompletionCacheEntry struct {
+	Completions []string  `json:"completions"`
+	RetrievedAt time.Time `json:"retrieved_at"`
+}
+
+// pathCompleter completes paths in the shell completion by querying the API for
+// the namespaces, repositories, directories and secrets the path can continue with.
+type pathCompleter struct {
+	newClient       newClientFunc
+	credentialStore CredentialConfig
+	timeout         time.Duration
+	ttl             time.Duration
+	now             func() time.Time
+}
+
+// newPathCompleter creates a new pathCompleter that caches the completions in the configuration directory.
+// The client that is used should not prompt for input, as the completion runs in the background of the shell.
+func newPathCompleter(newClient newClientFunc, credentialStore CredentialConfig) *pathCompleter {
+	return &pathCompleter{
+		newClient:       newClient,
+		credentialStore: credentialStore,
+		timeout:         pathCompletionTimeout,
+		ttl:             pathCompletionCacheTTL,
+		now:             time.Now,
+	}
+}
+
+// Register adds the path completion to the path arguments of the commands of the app.
+// Path arguments of commands that do not exist are skipped.
+func (c *pathCompleter) Register(app *kingpin.Application) {
+	args := []struct {
+		command []string
+		arg     string
+	}{
+		{command: []string{"read"}, arg: "secret-path"},
+		{command: []string{"rm"}, arg: "path"},
+		{command: []string{"ls"}, arg: "path"},
+		{command: []string{"tree"}, arg: "dir-path"},
+		{command: []string{"browse"}, arg: "path"},
+		{command: []string{"pick"}, arg: "root"},
+		{command: []string{"open"}, arg: "path"},
+		{command: []string{"inspect"}, arg: "repo or secret-path"},
+		{command: []string{"audit"}, arg: "repo-path or secret-path"},
+		{command: []string{"acl", "check"}, arg: "dir-path"},
+		{command: []string{"acl", "ls"}, arg: "dir-path"},
+		{command: []string{"acl", "rm"}, arg: "dir-path"},
+		{command: []string{"acl", "set"}, arg: "dir-path"},
+	}
+
+	for _, a := range args {
+		cmd := app.GetCommand(a.command[0])
+		for _, name := range a.command[1:] {
+			if cmd == nil {
+				break
+			}
+			cmd = cmd.GetCommand(name)
+		}
+		if cmd == nil {
+			continue
+		}
+
+		arg := cmd.GetArg(a.arg)
+		if arg != nil {
+			arg.HintAction(c.hintAction)
+		}
+	}
+}
+
+// hintAction returns the completions of the word that is completed, which
+// the completion scripts pass as the last argument.
+func (c *pathCompleter) hintAction() []string {
+	if len(os.Args) == 0 {
+		return nil
+	}
+	return c.complete(os.Args[len(os.Args)-1])
+}
+
+// complete returns the paths the given word can be completed to. Completions are
+// served from the cache when possible. When the API cannot be reached in time or
+// returns an error, no completions are returned.
+func (c *pathCompleter) complete(word string) []string {
+	parent := completionParent(word)
+
+	file, ok := c.cacheFile()
+	if ok {
+		var cache pathCompletionCache
+		err := file.read(&cache)
+		if err == nil {
+			entry, ok := cache[parent]
+			if ok && c.now().Sub(entry.RetrievedAt) < c.ttl {
+				return entry.Completions
+			}
+		}
+	}
+
+	result := make(chan []string, 1)
+	go func() {
+		completions, err := c.list(parent)
+		if err != nil {
+			result <- nil
+			return
+		}
+		result <- completions
+	}()
+
+	select {
+	case completions := <-result:
+		if completions != nil {
+			c.store(parent, completions)
+		}
+		return completions
+	case <-time.After(c.timeout):
+		return nil
+	}
+}
+
+// store caches the completions of the parent path. Expired entries are removed from the cache.
+// Failing to cache the completions does not fail the completion, so errors are ignored.
+func (c *pathCompleter) store(parent string, completions []string) {
+	file, ok := c.cacheFile()
+	if !ok {
+		return
+	}
+
+	var cache pathCompletionCache
+	_ = file.update(&cache, func() {
+		if cache == nil {
+			cache = pathCompletionCache{}
+		}
+		for path, entry := range cache {
+			if c.now().Sub(entry.RetrievedAt) >= c.ttl {
+				delete(cache, path)
+			}
+		}
+		cache[parent] = pathCompletionCacheEntry{
+			Completions: completions,
+			RetrievedAt: c.now(),
+		}
+	})
+}
+
+// cacheFile returns the file in the configuration directory that caches the completions.
+// It returns false when no configuration directory is configured.
+func (c *pathCompleter) cacheFile() (jsonFile, bool) {
+	dir := c.credentialStore.ConfigDir().Path()
+	if dir == "" {
+		return jsonFile{}, false
+	}
+	return jsonFile{path: filepath.Join(dir, pathCompletionCacheFilename)}, true
+}
+
+// list retrieves the paths that directly follow the parent path.
+func (c *pathCompleter) list(parent string) ([]string, error) {
+	client, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+	return listPathEntries(client, parent)
+}
+
+// listPathEntries retrieves the paths that directly follow the parent path: the namespaces
+// when the parent is empty, the repositories of a namespace, or the directories
+// and secrets in a directory. Directories and namespaces end with a slash, so
+// they can be completed further.
+func listPathEntries(client secrethub.ClientInterface, parent string) ([]string, error) {
+	elements := strings.Split(strings.TrimSuffix(parent, "/"), "/")
+
+	entries := []string{}
+	switch {
+	case parent == "":
+		namespaces := map[string]struct{}{}
+
+		orgs, err := client.Orgs().ListMine()
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			namespaces[org.Name] = struct{}{}
+		}
+
+		repos, err := client.Repos().ListMine()
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			namespaces[repo.Owner] = struct{}{}
+		}
+
+		for namespace := range namespaces {
+			entries = append(entries, namespace+"/")
+		}
+	case len(elements) == 1:
+		repos, err := client.Repos().List(elements[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			entries = append(entries, parent+repo.Name+"/")
+		}
+	default:
+		tree, err := client.Dirs().GetTree(strings.TrimSuffix(parent, "/"), 1, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range tree.RootDir.SubDirs {
+			entries = append(entries, parent+dir.Name+"/")
+		}
+		for _, secret := range tree.RootDir.Secrets {
+			entries = append(entries, parent+secret.Name)
+		}
+	}
+
+	sort.Strings(entries)
+	return entries, nil
+}
+
+// completionParent returns the part of the word up to and including the last slash,
+// which is the path whose contents the word is completed with.
+func completionParent(word string) string {
+	i := strings.LastIndex(word, "/")
+	if i < 0 {
+		return ""
+	}
+	return word[:i+1]
+}
@@ -0,0 +1,151 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestCompletionParent(t *testing.T) {
+	cases := map[string]string{
+		"":                     "",
+		"comp":                 "",
+		"company/":             "company/",
+		"company/re":           "company/",
+		"company/repo/dir/sec": "company/repo/dir/",
+	}
+
+	for word, expected := range cases {
+		t.Run(word, func(t *testing.T) {
+			assert.Equal(t, completionParent(word), expected)
+		})
+	}
+}
+
+func TestPathCompleter_complete(t *testing.T) {
+	client := fakeclient.Client{
+		OrgService: &fakeclient.OrgService{
+			ListMineFunc: func() ([]*api.Org, error) {
+				return []*api.Org{{Name: "company"}}, nil
+			},
+		},
+		RepoService: &fakeclient.RepoService{
+			ListMineFunc: func() ([]*api.Repo, error) {
+				return []*api.Repo{{Owner: "dev1", Name: "repo"}, {Owner: "company", Name: "repo"}}, nil
+			},
+			ListFunc: func(namespace string) ([]*api.Repo, error) {
+				assert.Equal(t, namespace, "company")
+				return []*api.Repo{{Owner: "company", Name: "repo"}, {Owner: "company", Name: "app"}}, nil
+			},
+		},
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				assert.Equal(t, path, "company/repo")
+				assert.Equal(t, depth, 1)
+				return &api.Tree{
+					RootDir: &api.Dir{
+						Name:    "repo",
+						SubDirs: []*api.Dir{{Name: "prod"}},
+						Secrets: []*api.Secret{{Name: "api_key"}},
+					},
+				}, nil
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		word     string
+		expected []string
+	}{
+		"namespaces": {
+			word:     "",
+			expected: []string{"company/", "dev1/"},
+		},
+		"partial namespace": {
+			word:     "comp",
+			expected: []string{"company/", "dev1/"},
+		},
+		"repos": {
+			word:     "company/r",
+			expected: []string{"company/app/", "company/repo/"},
+		},
+		"dir contents": {
+			word:     "company/repo/",
+			expected: []string{"company/repo/api_key", "company/repo/prod/"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			completer := newPathCompleter(func() (secrethub.ClientInterface, error) {
+				return client, nil
+			}, &credentialConfig{configDir: ConfigDir{Dir: configdir.New(dir)}})
+
+			actual := completer.complete(tc.word)
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestPathCompleter_complete_Cache(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	calls := 0
+	client := fakeclient.Client{
+		RepoService: &fakeclient.RepoService{
+			ListFunc: func(namespace string) ([]*api.Repo, error) {
+				calls++
+				return []*api.Repo{{Owner: "company", Name: "repo"}}, nil
+			},
+		},
+	}
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	completer := newPathCompleter(func() (secrethub.ClientInterface, error) {
+		return client, nil
+	}, &credentialConfig{configDir: ConfigDir{Dir: configdir.New(dir)}})
+	completer.now = func() time.Time {
+		return now
+	}
+
+	assert.Equal(t, completer.complete("company/"), []string{"company/repo/"})
+	assert.Equal(t, completer.complete("company/re"), []string{"company/repo/"})
+	assert.Equal(t, calls, 1)
+
+	now = now.Add(pathCompletionCacheTTL)
+	assert.Equal(t, completer.complete("company/"), []string{"company/repo/"})
+	assert.Equal(t, calls, 2)
+}
+
+func TestPathCompleter_complete_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+
+	client := fakeclient.Client{
+		RepoService: &fakeclient.RepoService{
+			ListFunc: func(namespace string) ([]*api.Repo, error) {
+				<-done
+				return []*api.Repo{{Owner: "company", Name: "repo"}}, nil
+			},
+		},
+	}
+
+	completer := newPathCompleter(func() (secrethub.ClientInterface, error) {
+		return client, nil
+	}, &credentialConfig{})
+	completer.timeout = time.Millisecond
+
+	actual := completer.complete("company/")
+
+	assert.Equal(t, actual, []string(nil))
+}
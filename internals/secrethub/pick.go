@@ -0,0 +1,354 @@
+package secrethub
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Errors
+var (
+	ErrPickRequiresTerminal = errMain.Code("pick_requires_terminal").Error("the picker can only be used when a terminal is available")
+	ErrNoSecretsToPick      = errMain.Code("no_secrets_to_pick").ErrorPref("there are no secrets in %s to pick from")
+	ErrPickAborted          = errMain.Code("pick_aborted").Error("no secret was picked")
+)
+
+// pickFixedLines is the number of lines the picker uses next to the list of matches.
+const pickFixedLines = 2
+
+// PickCommand picks a secret path with a fuzzy finder.
+type PickCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+	root      string
+	then      string
+}
+
+// NewPickCommand creates a new PickCommand.
+func NewPickCommand(io ui.IO, newClient newClientFunc) *PickCommand {
+	return &PickCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *PickCommand) Register(r command.Registerer) {
+	clause := r.Command("pick", "Pick the path of a secret with a fuzzy finder and print it. "+
+		"Type to filter the paths, use the arrow keys to select a path and press enter to pick it. "+
+		"The finder is drawn on the terminal, so the output can be used in another command, e.g. `secrethub read $(secrethub pick)`.")
+	clause.Arg("root", "The namespace, repository or directory to pick a secret from. Defaults to all secrets you have access to.").StringVar(&cmd.root)
+	clause.Flag("then", "Run a command on the picked secret instead of printing its path: read, rm, inspect or audit.").EnumVar(&cmd.then, "read", "rm", "inspect", "audit")
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run lists the secret paths, lets the user pick one and prints it or passes it to the follow-up command.
+func (cmd *PickCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	root := strings.Trim(cmd.root, "/")
+	paths, err := listSecretPaths(client, root)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		if root == "" {
+			root = "your namespaces"
+		}
+		return ErrNoSecretsToPick(root)
+	}
+
+	path, err := cmd.pick(paths)
+	if err != nil {
+		return err
+	}
+
+	if cmd.then == "" {
+		fmt.Fprintln(cmd.io.Output(), path)
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	followUp := exec.Command(executable, cmd.then, path)
+	followUp.Stdin = cmd.io.Stdin()
+	followUp.Stdout = cmd.io.Stdout()
+	followUp.Stderr = os.Stderr
+	return followUp.Run()
+}
+
+// pick draws the fuzzy finder on the terminal and returns the picked path.
+// The terminal is used directly, so stdin and stdout can be piped.
+func (cmd *PickCommand) pick(paths []string) (string, error) {
+	promptIn, promptOut, err := cmd.io.Prompts()
+	if err != nil {
+		return "", ErrPickRequiresTerminal
+	}
+	tty, ok := promptIn.(*os.File)
+	if !ok || !terminal.IsTerminal(int(tty.Fd())) {
+		return "", ErrPickRequiresTerminal
+	}
+
+	state, err := terminal.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = terminal.Restore(int(tty.Fd()), state)
+	}()
+
+	fmt.Fprint(promptOut, "\x1b[?1049h")
+	defer fmt.Fprint(promptOut, "\x1b[?1049l")
+
+	p := newPicker(paths)
+	keys := bufio.NewReader(tty)
+	for {
+		_, height, err := terminal.GetSize(int(tty.Fd()))
+		if err != nil {
+			height = 24
+		}
+
+		err = p.render(promptOut, height)
+		if err != nil {
+			return "", err
+		}
+
+		key, err := readKey(keys)
+		if err != nil {
+			return "", err
+		}
+
+		done, err := p.handleKey(key)
+		if err != nil {
+			return "", err
+		}
+		if done {
+			path, _ := p.selected()
+			return path, nil
+		}
+	}
+}
+
+// listSecretPaths returns the paths of all secrets in the root, in alphabetical order.
+// The root is a namespace, repository or directory. When it is empty, the secrets
+// of all repositories of the user are listed.
+func listSecretPaths(client secrethub.ClientInterface, root string) ([]string, error) {
+	var dirs []string
+	switch {
+	case strings.Contains(root, "/"):
+		dirs = []string{root}
+	case root == "":
+		repos, err := client.Repos().ListMine()
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			dirs = append(dirs, repo.Owner+"/"+repo.Name)
+		}
+	default:
+		repos, err := client.Repos().List(root)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			dirs = append(dirs, repo.Owner+"/"+repo.Name)
+		}
+	}
+
+	progress := newProgressCounter("Reading secrets", len(dirs))
+	defer progress.Finish()
+
+	var paths []string
+	for _, dir := range dirs {
+		tree, err := client.Dirs().GetTree(dir, -1, false)
+		if err != nil {
+			progress.Fail()
+			return nil, err
+		}
+
+		for secretID := range tree.Secrets {
+			secretPath, err := tree.AbsSecretPath(secretID)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, secretPath.Value())
+		}
+		progress.Done()
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// picker holds the state of the fuzzy finder.
+type picker struct {
+	paths   []string
+	query   string
+	matches []string
+	cursor  int
+}
+
+// newPicker creates a picker that picks from the given paths.
+func newPicker(paths []string) *picker {
+	p := &picker{
+		paths: paths,
+	}
+	p.filter()
+	return p
+}
+
+// filter selects the paths that match the query, ordered from the best to the worst match.
+func (p *picker) filter() {
+	type match struct {
+		path  string
+		score int
+	}
+
+	var matches []match
+	for _, path := range p.paths {
+		score, ok := fuzzyMatch(p.query, path)
+		if ok {
+			matches = append(matches, match{path: path, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	p.matches = make([]string, len(matches))
+	for i, m := range matches {
+		p.matches[i] = m.path
+	}
+	p.cursor = 0
+}
+
+// selected returns the match under the cursor.
+func (p *picker) selected() (string, bool) {
+	if len(p.matches) == 0 {
+		return "", false
+	}
+	return p.matches[p.cursor], true
+}
+
+// handleKey handles a key press and returns whether a path is picked.
+// ErrPickAborted is returned when the picker is closed without picking a path.
+func (p *picker) handleKey(key string) (bool, error) {
+	switch key {
+	case keyEscape, keyInterrupt:
+		return false, ErrPickAborted
+	case keyEnter:
+		_, ok := p.selected()
+		return ok, nil
+	case keyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case keyDown:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case keyBackspace:
+		runes := []rune(p.query)
+		if len(runes) > 0 {
+			p.query = string(runes[:len(runes)-1])
+			p.filter()
+		}
+	case keyLeft, keyRight:
+	default:
+		if len([]rune(key)) == 1 {
+			p.query += key
+			p.filter()
+		}
+	}
+	return false, nil
+}
+
+// render draws the picker on a screen of the given height.
+func (p *picker) render(w io.Writer, height int) error {
+	lines := []string{
+		"Pick a secret: " + p.query + "_",
+		fmt.Sprintf("  %d/%d", len(p.matches), len(p.paths)),
+	}
+
+	listHeight := height - pickFixedLines
+	if listHeight < 1 {
+		listHeight = 1
+	}
+	start := 0
+	if p.cursor >= listHeight {
+		start = p.cursor - listHeight + 1
+	}
+	for i := start; i < len(p.matches) && i < start+listHeight; i++ {
+		marker := "  "
+		if i == p.cursor {
+			marker = "> "
+		}
+		lines = append(lines, marker+p.matches[i])
+	}
+
+	_, err := fmt.Fprint(w, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+	return err
+}
+
+// fuzzyMatch returns whether all characters of the query occur in the candidate in the
+// same order, ignoring case, and a score of how well they match. Characters that follow
+// each other or that start an element of the path, a word or the candidate score higher.
+func fuzzyMatch(query, candidate string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	queryRunes := []rune(strings.ToLower(query))
+	candidateRunes := []rune(strings.ToLower(candidate))
+
+	score := 0
+	q := 0
+	previous := -2
+	for i, c := range candidateRunes {
+		if q == len(queryRunes) {
+			break
+		}
+		if c != queryRunes[q] {
+			continue
+		}
+
+		score++
+		if previous == i-1 {
+			score += 5
+		}
+		if i == 0 || isPathSeparator(candidateRunes[i-1]) {
+			score += 3
+		}
+		previous = i
+		q++
+	}
+	if q < len(queryRunes) {
+		return 0, false
+	}
+
+	// Prefer shorter paths when the matched characters score the same.
+	return score*100 - len(candidateRunes), true
+}
+
+// isPathSeparator returns whether the character separates the elements or words of a path.
+func isPathSeparator(c rune) bool {
+	return c == '/' || c == '_' || c == '-' || c == '.' || unicode.IsSpace(c)
+}
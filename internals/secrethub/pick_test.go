@@ -0,0 +1,78 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	cases := map[string]struct {
+		query     string
+		candidate string
+		matches   bool
+	}{
+		"empty query": {
+			query:     "",
+			candidate: "company/repo/db_password",
+			matches:   true,
+		},
+		"subsequence": {
+			query:     "crdbp",
+			candidate: "company/repo/db_password",
+			matches:   true,
+		},
+		"case insensitive": {
+			query:     "DB",
+			candidate: "company/repo/db_password",
+			matches:   true,
+		},
+		"wrong order": {
+			query:     "passwordrepo",
+			candidate: "company/repo/db_password",
+			matches:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, ok := fuzzyMatch(tc.query, tc.candidate)
+
+			assert.Equal(t, ok, tc.matches)
+		})
+	}
+}
+
+func TestPicker(t *testing.T) {
+	p := newPicker([]string{
+		"company/app/prod/db_password",
+		"company/app/dev/db_password",
+		"company/app/prod/api_key",
+	})
+	assert.Equal(t, len(p.matches), 3)
+
+	for _, key := range []string{"p", "r", "o", "d", "d", "b"} {
+		done, err := p.handleKey(key)
+		assert.OK(t, err)
+		assert.Equal(t, done, false)
+	}
+	assert.Equal(t, p.matches, []string{"company/app/prod/db_password"})
+
+	_, err := p.handleKey(keyBackspace)
+	assert.OK(t, err)
+	_, err = p.handleKey(keyBackspace)
+	assert.OK(t, err)
+	assert.Equal(t, p.query, "prod")
+	assert.Equal(t, p.matches[0], "company/app/prod/api_key")
+
+	_, err = p.handleKey(keyDown)
+	assert.OK(t, err)
+	done, err := p.handleKey(keyEnter)
+	assert.OK(t, err)
+	assert.Equal(t, done, true)
+	selected, _ := p.selected()
+	assert.Equal(t, selected, "company/app/prod/db_password")
+
+	_, err = p.handleKey(keyEscape)
+	assert.Equal(t, err, ErrPickAborted)
+}
@@ -0,0 +1,56 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// manifestHelp describes the format of a manifest for the commands that use one.
+const manifestHelp = "The manifest is a YAML file with any of: dirs, a list of directory paths; " +
+	"secrets, a list of secrets with a path and one of value, a literal value; file, a file to read the value from, relative to the manifest; " +
+	"ref, the path of another secret to copy the value of; or generate, with an optional length and charset, to generate a random value when the secret does not exist; " +
+	"and access, a list of access rules with a path, account and permission. " +
+	"The parent directories of all declared resources are created as well."
+
+// PlanCommand shows the changes that are needed to make the remote state match a manifest.
+type PlanCommand struct {
+	io        ui.IO
+	file      string
+	newClient newClientFunc
+}
+
+// NewPlanCommand creates a new PlanCommand.
+func NewPlanCommand(io ui.IO, newClient newClientFunc) *PlanCommand {
+	return &PlanCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *PlanCommand) Register(r command.Registerer) {
+	clause := r.Command("plan", "Show which directories, secrets and access rules `secrethub apply` would create or update to match a manifest, without changing anything. "+manifestHelp)
+	clause.Arg("manifest-file", "The path to the YAML manifest").Required().ExistingFileVar(&cmd.file)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run shows the plan.
+func (cmd *PlanCommand) Run() error {
+	m, err := readManifest(cmd.file)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := m.plan(client)
+	if err != nil {
+		return err
+	}
+
+	return plan.print(cmd.io.Output())
+}
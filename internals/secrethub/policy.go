@@ -0,0 +1,367 @@
+package secrethub
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	errPolicy           = errio.Namespace("policy")
+	ErrInvalidPolicy    = errPolicy.Code("invalid_policy").ErrorPref("invalid policy: %s")
+	ErrPolicyViolation  = errPolicy.Code("violation").ErrorPref("%s violates the policy of %s: %s")
+	ErrCannotReadPolicy = errPolicy.Code("cannot_read_policy").ErrorPref("cannot read the policy of %s: %s. Ask an admin of %s to run `secrethub policy apply` again to give you access to it")
+)
+
+// orgPolicyRepoName is the name of the repository in an organization that contains its policy.
+// Every member of the organization gets read access to it, so the CLI of every member enforces the policy.
+const orgPolicyRepoName = ".policy"
+
+// orgPolicySecretName is the name of the secret in the policy repository that contains the policy in YAML.
+const orgPolicySecretName = "policy"
+
+// orgPolicyPath returns the path of the secret that contains the policy of the organization.
+func orgPolicyPath(org string) api.SecretPath {
+	return api.SecretPath(org + "/" + orgPolicyRepoName + "/" + orgPolicySecretName)
+}
+
+// PolicyCommand handles the naming and structure policies of organizations.
+type PolicyCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewPolicyCommand creates a new PolicyCommand.
+func NewPolicyCommand(io ui.IO, newClient newClientFunc) *PolicyCommand {
+	return &PolicyCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *PolicyCommand) Register(r command.Registerer) {
+	clause := r.Command("policy", "Manage the naming and structure policies of organizations. "+
+		"The policy of an organization is stored in the "+orgPolicyRepoName+" repository of the organization "+
+		"and is enforced by the CLI of every member on every write and mkdir in the organization.")
+	NewPolicyApplyCommand(cmd.io, cmd.newClient).Register(clause)
+	NewPolicyCheckCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// orgPolicy defines the rules the repositories of an organization must follow.
+// Directories are relative to the root of a repository.
+type orgPolicy struct {
+	// Org is the organization the policy applies to.
+	Org string `yaml:"org" json:"org"`
+	// RequiredDirs are the directories every repository must contain.
+	RequiredDirs []string `yaml:"required_dirs" json:"required_dirs,omitempty"`
+	// SecretNamePatterns are regular expressions of which the name of a secret must match at least one.
+	SecretNamePatterns []string `yaml:"secret_name_patterns" json:"secret_name_patterns,omitempty"`
+	// Environments are the top level directories every repository must contain.
+	// When set, secrets can only be stored in an environment directory.
+	Environments []string `yaml:"environments" json:"environments,omitempty"`
+	// Generate are the rules `secrethub generate` follows for secrets at matching paths.
+	// The first rule that matches a path applies.
+	Generate []generateRule `yaml:"generate" json:"generate,omitempty"`
+
+	// namePatterns are the compiled SecretNamePatterns.
+	namePatterns []*regexp.Regexp
+}
+
+// parsePolicy parses and validates a policy in YAML.
+func parsePolicy(data []byte) (orgPolicy, error) {
+	var policy orgPolicy
+	err := yaml.UnmarshalStrict(data, &policy)
+	if err != nil {
+		return orgPolicy{}, ErrInvalidPolicy(err)
+	}
+
+	err = policy.validate()
+	if err != nil {
+		return orgPolicy{}, err
+	}
+
+	err = policy.compile()
+	if err != nil {
+		return orgPolicy{}, err
+	}
+	return policy, nil
+}
+
+// compile compiles the secret name patterns of the policy, so they are compiled once
+// instead of for every secret that is checked.
+func (p *orgPolicy) compile() error {
+	p.namePatterns = nil
+	for _, pattern := range p.SecretNamePatterns {
+		namePattern, err := regexp.Compile(pattern)
+		if err != nil {
+			return ErrInvalidPolicy(fmt.Sprintf("secret name pattern %q is not a valid regular expression", pattern))
+		}
+		p.namePatterns = append(p.namePatterns, namePattern)
+	}
+	return nil
+}
+
+// validate returns an error when the policy cannot be applied.
+func (p orgPolicy) validate() error {
+	if api.ValidateOrgName(p.Org) != nil {
+		return ErrInvalidPolicy(fmt.Sprintf("%q is not a valid organization name", p.Org))
+	}
+
+	for _, dir := range p.RequiredDirs {
+		if api.ValidateDirPath(p.Org+"/repo/"+dir) != nil {
+			return ErrInvalidPolicy(fmt.Sprintf("required directory %q is not a valid directory path", dir))
+		}
+	}
+
+	for _, env := range p.Environments {
+		if strings.Contains(env, "/") || api.ValidateDirPath(p.Org+"/repo/"+env) != nil {
+			return ErrInvalidPolicy(fmt.Sprintf("environment %q is not a valid directory name", env))
+		}
+	}
+
+	for _, rule := range p.Generate {
+		err := rule.validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSecret returns the reasons the secret at the given path violates the policy.
+// The secret name patterns are only checked when the policy has been compiled.
+func (p orgPolicy) checkSecret(path api.SecretPath) []string {
+	if path.Value() == path.GetRepoPath().GetDirPath().JoinSecret(repoGeneratePolicySecretName).Value() ||
+		path.GetRepo() == orgPolicyRepoName {
+		return nil
+	}
+
+	var res []string
+
+	if len(p.namePatterns) > 0 {
+		matched := false
+		for _, namePattern := range p.namePatterns {
+			if namePattern.MatchString(path.GetSecret()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			res = append(res, fmt.Sprintf("the secret name %s does not match any of the allowed patterns (%s)", path.GetSecret(), strings.Join(p.SecretNamePatterns, ", ")))
+		}
+	}
+
+	segments := strings.Split(path.Value(), "/")
+	if len(p.Environments) > 0 && (len(segments) < 4 || !containsString(p.Environments, segments[2])) {
+		res = append(res, fmt.Sprintf("secrets must be stored in one of the environment directories (%s)", strings.Join(p.Environments, ", ")))
+	}
+
+	return res
+}
+
+// checkDir returns the reasons the directory at the given path violates the policy.
+func (p orgPolicy) checkDir(path api.DirPath) []string {
+	segments := strings.Split(path.Value(), "/")
+	if len(p.Environments) == 0 || len(segments) < 3 || segments[1] == orgPolicyRepoName {
+		return nil
+	}
+
+	topLevel := segments[2]
+	if containsString(p.Environments, topLevel) {
+		return nil
+	}
+	for _, dir := range p.RequiredDirs {
+		if strings.Split(dir, "/")[0] == topLevel {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("top level directories must be an environment (%s) or a required directory", strings.Join(p.Environments, ", "))}
+}
+
+// requiredDirs returns the paths of the directories the repository must contain.
+func (p orgPolicy) requiredDirs(repoPath api.RepoPath) []string {
+	var res []string
+	for _, env := range p.Environments {
+		res = append(res, repoPath.GetDirPath().JoinDir(env).String())
+	}
+	for _, dir := range p.RequiredDirs {
+		res = append(res, repoPath.GetDirPath().JoinDir(dir).String())
+	}
+	return res
+}
+
+// policyViolation is a path in a repository that does not follow the policy.
+type policyViolation struct {
+	Path   string
+	Reason string
+}
+
+// checkRepo returns all violations of the policy in the given repository tree, sorted by path.
+func (p orgPolicy) checkRepo(repoPath api.RepoPath, tree *api.Tree) ([]policyViolation, error) {
+	var res []policyViolation
+
+	dirs := make(map[string]bool, len(tree.Dirs))
+	for dirID := range tree.Dirs {
+		dirPath, err := tree.AbsDirPath(dirID)
+		if err != nil {
+			return nil, err
+		}
+		dirs[dirPath.String()] = true
+
+		for _, reason := range p.checkDir(dirPath) {
+			res = append(res, policyViolation{Path: dirPath.String(), Reason: reason})
+		}
+	}
+
+	for _, dir := range p.requiredDirs(repoPath) {
+		if !dirs[dir] {
+			res = append(res, policyViolation{Path: dir, Reason: "the required directory does not exist"})
+		}
+	}
+
+	for secretID := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secretID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reason := range p.checkSecret(*secretPath) {
+			res = append(res, policyViolation{Path: secretPath.String(), Reason: reason})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Path != res[j].Path {
+			return res[i].Path < res[j].Path
+		}
+		return res[i].Reason < res[j].Reason
+	})
+	return res, nil
+}
+
+// policyStore reads and writes the policies of organizations. The policy of an organization is stored in
+// a secret in the organization, so that it is read by the CLI of every member of the organization.
+type policyStore struct {
+	client secrethub.ClientInterface
+}
+
+// newPolicyStore creates a policyStore that reads and writes policies with the given client.
+func newPolicyStore(client secrethub.ClientInterface) policyStore {
+	return policyStore{
+		client: client,
+	}
+}
+
+// Get returns the policy of the given organization and whether one has been applied.
+func (s policyStore) Get(org string) (orgPolicy, bool, error) {
+	policyPath := orgPolicyPath(org)
+	secret, err := s.client.Secrets().Versions().GetWithData(policyPath.Value())
+	if api.IsErrNotFound(err) {
+		return orgPolicy{}, false, nil
+	} else if err != nil {
+		return orgPolicy{}, false, ErrCannotReadPolicy(org, err, org)
+	}
+
+	policy, err := parsePolicy(secret.Data)
+	if err != nil {
+		return orgPolicy{}, false, err
+	}
+	if policy.Org != org {
+		return orgPolicy{}, false, ErrInvalidPolicy(fmt.Sprintf("%s contains the policy of %s", policyPath, policy.Org))
+	}
+	return policy, true, nil
+}
+
+// Set stores the policy in YAML in the organization it applies to, replacing the previous policy.
+// The policy repository is created when it does not exist yet.
+func (s policyStore) Set(org string, data []byte) error {
+	policyPath := orgPolicyPath(org)
+	repoPath := policyPath.GetRepoPath()
+	_, err := s.client.Repos().Get(repoPath.Value())
+	if api.IsErrNotFound(err) {
+		_, err = s.client.Repos().Create(repoPath.Value())
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Secrets().Write(policyPath.Value(), data)
+	return err
+}
+
+// Share gives the members of the organization that cannot read its policy yet read access to it.
+// It returns the names of the members it was shared with.
+func (s policyStore) Share(org string) ([]string, error) {
+	repoPath := orgPolicyPath(org).GetRepoPath()
+	users, err := s.client.Repos().Users().List(repoPath.Value())
+	if err != nil {
+		return nil, err
+	}
+	isRepoMember := make(map[string]bool, len(users))
+	for _, user := range users {
+		isRepoMember[user.Username] = true
+	}
+
+	members, err := s.client.Orgs().Members().List(org)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	for _, member := range members {
+		if member.User == nil || isRepoMember[member.User.Username] {
+			continue
+		}
+
+		_, err = s.client.Repos().Users().Invite(repoPath.Value(), member.User.Username)
+		if err != nil {
+			return shared, err
+		}
+		_, err = s.client.AccessRules().Set(repoPath.GetDirPath().Value(), api.PermissionRead.String(), member.User.Username)
+		if err != nil {
+			return shared, err
+		}
+		shared = append(shared, member.User.Username)
+	}
+	return shared, nil
+}
+
+// checkSecretPolicy returns an error when the policy of the namespace of the secret forbids writing it.
+func checkSecretPolicy(store policyStore, path api.SecretPath) error {
+	policy, ok, err := store.Get(path.GetNamespace())
+	if err != nil || !ok {
+		return err
+	}
+
+	reasons := policy.checkSecret(path)
+	if len(reasons) > 0 {
+		return ErrPolicyViolation(path, policy.Org, strings.Join(reasons, "; "))
+	}
+	return nil
+}
+
+// checkDirPolicy returns an error when the policy of the namespace of the directory forbids creating it.
+func checkDirPolicy(store policyStore, path api.DirPath) error {
+	policy, ok, err := store.Get(path.GetNamespace())
+	if err != nil || !ok {
+		return err
+	}
+
+	reasons := policy.checkDir(path)
+	if len(reasons) > 0 {
+		return ErrPolicyViolation(path, policy.Org, strings.Join(reasons, "; "))
+	}
+	return nil
+}
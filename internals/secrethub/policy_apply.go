@@ -0,0 +1,76 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// PolicyApplyCommand applies an organization policy.
+type PolicyApplyCommand struct {
+	io        ui.IO
+	file      string
+	newClient newClientFunc
+}
+
+// NewPolicyApplyCommand creates a new PolicyApplyCommand.
+func NewPolicyApplyCommand(io ui.IO, newClient newClientFunc) *PolicyApplyCommand {
+	return &PolicyApplyCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *PolicyApplyCommand) Register(r command.Registerer) {
+	clause := r.Command("apply", "Apply the policy of an organization from a YAML file. "+
+		"The file sets the org it applies to and any of: required_dirs, the directories every repository must contain; "+
+		"secret_name_patterns, regular expressions of which every secret name must match at least one; "+
+		"environments, the top level directories every repository must contain and in which all secrets must be stored; "+
+		"and generate, the rules `secrethub generate` follows for the secrets at paths that match a pattern. "+
+		"Applying a policy replaces the previous policy of the organization. "+
+		"The policy is stored in the "+orgPolicyRepoName+" repository of the organization and every member is given read access to it. "+
+		"Apply the policy again after adding members, to give them access too.")
+	clause.Arg("policy-file", "The path to the YAML file containing the policy").Required().ExistingFileVar(&cmd.file)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run applies the policy.
+func (cmd *PolicyApplyCommand) Run() error {
+	data, err := ioutil.ReadFile(cmd.file)
+	if err != nil {
+		return ErrReadFile(cmd.file, err)
+	}
+
+	policy, err := parsePolicy(data)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	store := newPolicyStore(client)
+	err = store.Set(policy.Org, data)
+	if err != nil {
+		return err
+	}
+
+	shared, err := store.Share(policy.Org)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Applied the policy of %s. Writes and new directories in %s are now checked against it.\n", policy.Org, policy.Org)
+	if len(shared) > 0 {
+		fmt.Fprintf(cmd.io.Output(), "Gave %s read access to the policy.\n", strings.Join(shared, ", "))
+	}
+	fmt.Fprintf(cmd.io.Output(), "Run `secrethub policy check %s` to find existing violations.\n", policy.Org)
+	return nil
+}
@@ -0,0 +1,100 @@
+package secrethub
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrNoPolicy          = errPolicy.Code("no_policy").ErrorPref("no policy has been applied to %s: use `secrethub policy apply` first")
+	ErrPolicyCheckFailed = errPolicy.Code("check_failed").ErrorPref("found %d policy violation(s)")
+)
+
+// PolicyCheckCommand reports the violations of the policy of an organization in its existing repositories.
+type PolicyCheckCommand struct {
+	io        ui.IO
+	name      api.OrgName
+	newClient newClientFunc
+}
+
+// NewPolicyCheckCommand creates a new PolicyCheckCommand.
+func NewPolicyCheckCommand(io ui.IO, newClient newClientFunc) *PolicyCheckCommand {
+	return &PolicyCheckCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *PolicyCheckCommand) Register(r command.Registerer) {
+	clause := r.Command("check", "Check all repositories of an organization against its applied policy and report the violations. "+
+		"Exits with an error when a violation is found.")
+	clause.Arg("org-name", "The organization name").Required().SetValue(&cmd.name)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run reports the policy violations.
+func (cmd *PolicyCheckCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	policy, ok, err := newPolicyStore(client).Get(cmd.name.Value())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoPolicy(cmd.name)
+	}
+
+	repos, err := client.Repos().List(cmd.name.Value())
+	if err != nil {
+		return err
+	}
+
+	checked := 0
+	var violations []policyViolation
+	for _, repo := range repos {
+		if repo.Name == orgPolicyRepoName {
+			continue
+		}
+		checked++
+
+		repoPath := repo.Path()
+		tree, err := client.Dirs().GetTree(repoPath.GetDirPath().Value(), -1, false)
+		if err != nil {
+			return err
+		}
+
+		repoViolations, err := policy.checkRepo(repoPath, tree)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, repoViolations...)
+	}
+
+	if len(violations) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "All %d repositories of %s follow the policy.\n", checked, cmd.name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\n", "PATH", "VIOLATION")
+	for _, violation := range violations {
+		fmt.Fprintf(w, "%s\t%s\n", violation.Path, violation.Reason)
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	return ErrPolicyCheckFailed(len(violations))
+}
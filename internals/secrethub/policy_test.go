@@ -0,0 +1,350 @@
+package secrethub
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestParsePolicy(t *testing.T) {
+	cases := map[string]struct {
+		in       string
+		expected orgPolicy
+		err      error
+	}{
+		"full policy": {
+			in: "org: acme\n" +
+				"required_dirs:\n" +
+				"  - shared/certs\n" +
+				"secret_name_patterns:\n" +
+				"  - '^[a-z_]+$'\n" +
+				"environments:\n" +
+				"  - dev\n" +
+				"  - prod\n",
+			expected: orgPolicy{
+				Org:                "acme",
+				RequiredDirs:       []string{"shared/certs"},
+				SecretNamePatterns: []string{"^[a-z_]+$"},
+				Environments:       []string{"dev", "prod"},
+				namePatterns:       []*regexp.Regexp{regexp.MustCompile("^[a-z_]+$")},
+			},
+		},
+		"invalid org": {
+			in:  "org: a/b\n",
+			err: ErrInvalidPolicy(`"a/b" is not a valid organization name`),
+		},
+		"invalid pattern": {
+			in:  "org: acme\nsecret_name_patterns: ['[a-z']\n",
+			err: ErrInvalidPolicy(`secret name pattern "[a-z" is not a valid regular expression`),
+		},
+		"nested environment": {
+			in:  "org: acme\nenvironments: [prod/eu]\n",
+			err: ErrInvalidPolicy(`environment "prod/eu" is not a valid directory name`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parsePolicy([]byte(tc.in))
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestParsePolicy_UnknownField(t *testing.T) {
+	_, err := parsePolicy([]byte("org: acme\nenvironment: [prod]\n"))
+
+	if err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
+
+func TestOrgPolicy_checkRepo(t *testing.T) {
+	policy := orgPolicy{
+		Org:                "namespace",
+		RequiredDirs:       []string{"shared"},
+		SecretNamePatterns: []string{"^[a-z_]+$"},
+		Environments:       []string{"dev", "prod"},
+	}
+	assert.OK(t, policy.compile())
+
+	rootDirID := uuid.New()
+	devDirID := uuid.New()
+	otherDirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		RootDir: &api.Dir{
+			DirID: rootDirID,
+			Name:  "repo",
+		},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {
+				DirID: rootDirID,
+				Name:  "repo",
+			},
+			devDirID: {
+				DirID:    devDirID,
+				Name:     "dev",
+				ParentID: &rootDirID,
+			},
+			otherDirID: {
+				DirID:    otherDirID,
+				Name:     "other",
+				ParentID: &rootDirID,
+			},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{},
+	}
+	addSecret := func(dirID uuid.UUID, name string) {
+		secretID := uuid.New()
+		tree.Secrets[secretID] = &api.Secret{SecretID: secretID, DirID: dirID, Name: name}
+	}
+	addSecret(devDirID, "db_password")
+	addSecret(devDirID, "DB-USER")
+	addSecret(rootDirID, "token")
+
+	actual, err := policy.checkRepo("namespace/repo", tree)
+
+	assert.OK(t, err)
+	assert.Equal(t, actual, []policyViolation{
+		{Path: "namespace/repo/dev/DB-USER", Reason: "the secret name DB-USER does not match any of the allowed patterns (^[a-z_]+$)"},
+		{Path: "namespace/repo/other", Reason: "top level directories must be an environment (dev, prod) or a required directory"},
+		{Path: "namespace/repo/prod", Reason: "the required directory does not exist"},
+		{Path: "namespace/repo/shared", Reason: "the required directory does not exist"},
+		{Path: "namespace/repo/token", Reason: "secrets must be stored in one of the environment directories (dev, prod)"},
+	})
+}
+
+// fakePolicyVersionService returns a SecretVersionService that serves the given policy in YAML
+// as the policy of every organization. An empty policy means no policy has been applied.
+func fakePolicyVersionService(policy string) *fakeclient.SecretVersionService {
+	return &fakeclient.SecretVersionService{
+		GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+			if policy == "" || !strings.HasSuffix(path, "/"+orgPolicyRepoName+"/"+orgPolicySecretName) {
+				return nil, api.ErrSecretNotFound
+			}
+			return &api.SecretVersion{Data: []byte(policy)}, nil
+		},
+	}
+}
+
+func TestPolicyStore_Get(t *testing.T) {
+	cases := map[string]struct {
+		policy   string
+		getErr   error
+		expected orgPolicy
+		ok       bool
+		err      error
+	}{
+		"no policy": {},
+		"policy": {
+			policy: "org: acme\nenvironments: [prod]\n",
+			expected: orgPolicy{
+				Org:          "acme",
+				Environments: []string{"prod"},
+			},
+			ok: true,
+		},
+		"policy of other org": {
+			policy: "org: other\n",
+			err:    ErrInvalidPolicy("acme/.policy/policy contains the policy of other"),
+		},
+		"invalid policy": {
+			policy: "org: acme\nsecret_name_patterns: ['[a-z']\n",
+			err:    ErrInvalidPolicy(`secret name pattern "[a-z" is not a valid regular expression`),
+		},
+		"no access": {
+			getErr: api.ErrForbidden,
+			err:    ErrCannotReadPolicy("acme", api.ErrForbidden, "acme"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			versions := fakePolicyVersionService(tc.policy)
+			if tc.getErr != nil {
+				versions.GetWithDataFunc = func(path string) (*api.SecretVersion, error) {
+					return nil, tc.getErr
+				}
+			}
+			store := newPolicyStore(fakeclient.Client{
+				SecretService: &fakeclient.SecretService{
+					VersionService: versions,
+				},
+			})
+
+			actual, ok, err := store.Get("acme")
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, ok, tc.ok)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestPolicyApplyCommand_Run(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+	file := filepath.Join(dir, "policy.yml")
+	policy := "org: acme\nenvironments: [prod]\n"
+	assert.OK(t, ioutil.WriteFile(file, []byte(policy), 0600))
+
+	var created, written string
+	var invited, readers []string
+	io := fakeui.NewIO(t)
+	cmd := PolicyApplyCommand{
+		io:   io,
+		file: file,
+		newClient: func() (secrethub.ClientInterface, error) {
+			return fakeclient.Client{
+				RepoService: &fakeclient.RepoService{
+					GetFunc: func(path string) (*api.Repo, error) {
+						return nil, api.ErrRepoNotFound(path)
+					},
+					CreateFunc: func(path string) (*api.Repo, error) {
+						created = path
+						return &api.Repo{Owner: "acme", Name: orgPolicyRepoName}, nil
+					},
+					UserService: &fakeclient.RepoUserService{
+						ListFunc: func(path string) ([]*api.User, error) {
+							return []*api.User{{Username: "admin"}}, nil
+						},
+						InviteFunc: func(path string, username string) (*api.RepoMember, error) {
+							invited = append(invited, username)
+							return &api.RepoMember{}, nil
+						},
+					},
+				},
+				OrgService: &fakeclient.OrgService{
+					MembersService: &fakeclient.OrgMemberService{
+						ListFunc: func(org string) ([]*api.OrgMember, error) {
+							return []*api.OrgMember{
+								{User: &api.User{Username: "admin"}},
+								{User: &api.User{Username: "dev1"}},
+								{User: &api.User{Username: "dev2"}},
+							}, nil
+						},
+					},
+				},
+				AccessRuleService: &fakeclient.AccessRuleService{
+					SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+						assert.Equal(t, path, "acme/"+orgPolicyRepoName)
+						assert.Equal(t, permission, "read")
+						readers = append(readers, accountName)
+						return &api.AccessRule{}, nil
+					},
+				},
+				SecretService: &fakeclient.SecretService{
+					WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+						assert.Equal(t, path, "acme/"+orgPolicyRepoName+"/"+orgPolicySecretName)
+						written = string(data)
+						return &api.SecretVersion{Version: 1}, nil
+					},
+				},
+			}, nil
+		},
+	}
+
+	err := cmd.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, created, "acme/"+orgPolicyRepoName)
+	assert.Equal(t, written, policy)
+	assert.Equal(t, invited, []string{"dev1", "dev2"})
+	assert.Equal(t, readers, []string{"dev1", "dev2"})
+	assert.Equal(t, io.Out.String(), "Applied the policy of acme. Writes and new directories in acme are now checked against it.\n"+
+		"Gave dev1, dev2 read access to the policy.\n"+
+		"Run `secrethub policy check acme` to find existing violations.\n")
+}
+
+func TestPolicyCheckCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	rootDirID := uuid.New()
+	newClient := func(policy string, dirs ...string) newClientFunc {
+		return func() (secrethub.ClientInterface, error) {
+			return fakeclient.Client{
+				SecretService: &fakeclient.SecretService{
+					VersionService: fakePolicyVersionService(policy),
+				},
+				RepoService: &fakeclient.RepoService{
+					ListFunc: func(namespace string) ([]*api.Repo, error) {
+						return []*api.Repo{{Owner: "acme", Name: "repo"}, {Owner: "acme", Name: orgPolicyRepoName}}, nil
+					},
+				},
+				DirService: &fakeclient.DirService{
+					GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+						tree := &api.Tree{
+							ParentPath: "acme",
+							RootDir:    &api.Dir{DirID: rootDirID, Name: "repo"},
+							Dirs: map[uuid.UUID]*api.Dir{
+								rootDirID: {DirID: rootDirID, Name: "repo"},
+							},
+						}
+						for _, dir := range dirs {
+							dirID := uuid.New()
+							tree.Dirs[dirID] = &api.Dir{DirID: dirID, Name: dir, ParentID: &rootDirID}
+						}
+						return tree, nil
+					},
+				},
+			}, nil
+		}
+	}
+
+	cases := map[string]struct {
+		newClient newClientFunc
+		out       string
+		err       error
+	}{
+		"no violations": {
+			newClient: newClient("org: acme\nenvironments: [prod]\n", "prod"),
+			out:       "All 1 repositories of acme follow the policy.\n",
+		},
+		"violations": {
+			newClient: newClient("org: acme\nenvironments: [prod]\n"),
+			out: "PATH            VIOLATION\n" +
+				"acme/repo/prod  the required directory does not exist\n",
+			err: ErrPolicyCheckFailed(1),
+		},
+		"no policy": {
+			newClient: newClient(""),
+			err:       ErrNoPolicy("acme"),
+		},
+		"client error": {
+			newClient: func() (secrethub.ClientInterface, error) {
+				return nil, testErr
+			},
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := PolicyCheckCommand{
+				io:        io,
+				name:      "acme",
+				newClient: tc.newClient,
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
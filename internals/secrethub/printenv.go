@@ -10,10 +10,9 @@ import (
 
 // PrintEnvCommand prints out debug statements about all environment variables.
 type PrintEnvCommand struct {
-	app     *cli.App
-	io      ui.IO
-	osEnv   func() []string
-	verbose bool
+	app   *cli.App
+	io    ui.IO
+	osEnv func() []string
 }
 
 // NewPrintEnvCommand creates a new PrintEnvCommand.
@@ -27,7 +26,7 @@ func NewPrintEnvCommand(app *cli.App, io ui.IO) *PrintEnvCommand {
 
 // Run prints out debug statements about all environment variables.
 func (cmd *PrintEnvCommand) Run() error {
-	err := cmd.app.PrintEnv(cmd.io.Output(), cmd.verbose, cmd.osEnv)
+	err := cmd.app.PrintEnv(cmd.io.Output(), verbosity > 0, cmd.osEnv)
 	if err != nil {
 		return err
 	}
@@ -36,8 +35,7 @@ func (cmd *PrintEnvCommand) Run() error {
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *PrintEnvCommand) Register(r command.Registerer) {
-	clause := r.Command("printenv", "Print environment variables.")
-	clause.Flag("verbose", "Show all possible environment variables.").Short('v').BoolVar(&cmd.verbose)
+	clause := r.Command("printenv", "Print environment variables. Use the global --verbose flag to show all possible environment variables.")
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -1,9 +1,14 @@
 package secrethub
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/clip"
 	"github.com/secrethub/secrethub-cli/internals/cli/filemode"
@@ -12,10 +17,43 @@ import (
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 
 	"github.com/docker/go-units"
 )
 
+// Errors
+var (
+	ErrInvalidReadFormat   = errMain.Code("invalid_read_format").ErrorPref("invalid format %q: must be one of raw, json or ansible-vault")
+	ErrNoVaultPasswordFile = errMain.Code("no_vault_password_file").Error("the ansible-vault format requires a vault password: use --vault-password-file")
+	ErrEmptyVaultPassword  = errMain.Code("empty_vault_password").ErrorPref("the vault password file %s is empty")
+)
+
+// The output formats of the read command.
+const (
+	readFormatRaw          = "raw"
+	readFormatJSON         = "json"
+	readFormatAnsibleVault = "ansible-vault"
+)
+
+// The encodings of the value in the JSON output of the read command.
+const (
+	readEncodingUTF8   = "utf-8"
+	readEncodingBase64 = "base64"
+)
+
+// readJSONOutput is the JSON output of the read command. It is written on a single line,
+// so it can be read by configuration management tools that shell out to the CLI.
+type readJSONOutput struct {
+	Path      string    `json:"path"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Status    string    `json:"status"`
+	// Value is the value of the secret. Values that are not valid UTF-8 are base64 encoded.
+	Value    string `json:"value"`
+	Encoding string `json:"encoding"`
+}
+
 // ReadCommand is a command to read a secret.
 type ReadCommand struct {
 	io                  ui.IO
@@ -26,6 +64,8 @@ type ReadCommand struct {
 	outFile             string
 	fileMode            filemode.FileMode
 	noNewLine           bool
+	format              string
+	vaultPasswordFile   string
 	newClient           newClientFunc
 }
 
@@ -34,6 +74,7 @@ func NewReadCommand(io ui.IO, newClient newClientFunc) *ReadCommand {
 	return &ReadCommand{
 		clipper:             clip.NewClipboard(),
 		clearClipboardAfter: defaultClearClipboardAfter,
+		format:              readFormatRaw,
 		io:                  io,
 		newClient:           newClient,
 	}
@@ -46,31 +87,50 @@ func (cmd *ReadCommand) Register(r command.Registerer) {
 	clause.Flag(
 		"clip",
 		fmt.Sprintf(
-			"Copy the secret value to the clipboard. The clipboard is automatically cleared after %s.",
+			"Copy the secret value to the clipboard. The clipboard is automatically cleared after %s. "+
+				"When no clipboard utility is available or the CLI runs in an SSH session, the value is copied with an OSC 52 escape sequence "+
+				"to the clipboard of the terminal, if the terminal supports it.",
 			units.HumanDuration(cmd.clearClipboardAfter),
 		),
 	).Short('c').BoolVar(&cmd.useClipboard)
 	clause.Flag("out-file", "Write the secret value to this file.").Short('o').StringVar(&cmd.outFile)
 	clause.Flag("file-mode", "Set filemode for the output file. Defaults to 0600 (read and write for current user) and is ignored without the --out-file flag.").Default("0600").SetValue(&cmd.fileMode)
 	clause.Flag("no-newline", "Do not print a new line after the secret.").Short('n').BoolVar(&cmd.noNewLine)
+	clause.Flag("format", "The format to output the secret in. Options are: raw (the value of the secret), "+
+		"json (a single line object with the path, version, created_at, status, value and encoding of the secret, where the encoding is utf-8, or base64 for values that are not valid UTF-8) "+
+		"and ansible-vault (the value encrypted with the password in --vault-password-file, as `ansible-vault encrypt` does).").
+		HintOptions(readFormatRaw, readFormatJSON, readFormatAnsibleVault).Default(readFormatRaw).StringVar(&cmd.format)
+	clause.Flag("vault-password-file", "The file with the vault password to encrypt the secret with in the ansible-vault format.").ExistingFileVar(&cmd.vaultPasswordFile)
 
 	command.BindAction(clause, cmd.Run)
 }
 
 // Run handles the command with the options as specified in the command.
 func (cmd *ReadCommand) Run() error {
+	if cmd.format != readFormatRaw && cmd.format != readFormatJSON && cmd.format != readFormatAnsibleVault {
+		return ErrInvalidReadFormat(cmd.format)
+	}
+	if cmd.format == readFormatAnsibleVault && cmd.vaultPasswordFile == "" {
+		return ErrNoVaultPasswordFile
+	}
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
 	}
 
-	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	secret, err := readSecretOrLink(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	secretData, err := cmd.formatSecret(secret)
 	if err != nil {
 		return err
 	}
 
 	if cmd.useClipboard {
-		err = WriteClipboardAutoClear(secret.Data, cmd.clearClipboardAfter, cmd.clipper)
+		err = WriteClipboardAutoClear(secretData, cmd.clearClipboardAfter, cmd.clipper)
 		if err != nil {
 			return err
 		}
@@ -83,7 +143,6 @@ func (cmd *ReadCommand) Run() error {
 		)
 	}
 
-	secretData := secret.Data
 	if !cmd.noNewLine {
 		secretData = posix.AddNewLine(secretData)
 	}
@@ -101,3 +160,62 @@ func (cmd *ReadCommand) Run() error {
 
 	return nil
 }
+
+// readSecretOrLink returns the secret version at the path. When there is no secret at the path,
+// the link at the path is resolved, with the version applied to the secret it points to.
+func readSecretOrLink(client secrethub.ClientInterface, secretPath api.SecretPath) (*api.SecretVersion, error) {
+	secret, err := client.Secrets().Versions().GetWithData(secretPath.Value())
+	if !api.IsErrNotFound(err) {
+		return secret, err
+	}
+
+	linkPath := secretPath
+	version, versionErr := secretPath.GetVersion()
+	if versionErr == nil {
+		linkPath = api.SecretPath(strings.TrimSuffix(secretPath.Value(), ":"+version))
+	}
+	target, ok, linkErr := resolveLink(client, linkPath)
+	if linkErr != nil {
+		return nil, linkErr
+	}
+	if !ok {
+		return nil, err
+	}
+	if versionErr == nil {
+		target = api.SecretPath(target.Value() + ":" + version)
+	}
+	return client.Secrets().Versions().GetWithData(target.Value())
+}
+
+// formatSecret returns the secret version in the output format.
+func (cmd *ReadCommand) formatSecret(secret *api.SecretVersion) ([]byte, error) {
+	switch cmd.format {
+	case readFormatJSON:
+		output := readJSONOutput{
+			Path:      strings.SplitN(cmd.path.Value(), ":", 2)[0],
+			Version:   secret.Version,
+			CreatedAt: secret.CreatedAt,
+			Status:    secret.Status,
+			Value:     string(secret.Data),
+			Encoding:  readEncodingUTF8,
+		}
+		if !utf8.Valid(secret.Data) {
+			output.Value = base64.StdEncoding.EncodeToString(secret.Data)
+			output.Encoding = readEncodingBase64
+		}
+		return json.Marshal(output)
+	case readFormatAnsibleVault:
+		password, err := ioutil.ReadFile(cmd.vaultPasswordFile)
+		if err != nil {
+			return nil, ErrCannotReadFile(cmd.vaultPasswordFile, err)
+		}
+		// Ansible ignores the whitespace around the password in the file as well.
+		password = bytes.TrimSpace(password)
+		if len(password) == 0 {
+			return nil, ErrEmptyVaultPassword(cmd.vaultPasswordFile)
+		}
+		return ansibleVaultEncrypt(secret.Data, password)
+	default:
+		return secret.Data, nil
+	}
+}
@@ -0,0 +1,75 @@
+package secrethub
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// Errors
+var (
+	ErrReadOnly = errMain.Code("read_only").StatusErrorPref(
+		"refusing to make changes (%s %s) because the CLI is in read-only mode. "+
+			"Read-only mode is enabled with the --read-only flag, the SECRETHUB_READ_ONLY environment variable or the read_only setting in the "+configFilename+" file",
+		http.StatusForbidden,
+	)
+)
+
+// loadReadOnly returns whether read-only mode is enabled in the configuration directory.
+func loadReadOnly(dir configdir.Dir) (bool, error) {
+	config, err := loadConfig(dir)
+	if err != nil {
+		return false, err
+	}
+	return config.ReadOnly, nil
+}
+
+// isReadOnly returns whether read-only mode is enabled with the --read-only flag or in the configuration file.
+// An invalid configuration is treated as read-only, because commands should never make changes by accident.
+func (f *clientFactory) isReadOnly() bool {
+	if f.readOnly {
+		return true
+	}
+	readOnly, err := loadReadOnly(f.store.ConfigDir())
+	if err != nil {
+		warnConfig(err)
+		return true
+	}
+	return readOnly
+}
+
+// readOnlyTransport is a http.RoundTripper that refuses all API calls that make changes.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+// newReadOnlyTransport returns a transport that only passes the API calls that do not make changes on to next.
+func newReadOnlyTransport(next http.RoundTripper) *readOnlyTransport {
+	return &readOnlyTransport{
+		next: next,
+	}
+}
+
+// RoundTrip performs the request when it only reads data and otherwise responds with an error.
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadOnlyRequest(req) {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return errorResponse(req, ErrReadOnly(req.Method, req.URL.Path))
+	}
+	return t.next.RoundTrip(req)
+}
+
+// isReadOnlyRequest returns whether the request does not make changes. Creating a session to
+// authenticate with an identity provider is allowed, as it is needed to read anything at all.
+func isReadOnlyRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(strings.TrimSuffix(req.URL.Path, "/"), "/auth")
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+func TestReadOnlyTransport_RoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		method  string
+		url     string
+		allowed bool
+	}{
+		"get": {
+			method:  http.MethodGet,
+			url:     "https://api.secrethub.io/v1/secrets/blindname/versions/1",
+			allowed: true,
+		},
+		"head": {
+			method:  http.MethodHead,
+			url:     "https://api.secrethub.io/v1/dirs/blindname",
+			allowed: true,
+		},
+		"session": {
+			method:  http.MethodPost,
+			url:     "https://api.secrethub.io/v1/auth",
+			allowed: true,
+		},
+		"write": {
+			method: http.MethodPost,
+			url:    "https://api.secrethub.io/v1/secrets/blindname/versions",
+		},
+		"acl set": {
+			method: http.MethodPut,
+			url:    "https://api.secrethub.io/v1/namespaces/dev/repos/repo/dirs/blindname/acl/dev1",
+		},
+		"rm": {
+			method: http.MethodDelete,
+			url:    "https://api.secrethub.io/v1/secrets/blindname",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			transport := newReadOnlyTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				called = true
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				}, nil
+			}))
+
+			req, err := http.NewRequest(tc.method, tc.url, strings.NewReader("{}"))
+			assert.OK(t, err)
+
+			resp, err := transport.RoundTrip(req)
+
+			assert.OK(t, err)
+			assert.Equal(t, called, tc.allowed)
+			if tc.allowed {
+				assert.Equal(t, resp.StatusCode, 200)
+			} else {
+				assert.Equal(t, resp.StatusCode, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestLoadReadOnly(t *testing.T) {
+	cases := map[string]struct {
+		config   cliConfig
+		expected bool
+	}{
+		"default": {
+			expected: false,
+		},
+		"configured": {
+			config:   cliConfig{ReadOnly: true},
+			expected: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			assert.OK(t, configFile(configdir.New(dir)).write(tc.config))
+
+			actual, err := loadReadOnly(configdir.New(dir))
+
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -1,9 +1,55 @@
 package secrethub
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
 )
 
 func TestReadCommand_Run(t *testing.T) {
 	// TODO SHDEV-1029 Test ReadCommand.
 }
+
+func TestReadCommand_formatSecret(t *testing.T) {
+	createdAt := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		path     api.SecretPath
+		data     []byte
+		expected string
+	}{
+		"utf-8": {
+			path:     "namespace/repo/secret",
+			data:     []byte("multi\nline \"value\""),
+			expected: `{"path":"namespace/repo/secret","version":3,"created_at":"2020-06-01T12:00:00Z","status":"ok","value":"multi\nline \"value\"","encoding":"utf-8"}`,
+		},
+		"binary": {
+			path:     "namespace/repo/secret:3",
+			data:     []byte{0xff, 0x00, 0x01},
+			expected: `{"path":"namespace/repo/secret","version":3,"created_at":"2020-06-01T12:00:00Z","status":"ok","value":"/wAB","encoding":"base64"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := ReadCommand{
+				path:   tc.path,
+				format: readFormatJSON,
+			}
+
+			actual, err := cmd.formatSecret(&api.SecretVersion{
+				Version:   3,
+				CreatedAt: createdAt,
+				Status:    "ok",
+				Data:      tc.data,
+			})
+
+			assert.OK(t, err)
+			assert.Equal(t, string(actual), tc.expected)
+			assert.Equal(t, json.Valid(actual), true)
+		})
+	}
+}
@@ -7,15 +7,17 @@ import (
 
 // RepoCommand handles operations on repositories.
 type RepoCommand struct {
-	io        ui.IO
-	newClient newClientFunc
+	io              ui.IO
+	newClient       newClientFunc
+	credentialStore CredentialConfig
 }
 
 // NewRepoCommand creates a new RepoCommand.
-func NewRepoCommand(io ui.IO, newClient newClientFunc) *RepoCommand {
+func NewRepoCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *RepoCommand {
 	return &RepoCommand{
-		io:        io,
-		newClient: newClient,
+		io:              io,
+		newClient:       newClient,
+		credentialStore: credentialStore,
 	}
 }
 
@@ -27,9 +29,15 @@ func (cmd *RepoCommand) Register(r command.Registerer) {
 	clause.Alias("repositories")
 	NewRepoInitCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoInspectCommand(cmd.io, cmd.newClient).Register(clause)
-	NewRepoInviteCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoInviteCommand(cmd.io, cmd.newClient, cmd.credentialStore).Register(clause)
+	NewRepoInvitesCommand(cmd.io, cmd.newClient, cmd.credentialStore).Register(clause)
+	NewRepoArchiveCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoExportCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoGraphCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoImportCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoLSCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoRevokeCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoRmCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoStatsCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoUnarchiveCommand(cmd.io, cmd.newClient).Register(clause)
 }
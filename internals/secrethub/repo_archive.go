@@ -0,0 +1,227 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrRepoAlreadyArchived = errMain.Code("repo_already_archived").ErrorPref("the repository %s is already archived")
+	ErrRepoNotArchived     = errMain.Code("repo_not_archived").ErrorPref("the repository %s is not archived")
+)
+
+// repoArchiveMarkerName is the name of the secret in the root directory of a repository that marks
+// the repository as archived. It contains the access rules that were changed when archiving.
+const repoArchiveMarkerName = ".archived"
+
+// repoArchiveMarker is the content of the archive marker secret.
+type repoArchiveMarker struct {
+	ArchivedAt time.Time
+	Rules      []repoArchiveRule
+}
+
+// repoArchiveRule is an access rule that was downgraded from write to read when archiving the repository.
+type repoArchiveRule struct {
+	Path    string
+	Account string
+}
+
+// repoArchiveMarkerPath returns the path of the archive marker secret of the repository.
+func repoArchiveMarkerPath(repoPath api.RepoPath) string {
+	return repoPath.GetDirPath().JoinSecret(repoArchiveMarkerName).Value()
+}
+
+// isRepoArchived returns whether the repository has an archive marker.
+func isRepoArchived(client secrethub.ClientInterface, repoPath api.RepoPath) (bool, error) {
+	_, err := client.Secrets().Get(repoArchiveMarkerPath(repoPath))
+	if err == api.ErrSecretNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RepoArchiveCommand freezes a repository by revoking write access for everyone except admins.
+type RepoArchiveCommand struct {
+	io        ui.IO
+	path      api.RepoPath
+	force     bool
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewRepoArchiveCommand creates a new RepoArchiveCommand.
+func NewRepoArchiveCommand(io ui.IO, newClient newClientFunc) *RepoArchiveCommand {
+	return &RepoArchiveCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoArchiveCommand) Register(r command.Registerer) {
+	clause := r.Command("archive", "Freeze a repository: every write permission is changed to read, so only admins can still modify it. "+
+		"The repository is marked as archived in repository listings and can be restored with `repo unarchive`.")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run archives the repository.
+func (cmd *RepoArchiveCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	archived, err := isRepoArchived(client, cmd.path)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return ErrRepoAlreadyArchived(cmd.path)
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	rules, err := client.AccessRules().List(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	marker := repoArchiveMarker{
+		ArchivedAt: cmd.now().UTC(),
+		Rules:      []repoArchiveRule{},
+	}
+	for _, rule := range rules {
+		if rule.Permission != api.PermissionWrite || rule.Account == nil {
+			continue
+		}
+
+		dirPath, err := tree.AbsDirPath(rule.DirID)
+		if err != nil {
+			return err
+		}
+		marker.Rules = append(marker.Rules, repoArchiveRule{
+			Path:    dirPath.String(),
+			Account: rule.Account.Name.String(),
+		})
+	}
+
+	if !cmd.force {
+		msg := fmt.Sprintf("Are you sure you want to archive the repository %s? This changes %d write permission(s) to read.",
+			cmd.path,
+			len(marker.Rules),
+		)
+
+		confirmed, err := ui.AskYesNo(cmd.io, msg, ui.DefaultNo)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return ErrAborted
+		}
+	}
+
+	data, err := json.Marshal(marker)
+	if err != nil {
+		return err
+	}
+
+	// The marker is written before changing any rule, so that an interrupted
+	// archive can always be reverted with unarchive.
+	_, err = client.Secrets().Write(repoArchiveMarkerPath(cmd.path), data)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range marker.Rules {
+		_, err = client.AccessRules().Set(rule.Path, api.PermissionRead.String(), rule.Account)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Changed the permission of %s on %s to read.\n", rule.Account, rule.Path)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Archived the repository %s.\n", cmd.path)
+	return nil
+}
+
+// RepoUnarchiveCommand restores the write permissions of an archived repository.
+type RepoUnarchiveCommand struct {
+	io        ui.IO
+	path      api.RepoPath
+	newClient newClientFunc
+}
+
+// NewRepoUnarchiveCommand creates a new RepoUnarchiveCommand.
+func NewRepoUnarchiveCommand(io ui.IO, newClient newClientFunc) *RepoUnarchiveCommand {
+	return &RepoUnarchiveCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoUnarchiveCommand) Register(r command.Registerer) {
+	clause := r.Command("unarchive", "Restore an archived repository: the write permissions that were changed to read when archiving are changed back to write.")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run unarchives the repository.
+func (cmd *RepoUnarchiveCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	markerPath := repoArchiveMarkerPath(cmd.path)
+	version, err := client.Secrets().Versions().GetWithData(markerPath)
+	if err == api.ErrSecretNotFound {
+		return ErrRepoNotArchived(cmd.path)
+	} else if err != nil {
+		return err
+	}
+
+	var marker repoArchiveMarker
+	err = json.Unmarshal(version.Data, &marker)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range marker.Rules {
+		_, err = client.AccessRules().Set(rule.Path, api.PermissionWrite.String(), rule.Account)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Changed the permission of %s on %s to write.\n", rule.Account, rule.Path)
+	}
+
+	err = client.Secrets().Delete(markerPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Unarchived the repository %s.\n", cmd.path)
+	return nil
+}
@@ -0,0 +1,252 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestRepoArchiveCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	rootDirID := uuid.New()
+	subDirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {
+				Name:  "repo",
+				DirID: rootDirID,
+			},
+			subDirID: {
+				Name:     "dir",
+				DirID:    subDirID,
+				ParentID: &rootDirID,
+			},
+		},
+		RootDir: &api.Dir{
+			Name:  "repo",
+			DirID: rootDirID,
+		},
+	}
+	rules := []*api.AccessRule{
+		{
+			Account:    &api.Account{Name: "admin"},
+			DirID:      rootDirID,
+			Permission: api.PermissionAdmin,
+		},
+		{
+			Account:    &api.Account{Name: "developer"},
+			DirID:      subDirID,
+			Permission: api.PermissionWrite,
+		},
+		{
+			Account:    &api.Account{Name: "s-abc"},
+			DirID:      rootDirID,
+			Permission: api.PermissionRead,
+		},
+	}
+
+	cases := map[string]struct {
+		force         bool
+		promptIn      string
+		archived      bool
+		setErr        error
+		expectedData  string
+		expectedRules []string
+		out           string
+		err           error
+	}{
+		"success": {
+			force:         true,
+			expectedData:  `{"ArchivedAt":"2020-01-01T12:00:00Z","Rules":[{"Path":"namespace/repo/dir","Account":"developer"}]}`,
+			expectedRules: []string{"namespace/repo/dir read developer"},
+			out: "Changed the permission of developer on namespace/repo/dir to read.\n" +
+				"Archived the repository namespace/repo.\n",
+		},
+		"confirmed": {
+			promptIn:      "y",
+			expectedData:  `{"ArchivedAt":"2020-01-01T12:00:00Z","Rules":[{"Path":"namespace/repo/dir","Account":"developer"}]}`,
+			expectedRules: []string{"namespace/repo/dir read developer"},
+			out: "Changed the permission of developer on namespace/repo/dir to read.\n" +
+				"Archived the repository namespace/repo.\n",
+		},
+		"abort": {
+			promptIn: "n",
+			out:      "Aborting.\n",
+			err:      ErrAborted,
+		},
+		"already archived": {
+			force:    true,
+			archived: true,
+			err:      ErrRepoAlreadyArchived("namespace/repo"),
+		},
+		"set error": {
+			force:         true,
+			setErr:        testErr,
+			expectedData:  `{"ArchivedAt":"2020-01-01T12:00:00Z","Rules":[{"Path":"namespace/repo/dir","Account":"developer"}]}`,
+			expectedRules: []string{"namespace/repo/dir read developer"},
+			err:           testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var data string
+			var setRules []string
+
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.promptIn)
+			cmd := RepoArchiveCommand{
+				io:    io,
+				path:  "namespace/repo",
+				force: tc.force,
+				now: func() time.Time {
+					return now
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							GetFunc: func(path string) (*api.Secret, error) {
+								assert.Equal(t, path, "namespace/repo/.archived")
+								if tc.archived {
+									return &api.Secret{}, nil
+								}
+								return nil, api.ErrSecretNotFound
+							},
+							WriteFunc: func(path string, d []byte) (*api.SecretVersion, error) {
+								assert.Equal(t, path, "namespace/repo/.archived")
+								data = string(d)
+								return &api.SecretVersion{}, nil
+							},
+						},
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return tree, nil
+							},
+						},
+						AccessRuleService: &fakeclient.AccessRuleService{
+							ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+								return rules, nil
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								setRules = append(setRules, path+" "+permission+" "+accountName)
+								return nil, tc.setErr
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, data, tc.expectedData)
+			assert.Equal(t, setRules, tc.expectedRules)
+		})
+	}
+}
+
+func TestRepoUnarchiveCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	cases := map[string]struct {
+		getErr        error
+		deleteErr     error
+		expectedRules []string
+		deleted       bool
+		out           string
+		err           error
+	}{
+		"success": {
+			expectedRules: []string{
+				"namespace/repo/dir write developer",
+				"namespace/repo write s-abc",
+			},
+			deleted: true,
+			out: "Changed the permission of developer on namespace/repo/dir to write.\n" +
+				"Changed the permission of s-abc on namespace/repo to write.\n" +
+				"Unarchived the repository namespace/repo.\n",
+		},
+		"not archived": {
+			getErr: api.ErrSecretNotFound,
+			err:    ErrRepoNotArchived("namespace/repo"),
+		},
+		"get error": {
+			getErr: testErr,
+			err:    testErr,
+		},
+		"delete error": {
+			expectedRules: []string{
+				"namespace/repo/dir write developer",
+				"namespace/repo write s-abc",
+			},
+			deleted:   true,
+			deleteErr: testErr,
+			out: "Changed the permission of developer on namespace/repo/dir to write.\n" +
+				"Changed the permission of s-abc on namespace/repo to write.\n",
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var setRules []string
+			var deleted bool
+
+			io := fakeui.NewIO(t)
+			cmd := RepoUnarchiveCommand{
+				io:   io,
+				path: "namespace/repo",
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									assert.Equal(t, path, "namespace/repo/.archived")
+									if tc.getErr != nil {
+										return nil, tc.getErr
+									}
+									return &api.SecretVersion{
+										Data: []byte(`{"ArchivedAt":"2020-01-01T12:00:00Z","Rules":[` +
+											`{"Path":"namespace/repo/dir","Account":"developer"},` +
+											`{"Path":"namespace/repo","Account":"s-abc"}]}`),
+									}, nil
+								},
+							},
+							DeleteFunc: func(path string) error {
+								assert.Equal(t, path, "namespace/repo/.archived")
+								deleted = true
+								return tc.deleteErr
+							},
+						},
+						AccessRuleService: &fakeclient.AccessRuleService{
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								setRules = append(setRules, path+" "+permission+" "+accountName)
+								return nil, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, setRules, tc.expectedRules)
+			assert.Equal(t, deleted, tc.deleted)
+		})
+	}
+}
@@ -2,16 +2,21 @@ package secrethub
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/posix"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/backup"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
 
 // Error
@@ -21,10 +26,12 @@ var (
 
 // RepoExportCommand exports a repo to a zip file.
 type RepoExportCommand struct {
-	path      api.RepoPath
-	zipName   string
-	io        ui.IO
-	newClient newClientFunc
+	path           api.RepoPath
+	zipName        string
+	out            string
+	passphraseFile string
+	io             ui.IO
+	newClient      newClientFunc
 }
 
 // NewRepoExportCommand creates a new RepoExportCommand.
@@ -40,12 +47,18 @@ func (cmd *RepoExportCommand) Register(r command.Registerer) {
 	clause := r.Command("export", "Export the repository to a zip file.")
 	clause.Arg("repo-path", "The repository to export").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
 	clause.Arg("zip-file-name", "The file name to assign to the exported .zip file. Defaults to secrethub_export_<namespace>_<repo>_<timestamp>.zip with the timestamp formatted as YYYYMMDD_HHMMSS").StringVar(&cmd.zipName)
+	clause.Flag("out", "Instead of an unencrypted .zip file, write an encrypted backup archive containing all secret versions, directories and access rules to this file. The archive can be restored with `repo import`.").PlaceHolder("backup.shub").StringVar(&cmd.out)
+	clause.Flag("passphrase-file", "Read the passphrase to encrypt the backup archive with from this file. When not set, the passphrase is asked interactively.").ExistingFileVar(&cmd.passphraseFile)
 
 	command.BindAction(clause, cmd.Run)
 }
 
 // Run exports a repo to a zip file
 func (cmd *RepoExportCommand) Run() error {
+	if cmd.out != "" {
+		return cmd.runEncrypted()
+	}
+
 	if cmd.zipName == "" {
 		// secrethub_export_repo_date_time.zip
 		cmd.zipName = fmt.Sprintf("%s_export_%s_%s.zip", ApplicationName, cmd.path.GetRepo(), time.Now().Format("20060102_150405"))
@@ -72,7 +85,7 @@ func (cmd *RepoExportCommand) Run() error {
 
 	if !confirmed {
 		fmt.Fprintln(cmd.io.Output(), "Name does not match. Aborting.")
-		return nil
+		return ErrAborted
 	}
 
 	client, err := cmd.newClient()
@@ -85,6 +98,33 @@ func (cmd *RepoExportCommand) Run() error {
 		return err
 	}
 
+	secretPaths := make([]api.SecretPath, 0, len(rootDir.Secrets))
+	for _, secret := range rootDir.Secrets {
+		secretPath, err := rootDir.AbsSecretPath(secret.SecretID)
+		if err != nil {
+			return err
+		}
+		secretPaths = append(secretPaths, *secretPath)
+	}
+
+	// The tree is fetched first, so the client has loaded the keys it needs to read the secrets concurrently.
+	// The secrets are read before the zip file is created, so no partial export is left behind when reading fails.
+	counter := newProgressCounter("Reading secrets", len(secretPaths))
+	versions := make([][]*api.SecretVersion, len(secretPaths))
+	err = forEachConcurrently(int(concurrency), len(secretPaths), func(i int) error {
+		secretVersions, err := client.Secrets().Versions().ListWithData(secretPaths[i].Value())
+		if err != nil {
+			return err
+		}
+		versions[i] = secretVersions
+		counter.Done()
+		return nil
+	})
+	counter.Finish()
+	if err != nil {
+		return err
+	}
+
 	zipFile, err := os.Create(cmd.zipName)
 	if err != nil {
 		return err
@@ -103,18 +143,8 @@ func (cmd *RepoExportCommand) Run() error {
 		}
 	}()
 
-	for _, secret := range rootDir.Secrets {
-		secretPath, err := rootDir.AbsSecretPath(secret.SecretID)
-		if err != nil {
-			return err
-		}
-
-		versions, err := client.Secrets().Versions().ListWithData(secretPath.Value())
-		if err != nil {
-			return err
-		}
-
-		for _, version := range versions {
+	for i, secretPath := range secretPaths {
+		for _, version := range versions[i] {
 			versionPath, err := secretPath.AddVersion(version.Version)
 			if err != nil {
 				return err
@@ -139,3 +169,170 @@ func (cmd *RepoExportCommand) Run() error {
 
 	return nil
 }
+
+// runEncrypted exports the repo to an encrypted backup archive.
+func (cmd *RepoExportCommand) runEncrypted() error {
+	if cmd.zipName != "" {
+		return ErrFlagsConflict("zip-file-name and --out")
+	}
+
+	_, err := os.Stat(cmd.out)
+	if err == nil {
+		return ErrExportAlreadyExists
+	}
+
+	passphrase, err := readBackupPassphrase(cmd.io, cmd.passphraseFile, true)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Exporting %s...\n", cmd.path)
+
+	archive, err := newRepoBackup(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = backup.Write(&buf, archive, passphrase)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(cmd.out, buf.Bytes(), configFileMode)
+	if err != nil {
+		return ErrCannotWrite(cmd.out, err)
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Export complete! %s and %s written to %s.\n",
+		pluralize("directory", "directories", len(archive.Dirs)),
+		pluralize("secret", "secrets", len(archive.Secrets)),
+		cmd.out,
+	)
+	return nil
+}
+
+// newRepoBackup downloads all directories, secret versions and access rules of a repository into a backup archive.
+func newRepoBackup(client secrethub.ClientInterface, repoPath api.RepoPath) (*backup.Archive, error) {
+	tree, err := client.Dirs().GetTree(repoPath.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	relativePath := func(path string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(path, repoPath.String()), "/")
+	}
+
+	archive := &backup.Archive{
+		FormatVersion: backup.FormatVersion,
+		Repo:          repoPath.String(),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	for dirID := range tree.Dirs {
+		dirPath, err := tree.AbsDirPath(dirID)
+		if err != nil {
+			return nil, err
+		}
+		if rel := relativePath(dirPath.String()); rel != "" {
+			archive.Dirs = append(archive.Dirs, rel)
+		}
+	}
+	sort.Strings(archive.Dirs)
+
+	secretPaths := make([]api.SecretPath, 0, len(tree.Secrets))
+	for secretID := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secretID)
+		if err != nil {
+			return nil, err
+		}
+		secretPaths = append(secretPaths, *secretPath)
+	}
+
+	// The tree is fetched first, so the client has loaded the keys it needs to read the secrets concurrently.
+	counter := newProgressCounter("Downloading secrets", len(secretPaths))
+	defer counter.Finish()
+
+	archive.Secrets = make([]backup.Secret, len(secretPaths))
+	err = forEachConcurrently(int(concurrency), len(secretPaths), func(i int) error {
+		versions, err := client.Secrets().Versions().ListWithData(secretPaths[i].Value())
+		if err != nil {
+			return err
+		}
+
+		backupSecret := backup.Secret{
+			Path:     relativePath(secretPaths[i].String()),
+			Versions: make([]backup.SecretVersion, len(versions)),
+		}
+		for j, version := range versions {
+			backupSecret.Versions[j] = backup.SecretVersion{
+				Version:   version.Version,
+				Data:      version.Data,
+				CreatedAt: version.CreatedAt,
+			}
+		}
+		sort.Slice(backupSecret.Versions, func(a, b int) bool {
+			return backupSecret.Versions[a].Version < backupSecret.Versions[b].Version
+		})
+		archive.Secrets[i] = backupSecret
+		counter.Done()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(archive.Secrets, func(i, j int) bool {
+		return archive.Secrets[i].Path < archive.Secrets[j].Path
+	})
+
+	rules, err := client.AccessRules().List(repoPath.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Account == nil {
+			continue
+		}
+
+		dirPath, err := tree.AbsDirPath(rule.DirID)
+		if err != nil {
+			return nil, err
+		}
+		archive.AccessRules = append(archive.AccessRules, backup.AccessRule{
+			Path:       relativePath(dirPath.String()),
+			Account:    rule.Account.Name.String(),
+			Permission: rule.Permission.String(),
+		})
+	}
+
+	return archive, nil
+}
+
+// readBackupPassphrase reads the passphrase of a backup archive from the given file or,
+// when no file is given, asks for it. When confirm is true, the passphrase has to be entered twice.
+func readBackupPassphrase(io ui.IO, passphraseFile string, confirm bool) ([]byte, error) {
+	if passphraseFile != "" {
+		raw, err := ioutil.ReadFile(passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.TrimRight(raw, "\r\n"), nil
+	}
+
+	var passphrase string
+	var err error
+	if confirm {
+		passphrase, err = ui.AskPassphrase(io, "Please enter a passphrase to encrypt the backup with: ", "Enter the passphrase again: ", 3)
+	} else {
+		passphrase, err = ui.AskSecret(io, "Please enter the passphrase of the backup: ")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(passphrase), nil
+}
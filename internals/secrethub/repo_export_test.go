@@ -0,0 +1,175 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/backup"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// newRepoExportTestClient returns a client serving a repository with a nested directory,
+// two secrets and access rules on both directories.
+func newRepoExportTestClient() secrethub.ClientInterface {
+	rootID := uuid.New()
+	subID := uuid.New()
+	secret1ID := uuid.New()
+	secret2ID := uuid.New()
+	createdAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tree := &api.Tree{
+		ParentPath: "company",
+		RootDir: &api.Dir{
+			DirID: rootID,
+			Name:  "repo",
+		},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootID: {DirID: rootID, Name: "repo"},
+			subID:  {DirID: subID, Name: "db", ParentID: &rootID},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{
+			secret1ID: {SecretID: secret1ID, DirID: rootID, Name: "api_key"},
+			secret2ID: {SecretID: secret2ID, DirID: subID, Name: "password"},
+		},
+	}
+
+	versions := map[string][]*api.SecretVersion{
+		"company/repo/api_key": {
+			{Version: 2, Data: []byte("key2"), CreatedAt: createdAt.Add(time.Hour)},
+			{Version: 1, Data: []byte("key1"), CreatedAt: createdAt},
+		},
+		"company/repo/db/password": {
+			{Version: 1, Data: []byte("secret"), CreatedAt: createdAt},
+		},
+	}
+
+	return fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return tree, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				ListWithDataFunc: func(path string) ([]*api.SecretVersion, error) {
+					return versions[path], nil
+				},
+			},
+		},
+		AccessRuleService: &fakeclient.AccessRuleService{
+			ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+				return []*api.AccessRule{
+					{DirID: rootID, Account: &api.Account{Name: "dev1"}, Permission: api.PermissionAdmin},
+					{DirID: subID, Account: &api.Account{Name: "s-ci"}, Permission: api.PermissionRead},
+					{DirID: subID, Permission: api.PermissionRead},
+				}, nil
+			},
+		},
+	}
+}
+
+func TestNewRepoBackup(t *testing.T) {
+	createdAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	archive, err := newRepoBackup(newRepoExportTestClient(), "company/repo")
+	assert.OK(t, err)
+
+	assert.Equal(t, archive.Repo, "company/repo")
+	assert.Equal(t, archive.FormatVersion, backup.FormatVersion)
+	assert.Equal(t, archive.Dirs, []string{"db"})
+	assert.Equal(t, archive.Secrets, []backup.Secret{
+		{
+			Path: "api_key",
+			Versions: []backup.SecretVersion{
+				{Version: 1, Data: []byte("key1"), CreatedAt: createdAt},
+				{Version: 2, Data: []byte("key2"), CreatedAt: createdAt.Add(time.Hour)},
+			},
+		},
+		{
+			Path: "db/password",
+			Versions: []backup.SecretVersion{
+				{Version: 1, Data: []byte("secret"), CreatedAt: createdAt},
+			},
+		},
+	})
+	assert.Equal(t, archive.AccessRules, []backup.AccessRule{
+		{Path: "", Account: "dev1", Permission: "admin"},
+		{Path: "db", Account: "s-ci", Permission: "read"},
+	})
+}
+
+func TestRepoExportCommand_runEncrypted(t *testing.T) {
+	passphrase := "correct horse battery staple"
+
+	cases := map[string]struct {
+		zipName  string
+		existing bool
+		err      error
+	}{
+		"success": {},
+		"zip file name and out": {
+			zipName: "export.zip",
+			err:     ErrFlagsConflict("zip-file-name and --out"),
+		},
+		"file exists": {
+			existing: true,
+			err:      ErrExportAlreadyExists,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			out := filepath.Join(dir, "backup.shub")
+			if tc.existing {
+				err := ioutil.WriteFile(out, []byte("existing"), 0600)
+				assert.OK(t, err)
+			}
+
+			passphraseFile := filepath.Join(dir, "passphrase")
+			err := ioutil.WriteFile(passphraseFile, []byte(passphrase+"\n"), 0600)
+			assert.OK(t, err)
+
+			cmd := RepoExportCommand{
+				path:           "company/repo",
+				zipName:        tc.zipName,
+				out:            out,
+				passphraseFile: passphraseFile,
+				io:             fakeui.NewIO(t),
+				newClient: func() (secrethub.ClientInterface, error) {
+					return newRepoExportTestClient(), nil
+				},
+			}
+
+			// Act
+			err = cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+
+			f, err := os.Open(out)
+			assert.OK(t, err)
+			defer f.Close()
+
+			archive, err := backup.Read(f, []byte(passphrase))
+			assert.OK(t, err)
+			assert.Equal(t, archive.Repo, "company/repo")
+			assert.Equal(t, len(archive.Secrets), 2)
+		})
+	}
+}
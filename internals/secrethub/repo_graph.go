@@ -0,0 +1,242 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+)
+
+const (
+	formatDOT     = "dot"
+	formatMermaid = "mermaid"
+)
+
+// RepoGraphCommand prints the directories, accounts and permissions of a repository as a graph.
+type RepoGraphCommand struct {
+	io        ui.IO
+	path      api.RepoPath
+	format    string
+	newClient newClientFunc
+}
+
+// NewRepoGraphCommand creates a new RepoGraphCommand.
+func NewRepoGraphCommand(io ui.IO, newClient newClientFunc) *RepoGraphCommand {
+	return &RepoGraphCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoGraphCommand) Register(r command.Registerer) {
+	clause := r.Command("graph", "Print the directories, accounts and permissions of a repository as a graph that can be rendered with Graphviz or Mermaid. "+
+		"Directories are connected to their subdirectories and accounts are connected to the directories they have an access rule on, labeled with the permission.")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("format", "The format of the graph. Options are: dot and mermaid.").HintOptions(formatDOT, formatMermaid).Default(formatDOT).StringVar(&cmd.format)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the graph of the repository.
+func (cmd *RepoGraphCommand) Run() error {
+	if cmd.format != formatDOT && cmd.format != formatMermaid {
+		return errNoSuchFormat(cmd.format)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := client.Repos().ListAccounts(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	rules, err := client.AccessRules().List(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	graph, err := newRepoGraph(tree, accounts, rules)
+	if err != nil {
+		return err
+	}
+
+	if cmd.format == formatMermaid {
+		graph.writeMermaid(cmd.io.Output())
+		return nil
+	}
+	graph.writeDOT(cmd.io.Output(), cmd.path.String())
+	return nil
+}
+
+// repoGraphNode is a directory or an account in a repoGraph.
+type repoGraphNode struct {
+	id      string
+	label   string
+	service bool
+}
+
+// repoGraphEdge connects two nodes of a repoGraph. Edges without a label
+// connect a directory to its subdirectory.
+type repoGraphEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+// repoGraph is the access topology of a repository. Nodes and edges are sorted,
+// so that the graph of an unchanged repository is always printed the same.
+type repoGraph struct {
+	dirs     []repoGraphNode
+	accounts []repoGraphNode
+	edges    []repoGraphEdge
+}
+
+// newRepoGraph creates the graph of the given directory tree, repository accounts and access rules.
+func newRepoGraph(tree *api.Tree, accounts []*api.Account, rules []*api.AccessRule) (repoGraph, error) {
+	var graph repoGraph
+
+	dirPaths := make(map[uuid.UUID]string, len(tree.Dirs))
+	for dirID := range tree.Dirs {
+		dirPath, err := tree.AbsDirPath(dirID)
+		if err != nil {
+			return repoGraph{}, err
+		}
+		dirPaths[dirID] = dirPath.String()
+	}
+
+	dirIDs := make([]uuid.UUID, 0, len(dirPaths))
+	for dirID := range dirPaths {
+		dirIDs = append(dirIDs, dirID)
+	}
+	sort.Slice(dirIDs, func(i, j int) bool {
+		return dirPaths[dirIDs[i]] < dirPaths[dirIDs[j]]
+	})
+
+	dirNodes := make(map[uuid.UUID]string, len(dirIDs))
+	for i, dirID := range dirIDs {
+		id := fmt.Sprintf("d%d", i)
+		dirNodes[dirID] = id
+		graph.dirs = append(graph.dirs, repoGraphNode{id: id, label: dirPaths[dirID]})
+	}
+
+	for _, dirID := range dirIDs {
+		dir := tree.Dirs[dirID]
+		if dir.ParentID == nil {
+			continue
+		}
+		parent, ok := dirNodes[*dir.ParentID]
+		if !ok {
+			continue
+		}
+		graph.edges = append(graph.edges, repoGraphEdge{from: parent, to: dirNodes[dirID]})
+	}
+
+	sorted := make([]*api.Account, len(accounts))
+	copy(sorted, accounts)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	accountNodes := make(map[api.AccountName]string, len(sorted))
+	for i, account := range sorted {
+		id := fmt.Sprintf("a%d", i)
+		accountNodes[account.Name] = id
+		graph.accounts = append(graph.accounts, repoGraphNode{id: id, label: account.Name.String(), service: account.Name.IsService()})
+	}
+
+	var permissions []repoGraphEdge
+	for _, rule := range rules {
+		if rule.Account == nil {
+			continue
+		}
+		from, ok := accountNodes[rule.Account.Name]
+		if !ok {
+			continue
+		}
+		to, ok := dirNodes[rule.DirID]
+		if !ok {
+			continue
+		}
+		permissions = append(permissions, repoGraphEdge{from: from, to: to, label: rule.Permission.String()})
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].from != permissions[j].from {
+			return permissions[i].from < permissions[j].from
+		}
+		return permissions[i].to < permissions[j].to
+	})
+	graph.edges = append(graph.edges, permissions...)
+
+	return graph, nil
+}
+
+// writeDOT writes the graph in the Graphviz DOT language.
+func (g repoGraph) writeDOT(w io.Writer, name string) {
+	fmt.Fprintf(w, "digraph %s {\n", dotQuote(name))
+	fmt.Fprintln(w, "    rankdir=LR;")
+	for _, dir := range g.dirs {
+		fmt.Fprintf(w, "    %s [label=%s, shape=folder];\n", dir.id, dotQuote(dir.label))
+	}
+	for _, account := range g.accounts {
+		shape := "ellipse"
+		if account.service {
+			shape = "box"
+		}
+		fmt.Fprintf(w, "    %s [label=%s, shape=%s];\n", account.id, dotQuote(account.label), shape)
+	}
+	for _, edge := range g.edges {
+		if edge.label == "" {
+			fmt.Fprintf(w, "    %s -> %s [style=dashed];\n", edge.from, edge.to)
+		} else {
+			fmt.Fprintf(w, "    %s -> %s [label=%s];\n", edge.from, edge.to, dotQuote(edge.label))
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// writeMermaid writes the graph as a Mermaid flowchart.
+func (g repoGraph) writeMermaid(w io.Writer) {
+	fmt.Fprintln(w, "graph LR")
+	for _, dir := range g.dirs {
+		fmt.Fprintf(w, "    %s[%s]\n", dir.id, mermaidQuote(dir.label))
+	}
+	for _, account := range g.accounts {
+		if account.service {
+			fmt.Fprintf(w, "    %s[[%s]]\n", account.id, mermaidQuote(account.label))
+		} else {
+			fmt.Fprintf(w, "    %s(%s)\n", account.id, mermaidQuote(account.label))
+		}
+	}
+	for _, edge := range g.edges {
+		if edge.label == "" {
+			fmt.Fprintf(w, "    %s -.-> %s\n", edge.from, edge.to)
+		} else {
+			fmt.Fprintf(w, "    %s -->|%s| %s\n", edge.from, edge.label, edge.to)
+		}
+	}
+}
+
+// dotQuote returns s as a quoted DOT identifier.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// mermaidQuote returns s as a quoted Mermaid node label.
+func mermaidQuote(s string) string {
+	return `"` + strings.Replace(s, `"`, "#quot;", -1) + `"`
+}
@@ -0,0 +1,140 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestRepoGraphCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+
+	rootDirID := uuid.New()
+	subDirID := uuid.New()
+	developer := &api.Account{Name: "developer"}
+	service := &api.Account{Name: "s-abc"}
+
+	client := fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return &api.Tree{
+					ParentPath: "namespace",
+					Dirs: map[uuid.UUID]*api.Dir{
+						rootDirID: {
+							Name:  "repo",
+							DirID: rootDirID,
+						},
+						subDirID: {
+							Name:     "dir",
+							DirID:    subDirID,
+							ParentID: &rootDirID,
+						},
+					},
+					RootDir: &api.Dir{
+						Name:  "repo",
+						DirID: rootDirID,
+					},
+				}, nil
+			},
+		},
+		RepoService: &fakeclient.RepoService{
+			ListAccountsFunc: func(path string) ([]*api.Account, error) {
+				return []*api.Account{service, developer}, nil
+			},
+		},
+		AccessRuleService: &fakeclient.AccessRuleService{
+			ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+				return []*api.AccessRule{
+					{
+						Account:    service,
+						DirID:      subDirID,
+						Permission: api.PermissionRead,
+					},
+					{
+						Account:    developer,
+						DirID:      rootDirID,
+						Permission: api.PermissionAdmin,
+					},
+				}, nil
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		cmd       RepoGraphCommand
+		newClient newClientFunc
+		out       string
+		err       error
+	}{
+		"dot": {
+			cmd: RepoGraphCommand{
+				format: formatDOT,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return client, nil
+			},
+			out: "digraph \"namespace/repo\" {\n" +
+				"    rankdir=LR;\n" +
+				"    d0 [label=\"namespace/repo\", shape=folder];\n" +
+				"    d1 [label=\"namespace/repo/dir\", shape=folder];\n" +
+				"    a0 [label=\"developer\", shape=ellipse];\n" +
+				"    a1 [label=\"s-abc\", shape=box];\n" +
+				"    d0 -> d1 [style=dashed];\n" +
+				"    a0 -> d0 [label=\"admin\"];\n" +
+				"    a1 -> d1 [label=\"read\"];\n" +
+				"}\n",
+		},
+		"mermaid": {
+			cmd: RepoGraphCommand{
+				format: formatMermaid,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return client, nil
+			},
+			out: "graph LR\n" +
+				"    d0[\"namespace/repo\"]\n" +
+				"    d1[\"namespace/repo/dir\"]\n" +
+				"    a0(\"developer\")\n" +
+				"    a1[[\"s-abc\"]]\n" +
+				"    d0 -.-> d1\n" +
+				"    a0 -->|admin| d0\n" +
+				"    a1 -->|read| d1\n",
+		},
+		"invalid format": {
+			cmd: RepoGraphCommand{
+				format: "svg",
+			},
+			err: errNoSuchFormat("svg"),
+		},
+		"client error": {
+			cmd: RepoGraphCommand{
+				format: formatDOT,
+			},
+			newClient: func() (secrethub.ClientInterface, error) {
+				return nil, testErr
+			},
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+			tc.cmd.path = "namespace/repo"
+			tc.cmd.newClient = tc.newClient
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/backup"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrInvalidConflictStrategy = errMain.Code("invalid_conflict_strategy").ErrorPref("invalid conflict strategy %q: must be one of skip, overwrite or rename")
+)
+
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictRename    = "rename"
+
+	// importRenameSuffix is appended to the name of imported secrets that conflict with existing secrets.
+	importRenameSuffix = "_imported"
+)
+
+// RepoImportCommand restores a repository from an encrypted backup archive.
+type RepoImportCommand struct {
+	file           string
+	path           api.RepoPath
+	passphraseFile string
+	onConflict     string
+	createRepo     bool
+	importACL      bool
+	io             ui.IO
+	newClient      newClientFunc
+}
+
+// NewRepoImportCommand creates a new RepoImportCommand.
+func NewRepoImportCommand(io ui.IO, newClient newClientFunc) *RepoImportCommand {
+	return &RepoImportCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoImportCommand) Register(r command.Registerer) {
+	clause := r.Command("import", "Restore the directories and secrets from a backup archive created with `repo export --out` into a repository. "+
+		"All versions of a secret are written in order, so restored secrets get new version numbers.")
+	clause.Arg("backup-file", "The backup archive to import").Required().ExistingFileVar(&cmd.file)
+	clause.Arg("repo-path", "The repository to import the backup into").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("passphrase-file", "Read the passphrase of the backup archive from this file. When not set, the passphrase is asked interactively.").ExistingFileVar(&cmd.passphraseFile)
+	clause.Flag("on-conflict", "What to do with secrets that already exist in the repository: skip them, overwrite them by writing the imported versions on top or rename the imported secret by adding an "+importRenameSuffix+" suffix.").HintOptions(conflictSkip, conflictOverwrite, conflictRename).Default(conflictSkip).StringVar(&cmd.onConflict)
+	clause.Flag("create-repo", "Create the repository before importing.").BoolVar(&cmd.createRepo)
+	clause.Flag("acl", "Also restore the access rules in the archive. Accounts that no longer exist are reported and skipped.").BoolVar(&cmd.importACL)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the backup archive into the repository.
+func (cmd *RepoImportCommand) Run() error {
+	if cmd.onConflict != conflictSkip && cmd.onConflict != conflictOverwrite && cmd.onConflict != conflictRename {
+		return ErrInvalidConflictStrategy(cmd.onConflict)
+	}
+
+	f, err := os.Open(cmd.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	passphrase, err := readBackupPassphrase(cmd.io, cmd.passphraseFile, false)
+	if err != nil {
+		return err
+	}
+
+	archive, err := backup.Read(f, passphrase)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return cmd.importArchive(client, archive)
+}
+
+// importArchive writes the contents of the archive to the repository.
+func (cmd *RepoImportCommand) importArchive(client secrethub.ClientInterface, archive *backup.Archive) error {
+	if cmd.createRepo {
+		_, err := client.Repos().Create(cmd.path.Value())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Created repository %s\n", cmd.path)
+	}
+
+	for _, dir := range archive.Dirs {
+		dirPath := cmd.path.String() + "/" + dir
+		exists, err := client.Dirs().Exists(dirPath)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		_, err = client.Dirs().Create(dirPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	var written, skipped, renamed int
+	for _, secret := range archive.Secrets {
+		secretPath := cmd.path.String() + "/" + secret.Path
+		exists, err := client.Secrets().Exists(secretPath)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			switch cmd.onConflict {
+			case conflictSkip:
+				fmt.Fprintf(cmd.io.Output(), "Skipped %s: the secret already exists\n", secretPath)
+				skipped++
+				continue
+			case conflictRename:
+				secretPath, err = cmd.freePath(client, secretPath)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.io.Output(), "Renamed %s to %s: the secret already exists\n", cmd.path.String()+"/"+secret.Path, secretPath)
+				renamed++
+			}
+		}
+
+		for _, version := range secret.Versions {
+			_, err = client.Secrets().Write(secretPath, version.Data)
+			if err != nil {
+				return err
+			}
+		}
+		written++
+	}
+
+	if cmd.importACL {
+		for _, rule := range archive.AccessRules {
+			dirPath := cmd.path.String()
+			if rule.Path != "" {
+				dirPath += "/" + rule.Path
+			}
+
+			_, err := client.AccessRules().Set(dirPath, rule.Permission, rule.Account)
+			if err != nil {
+				fmt.Fprintf(cmd.io.Output(), "Could not restore the %s access rule for %s on %s: %s\n", rule.Permission, rule.Account, dirPath, err)
+			}
+		}
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Import complete! Secrets: %d written (%d renamed), %d skipped.\n", written, renamed, skipped)
+	return nil
+}
+
+// freePath returns the first path with the rename suffix at which no secret exists yet.
+func (cmd *RepoImportCommand) freePath(client secrethub.ClientInterface, secretPath string) (string, error) {
+	candidate := secretPath + importRenameSuffix
+	for i := 2; ; i++ {
+		exists, err := client.Secrets().Exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s%s_%d", secretPath, importRenameSuffix, i)
+	}
+}
@@ -0,0 +1,126 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/backup"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// importTestClient is a fake client that uses importTestSecretService for its secrets.
+type importTestClient struct {
+	fakeclient.Client
+	secrets importTestSecretService
+}
+
+// Secrets implements the secrethub.ClientInterface interface.
+func (c importTestClient) Secrets() secrethub.SecretService {
+	return c.secrets
+}
+
+// importTestSecretService is a fake secret service that reports the configured paths as existing secrets.
+type importTestSecretService struct {
+	*fakeclient.SecretService
+	existing map[string]bool
+}
+
+// Exists returns whether the given path is configured as existing.
+func (s importTestSecretService) Exists(path string) (bool, error) {
+	return s.existing[path], nil
+}
+
+func TestRepoImportCommand_importArchive(t *testing.T) {
+	archive := &backup.Archive{
+		Dirs: []string{"dir"},
+		Secrets: []backup.Secret{
+			{
+				Path: "dir/existing",
+				Versions: []backup.SecretVersion{
+					{Version: 1, Data: []byte("v1")},
+				},
+			},
+			{
+				Path: "new",
+				Versions: []backup.SecretVersion{
+					{Version: 1, Data: []byte("v1")},
+					{Version: 2, Data: []byte("v2")},
+				},
+			},
+		},
+	}
+
+	existing := map[string]bool{
+		"namespace/repo/dir/existing":          true,
+		"namespace/repo/dir/existing_imported": true,
+	}
+
+	cases := map[string]struct {
+		onConflict string
+		written    []string
+		out        string
+	}{
+		"skip": {
+			onConflict: conflictSkip,
+			written:    []string{"namespace/repo/new:v1", "namespace/repo/new:v2"},
+			out: "Skipped namespace/repo/dir/existing: the secret already exists\n" +
+				"Import complete! Secrets: 1 written (0 renamed), 1 skipped.\n",
+		},
+		"overwrite": {
+			onConflict: conflictOverwrite,
+			written:    []string{"namespace/repo/dir/existing:v1", "namespace/repo/new:v1", "namespace/repo/new:v2"},
+			out:        "Import complete! Secrets: 2 written (0 renamed), 0 skipped.\n",
+		},
+		"rename": {
+			onConflict: conflictRename,
+			written:    []string{"namespace/repo/dir/existing_imported_2:v1", "namespace/repo/new:v1", "namespace/repo/new:v2"},
+			out: "Renamed namespace/repo/dir/existing to namespace/repo/dir/existing_imported_2: the secret already exists\n" +
+				"Import complete! Secrets: 2 written (1 renamed), 0 skipped.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written, created []string
+			io := fakeui.NewIO(t)
+			cmd := RepoImportCommand{
+				path:       "namespace/repo",
+				onConflict: tc.onConflict,
+				io:         io,
+			}
+			client := importTestClient{
+				Client: fakeclient.Client{
+					DirService: &fakeclient.DirService{
+						ExistsFunc: func(path string) (bool, error) {
+							return false, nil
+						},
+						CreateFunc: func(path string) (*api.Dir, error) {
+							created = append(created, path)
+							return nil, nil
+						},
+					},
+				},
+				secrets: importTestSecretService{
+					SecretService: &fakeclient.SecretService{
+						WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+							written = append(written, path+":"+string(data))
+							return nil, nil
+						},
+					},
+					existing: existing,
+				},
+			}
+
+			err := cmd.importArchive(client, archive)
+
+			assert.OK(t, err)
+			assert.Equal(t, created, []string{"namespace/repo/dir"})
+			assert.Equal(t, written, tc.written)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -1,9 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-
-	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
@@ -14,6 +11,7 @@ import (
 type RepoInspectCommand struct {
 	path          api.RepoPath
 	timeFormatter TimeFormatter
+	output        string
 	io            ui.IO
 	newClient     newClientFunc
 }
@@ -31,6 +29,7 @@ func NewRepoInspectCommand(io ui.IO, newClient newClientFunc) *RepoInspectComman
 func (cmd *RepoInspectCommand) Register(r command.Registerer) {
 	clause := r.Command("inspect", "Show the details of a repository.")
 	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	registerOutputFlag(clause, &cmd.output, formatJSON, formatYAML)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -57,14 +56,7 @@ func (cmd *RepoInspectCommand) Run() error {
 		return err
 	}
 
-	output, err := cli.PrettyJSON(newInspectRepoOutput(repo, users, services, cmd.timeFormatter))
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.io.Output(), output)
-
-	return nil
+	return writeOutput(cmd.io.Output(), cmd.output, newInspectRepoOutput(repo, users, services, cmd.timeFormatter))
 }
 
 func newInspectRepoOutput(repo *api.Repo, users []*api.User, services []*api.Service, timeFormatter TimeFormatter) inspectRepoOutput {
@@ -2,6 +2,7 @@ package secrethub
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -13,16 +14,22 @@ import (
 type RepoInviteCommand struct {
 	path      api.RepoPath
 	username  string
+	expiresIn time.Duration
+	expires   bool
 	force     bool
 	io        ui.IO
+	invites   func() inviteStore
 	newClient newClientFunc
 }
 
 // NewRepoInviteCommand creates a new RepoInviteCommand.
-func NewRepoInviteCommand(io ui.IO, newClient newClientFunc) *RepoInviteCommand {
+func NewRepoInviteCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *RepoInviteCommand {
 	return &RepoInviteCommand{
 		io:        io,
 		newClient: newClient,
+		invites: func() inviteStore {
+			return newInviteStore(credentialStore.ConfigDir())
+		},
 	}
 }
 
@@ -31,6 +38,7 @@ func (cmd *RepoInviteCommand) Register(r command.Registerer) {
 	clause := r.Command("invite", "Invite a user to collaborate on a repository.")
 	clause.Arg("repo-path", "The repository to invite the user to").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
 	clause.Arg("username", "username of the user").Required().StringVar(&cmd.username)
+	clause.Flag("expires-in", "Let the invite expire after the given duration (e.g. 7d). The invite is tracked locally and can be revoked with `repo invites revoke --expired` once it has expired.").IsSetByUser(&cmd.expires).SetValue((*durationValue)(&cmd.expiresIn))
 	registerForceFlag(clause).BoolVar(&cmd.force)
 
 	command.BindAction(clause, cmd.Run)
@@ -38,6 +46,10 @@ func (cmd *RepoInviteCommand) Register(r command.Registerer) {
 
 // Run invites the configured user to collaborate on the repo.
 func (cmd *RepoInviteCommand) Run() error {
+	if cmd.expires && cmd.expiresIn <= 0 {
+		return ErrInvalidExpiry
+	}
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
@@ -60,17 +72,36 @@ func (cmd *RepoInviteCommand) Run() error {
 
 		if !confirmed {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 	}
-	fmt.Fprintln(cmd.io.Output(), "Inviting user...")
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Inviting user...")
 
 	_, err = client.Repos().Users().Invite(cmd.path.Value(), cmd.username)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Invite complete! The user %s is now a member of the %s repository.\n", cmd.username, cmd.path)
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Invite complete! The user %s is now a member of the %s repository.\n", cmd.username, cmd.path)
+
+	if cmd.expires {
+		invitedAt := time.Now().UTC()
+		invite := repoInvite{
+			Repo:      cmd.path.Value(),
+			Username:  cmd.username,
+			InvitedAt: invitedAt,
+			ExpiresAt: invitedAt.Add(cmd.expiresIn),
+		}
+
+		err = cmd.invites().Add(invite)
+		if err != nil {
+			// The invite itself succeeded, so only warn about the expiry not being tracked.
+			fmt.Fprintf(cmd.io.Output(), "WARNING: the expiry of the invite could not be recorded: %s\n", err)
+			return nil
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "The invite expires at %s.\n", invite.ExpiresAt.Local().Format(time.RFC3339))
+	}
 
 	return nil
 }
@@ -1,7 +1,9 @@
 package secrethub
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
 
@@ -114,3 +116,78 @@ func TestRepoInviteCommand_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoInviteCommand_Run_ExpiresIn(t *testing.T) {
+	cases := map[string]struct {
+		expiresIn   time.Duration
+		err         error
+		out         string
+		expectTrack bool
+	}{
+		"tracked": {
+			expiresIn:   7 * 24 * time.Hour,
+			out:         "Inviting user...\nInvite complete! The user dev1 is now a member of the dev2/repo repository.\n",
+			expectTrack: true,
+		},
+		"zero duration": {
+			expiresIn: 0,
+			err:       ErrInvalidExpiry,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			store := inviteStore{jsonFile{path: filepath.Join(dir, invitesFilename)}}
+
+			fakeIO := fakeui.NewIO(t)
+			cmd := RepoInviteCommand{
+				path:      "dev2/repo",
+				username:  "dev1",
+				force:     true,
+				expiresIn: tc.expiresIn,
+				expires:   true,
+				io:        fakeIO,
+				invites: func() inviteStore {
+					return store
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						RepoService: &fakeclient.RepoService{
+							UserService: &fakeclient.RepoUserService{
+								InviteFunc: func(path string, username string) (*api.RepoMember, error) {
+									return &api.RepoMember{}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			// Run
+			before := time.Now().UTC()
+			err := cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+
+			invites, err := store.List()
+			assert.OK(t, err)
+			if !tc.expectTrack {
+				assert.Equal(t, len(invites), 0)
+				assert.Equal(t, fakeIO.Out.String(), "")
+				return
+			}
+
+			assert.Equal(t, len(invites), 1)
+			invite := invites[0]
+			assert.Equal(t, invite.Repo, "dev2/repo")
+			assert.Equal(t, invite.Username, "dev1")
+			assert.Equal(t, invite.InvitedAt.Before(before), false)
+			assert.Equal(t, invite.ExpiresAt.Sub(invite.InvitedAt), tc.expiresIn)
+			assert.Equal(t, fakeIO.Out.String(), tc.out+"The invite expires at "+invite.ExpiresAt.Local().Format(time.RFC3339)+".\n")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package secrethub
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// invitesFilename is the name of the file in the configuration directory
+// that keeps track of the expiring repository invites sent from this machine.
+const invitesFilename = "invites.json"
+
+// RepoInvitesCommand handles operations on the repository invites tracked by the CLI.
+type RepoInvitesCommand struct {
+	io              ui.IO
+	newClient       newClientFunc
+	credentialStore CredentialConfig
+}
+
+// NewRepoInvitesCommand creates a new RepoInvitesCommand.
+func NewRepoInvitesCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *RepoInvitesCommand {
+	return &RepoInvitesCommand{
+		io:              io,
+		newClient:       newClient,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *RepoInvitesCommand) Register(r command.Registerer) {
+	clause := r.Command("invites", "Manage the expiring repository invites sent with `repo invite --expires-in`.")
+	NewRepoInvitesLsCommand(cmd.io, cmd.credentialStore).Register(clause)
+	NewRepoInvitesRevokeCommand(cmd.io, cmd.newClient, cmd.credentialStore).Register(clause)
+}
+
+// repoInvite is an invite of a user to a repository with an expiry.
+type repoInvite struct {
+	Repo      string    `json:"repo"`
+	Username  string    `json:"username"`
+	InvitedAt time.Time `json:"invited_at"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// isExpired returns whether the invite has an expiry that has passed at the given time.
+func (i repoInvite) isExpired(now time.Time) bool {
+	return !i.ExpiresAt.IsZero() && !now.Before(i.ExpiresAt)
+}
+
+// inviteStore keeps track of repository invites in a file in the configuration directory.
+type inviteStore struct {
+	jsonFile
+}
+
+// newInviteStore creates an inviteStore that stores its invites in the given configuration directory.
+func newInviteStore(dir configdir.Dir) inviteStore {
+	return inviteStore{
+		jsonFile{path: filepath.Join(dir.Path(), invitesFilename)},
+	}
+}
+
+// List returns all tracked invites. When no invites have been stored yet, an empty list is returned.
+func (s inviteStore) List() ([]repoInvite, error) {
+	invites := []repoInvite{}
+	err := s.read(&invites)
+	if err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// Add starts tracking the given invite, replacing any invite for the same user to the same repository.
+func (s inviteStore) Add(invite repoInvite) error {
+	invites := []repoInvite{}
+	return s.update(&invites, func() {
+		res := []repoInvite{invite}
+		for _, i := range invites {
+			if i.Repo != invite.Repo || i.Username != invite.Username {
+				res = append(res, i)
+			}
+		}
+		invites = res
+	})
+}
+
+// Save overwrites the tracked invites with the given invites.
+func (s inviteStore) Save(invites []repoInvite) error {
+	return s.write(invites)
+}
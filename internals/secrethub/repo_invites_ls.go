@@ -0,0 +1,91 @@
+package secrethub
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// RepoInvitesLsCommand lists the tracked repository invites.
+type RepoInvitesLsCommand struct {
+	path          api.RepoPath
+	useTimestamps bool
+	timeFormatter TimeFormatter
+	io            ui.IO
+	invites       func() inviteStore
+	now           func() time.Time
+}
+
+// NewRepoInvitesLsCommand creates a new RepoInvitesLsCommand.
+func NewRepoInvitesLsCommand(io ui.IO, credentialStore CredentialConfig) *RepoInvitesLsCommand {
+	return &RepoInvitesLsCommand{
+		io: io,
+		invites: func() inviteStore {
+			return newInviteStore(credentialStore.ConfigDir())
+		},
+		now: time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoInvitesLsCommand) Register(r command.Registerer) {
+	clause := r.Command("ls", "List the expiring repository invites sent from this machine.")
+	clause.Alias("list")
+	clause.Arg("repo-path", "Only list the invites to this repository").PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run lists the tracked repository invites.
+func (cmd *RepoInvitesLsCommand) Run() error {
+	cmd.beforeRun()
+	return cmd.run()
+}
+
+// beforeRun configures the command using the flag values.
+func (cmd *RepoInvitesLsCommand) beforeRun() {
+	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+}
+
+func (cmd *RepoInvitesLsCommand) run() error {
+	invites, err := cmd.invites().List()
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "REPO", "USERNAME", "INVITED", "EXPIRES", "STATUS")
+
+	for _, invite := range invites {
+		if cmd.path != "" && invite.Repo != cmd.path.Value() {
+			continue
+		}
+
+		expires := "never"
+		if !invite.ExpiresAt.IsZero() {
+			expires = invite.ExpiresAt.Local().Format(time.RFC3339)
+		}
+
+		status := "active"
+		if invite.isExpired(now) {
+			status = "expired"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			invite.Repo,
+			invite.Username,
+			cmd.timeFormatter.Format(invite.InvitedAt.Local()),
+			expires,
+			status,
+		)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,91 @@
+package secrethub
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/fakes"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRepoInvitesLsCommand_run(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	expired := repoInvite{
+		Repo:      "namespace/repo",
+		Username:  "contractor",
+		InvitedAt: now.Add(-8 * 24 * time.Hour),
+		ExpiresAt: now.Add(-24 * time.Hour),
+	}
+	active := repoInvite{
+		Repo:      "namespace/other",
+		Username:  "dev1",
+		InvitedAt: now.Add(-time.Hour),
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	// The width of the EXPIRES column depends on the local time zone.
+	expiresPadding := strings.Repeat(" ", len(active.ExpiresAt.Local().Format(time.RFC3339))-len("EXPIRES")+2)
+
+	cases := map[string]struct {
+		path    api.RepoPath
+		invites []repoInvite
+		out     string
+	}{
+		"no invites": {
+			out: "REPO  USERNAME  INVITED  EXPIRES  STATUS\n",
+		},
+		"all invites": {
+			invites: []repoInvite{expired, active},
+			out: "REPO             USERNAME    INVITED     EXPIRES" + expiresPadding + "STATUS\n" +
+				"namespace/repo   contractor  1 hour ago  " + expired.ExpiresAt.Local().Format(time.RFC3339) + "  expired\n" +
+				"namespace/other  dev1        1 hour ago  " + active.ExpiresAt.Local().Format(time.RFC3339) + "  active\n",
+		},
+		"filter on repo": {
+			path:    "namespace/other",
+			invites: []repoInvite{expired, active},
+			out: "REPO             USERNAME  INVITED     EXPIRES" + expiresPadding + "STATUS\n" +
+				"namespace/other  dev1      1 hour ago  " + active.ExpiresAt.Local().Format(time.RFC3339) + "  active\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			store := inviteStore{jsonFile{path: filepath.Join(dir, invitesFilename)}}
+			if tc.invites != nil {
+				err := store.Save(tc.invites)
+				assert.OK(t, err)
+			}
+
+			fakeIO := fakeui.NewIO(t)
+			cmd := RepoInvitesLsCommand{
+				path: tc.path,
+				io:   fakeIO,
+				invites: func() inviteStore {
+					return store
+				},
+				now: func() time.Time {
+					return now
+				},
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "1 hour ago",
+				},
+			}
+
+			// Run
+			err := cmd.run()
+
+			// Assert
+			assert.OK(t, err)
+			assert.Equal(t, fakeIO.Out.String(), tc.out)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package secrethub
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrNoInvitesSelected = errMain.Code("no_invites_selected").Error("provide a username or use the --expired flag to select the invites to revoke")
+	ErrInviteNotFound    = errMain.Code("invite_not_found").ErrorPref("no invite for %s to %s is tracked on this machine")
+)
+
+// RepoInvitesRevokeCommand revokes the access of invited users to a repository.
+type RepoInvitesRevokeCommand struct {
+	path      api.RepoPath
+	username  string
+	expired   bool
+	force     bool
+	io        ui.IO
+	invites   func() inviteStore
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewRepoInvitesRevokeCommand creates a new RepoInvitesRevokeCommand.
+func NewRepoInvitesRevokeCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *RepoInvitesRevokeCommand {
+	return &RepoInvitesRevokeCommand{
+		io:        io,
+		newClient: newClient,
+		invites: func() inviteStore {
+			return newInviteStore(credentialStore.ConfigDir())
+		},
+		now: time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoInvitesRevokeCommand) Register(r command.Registerer) {
+	clause := r.Command("revoke", "Revoke the access of invited users to a repository and stop tracking their invites. Use `repo revoke` to see which secrets should be rotated.")
+	clause.Arg("repo-path", "The repository the users were invited to").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Arg("username", "The invited user to revoke").StringVar(&cmd.username)
+	clause.Flag("expired", "Revoke all invites to the repository that have expired.").BoolVar(&cmd.expired)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run revokes the selected invites.
+func (cmd *RepoInvitesRevokeCommand) Run() error {
+	if cmd.username == "" && !cmd.expired {
+		return ErrNoInvitesSelected
+	}
+
+	store := cmd.invites()
+	invites, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	var selected, remaining []repoInvite
+	for _, invite := range invites {
+		if invite.Repo == cmd.path.Value() &&
+			(invite.Username == cmd.username || (cmd.expired && invite.isExpired(now))) {
+			selected = append(selected, invite)
+		} else {
+			remaining = append(remaining, invite)
+		}
+	}
+
+	if len(selected) == 0 {
+		if cmd.username != "" {
+			return ErrInviteNotFound(cmd.username, cmd.path)
+		}
+		fmt.Fprintln(cmd.io.Output(), "No expired invites found.")
+		return nil
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.AskYesNo(
+			cmd.io,
+			fmt.Sprintf("Are you sure you want to revoke %s from the repository %s?", pluralize("user", "users", len(selected)), cmd.path),
+			ui.DefaultNo,
+		)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return ErrAborted
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	for _, invite := range selected {
+		revoked, err := client.Repos().Users().Revoke(invite.Repo, invite.Username)
+		if err != nil {
+			fmt.Fprintf(cmd.io.Output(), "Could not revoke %s from %s: %s\n", invite.Username, invite.Repo, err)
+			remaining = append(remaining, invite)
+			continue
+		}
+
+		if revoked.Status == api.StatusFailed {
+			fmt.Fprintf(cmd.io.Output(), "Could not revoke %s from %s: the user is the only admin of the repository.\n", invite.Username, invite.Repo)
+			remaining = append(remaining, invite)
+			continue
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "Revoked %s from %s.\n", invite.Username, invite.Repo)
+	}
+
+	return store.Save(remaining)
+}
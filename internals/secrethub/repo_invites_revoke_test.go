@@ -0,0 +1,125 @@
+package secrethub
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestRepoInvitesRevokeCommand_Run(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	expired := repoInvite{
+		Repo:      "namespace/repo",
+		Username:  "contractor",
+		InvitedAt: now.Add(-8 * 24 * time.Hour),
+		ExpiresAt: now.Add(-24 * time.Hour),
+	}
+	active := repoInvite{
+		Repo:      "namespace/repo",
+		Username:  "dev1",
+		InvitedAt: now.Add(-time.Hour),
+	}
+	otherRepo := repoInvite{
+		Repo:      "namespace/other",
+		Username:  "contractor",
+		InvitedAt: now.Add(-8 * 24 * time.Hour),
+		ExpiresAt: now.Add(-24 * time.Hour),
+	}
+
+	cases := map[string]struct {
+		cmd       RepoInvitesRevokeCommand
+		invites   []repoInvite
+		revoked   []string
+		remaining []repoInvite
+		out       string
+		err       error
+	}{
+		"nothing selected": {
+			cmd: RepoInvitesRevokeCommand{
+				path: "namespace/repo",
+			},
+			err: ErrNoInvitesSelected,
+		},
+		"unknown user": {
+			cmd: RepoInvitesRevokeCommand{
+				path:     "namespace/repo",
+				username: "unknown",
+			},
+			invites:   []repoInvite{active},
+			remaining: []repoInvite{active},
+			err:       ErrInviteNotFound("unknown", api.RepoPath("namespace/repo")),
+		},
+		"revoke expired": {
+			cmd: RepoInvitesRevokeCommand{
+				path:    "namespace/repo",
+				expired: true,
+				force:   true,
+			},
+			invites:   []repoInvite{expired, active, otherRepo},
+			revoked:   []string{"namespace/repo:contractor"},
+			remaining: []repoInvite{active, otherRepo},
+			out:       "Revoked contractor from namespace/repo.\n",
+		},
+		"revoke user": {
+			cmd: RepoInvitesRevokeCommand{
+				path:     "namespace/repo",
+				username: "dev1",
+				force:    true,
+			},
+			invites:   []repoInvite{expired, active},
+			revoked:   []string{"namespace/repo:dev1"},
+			remaining: []repoInvite{expired},
+			out:       "Revoked dev1 from namespace/repo.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			store := inviteStore{jsonFile{path: filepath.Join(dir, invitesFilename)}}
+			if tc.invites != nil {
+				err := store.Save(tc.invites)
+				assert.OK(t, err)
+			}
+
+			var revoked []string
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+			tc.cmd.invites = func() inviteStore { return store }
+			tc.cmd.now = func() time.Time { return now }
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					RepoService: &fakeclient.RepoService{
+						UserService: &fakeclient.RepoUserService{
+							RevokeFunc: func(path string, username string) (*api.RevokeRepoResponse, error) {
+								revoked = append(revoked, path+":"+username)
+								return &api.RevokeRepoResponse{Status: api.StatusOK}, nil
+							},
+						},
+					},
+				}, nil
+			}
+
+			err := tc.cmd.Run()
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+			assert.Equal(t, revoked, tc.revoked)
+
+			if tc.remaining != nil {
+				remaining, err := store.List()
+				assert.OK(t, err)
+				assert.Equal(t, remaining, tc.remaining)
+			}
+		})
+	}
+}
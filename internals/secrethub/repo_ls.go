@@ -4,17 +4,21 @@ import (
 	"fmt"
 	"sort"
 	"text/tabwriter"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
 
 // RepoLSCommand lists repositories.
 type RepoLSCommand struct {
 	useTimestamps bool
 	quiet         bool
+	all           bool
+	output        string
 	workspace     api.Namespace
 	io            ui.IO
 	timeFormatter TimeFormatter
@@ -31,11 +35,13 @@ func NewRepoLSCommand(io ui.IO, newClient newClientFunc) *RepoLSCommand {
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *RepoLSCommand) Register(r command.Registerer) {
-	clause := r.Command("ls", "List all repositories you have access to.")
+	clause := r.Command("ls", "List all repositories you have access to. Archived repositories have the status archived.")
 	clause.Alias("list")
-	clause.Flag("quiet", "Only print paths.").Short('q').BoolVar(&cmd.quiet)
 	clause.Arg("workspace", "When supplied, results are limited to repositories in this workspace.").SetValue(&cmd.workspace)
+	clause.Flag("all", "List the repositories in all namespaces you have access to, including the repositories of organizations you are an admin of "+
+		"that you are not a member of, together with your role in their namespace. Use `namespaces ls` to list the namespaces themselves.").BoolVar(&cmd.all)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFlag(clause, &cmd.output, formatTable, formatJSON, formatYAML)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -49,6 +55,7 @@ func (cmd *RepoLSCommand) Run() error {
 // beforeRun configures the command using the flag values.
 func (cmd *RepoLSCommand) beforeRun() {
 	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+	cmd.quiet = cmd.quiet || quietOutput
 }
 
 // run lists the repositories a user has access to.
@@ -58,8 +65,24 @@ func (cmd *RepoLSCommand) run() error {
 		return err
 	}
 
+	if cmd.all && cmd.workspace != "" {
+		return ErrFlagsConflict("--all and workspace")
+	}
+
 	var list []*api.Repo
-	if cmd.workspace == "" {
+	// roles maps the namespaces to the role of the account in them when all namespaces are listed.
+	var roles map[string]string
+	if cmd.all {
+		namespaces, err := listAccessibleNamespaces(client)
+		if err != nil {
+			return err
+		}
+		roles = make(map[string]string, len(namespaces))
+		for _, namespace := range namespaces {
+			roles[namespace.name] = namespace.role
+			list = append(list, namespace.repos...)
+		}
+	} else if cmd.workspace == "" {
 		list, err = client.Repos().ListMine()
 		if err != nil {
 			return err
@@ -73,15 +96,49 @@ func (cmd *RepoLSCommand) run() error {
 
 	sort.Sort(api.SortRepoByName(list))
 
-	if cmd.quiet {
+	switch {
+	case cmd.output != formatTable && cmd.output != "":
+		out := make([]repoLSOutput, len(list))
+		for i, repo := range list {
+			status, err := repoStatus(client, repo)
+			if err != nil {
+				return err
+			}
+			out[i] = repoLSOutput{
+				Path:          repo.Path().String(),
+				Status:        status,
+				CreatedAt:     repo.CreatedAt.UTC().Format(time.RFC3339),
+				NamespaceRole: roles[repo.Owner],
+			}
+		}
+		return writeOutput(cmd.io.Output(), cmd.output, out)
+	case cmd.quiet:
 		for _, repo := range list {
 			fmt.Fprintf(cmd.io.Output(), "%s\n", repo.Path())
 		}
-	} else {
+	case cmd.all:
+		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "NAME", "STATUS", "NAMESPACE ROLE", "CREATED")
+		for _, repo := range list {
+			status, err := repoStatus(client, repo)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", repo.Path(), status, roles[repo.Owner], cmd.timeFormatter.Format(repo.CreatedAt.Local()))
+		}
+		err = w.Flush()
+		if err != nil {
+			return err
+		}
+	default:
 		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
 		fmt.Fprintf(w, "%s\t%s\t%s\n", "NAME", "STATUS", "CREATED")
 		for _, repo := range list {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", repo.Path(), repo.Status, cmd.timeFormatter.Format(repo.CreatedAt.Local()))
+			status, err := repoStatus(client, repo)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", repo.Path(), status, cmd.timeFormatter.Format(repo.CreatedAt.Local()))
 		}
 		err = w.Flush()
 		if err != nil {
@@ -91,3 +148,24 @@ func (cmd *RepoLSCommand) run() error {
 
 	return nil
 }
+
+// repoStatus returns the status of the repository, which is archived for archived repositories.
+func repoStatus(client secrethub.ClientInterface, repo *api.Repo) (string, error) {
+	archived, err := isRepoArchived(client, repo.Path())
+	if err != nil {
+		return "", err
+	}
+	if archived {
+		return "archived", nil
+	}
+	return repo.Status, nil
+}
+
+// repoLSOutput is the machine readable format of a listed repository.
+type repoLSOutput struct {
+	Path      string
+	Status    string
+	CreatedAt string
+	// NamespaceRole is the role of the account in the namespace of the repository, which is only set with --all.
+	NamespaceRole string `json:",omitempty"`
+}
@@ -22,6 +22,7 @@ func TestRepoLSCommand_run(t *testing.T) {
 		cmd          RepoLSCommand
 		newClientErr error
 		repoService  fakeclient.RepoService
+		getSecret    func(path string) (*api.Secret, error)
 		out          string
 		err          error
 	}{
@@ -103,6 +104,90 @@ func TestRepoLSCommand_run(t *testing.T) {
 			out: "NAME             STATUS  CREATED\n" +
 				"dev1/repository  ok      2018-01-01T01:01:01+01:00\n",
 		},
+		"archived repo": {
+			cmd: RepoLSCommand{
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-01T01:01:01+01:00",
+				},
+			},
+			repoService: fakeclient.RepoService{
+				ListMineFunc: func() ([]*api.Repo, error) {
+					return []*api.Repo{
+						{
+							Owner:     "dev1",
+							Name:      "archived",
+							Status:    api.StatusOK,
+							CreatedAt: testTime,
+						},
+						{
+							Owner:     "dev1",
+							Name:      "repository",
+							Status:    api.StatusOK,
+							CreatedAt: testTime,
+						},
+					}, nil
+				},
+			},
+			getSecret: func(path string) (*api.Secret, error) {
+				if path == "dev1/archived/.archived" {
+					return &api.Secret{}, nil
+				}
+				return nil, api.ErrSecretNotFound
+			},
+			out: "NAME             STATUS    CREATED\n" +
+				"dev1/archived    archived  2018-01-01T01:01:01+01:00\n" +
+				"dev1/repository  ok        2018-01-01T01:01:01+01:00\n",
+		},
+		"json": {
+			cmd: RepoLSCommand{
+				output: formatJSON,
+			},
+			repoService: fakeclient.RepoService{
+				ListMineFunc: func() ([]*api.Repo, error) {
+					return []*api.Repo{
+						{
+							Owner:     "dev1",
+							Name:      "archived",
+							Status:    api.StatusOK,
+							CreatedAt: testTime,
+						},
+					}, nil
+				},
+			},
+			getSecret: func(path string) (*api.Secret, error) {
+				return &api.Secret{}, nil
+			},
+			out: "[\n" +
+				"    {\n" +
+				"        \"Path\": \"dev1/archived\",\n" +
+				"        \"Status\": \"archived\",\n" +
+				"        \"CreatedAt\": \"2018-01-01T01:01:01Z\"\n" +
+				"    }\n" +
+				"]\n",
+		},
+		"archive marker error": {
+			repoService: fakeclient.RepoService{
+				ListMineFunc: func() ([]*api.Repo, error) {
+					return []*api.Repo{
+						{
+							Owner: "dev1",
+							Name:  "repository",
+						},
+					}, nil
+				},
+			},
+			getSecret: func(path string) (*api.Secret, error) {
+				return nil, testErr
+			},
+			err: testErr,
+		},
+		"all and workspace": {
+			cmd: RepoLSCommand{
+				all:       true,
+				workspace: "dev1",
+			},
+			err: ErrFlagsConflict("--all and workspace"),
+		},
 		"new client error": {
 			newClientErr: testErr,
 			err:          testErr,
@@ -128,9 +213,18 @@ func TestRepoLSCommand_run(t *testing.T) {
 					return nil, tc.newClientErr
 				}
 			} else {
+				getSecret := tc.getSecret
+				if getSecret == nil {
+					getSecret = func(path string) (*api.Secret, error) {
+						return nil, api.ErrSecretNotFound
+					}
+				}
 				tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
 					return fakeclient.Client{
 						RepoService: &tc.repoService,
+						SecretService: &fakeclient.SecretService{
+							GetFunc: getSecret,
+						},
 					}, nil
 				}
 			}
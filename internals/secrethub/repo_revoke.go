@@ -3,12 +3,21 @@ package secrethub
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"text/tabwriter"
 
+	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrAccountNotRepoMember = errMain.Code("account_not_repo_member").ErrorPref("%s is not a member of the %s repository")
 )
 
 // RepoRevokeCommand handles revoking an account access to a repository.
@@ -16,6 +25,8 @@ type RepoRevokeCommand struct {
 	accountName api.AccountName
 	path        api.RepoPath
 	force       bool
+	dryRun      bool
+	format      string
 	io          ui.IO
 	newClient   newClientFunc
 }
@@ -34,6 +45,8 @@ func (cmd *RepoRevokeCommand) Register(r command.Registerer) {
 	clause.Arg("repo-path", "The repository to revoke the account from").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
 	clause.Arg("account-name", "The account name (username or service name) to revoke access for").Required().SetValue(&cmd.accountName)
 	registerForceFlag(clause).BoolVar(&cmd.force)
+	clause.Flag("dry-run", "Do not revoke the account, but print which access rules would be removed, which secrets would be flagged for rotation and which services are affected.").BoolVar(&cmd.dryRun)
+	clause.Flag("output", "Specify the format in which to output the --dry-run report. Options are: table and json.").HintOptions(formatTable, formatJSON).Default(formatTable).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -45,6 +58,10 @@ func (cmd *RepoRevokeCommand) Run() error {
 		return err
 	}
 
+	if cmd.dryRun {
+		return cmd.printBlastRadius(client)
+	}
+
 	var prettyName string
 	if cmd.accountName.IsUser() {
 		user, err := client.Users().Get(string(cmd.accountName))
@@ -71,11 +88,11 @@ func (cmd *RepoRevokeCommand) Run() error {
 
 		if !confirmed {
 			fmt.Fprintln(cmd.io.Output(), "Aborting.")
-			return nil
+			return ErrAborted
 		}
 	}
 
-	fmt.Fprint(cmd.io.Output(), "Revoking account...\n\n")
+	fmt.Fprint(statusWriter(cmd.io.Output()), "Revoking account...\n\n")
 
 	var revoked *api.RevokeRepoResponse
 	if cmd.accountName.IsService() {
@@ -113,7 +130,7 @@ func (cmd *RepoRevokeCommand) Run() error {
 	if countFlagged > 0 {
 		fmt.Fprintln(cmd.io.Output())
 	}
-	fmt.Fprintf(cmd.io.Output(),
+	fmt.Fprintf(statusWriter(cmd.io.Output()),
 		"Revoke complete! The account %s can no longer access the %s repository. "+
 			"Make sure you overwrite or delete all flagged secrets. "+
 			"Secrets: %d unaffected, %d flagged\n",
@@ -153,3 +170,221 @@ func printFlaggedSecrets(w io.Writer, dir *api.Dir, prePath string) (int, int) {
 
 	return countUnaffected, countFlagged
 }
+
+// revokeBlastRadius describes the impact of revoking an account from a repository.
+// ServicesLosingAccess contains the service itself when a service is revoked.
+// CredentialsToRotate contains the services on the repository created by a revoked
+// user, as the user may still hold a copy of their credentials.
+type revokeBlastRadius struct {
+	Account               string
+	Repo                  string
+	AccessRules           []revokeAccessRule
+	FlaggedSecrets        []string
+	UnaffectedSecretCount int
+	ServicesLosingAccess  []revokeService
+	CredentialsToRotate   []revokeService
+}
+
+// revokeAccessRule is an access rule that is removed when revoking an account.
+type revokeAccessRule struct {
+	Path       string
+	Permission string
+}
+
+// revokeService is a service affected by revoking an account.
+type revokeService struct {
+	ID          string
+	Description string
+}
+
+// printBlastRadius prints which access rules would be removed, which secrets would be flagged
+// for rotation, which services would lose access and which service credentials should be rotated
+// when revoking the account, without making any changes. Secrets are flagged when they are in a
+// directory on which the account has an access rule, as the account could have read them.
+func (cmd *RepoRevokeCommand) printBlastRadius(client secrethub.ClientInterface) error {
+	if cmd.format != formatTable && cmd.format != formatJSON {
+		return errNoSuchFormat(cmd.format)
+	}
+
+	report := revokeBlastRadius{
+		Account:              cmd.accountName.String(),
+		Repo:                 cmd.path.String(),
+		AccessRules:          []revokeAccessRule{},
+		FlaggedSecrets:       []string{},
+		ServicesLosingAccess: []revokeService{},
+		CredentialsToRotate:  []revokeService{},
+	}
+
+	services, err := client.Services().List(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	if cmd.accountName.IsService() {
+		var found bool
+		for _, service := range services {
+			if service.ServiceID == cmd.accountName.String() {
+				found = true
+				report.ServicesLosingAccess = append(report.ServicesLosingAccess, revokeService{
+					ID:          service.ServiceID,
+					Description: service.Description,
+				})
+			}
+		}
+		if !found {
+			return ErrAccountNotRepoMember(cmd.accountName, cmd.path)
+		}
+	} else {
+		users, err := client.Repos().Users().List(cmd.path.Value())
+		if err != nil {
+			return err
+		}
+
+		var member *api.User
+		for _, user := range users {
+			if user.Username == cmd.accountName.String() {
+				member = user
+				break
+			}
+		}
+		if member == nil {
+			return ErrAccountNotRepoMember(cmd.accountName, cmd.path)
+		}
+
+		for _, service := range services {
+			if service.CreatedBy == member.AccountID {
+				report.CredentialsToRotate = append(report.CredentialsToRotate, revokeService{
+					ID:          service.ServiceID,
+					Description: service.Description,
+				})
+			}
+		}
+		sort.Slice(report.CredentialsToRotate, func(i, j int) bool {
+			return report.CredentialsToRotate[i].ID < report.CredentialsToRotate[j].ID
+		})
+	}
+
+	rules, err := client.AccessRules().List(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	var accessibleDirs []string
+	for _, rule := range rules {
+		if rule.Account == nil || rule.Account.Name != cmd.accountName {
+			continue
+		}
+
+		dirPath, err := tree.AbsDirPath(rule.DirID)
+		if err != nil {
+			return err
+		}
+
+		accessibleDirs = append(accessibleDirs, dirPath.String())
+		report.AccessRules = append(report.AccessRules, revokeAccessRule{
+			Path:       dirPath.String(),
+			Permission: rule.Permission.String(),
+		})
+	}
+	sort.Slice(report.AccessRules, func(i, j int) bool {
+		return report.AccessRules[i].Path < report.AccessRules[j].Path
+	})
+
+	for _, secret := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secret.SecretID)
+		if err != nil {
+			return err
+		}
+
+		flagged := false
+		for _, dir := range accessibleDirs {
+			if strings.HasPrefix(secretPath.String(), dir+"/") {
+				flagged = true
+				break
+			}
+		}
+
+		if flagged {
+			report.FlaggedSecrets = append(report.FlaggedSecrets, secretPath.String())
+		} else {
+			report.UnaffectedSecretCount++
+		}
+	}
+	sort.Strings(report.FlaggedSecrets)
+
+	if cmd.format == formatJSON {
+		output, err := cli.PrettyJSON(report)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.io.Output(), output)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Revoking %s from the %s repository would:\n\n", report.Account, report.Repo)
+
+	ruleRows := make([][]string, len(report.AccessRules))
+	for i, rule := range report.AccessRules {
+		ruleRows[i] = []string{rule.Path, rule.Permission}
+	}
+	err = printBlastRadiusSection(cmd.io.Output(), "Remove the access rules:", ruleRows)
+	if err != nil {
+		return err
+	}
+
+	secretRows := make([][]string, len(report.FlaggedSecrets))
+	for i, secretPath := range report.FlaggedSecrets {
+		secretRows[i] = []string{secretPath}
+	}
+	err = printBlastRadiusSection(cmd.io.Output(), "Flag the following secrets for rotation:", secretRows)
+	if err != nil {
+		return err
+	}
+
+	err = printBlastRadiusSection(cmd.io.Output(), "Revoke the access of the services:", serviceRows(report.ServicesLosingAccess))
+	if err != nil {
+		return err
+	}
+
+	err = printBlastRadiusSection(cmd.io.Output(), fmt.Sprintf("Require rotating the credentials of the services created by %s:", report.Account), serviceRows(report.CredentialsToRotate))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Secrets: %d unaffected, %d to flag\n", report.UnaffectedSecretCount, len(report.FlaggedSecrets))
+	return nil
+}
+
+// printBlastRadiusSection prints a titled, indented list of rows, aligning the
+// columns of the rows. Nothing is printed when there are no rows.
+func printBlastRadiusSection(w io.Writer, title string, rows [][]string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w, title)
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(tw, "\t%s\n", strings.Join(row, "\t"))
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// serviceRows converts services to rows of a blast radius section.
+func serviceRows(services []revokeService) [][]string {
+	rows := make([][]string, len(services))
+	for i, service := range services {
+		rows[i] = []string{service.ID, service.Description}
+	}
+	return rows
+}
@@ -25,6 +25,7 @@ func TestRepoRevokeCommand_Run(t *testing.T) {
 		repoService    fakeclient.RepoService
 		userService    fakeclient.UserService
 		serviceService fakeclient.ServiceService
+		accessRules    fakeclient.AccessRuleService
 		newClientErr   error
 		out            string
 		err            error
@@ -332,11 +333,12 @@ func TestRepoRevokeCommand_Run(t *testing.T) {
 			} else {
 				tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
 					return fakeclient.Client{
-						AccountService: &tc.accountService,
-						DirService:     &tc.dirService,
-						RepoService:    &tc.repoService,
-						ServiceService: &tc.serviceService,
-						UserService:    &tc.userService,
+						AccountService:    &tc.accountService,
+						AccessRuleService: &tc.accessRules,
+						DirService:        &tc.dirService,
+						RepoService:       &tc.repoService,
+						ServiceService:    &tc.serviceService,
+						UserService:       &tc.userService,
 					}, nil
 				}
 			}
@@ -353,3 +355,195 @@ func TestRepoRevokeCommand_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoRevokeCommand_printBlastRadius(t *testing.T) {
+	rootDirID := uuid.New()
+	dirID := uuid.New()
+	secret1ID := uuid.New()
+	secret2ID := uuid.New()
+	dev1ID := uuid.New()
+
+	accessRules := fakeclient.AccessRuleService{
+		ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+			return []*api.AccessRule{
+				{
+					Account:    &api.Account{Name: "dev1"},
+					DirID:      dirID,
+					Permission: api.PermissionRead,
+				},
+				{
+					Account:    &api.Account{Name: "s-hTvStO9KaswJ"},
+					DirID:      rootDirID,
+					Permission: api.PermissionRead,
+				},
+				{
+					Account:    &api.Account{Name: "dev2"},
+					DirID:      rootDirID,
+					Permission: api.PermissionAdmin,
+				},
+			}, nil
+		},
+	}
+	dirService := fakeclient.DirService{
+		GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+			rootDir := &api.Dir{
+				DirID: rootDirID,
+				Name:  "repo",
+			}
+			return &api.Tree{
+				ParentPath: "namespace",
+				RootDir:    rootDir,
+				Dirs: map[uuid.UUID]*api.Dir{
+					rootDirID: rootDir,
+					dirID: {
+						DirID:    dirID,
+						ParentID: &rootDirID,
+						Name:     "dir",
+					},
+				},
+				Secrets: map[uuid.UUID]*api.Secret{
+					secret1ID: {
+						SecretID: secret1ID,
+						DirID:    dirID,
+						Name:     "secret",
+					},
+					secret2ID: {
+						SecretID: secret2ID,
+						DirID:    rootDirID,
+						Name:     "other",
+					},
+				},
+			}, nil
+		},
+	}
+	repoService := fakeclient.RepoService{
+		UserService: &fakeclient.RepoUserService{
+			ListFunc: func(path string) ([]*api.User, error) {
+				return []*api.User{
+					{Username: "dev1", AccountID: dev1ID},
+					{Username: "dev2", AccountID: uuid.New()},
+				}, nil
+			},
+		},
+	}
+	serviceService := fakeclient.ServiceService{
+		ListFunc: func(path string) ([]*api.Service, error) {
+			return []*api.Service{
+				{ServiceID: "s-hTvStO9KaswJ", Description: "deploy", CreatedBy: dev1ID},
+				{ServiceID: "s-other", Description: "backup", CreatedBy: uuid.New()},
+			}, nil
+		},
+	}
+
+	cases := map[string]struct {
+		cmd RepoRevokeCommand
+		out string
+		err error
+	}{
+		"user table": {
+			cmd: RepoRevokeCommand{
+				accountName: "dev1",
+				path:        "namespace/repo",
+				format:      formatTable,
+			},
+			out: "Revoking dev1 from the namespace/repo repository would:\n\n" +
+				"Remove the access rules:\n" +
+				"  namespace/repo/dir  read\n\n" +
+				"Flag the following secrets for rotation:\n" +
+				"  namespace/repo/dir/secret\n\n" +
+				"Require rotating the credentials of the services created by dev1:\n" +
+				"  s-hTvStO9KaswJ  deploy\n\n" +
+				"Secrets: 1 unaffected, 1 to flag\n",
+		},
+		"user json": {
+			cmd: RepoRevokeCommand{
+				accountName: "dev1",
+				path:        "namespace/repo",
+				format:      formatJSON,
+			},
+			out: "{\n" +
+				"    \"Account\": \"dev1\",\n" +
+				"    \"Repo\": \"namespace/repo\",\n" +
+				"    \"AccessRules\": [\n" +
+				"        {\n" +
+				"            \"Path\": \"namespace/repo/dir\",\n" +
+				"            \"Permission\": \"read\"\n" +
+				"        }\n" +
+				"    ],\n" +
+				"    \"FlaggedSecrets\": [\n" +
+				"        \"namespace/repo/dir/secret\"\n" +
+				"    ],\n" +
+				"    \"UnaffectedSecretCount\": 1,\n" +
+				"    \"ServicesLosingAccess\": [],\n" +
+				"    \"CredentialsToRotate\": [\n" +
+				"        {\n" +
+				"            \"ID\": \"s-hTvStO9KaswJ\",\n" +
+				"            \"Description\": \"deploy\"\n" +
+				"        }\n" +
+				"    ]\n" +
+				"}\n",
+		},
+		"service table": {
+			cmd: RepoRevokeCommand{
+				accountName: "s-hTvStO9KaswJ",
+				path:        "namespace/repo",
+				format:      formatTable,
+			},
+			out: "Revoking s-hTvStO9KaswJ from the namespace/repo repository would:\n\n" +
+				"Remove the access rules:\n" +
+				"  namespace/repo  read\n\n" +
+				"Flag the following secrets for rotation:\n" +
+				"  namespace/repo/dir/secret\n" +
+				"  namespace/repo/other\n\n" +
+				"Revoke the access of the services:\n" +
+				"  s-hTvStO9KaswJ  deploy\n\n" +
+				"Secrets: 0 unaffected, 2 to flag\n",
+		},
+		"user not a member": {
+			cmd: RepoRevokeCommand{
+				accountName: "dev3",
+				path:        "namespace/repo",
+				format:      formatTable,
+			},
+			err: ErrAccountNotRepoMember(api.AccountName("dev3"), api.RepoPath("namespace/repo")),
+		},
+		"service not a member": {
+			cmd: RepoRevokeCommand{
+				accountName: "s-unknown",
+				path:        "namespace/repo",
+				format:      formatJSON,
+			},
+			err: ErrAccountNotRepoMember(api.AccountName("s-unknown"), api.RepoPath("namespace/repo")),
+		},
+		"invalid format": {
+			cmd: RepoRevokeCommand{
+				accountName: "dev1",
+				path:        "namespace/repo",
+				format:      "xml",
+			},
+			err: errNoSuchFormat("xml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+
+			client := fakeclient.Client{
+				AccessRuleService: &accessRules,
+				DirService:        &dirService,
+				RepoService:       &repoService,
+				ServiceService:    &serviceService,
+			}
+
+			// Run
+			err := tc.cmd.printBlastRadius(client)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -61,17 +61,17 @@ func (cmd *RepoRmCommand) Run() error {
 
 	if !confirmed {
 		fmt.Fprintln(cmd.io.Output(), "Name does not match. Aborting.")
-		return nil
+		return ErrAborted
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Removing repository...")
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Removing repository...")
 
 	err = client.Repos().Delete(cmd.path.Value())
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Removal complete! The repository %s has been permanently removed.\n", cmd.path)
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Removal complete! The repository %s has been permanently removed.\n", cmd.path)
 
 	return nil
 }
@@ -61,6 +61,7 @@ func TestRepoRmCommand_Run(t *testing.T) {
 				"This will permanently remove the namespace/repo repository, all its secrets and all associated service accounts. " +
 				"Please type in the full path of the repository to confirm: ",
 			out: "Name does not match. Aborting.\n",
+			err: ErrAborted,
 		},
 		"new client error": {
 			newClientErr: testErr,
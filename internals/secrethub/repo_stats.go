@@ -0,0 +1,228 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// aesGCMOverhead is the number of bytes AES-GCM adds to every encrypted secret version: a 12 byte nonce and a 16 byte authentication tag.
+const aesGCMOverhead = 12 + 16
+
+// RepoStatsCommand reports statistics of a repository.
+type RepoStatsCommand struct {
+	io        ui.IO
+	path      api.RepoPath
+	since     time.Time
+	top       int
+	format    string
+	newClient newClientFunc
+}
+
+// NewRepoStatsCommand creates a new RepoStatsCommand.
+func NewRepoStatsCommand(io ui.IO, newClient newClientFunc) *RepoStatsCommand {
+	return &RepoStatsCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoStatsCommand) Register(r command.Registerer) {
+	clause := r.Command("stats", "Show the number of secrets and versions and the total encrypted size of a repository, "+
+		"together with the accounts that wrote the most secrets and the directories with the most activity since the given time.")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("since", "The start of the time window for the activity statistics. Accepts an RFC3339 timestamp, a date (YYYY-MM-DD) or a duration relative to now (e.g. 24h or 7d).").Default("30d").SetValue(&auditTimeValue{t: &cmd.since})
+	clause.Flag("top", "The number of writers and directories to show.").Default("5").IntVar(&cmd.top)
+	clause.Flag("output", "Specify the format in which to output the statistics. Options are: table and json.").HintOptions(formatTable, formatJSON).Default(formatTable).StringVar(&cmd.format)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// repoStatsOutput is the json format of the statistics of a repository.
+type repoStatsOutput struct {
+	Path             string
+	Since            time.Time
+	SecretCount      int
+	VersionCount     int
+	EncryptedSize    int
+	VersionsInWindow int
+	TopWriters       []repoStatsCount
+	BusiestDirs      []repoStatsCount
+}
+
+// repoStatsCount is the number of events of a writer or directory.
+type repoStatsCount struct {
+	Name  string
+	Count int
+}
+
+// Run prints the statistics of the repository.
+func (cmd *RepoStatsCommand) Run() error {
+	if cmd.format != formatTable && cmd.format != formatJSON {
+		return errNoSuchFormat(cmd.format)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	stats := repoStatsOutput{
+		Path:        cmd.path.String(),
+		Since:       cmd.since,
+		SecretCount: len(tree.Secrets),
+	}
+
+	for secretID := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secretID)
+		if err != nil {
+			return err
+		}
+
+		versions, err := client.Secrets().Versions().ListWithData(secretPath.Value())
+		if err != nil {
+			return err
+		}
+
+		for _, version := range versions {
+			stats.VersionCount++
+			stats.EncryptedSize += len(version.Data) + aesGCMOverhead
+			if !version.CreatedAt.Before(cmd.since) {
+				stats.VersionsInWindow++
+			}
+		}
+	}
+
+	writers, dirs, err := cmd.countActivity(client, tree)
+	if err != nil {
+		return err
+	}
+	stats.TopWriters = topRepoStatsCounts(writers, cmd.top)
+	stats.BusiestDirs = topRepoStatsCounts(dirs, cmd.top)
+
+	if cmd.format == formatJSON {
+		output, err := cli.PrettyJSON(stats)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.io.Output(), output)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "Secrets:\t%d\n", stats.SecretCount)
+	fmt.Fprintf(w, "Versions:\t%d (%d since %s)\n", stats.VersionCount, stats.VersionsInWindow, stats.Since.Format(time.RFC3339))
+	fmt.Fprintf(w, "Encrypted size:\t%d bytes\n", stats.EncryptedSize)
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	for _, section := range []struct {
+		header string
+		counts []repoStatsCount
+	}{
+		{"WRITER", stats.TopWriters},
+		{"DIRECTORY", stats.BusiestDirs},
+	} {
+		if len(section.counts) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(cmd.io.Output())
+		w = tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\n", section.header, "EVENTS")
+		for _, count := range section.counts {
+			fmt.Fprintf(w, "%s\t%d\n", count.Name, count.Count)
+		}
+		err = w.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countActivity counts the write events per account and the events on secrets per directory since the start of the window.
+func (cmd *RepoStatsCommand) countActivity(client secrethub.ClientInterface, tree *api.Tree) (map[string]int, map[string]int, error) {
+	writers := make(map[string]int)
+	dirs := make(map[string]int)
+	filter := auditFilter{since: cmd.since}
+
+	iter := client.Repos().EventIterator(cmd.path.Value(), &secrethub.AuditEventIteratorParams{})
+	for {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if filter.isBeforeRange(event) {
+			break
+		}
+
+		if auditActionCategory(event) == auditActionWrite {
+			actor, err := getAuditActor(event)
+			if err == nil {
+				writers[actor]++
+			}
+		}
+
+		var secret *api.Secret
+		switch {
+		case event.Subject.Secret != nil:
+			secret = tree.Secrets[event.Subject.Secret.SecretID]
+		case event.Subject.SecretVersion != nil && event.Subject.SecretVersion.Secret != nil:
+			secret = tree.Secrets[event.Subject.SecretVersion.Secret.SecretID]
+		}
+		if secret == nil {
+			// The secret has been deleted or the event is not about a secret.
+			continue
+		}
+
+		dirPath, err := tree.AbsDirPath(secret.DirID)
+		if err != nil {
+			return nil, nil, err
+		}
+		dirs[dirPath.String()]++
+	}
+	return writers, dirs, nil
+}
+
+// topRepoStatsCounts returns the n names with the highest counts, from high to low.
+// Names with the same count are sorted alphabetically.
+func topRepoStatsCounts(counts map[string]int, n int) []repoStatsCount {
+	res := make([]repoStatsCount, 0, len(counts))
+	for name, count := range counts {
+		res = append(res, repoStatsCount{Name: name, Count: count})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Name < res[j].Name
+	})
+
+	if n >= 0 && len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
@@ -0,0 +1,178 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestRepoStatsCommand_Run(t *testing.T) {
+	testErr := errors.New("test error")
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rootDirID := uuid.New()
+	subDirID := uuid.New()
+	rootSecret := &api.Secret{SecretID: uuid.New(), DirID: rootDirID, Name: "a"}
+	subSecret := &api.Secret{SecretID: uuid.New(), DirID: subDirID, Name: "b"}
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		RootDir:    &api.Dir{DirID: rootDirID, Name: "repo"},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootDirID: {DirID: rootDirID, Name: "repo"},
+			subDirID:  {DirID: subDirID, Name: "dir", ParentID: &rootDirID},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{
+			rootSecret.SecretID: rootSecret,
+			subSecret.SecretID:  subSecret,
+		},
+	}
+
+	event := func(action api.AuditAction, actor string, secret *api.Secret, loggedAt time.Time) api.Audit {
+		return api.Audit{
+			Action:   action,
+			LoggedAt: loggedAt,
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{Username: actor},
+			},
+			Subject: api.AuditSubject{
+				Type:          api.AuditSubjectSecretVersion,
+				SecretVersion: &api.SecretVersion{Secret: secret},
+			},
+		}
+	}
+
+	newClient := func() (secrethub.ClientInterface, error) {
+		return fakeclient.Client{
+			DirService: &fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return tree, nil
+				},
+			},
+			SecretService: &fakeclient.SecretService{
+				VersionService: &fakeclient.SecretVersionService{
+					ListWithDataFunc: func(path string) ([]*api.SecretVersion, error) {
+						if path == "namespace/repo/a" {
+							return []*api.SecretVersion{
+								{Version: 1, Data: []byte("12345"), CreatedAt: since.Add(-time.Hour)},
+								{Version: 2, Data: []byte("1234567890"), CreatedAt: since.Add(time.Hour)},
+							}, nil
+						}
+						return []*api.SecretVersion{
+							{Version: 1, Data: []byte("12"), CreatedAt: since.Add(time.Hour)},
+						}, nil
+					},
+				},
+			},
+			RepoService: &fakeclient.RepoService{
+				AuditEventIterator: &fakeclient.AuditEventIterator{
+					Events: []api.Audit{
+						event(api.AuditActionRead, "dev1", subSecret, since.Add(3*time.Hour)),
+						event(api.AuditActionCreate, "dev2", subSecret, since.Add(2*time.Hour)),
+						event(api.AuditActionCreate, "dev1", rootSecret, since.Add(time.Hour)),
+						event(api.AuditActionCreate, "dev1", rootSecret, since.Add(-time.Hour)),
+					},
+				},
+			},
+		}, nil
+	}
+
+	cases := map[string]struct {
+		format    string
+		top       int
+		newClient newClientFunc
+		out       string
+		err       error
+	}{
+		"table": {
+			format:    formatTable,
+			top:       5,
+			newClient: newClient,
+			out: "Secrets:         2\n" +
+				"Versions:        3 (2 since 2020-01-01T00:00:00Z)\n" +
+				"Encrypted size:  101 bytes\n" +
+				"\n" +
+				"WRITER  EVENTS\n" +
+				"dev1    1\n" +
+				"dev2    1\n" +
+				"\n" +
+				"DIRECTORY           EVENTS\n" +
+				"namespace/repo/dir  2\n" +
+				"namespace/repo      1\n",
+		},
+		"json top 1": {
+			format:    formatJSON,
+			top:       1,
+			newClient: newClient,
+			out: "{\n" +
+				"    \"Path\": \"namespace/repo\",\n" +
+				"    \"Since\": \"2020-01-01T00:00:00Z\",\n" +
+				"    \"SecretCount\": 2,\n" +
+				"    \"VersionCount\": 3,\n" +
+				"    \"EncryptedSize\": 101,\n" +
+				"    \"VersionsInWindow\": 2,\n" +
+				"    \"TopWriters\": [\n" +
+				"        {\n" +
+				"            \"Name\": \"dev1\",\n" +
+				"            \"Count\": 1\n" +
+				"        }\n" +
+				"    ],\n" +
+				"    \"BusiestDirs\": [\n" +
+				"        {\n" +
+				"            \"Name\": \"namespace/repo/dir\",\n" +
+				"            \"Count\": 2\n" +
+				"        }\n" +
+				"    ]\n" +
+				"}\n",
+		},
+		"invalid format": {
+			format: "csv",
+			err:    errNoSuchFormat("csv"),
+		},
+		"client error": {
+			format: formatTable,
+			newClient: func() (secrethub.ClientInterface, error) {
+				return nil, testErr
+			},
+			err: testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := RepoStatsCommand{
+				io:        io,
+				path:      "namespace/repo",
+				since:     since,
+				top:       tc.top,
+				format:    tc.format,
+				newClient: tc.newClient,
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
+
+func TestTopRepoStatsCounts(t *testing.T) {
+	counts := map[string]int{"a": 1, "b": 3, "c": 3, "d": 2}
+
+	assert.Equal(t, topRepoStatsCounts(counts, 3), []repoStatsCount{
+		{Name: "b", Count: 3},
+		{Name: "c", Count: 3},
+		{Name: "d", Count: 2},
+	})
+	assert.Equal(t, len(topRepoStatsCounts(counts, -1)), 4)
+}
@@ -0,0 +1,256 @@
+package secrethub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// Errors
+var (
+	ErrInvalidRetryConfig = errMain.Code("invalid_retry_config").ErrorPref("invalid retry policy configured in %s: %s")
+)
+
+// The defaults of the retry policy of API calls.
+const (
+	defaultRetries           = 2
+	defaultRetryInitialDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay     = 30 * time.Second
+)
+
+// retryConfig is the retry policy in the configuration file.
+type retryConfig struct {
+	Retries      *int   `json:"retries,omitempty"`
+	InitialDelay string `json:"initial_delay,omitempty"`
+	MaxDelay     string `json:"max_delay,omitempty"`
+}
+
+// retryPolicy configures how API calls that fail because of a transient error are retried.
+type retryPolicy struct {
+	// retries is the maximum number of times a call is retried.
+	retries int
+	// initialDelay is the delay before the first retry. It doubles with every retry.
+	initialDelay time.Duration
+	// maxDelay is the maximum delay between retries.
+	maxDelay time.Duration
+}
+
+// defaultRetryPolicy returns the retry policy that is used when none is configured.
+func defaultRetryPolicy() retryPolicy {
+	return retryPolicy{
+		retries:      defaultRetries,
+		initialDelay: defaultRetryInitialDelay,
+		maxDelay:     defaultRetryMaxDelay,
+	}
+}
+
+// loadRetryPolicy returns the retry policy configured in the configuration directory,
+// with the defaults for the settings that are not configured.
+func loadRetryPolicy(dir configdir.Dir) (retryPolicy, error) {
+	policy := defaultRetryPolicy()
+	config, err := loadConfig(dir)
+	if err != nil {
+		return policy, err
+	}
+	file := configFile(dir)
+	if config.Retry == nil {
+		return policy, nil
+	}
+
+	if config.Retry.Retries != nil {
+		if *config.Retry.Retries < 0 {
+			return defaultRetryPolicy(), ErrInvalidRetryConfig(file.path, "retries cannot be negative")
+		}
+		policy.retries = *config.Retry.Retries
+	}
+	if config.Retry.InitialDelay != "" {
+		policy.initialDelay, err = time.ParseDuration(config.Retry.InitialDelay)
+		if err != nil {
+			return defaultRetryPolicy(), ErrInvalidRetryConfig(file.path, err)
+		}
+	}
+	if config.Retry.MaxDelay != "" {
+		policy.maxDelay, err = time.ParseDuration(config.Retry.MaxDelay)
+		if err != nil {
+			return defaultRetryPolicy(), ErrInvalidRetryConfig(file.path, err)
+		}
+	}
+	return policy, nil
+}
+
+// retriesFlag is the value of the --retries flag, which tracks whether it is set.
+type retriesFlag struct {
+	value int
+	set   bool
+}
+
+// String implements the flag.Value interface.
+func (f *retriesFlag) String() string {
+	return strconv.Itoa(f.value)
+}
+
+// Set sets the number of retries.
+func (f *retriesFlag) Set(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("the number of retries cannot be negative")
+	}
+	f.value = n
+	f.set = true
+	return nil
+}
+
+// retryTransport is a http.RoundTripper that retries API calls that fail because of a network error
+// or a temporary server error, with a jittered exponential backoff. Only calls that can safely be
+// repeated are retried.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy retryPolicy
+	wait   func(ctx context.Context, d time.Duration) error
+	random func() float64
+}
+
+// newRetryTransport returns a transport that passes the API calls on to next and retries them with the policy.
+func newRetryTransport(next http.RoundTripper, policy retryPolicy) *retryTransport {
+	return &retryTransport{
+		next:   next,
+		policy: policy,
+		wait:   waitContext,
+		random: rand.Float64,
+	}
+}
+
+// RoundTrip performs the request and retries it while it fails with a transient error.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.policy.retries == 0 || !isIdempotent(req) || (req.Body != nil && req.GetBody == nil) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt == t.policy.retries || !isTransient(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if ok && retryAfter > t.policy.maxDelay {
+				// The server asks to wait longer than the policy allows, so it is not retried.
+				return resp, err
+			}
+			if ok && retryAfter > delay {
+				delay = retryAfter
+			}
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		// The URL and the error are not logged, because they contain the paths of secrets.
+		fields := logFields{"method": req.Method, "attempt": attempt + 1, "delay_ms": delay.Milliseconds()}
+		if resp != nil {
+			fields["status"] = resp.StatusCode
+		}
+		structuredLog.debug("http_retry", fields)
+
+		err = t.wait(req.Context(), delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitContext waits for the given duration or until the context is done, in which case the error of the context is returned.
+func waitContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff returns the delay before the given retry, starting at zero. The delay doubles with
+// every retry up to the maximum delay and is randomized to spread the retries of many clients.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := float64(t.policy.initialDelay) * math.Pow(2, float64(attempt))
+	if delay > float64(t.policy.maxDelay) {
+		delay = float64(t.policy.maxDelay)
+	}
+	return time.Duration(delay/2 + t.random()*delay/2)
+}
+
+// isIdempotent returns whether the request can safely be repeated.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isTransient returns whether the call failed with an error that can be resolved by retrying it:
+// a network error, too many requests or a server error other than not implemented.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// parseRetryAfter returns the delay in the value of a Retry-After header,
+// which is either a number of seconds or a date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// retryPolicy returns the retry policy from the configuration file with the retries set by
+// the --retries flag. An invalid configuration does not stop the command: a warning is printed
+// and the default policy is used.
+func (f *clientFactory) retryPolicy() retryPolicy {
+	policy, err := loadRetryPolicy(f.store.ConfigDir())
+	if err != nil {
+		warnConfig(err)
+	}
+	if f.retries.set {
+		policy.retries = f.retries.value
+	}
+	return policy
+}
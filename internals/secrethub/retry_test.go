@@ -0,0 +1,248 @@
+package secrethub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRetryTransport_RoundTrip(t *testing.T) {
+	networkErr := errors.New("connection reset by peer")
+
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	cases := map[string]struct {
+		method    string
+		responses []*http.Response
+		errs      []error
+		status    int
+		err       error
+		attempts  int
+		delays    []time.Duration
+	}{
+		"success": {
+			method:    http.MethodGet,
+			responses: []*http.Response{response(200, "")},
+			status:    200,
+			attempts:  1,
+		},
+		"retried server error": {
+			method:    http.MethodGet,
+			responses: []*http.Response{response(503, ""), response(200, "")},
+			status:    200,
+			attempts:  2,
+			delays:    []time.Duration{time.Second},
+		},
+		"retried network error": {
+			method:    http.MethodDelete,
+			responses: []*http.Response{nil, nil, response(200, "")},
+			errs:      []error{networkErr, networkErr, nil},
+			status:    200,
+			attempts:  3,
+			delays:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		"retries exhausted": {
+			method:    http.MethodGet,
+			responses: []*http.Response{response(502, ""), response(502, ""), response(502, "")},
+			status:    502,
+			attempts:  3,
+			delays:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		"retry after": {
+			method:    http.MethodGet,
+			responses: []*http.Response{response(429, "5"), response(200, "")},
+			status:    200,
+			attempts:  2,
+			delays:    []time.Duration{5 * time.Second},
+		},
+		"retry after too long": {
+			method:    http.MethodGet,
+			responses: []*http.Response{response(429, "120")},
+			status:    429,
+			attempts:  1,
+		},
+		"client error": {
+			method:    http.MethodGet,
+			responses: []*http.Response{response(404, "")},
+			status:    404,
+			attempts:  1,
+		},
+		"not idempotent": {
+			method:    http.MethodPost,
+			responses: []*http.Response{response(503, "")},
+			status:    503,
+			attempts:  1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			var delays []time.Duration
+			transport := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body, err := ioutil.ReadAll(req.Body)
+				assert.OK(t, err)
+				assert.Equal(t, string(body), "body")
+
+				attempts++
+				var respErr error
+				if tc.errs != nil {
+					respErr = tc.errs[attempts-1]
+				}
+				return tc.responses[attempts-1], respErr
+			}), retryPolicy{
+				retries:      2,
+				initialDelay: time.Second,
+				maxDelay:     time.Minute,
+			})
+			transport.wait = func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+			transport.random = func() float64 {
+				return 1
+			}
+
+			req, err := http.NewRequest(tc.method, "https://api.secrethub.io/", bytes.NewBufferString("body"))
+			assert.OK(t, err)
+
+			resp, err := transport.RoundTrip(req)
+
+			assert.Equal(t, err, tc.err)
+			if err == nil {
+				assert.Equal(t, resp.StatusCode, tc.status)
+			}
+			assert.Equal(t, attempts, tc.attempts)
+			assert.Equal(t, delays, tc.delays)
+		})
+	}
+}
+
+func TestRetryTransport_RoundTrip_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	transport := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		// The request is canceled while the transport waits for the retry.
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return &http.Response{
+			StatusCode: 503,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}), retryPolicy{
+		retries:      2,
+		initialDelay: time.Hour,
+		maxDelay:     time.Hour,
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.secrethub.io/", nil)
+	assert.OK(t, err)
+
+	_, err = transport.RoundTrip(req.WithContext(ctx))
+
+	assert.Equal(t, err, context.Canceled)
+	assert.Equal(t, attempts, 1)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		"empty": {},
+		"seconds": {
+			value:    "30",
+			expected: 30 * time.Second,
+			ok:       true,
+		},
+		"date": {
+			value:    "Wed, 01 Jan 2020 12:01:00 GMT",
+			expected: time.Minute,
+			ok:       true,
+		},
+		"past date": {
+			value:    "Wed, 01 Jan 2020 11:00:00 GMT",
+			expected: 0,
+			ok:       true,
+		},
+		"invalid": {
+			value: "soon",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := parseRetryAfter(tc.value, now)
+
+			assert.Equal(t, ok, tc.ok)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestLoadRetryPolicy(t *testing.T) {
+	retries := 5
+
+	cases := map[string]struct {
+		config   cliConfig
+		expected retryPolicy
+	}{
+		"default": {
+			expected: defaultRetryPolicy(),
+		},
+		"configured": {
+			config: cliConfig{
+				Retry: &retryConfig{
+					Retries:      &retries,
+					InitialDelay: "1s",
+					MaxDelay:     "1m",
+				},
+			},
+			expected: retryPolicy{
+				retries:      5,
+				initialDelay: time.Second,
+				maxDelay:     time.Minute,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			assert.OK(t, configFile(configdir.New(dir)).write(tc.config))
+
+			actual, err := loadRetryPolicy(configdir.New(dir))
+
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -100,7 +100,7 @@ func rmSecretVersion(client secrethub.ClientInterface, secretPath api.SecretPath
 		return err
 	}
 
-	ok, err := askRmConfirmation(
+	err = askRmConfirmation(
 		io,
 		fmt.Sprintf("This will permanently remove the %s secret version. "+
 			"Please type in the name of the secret and the version (<name>:<version>) to confirm", secretPath.String()),
@@ -111,9 +111,6 @@ func rmSecretVersion(client secrethub.ClientInterface, secretPath api.SecretPath
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return nil
-	}
 
 	err = client.Secrets().Versions().Delete(secretPath.Value())
 	if err != nil {
@@ -121,7 +118,7 @@ func rmSecretVersion(client secrethub.ClientInterface, secretPath api.SecretPath
 	}
 
 	fmt.Fprintf(
-		io.Output(),
+		statusWriter(io.Output()),
 		"Removal complete! The secret version %s has been permanently removed.\n",
 		secretPath,
 	)
@@ -130,7 +127,7 @@ func rmSecretVersion(client secrethub.ClientInterface, secretPath api.SecretPath
 }
 
 func rmSecret(client secrethub.ClientInterface, secretPath api.SecretPath, force bool, io ui.IO) error {
-	ok, err := askRmConfirmation(
+	err := askRmConfirmation(
 		io,
 		fmt.Sprintf("This will permanently remove the %s secret and all its versions. "+
 			"Please type in the name of the secret to confirm", secretPath.String()),
@@ -141,9 +138,6 @@ func rmSecret(client secrethub.ClientInterface, secretPath api.SecretPath, force
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return nil
-	}
 
 	err = client.Secrets().Delete(secretPath.Value())
 	if err != nil {
@@ -151,7 +145,7 @@ func rmSecret(client secrethub.ClientInterface, secretPath api.SecretPath, force
 	}
 
 	fmt.Fprintf(
-		io.Output(),
+		statusWriter(io.Output()),
 		"Removal complete! The secret %s has been permanently removed.\n",
 		secretPath,
 	)
@@ -160,7 +154,7 @@ func rmSecret(client secrethub.ClientInterface, secretPath api.SecretPath, force
 }
 
 func rmDir(client secrethub.ClientInterface, dirPath api.DirPath, force bool, io ui.IO) error {
-	ok, err := askRmConfirmation(
+	err := askRmConfirmation(
 		io,
 		fmt.Sprintf("This will permanently remove the %s directory and all the directories and secrets it contains. "+
 			"Please type in the name of the directory to confirm", dirPath.String()),
@@ -171,9 +165,6 @@ func rmDir(client secrethub.ClientInterface, dirPath api.DirPath, force bool, io
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return nil
-	}
 
 	err = client.Dirs().Delete(dirPath.Value())
 	if err != nil {
@@ -181,7 +172,7 @@ func rmDir(client secrethub.ClientInterface, dirPath api.DirPath, force bool, io
 	}
 
 	fmt.Fprintf(
-		io.Output(),
+		statusWriter(io.Output()),
 		"Removal complete! The directory %s has been permanently removed.\n",
 		dirPath,
 	)
@@ -189,9 +180,9 @@ func rmDir(client secrethub.ClientInterface, dirPath api.DirPath, force bool, io
 	return nil
 }
 
-func askRmConfirmation(io ui.IO, confirmationText string, force bool, expected ...string) (bool, error) {
+func askRmConfirmation(io ui.IO, confirmationText string, force bool, expected ...string) error {
 	if force {
-		return true, nil
+		return nil
 	}
 
 	confirmed, err := ui.ConfirmCaseInsensitive(
@@ -204,14 +195,14 @@ func askRmConfirmation(io ui.IO, confirmationText string, force bool, expected .
 	)
 
 	if err == ui.ErrCannotAsk {
-		return false, ErrCannotDoWithoutForce
+		return ErrCannotDoWithoutForce
 	} else if err != nil {
-		return false, err
+		return err
 	}
 
 	if !confirmed {
 		fmt.Fprintln(io.Output(), "Name does not match. Aborting.")
-		return false, nil
+		return ErrAborted
 	}
-	return true, nil
+	return nil
 }
@@ -106,6 +106,7 @@ func (cmd *RunCommand) Run() error {
 			sequences = append(sequences, []byte(val))
 		}
 	}
+	structuredLog.maskSecrets(sequences)
 	m := masker.New(sequences, &cmd.maskerOptions)
 
 	command := exec.Command(cmd.command[0], cmd.command[1:]...)
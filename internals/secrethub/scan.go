@@ -0,0 +1,217 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/scanner"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrLeaksFound = errMain.Code("leaks_found").ErrorPref("found %d possible secrets")
+	ErrGitFailed  = errMain.Code("git_failed").ErrorPref("git failed: %s")
+)
+
+// gitDiffArgs are the arguments to git that print the lines that were added, in a format that
+// scanner.ScanGitLog understands. They are followed by git log or git diff.
+var gitDiffArgs = []string{"--patch", "--unified=0", "--no-color", "--no-ext-diff"}
+
+// scanGitFunc returns the findings of the scanner in the output of git, run with the given arguments in a directory.
+type scanGitFunc func(dir string, args []string, s *scanner.Scanner) ([]scanner.Finding, error)
+
+// scanGit streams the output of git log into the scanner, so the history is never held in memory.
+func scanGit(dir string, args []string, s *scanner.Scanner) ([]scanner.Finding, error) {
+	var stderr bytes.Buffer
+	git := exec.Command("git", args...)
+	git.Dir = dir
+	git.Stderr = &stderr
+
+	stdout, err := git.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = git.Start()
+	if err != nil {
+		return nil, ErrGitFailed(err)
+	}
+
+	findings, scanErr := s.ScanGitLog(stdout)
+	if scanErr != nil {
+		// Drain the output, so git does not block on a full pipe.
+		_, _ = io.Copy(ioutil.Discard, stdout)
+	}
+	err = git.Wait()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, ErrGitFailed(msg)
+	}
+	return findings, scanErr
+}
+
+// ScanCommand finds secrets in files or in the history of a git repository.
+type ScanCommand struct {
+	io         ui.IO
+	path       string
+	git        bool
+	staged     bool
+	revisions  []string
+	crossCheck []string
+	newClient  newClientFunc
+	scanGit    scanGitFunc
+}
+
+// NewScanCommand creates a new ScanCommand.
+func NewScanCommand(io ui.IO, newClient newClientFunc) *ScanCommand {
+	return &ScanCommand{
+		io:        io,
+		newClient: newClient,
+		scanGit:   scanGit,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ScanCommand) Register(r command.Registerer) {
+	clause := r.Command("scan", "Find secrets that are leaked in the files of a directory or in the history of a git repository, "+
+		"before they are pushed. Lines are matched against known credential formats, such as AWS access keys, GitHub tokens and private keys, "+
+		"and long random words are reported as high-entropy strings. With --cross-check, the values of the secrets in SecretHub "+
+		"are also looked for, which confirms that a finding is a leak. Scanning is done locally and matches are only printed partially. "+
+		"The command fails when anything is found, so it can be used as a pre-commit hook or in CI.")
+	clause.Arg("path", "The directory to scan. Defaults to the current directory.").Default(".").StringVar(&cmd.path)
+	clause.Flag("git", "Scan every line that was ever added in the history of the git repository in the directory, instead of the files.").BoolVar(&cmd.git)
+	clause.Flag("staged", "Scan the lines that are staged to be committed in the git repository in the directory, instead of the files.").BoolVar(&cmd.staged)
+	clause.Flag("range", "Scan the lines that were added in a range of commits in the git repository in the directory, as passed to git log, "+
+		"e.g. origin/master..HEAD. Can be repeated, e.g. to exclude commits with ^<commit>.").PlaceHolder("<revision-range>").StringsVar(&cmd.revisions)
+	clause.Flag("cross-check", "A directory in SecretHub of which the values of the secrets are looked for. Can be repeated.").PlaceHolder(dirPathPlaceHolder).StringsVar(&cmd.crossCheck)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run scans the directory and prints the findings.
+func (cmd *ScanCommand) Run() error {
+	known, err := cmd.knownValues()
+	if err != nil {
+		return err
+	}
+	s := scanner.New(known)
+
+	var findings []scanner.Finding
+	if args := cmd.gitArgs(); args != nil {
+		findings, err = cmd.scanGit(cmd.path, args, s)
+	} else {
+		findings, err = s.ScanDir(cmd.path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "No secrets found in %s.\n", cmd.path)
+		return nil
+	}
+	err = cmd.printFindings(findings)
+	if err != nil {
+		return err
+	}
+	return ErrLeaksFound(len(findings))
+}
+
+// gitArgs returns the arguments to git that print the lines to scan, or nil when files are scanned.
+func (cmd *ScanCommand) gitArgs() []string {
+	switch {
+	case cmd.staged:
+		return append([]string{"diff", "--cached"}, gitDiffArgs...)
+	case len(cmd.revisions) > 0:
+		return append(append([]string{"log", "--format=commit %H"}, gitDiffArgs...), cmd.revisions...)
+	case cmd.git:
+		return append([]string{"log", "--format=commit %H", "--all"}, gitDiffArgs...)
+	default:
+		return nil
+	}
+}
+
+// knownValues returns the values of the latest versions of the secrets in the directories
+// to cross-check, mapped to the paths of their secrets.
+func (cmd *ScanCommand) knownValues() (map[string]string, error) {
+	if len(cmd.crossCheck) == 0 {
+		return nil, nil
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]string{}
+	for _, path := range cmd.crossCheck {
+		dirPath, err := api.NewDirPath(path)
+		if err != nil {
+			return nil, err
+		}
+		secrets, err := readDirSecrets(client, dirPath, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets {
+			known[string(secret.Value)] = dirPath.Value() + "/" + secret.Name
+		}
+	}
+	return known, nil
+}
+
+// printFindings prints a table of the findings. Confirmed leaks are highlighted.
+func (cmd *ScanCommand) printFindings(findings []scanner.Finding) error {
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	showCommit := cmd.git || len(cmd.revisions) > 0
+	if showCommit {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "LOCATION", "COMMIT", "RULE", "MATCH", "SECRET")
+	} else {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "LOCATION", "RULE", "MATCH", "SECRET")
+	}
+
+	for _, finding := range findings {
+		location := finding.Path + ":" + strconv.Itoa(finding.Line)
+		var secret interface{} = finding.Secret
+		if finding.Secret != "" {
+			secret = colorize(colorRoleFlagged, finding.Secret)
+		}
+
+		if showCommit {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", location, shortCommit(finding.Commit), finding.Rule, redactMatch(finding.Match), secret)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", location, finding.Rule, redactMatch(finding.Match), secret)
+		}
+	}
+	return w.Flush()
+}
+
+// redactMatch returns the first characters of a match, which is enough to recognize it
+// without printing the secret.
+func redactMatch(match string) string {
+	const visible = 4
+	if len(match) <= visible {
+		return "****"
+	}
+	return match[:visible] + "****"
+}
+
+// shortCommit returns the abbreviated hash of a git commit.
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
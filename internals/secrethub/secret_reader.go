@@ -16,14 +16,15 @@ func newSecretReader(newClient newClientFunc) *secretReader {
 	}
 }
 
-// ReadSecret reads the secret using the provided client.
+// ReadSecret reads the secret using the provided client. When there is no secret at the path,
+// the link at the path is resolved.
 func (sr secretReader) ReadSecret(path string) (string, error) {
 	client, err := sr.newClient()
 	if err != nil {
 		return "", err
 	}
 
-	secret, err := client.Secrets().Versions().GetWithData(path)
+	secret, err := readSecretOrLink(client, api.SecretPath(path))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,106 @@
+package secrethub
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/selfupdate"
+)
+
+// Errors
+var (
+	ErrNoReleaseKey     = errMain.Code("no_release_key").Error("this build of the CLI has no release key to verify updates with, so it cannot update itself")
+	ErrManagedInstall   = errMain.Code("managed_install").ErrorPref("the CLI was installed with %s, so update it with `%s` instead")
+	ErrSelfUpdateFailed = errMain.Code("self_update_failed").ErrorPref("cannot update the CLI: %s")
+)
+
+// SelfUpdateCommand replaces the running binary with the latest verified release.
+type SelfUpdateCommand struct {
+	io          ui.IO
+	channel     string
+	check       bool
+	releasesURL string
+}
+
+// NewSelfUpdateCommand creates a new SelfUpdateCommand.
+func NewSelfUpdateCommand(io ui.IO) *SelfUpdateCommand {
+	return &SelfUpdateCommand{
+		io: io,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SelfUpdateCommand) Register(r command.Registerer) {
+	clause := r.Command("self-update", "Update the CLI to the latest release. The checksums of the release must be signed with the release key "+
+		"that is built into the CLI and the downloaded archive must match its checksum, otherwise nothing is installed. "+
+		"The binary is replaced atomically, so it is never left half-written. When the CLI was installed with a package manager, "+
+		"such as Homebrew, Scoop, apt or yum, it is not updated and the command to update it with the package manager is shown instead.")
+	clause.Flag("channel", "The release channel to update from: stable for releases only, beta to include pre-releases.").Default(selfupdate.ChannelStable).EnumVar(&cmd.channel, selfupdate.ChannelStable, selfupdate.ChannelBeta)
+	clause.Flag("check", "Only show whether an update is available, without installing it.").BoolVar(&cmd.check)
+	clause.Flag("releases-url", "The URL of the GitHub API that lists the releases, to update from a mirror.").Default(selfupdate.DefaultReleasesURL).StringVar(&cmd.releasesURL)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run checks for a newer release and installs it.
+func (cmd *SelfUpdateCommand) Run() error {
+	publicKey, err := base64.StdEncoding.DecodeString(ReleasePublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return ErrNoReleaseKey
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return ErrSelfUpdateFailed(err)
+	}
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return ErrSelfUpdateFailed(err)
+	}
+
+	updater := selfupdate.Updater{
+		Client:      &http.Client{Timeout: 5 * time.Minute},
+		ReleasesURL: cmd.releasesURL,
+		PublicKey:   publicKey,
+	}
+	release, err := updater.Latest(cmd.channel)
+	if err != nil {
+		return ErrSelfUpdateFailed(err)
+	}
+
+	w := cmd.io.Output()
+	if selfupdate.CompareVersions(release.Version(), Version) <= 0 {
+		fmt.Fprintf(w, "The CLI is up to date with version %s.\n", Version)
+		return nil
+	}
+	if cmd.check {
+		fmt.Fprintf(w, "Version %s is available, the CLI has version %s.\n", release.Version(), Version)
+		return nil
+	}
+
+	name, update, managed := selfupdate.PackageManager(executable)
+	if managed {
+		return ErrManagedInstall(name, update)
+	}
+
+	fmt.Fprintf(statusWriter(w), "Downloading version %s...\n", release.Version())
+	binary, err := updater.Download(release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return ErrSelfUpdateFailed(err)
+	}
+	err = selfupdate.Replace(executable, binary)
+	if err != nil {
+		return ErrSelfUpdateFailed(err)
+	}
+
+	fmt.Fprintf(w, "Updated %s from version %s to %s.\n", executable, Version, release.Version())
+	return nil
+}
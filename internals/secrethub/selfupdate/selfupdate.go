@@ -0,0 +1,408 @@
+// Package selfupdate finds, verifies and installs releases of the CLI.
+//
+// Every release has a checksums file with the SHA-256 checksums of its archives and a
+// detached Ed25519 signature of the checksums file. An archive is only installed when
+// the signature is made with the release key and the checksum of the archive matches.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DefaultReleasesURL is the URL of the GitHub API that lists the releases of the CLI.
+const DefaultReleasesURL = "https://api.github.com/repos/secrethub/secrethub-cli/releases"
+
+// maxDownloadSize is the maximum size of a downloaded file, which is far larger than any archive.
+const maxDownloadSize = 256 << 20
+
+// Channels of releases.
+const (
+	// ChannelStable has the releases without a pre-release version, e.g. v0.40.0.
+	ChannelStable = "stable"
+	// ChannelBeta has all releases, including pre-releases such as v0.41.0-beta.1.
+	ChannelBeta = "beta"
+)
+
+// Errors
+var (
+	ErrUnknownChannel     = errors.New("unknown channel: must be stable or beta")
+	ErrNoRelease          = errors.New("no release found")
+	ErrInvalidSignature   = errors.New("the signature of the checksums does not match the release key")
+	ErrChecksumMismatch   = errors.New("the checksum of the downloaded archive does not match the signed checksum")
+	ErrBinaryNotInRelease = errors.New("the archive does not contain the secrethub binary")
+)
+
+// Release is a release of the CLI with the download URLs of its assets by their names.
+type Release struct {
+	Tag    string
+	Assets map[string]string
+}
+
+// Version returns the version of the release, which is its tag without the v prefix.
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// Updater finds and downloads releases.
+type Updater struct {
+	Client      *http.Client
+	ReleasesURL string
+	// PublicKey is the release key with which the checksums of the releases are signed.
+	PublicKey ed25519.PublicKey
+}
+
+// githubRelease is a release as it is returned by the GitHub API.
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Draft   bool   `json:"draft"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Latest returns the release with the highest version in the channel.
+func (u Updater) Latest(channel string) (*Release, error) {
+	if channel != ChannelStable && channel != ChannelBeta {
+		return nil, ErrUnknownChannel
+	}
+
+	data, err := u.get(u.ReleasesURL + "?per_page=100")
+	if err != nil {
+		return nil, err
+	}
+	var releases []githubRelease
+	err = json.Unmarshal(data, &releases)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the list of releases: %s", err)
+	}
+
+	var latest *githubRelease
+	for i, release := range releases {
+		version, ok := parseVersion(release.TagName)
+		if release.Draft || !ok || (channel == ChannelStable && version.pre != "") {
+			continue
+		}
+		if latest == nil || CompareVersions(release.TagName, latest.TagName) > 0 {
+			latest = &releases[i]
+		}
+	}
+	if latest == nil {
+		return nil, ErrNoRelease
+	}
+
+	result := &Release{
+		Tag:    latest.TagName,
+		Assets: map[string]string{},
+	}
+	for _, asset := range latest.Assets {
+		result.Assets[asset.Name] = asset.BrowserDownloadURL
+	}
+	return result, nil
+}
+
+// Download downloads the archive of the release for the platform, verifies it and returns the binary in it.
+func (u Updater) Download(release *Release, goos string, goarch string) ([]byte, error) {
+	checksumsName := "secrethub-" + release.Tag + "-checksums.txt"
+	checksums, err := u.asset(release, checksumsName)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := u.asset(release, checksumsName+".sig")
+	if err != nil {
+		return nil, err
+	}
+	if len(u.PublicKey) != ed25519.PublicKeySize || !ed25519.Verify(u.PublicKey, checksums, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	name := ArchiveName(release.Tag, goos, goarch)
+	expected, ok := findChecksum(checksums, name)
+	if !ok {
+		return nil, fmt.Errorf("the release has no checksum for %s", name)
+	}
+	archive, err := u.asset(release, name)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(archive)
+	if hex.EncodeToString(sum[:]) != expected {
+		return nil, ErrChecksumMismatch
+	}
+
+	if goos == "windows" {
+		return extractZip(archive, "bin/secrethub.exe")
+	}
+	return extractTarGz(archive, "bin/secrethub")
+}
+
+// ArchiveName returns the name of the archive of the release for the platform. ARM builds are released
+// for several ARM versions, of which version 5 runs on all ARM processors.
+func ArchiveName(tag string, goos string, goarch string) string {
+	if goarch == "arm" {
+		goarch = "armv5"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return "secrethub-" + tag + "-" + goos + "-" + goarch + ext
+}
+
+// asset downloads the asset of the release with the name.
+func (u Updater) asset(release *Release, name string) ([]byte, error) {
+	url, ok := release.Assets[name]
+	if !ok {
+		return nil, fmt.Errorf("the release %s has no %s", release.Tag, name)
+	}
+	return u.get(url)
+}
+
+// get returns the response body of a GET request to the URL.
+func (u Updater) get(url string) ([]byte, error) {
+	resp, err := u.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+}
+
+// findChecksum returns the checksum of the file in a checksums file, which has a line with the
+// hex encoded checksum and the name of every file.
+func findChecksum(checksums []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// extractTarGz returns the file at the path in the gzipped tar archive.
+func extractTarGz(archive []byte, path string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	r := tar.NewReader(gz)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return nil, ErrBinaryNotInRelease
+		} else if err != nil {
+			return nil, err
+		}
+		if header.Name == path {
+			return ioutil.ReadAll(r)
+		}
+	}
+}
+
+// extractZip returns the file at the path in the zip archive.
+func extractZip(archive []byte, path string) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range r.File {
+		if file.Name != path {
+			continue
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ioutil.ReadAll(f)
+	}
+	return nil, ErrBinaryNotInRelease
+}
+
+// Replace atomically replaces the executable with the binary, keeping its file mode. The binary is
+// written next to the executable and then renamed, so the executable is either the old or the new binary.
+// On Windows, the running executable is first moved to a file with the .old extension.
+func Replace(executable string, binary []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(executable), ".secrethub-update-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(binary)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), info.Mode())
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS != "windows" {
+		return os.Rename(tmp.Name(), executable)
+	}
+
+	old := executable + ".old"
+	_ = os.Remove(old)
+	err = os.Rename(executable, old)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp.Name(), executable)
+	if err != nil {
+		_ = os.Rename(old, executable)
+		return err
+	}
+	return nil
+}
+
+// PackageManager returns the package manager that installed the executable and the command
+// to update it with, or false when the executable was not installed by a package manager.
+func PackageManager(executable string) (name string, update string, ok bool) {
+	path := strings.ReplaceAll(executable, `\`, "/")
+	switch {
+	case strings.Contains(path, "/Cellar/") || strings.Contains(path, "/homebrew/"):
+		return "Homebrew", "brew upgrade secrethub-cli", true
+	case strings.Contains(strings.ToLower(path), "/scoop/"):
+		return "Scoop", "scoop update secrethub-cli", true
+	case path == "/usr/bin/secrethub":
+		if _, err := os.Stat("/var/lib/dpkg/info/secrethub-cli.list"); err == nil {
+			return "apt", "apt-get install --only-upgrade secrethub-cli", true
+		}
+		if _, err := os.Stat("/var/lib/rpm"); err == nil {
+			return "yum", "yum update secrethub-cli", true
+		}
+	}
+	return "", "", false
+}
+
+// version is a semantic version.
+type version struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// parseVersion parses a semantic version with an optional v prefix and ignores build metadata.
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var v version
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, v.pre = s[:i], s[i+1:]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return version{}, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		numbers[i] = n
+	}
+	v.major, v.minor, v.patch = numbers[0], numbers[1], numbers[2]
+	return v, true
+}
+
+// CompareVersions returns -1, 0 or 1 when the semantic version a is lower than, equal to or higher than b.
+// Versions that cannot be parsed, such as those of development builds, are lower than all others.
+func CompareVersions(a string, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	for _, diff := range []int{va.major - vb.major, va.minor - vb.minor, va.patch - vb.patch} {
+		if diff != 0 {
+			return sign(diff)
+		}
+	}
+	return comparePreRelease(va.pre, vb.pre)
+}
+
+// comparePreRelease compares pre-release versions, of which the identifiers are compared numerically
+// when they are numbers. A version without a pre-release is higher than one with a pre-release.
+func comparePreRelease(a string, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	idsA, idsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(idsA) && i < len(idsB); i++ {
+		if idsA[i] == idsB[i] {
+			continue
+		}
+		numA, errA := strconv.Atoi(idsA[i])
+		numB, errB := strconv.Atoi(idsB[i])
+		switch {
+		case errA == nil && errB == nil:
+			return sign(numA - numB)
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		}
+		return strings.Compare(idsA[i], idsB[i])
+	}
+	return sign(len(idsA) - len(idsB))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := map[string]struct {
+		a        string
+		b        string
+		expected int
+	}{
+		"equal": {
+			a:        "v0.40.0",
+			b:        "0.40.0",
+			expected: 0,
+		},
+		"minor": {
+			a:        "0.9.0",
+			b:        "0.10.0",
+			expected: -1,
+		},
+		"patch": {
+			a:        "1.0.1",
+			b:        "1.0.0",
+			expected: 1,
+		},
+		"pre-release is lower": {
+			a:        "0.41.0-beta.1",
+			b:        "0.41.0",
+			expected: -1,
+		},
+		"numeric pre-release": {
+			a:        "0.41.0-beta.10",
+			b:        "0.41.0-beta.2",
+			expected: 1,
+		},
+		"build metadata is ignored": {
+			a:        "0.41.0+abc",
+			b:        "0.41.0",
+			expected: 0,
+		},
+		"development build is lower": {
+			a:        "",
+			b:        "0.1.0",
+			expected: -1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, CompareVersions(tc.a, tc.b), tc.expected)
+		})
+	}
+}
+
+// releaseServer serves the releases with the given tags, of which the archives contain the binary.
+type releaseServer struct {
+	*httptest.Server
+	files map[string][]byte
+}
+
+func newReleaseServer(t *testing.T, key ed25519.PrivateKey, binary []byte, tags ...string) *releaseServer {
+	s := &releaseServer{files: map[string][]byte{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/releases" {
+			var releases []map[string]interface{}
+			for _, tag := range tags {
+				var assets []map[string]string
+				for name := range s.files {
+					assets = append(assets, map[string]string{"name": name, "browser_download_url": s.URL + "/download/" + name})
+				}
+				releases = append(releases, map[string]interface{}{"tag_name": tag, "assets": assets})
+			}
+			_ = json.NewEncoder(w).Encode(releases)
+			return
+		}
+		data, ok := s.files[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(s.Close)
+
+	for _, tag := range tags {
+		archiveName := ArchiveName(tag, "linux", "amd64")
+		archive := tarGz(t, "bin/secrethub", binary)
+		sum := sha256.Sum256(archive)
+		checksums := []byte(hex.EncodeToString(sum[:]) + "  " + archiveName + "\n")
+
+		s.files[archiveName] = archive
+		s.files["secrethub-"+tag+"-checksums.txt"] = checksums
+		s.files["secrethub-"+tag+"-checksums.txt.sig"] = ed25519.Sign(key, checksums)
+	}
+	return s
+}
+
+func tarGz(t *testing.T, name string, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	assert.OK(t, w.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(data))}))
+	_, err := w.Write(data)
+	assert.OK(t, err)
+	assert.OK(t, w.Close())
+	assert.OK(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestUpdater(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.OK(t, err)
+	server := newReleaseServer(t, privateKey, []byte("binary"), "v0.40.0", "v0.41.0-beta.1", "v0.39.2", "nightly")
+
+	updater := Updater{
+		Client:      server.Client(),
+		ReleasesURL: server.URL + "/releases",
+		PublicKey:   publicKey,
+	}
+
+	release, err := updater.Latest(ChannelStable)
+	assert.OK(t, err)
+	assert.Equal(t, release.Tag, "v0.40.0")
+
+	release, err = updater.Latest(ChannelBeta)
+	assert.OK(t, err)
+	assert.Equal(t, release.Tag, "v0.41.0-beta.1")
+
+	binary, err := updater.Download(release, "linux", "amd64")
+	assert.OK(t, err)
+	assert.Equal(t, string(binary), "binary")
+
+	_, err = updater.Download(release, "darwin", "amd64")
+	assert.Equal(t, err != nil, true)
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.OK(t, err)
+	updater.PublicKey = otherKey
+	_, err = updater.Download(release, "linux", "amd64")
+	assert.Equal(t, err, ErrInvalidSignature)
+
+	updater.PublicKey = publicKey
+	server.files[ArchiveName(release.Tag, "linux", "amd64")] = tarGz(t, "bin/secrethub", []byte("tampered"))
+	_, err = updater.Download(release, "linux", "amd64")
+	assert.Equal(t, err, ErrChecksumMismatch)
+}
+
+func TestReplace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrethub-selfupdate")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+
+	executable := filepath.Join(dir, "secrethub")
+	assert.OK(t, ioutil.WriteFile(executable, []byte("old"), 0750))
+
+	err = Replace(executable, []byte("new"))
+	assert.OK(t, err)
+
+	data, err := ioutil.ReadFile(executable)
+	assert.OK(t, err)
+	assert.Equal(t, string(data), "new")
+	info, err := os.Stat(executable)
+	assert.OK(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0750))
+
+	files, err := ioutil.ReadDir(dir)
+	assert.OK(t, err)
+	assert.Equal(t, len(files), 1)
+}
+
+func TestPackageManager(t *testing.T) {
+	cases := map[string]struct {
+		executable string
+		expected   string
+	}{
+		"homebrew": {
+			executable: "/usr/local/Cellar/secrethub-cli/0.40.0/bin/secrethub",
+			expected:   "Homebrew",
+		},
+		"scoop": {
+			executable: `C:\Users\dev\scoop\apps\secrethub-cli\current\bin\secrethub.exe`,
+			expected:   "Scoop",
+		},
+		"manual install": {
+			executable: "/usr/local/bin/secrethub",
+			expected:   "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, _, _ := PackageManager(tc.executable)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
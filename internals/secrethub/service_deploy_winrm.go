@@ -189,13 +189,13 @@ func (cmd *ServiceDeployWinRmCommand) Run() error {
 	}
 
 	// Copy the config to the host.
-	fmt.Fprintln(cmd.io.Output(), "Deploying configuration...")
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Deploying configuration...")
 	err = deployer.configure(credential)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Deploy complete! The service account can now be used to connect to SecretHub from the host.")
+	fmt.Fprintln(statusWriter(cmd.io.Output()), "Deploy complete! The service account can now be used to connect to SecretHub from the host.")
 
 	return nil
 }
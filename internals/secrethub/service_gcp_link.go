@@ -173,7 +173,7 @@ func (cmd *ServiceGCPDeleteLinkCommand) Run() error {
 		return err
 	} else if !confirm {
 		fmt.Println("Aborting.")
-		return nil
+		return ErrAborted
 	}
 
 	return client.IDPLinks().GCP().Delete(cmd.namespace.String(), cmd.projectID.String())
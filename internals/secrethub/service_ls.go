@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -15,6 +16,7 @@ import (
 type ServiceLsCommand struct {
 	repoPath api.RepoPath
 	quiet    bool
+	output   string
 
 	io              ui.IO
 	useTimestamps   bool
@@ -63,15 +65,16 @@ func (cmd *ServiceLsCommand) Register(r command.Registerer) {
 	clause := r.Command("ls", cmd.help)
 	clause.Alias("list")
 	clause.Arg("repo-path", "The path to the repository to list services for").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.repoPath)
-	clause.Flag("quiet", "Only print service IDs.").Short('q').BoolVar(&cmd.quiet)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFlag(clause, &cmd.output, formatTable, formatJSON, formatYAML)
 
 	command.BindAction(clause, cmd.Run)
 }
 
-// Run lists all service accounts in a given repository.
 // Run lists all service accounts in a given repository.
 func (cmd *ServiceLsCommand) Run() error {
+	cmd.quiet = cmd.quiet || quietOutput
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
@@ -93,11 +96,18 @@ outer:
 		included = append(included, service)
 	}
 
-	if cmd.quiet {
+	switch {
+	case cmd.output != formatTable && cmd.output != "":
+		out := make([]serviceLSOutput, len(included))
+		for i, service := range included {
+			out[i] = newServiceLSOutput(service)
+		}
+		return writeOutput(cmd.io.Output(), cmd.output, out)
+	case cmd.quiet:
 		for _, service := range included {
 			fmt.Fprintf(cmd.io.Output(), "%s\n", service.ServiceID)
 		}
-	} else {
+	default:
 		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
 		serviceTable := cmd.newServiceTable(NewTimeFormatter(cmd.useTimestamps))
 
@@ -116,6 +126,32 @@ outer:
 	return nil
 }
 
+// serviceLSOutput is the machine readable format of a listed service account.
+type serviceLSOutput struct {
+	ID                 string
+	Description        string
+	CredentialType     string
+	CredentialMetadata map[string]string
+	CreatedAt          string
+}
+
+// newServiceLSOutput converts a service account to its machine readable format.
+func newServiceLSOutput(service *api.Service) serviceLSOutput {
+	out := serviceLSOutput{
+		ID:                 service.ServiceID,
+		Description:        service.Description,
+		CredentialMetadata: map[string]string{},
+		CreatedAt:          service.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if service.Credential != nil {
+		out.CredentialType = string(service.Credential.Type)
+		for key, value := range service.Credential.Metadata {
+			out.CredentialMetadata[key] = value
+		}
+	}
+	return out
+}
+
 type serviceTable interface {
 	header() []string
 	row(service *api.Service) []string
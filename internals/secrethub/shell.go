@@ -0,0 +1,147 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/masker"
+	"github.com/secrethub/secrethub-cli/internals/cli/pty"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// shellEnv is set in the environment of a masked shell, so prompts and scripts can
+// tell that they run in one.
+const shellEnv = "SECRETHUB_SHELL"
+
+// ShellCommand starts an interactive shell with secrets in its environment, of which
+// the whole terminal session is masked.
+type ShellCommand struct {
+	io                   ui.IO
+	osEnv                []string
+	shell                string
+	environment          *environment
+	maskerOptions        masker.Options
+	newClient            newClientFunc
+	ignoreMissingSecrets bool
+}
+
+// NewShellCommand creates a new ShellCommand.
+func NewShellCommand(io ui.IO, newClient newClientFunc) *ShellCommand {
+	return &ShellCommand{
+		io:          io,
+		osEnv:       os.Environ(),
+		environment: newEnvironment(io),
+		newClient:   newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ShellCommand) Register(r command.Registerer) {
+	clause := r.Command("shell", "Start an interactive shell with secrets as environment variables, like the run command. "+
+		"The shell runs in a pseudo-terminal of which all output, including the echo of what is typed, is monitored for secrets, "+
+		"so secrets are masked with \""+maskString+"\" during the whole session and not just in the output of a single command. "+
+		"The "+shellEnv+" environment variable is set in the shell, e.g. to show it in the prompt. "+
+		"Masking is a best effort attempt: a secret that is printed in pieces, for example because it is typed slowly, is not masked. "+
+		"Only supported on Linux.")
+	clause.Flag("shell", "The shell to start. Defaults to the shell in the SHELL environment variable or /bin/sh.").StringVar(&cmd.shell)
+	clause.Flag("masking-buffer-period", "The time period for which output is buffered. A higher value increases the probability that secrets get masked but decreases responsiveness.").Default("50ms").DurationVar(&cmd.maskerOptions.BufferDelay)
+	clause.Flag("ignore-missing-secrets", "Do not return an error when a secret does not exist and use an empty value instead.").BoolVar(&cmd.ignoreMissingSecrets)
+	cmd.environment.register(clause)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run starts the shell and masks its terminal until it exits.
+func (cmd *ShellCommand) Run() error {
+	run := RunCommand{
+		io:                   cmd.io,
+		osEnv:                cmd.osEnv,
+		environment:          cmd.environment,
+		newClient:            cmd.newClient,
+		ignoreMissingSecrets: cmd.ignoreMissingSecrets,
+	}
+	environment, secrets, err := run.sourceEnvironment()
+	if err != nil {
+		return err
+	}
+
+	sequences := make([][]byte, 0, len(secrets))
+	for _, val := range secrets {
+		if val != "" {
+			sequences = append(sequences, []byte(val))
+		}
+	}
+
+	shell := cmd.shellPath()
+	c := exec.Command(shell)
+	c.Env = append(environment, shellEnv+"=1")
+	master, err := pty.Start(c)
+	if err != nil {
+		return ErrStartFailed(err)
+	}
+	defer master.Close()
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Starting %s with %d secrets masked. Exit the shell to end the session.\n", shell, len(sequences))
+
+	stdin := cmd.io.Stdin()
+	stopWatching := pty.WatchSize(stdin, master)
+	defer stopWatching()
+
+	// The terminal of the shell handles line editing and echoing, so the input is passed
+	// through as it is typed.
+	var restore func()
+	if terminal.IsTerminal(int(stdin.Fd())) {
+		state, err := terminal.MakeRaw(int(stdin.Fd()))
+		if err != nil {
+			return err
+		}
+		restore = func() { _ = terminal.Restore(int(stdin.Fd()), state) }
+		defer restore()
+	}
+
+	structuredLog.maskSecrets(sequences)
+	m := masker.New(sequences, &cmd.maskerOptions)
+	out := m.AddStream(cmd.io.Stdout())
+	go m.Start()
+
+	go func() {
+		_, _ = io.Copy(master, stdin)
+	}()
+	// Reading fails once the shell has exited and closed the terminal.
+	_, _ = io.Copy(out, master)
+
+	shellErr := c.Wait()
+	err = m.Stop()
+	if err != nil {
+		return err
+	}
+
+	if exitErr, ok := shellErr.(*exec.ExitError); ok {
+		if waitStatus, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			// Exit with the status of the shell, after restoring the terminal as os.Exit skips the deferred calls.
+			if restore != nil {
+				restore()
+			}
+			os.Exit(waitStatus.ExitStatus())
+		}
+	}
+	return shellErr
+}
+
+// shellPath returns the path of the shell to start.
+func (cmd *ShellCommand) shellPath() string {
+	if cmd.shell != "" {
+		return cmd.shell
+	}
+	env, _ := parseKeyValueStringsToMap(cmd.osEnv)
+	if shell := env["SHELL"]; shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
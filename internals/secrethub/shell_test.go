@@ -0,0 +1,40 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestShellCommand_shellPath(t *testing.T) {
+	cases := map[string]struct {
+		shell    string
+		osEnv    []string
+		expected string
+	}{
+		"flag": {
+			shell:    "/bin/zsh",
+			osEnv:    []string{"SHELL=/bin/bash"},
+			expected: "/bin/zsh",
+		},
+		"environment": {
+			osEnv:    []string{"HOME=/home/dev", "SHELL=/bin/bash"},
+			expected: "/bin/bash",
+		},
+		"default": {
+			osEnv:    []string{"HOME=/home/dev"},
+			expected: "/bin/sh",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := ShellCommand{
+				shell: tc.shell,
+				osEnv: tc.osEnv,
+			}
+
+			assert.Equal(t, cmd.shellPath(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package secrethub
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrSecretNotSigned           = errMain.Code("secret_not_signed").ErrorPref("the secret %s has not been signed")
+	ErrSignatureVersion          = errMain.Code("signature_version").ErrorPref("the signature of %s is for version %d, not for version %d")
+	ErrInvalidSignature          = errMain.Code("invalid_signature").ErrorPref("the signature of %s is invalid: %s")
+	ErrUntrustedSigner           = errMain.Code("untrusted_signer").ErrorPref("%s is signed with the key %s, which is not one of the trusted signers")
+	ErrCannotSignSignature       = errMain.Code("cannot_sign_signature").Error("signatures themselves cannot be signed")
+	ErrCredentialCannotSign      = errMain.Code("credential_cannot_sign").Error("the configured credential cannot be used to sign secrets")
+	ErrSignerFingerprintTooShort = errMain.Code("signer_fingerprint_too_short").ErrorPref("the fingerprint of a trusted signer must have at least %d characters")
+)
+
+// secretSignatureSuffix is appended to the path of a secret to get the path of the secret that
+// contains its signature. Signatures are stored as secrets next to the secret they sign, so anyone
+// who can read the secret can also verify it.
+const secretSignatureSuffix = ".sig"
+
+// secretSignature is a detached signature over a version of a secret.
+type secretSignature struct {
+	Version     int       `json:"version"`
+	Signer      string    `json:"signer"`
+	Fingerprint string    `json:"fingerprint"`
+	PublicKey   string    `json:"public_key"`
+	Signature   string    `json:"signature"`
+	SignedAt    time.Time `json:"signed_at"`
+}
+
+// signer creates signatures with the private key of a credential.
+type signer interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// secretPathWithoutVersion returns the path of the secret without the version.
+func secretPathWithoutVersion(secretPath api.SecretPath) string {
+	return strings.SplitN(secretPath.Value(), ":", 2)[0]
+}
+
+// secretSignaturePath returns the path of the secret that contains the signature of the secret.
+func secretSignaturePath(secretPath api.SecretPath) string {
+	return secretPathWithoutVersion(secretPath) + secretSignatureSuffix
+}
+
+// signatureMessage returns the message that is signed for the version of a secret.
+// It contains the path and the version of the secret, so a signature cannot be moved to
+// another secret or be replayed for an older version.
+func signatureMessage(secretPath api.SecretPath, version int, data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return []byte(fmt.Sprintf("secrethub-secret-signature-v1\n%s\n%d\n%s\n", secretPathWithoutVersion(secretPath), version, hex.EncodeToString(sum[:])))
+}
+
+// SignCommand signs a version of a secret with the key of the configured credential.
+type SignCommand struct {
+	io              ui.IO
+	path            api.SecretPath
+	credentialStore CredentialConfig
+	newClient       newClientFunc
+	now             func() time.Time
+}
+
+// NewSignCommand creates a new SignCommand.
+func NewSignCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *SignCommand {
+	return &SignCommand{
+		io:              io,
+		credentialStore: credentialStore,
+		newClient:       newClient,
+		now:             time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SignCommand) Register(r command.Registerer) {
+	clause := r.Command("sign", "Sign a version of a secret with the key of your credential, so others can verify with `verify` that it "+
+		"was written by you and has not been changed since. The signature is stored in a secret next to the signed secret, "+
+		"with the name of the secret followed by "+secretSignatureSuffix+". Only the signed version is covered, so sign every new version you write.")
+	clause.Arg("secret-path", "The path to the secret to sign. Defaults to the latest version.").Required().PlaceHolder(secretPathOptionalVersionPlaceHolder).SetValue(&cmd.path)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run signs the secret.
+func (cmd *SignCommand) Run() error {
+	if strings.HasSuffix(secretPathWithoutVersion(cmd.path), secretSignatureSuffix) {
+		return ErrCannotSignSignature
+	}
+
+	key, err := cmd.credentialStore.Import()
+	if err != nil {
+		return err
+	}
+	credential, ok := key.Verifier().(signer)
+	if !ok {
+		return ErrCredentialCannotSign
+	}
+	publicKey, fingerprint, err := key.Verifier().Export()
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	me, err := client.Me().GetUser()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	signature, err := credential.Sign(signatureMessage(cmd.path, secret.Version, secret.Data))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(secretSignature{
+		Version:     secret.Version,
+		Signer:      me.Username,
+		Fingerprint: fingerprint,
+		PublicKey:   base64.StdEncoding.EncodeToString(publicKey),
+		Signature:   base64.StdEncoding.EncodeToString(signature),
+		SignedAt:    cmd.now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Secrets().Write(secretSignaturePath(cmd.path), data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Signed version %d of %s with the key %s.\n", secret.Version, secretPathWithoutVersion(cmd.path), fingerprint)
+	return nil
+}
+
+// VerifyCommand verifies the signature of a version of a secret.
+type VerifyCommand struct {
+	io        ui.IO
+	path      api.SecretPath
+	signers   []string
+	newClient newClientFunc
+}
+
+// NewVerifyCommand creates a new VerifyCommand.
+func NewVerifyCommand(io ui.IO, newClient newClientFunc) *VerifyCommand {
+	return &VerifyCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *VerifyCommand) Register(r command.Registerer) {
+	clause := r.Command("verify", "Verify that a version of a secret has been signed with `sign` and has not been changed since. "+
+		"Anyone with write access could replace both the secret and its signature, so use --signer to only accept signatures "+
+		"made with the keys of trusted producers. The fingerprints of your keys are shown by `credential ls`.")
+	clause.Arg("secret-path", "The path to the secret to verify. Defaults to the latest version.").Required().PlaceHolder(secretPathOptionalVersionPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("signer", "The fingerprint of a key that is trusted to sign the secret. Can be repeated to trust multiple keys. "+
+		fmt.Sprintf("At least the first %d characters must be entered.", api.ShortCredentialFingerprintMinimumLength)).StringsVar(&cmd.signers)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run verifies the signature of the secret.
+func (cmd *VerifyCommand) Run() error {
+	for _, fingerprint := range cmd.signers {
+		if len(fingerprint) < api.ShortCredentialFingerprintMinimumLength {
+			return ErrSignerFingerprintTooShort(api.ShortCredentialFingerprintMinimumLength)
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	signature, err := getSecretSignature(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	err = signature.verify(cmd.path, secret.Version, secret.Data)
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.signers) > 0 && !isTrustedSigner(signature.Fingerprint, cmd.signers) {
+		return ErrUntrustedSigner(secretPathWithoutVersion(cmd.path), signature.Fingerprint)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Version %d of %s was signed by %s with the key %s on %s.\n",
+		secret.Version, secretPathWithoutVersion(cmd.path), signature.Signer, signature.Fingerprint, signature.SignedAt.Format(time.RFC3339))
+	if len(cmd.signers) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "%s the key is not checked against a trusted signer. Use --signer to only accept signatures of trusted keys.\n",
+			colorize(colorRoleWarning, "Warning:"))
+	}
+	return nil
+}
+
+// getSecretSignature returns the signature of the secret.
+func getSecretSignature(client secrethub.ClientInterface, secretPath api.SecretPath) (*secretSignature, error) {
+	secret, err := client.Secrets().Versions().GetWithData(secretSignaturePath(secretPath))
+	if api.IsErrNotFound(err) {
+		return nil, ErrSecretNotSigned(secretPathWithoutVersion(secretPath))
+	} else if err != nil {
+		return nil, err
+	}
+
+	var signature secretSignature
+	err = json.Unmarshal(secret.Data, &signature)
+	if err != nil {
+		return nil, ErrInvalidSignature(secretPathWithoutVersion(secretPath), err)
+	}
+	return &signature, nil
+}
+
+// verify returns an error when the signature is not a valid signature of the version of the secret
+// made with the key of its fingerprint.
+func (s secretSignature) verify(secretPath api.SecretPath, version int, data []byte) error {
+	path := secretPathWithoutVersion(secretPath)
+	if s.Version != version {
+		return ErrSignatureVersion(path, s.Version, version)
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(s.PublicKey)
+	if err != nil {
+		return ErrInvalidSignature(path, err)
+	}
+	if api.GetFingerprint(api.CredentialTypeKey, publicKey) != s.Fingerprint {
+		return ErrInvalidSignature(path, "the public key does not match the fingerprint")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil {
+		return ErrInvalidSignature(path, err)
+	}
+	err = crypto.Verify(publicKey, signatureMessage(secretPath, version, data), signature)
+	if err != nil {
+		return ErrInvalidSignature(path, "the secret has been changed after it was signed")
+	}
+	return nil
+}
+
+// isTrustedSigner returns whether the fingerprint starts with one of the trusted fingerprints.
+func isTrustedSigner(fingerprint string, trusted []string) bool {
+	for _, t := range trusted {
+		if strings.HasPrefix(fingerprint, strings.ToLower(t)) {
+			return true
+		}
+	}
+	return false
+}
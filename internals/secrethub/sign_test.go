@@ -0,0 +1,130 @@
+package secrethub
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestVerifyCommand_Run(t *testing.T) {
+	credential, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+	publicKey, fingerprint, err := credential.Export()
+	assert.OK(t, err)
+
+	sign := func(path api.SecretPath, version int, data string) string {
+		signature, err := credential.Sign(signatureMessage(path, version, []byte(data)))
+		assert.OK(t, err)
+		encoded, err := json.Marshal(secretSignature{
+			Version:     version,
+			Signer:      "producer",
+			Fingerprint: fingerprint,
+			PublicKey:   base64.StdEncoding.EncodeToString(publicKey),
+			Signature:   base64.StdEncoding.EncodeToString(signature),
+			SignedAt:    time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+		})
+		assert.OK(t, err)
+		return string(encoded)
+	}
+
+	cases := map[string]struct {
+		path      api.SecretPath
+		signers   []string
+		data      string
+		signature string
+		out       string
+		err       error
+	}{
+		"trusted signer": {
+			path:      "namespace/repo/secret",
+			signers:   []string{fingerprint[:10]},
+			data:      "value",
+			signature: sign("namespace/repo/secret", 2, "value"),
+			out:       "Version 2 of namespace/repo/secret was signed by producer with the key " + fingerprint + " on 2020-01-01T12:00:00Z.\n",
+		},
+		"no trusted signers": {
+			path:      "namespace/repo/secret:2",
+			data:      "value",
+			signature: sign("namespace/repo/secret", 2, "value"),
+			out: "Version 2 of namespace/repo/secret was signed by producer with the key " + fingerprint + " on 2020-01-01T12:00:00Z.\n" +
+				"Warning: the key is not checked against a trusted signer. Use --signer to only accept signatures of trusted keys.\n",
+		},
+		"untrusted signer": {
+			path:      "namespace/repo/secret",
+			signers:   []string{"0123456789abcdef"},
+			data:      "value",
+			signature: sign("namespace/repo/secret", 2, "value"),
+			err:       ErrUntrustedSigner("namespace/repo/secret", fingerprint),
+		},
+		"changed value": {
+			path:      "namespace/repo/secret",
+			data:      "changed",
+			signature: sign("namespace/repo/secret", 2, "value"),
+			err:       ErrInvalidSignature("namespace/repo/secret", "the secret has been changed after it was signed"),
+		},
+		"moved signature": {
+			path:      "namespace/repo/secret",
+			data:      "value",
+			signature: sign("namespace/repo/other", 2, "value"),
+			err:       ErrInvalidSignature("namespace/repo/secret", "the secret has been changed after it was signed"),
+		},
+		"older version signed": {
+			path:      "namespace/repo/secret",
+			data:      "value",
+			signature: sign("namespace/repo/secret", 1, "value"),
+			err:       ErrSignatureVersion("namespace/repo/secret", 1, 2),
+		},
+		"not signed": {
+			path: "namespace/repo/secret",
+			data: "value",
+			err:  ErrSecretNotSigned("namespace/repo/secret"),
+		},
+		"signer too short": {
+			path:    "namespace/repo/secret",
+			signers: []string{"abc"},
+			err:     ErrSignerFingerprintTooShort(api.ShortCredentialFingerprintMinimumLength),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fakeIO := fakeui.NewIO(t)
+			cmd := VerifyCommand{
+				io:      fakeIO,
+				path:    tc.path,
+				signers: tc.signers,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									if path == "namespace/repo/secret.sig" {
+										if tc.signature == "" {
+											return nil, api.ErrSecretNotFound
+										}
+										return &api.SecretVersion{Version: 1, Data: []byte(tc.signature)}, nil
+									}
+									return &api.SecretVersion{Version: 2, Data: []byte(tc.data)}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, fakeIO.Out.String(), tc.out)
+		})
+	}
+}
@@ -79,7 +79,7 @@ func (cmd *SignUpCommand) Run() error {
 
 			if !confirmed {
 				fmt.Fprintln(cmd.io.Output(), "Aborting.")
-				return nil
+				return ErrAborted
 			}
 		}
 
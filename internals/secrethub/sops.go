@@ -0,0 +1,243 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrSopsFailed = errMain.Code("sops_failed").ErrorPref("sops failed: %s")
+)
+
+// The environment variables with which SOPS reads age keys.
+const (
+	sopsAgeKeyEnvar        = "SOPS_AGE_KEY"
+	sopsAgeRecipientsEnvar = "SOPS_AGE_RECIPIENTS"
+)
+
+// runSopsFunc runs sops with the given arguments and additional environment variables.
+type runSopsFunc func(io ui.IO, args []string, env []string) error
+
+// runSops runs the sops binary, connected to the standard input and the output of the CLI.
+func runSops(io ui.IO, args []string, env []string) error {
+	sops := exec.Command("sops", args...)
+	sops.Env = append(os.Environ(), env...)
+	sops.Stdin = os.Stdin
+	sops.Stdout = io.Stdout()
+	sops.Stderr = os.Stderr
+
+	err := sops.Run()
+	if err != nil {
+		return ErrSopsFailed(err)
+	}
+	return nil
+}
+
+// SopsCommand handles using SecretHub for the key custody of SOPS encrypted files.
+type SopsCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewSopsCommand creates a new SopsCommand.
+func NewSopsCommand(io ui.IO, newClient newClientFunc) *SopsCommand {
+	return &SopsCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *SopsCommand) Register(r command.Registerer) {
+	clause := r.Command("sops", "Use SecretHub for the key custody of files encrypted with SOPS (https://github.com/mozilla/sops). "+
+		"The files are encrypted with an age key that is stored as a SecretHub secret, so access to the files is managed with the access rules of the secret. "+
+		"Requires SOPS 3.7.2 or later on the PATH.")
+	NewSopsInitCommand(cmd.io, cmd.newClient).Register(clause)
+	NewSopsCryptCommand(cmd.io, cmd.newClient, true).Register(clause)
+	NewSopsCryptCommand(cmd.io, cmd.newClient, false).Register(clause)
+	NewSopsExecCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// readSopsKey reads the age identity stored in the secret at the given path.
+func readSopsKey(client secrethub.ClientInterface, path api.SecretPath) (ageIdentity, error) {
+	version, err := client.Secrets().Versions().GetWithData(path.Value())
+	if err != nil {
+		return ageIdentity{}, err
+	}
+	return parseAgeIdentity(string(version.Data))
+}
+
+// sopsEnv returns the environment variables with which SOPS encrypts to and decrypts with the identity.
+func sopsEnv(identity ageIdentity) []string {
+	return []string{
+		sopsAgeKeyEnvar + "=" + identity.String(),
+		sopsAgeRecipientsEnvar + "=" + identity.Recipient().String(),
+	}
+}
+
+// SopsInitCommand generates an age key for SOPS and stores it as a secret.
+type SopsInitCommand struct {
+	io        ui.IO
+	path      api.SecretPath
+	force     bool
+	newClient newClientFunc
+}
+
+// NewSopsInitCommand creates a new SopsInitCommand.
+func NewSopsInitCommand(io ui.IO, newClient newClientFunc) *SopsInitCommand {
+	return &SopsInitCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SopsInitCommand) Register(r command.Registerer) {
+	clause := r.Command("init", "Generate an age key for SOPS, store it as a secret and print the age recipient to use in the creation rules of .sops.yaml.")
+	clause.Arg("key-path", "The path to the secret to store the key in").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.path)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run generates and stores the key.
+func (cmd *SopsInitCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.Secrets().Exists(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+	if exists && !cmd.force {
+		return ErrSecretAlreadyExists
+	}
+
+	identity, err := generateAgeIdentity()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Secrets().Write(cmd.path.Value(), []byte(identity.String()))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Stored a new SOPS key in %s.\n\n", cmd.path)
+	fmt.Fprintf(cmd.io.Output(), "Add its age recipient to the creation rules in .sops.yaml:\n\n")
+	fmt.Fprintf(cmd.io.Output(), "creation_rules:\n  - age: %s\n", identity.Recipient())
+	return nil
+}
+
+// SopsCryptCommand encrypts or decrypts a file with SOPS using a key stored in SecretHub.
+type SopsCryptCommand struct {
+	io        ui.IO
+	encrypt   bool
+	keyPath   api.SecretPath
+	file      string
+	inPlace   bool
+	newClient newClientFunc
+	runSops   runSopsFunc
+}
+
+// NewSopsCryptCommand creates a new SopsCryptCommand that encrypts when encrypt is set and decrypts otherwise.
+func NewSopsCryptCommand(io ui.IO, newClient newClientFunc, encrypt bool) *SopsCryptCommand {
+	return &SopsCryptCommand{
+		io:        io,
+		encrypt:   encrypt,
+		newClient: newClient,
+		runSops:   runSops,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SopsCryptCommand) Register(r command.Registerer) {
+	name, help := "decrypt", "Decrypt a SOPS encrypted file with the age key stored in SecretHub and print the result."
+	if cmd.encrypt {
+		name, help = "encrypt", "Encrypt a file with SOPS to the age key stored in SecretHub and print the result."
+	}
+
+	clause := r.Command(name, help)
+	clause.Arg("key-path", "The path to the secret with the key").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.keyPath)
+	clause.Arg("file", "The file to "+name).Required().ExistingFileVar(&cmd.file)
+	clause.Flag("in-place", "Write the result to the file instead of printing it.").Short('i').BoolVar(&cmd.inPlace)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run encrypts or decrypts the file.
+func (cmd *SopsCryptCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	identity, err := readSopsKey(client, cmd.keyPath)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"--decrypt"}
+	if cmd.encrypt {
+		args = []string{"--encrypt", "--age", identity.Recipient().String()}
+	}
+	if cmd.inPlace {
+		args = append(args, "--in-place")
+	}
+	args = append(args, cmd.file)
+
+	return cmd.runSops(cmd.io, args, sopsEnv(identity))
+}
+
+// SopsExecCommand runs sops with access to a key stored in SecretHub.
+type SopsExecCommand struct {
+	io        ui.IO
+	keyPath   api.SecretPath
+	args      []string
+	newClient newClientFunc
+	runSops   runSopsFunc
+}
+
+// NewSopsExecCommand creates a new SopsExecCommand.
+func NewSopsExecCommand(io ui.IO, newClient newClientFunc) *SopsExecCommand {
+	return &SopsExecCommand{
+		io:        io,
+		newClient: newClient,
+		runSops:   runSops,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SopsExecCommand) Register(r command.Registerer) {
+	clause := r.Command("exec", "Run sops with the given arguments, with "+sopsAgeKeyEnvar+" and "+sopsAgeRecipientsEnvar+" set to the age key stored in SecretHub, "+
+		"e.g. `secrethub sops exec <key-path> -- secrets.enc.yaml` to edit an encrypted file.")
+	clause.Arg("key-path", "The path to the secret with the key").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.keyPath)
+	clause.Arg("args", "The arguments to pass to sops").StringsVar(&cmd.args)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run runs sops.
+func (cmd *SopsExecCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	identity, err := readSopsKey(client, cmd.keyPath)
+	if err != nil {
+		return err
+	}
+
+	return cmd.runSops(cmd.io, cmd.args, sopsEnv(identity))
+}
@@ -0,0 +1,128 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestSopsInitCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		exists bool
+		force  bool
+		err    error
+	}{
+		"new key": {},
+		"existing key": {
+			exists: true,
+			err:    ErrSecretAlreadyExists,
+		},
+		"overwrite existing key": {
+			exists: true,
+			force:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written string
+			io := fakeui.NewIO(t)
+			cmd := SopsInitCommand{
+				io:    io,
+				path:  "namespace/repo/sops-key",
+				force: tc.force,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return importTestClient{
+						secrets: importTestSecretService{
+							SecretService: &fakeclient.SecretService{
+								WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+									assert.Equal(t, path, "namespace/repo/sops-key")
+									written = string(data)
+									return nil, nil
+								},
+							},
+							existing: map[string]bool{"namespace/repo/sops-key": tc.exists},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				assert.Equal(t, written, "")
+				return
+			}
+
+			identity, err := parseAgeIdentity(written)
+			assert.OK(t, err)
+			assert.Equal(t, io.Out.String(), "Stored a new SOPS key in namespace/repo/sops-key.\n\n"+
+				"Add its age recipient to the creation rules in .sops.yaml:\n\n"+
+				"creation_rules:\n  - age: "+identity.Recipient().String()+"\n")
+		})
+	}
+}
+
+func TestSopsCryptCommand_Run(t *testing.T) {
+	identity, err := generateAgeIdentity()
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		encrypt bool
+		inPlace bool
+		args    []string
+	}{
+		"encrypt": {
+			encrypt: true,
+			args:    []string{"--encrypt", "--age", identity.Recipient().String(), "secrets.yaml"},
+		},
+		"decrypt in place": {
+			inPlace: true,
+			args:    []string{"--decrypt", "--in-place", "secrets.yaml"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var args, env []string
+			cmd := SopsCryptCommand{
+				io:      fakeui.NewIO(t),
+				encrypt: tc.encrypt,
+				keyPath: "namespace/repo/sops-key",
+				file:    "secrets.yaml",
+				inPlace: tc.inPlace,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									return &api.SecretVersion{Data: []byte(identity.String() + "\n")}, nil
+								},
+							},
+						},
+					}, nil
+				},
+				runSops: func(io ui.IO, a []string, e []string) error {
+					args, env = a, e
+					return nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.OK(t, err)
+			assert.Equal(t, args, tc.args)
+			assert.Equal(t, env, []string{
+				"SOPS_AGE_KEY=" + identity.String(),
+				"SOPS_AGE_RECIPIENTS=" + identity.Recipient().String(),
+			})
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package secrethub
+
+import (
+	"crypto/ed25519"
+	"net"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// Errors
+var (
+	ErrNoSSHAgent         = errMain.Code("no_ssh_agent").Error("no ssh-agent is running: the SSH_AUTH_SOCK environment variable is not set")
+	ErrCannotConnectAgent = errMain.Code("cannot_connect_agent").ErrorPref("cannot connect to the ssh-agent: %s")
+	ErrAgentRefusedKey    = errMain.Code("agent_refused_key").ErrorPref("the ssh-agent did not add the key: %s")
+	ErrInvalidSSHKey      = errMain.Code("invalid_ssh_key").ErrorPref("%s is not an SSH private key: %s")
+	ErrEncryptedSSHKey    = errMain.Code("encrypted_ssh_key").ErrorPref("%s is encrypted with a passphrase, which is not supported: SecretHub already encrypts the key, so store it without a passphrase")
+)
+
+// SSHCommand handles storing SSH keys in SecretHub and using them.
+type SSHCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewSSHCommand creates a new SSHCommand.
+func NewSSHCommand(io ui.IO, newClient newClientFunc) *SSHCommand {
+	return &SSHCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *SSHCommand) Register(r command.Registerer) {
+	clause := r.Command("ssh", "Store SSH keys in SecretHub and load them into the ssh-agent, so key files do not have to be copied around.")
+	NewSSHAddCommand(cmd.io, cmd.newClient).Register(clause)
+	NewSSHKeygenCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// dialAgentFunc connects to the ssh-agent.
+type dialAgentFunc func() (net.Conn, error)
+
+// dialAgent connects to the ssh-agent at the socket in the SSH_AUTH_SOCK environment variable.
+func dialAgent() (net.Conn, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, ErrNoSSHAgent
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, ErrCannotConnectAgent(err)
+	}
+	return conn, nil
+}
+
+// addToAgent adds a private key to the ssh-agent. The agent only accepts ed25519 keys by reference,
+// so keys that are parsed from PKCS #8 are converted.
+func addToAgent(dial dialAgentFunc, key interface{}, comment string, lifetime uint32, confirm bool) error {
+	if ed25519Key, ok := key.(ed25519.PrivateKey); ok {
+		key = &ed25519Key
+	}
+
+	conn, err := dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = agent.NewClient(conn).Add(agent.AddedKey{
+		PrivateKey:       key,
+		Comment:          comment,
+		LifetimeSecs:     lifetime,
+		ConfirmBeforeUse: confirm,
+	})
+	if err != nil {
+		return ErrAgentRefusedKey(err)
+	}
+	return nil
+}
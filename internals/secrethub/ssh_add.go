@@ -0,0 +1,78 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHAddCommand loads a private key that is stored in SecretHub into the ssh-agent.
+type SSHAddCommand struct {
+	io        ui.IO
+	path      api.SecretPath
+	lifetime  time.Duration
+	confirm   bool
+	newClient newClientFunc
+	dialAgent dialAgentFunc
+}
+
+// NewSSHAddCommand creates a new SSHAddCommand.
+func NewSSHAddCommand(io ui.IO, newClient newClientFunc) *SSHAddCommand {
+	return &SSHAddCommand{
+		io:        io,
+		newClient: newClient,
+		dialAgent: dialAgent,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SSHAddCommand) Register(r command.Registerer) {
+	clause := r.Command("add", "Load a private key that is stored in a secret into the running ssh-agent, like ssh-add does for key files. "+
+		"The key is sent to the agent directly and is never written to disk. "+
+		"Keys in the OpenSSH, PKCS #1, PKCS #8 and EC formats are supported, but they cannot be encrypted with a passphrase.")
+	clause.Arg("secret-path", "The path to the secret with the private key").Required().PlaceHolder(secretPathOptionalVersionPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("lifetime", "Remove the key from the agent after this duration, e.g. 8h. By default the key stays in the agent until it stops.").DurationVar(&cmd.lifetime)
+	clause.Flag("confirm", "Let the agent ask for confirmation every time the key is used.").BoolVar(&cmd.confirm)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run reads the private key and adds it to the agent.
+func (cmd *SSHAddCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	key, err := ssh.ParseRawPrivateKey(secret.Data)
+	if err != nil {
+		if strings.Contains(err.Error(), "encrypted") {
+			return ErrEncryptedSSHKey(cmd.path)
+		}
+		return ErrInvalidSSHKey(cmd.path, err)
+	}
+
+	err = addToAgent(cmd.dialAgent, key, cmd.path.String(), uint32(cmd.lifetime/time.Second), cmd.confirm)
+	if err != nil {
+		return err
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.io.Output(), "Added %s (%s) to the ssh-agent.\n", cmd.path, ssh.FingerprintSHA256(signer.PublicKey()))
+	return nil
+}
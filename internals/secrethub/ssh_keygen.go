@@ -0,0 +1,209 @@
+package secrethub
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Errors
+var (
+	ErrSSHKeyExists = errMain.Code("ssh_key_exists").ErrorPref("%s already exists: use --force to replace the key")
+)
+
+// The types of SSH keys that can be generated.
+const (
+	sshKeyTypeEd25519 = "ed25519"
+	sshKeyTypeRSA     = "rsa"
+)
+
+// publicKeySuffix is appended to the path of a private key to get the path of its public key, like ssh-keygen does.
+const publicKeySuffix = ".pub"
+
+// SSHKeygenCommand generates an SSH keypair and stores it in SecretHub.
+type SSHKeygenCommand struct {
+	io        ui.IO
+	path      api.SecretPath
+	keyType   string
+	bits      int
+	comment   string
+	add       bool
+	force     bool
+	newClient newClientFunc
+	dialAgent dialAgentFunc
+}
+
+// NewSSHKeygenCommand creates a new SSHKeygenCommand.
+func NewSSHKeygenCommand(io ui.IO, newClient newClientFunc) *SSHKeygenCommand {
+	return &SSHKeygenCommand{
+		io:        io,
+		newClient: newClient,
+		dialAgent: dialAgent,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SSHKeygenCommand) Register(r command.Registerer) {
+	clause := r.Command("keygen", "Generate an SSH keypair and store it in SecretHub. The private key is written to the secret at the given path "+
+		"and the public key to the same path with "+publicKeySuffix+" appended, like ssh-keygen does. "+
+		"The public key is also printed in the authorized_keys format, so it can be added to servers right away.")
+	clause.Arg("secret-path", "The path to write the private key to").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("type", "The type of key to generate: ed25519 or rsa.").Short('t').Default(sshKeyTypeEd25519).EnumVar(&cmd.keyType, sshKeyTypeEd25519, sshKeyTypeRSA)
+	clause.Flag("bits", "The size of RSA keys in bits.").Short('b').Default("4096").IntVar(&cmd.bits)
+	clause.Flag("comment", "The comment of the public key. Defaults to the path of the secret.").Short('C').StringVar(&cmd.comment)
+	clause.Flag("add", "Also load the key into the running ssh-agent.").BoolVar(&cmd.add)
+	clause.Flag("force", "Replace the key when the secrets at the paths already exist.").Short('f').BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run generates the keypair and writes it.
+func (cmd *SSHKeygenCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	publicKeyPath := cmd.path.Value() + publicKeySuffix
+	if !cmd.force {
+		for _, path := range []string{cmd.path.Value(), publicKeyPath} {
+			_, err := client.Secrets().Get(path)
+			if err == nil {
+				return ErrSSHKeyExists(path)
+			} else if !api.IsErrNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	key, privateKey, err := generateSSHKey(cmd.keyType, cmd.bits)
+	if err != nil {
+		return err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return err
+	}
+
+	comment := cmd.comment
+	if comment == "" {
+		comment = cmd.path.Value()
+	}
+	authorizedKey := ssh.MarshalAuthorizedKey(signer.PublicKey())
+	authorizedKey = append(authorizedKey[:len(authorizedKey)-1], []byte(" "+comment+"\n")...)
+
+	_, err = client.Secrets().Write(cmd.path.Value(), privateKey)
+	if err != nil {
+		return err
+	}
+	_, err = client.Secrets().Write(publicKeyPath, authorizedKey)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "Written the private key to %s and the public key to %s.\n", cmd.path, publicKeyPath)
+
+	if cmd.add {
+		err = addToAgent(cmd.dialAgent, key, cmd.path.Value(), 0, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(statusWriter(cmd.io.Output()), "Added %s (%s) to the ssh-agent.\n", cmd.path, ssh.FingerprintSHA256(signer.PublicKey()))
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "%s", authorizedKey)
+	return nil
+}
+
+// generateSSHKey generates a private key and returns it with its encoding in the format that
+// ssh-keygen uses for its type, so the key can also be used as a file.
+func generateSSHKey(keyType string, bits int) (interface{}, []byte, error) {
+	switch keyType {
+	case sshKeyTypeRSA:
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}), nil
+	default:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded, err := marshalOpenSSHEd25519Key(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, encoded, nil
+	}
+}
+
+// marshalOpenSSHEd25519Key encodes an unencrypted ed25519 private key in the OpenSSH format,
+// as specified in PROTOCOL.key of OpenSSH. OpenSSH only reads ed25519 keys in this format.
+func marshalOpenSSHEd25519Key(key ed25519.PrivateKey) ([]byte, error) {
+	check := make([]byte, 4)
+	_, err := rand.Read(check)
+	if err != nil {
+		return nil, err
+	}
+	checkInt := binary.BigEndian.Uint32(check)
+
+	publicKey := key.Public().(ed25519.PublicKey)
+	private := struct {
+		Check1  uint32
+		Check2  uint32
+		KeyType string
+		Pub     []byte
+		Priv    []byte
+		Comment string
+		Pad     []byte `ssh:"rest"`
+	}{
+		Check1:  checkInt,
+		Check2:  checkInt,
+		KeyType: ssh.KeyAlgoED25519,
+		Pub:     publicKey,
+		Priv:    key,
+	}
+	// The private section is padded with 1, 2, 3, ... to a multiple of the block size of 8,
+	// which is the block size of the "none" cipher.
+	for i := 1; len(ssh.Marshal(private))%8 != 0; i++ {
+		private.Pad = append(private.Pad, byte(i))
+	}
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	encoded := ssh.Marshal(struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{
+		CipherName:   "none",
+		KdfName:      "none",
+		NumKeys:      1,
+		PubKey:       sshPublicKey.Marshal(),
+		PrivKeyBlock: ssh.Marshal(private),
+	})
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "OPENSSH PRIVATE KEY",
+		Bytes: append([]byte("openssh-key-v1\x00"), encoded...),
+	}), nil
+}
@@ -0,0 +1,179 @@
+package secrethub
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// fakeAgent returns a keyring and a function that connects to an ssh-agent that serves it.
+func fakeAgent() (agent.Agent, dialAgentFunc) {
+	keyring := agent.NewKeyring()
+	return keyring, func() (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			_ = agent.ServeAgent(keyring, server)
+		}()
+		return client, nil
+	}
+}
+
+func TestSSHAddCommand_Run(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.OK(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	assert.OK(t, err)
+	_, ed25519PEM, err := generateSSHKey(sshKeyTypeEd25519, 0)
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		value []byte
+		err   error
+	}{
+		"openssh ed25519": {
+			value: ed25519PEM,
+		},
+		"ec": {
+			value: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}),
+		},
+		"encrypted": {
+			value: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Headers: map[string]string{"Proc-Type": "4,ENCRYPTED"}, Bytes: ecDER}),
+			err:   ErrEncryptedSSHKey("namespace/repo/id_ed25519"),
+		},
+		"not a key": {
+			value: []byte("hunter2"),
+			err:   ErrInvalidSSHKey("namespace/repo/id_ed25519", "ssh: no key found"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			keyring, dial := fakeAgent()
+			io := fakeui.NewIO(t)
+			cmd := SSHAddCommand{
+				io:   io,
+				path: "namespace/repo/id_ed25519",
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									return &api.SecretVersion{Data: tc.value}, nil
+								},
+							},
+						},
+					}, nil
+				},
+				dialAgent: dial,
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			keys, err := keyring.List()
+			assert.OK(t, err)
+			if tc.err != nil {
+				assert.Equal(t, len(keys), 0)
+				return
+			}
+			assert.Equal(t, len(keys), 1)
+			assert.Equal(t, keys[0].Comment, "namespace/repo/id_ed25519")
+			assert.Equal(t, io.Out.String(), "Added namespace/repo/id_ed25519 ("+ssh.FingerprintSHA256(keys[0])+") to the ssh-agent.\n")
+		})
+	}
+}
+
+func TestSSHKeygenCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		keyType string
+		exists  bool
+		force   bool
+		err     error
+	}{
+		"ed25519": {
+			keyType: sshKeyTypeEd25519,
+		},
+		"rsa": {
+			keyType: sshKeyTypeRSA,
+		},
+		"exists": {
+			keyType: sshKeyTypeEd25519,
+			exists:  true,
+			err:     ErrSSHKeyExists("namespace/repo/id_ed25519"),
+		},
+		"force": {
+			keyType: sshKeyTypeEd25519,
+			exists:  true,
+			force:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			keyring, dial := fakeAgent()
+			written := map[string][]byte{}
+			io := fakeui.NewIO(t)
+			cmd := SSHKeygenCommand{
+				io:      io,
+				path:    "namespace/repo/id_ed25519",
+				keyType: tc.keyType,
+				bits:    1024,
+				add:     true,
+				force:   tc.force,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							GetFunc: func(path string) (*api.Secret, error) {
+								if tc.exists {
+									return &api.Secret{}, nil
+								}
+								return nil, api.ErrSecretNotFound
+							},
+							WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+								written[path] = data
+								return &api.SecretVersion{}, nil
+							},
+						},
+					}, nil
+				},
+				dialAgent: dial,
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err != nil {
+				assert.Equal(t, len(written), 0)
+				return
+			}
+
+			privateKey, err := ssh.ParsePrivateKey(written["namespace/repo/id_ed25519"])
+			assert.OK(t, err)
+			publicKey, comment, _, _, err := ssh.ParseAuthorizedKey(written["namespace/repo/id_ed25519.pub"])
+			assert.OK(t, err)
+			assert.Equal(t, comment, "namespace/repo/id_ed25519")
+			assert.Equal(t, publicKey.Marshal(), privateKey.PublicKey().Marshal())
+			assert.Equal(t, strings.HasSuffix(io.Out.String(), string(written["namespace/repo/id_ed25519.pub"])), true)
+
+			keys, err := keyring.List()
+			assert.OK(t, err)
+			assert.Equal(t, len(keys), 1)
+			assert.Equal(t, keys[0].Marshal(), publicKey.Marshal())
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/nbutton23/zxcvbn-go"
+)
+
+// Errors
+var (
+	ErrWeakSecret = errMain.Code("weak_secret").ErrorPref("the secret is weak: %s")
+)
+
+// minSecretEntropy is the number of bits of entropy below which a secret is reported as weak.
+// It is the entropy zxcvbn requires for a score of 3 out of 4, which is safe against online guessing.
+const minSecretEntropy = 35
+
+// maxStrengthCheckLength is the length above which values are not estimated, as such values are
+// keys, certificates or configuration, rather than passwords, and the estimation gets slow.
+const maxStrengthCheckLength = 128
+
+// strengthPatterns describes the zxcvbn patterns in the warnings of weak secrets.
+var strengthPatterns = map[string]string{
+	"dictionary": "a common password or word",
+	"spatial":    "a keyboard pattern",
+	"repeat":     "repeated characters",
+	"sequence":   "a sequence such as abc or 123",
+	"date":       "a date",
+}
+
+// secretWeakness returns why the given value is weak, or an empty string when it is strong enough
+// or cannot be estimated. The value is estimated with zxcvbn, so passwords that look random but
+// consist of common words and patterns are reported as well.
+func secretWeakness(value []byte) string {
+	if len(value) > maxStrengthCheckLength || bytes.ContainsAny(value, "\r\n") {
+		return ""
+	}
+
+	result := zxcvbn.PasswordStrength(string(value), nil)
+	if result.Entropy >= minSecretEntropy {
+		return ""
+	}
+
+	guessTime := "in " + result.CrackTimeDisplay
+	if result.CrackTimeDisplay == "instant" {
+		guessTime = "instantly"
+	}
+
+	for _, match := range result.MatchSequence {
+		if description, ok := strengthPatterns[match.Pattern]; ok {
+			return fmt.Sprintf("it contains %s and could be guessed %s", description, guessTime)
+		}
+	}
+	return fmt.Sprintf("it is too short or simple and could be guessed %s", guessTime)
+}
+
+// generatedWeakness returns why secrets generated with the given entropy in bits are weak,
+// or an empty string when they are strong enough.
+func generatedWeakness(entropy float64) string {
+	if entropy >= minSecretEntropy {
+		return ""
+	}
+	return fmt.Sprintf("it only has %d bits of entropy, use a longer length or more characters", int(math.Floor(entropy)))
+}
+
+// checkStrength writes a warning when the secret is weak, or returns an error when
+// the strength is enforced. The weakness is empty when the secret is strong enough.
+func checkStrength(w io.Writer, weakness string, enforce bool) error {
+	if weakness == "" {
+		return nil
+	}
+	if enforce {
+		return ErrWeakSecret(weakness)
+	}
+	_, err := fmt.Fprintf(w, "%s the secret is weak: %s. Use --enforce-strength to refuse weak secrets.\n", colorize(colorRoleWarning, "Warning:"), weakness)
+	return err
+}
@@ -0,0 +1,82 @@
+package secrethub
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestSecretWeakness(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected string
+	}{
+		"common password": {
+			value:    "password1",
+			expected: "it contains a common password or word and could be guessed instantly",
+		},
+		"keyboard pattern": {
+			value:    "zxcvbnm,./",
+			expected: "it contains a keyboard pattern and could be guessed instantly",
+		},
+		"sequence": {
+			value:    "abcdefgh",
+			expected: "it contains a sequence such as abc or 123 and could be guessed instantly",
+		},
+		"short": {
+			value:    "secret value",
+			expected: "it contains a common password or word and could be guessed in 10.0 minutes",
+		},
+		"random": {
+			value: "n8Kp2xQv7Lm4",
+		},
+		"words": {
+			value: "correct-horse-battery-staple",
+		},
+		"multiline": {
+			value: "password\npassword",
+		},
+		"long": {
+			value: strings.Repeat("a", maxStrengthCheckLength+1),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, secretWeakness([]byte(tc.value)), tc.expected)
+		})
+	}
+}
+
+func TestCheckStrength(t *testing.T) {
+	cases := map[string]struct {
+		weakness string
+		enforce  bool
+		out      string
+		err      error
+	}{
+		"strong": {},
+		"warn": {
+			weakness: "it only has 13 bits of entropy, use a longer length or more characters",
+			out:      "Warning: the secret is weak: it only has 13 bits of entropy, use a longer length or more characters. Use --enforce-strength to refuse weak secrets.\n",
+		},
+		"enforce": {
+			weakness: "it only has 13 bits of entropy, use a longer length or more characters",
+			enforce:  true,
+			err:      ErrWeakSecret("it only has 13 bits of entropy, use a longer length or more characters"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := checkStrength(&out, tc.weakness, tc.enforce)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, out.String(), tc.out)
+		})
+	}
+}
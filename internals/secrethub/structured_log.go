@@ -0,0 +1,286 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/masker"
+
+	"github.com/alecthomas/kingpin"
+)
+
+// Errors
+var (
+	ErrCannotOpenLogFile = errMain.Code("cannot_open_log_file").ErrorPref("cannot open the log file %s: %s")
+)
+
+// The values of the --log-level flag, from the most to the least verbose.
+const (
+	logLevelDebug   = "debug"
+	logLevelInfo    = "info"
+	logLevelWarning = "warning"
+	logLevelError   = "error"
+)
+
+// logLevels orders the log levels by severity.
+var logLevels = map[string]int{
+	logLevelDebug:   0,
+	logLevelInfo:    1,
+	logLevelWarning: 2,
+	logLevelError:   3,
+}
+
+// The values of the --log-format flag.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// structuredLog is the logger configured with the --log-level flag.
+// It is nil when no log level is set, in which case nothing is logged.
+var structuredLog *structuredLogger
+
+// logFields are the fields of a log entry.
+type logFields map[string]interface{}
+
+// structuredLogger writes log entries with fields as lines of text or JSON. The values of fields that
+// can hold secrets are redacted and, once they are known, the values of the secrets that are used
+// by the command are masked.
+type structuredLogger struct {
+	mutex sync.Mutex
+	// w is the writer the entries are written to, which is out or the masked stream to out.
+	w         io.Writer
+	out       io.Writer
+	file      io.Closer
+	masker    *masker.Masker
+	sequences [][]byte
+	level     int
+	format    string
+	now       func() time.Time
+	start     time.Time
+}
+
+// RegisterLogFlags registers the flags that configure the structured log and creates the log before a command is run.
+func RegisterLogFlags(app *cli.App) {
+	var level, format, file string
+	app.Flag("log-level", "Write a structured log of the messages of this level and above to stderr. Options are: debug, info, warning and error. "+
+		"The debug level logs every API call with its timing, every retry and every cache hit. "+
+		"Secret material is never logged: fields that can hold secrets are redacted and the values of the secrets used by run and shell are masked.").
+		HintOptions(logLevelDebug, logLevelInfo, logLevelWarning, logLevelError).
+		EnumVar(&level, logLevelDebug, logLevelInfo, logLevelWarning, logLevelError)
+	app.Flag("log-format", "The format of the structured log. Options are: text and json, which writes every entry as a JSON object on its own line.").
+		HintOptions(logFormatText, logFormatJSON).Default(logFormatText).EnumVar(&format, logFormatText, logFormatJSON)
+	app.Flag("log-file", "Append the structured log to this file instead of writing it to stderr, e.g. to attach it to a support request.").StringVar(&file)
+
+	app.PreAction(func(context *kingpin.ParseContext) error {
+		if level == "" {
+			return nil
+		}
+
+		var w io.Writer = os.Stderr
+		var closer io.Closer
+		if file != "" {
+			f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+			if err != nil {
+				return ErrCannotOpenLogFile(file, err)
+			}
+			w, closer = f, f
+		}
+
+		// The commands of a batch can configure their own log, which replaces the log of the batch.
+		structuredLog.close()
+		structuredLog = newStructuredLogger(w, level, format)
+		structuredLog.file = closer
+
+		command := ""
+		if context.SelectedCommand != nil {
+			command = context.SelectedCommand.FullCommand()
+		}
+		structuredLog.info("command_started", logFields{"command": command, "version": Version})
+		return nil
+	})
+}
+
+// newStructuredLogger returns a logger that writes the entries of the level and above to w in the format.
+func newStructuredLogger(w io.Writer, level string, format string) *structuredLogger {
+	return &structuredLogger{
+		w:      w,
+		out:    w,
+		level:  logLevels[level],
+		format: format,
+		now:    time.Now,
+		start:  time.Now(),
+	}
+}
+
+// enabled returns whether entries of the level are logged.
+func (l *structuredLogger) enabled(level string) bool {
+	return l != nil && logLevels[level] >= l.level
+}
+
+func (l *structuredLogger) debug(event string, fields logFields) {
+	l.log(logLevelDebug, event, fields)
+}
+
+func (l *structuredLogger) info(event string, fields logFields) {
+	l.log(logLevelInfo, event, fields)
+}
+
+func (l *structuredLogger) warning(event string, fields logFields) {
+	l.log(logLevelWarning, event, fields)
+}
+
+func (l *structuredLogger) error(event string, fields logFields) {
+	l.log(logLevelError, event, fields)
+}
+
+// log writes an entry with the level, event and fields when the level is enabled.
+func (l *structuredLogger) log(level string, event string, fields logFields) {
+	if !l.enabled(level) {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := l.now().UTC().Format(time.RFC3339Nano)
+	var buf bytes.Buffer
+	if l.format == logFormatJSON {
+		buf.WriteString(`{"time":` + strconv.Quote(now) + `,"level":` + strconv.Quote(level) + `,"event":` + strconv.Quote(event))
+		for _, name := range names {
+			value, err := json.Marshal(redactLogField(name, fields[name]))
+			if err != nil {
+				value = []byte(strconv.Quote(redactedValue))
+			}
+			buf.WriteString("," + strconv.Quote(name) + ":")
+			buf.Write(value)
+		}
+		buf.WriteString("}\n")
+	} else {
+		fmt.Fprintf(&buf, "%s %s %s", now, strings.ToUpper(level), event)
+		for _, name := range names {
+			value := fmt.Sprint(redactLogField(name, fields[name]))
+			if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+				value = strconv.Quote(value)
+			}
+			fmt.Fprintf(&buf, " %s=%s", name, value)
+		}
+		buf.WriteString("\n")
+	}
+
+	_, _ = l.w.Write(buf.Bytes())
+}
+
+// redactLogField returns the value of the field, or the redacted value when the field can hold a secret.
+func redactLogField(name string, value interface{}) interface{} {
+	if isRedactedField(name) {
+		return redactedValue
+	}
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+	return value
+}
+
+// maskSecrets passes the log through a masker that masks the given secret values from now on.
+func (l *structuredLogger) maskSecrets(sequences [][]byte) {
+	if l == nil || len(sequences) == 0 {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.masker != nil {
+		// The masker is replaced by one that also masks the secrets that are already masked.
+		_ = l.masker.Stop()
+	}
+	l.sequences = append(l.sequences, sequences...)
+	l.masker = masker.New(l.sequences, &masker.Options{DisableBuffer: true})
+	l.w = l.masker.AddStream(l.out)
+	go l.masker.Start()
+}
+
+// finish logs that the command has finished with the error and closes the log.
+func (l *structuredLogger) finish(err error) {
+	if l == nil {
+		return
+	}
+
+	duration := l.now().Sub(l.start)
+	if err != nil {
+		l.error("command_failed", logFields{"duration_ms": duration.Milliseconds(), "error": err})
+	} else {
+		l.info("command_finished", logFields{"duration_ms": duration.Milliseconds()})
+	}
+	l.close()
+}
+
+// close flushes the masker and closes the log file. Nothing is logged after the log is closed.
+func (l *structuredLogger) close() {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.masker != nil {
+		_ = l.masker.Stop()
+		l.masker = nil
+	}
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+	l.w = ioutil.Discard
+}
+
+// structuredLogTransport is a http.RoundTripper that writes every API call it performs to the structured log.
+type structuredLogTransport struct {
+	next http.RoundTripper
+	log  *structuredLogger
+}
+
+// newStructuredLogTransport returns a transport that passes the API calls on to next and logs them with their timing.
+func newStructuredLogTransport(next http.RoundTripper, log *structuredLogger) *structuredLogTransport {
+	return &structuredLogTransport{
+		next: next,
+		log:  log,
+	}
+}
+
+// RoundTrip performs the request and logs it.
+func (t *structuredLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields := logFields{
+		"method":      req.Method,
+		"url":         req.URL.String(),
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err
+		t.log.warning("http_request_failed", fields)
+		return nil, err
+	}
+	fields["status"] = resp.StatusCode
+	t.log.debug("http_request", fields)
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestStructuredLogger(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		level    string
+		format   string
+		log      func(l *structuredLogger)
+		expected string
+	}{
+		"json": {
+			level:  logLevelDebug,
+			format: logFormatJSON,
+			log: func(l *structuredLogger) {
+				l.debug("http_request", logFields{"method": "GET", "status": 200})
+			},
+			expected: `{"time":"2020-07-01T12:00:00Z","level":"debug","event":"http_request","method":"GET","status":200}` + "\n",
+		},
+		"text": {
+			level:  logLevelDebug,
+			format: logFormatText,
+			log: func(l *structuredLogger) {
+				l.warning("http_request_failed", logFields{"error": errors.New("connection refused"), "method": "GET"})
+			},
+			expected: `2020-07-01T12:00:00Z WARNING http_request_failed error="connection refused" method=GET` + "\n",
+		},
+		"level filtered": {
+			level:  logLevelInfo,
+			format: logFormatText,
+			log: func(l *structuredLogger) {
+				l.debug("http_request", logFields{"method": "GET"})
+				l.info("command_started", logFields{"command": "read"})
+			},
+			expected: "2020-07-01T12:00:00Z INFO command_started command=read\n",
+		},
+		"fields with secrets redacted": {
+			level:  logLevelDebug,
+			format: logFormatJSON,
+			log: func(l *structuredLogger) {
+				l.debug("event", logFields{"password": "hunter2", "encrypted_key": "abc"})
+			},
+			expected: `{"time":"2020-07-01T12:00:00Z","level":"debug","event":"event","encrypted_key":"[REDACTED]","password":"[REDACTED]"}` + "\n",
+		},
+		"secret values masked": {
+			level:  logLevelDebug,
+			format: logFormatText,
+			log: func(l *structuredLogger) {
+				l.maskSecrets([][]byte{[]byte("hunter2")})
+				l.error("command_failed", logFields{"error": errors.New("cannot connect with hunter2")})
+			},
+			expected: "2020-07-01T12:00:00Z ERROR command_failed error=\"cannot connect with <redacted by SecretHub>\"\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := newStructuredLogger(&buf, tc.level, tc.format)
+			l.now = func() time.Time { return now }
+
+			tc.log(l)
+			l.close()
+
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestStructuredLogger_Nil(t *testing.T) {
+	var l *structuredLogger
+
+	assert.Equal(t, l.enabled(logLevelError), false)
+	l.debug("event", nil)
+	l.maskSecrets([][]byte{[]byte("secret")})
+	l.finish(nil)
+}
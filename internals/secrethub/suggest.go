@@ -0,0 +1,242 @@
+package secrethub
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kingpin"
+)
+
+// maxSuggestions is the maximum number of suggestions given for a mistyped command or path.
+const maxSuggestions = 3
+
+// unknownCommandPattern matches the error kingpin returns for a command that does not exist.
+var unknownCommandPattern = regexp.MustCompile(`^expected command but got "(.*)"$`)
+
+// suggestionError is an error for a mistyped command or path with suggestions of what was meant.
+type suggestionError struct {
+	err         error
+	suggestions []string
+}
+
+// Error returns the message of the error followed by the suggestions.
+func (e suggestionError) Error() string {
+	return e.err.Error() + "\n\n" + e.hint()
+}
+
+// Unwrap returns the error the suggestions are given for.
+func (e suggestionError) Unwrap() error {
+	return e.err
+}
+
+// hint returns the suggestions as a question.
+func (e suggestionError) hint() string {
+	if len(e.suggestions) == 1 {
+		return "Did you mean this?\n\t" + e.suggestions[0]
+	}
+	return "Did you mean one of these?\n\t" + strings.Join(e.suggestions, "\n\t")
+}
+
+// suggest adds suggestions to the error returned for the args when a command is mistyped
+// or a path does not exist. The error is returned unchanged when there is nothing to suggest.
+func (app *App) suggest(args []string, err error) error {
+	var suggestions []string
+	if !app.errorWriter.parsed {
+		suggestions = app.suggestCommands(args, err)
+	} else if exitCode, _ := classifyError(err, true); exitCode == exitCodeNotFound {
+		suggestions = app.suggestPaths(args)
+	}
+
+	if len(suggestions) == 0 {
+		return err
+	}
+	return suggestionError{
+		err:         err,
+		suggestions: suggestions,
+	}
+}
+
+// suggestCommands returns the commands that are close to the command that could not be found.
+func (app *App) suggestCommands(args []string, err error) []string {
+	matches := unknownCommandPattern.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return nil
+	}
+
+	context, _ := app.cli.ParseContext(args)
+	commands := app.cli.Model().Commands
+	prefix := ApplicationName + " "
+	if context != nil && context.SelectedCommand != nil {
+		commands = context.SelectedCommand.Model().Commands
+		prefix += context.SelectedCommand.FullCommand() + " "
+	}
+
+	suggestions := closestMatches(matches[1], commandNames(commands))
+	for i, suggestion := range suggestions {
+		suggestions[i] = prefix + suggestion
+	}
+	return suggestions
+}
+
+// commandNames returns the names and aliases of the commands that are not hidden.
+func commandNames(commands []*kingpin.CmdModel) []string {
+	var names []string
+	for _, cmd := range commands {
+		if cmd.Hidden {
+			continue
+		}
+		names = append(names, cmd.Name)
+		names = append(names, cmd.Aliases...)
+	}
+	return names
+}
+
+// suggestPaths returns the existing paths that are close to the paths passed as argument.
+func (app *App) suggestPaths(args []string) []string {
+	context, err := app.cli.ParseContext(args)
+	if err != nil {
+		return nil
+	}
+
+	client, err := app.clientFactory.NewNonInteractiveClient()
+	if err != nil {
+		return nil
+	}
+	list := func(parent string) ([]string, error) {
+		return listPathEntries(client, parent)
+	}
+
+	var suggestions []string
+	for _, element := range context.Elements {
+		arg, ok := element.Clause.(*kingpin.ArgClause)
+		if !ok || element.Value == nil || !strings.Contains(arg.Model().Name, "path") {
+			continue
+		}
+		suggestions = append(suggestions, closestPaths(list, *element.Value)...)
+	}
+	return suggestions
+}
+
+// closestPaths walks the path from its root and returns the existing paths that are
+// close to the path at the first element that does not exist. The elements after the
+// mistyped element are kept, so a typo deep in a path is corrected as a whole.
+func closestPaths(list func(parent string) ([]string, error), path string) []string {
+	path = strings.Trim(path, "/")
+	if i := strings.Index(path, ":"); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" {
+		return nil
+	}
+
+	elements := strings.Split(path, "/")
+	parent := ""
+	for i, element := range elements {
+		entries, err := list(parent)
+		if err != nil {
+			return nil
+		}
+
+		names := make([]string, len(entries))
+		exists := false
+		for j, entry := range entries {
+			names[j] = strings.TrimSuffix(strings.TrimPrefix(entry, parent), "/")
+			if names[j] == element {
+				exists = true
+			}
+		}
+		if exists {
+			parent += element + "/"
+			continue
+		}
+
+		rest := strings.Join(elements[i+1:], "/")
+		suggestions := closestMatches(element, names)
+		for j, suggestion := range suggestions {
+			suggestions[j] = parent + suggestion
+			if rest != "" {
+				suggestions[j] += "/" + rest
+			}
+		}
+		return suggestions
+	}
+	return nil
+}
+
+// closestMatches returns the candidates that are close to the word, ordered from closest to
+// farthest. A candidate is close when it can be changed into the word with at most two edits,
+// or one edit per three characters for longer words.
+func closestMatches(word string, candidates []string) []string {
+	maxDistance := len(word) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	type match struct {
+		candidate string
+		distance  int
+	}
+
+	var matches []match
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate] || candidate == word {
+			continue
+		}
+		seen[candidate] = true
+
+		distance := levenshtein(strings.ToLower(word), strings.ToLower(candidate))
+		if distance <= maxDistance {
+			matches = append(matches, match{candidate: candidate, distance: distance})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].candidate < matches[j].candidate
+	})
+
+	var res []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		res = append(res, matches[i].candidate)
+	}
+	return res
+}
+
+// levenshtein returns the minimum number of insertions, deletions and substitutions
+// of characters needed to change a into b.
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(t)]
+}
+
+// min3 returns the smallest of three integers.
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
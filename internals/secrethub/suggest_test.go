@@ -0,0 +1,140 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	cases := map[string]struct {
+		a        string
+		b        string
+		expected int
+	}{
+		"equal": {
+			a:        "read",
+			b:        "read",
+			expected: 0,
+		},
+		"empty": {
+			a:        "",
+			b:        "read",
+			expected: 4,
+		},
+		"substitution": {
+			a:        "reed",
+			b:        "read",
+			expected: 1,
+		},
+		"transposition": {
+			a:        "raed",
+			b:        "read",
+			expected: 2,
+		},
+		"insertion and deletion": {
+			a:        "inspet",
+			b:        "inspects",
+			expected: 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, levenshtein(tc.a, tc.b), tc.expected)
+		})
+	}
+}
+
+func TestClosestMatches(t *testing.T) {
+	commands := []string{"read", "rm", "repo", "run", "write", "tree", "inspect"}
+
+	cases := map[string]struct {
+		word     string
+		expected []string
+	}{
+		"transposition": {
+			word:     "wirte",
+			expected: []string{"write"},
+		},
+		"ordered by distance": {
+			word:     "rea",
+			expected: []string{"read", "repo", "rm"},
+		},
+		"case insensitive": {
+			word:     "INSPCT",
+			expected: []string{"inspect"},
+		},
+		"no matches": {
+			word: "signup",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, closestMatches(tc.word, commands), tc.expected)
+		})
+	}
+}
+
+func TestClosestPaths(t *testing.T) {
+	entries := map[string][]string{
+		"":                 {"company/", "dev1/"},
+		"company/":         {"company/prod/", "company/staging/"},
+		"company/prod/":    {"company/prod/db/", "company/prod/api_key"},
+		"company/prod/db/": {"company/prod/db/password", "company/prod/db/user"},
+		"company/staging/": {},
+		"dev1/":            {},
+	}
+	list := func(parent string) ([]string, error) {
+		res, ok := entries[parent]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return res, nil
+	}
+
+	cases := map[string]struct {
+		path     string
+		expected []string
+	}{
+		"secret": {
+			path:     "company/prod/db/pasword",
+			expected: []string{"company/prod/db/password"},
+		},
+		"deep path": {
+			path:     "company/prdo/db/password",
+			expected: []string{"company/prod/db/password"},
+		},
+		"namespace": {
+			path:     "compnay/prod",
+			expected: []string{"company/prod"},
+		},
+		"version": {
+			path:     "company/prod/api_kye:3",
+			expected: []string{"company/prod/api_key"},
+		},
+		"existing path": {
+			path: "company/prod/db",
+		},
+		"no close match": {
+			path: "company/prod/certificate",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, closestPaths(list, tc.path), tc.expected)
+		})
+	}
+}
+
+func TestSuggestionError(t *testing.T) {
+	err := suggestionError{
+		err:         errors.New(`expected command but got "raed"`),
+		suggestions: []string{"secrethub read"},
+	}
+
+	assert.Equal(t, err.Error(), "expected command but got \"raed\"\n\nDid you mean this?\n\tsecrethub read")
+}
@@ -0,0 +1,541 @@
+package secrethub
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/progress"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrSyncConflicts               = errMain.Code("sync_conflicts").ErrorPref("%d files changed both locally and in SecretHub and were not synced: resolve the conflicts or use --on-conflict")
+	ErrInvalidSyncConflictStrategy = errMain.Code("invalid_sync_conflict_strategy").ErrorPref("invalid conflict strategy %q: must be one of skip, local or remote")
+	ErrInvalidSyncDeletes          = errMain.Code("invalid_sync_deletes").ErrorPref("invalid value %q for --propagate-deletes: must be one of none, push, pull or both")
+	ErrInvalidSyncState            = errMain.Code("invalid_sync_state").ErrorPref("cannot read the sync state at %s: %s")
+)
+
+const (
+	// syncStateFileName is the name of the file in the local directory in which the state
+	// of the last sync is stored. It is used to detect changes and deletions on both sides.
+	syncStateFileName = ".secrethub-sync.json"
+	// syncIgnoreFileName is the name of the file in the local directory with ignore patterns.
+	syncIgnoreFileName = ".secrethubignore"
+
+	syncFileMode = os.FileMode(0600)
+	syncDirMode  = os.FileMode(0700)
+
+	conflictLocal  = "local"
+	conflictRemote = "remote"
+
+	syncDeletesNone = "none"
+	syncDeletesPush = "push"
+	syncDeletesPull = "pull"
+	syncDeletesBoth = "both"
+)
+
+// SyncCommand mirrors a local directory of files to a directory of secrets and back.
+type SyncCommand struct {
+	io         ui.IO
+	localDir   string
+	remotePath api.DirPath
+	watch      bool
+	interval   time.Duration
+	onConflict string
+	deletes    string
+	ignore     []string
+	newClient  newClientFunc
+	sleep      func(time.Duration)
+}
+
+// NewSyncCommand creates a new SyncCommand.
+func NewSyncCommand(io ui.IO, newClient newClientFunc) *SyncCommand {
+	return &SyncCommand{
+		io:        io,
+		newClient: newClient,
+		sleep:     time.Sleep,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SyncCommand) Register(r command.Registerer) {
+	clause := r.Command("sync", "Sync a local directory of files with a directory of secrets in both directions. "+
+		"Every file is stored as the secret with the same relative path. Files changed locally are written as a new secret version and secrets changed in SecretHub are written to their file. "+
+		"When a file changed on both sides since the last sync, it is reported as a conflict and left untouched, unless --on-conflict is set. "+
+		"The state of the last sync is stored in "+syncStateFileName+" in the local directory. Files matching the patterns in "+syncIgnoreFileName+" in the local directory are ignored.")
+	clause.Arg("local-dir", "The local directory to sync").Required().StringVar(&cmd.localDir)
+	clause.Arg("remote-path", "The directory of secrets to sync with").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.remotePath)
+	clause.Flag("watch", "Keep running and sync again every interval, until interrupted.").BoolVar(&cmd.watch)
+	clause.Flag("interval", "The interval at which to sync when --watch is set.").Default("30s").DurationVar(&cmd.interval)
+	clause.Flag("on-conflict", "What to do with files that changed both locally and in SecretHub: skip them, keep the local file or keep the secret.").HintOptions(conflictSkip, conflictLocal, conflictRemote).Default(conflictSkip).StringVar(&cmd.onConflict)
+	clause.Flag("propagate-deletes", "Which deletions to sync: none, files deleted locally (push), secrets deleted in SecretHub (pull) or both. Deletions that are not synced are restored from the other side.").HintOptions(syncDeletesNone, syncDeletesPush, syncDeletesPull, syncDeletesBoth).Default(syncDeletesNone).StringVar(&cmd.deletes)
+	clause.Flag("ignore", "Ignore files and directories of which the name or relative path matches this pattern, e.g. *.bak. Can be repeated.").StringsVar(&cmd.ignore)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run syncs the directories once, or every interval when --watch is set.
+func (cmd *SyncCommand) Run() error {
+	if cmd.onConflict != conflictSkip && cmd.onConflict != conflictLocal && cmd.onConflict != conflictRemote {
+		return ErrInvalidSyncConflictStrategy(cmd.onConflict)
+	}
+	if cmd.deletes != syncDeletesNone && cmd.deletes != syncDeletesPush && cmd.deletes != syncDeletesPull && cmd.deletes != syncDeletesBoth {
+		return ErrInvalidSyncDeletes(cmd.deletes)
+	}
+	if cmd.watch && cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	if !cmd.watch {
+		result, err := cmd.sync(client)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(statusWriter(cmd.io.Output()), "Sync complete! %s\n", result)
+		if result.conflicts > 0 {
+			return ErrSyncConflicts(result.conflicts)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Syncing %s with %s every %s.\n", cmd.localDir, cmd.remotePath, cmd.interval)
+	for {
+		result, err := cmd.sync(client)
+		if err != nil {
+			return err
+		}
+		if result.changed() {
+			fmt.Fprintf(cmd.io.Output(), "Synced at %s. %s\n", time.Now().Format("15:04:05"), result)
+		}
+
+		cmd.sleep(cmd.interval)
+	}
+}
+
+// syncResult counts what a sync has done.
+type syncResult struct {
+	pushed    int
+	pulled    int
+	deleted   int
+	conflicts int
+}
+
+// changed returns whether anything was synced or found to be in conflict.
+func (r syncResult) changed() bool {
+	return r.pushed+r.pulled+r.deleted+r.conflicts > 0
+}
+
+// String returns a summary of the result.
+func (r syncResult) String() string {
+	return fmt.Sprintf("Files: %d pushed, %d pulled, %d deleted, %d in conflict.", r.pushed, r.pulled, r.deleted, r.conflicts)
+}
+
+// syncState is the state of the files after the last sync.
+type syncState struct {
+	Remote string                   `json:"remote"`
+	Files  map[string]syncFileState `json:"files"`
+}
+
+// syncFileState is the state of a file after the last sync.
+type syncFileState struct {
+	// Version is the version of the secret the file was last synced with.
+	Version int `json:"version"`
+	// Hash is the hex encoded SHA-256 hash of the content of the file.
+	Hash string `json:"hash"`
+}
+
+// syncHash returns the hex encoded SHA-256 hash of data.
+func syncHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sync syncs the directories once.
+func (cmd *SyncCommand) sync(client secrethub.ClientInterface) (syncResult, error) {
+	var result syncResult
+
+	patterns, err := cmd.ignorePatterns()
+	if err != nil {
+		return result, err
+	}
+
+	local, err := readSyncFiles(cmd.localDir, patterns)
+	if err != nil {
+		return result, err
+	}
+	// The links of a repository are not a secret, so a local file cannot overwrite them.
+	if cmd.remotePath.IsRepoPath() {
+		delete(local, repoLinksSecretName)
+	}
+
+	remote, err := cmd.readRemoteVersions(client, patterns)
+	if err != nil {
+		return result, err
+	}
+
+	state, err := cmd.readState()
+	if err != nil {
+		return result, err
+	}
+
+	nameSet := map[string]bool{}
+	for name := range local {
+		nameSet[name] = true
+	}
+	for name := range remote {
+		nameSet[name] = true
+	}
+	for name := range state.Files {
+		nameSet[name] = true
+	}
+	names := make([]string, 0, len(nameSet))
+	for name := range nameSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pushDeletes := cmd.deletes == syncDeletesPush || cmd.deletes == syncDeletesBoth
+	pullDeletes := cmd.deletes == syncDeletesPull || cmd.deletes == syncDeletesBoth
+
+	// The progress is only reported for a single sync, as it would flood the output when watching.
+	counter := progress.NewDiscardCounter()
+	if !cmd.watch {
+		counter = newProgressCounter("Syncing files", len(names))
+	}
+	defer counter.Finish()
+
+	for _, name := range names {
+		data, isLocal := local[name]
+		version, isRemote := remote[name]
+		last, synced := state.Files[name]
+
+		if isLocal && api.ValidateSecretPath(cmd.secretPath(name)) != nil {
+			fmt.Fprintf(cmd.io.Output(), "Skipped %s: not a valid secret name\n", name)
+			counter.Done()
+			continue
+		}
+
+		localChanged := isLocal && (!synced || syncHash(data) != last.Hash)
+		remoteChanged := isRemote && (!synced || version != last.Version)
+
+		switch {
+		case !isLocal && !isRemote:
+			delete(state.Files, name)
+		case isLocal && isRemote && !localChanged && !remoteChanged:
+			counter.Done()
+			continue
+		case isLocal && !isRemote && synced && !localChanged && pullDeletes:
+			err = cmd.deleteLocal(name)
+			delete(state.Files, name)
+			result.deleted++
+		case !isLocal && isRemote && synced && !remoteChanged && pushDeletes:
+			err = cmd.deleteRemote(client, name)
+			delete(state.Files, name)
+			result.deleted++
+		case isLocal && (!isRemote || !remoteChanged):
+			err = cmd.push(client, state, name, data)
+			result.pushed++
+		case isRemote && (!isLocal || !localChanged):
+			err = cmd.pull(client, state, name)
+			result.pulled++
+		default:
+			err = cmd.resolveConflict(client, state, name, data, &result)
+		}
+		if err != nil {
+			return result, err
+		}
+		counter.Done()
+	}
+	counter.Finish()
+
+	return result, cmd.writeState(state)
+}
+
+// resolveConflict handles a file that changed both locally and in SecretHub since the last sync.
+func (cmd *SyncCommand) resolveConflict(client secrethub.ClientInterface, state *syncState, name string, data []byte, result *syncResult) error {
+	version, err := client.Secrets().Versions().GetWithData(cmd.secretPath(name))
+	if err != nil {
+		return err
+	}
+
+	// When both sides changed to the same value, there is nothing to resolve.
+	if bytes.Equal(version.Data, data) {
+		state.Files[name] = syncFileState{Version: version.Version, Hash: syncHash(data)}
+		return nil
+	}
+
+	switch cmd.onConflict {
+	case conflictLocal:
+		result.pushed++
+		return cmd.push(client, state, name, data)
+	case conflictRemote:
+		result.pulled++
+		return cmd.pull(client, state, name)
+	default:
+		fmt.Fprintf(cmd.io.Output(), "Conflict %s: changed both locally and in SecretHub\n", name)
+		result.conflicts++
+		return nil
+	}
+}
+
+// push writes the file to its secret.
+func (cmd *SyncCommand) push(client secrethub.ClientInterface, state *syncState, name string, data []byte) error {
+	importer := secretImporter{dirPath: cmd.remotePath}
+	for _, dir := range importer.dirs([]namedSecret{{Name: name}}) {
+		exists, err := client.Dirs().Exists(dir)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		_, err = client.Dirs().Create(dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	version, err := client.Secrets().Write(cmd.secretPath(name), data)
+	if err != nil {
+		return err
+	}
+
+	state.Files[name] = syncFileState{Version: version.Version, Hash: syncHash(data)}
+	fmt.Fprintf(cmd.io.Output(), "Pushed %s\n", name)
+	return nil
+}
+
+// pull writes the latest version of the secret to its file.
+func (cmd *SyncCommand) pull(client secrethub.ClientInterface, state *syncState, name string) error {
+	version, err := client.Secrets().Versions().GetWithData(cmd.secretPath(name))
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(cmd.localDir, filepath.FromSlash(name))
+	err = os.MkdirAll(filepath.Dir(path), syncDirMode)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	err = ioutil.WriteFile(path, version.Data, syncFileMode)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+
+	state.Files[name] = syncFileState{Version: version.Version, Hash: syncHash(version.Data)}
+	fmt.Fprintf(cmd.io.Output(), "Pulled %s\n", name)
+	return nil
+}
+
+// deleteLocal removes the file of a secret that was deleted in SecretHub.
+func (cmd *SyncCommand) deleteLocal(name string) error {
+	path := filepath.Join(cmd.localDir, filepath.FromSlash(name))
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Deleted %s\n", path)
+	return nil
+}
+
+// deleteRemote removes the secret of a file that was deleted locally.
+func (cmd *SyncCommand) deleteRemote(client secrethub.ClientInterface, name string) error {
+	path := cmd.secretPath(name)
+	err := client.Secrets().Delete(path)
+	if err != nil && !api.IsErrNotFound(err) {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Deleted %s\n", path)
+	return nil
+}
+
+// secretPath returns the path of the secret the file with the given name is synced with.
+func (cmd *SyncCommand) secretPath(name string) string {
+	return cmd.remotePath.Value() + "/" + name
+}
+
+// readRemoteVersions returns the latest version of every secret in the remote directory
+// that is not ignored, by the path of the secret relative to the directory.
+func (cmd *SyncCommand) readRemoteVersions(client secrethub.ClientInterface, patterns []string) (map[string]int, error) {
+	versions := map[string]int{}
+
+	tree, err := client.Dirs().GetTree(cmd.remotePath.Value(), -1, false)
+	if api.IsErrNotFound(err) {
+		return versions, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for secretID, secret := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secretID)
+		if err != nil {
+			return nil, err
+		}
+		if isRepoLinksPath(*secretPath) {
+			continue
+		}
+
+		name := strings.TrimPrefix(secretPath.Value(), cmd.remotePath.Value()+"/")
+		if syncIgnored(patterns, name) {
+			continue
+		}
+		versions[name] = secret.LatestVersion
+	}
+	return versions, nil
+}
+
+// ignorePatterns returns the patterns given with --ignore and in the ignore file of the local directory.
+func (cmd *SyncCommand) ignorePatterns() ([]string, error) {
+	patterns := append([]string{syncStateFileName, syncIgnoreFileName}, cmd.ignore...)
+
+	path := filepath.Join(cmd.localDir, syncIgnoreFileName)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return patterns, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, strings.TrimSuffix(line, "/"))
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+	return patterns, nil
+}
+
+// syncIgnored returns whether the file with the given slash separated relative path, or one of
+// the directories it is in, matches one of the patterns. A pattern matches when it matches
+// either the name or the relative path of the file or directory.
+func syncIgnored(patterns []string, name string) bool {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		path := strings.Join(parts[:i+1], "/")
+		for _, pattern := range patterns {
+			nameMatch, _ := filepath.Match(pattern, part)
+			pathMatch, _ := filepath.Match(pattern, path)
+			if nameMatch || pathMatch {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readSyncFiles returns the content of the files in the directory and its subdirectories that are
+// not ignored, by their slash separated path relative to the directory.
+func readSyncFiles(dir string, patterns []string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+
+		if syncIgnored(patterns, name) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return ErrCannotReadFile(path, err)
+		}
+		files[name] = data
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return files, nil
+	}
+	return files, err
+}
+
+// readState reads the state of the last sync. When the directory has not been synced
+// with the remote path before, an empty state is returned.
+func (cmd *SyncCommand) readState() (*syncState, error) {
+	state := &syncState{
+		Remote: cmd.remotePath.Value(),
+		Files:  map[string]syncFileState{},
+	}
+
+	path := filepath.Join(cmd.localDir, syncStateFileName)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	var stored syncState
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return nil, ErrInvalidSyncState(path, err)
+	}
+	if stored.Remote != state.Remote || stored.Files == nil {
+		return state, nil
+	}
+	return &stored, nil
+}
+
+// writeState stores the state of the sync in the local directory.
+func (cmd *SyncCommand) writeState(state *syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(cmd.localDir, syncStateFileName)
+	err = os.MkdirAll(cmd.localDir, syncDirMode)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	err = ioutil.WriteFile(path, data, syncFileMode)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+// syncTestRemote is a directory of secrets, by their path relative to namespace/repo/app.
+type syncTestRemote map[string]*api.SecretVersion
+
+// client returns a client for the secrets.
+func (remote syncTestRemote) client() secrethub.ClientInterface {
+	return fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return remote.tree(), nil
+			},
+			ExistsFunc: func(path string) (bool, error) {
+				return path == "namespace/repo/app", nil
+			},
+			CreateFunc: func(path string) (*api.Dir, error) {
+				return &api.Dir{}, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				name := strings.TrimPrefix(path, "namespace/repo/app/")
+				version := 1
+				if remote[name] != nil {
+					version = remote[name].Version + 1
+				}
+				remote[name] = &api.SecretVersion{Version: version, Data: data}
+				return remote[name], nil
+			},
+			DeleteFunc: func(path string) error {
+				delete(remote, strings.TrimPrefix(path, "namespace/repo/app/"))
+				return nil
+			},
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					version, ok := remote[strings.TrimPrefix(path, "namespace/repo/app/")]
+					if !ok {
+						return nil, api.ErrSecretNotFound
+					}
+					return version, nil
+				},
+			},
+		},
+	}
+}
+
+// tree returns the directory tree of the secrets.
+func (remote syncTestRemote) tree() *api.Tree {
+	rootDirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace/repo",
+		RootDir:    &api.Dir{DirID: rootDirID, Name: "app"},
+		Dirs:       map[uuid.UUID]*api.Dir{rootDirID: {DirID: rootDirID, Name: "app"}},
+		Secrets:    map[uuid.UUID]*api.Secret{},
+	}
+
+	dirIDs := map[string]uuid.UUID{"": rootDirID}
+	for name, version := range remote {
+		parts := strings.Split(name, "/")
+		parent := ""
+		for i, part := range parts[:len(parts)-1] {
+			path := strings.Join(parts[:i+1], "/")
+			if _, ok := dirIDs[path]; !ok {
+				dirID := uuid.New()
+				parentID := dirIDs[parent]
+				tree.Dirs[dirID] = &api.Dir{DirID: dirID, Name: part, ParentID: &parentID}
+				dirIDs[path] = dirID
+			}
+			parent = path
+		}
+
+		secretID := uuid.New()
+		tree.Secrets[secretID] = &api.Secret{
+			SecretID:      secretID,
+			DirID:         dirIDs[parent],
+			Name:          parts[len(parts)-1],
+			LatestVersion: version.Version,
+		}
+	}
+	return tree
+}
+
+func TestSyncCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		local          map[string]string
+		remote         syncTestRemote
+		state          map[string]syncFileState
+		onConflict     string
+		deletes        string
+		ignore         []string
+		expectedLocal  map[string]string
+		expectedRemote map[string]string
+		out            string
+		err            error
+	}{
+		"first sync": {
+			local: map[string]string{
+				"db/password": "local",
+				"same":        "value",
+			},
+			remote: syncTestRemote{
+				"token": {Version: 1, Data: []byte("remote")},
+				"same":  {Version: 3, Data: []byte("value")},
+			},
+			expectedLocal: map[string]string{
+				"db/password": "local",
+				"same":        "value",
+				"token":       "remote",
+			},
+			expectedRemote: map[string]string{
+				"db/password": "local",
+				"same":        "value",
+				"token":       "remote",
+			},
+			out: "Pushed db/password\n" +
+				"Pulled token\n" +
+				"Sync complete! Files: 1 pushed, 1 pulled, 0 deleted, 0 in conflict.\n",
+		},
+		"changes since last sync": {
+			local: map[string]string{
+				"changed locally":  "new",
+				"changed remotely": "old",
+				"unchanged":        "old",
+			},
+			remote: syncTestRemote{
+				"changed locally":  {Version: 1, Data: []byte("old")},
+				"changed remotely": {Version: 2, Data: []byte("new")},
+				"unchanged":        {Version: 1, Data: []byte("old")},
+			},
+			state: map[string]syncFileState{
+				"changed locally":  {Version: 1, Hash: syncHash([]byte("old"))},
+				"changed remotely": {Version: 1, Hash: syncHash([]byte("old"))},
+				"unchanged":        {Version: 1, Hash: syncHash([]byte("old"))},
+			},
+			expectedLocal: map[string]string{
+				"changed locally":  "new",
+				"changed remotely": "new",
+				"unchanged":        "old",
+			},
+			expectedRemote: map[string]string{
+				"changed locally":  "new",
+				"changed remotely": "new",
+				"unchanged":        "old",
+			},
+			out: "Pushed changed locally\n" +
+				"Pulled changed remotely\n" +
+				"Sync complete! Files: 1 pushed, 1 pulled, 0 deleted, 0 in conflict.\n",
+		},
+		"conflict": {
+			local: map[string]string{
+				"token": "local",
+			},
+			remote: syncTestRemote{
+				"token": {Version: 1, Data: []byte("remote")},
+			},
+			expectedLocal: map[string]string{
+				"token": "local",
+			},
+			expectedRemote: map[string]string{
+				"token": "remote",
+			},
+			out: "Conflict token: changed both locally and in SecretHub\n" +
+				"Sync complete! Files: 0 pushed, 0 pulled, 0 deleted, 1 in conflict.\n",
+			err: ErrSyncConflicts(1),
+		},
+		"conflict keep local": {
+			local: map[string]string{
+				"token": "local",
+			},
+			remote: syncTestRemote{
+				"token": {Version: 1, Data: []byte("remote")},
+			},
+			onConflict: conflictLocal,
+			expectedLocal: map[string]string{
+				"token": "local",
+			},
+			expectedRemote: map[string]string{
+				"token": "local",
+			},
+			out: "Pushed token\n" +
+				"Sync complete! Files: 1 pushed, 0 pulled, 0 deleted, 0 in conflict.\n",
+		},
+		"conflict keep remote": {
+			local: map[string]string{
+				"token": "local",
+			},
+			remote: syncTestRemote{
+				"token": {Version: 1, Data: []byte("remote")},
+			},
+			onConflict: conflictRemote,
+			expectedLocal: map[string]string{
+				"token": "remote",
+			},
+			expectedRemote: map[string]string{
+				"token": "remote",
+			},
+			out: "Pulled token\n" +
+				"Sync complete! Files: 0 pushed, 1 pulled, 0 deleted, 0 in conflict.\n",
+		},
+		"deletions restored": {
+			local: map[string]string{
+				"deleted remotely": "value",
+			},
+			remote: syncTestRemote{
+				"deleted locally": {Version: 1, Data: []byte("value")},
+			},
+			state: map[string]syncFileState{
+				"deleted locally":  {Version: 1, Hash: syncHash([]byte("value"))},
+				"deleted remotely": {Version: 1, Hash: syncHash([]byte("value"))},
+			},
+			expectedLocal: map[string]string{
+				"deleted locally":  "value",
+				"deleted remotely": "value",
+			},
+			expectedRemote: map[string]string{
+				"deleted locally":  "value",
+				"deleted remotely": "value",
+			},
+			out: "Pulled deleted locally\n" +
+				"Pushed deleted remotely\n" +
+				"Sync complete! Files: 1 pushed, 1 pulled, 0 deleted, 0 in conflict.\n",
+		},
+		"deletions propagated": {
+			local: map[string]string{
+				"deleted remotely": "value",
+			},
+			remote: syncTestRemote{
+				"deleted locally": {Version: 1, Data: []byte("value")},
+			},
+			state: map[string]syncFileState{
+				"deleted locally":  {Version: 1, Hash: syncHash([]byte("value"))},
+				"deleted remotely": {Version: 1, Hash: syncHash([]byte("value"))},
+			},
+			deletes:        syncDeletesBoth,
+			expectedLocal:  map[string]string{},
+			expectedRemote: map[string]string{},
+			out: "Deleted namespace/repo/app/deleted locally\n" +
+				"Deleted %s\n" +
+				"Sync complete! Files: 0 pushed, 0 pulled, 2 deleted, 0 in conflict.\n",
+		},
+		"changes win over deletions": {
+			local: map[string]string{
+				"deleted remotely": "new",
+			},
+			remote: syncTestRemote{
+				"deleted locally": {Version: 2, Data: []byte("new")},
+			},
+			state: map[string]syncFileState{
+				"deleted locally":  {Version: 1, Hash: syncHash([]byte("old"))},
+				"deleted remotely": {Version: 1, Hash: syncHash([]byte("old"))},
+			},
+			deletes: syncDeletesBoth,
+			expectedLocal: map[string]string{
+				"deleted locally":  "new",
+				"deleted remotely": "new",
+			},
+			expectedRemote: map[string]string{
+				"deleted locally":  "new",
+				"deleted remotely": "new",
+			},
+			out: "Pulled deleted locally\n" +
+				"Pushed deleted remotely\n" +
+				"Sync complete! Files: 1 pushed, 1 pulled, 0 deleted, 0 in conflict.\n",
+		},
+		"ignored": {
+			local: map[string]string{
+				"token":        "local",
+				"token.bak":    "local",
+				"cache/secret": "local",
+			},
+			remote: syncTestRemote{
+				"cache/other": {Version: 1, Data: []byte("remote")},
+			},
+			ignore: []string{"*.bak", "cache"},
+			expectedLocal: map[string]string{
+				"token":        "local",
+				"token.bak":    "local",
+				"cache/secret": "local",
+			},
+			expectedRemote: map[string]string{
+				"token":       "local",
+				"cache/other": "remote",
+			},
+			out: "Pushed token\n" +
+				"Sync complete! Files: 1 pushed, 0 pulled, 0 deleted, 0 in conflict.\n",
+		},
+		"invalid conflict strategy": {
+			onConflict: "merge",
+			err:        ErrInvalidSyncConflictStrategy("merge"),
+		},
+		"invalid deletes": {
+			deletes: "all",
+			err:     ErrInvalidSyncDeletes("all"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			for file, content := range tc.local {
+				path := filepath.Join(dir, filepath.FromSlash(file))
+				assert.OK(t, os.MkdirAll(filepath.Dir(path), 0700))
+				assert.OK(t, ioutil.WriteFile(path, []byte(content), 0600))
+			}
+			if tc.state != nil {
+				data, err := json.Marshal(syncState{Remote: "namespace/repo/app", Files: tc.state})
+				assert.OK(t, err)
+				assert.OK(t, ioutil.WriteFile(filepath.Join(dir, syncStateFileName), data, 0600))
+			}
+			if tc.remote == nil {
+				tc.remote = syncTestRemote{}
+			}
+			if tc.onConflict == "" {
+				tc.onConflict = conflictSkip
+			}
+			if tc.deletes == "" {
+				tc.deletes = syncDeletesNone
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := SyncCommand{
+				io:         io,
+				localDir:   dir,
+				remotePath: api.DirPath("namespace/repo/app"),
+				onConflict: tc.onConflict,
+				deletes:    tc.deletes,
+				ignore:     tc.ignore,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return tc.remote.client(), nil
+				},
+			}
+
+			err := cmd.Run()
+			assert.Equal(t, err, tc.err)
+			if tc.out != "" {
+				assert.Equal(t, io.Out.String(), strings.Replace(tc.out, "%s", filepath.Join(dir, "deleted remotely"), 1))
+			}
+
+			if tc.expectedLocal != nil {
+				local, err := readSyncFiles(dir, []string{syncStateFileName})
+				assert.OK(t, err)
+				actual := map[string]string{}
+				for file, data := range local {
+					actual[file] = string(data)
+				}
+				assert.Equal(t, actual, tc.expectedLocal)
+			}
+
+			if tc.expectedRemote != nil {
+				actual := map[string]string{}
+				for name, version := range tc.remote {
+					actual[name] = string(version.Data)
+				}
+				assert.Equal(t, actual, tc.expectedRemote)
+			}
+		})
+	}
+}
+
+func TestSyncCommand_Run_SecondSyncIsNoop(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	assert.OK(t, ioutil.WriteFile(filepath.Join(dir, "local"), []byte("local"), 0600))
+	remote := syncTestRemote{
+		"remote": {Version: 1, Data: []byte("remote")},
+	}
+
+	for _, expected := range []string{
+		"Pushed local\nPulled remote\nSync complete! Files: 1 pushed, 1 pulled, 0 deleted, 0 in conflict.\n",
+		"Sync complete! Files: 0 pushed, 0 pulled, 0 deleted, 0 in conflict.\n",
+	} {
+		io := fakeui.NewIO(t)
+		cmd := SyncCommand{
+			io:         io,
+			localDir:   dir,
+			remotePath: api.DirPath("namespace/repo/app"),
+			onConflict: conflictSkip,
+			deletes:    syncDeletesNone,
+			newClient: func() (secrethub.ClientInterface, error) {
+				return remote.client(), nil
+			},
+		}
+
+		err := cmd.Run()
+		assert.OK(t, err)
+		assert.Equal(t, io.Out.String(), expected)
+	}
+}
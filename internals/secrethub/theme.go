@@ -0,0 +1,232 @@
+package secrethub
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+
+	"github.com/fatih/color"
+)
+
+// Errors
+var (
+	ErrUnknownColorTheme = errMain.Code("unknown_color_theme").ErrorPref("unknown color theme %s: the themes are %s")
+	ErrInvalidColorTheme = errMain.Code("invalid_color_theme").ErrorPref("invalid color theme configuration in %s: %s")
+)
+
+// themeFilename is the name of the file in the configuration directory that configures the colors of the output.
+const themeFilename = "theme.json"
+
+// The names of the built-in color themes.
+const (
+	themeDefault      = "default"
+	themeHighContrast = "high-contrast"
+)
+
+// The roles that are colored in the output.
+const (
+	colorRoleFlagged = "flagged"
+	colorRoleAdded   = "added"
+	colorRoleChanged = "changed"
+	colorRoleRemoved = "removed"
+	colorRoleWarning = "warning"
+)
+
+// colorThemes are the built-in color themes. The high-contrast theme does not rely on
+// telling red and green apart and uses bright colors and styles for every role.
+var colorThemes = map[string]colorTheme{
+	themeDefault: {
+		colorRoleFlagged: color.New(color.FgRed, color.Bold),
+		colorRoleAdded:   color.New(color.FgGreen),
+		colorRoleChanged: color.New(color.FgYellow),
+		colorRoleRemoved: color.New(color.FgRed),
+		colorRoleWarning: color.New(color.FgYellow),
+	},
+	themeHighContrast: {
+		colorRoleFlagged: color.New(color.FgBlack, color.BgHiYellow, color.Bold),
+		colorRoleAdded:   color.New(color.FgHiBlue, color.Bold),
+		colorRoleChanged: color.New(color.FgHiWhite, color.Bold, color.Underline),
+		colorRoleRemoved: color.New(color.FgHiMagenta, color.Bold),
+		colorRoleWarning: color.New(color.FgHiYellow, color.Bold),
+	},
+}
+
+// colorAttributes are the names of the attributes that can be used in a color theme configuration.
+var colorAttributes = map[string]color.Attribute{
+	"bold":       color.Bold,
+	"faint":      color.Faint,
+	"italic":     color.Italic,
+	"underline":  color.Underline,
+	"reverse":    color.ReverseVideo,
+	"black":      color.FgBlack,
+	"red":        color.FgRed,
+	"green":      color.FgGreen,
+	"yellow":     color.FgYellow,
+	"blue":       color.FgBlue,
+	"magenta":    color.FgMagenta,
+	"cyan":       color.FgCyan,
+	"white":      color.FgWhite,
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+	"bg-black":   color.BgBlack,
+	"bg-red":     color.BgRed,
+	"bg-green":   color.BgGreen,
+	"bg-yellow":  color.BgYellow,
+	"bg-blue":    color.BgBlue,
+	"bg-magenta": color.BgMagenta,
+	"bg-cyan":    color.BgCyan,
+	"bg-white":   color.BgWhite,
+}
+
+// colorTheme maps the roles in the output to the color they are printed in.
+type colorTheme map[string]*color.Color
+
+// theme is the color theme the output is colored with.
+var theme = colorThemes[themeDefault]
+
+// colorize returns msg in the color of the given role in the configured theme.
+// Colors are left out when colored output is disabled.
+func colorize(role string, msg interface{}) interface{} {
+	c, ok := theme[role]
+	if !ok {
+		return msg
+	}
+	return c.Sprint(msg)
+}
+
+// themeConfig is the configuration of the colors in the theme file.
+type themeConfig struct {
+	// Theme is the name of the built-in theme to start from.
+	Theme string `json:"theme,omitempty"`
+	// Colors overrides the colors of roles with a space separated list of attributes, e.g. "hi-blue bold".
+	Colors map[string]string `json:"colors,omitempty"`
+}
+
+// theme returns the color theme that is configured.
+func (c themeConfig) theme() (colorTheme, error) {
+	name := c.Theme
+	if name == "" {
+		name = themeDefault
+	}
+	base, ok := colorThemes[name]
+	if !ok {
+		return nil, ErrUnknownColorTheme(name, strings.Join(colorThemeNames(), ", "))
+	}
+
+	res := colorTheme{}
+	for role, roleColor := range base {
+		res[role] = roleColor
+	}
+
+	for role, value := range c.Colors {
+		if _, ok := base[role]; !ok {
+			return nil, fmt.Errorf("unknown role %s", role)
+		}
+
+		var attributes []color.Attribute
+		for _, name := range strings.Fields(value) {
+			attribute, ok := colorAttributes[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("unknown color %s for role %s", name, role)
+			}
+			attributes = append(attributes, attribute)
+		}
+		res[role] = color.New(attributes...)
+	}
+	return res, nil
+}
+
+// colorThemeNames returns the names of the built-in themes in alphabetical order.
+func colorThemeNames() []string {
+	names := make([]string, 0, len(colorThemes))
+	for name := range colorThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// themeFile returns the file in the configuration directory that configures the color theme.
+func themeFile(dir configdir.Dir) jsonFile {
+	return jsonFile{path: filepath.Join(dir.Path(), themeFilename)}
+}
+
+// loadColorTheme sets the color theme to the theme configured in the configuration directory.
+// When no theme is configured, the default theme is used.
+func loadColorTheme(dir configdir.Dir) error {
+	if dir.Path() == "" {
+		return nil
+	}
+	file := themeFile(dir)
+
+	var config themeConfig
+	err := file.read(&config)
+	if err != nil {
+		return ErrInvalidColorTheme(file.path, err)
+	}
+
+	configured, err := config.theme()
+	if err != nil {
+		return ErrInvalidColorTheme(file.path, err)
+	}
+	theme = configured
+	return nil
+}
+
+// ConfigThemeCommand sets the color theme of the output.
+type ConfigThemeCommand struct {
+	io              ui.IO
+	name            string
+	credentialStore CredentialConfig
+}
+
+// NewConfigThemeCommand creates a new ConfigThemeCommand.
+func NewConfigThemeCommand(io ui.IO, credentialStore CredentialConfig) *ConfigThemeCommand {
+	return &ConfigThemeCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ConfigThemeCommand) Register(r command.Registerer) {
+	clause := r.Command("theme", "Set the color theme of listings, diffs and warnings. "+
+		"The high-contrast theme does not rely on telling red and green apart. "+
+		"The colors of the roles flagged, added, changed, removed and warning can be overridden in the colors section of the "+themeFilename+" file "+
+		"in the configuration directory, with a space separated list of colors and styles, e.g. \"hi-blue bold\". "+
+		"Colors are disabled with --no-color or the NO_COLOR environment variable.")
+	clause.Arg("theme", "The name of the theme: "+strings.Join(colorThemeNames(), " or ")).Required().HintOptions(colorThemeNames()...).StringVar(&cmd.name)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run stores the theme in the configuration directory.
+func (cmd *ConfigThemeCommand) Run() error {
+	_, ok := colorThemes[cmd.name]
+	if !ok {
+		return ErrUnknownColorTheme(cmd.name, strings.Join(colorThemeNames(), ", "))
+	}
+
+	var config themeConfig
+	err := themeFile(cmd.credentialStore.ConfigDir()).update(&config, func() {
+		config.Theme = cmd.name
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(statusWriter(cmd.io.Output()), "The color theme is set to %s.\n", cmd.name)
+	return nil
+}
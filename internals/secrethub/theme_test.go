@@ -0,0 +1,114 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+
+	"github.com/fatih/color"
+)
+
+func TestThemeConfig_theme(t *testing.T) {
+	cases := map[string]struct {
+		config   themeConfig
+		expected colorTheme
+		err      error
+	}{
+		"default": {
+			config:   themeConfig{},
+			expected: colorThemes[themeDefault],
+		},
+		"high contrast": {
+			config:   themeConfig{Theme: themeHighContrast},
+			expected: colorThemes[themeHighContrast],
+		},
+		"override": {
+			config: themeConfig{
+				Colors: map[string]string{
+					colorRoleAdded: "hi-blue Bold",
+				},
+			},
+			expected: colorTheme{
+				colorRoleFlagged: colorThemes[themeDefault][colorRoleFlagged],
+				colorRoleAdded:   color.New(color.FgHiBlue, color.Bold),
+				colorRoleChanged: colorThemes[themeDefault][colorRoleChanged],
+				colorRoleRemoved: colorThemes[themeDefault][colorRoleRemoved],
+				colorRoleWarning: colorThemes[themeDefault][colorRoleWarning],
+			},
+		},
+		"unknown theme": {
+			config: themeConfig{Theme: "pink"},
+			err:    ErrUnknownColorTheme("pink", "default, high-contrast"),
+		},
+		"unknown role": {
+			config: themeConfig{Colors: map[string]string{"title": "bold"}},
+			err:    fmt.Errorf("unknown role title"),
+		},
+		"unknown color": {
+			config: themeConfig{Colors: map[string]string{colorRoleWarning: "orange"}},
+			err:    fmt.Errorf("unknown color orange for role warning"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tc.config.theme()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestConfigThemeCommand_Run(t *testing.T) {
+	defer func() {
+		theme = colorThemes[themeDefault]
+	}()
+
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	io := fakeui.NewIO(t)
+	cmd := ConfigThemeCommand{
+		io:              io,
+		name:            themeHighContrast,
+		credentialStore: &credentialConfig{configDir: ConfigDir{Dir: configdir.New(dir)}},
+	}
+
+	err := cmd.Run()
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), "The color theme is set to high-contrast.\n")
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, themeFilename))
+	assert.OK(t, err)
+	assert.Equal(t, string(data), "{\n    \"theme\": \"high-contrast\"\n}")
+
+	err = loadColorTheme(configdir.New(dir))
+	assert.OK(t, err)
+	assert.Equal(t, theme, colorThemes[themeHighContrast])
+}
+
+func TestLoadColorTheme_Invalid(t *testing.T) {
+	defer func() {
+		theme = colorThemes[themeDefault]
+	}()
+
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, themeFilename)
+	err := ioutil.WriteFile(path, []byte(`{"theme": "pink"}`), configFileMode)
+	assert.OK(t, err)
+
+	err = loadColorTheme(configdir.New(dir))
+	assert.Equal(t, err, ErrInvalidColorTheme(path, ErrUnknownColorTheme("pink", "default, high-contrast")))
+	assert.Equal(t, theme, colorThemes[themeDefault])
+}
@@ -0,0 +1,191 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The limits on waiting for the rate limit of the API.
+const (
+	// maxThrottleAttempts is the maximum number of times a call that is rate limited is sent.
+	maxThrottleAttempts = 10
+	// maxThrottleWait is the maximum time a single call waits for the rate limit. A call that is
+	// rate limited for longer than that is not sent again, so the rate limit error is returned.
+	maxThrottleWait = 5 * time.Minute
+	// defaultThrottleWait is the time to wait when the API rate limits a call without saying for how long.
+	defaultThrottleWait = time.Second
+)
+
+// apiThrottle records how long the API calls of the command were throttled to stay under the rate limit.
+var apiThrottle throttleStats
+
+// throttleStats is the throttling that is applied to the API calls.
+type throttleStats struct {
+	mutex  sync.Mutex
+	calls  int
+	waited time.Duration
+}
+
+// record records that a call waited for the given time.
+func (s *throttleStats) record(wait time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.calls++
+	s.waited += wait
+}
+
+// summary returns a description of the throttling that is applied, or an empty string when no call was throttled.
+func (s *throttleStats) summary() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.calls == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Throttled %s for %s in total to stay under the rate limit of the API.",
+		pluralize("API call", "API calls", s.calls), s.waited.Round(time.Second))
+}
+
+// throttleTransport is a http.RoundTripper that keeps the API calls under the rate limit of the API.
+// When the API reports that no calls remain, the next calls wait until the limit resets. Calls that
+// are rejected because of the rate limit are sent again after the time the API asks to wait, which is
+// safe for all calls, as the API has not processed them. This keeps bulk operations like imports going
+// instead of failing halfway through.
+type throttleTransport struct {
+	next  http.RoundTripper
+	stats *throttleStats
+	sleep func(time.Duration)
+	now   func() time.Time
+
+	mutex     sync.Mutex
+	notBefore time.Time
+}
+
+// newThrottleTransport returns a transport that passes the API calls on to next and records the throttling in stats.
+func newThrottleTransport(next http.RoundTripper, stats *throttleStats) *throttleTransport {
+	return &throttleTransport{
+		next:  next,
+		stats: stats,
+		sleep: time.Sleep,
+		now:   time.Now,
+	}
+}
+
+// RoundTrip waits until the rate limit allows the call, performs it and sends it again while it is rate limited.
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	waited := time.Duration(0)
+	defer func() {
+		if waited > 0 {
+			t.stats.record(waited)
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		wait := t.wait()
+		if waited+wait > maxThrottleWait {
+			wait = maxThrottleWait - waited
+		}
+		if wait > 0 {
+			t.sleep(wait)
+			waited += wait
+		}
+
+		attemptReq := req
+		if attempt > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		t.update(resp)
+
+		canResend := req.Body == nil || req.GetBody != nil
+		if resp.StatusCode != http.StatusTooManyRequests || !canResend || attempt == maxThrottleAttempts ||
+			waited+t.wait() > maxThrottleWait || req.Context().Err() != nil {
+			return resp, nil
+		}
+
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
+
+// wait returns the time to wait before the next call is allowed.
+func (t *throttleTransport) wait() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.notBefore.Sub(t.now())
+}
+
+// update postpones the next calls until the rate limit resets when the response
+// says that no calls remain or that the call was rate limited.
+func (t *throttleTransport) update(resp *http.Response) {
+	now := t.now()
+
+	var until time.Time
+	if resp.StatusCode == http.StatusTooManyRequests {
+		until = now.Add(defaultThrottleWait)
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			until = now.Add(retryAfter)
+		} else if reset, ok := parseRateLimitReset(resp.Header, now); ok {
+			until = reset
+		}
+	} else if remaining, ok := parseRateLimitRemaining(resp.Header); ok && remaining == 0 {
+		if reset, ok := parseRateLimitReset(resp.Header, now); ok {
+			until = reset
+		}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if until.After(t.notBefore) {
+		t.notBefore = until
+	}
+}
+
+// parseRateLimitRemaining returns the number of calls that remain before the rate limit is reached,
+// from the RateLimit-Remaining or X-RateLimit-Remaining header.
+func parseRateLimitRemaining(header http.Header) (int, bool) {
+	value := rateLimitHeader(header, "Remaining")
+	remaining, err := strconv.Atoi(value)
+	if err != nil || remaining < 0 {
+		return 0, false
+	}
+	return remaining, true
+}
+
+// parseRateLimitReset returns the time at which the rate limit resets, from the RateLimit-Reset or
+// X-RateLimit-Reset header. The value is either the number of seconds until the reset or a Unix timestamp.
+func parseRateLimitReset(header http.Header, now time.Time) (time.Time, bool) {
+	value := rateLimitHeader(header, "Reset")
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset < 0 {
+		return time.Time{}, false
+	}
+	// Values that are later than 2001 cannot be a number of seconds to wait, so they are a timestamp.
+	if reset > 1e9 {
+		return time.Unix(reset, 0), true
+	}
+	return now.Add(time.Duration(reset) * time.Second), true
+}
+
+// rateLimitHeader returns the value of the rate limit header with the given field,
+// preferring the standard RateLimit-<field> header over X-RateLimit-<field>.
+func rateLimitHeader(header http.Header, field string) string {
+	value := header.Get("RateLimit-" + field)
+	if value == "" {
+		value = header.Get("X-RateLimit-" + field)
+	}
+	return value
+}
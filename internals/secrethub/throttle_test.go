@@ -0,0 +1,182 @@
+package secrethub
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestThrottleTransport_RoundTrip(t *testing.T) {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	response := func(status int, header http.Header) *http.Response {
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	}
+
+	cases := map[string]struct {
+		method    string
+		responses []*http.Response
+		calls     int
+		status    int
+		attempts  int
+		delays    []time.Duration
+		throttled int
+	}{
+		"under the limit": {
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(200, http.Header{"Ratelimit-Remaining": {"10"}, "Ratelimit-Reset": {"30"}}),
+				response(200, nil),
+			},
+			calls:    2,
+			status:   200,
+			attempts: 2,
+		},
+		"limit reached": {
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(200, http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"30"}}),
+				response(200, nil),
+			},
+			calls:     2,
+			status:    200,
+			attempts:  2,
+			delays:    []time.Duration{30 * time.Second},
+			throttled: 1,
+		},
+		"limit reached with timestamp": {
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(200, http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"1577880010"}}),
+				response(200, nil),
+			},
+			calls:     2,
+			status:    200,
+			attempts:  2,
+			delays:    []time.Duration{10 * time.Second},
+			throttled: 1,
+		},
+		"rate limited with retry after": {
+			method: http.MethodPost,
+			responses: []*http.Response{
+				response(429, http.Header{"Retry-After": {"5"}}),
+				response(201, nil),
+			},
+			calls:     1,
+			status:    201,
+			attempts:  2,
+			delays:    []time.Duration{5 * time.Second},
+			throttled: 1,
+		},
+		"rate limited with reset": {
+			method: http.MethodDelete,
+			responses: []*http.Response{
+				response(429, http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"20"}}),
+				response(200, nil),
+			},
+			calls:     1,
+			status:    200,
+			attempts:  2,
+			delays:    []time.Duration{20 * time.Second},
+			throttled: 1,
+		},
+		"rate limited without wait": {
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(429, nil),
+				response(429, nil),
+				response(200, nil),
+			},
+			calls:     1,
+			status:    200,
+			attempts:  3,
+			delays:    []time.Duration{defaultThrottleWait, defaultThrottleWait},
+			throttled: 1,
+		},
+		"max wait exceeded": {
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(429, http.Header{"Retry-After": {"240"}}),
+				response(429, http.Header{"Retry-After": {"240"}}),
+			},
+			calls:     1,
+			status:    429,
+			attempts:  2,
+			delays:    []time.Duration{240 * time.Second},
+			throttled: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := start
+			attempts := 0
+			var delays []time.Duration
+			stats := &throttleStats{}
+
+			transport := newThrottleTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body, err := ioutil.ReadAll(req.Body)
+				assert.OK(t, err)
+				assert.Equal(t, string(body), "body")
+
+				attempts++
+				return tc.responses[attempts-1], nil
+			}), stats)
+			transport.now = func() time.Time {
+				return now
+			}
+			transport.sleep = func(d time.Duration) {
+				delays = append(delays, d)
+				now = now.Add(d)
+			}
+
+			var resp *http.Response
+			for i := 0; i < tc.calls; i++ {
+				req, err := http.NewRequest(tc.method, "https://api.secrethub.io/", bytes.NewBufferString("body"))
+				assert.OK(t, err)
+
+				resp, err = transport.RoundTrip(req)
+				assert.OK(t, err)
+			}
+
+			assert.Equal(t, resp.StatusCode, tc.status)
+			assert.Equal(t, attempts, tc.attempts)
+			assert.Equal(t, delays, tc.delays)
+			assert.Equal(t, stats.calls, tc.throttled)
+		})
+	}
+}
+
+func TestThrottleStats_summary(t *testing.T) {
+	cases := map[string]struct {
+		stats    *throttleStats
+		expected string
+	}{
+		"not throttled": {
+			stats:    &throttleStats{},
+			expected: "",
+		},
+		"throttled": {
+			stats:    &throttleStats{calls: 3, waited: 90 * time.Second},
+			expected: "Throttled 3 API calls for 1m30s in total to stay under the rate limit of the API.",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.stats.summary(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package secrethub
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+// Errors
+var (
+	ErrInvalidTimeoutConfig = errMain.Code("invalid_timeout_config").ErrorPref("invalid timeout configured in %s: %s")
+)
+
+// defaultTimeout is the time an API call may take when no timeout is configured.
+const defaultTimeout = 30 * time.Second
+
+// loadTimeout returns the timeout of API calls configured in the configuration directory,
+// or the default timeout when none is configured.
+func loadTimeout(dir configdir.Dir) (time.Duration, error) {
+	config, err := loadConfig(dir)
+	if err != nil {
+		return defaultTimeout, err
+	}
+	file := configFile(dir)
+	if config.Timeout == "" {
+		return defaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return defaultTimeout, ErrInvalidTimeoutConfig(file.path, err)
+	}
+	if timeout <= 0 {
+		return defaultTimeout, ErrInvalidTimeoutConfig(file.path, "the timeout must be positive")
+	}
+	return timeout, nil
+}
+
+// requestTimeout returns the timeout set with the --timeout flag or in the configuration file.
+// An invalid configuration does not stop the command: a warning is printed and the default timeout is used.
+func (f *clientFactory) requestTimeout() time.Duration {
+	if f.timeout > 0 {
+		return f.timeout
+	}
+	timeout, err := loadTimeout(f.store.ConfigDir())
+	if err != nil {
+		warnConfig(err)
+	}
+	return timeout
+}
+
+// timeoutTransport is a http.RoundTripper that cancels API calls that do not complete within
+// the timeout, from connecting to the API until the response has been read.
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+// newTimeoutTransport returns a transport that passes the API calls on to next and cancels them after the timeout.
+func newTimeoutTransport(next http.RoundTripper, timeout time.Duration) *timeoutTransport {
+	return &timeoutTransport{
+		next:    next,
+		timeout: timeout,
+	}
+}
+
+// RoundTrip performs the request with a deadline. The deadline also applies to reading
+// the response body, so it is only released when the body is closed.
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose is a response body that cancels the context of its request when it is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the context of the request.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
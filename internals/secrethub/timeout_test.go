@@ -0,0 +1,95 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+func TestTimeoutTransport_RoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		delay   time.Duration
+		timeout bool
+	}{
+		"in time": {
+			delay: 0,
+		},
+		"timed out": {
+			delay:   time.Minute,
+			timeout: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			transport := newTimeoutTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				select {
+				case <-time.After(tc.delay):
+					return &http.Response{
+						StatusCode: 200,
+						Body:       ioutil.NopCloser(strings.NewReader("")),
+					}, nil
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+			}), 10*time.Millisecond)
+
+			req, err := http.NewRequest(http.MethodGet, "https://api.secrethub.io/", nil)
+			assert.OK(t, err)
+
+			resp, err := transport.RoundTrip(req)
+
+			if tc.timeout {
+				timeoutErr, ok := err.(interface{ Timeout() bool })
+				assert.Equal(t, ok && timeoutErr.Timeout(), true)
+			} else {
+				assert.OK(t, err)
+				assert.OK(t, resp.Body.Close())
+			}
+		})
+	}
+}
+
+func TestLoadTimeout(t *testing.T) {
+	cases := map[string]struct {
+		config   cliConfig
+		expected time.Duration
+		err      bool
+	}{
+		"default": {
+			expected: defaultTimeout,
+		},
+		"configured": {
+			config:   cliConfig{Timeout: "10s"},
+			expected: 10 * time.Second,
+		},
+		"invalid": {
+			config:   cliConfig{Timeout: "soon"},
+			expected: defaultTimeout,
+			err:      true,
+		},
+		"negative": {
+			config:   cliConfig{Timeout: "-1s"},
+			expected: defaultTimeout,
+			err:      true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			assert.OK(t, configFile(configdir.New(dir)).write(tc.config))
+
+			actual, err := loadTimeout(configdir.New(dir))
+
+			assert.Equal(t, err != nil, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package secrethub
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"github.com/docker/go-units"
+)
+
+// Errors
+var (
+	ErrInvalidTOTPKey = errMain.Code("invalid_totp_key").ErrorPref("the secret is neither an otpauth://totp/ URI nor a base32 encoded seed: %s")
+)
+
+// The defaults of the parameters of a TOTP key, as used by authenticator apps.
+const (
+	defaultTOTPDigits = 6
+	defaultTOTPPeriod = 30 * time.Second
+)
+
+// totpAlgorithms are the hash algorithms that can be used in an otpauth URI.
+var totpAlgorithms = map[string]func() hash.Hash{
+	"SHA1":   sha1.New,
+	"SHA256": sha256.New,
+	"SHA512": sha512.New,
+}
+
+// totpKey is a key to generate time-based one-time passwords with, as specified in RFC 6238.
+type totpKey struct {
+	seed      []byte
+	algorithm func() hash.Hash
+	digits    int
+	period    time.Duration
+}
+
+// parseTOTPKey parses an otpauth://totp/ URI, as shown in the QR codes of authenticator apps,
+// or a base32 encoded seed with the default parameters.
+func parseTOTPKey(value string) (*totpKey, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "otpauth://") {
+		seed, err := decodeTOTPSeed(value)
+		if err != nil {
+			return nil, err
+		}
+		return &totpKey{
+			seed:      seed,
+			algorithm: sha1.New,
+			digits:    defaultTOTPDigits,
+			period:    defaultTOTPPeriod,
+		}, nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, ErrInvalidTOTPKey("invalid URI")
+	}
+	if u.Host != "totp" {
+		return nil, ErrInvalidTOTPKey(fmt.Sprintf("one-time passwords of type %s are not supported", u.Host))
+	}
+
+	query := u.Query()
+	seed, err := decodeTOTPSeed(query.Get("secret"))
+	if err != nil {
+		return nil, err
+	}
+	key := &totpKey{
+		seed:      seed,
+		algorithm: sha1.New,
+		digits:    defaultTOTPDigits,
+		period:    defaultTOTPPeriod,
+	}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.algorithm = totpAlgorithms[strings.ToUpper(algorithm)]
+		if key.algorithm == nil {
+			return nil, ErrInvalidTOTPKey(fmt.Sprintf("unsupported algorithm %s", algorithm))
+		}
+	}
+	if digits := query.Get("digits"); digits != "" {
+		key.digits, err = strconv.Atoi(digits)
+		if err != nil || key.digits < 6 || key.digits > 10 {
+			return nil, ErrInvalidTOTPKey(fmt.Sprintf("invalid number of digits %s", digits))
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		seconds, err := strconv.Atoi(period)
+		if err != nil || seconds <= 0 {
+			return nil, ErrInvalidTOTPKey(fmt.Sprintf("invalid period %s", period))
+		}
+		key.period = time.Duration(seconds) * time.Second
+	}
+	return key, nil
+}
+
+// decodeTOTPSeed decodes a base32 encoded seed. Spaces, lowercase letters and missing padding are
+// accepted, as seeds are often shown that way to be typed over.
+func decodeTOTPSeed(seed string) ([]byte, error) {
+	seed = strings.ToUpper(strings.Join(strings.Fields(seed), ""))
+	seed = strings.TrimRight(seed, "=")
+	if seed == "" {
+		return nil, ErrInvalidTOTPKey("the seed is empty")
+	}
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(seed)
+	if err != nil {
+		return nil, ErrInvalidTOTPKey("the seed is not base32 encoded")
+	}
+	return decoded, nil
+}
+
+// code returns the one-time password at the given time.
+func (k *totpKey) code(t time.Time) string {
+	counter := uint64(t.Unix() / int64(k.period/time.Second))
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(k.algorithm, k.seed)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, as specified in RFC 4226.
+	offset := sum[len(sum)-1] & 0xf
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint64(1)
+	for i := 0; i < k.digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", k.digits, uint64(truncated)%mod)
+}
+
+// remaining returns how long the one-time password at the given time stays valid.
+func (k *totpKey) remaining(t time.Time) time.Duration {
+	seconds := int64(k.period / time.Second)
+	return time.Duration(seconds-t.Unix()%seconds) * time.Second
+}
+
+// TOTPCommand prints the time-based one-time password of a secret with a TOTP key.
+type TOTPCommand struct {
+	io                  ui.IO
+	path                api.SecretPath
+	useClipboard        bool
+	clearClipboardAfter time.Duration
+	clipper             clip.Clipper
+	newClient           newClientFunc
+	now                 func() time.Time
+}
+
+// NewTOTPCommand creates a new TOTPCommand.
+func NewTOTPCommand(io ui.IO, newClient newClientFunc) *TOTPCommand {
+	return &TOTPCommand{
+		io:                  io,
+		clipper:             clip.NewClipboard(),
+		clearClipboardAfter: defaultClearClipboardAfter,
+		newClient:           newClient,
+		now:                 time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *TOTPCommand) Register(r command.Registerer) {
+	clause := r.Command("totp", "Print the current time-based one-time password (TOTP) of a secret, so the two-factor authentication "+
+		"of shared accounts can be kept in SecretHub instead of on a personal phone. The secret is an otpauth://totp/ URI, "+
+		"as encoded in the QR code that is shown when two-factor authentication is set up, or the base32 encoded seed, "+
+		"in which case the code has 6 digits, is valid for 30 seconds and uses SHA1. "+
+		"When the output is a terminal, how long the code stays valid is shown as well.")
+	clause.Arg("secret-path", "The path to the secret").Required().PlaceHolder(secretPathOptionalVersionPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("clip", fmt.Sprintf("Copy the code to the clipboard instead of printing it. The clipboard is automatically cleared after %s.", units.HumanDuration(cmd.clearClipboardAfter))).Short('c').BoolVar(&cmd.useClipboard)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the one-time password.
+func (cmd *TOTPCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	key, err := parseTOTPKey(string(secret.Data))
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	code := key.code(now)
+	remaining := key.remaining(now)
+
+	if cmd.useClipboard {
+		err = WriteClipboardAutoClear([]byte(code), cmd.clearClipboardAfter, cmd.clipper)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Copied the code of %s to clipboard. It is valid for %s and the clipboard will be cleared after %s.\n",
+			cmd.path, remaining, units.HumanDuration(cmd.clearClipboardAfter))
+		return nil
+	}
+
+	if cmd.io.IsOutputPiped() {
+		fmt.Fprintln(cmd.io.Output(), code)
+		return nil
+	}
+	fmt.Fprintf(cmd.io.Output(), "%s (valid for %s)\n", code, remaining)
+	return nil
+}
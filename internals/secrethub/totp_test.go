@@ -0,0 +1,157 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// The seeds of the test vectors of RFC 6238, base32 encoded.
+const (
+	totpSeedSHA1   = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	totpSeedSHA256 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA"
+	totpSeedSHA512 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNA"
+)
+
+func TestTOTPKey_code(t *testing.T) {
+	cases := map[string]struct {
+		key      string
+		time     int64
+		expected string
+	}{
+		"sha1 59": {
+			key:      "otpauth://totp/Example?secret=" + totpSeedSHA1 + "&digits=8",
+			time:     59,
+			expected: "94287082",
+		},
+		"sha1 1111111109": {
+			key:      "otpauth://totp/Example?secret=" + totpSeedSHA1 + "&digits=8",
+			time:     1111111109,
+			expected: "07081804",
+		},
+		"sha1 2000000000": {
+			key:      "otpauth://totp/Example?secret=" + totpSeedSHA1 + "&digits=8",
+			time:     2000000000,
+			expected: "69279037",
+		},
+		"sha256 59": {
+			key:      "otpauth://totp/Example?secret=" + totpSeedSHA256 + "&digits=8&algorithm=SHA256",
+			time:     59,
+			expected: "46119246",
+		},
+		"sha512 1234567890": {
+			key:      "otpauth://totp/Example?secret=" + totpSeedSHA512 + "&digits=8&algorithm=SHA512",
+			time:     1234567890,
+			expected: "93441116",
+		},
+		"seed": {
+			key:      totpSeedSHA1,
+			time:     59,
+			expected: "287082",
+		},
+		"seed with spaces and lowercase": {
+			key:      "gezd gnbv gy3t qojq gezd gnbv gy3t qojq\n",
+			time:     59,
+			expected: "287082",
+		},
+		"period": {
+			key:      "otpauth://totp/Example?secret=" + totpSeedSHA1 + "&digits=8&period=60",
+			time:     118,
+			expected: "94287082",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := parseTOTPKey(tc.key)
+			assert.OK(t, err)
+
+			assert.Equal(t, key.code(time.Unix(tc.time, 0)), tc.expected)
+		})
+	}
+}
+
+func TestParseTOTPKey_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		key string
+		err error
+	}{
+		"hotp": {
+			key: "otpauth://hotp/Example?secret=" + totpSeedSHA1 + "&counter=1",
+			err: ErrInvalidTOTPKey("one-time passwords of type hotp are not supported"),
+		},
+		"no secret": {
+			key: "otpauth://totp/Example?issuer=Example",
+			err: ErrInvalidTOTPKey("the seed is empty"),
+		},
+		"algorithm": {
+			key: "otpauth://totp/Example?secret=" + totpSeedSHA1 + "&algorithm=MD5",
+			err: ErrInvalidTOTPKey("unsupported algorithm MD5"),
+		},
+		"digits": {
+			key: "otpauth://totp/Example?secret=" + totpSeedSHA1 + "&digits=4",
+			err: ErrInvalidTOTPKey("invalid number of digits 4"),
+		},
+		"not base32": {
+			key: "password1!",
+			err: ErrInvalidTOTPKey("the seed is not base32 encoded"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseTOTPKey(tc.key)
+
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func TestTOTPCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		piped bool
+		out   string
+	}{
+		"terminal": {
+			out: "287082 (valid for 1s)\n",
+		},
+		"piped": {
+			piped: true,
+			out:   "287082\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			io.Out.Piped = tc.piped
+			cmd := TOTPCommand{
+				io:   io,
+				path: "namespace/repo/totp",
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									return &api.SecretVersion{Data: []byte(totpSeedSHA1)}, nil
+								},
+							},
+						},
+					}, nil
+				},
+				now: func() time.Time { return time.Unix(59, 0) },
+			}
+
+			err := cmd.Run()
+
+			assert.OK(t, err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
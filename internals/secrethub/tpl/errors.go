@@ -6,7 +6,8 @@ import (
 
 // Evaluate errors
 var (
-	ErrTemplateVarNotFound = tplError.Code("template_var_not_found").ErrorPref("no value was supplied for template variable '%s'")
+	ErrTemplateVarNotFound  = tplError.Code("template_var_not_found").ErrorPref("no value was supplied for template variable '%s'")
+	ErrSecretDigestMismatch = tplError.Code("secret_digest_mismatch").ErrorPref("the value of secret %s does not match the pinned digest sha256=%s")
 )
 
 // Parse errors
@@ -73,3 +74,13 @@ func ErrVariableTagNotClosed(lineNo, colNo int) error {
 		msg:    "expected the closing of a variable tag `}`, but reached the end of the template.",
 	}
 }
+
+// ErrInvalidSecretDigest is returned when the digest that pins the content of a secret is not a valid SHA-256 digest.
+func ErrInvalidSecretDigest(lineNo, colNo int) error {
+	return templateSyntaxError{
+		lineNo: lineNo,
+		colNo:  colNo,
+		code:   "invalid_secret_digest",
+		msg:    "invalid digest. A digest must be given as sha256= followed by 64 hexadecimal characters.",
+	}
+}
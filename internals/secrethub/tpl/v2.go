@@ -2,6 +2,8 @@ package tpl
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"strings"
 	"unicode"
@@ -23,6 +25,11 @@ import (
 // Spaces directly after opening delimiters (`{{` and `${`) and directly
 // before closing delimiters (`}}`, `}`) are ignored. They are not
 // included in the secret pahts and variable names.
+//
+// The content of a secret can be pinned by adding the SHA-256 digest
+// of its value after the path:
+// {{ path/to/secret sha256=<digest> }}
+// Evaluating the template fails when the value does not match the digest.
 func NewV2Parser() Parser {
 	return parserV2{}
 }
@@ -40,8 +47,14 @@ type node interface {
 	evaluate(ctx context) (string, error)
 }
 
+// secretDigestPrefix is the prefix of the digest that pins the content of a secret.
+const secretDigestPrefix = "sha256="
+
 type secret struct {
 	path []node
+	// digest is the hex encoded SHA-256 digest the value of the secret must match.
+	// It is empty when the content of the secret is not pinned.
+	digest string
 }
 
 func (s secret) evaluate(ctx context) (string, error) {
@@ -54,7 +67,20 @@ func (s secret) evaluate(ctx context) (string, error) {
 
 		buffer.WriteString(eval)
 	}
-	return ctx.secret(buffer.String())
+
+	path := buffer.String()
+	value, err := ctx.secret(path)
+	if err != nil {
+		return "", err
+	}
+
+	if s.digest != "" {
+		sum := sha256.Sum256([]byte(value))
+		if hex.EncodeToString(sum[:]) != s.digest {
+			return "", ErrSecretDigestMismatch(path, s.digest)
+		}
+	}
+	return value, nil
 }
 
 type variable struct {
@@ -93,6 +119,8 @@ type parserV2 struct{}
 //   {{path/to/secret}} has.
 // - Secret tags can also contain variable tags: `{{ path/with/${var}/to/secret }}`
 // - Variable tags cannot contain secret tags.
+// - Secret tags can pin the content of the secret by adding its SHA-256 digest after
+//   the path: `{{ path/to/secret sha256=<digest> }}`.
 // - Secret tags cannot contain secret tags (they cannot be nested).
 // - Variable tags cannot contain variable tags (they cannot be nested).
 func (p parserV2) Parse(raw string, line, column int) (Template, error) {
@@ -360,6 +388,19 @@ func (p *v2Parser) parseSecret() (node, error) {
 				return nil, checkError(err)
 			}
 
+			var digest string
+			if p.nextHasPrefix(secretDigestPrefix) {
+				digest, err = p.parseDigest()
+				if err != nil {
+					return nil, checkError(err)
+				}
+
+				err = p.skipWhiteSpace()
+				if err != nil {
+					return nil, checkError(err)
+				}
+			}
+
 			if p.next != token.RBracket {
 				return nil, ErrUnexpectedCharacter(p.lineNo, p.columnNo+1, p.next, token.RBracket)
 			}
@@ -374,7 +415,8 @@ func (p *v2Parser) parseSecret() (node, error) {
 			}
 
 			return secret{
-				path: path,
+				path:   path,
+				digest: digest,
 			}, nil
 		}
 
@@ -396,6 +438,49 @@ func (p *v2Parser) parseSecret() (node, error) {
 	}
 }
 
+// parseDigest parses the digest that pins the content of a secret.
+// The next character should be the first character of the digest prefix
+// when parseDigest is called.
+//
+// When parseDigest returns, the next character in the buffer is the first
+// character after the digest.
+func (p *v2Parser) parseDigest() (string, error) {
+	lineNo, colNo := p.lineNo, p.columnNo+1
+
+	for range secretDigestPrefix {
+		err := p.readRune()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var buffer bytes.Buffer
+	for p.isHexRune(p.next) {
+		buffer.WriteRune(unicode.ToLower(p.next))
+
+		err := p.readRune()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if buffer.Len() != hex.EncodedLen(sha256.Size) {
+		return "", ErrInvalidSecretDigest(lineNo, colNo)
+	}
+	return buffer.String(), nil
+}
+
+// nextHasPrefix returns whether the unread part of the template, starting with
+// the next character, starts with the given ASCII prefix.
+func (p v2Parser) nextHasPrefix(prefix string) bool {
+	return p.next == rune(prefix[0]) && bytes.HasPrefix(p.buf.Bytes(), []byte(prefix[1:]))
+}
+
+// isHexRune returns whether the given rune is a hexadecimal digit.
+func (p v2Parser) isHexRune(r rune) bool {
+	return unicode.Is(unicode.ASCII_Hex_Digit, r)
+}
+
 // isSecretPathRune returns whether the given rune is allowed to be used in
 // a secret path.
 func (p v2Parser) isSecretPathRune(r rune) bool {
@@ -496,6 +496,28 @@ func TestParserV2_parse(t *testing.T) {
 				character('a'),
 			},
 		},
+		"secret with digest": {
+			input: "{{ a sha256=486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7 }}",
+			expected: []node{
+				secret{
+					path: []node{
+						character('a'),
+					},
+					digest: "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7",
+				},
+			},
+		},
+		"secret with uppercase digest without space before closing": {
+			input: "{{a sha256=486EA46224D1BB4FB680F34F7C9AD96A8F24EC88BE73EA8E5A6C65260E9CB8A7}}",
+			expected: []node{
+				secret{
+					path: []node{
+						character('a'),
+					},
+					digest: "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7",
+				},
+			},
+		},
 		"illegal variable space": {
 			input: "${ va r }",
 			err:   ErrUnexpectedCharacter(1, 7, 'r', '}'),
@@ -592,6 +614,18 @@ func TestParserV2_parse(t *testing.T) {
 			input: "{{ foo/bar }baz",
 			err:   ErrUnexpectedCharacter(1, 13, 'b', '}'),
 		},
+		"invalid digest": {
+			input: "{{ a sha256=abc }}",
+			err:   ErrInvalidSecretDigest(1, 6),
+		},
+		"digest with illegal character": {
+			input: "{{ a sha256=486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7g }}",
+			err:   ErrUnexpectedCharacter(1, 77, 'g', '}'),
+		},
+		"secret tag not closed after digest": {
+			input: "{{ a sha256=486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7",
+			err:   ErrSecretTagNotClosed(1, 77),
+		},
 		"variable tag not closed": {
 			input: "${ var",
 			err:   ErrVariableTagNotClosed(1, 7),
@@ -662,6 +696,20 @@ func TestV2(t *testing.T) {
 			},
 			expected: "hello world",
 		},
+		"pinned secret": {
+			raw: "hello {{ secret sha256=486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7 }}",
+			secrets: map[string]string{
+				"secret": "world",
+			},
+			expected: "hello world",
+		},
+		"pinned secret changed": {
+			raw: "hello {{ secret sha256=486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7 }}",
+			secrets: map[string]string{
+				"secret": "other",
+			},
+			evalErr: ErrSecretDigestMismatch("secret", "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"),
+		},
 		"missing var": {
 			raw:  "hello {{ ${app}/greeting }}",
 			vars: map[string]string{},
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -13,21 +14,30 @@ import (
 
 // TreeCommand lists the contents of a directory at a given path in a tree-like format.
 type TreeCommand struct {
-	path      api.DirPath
-	io        ui.IO
-	newClient newClientFunc
+	path               api.DirPath
+	output             string
+	io                 ui.IO
+	newClient          newClientFunc
+	newPaginatedWriter func(io.Writer) (io.WriteCloser, error)
 }
 
 // NewTreeCommand creates a new TreeCommand.
 func NewTreeCommand(io ui.IO, clientFactory newClientFunc) *TreeCommand {
 	return &TreeCommand{
-		io:        io,
-		newClient: clientFactory,
+		io:                 io,
+		newClient:          clientFactory,
+		newPaginatedWriter: newPaginatedWriter,
 	}
 }
 
 // Run prints the contents of a directory at a given path in a tree-like format.
+// Long trees are piped through the terminal pager.
 func (cmd *TreeCommand) Run() error {
+	return withPager(&cmd.io, cmd.newPaginatedWriter, cmd.run)
+}
+
+// run prints the contents of a directory at a given path in a tree-like format.
+func (cmd *TreeCommand) run() error {
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
@@ -38,14 +48,20 @@ func (cmd *TreeCommand) Run() error {
 		return err
 	}
 
-	printTree(t, cmd.io.Output())
-	return nil
+	switch cmd.output {
+	case formatTable, "":
+		printTree(t, cmd.io.Output())
+		return nil
+	default:
+		return writeOutput(cmd.io.Output(), cmd.output, newTreeOutput(cmd.path.Value(), t.RootDir))
+	}
 }
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *TreeCommand) Register(r command.Registerer) {
 	clause := r.Command("tree", "List contents of a directory in a tree-like format.")
 	clause.Arg("dir-path", "The path to to show contents for").Required().PlaceHolder(optionalDirPathPlaceHolder).SetValue(&cmd.path)
+	registerOutputFlag(clause, &cmd.output, formatTable, formatJSON, formatYAML)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -98,3 +114,39 @@ func printDirContentsRecursively(dir *api.Dir, prefix string, w io.Writer) {
 		i++
 	}
 }
+
+// treeEntryOutput is the machine readable format of a directory or secret in a tree.
+type treeEntryOutput struct {
+	Path      string
+	Type      string
+	Status    string
+	CreatedAt string
+}
+
+// newTreeOutput returns the directories and secrets in the directory at the given path and all of its
+// subdirectories, in the order in which they are printed in the tree, excluding the directory itself.
+func newTreeOutput(path string, dir *api.Dir) []treeEntryOutput {
+	sort.Sort(api.SortDirByName(dir.SubDirs))
+	sort.Sort(api.SortSecretByName(dir.Secrets))
+
+	out := []treeEntryOutput{}
+	for _, sub := range dir.SubDirs {
+		subPath := path + "/" + sub.Name
+		out = append(out, treeEntryOutput{
+			Path:      subPath,
+			Type:      lsTypeDir,
+			Status:    sub.Status,
+			CreatedAt: sub.CreatedAt.UTC().Format(time.RFC3339),
+		})
+		out = append(out, newTreeOutput(subPath, sub)...)
+	}
+	for _, secret := range dir.Secrets {
+		out = append(out, treeEntryOutput{
+			Path:      path + "/" + secret.Name,
+			Type:      lsTypeSecret,
+			Status:    secret.Status,
+			CreatedAt: secret.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return out
+}
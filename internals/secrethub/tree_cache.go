@@ -0,0 +1,432 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/auth"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	sechttp "github.com/secrethub/secrethub-go/pkg/secrethub/internals/http"
+)
+
+// Errors
+var (
+	ErrInvalidCacheConfig = errMain.Code("invalid_cache_config").ErrorPref("invalid cache_ttl configured in %s: %s")
+)
+
+// defaultCacheTTL is the time a cached directory tree is used when no cache_ttl is configured.
+// The cache is off by default, as cached listings do not show changes made by others.
+const defaultCacheTTL = 0
+
+// treeCacheKeyLength is the length in bytes of the AES-256 key the cached directory trees are encrypted with.
+const treeCacheKeyLength = 32
+
+// cacheDirMode is the file mode of the directories cached API responses are stored in.
+const cacheDirMode = os.FileMode(0700)
+
+// cacheDir returns the directory all API responses are cached in.
+func cacheDir(dir configdir.Dir) string {
+	return filepath.Join(dir.Path(), "cache")
+}
+
+// treeCacheDir returns the directory the directory trees are cached in.
+func treeCacheDir(dir configdir.Dir) string {
+	return filepath.Join(cacheDir(dir), "trees")
+}
+
+// treeCacheKeyPath returns the path of the file with the key the directory trees are encrypted with.
+// It is stored outside of the directory with the trees, so it is kept when the trees are cleared.
+func treeCacheKeyPath(dir configdir.Dir) string {
+	return filepath.Join(cacheDir(dir), "trees.key")
+}
+
+// clearCache removes all cached responses in the directory.
+func clearCache(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+// loadCacheTTL returns the time cached directory trees are used as configured in the configuration
+// directory, or the default when none is configured. A TTL of zero, the default, disables the cache.
+func loadCacheTTL(dir configdir.Dir) (time.Duration, error) {
+	config, err := loadConfig(dir)
+	if err != nil {
+		return defaultCacheTTL, err
+	}
+	file := configFile(dir)
+	if config.CacheTTL == "" {
+		return defaultCacheTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(config.CacheTTL)
+	if err != nil {
+		return defaultCacheTTL, ErrInvalidCacheConfig(file.path, err)
+	}
+	if ttl < 0 {
+		return defaultCacheTTL, ErrInvalidCacheConfig(file.path, "the TTL cannot be negative")
+	}
+	return ttl, nil
+}
+
+// cacheTTL returns the time cached directory trees are used, which is zero when --no-cache is set.
+// An invalid configuration does not stop the command: a warning is printed and the default TTL is used.
+func (f *clientFactory) cacheTTL() time.Duration {
+	if f.noCache || f.store.ConfigDir().Path() == "" {
+		return 0
+	}
+	ttl, err := loadCacheTTL(f.store.ConfigDir())
+	if err != nil {
+		warnConfig(err)
+	}
+	return ttl
+}
+
+// treeCacheKey is the key the cached directory trees are encrypted with. The key is stored on disk
+// wrapped with the credential of the client, so the cached trees can only be read with that credential.
+// Only credentials that can wrap keys, i.e. key credentials, can be used to cache directory trees.
+type treeCacheKey struct {
+	path      string
+	decrypter credentials.Decrypter
+	once      sync.Once
+	key       []byte
+}
+
+// newTreeCacheKey returns the key stored in the file at path.
+func newTreeCacheKey(path string) *treeCacheKey {
+	return &treeCacheKey{
+		path: path,
+	}
+}
+
+// Provider returns a credential provider that provides the credential of the given provider
+// and uses it to unwrap the key.
+func (k *treeCacheKey) Provider(provider credentials.Provider) credentials.Provider {
+	return treeCacheKeyProvider{
+		Provider: provider,
+		key:      k,
+	}
+}
+
+// Get returns the key. It creates a new key when there is no key yet or when the stored key cannot be
+// unwrapped with the credential. It returns false when the credential cannot wrap keys, in which case
+// no directory trees are cached.
+func (k *treeCacheKey) Get() ([]byte, bool) {
+	k.once.Do(func() {
+		k.key = k.load()
+	})
+	return k.key, k.key != nil
+}
+
+// load reads and unwraps the stored key or creates and stores a new one.
+func (k *treeCacheKey) load() []byte {
+	if k.decrypter == nil {
+		return nil
+	}
+
+	var wrapped api.EncryptedData
+	exists, err := readJSONFile(k.path, &wrapped)
+	if err == nil && exists {
+		key, err := k.decrypter.Unwrap(&wrapped)
+		if err == nil && len(key) == treeCacheKeyLength {
+			return key
+		}
+	}
+
+	// The trees cached with a key that cannot be unwrapped can no longer be read,
+	// so they are left to be replaced by trees encrypted with the new key.
+	encrypter, ok := k.decrypter.(credentials.Encrypter)
+	if !ok {
+		return nil
+	}
+	key := make([]byte, treeCacheKeyLength)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil
+	}
+	newWrapped, err := encrypter.Wrap(key)
+	if err != nil {
+		return nil
+	}
+	err = writeJSONFile(k.path, newWrapped, configFileMode)
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+// treeCacheKeyProvider is a credentials.Provider that keeps the decrypter of the credential it
+// provides, so it can be used to unwrap the key of the tree cache.
+type treeCacheKeyProvider struct {
+	credentials.Provider
+	key *treeCacheKey
+}
+
+// Provide provides the credential of the wrapped provider.
+func (p treeCacheKeyProvider) Provide(httpClient *sechttp.Client) (auth.Authenticator, credentials.Decrypter, error) {
+	authenticator, decrypter, err := p.Provider.Provide(httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.key.decrypter = decrypter
+	return authenticator, decrypter, nil
+}
+
+// treeCacheTransport is a http.RoundTripper that caches the directory trees fetched from the API on disk,
+// so repeated listings and completions do not fetch the same tree again. The cached trees are encrypted
+// with a key that is wrapped with the credential, so they can only be read by the account that fetched them.
+//
+// Every call that can change a directory tree, i.e. every call that is not a GET or HEAD request,
+// clears the whole cache. Changes made by others only show up once the cached trees expire, so a
+// notice is printed when a listing is served from the cache.
+type treeCacheTransport struct {
+	next     http.RoundTripper
+	dir      string
+	ttl      time.Duration
+	key      func() ([]byte, bool)
+	now      func() time.Time
+	stderr   io.Writer
+	notified sync.Once
+}
+
+// newTreeCacheTransport returns a transport that passes the API calls on to next and caches the
+// directory trees in dir for the given time, encrypted with the given key.
+func newTreeCacheTransport(next http.RoundTripper, dir string, ttl time.Duration, key func() ([]byte, bool)) *treeCacheTransport {
+	return &treeCacheTransport{
+		next:   next,
+		dir:    dir,
+		ttl:    ttl,
+		key:    key,
+		now:    time.Now,
+		stderr: os.Stderr,
+	}
+}
+
+// RoundTrip returns a cached directory tree if it has not expired and otherwise performs the request.
+func (t *treeCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.next.RoundTrip(req)
+		_ = clearCache(t.dir)
+		return resp, err
+	}
+
+	if req.Method != http.MethodGet || !isTreeRequest(req) {
+		return t.next.RoundTrip(req)
+	}
+	path, ok := cacheEntryPath(t.dir, req)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	key, ok := t.key()
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	now := t.now()
+	sealed, ok := readCacheEntry(path, t.ttl, now)
+	if ok {
+		body, err := openCacheEntry(key, path, sealed)
+		if err == nil {
+			structuredLog.debug("cache_hit", logFields{"cache": "tree", "url": req.URL.String()})
+			t.notify(path, now)
+			return cachedResponse(req, body), nil
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return t.cacheResponse(key, path, resp)
+}
+
+// notify prints a notice that a listing is served from the cache, once per command.
+func (t *treeCacheTransport) notify(path string, now time.Time) {
+	t.notified.Do(func() {
+		age := "recently"
+		info, err := os.Stat(path)
+		if err == nil {
+			age = units.HumanDuration(now.Sub(info.ModTime())) + " ago"
+		}
+		fmt.Fprintf(statusWriter(t.stderr), "Note: using directory trees cached %s. Use --no-cache to fetch the latest changes.\n", strings.ToLower(age))
+	})
+}
+
+// cacheResponse caches the body of a successful JSON response encrypted with the key and returns
+// the response with a body that can still be read. Other responses are returned as is.
+// Failing to cache the response does not fail the call.
+func (t *treeCacheTransport) cacheResponse(key []byte, path string, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	sealed, err := sealCacheEntry(key, path, body)
+	if err == nil {
+		_ = writeCacheEntry(t.dir, path, sealed)
+	}
+	return resp, nil
+}
+
+// sealCacheEntry encrypts the body of a cached response with AES-GCM. The name of the file it is
+// cached in is authenticated, so an entry cannot be swapped for the entry of another request.
+func sealCacheEntry(key []byte, path string, body []byte) ([]byte, error) {
+	gcm, err := newCacheCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, body, []byte(filepath.Base(path))), nil
+}
+
+// openCacheEntry decrypts the body of a cached response sealed with sealCacheEntry.
+func openCacheEntry(key []byte, path string, sealed []byte) ([]byte, error) {
+	gcm, err := newCacheCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, api.ErrInvalidCiphertext
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, []byte(filepath.Base(path)))
+}
+
+// newCacheCipher returns the AES-GCM cipher for the key.
+func newCacheCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// cacheEntryPath returns the path of the file in dir the response to the request is cached in.
+// Responses are only cached for requests signed with a credential, as the end-to-end encrypted
+// responses can only be decrypted by the account that fetched them. The path is derived from the
+// credential and the URL, so it does not reveal which resource is cached.
+func cacheEntryPath(dir string, req *http.Request) (string, bool) {
+	signer, ok := requestSigner(req)
+	if !ok {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(signer + "\n" + req.URL.String()))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json"), true
+}
+
+// readCacheEntry returns the cached response in the file if it is not older than maxAge.
+func readCacheEntry(path string, maxAge time.Duration, now time.Time) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil || now.Sub(info.ModTime()) > maxAge {
+		return nil, false
+	}
+
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// cacheResponse caches the body of a successful JSON response in the file at path and returns
+// the response with a body that can still be read. Other responses are returned as is.
+// Failing to cache the response does not fail the call.
+func cacheResponse(dir string, path string, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	_ = writeCacheEntry(dir, path, body)
+	return resp, nil
+}
+
+// cachedResponse returns the response to the request with the cached body.
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// writeCacheEntry writes the response to the file at path in dir. The response is first written to
+// a temporary file that is then renamed, so other processes never read a partially written response.
+func writeCacheEntry(dir string, path string, body []byte) error {
+	err := os.MkdirAll(dir, cacheDirMode)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(body)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// isTreeRequest returns whether the request fetches a directory tree, i.e. GET /dirs/<blind name>.
+func isTreeRequest(req *http.Request) bool {
+	elements := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	return len(elements) >= 2 && elements[len(elements)-2] == "dirs"
+}
+
+// requestSigner returns the identifier of the credential that signed the request, which is
+// the part of the Authorization header before the signature: <format> <identifier>:<signature>.
+func requestSigner(req *http.Request) (string, bool) {
+	authorization := req.Header.Get("Authorization")
+	i := strings.LastIndex(authorization, ":")
+	if i <= 0 {
+		return "", false
+	}
+	return authorization[:i], true
+}
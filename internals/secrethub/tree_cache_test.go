@@ -0,0 +1,177 @@
+package secrethub
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestTreeCacheTransport_RoundTrip(t *testing.T) {
+	const treeURL = "https://api.secrethub.io/v1/dirs/blindname?depth=1"
+
+	type call struct {
+		method        string
+		url           string
+		authorization string
+		after         time.Duration
+		fetched       bool
+	}
+
+	cases := map[string]struct {
+		calls  []call
+		noKey  bool
+		notice string
+	}{
+		"cached": {
+			calls: []call{
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig1", fetched: true},
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig2", after: 90 * time.Second, fetched: false},
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig3", after: 90 * time.Second, fetched: false},
+			},
+			notice: "Note: using directory trees cached about a minute ago. Use --no-cache to fetch the latest changes.\n",
+		},
+		"no key": {
+			calls: []call{
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig1", fetched: true},
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig2", fetched: true},
+			},
+			noKey: true,
+		},
+		"expired": {
+			calls: []call{
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig1", fetched: true},
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig2", after: time.Hour, fetched: true},
+			},
+		},
+		"other credential": {
+			calls: []call{
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig1", fetched: true},
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x bob:sig2", fetched: true},
+			},
+		},
+		"other depth": {
+			calls: []call{
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig1", fetched: true},
+				{method: http.MethodGet, url: "https://api.secrethub.io/v1/dirs/blindname?depth=-1", authorization: "SH1-x alice:sig2", fetched: true},
+			},
+		},
+		"cleared by change": {
+			calls: []call{
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig1", fetched: true},
+				{method: http.MethodPost, url: "https://api.secrethub.io/v1/namespaces/alice/repos/repo/secrets", authorization: "SH1-x alice:sig2", fetched: true},
+				{method: http.MethodGet, url: treeURL, authorization: "SH1-x alice:sig3", fetched: true},
+			},
+		},
+		"not signed": {
+			calls: []call{
+				{method: http.MethodGet, url: treeURL, fetched: true},
+				{method: http.MethodGet, url: treeURL, fetched: true},
+			},
+		},
+		"not a tree": {
+			calls: []call{
+				{method: http.MethodGet, url: "https://api.secrethub.io/v1/dirs/blindname/rules", authorization: "SH1-x alice:sig1", fetched: true},
+				{method: http.MethodGet, url: "https://api.secrethub.io/v1/dirs/blindname/rules", authorization: "SH1-x alice:sig2", fetched: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			fetched := false
+			transport := newTreeCacheTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				fetched = true
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       ioutil.NopCloser(strings.NewReader(`{"root_dir":{}}`)),
+				}, nil
+			}), filepath.Join(dir, "cache", "trees"), 5*time.Minute, func() ([]byte, bool) {
+				return bytes.Repeat([]byte{1}, treeCacheKeyLength), !tc.noKey
+			})
+			stderr := &bytes.Buffer{}
+			transport.stderr = stderr
+
+			now := time.Now()
+			for _, c := range tc.calls {
+				transport.now = func() time.Time {
+					return now.Add(c.after)
+				}
+				fetched = false
+
+				req, err := http.NewRequest(c.method, c.url, nil)
+				assert.OK(t, err)
+				if c.authorization != "" {
+					req.Header.Set("Authorization", c.authorization)
+				}
+
+				resp, err := transport.RoundTrip(req)
+				assert.OK(t, err)
+
+				body, err := ioutil.ReadAll(resp.Body)
+				assert.OK(t, err)
+				assert.Equal(t, string(body), `{"root_dir":{}}`)
+				assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+				assert.Equal(t, fetched, c.fetched)
+			}
+			assert.Equal(t, stderr.String(), tc.notice)
+		})
+	}
+}
+
+func TestTreeCacheTransport_RoundTrip_Encrypted(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+	cacheDir := filepath.Join(dir, "cache", "trees")
+
+	fetches := 0
+	newTransport := func(key byte) *treeCacheTransport {
+		transport := newTreeCacheTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			fetches++
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"root_dir":{"name":"secret"}}`)),
+			}, nil
+		}), cacheDir, 5*time.Minute, func() ([]byte, bool) {
+			return bytes.Repeat([]byte{key}, treeCacheKeyLength), true
+		})
+		transport.stderr = ioutil.Discard
+		return transport
+	}
+
+	get := func(transport *treeCacheTransport) {
+		req, err := http.NewRequest(http.MethodGet, "https://api.secrethub.io/v1/dirs/blindname?depth=1", nil)
+		assert.OK(t, err)
+		req.Header.Set("Authorization", "SH1-x alice:sig")
+		resp, err := transport.RoundTrip(req)
+		assert.OK(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.OK(t, err)
+		assert.Equal(t, string(body), `{"root_dir":{"name":"secret"}}`)
+	}
+
+	get(newTransport(1))
+
+	files, err := ioutil.ReadDir(cacheDir)
+	assert.OK(t, err)
+	assert.Equal(t, len(files), 1)
+	cached, err := ioutil.ReadFile(filepath.Join(cacheDir, files[0].Name()))
+	assert.OK(t, err)
+	assert.Equal(t, bytes.Contains(cached, []byte("root_dir")), false)
+
+	// A cached tree that cannot be decrypted with the key is fetched again.
+	get(newTransport(2))
+	assert.Equal(t, fetches, 2)
+	get(newTransport(2))
+	assert.Equal(t, fetches, 2)
+}
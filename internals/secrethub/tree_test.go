@@ -0,0 +1,40 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestNewTreeOutput(t *testing.T) {
+	createdAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	root := &api.Dir{
+		Name: "repo",
+		SubDirs: []*api.Dir{
+			{
+				Name:      "prod",
+				Status:    api.StatusOK,
+				CreatedAt: createdAt,
+				Secrets: []*api.Secret{
+					{Name: "password", Status: api.StatusOK, CreatedAt: createdAt},
+				},
+			},
+			{Name: "dev", Status: api.StatusOK, CreatedAt: createdAt},
+		},
+		Secrets: []*api.Secret{
+			{Name: "api_key", Status: api.StatusOK, CreatedAt: createdAt},
+		},
+	}
+
+	actual := newTreeOutput("company/repo", root)
+
+	assert.Equal(t, actual, []treeEntryOutput{
+		{Path: "company/repo/dev", Type: "dir", Status: "ok", CreatedAt: "2020-01-01T12:00:00Z"},
+		{Path: "company/repo/prod", Type: "dir", Status: "ok", CreatedAt: "2020-01-01T12:00:00Z"},
+		{Path: "company/repo/prod/password", Type: "secret", Status: "ok", CreatedAt: "2020-01-01T12:00:00Z"},
+		{Path: "company/repo/api_key", Type: "secret", Status: "ok", CreatedAt: "2020-01-01T12:00:00Z"},
+	})
+}
@@ -0,0 +1,28 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// VaultCommand handles the local vault, which stores repositories and secrets on this computer.
+type VaultCommand struct {
+	io            ui.IO
+	clientFactory ClientFactory
+}
+
+// NewVaultCommand creates a new VaultCommand.
+func NewVaultCommand(io ui.IO, clientFactory ClientFactory) *VaultCommand {
+	return &VaultCommand{
+		io:            io,
+		clientFactory: clientFactory,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *VaultCommand) Register(r command.Registerer) {
+	clause := r.Command("vault", "Manage the local vault, a passphrase-encrypted file that stores repositories and secrets on this computer, "+
+		"so the CLI can be used offline without an account. Use the vault with the --backend local flag or by setting SECRETHUB_BACKEND=local.")
+	NewVaultInitCommand(cmd.io, cmd.clientFactory.CreateVault).Register(clause)
+	NewVaultPushCommand(cmd.io, cmd.clientFactory.NewVaultClient, cmd.clientFactory.NewAPIClient).Register(clause)
+}
@@ -0,0 +1,222 @@
+// Package vault provides a passphrase-encrypted local file that stores repositories and
+// secrets, so the CLI can be used without a SecretHub account.
+//
+// A vault contains the state of a development server with a single account. Clients use
+// the vault as the transport to the server, which handles their requests in process and
+// saves the vault after every change. The file starts with a magic header, followed by
+// the random salt used to derive the encryption key from the passphrase, the nonce and
+// the AES-256-GCM encrypted snapshot of the server.
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/secrethub/secrethub-cli/internals/secrethub/devserver"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	saltLength = 16
+	keyLength  = 32
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var magic = []byte("SHUBVLT1")
+
+// Errors
+var (
+	ErrNotFound        = errors.New("the vault does not exist")
+	ErrAlreadyExists   = errors.New("the vault already exists")
+	ErrInvalidVault    = errors.New("the file is not a valid SecretHub vault")
+	ErrWrongPassphrase = errors.New("cannot unlock the vault: wrong passphrase or corrupted file")
+	ErrNoPassphrase    = errors.New("a passphrase is required to create or unlock a vault")
+)
+
+// Vault is a local file with repositories and secrets. It implements http.RoundTripper,
+// so it can be used as the transport of a client.
+type Vault struct {
+	mutex  sync.Mutex
+	path   string
+	salt   []byte
+	aead   cipher.AEAD
+	server *devserver.Server
+}
+
+// Create creates a new vault at the path with an account with the given username,
+// which is encrypted with the passphrase.
+func Create(path string, username string, passphrase []byte) (*Vault, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrNoPassphrase
+	}
+
+	_, err := os.Stat(path)
+	if err == nil {
+		return nil, ErrAlreadyExists
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	server, err := devserver.New(username)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, saltLength)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Vault{
+		path:   path,
+		salt:   salt,
+		aead:   aead,
+		server: server,
+	}
+	err = v.save()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Open reads the vault at the path and decrypts it with the passphrase.
+func Open(path string, passphrase []byte) (*Vault, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrNoPassphrase
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < len(magic)+saltLength || !bytes.Equal(raw[:len(magic)], magic) {
+		return nil, ErrInvalidVault
+	}
+	salt := raw[len(magic) : len(magic)+saltLength]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	headerLength := len(magic) + saltLength + aead.NonceSize()
+	if len(raw) < headerLength {
+		return nil, ErrInvalidVault
+	}
+	nonce := raw[len(magic)+saltLength : headerLength]
+
+	plaintext, err := aead.Open(nil, nonce, raw[headerLength:], raw[:headerLength])
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	server, err := devserver.Restore(plaintext)
+	if err != nil {
+		return nil, ErrInvalidVault
+	}
+
+	return &Vault{
+		path:   path,
+		salt:   salt,
+		aead:   aead,
+		server: server,
+	}, nil
+}
+
+// Credential returns the exported credential of the account of the vault.
+func (v *Vault) Credential() string {
+	return v.server.Credential()
+}
+
+// RoundTrip handles the request with the server of the vault and saves the vault
+// when the request has changed its contents.
+func (v *Vault) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	recorder := httptest.NewRecorder()
+	v.server.ServeHTTP(recorder, r)
+	resp := recorder.Result()
+	resp.Request = r
+
+	if r.Method != http.MethodGet && resp.StatusCode < 300 {
+		err := v.save()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// save encrypts the snapshot of the server and replaces the file of the vault with it.
+// A new nonce is used every time, so the key derived from the passphrase can be reused.
+func (v *Vault) save() error {
+	plaintext, err := v.server.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, v.aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	header := append(append(append([]byte{}, magic...), v.salt...), nonce...)
+	ciphertext := v.aead.Seal(nil, nonce, plaintext, header)
+
+	err = os.MkdirAll(filepath.Dir(v.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	// The vault is written to a temporary file first, so it is never left half written.
+	tmp := v.path + ".tmp"
+	err = ioutil.WriteFile(tmp, append(header, ciphertext...), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, v.path)
+}
+
+// newAEAD derives an AES-256-GCM cipher from the passphrase and salt.
+func newAEAD(passphrase []byte, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,71 @@
+package vault
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+func newClient(t *testing.T, v *Vault) secrethub.ClientInterface {
+	client, err := secrethub.NewClient(
+		secrethub.WithServerURL("http://vault"),
+		secrethub.WithTransport(v),
+		secrethub.WithCredentials(credentials.UseKey(credentials.FromString(v.Credential()))),
+	)
+	assert.OK(t, err)
+	return client
+}
+
+func TestVault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrethub-vault")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "vault")
+	passphrase := []byte("correct horse battery staple")
+
+	_, err = Open(path, passphrase)
+	assert.Equal(t, err, ErrNotFound)
+
+	v, err := Create(path, "local", passphrase)
+	assert.OK(t, err)
+	client := newClient(t, v)
+	_, err = client.Repos().Create("local/repo")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("local/repo/secret", []byte("value"))
+	assert.OK(t, err)
+
+	raw, err := ioutil.ReadFile(path)
+	assert.OK(t, err)
+	assert.Equal(t, raw[:len(magic)], magic)
+
+	_, err = Create(path, "local", passphrase)
+	assert.Equal(t, err, ErrAlreadyExists)
+	_, err = Open(path, []byte("wrong"))
+	assert.Equal(t, err, ErrWrongPassphrase)
+	_, err = Open(path, nil)
+	assert.Equal(t, err, ErrNoPassphrase)
+
+	reopened, err := Open(path, passphrase)
+	assert.OK(t, err)
+	value, err := newClient(t, reopened).Secrets().ReadString("local/repo/secret")
+	assert.OK(t, err)
+	assert.Equal(t, value, "value")
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrethub-vault")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "vault")
+
+	err = ioutil.WriteFile(path, []byte("not a vault"), 0600)
+	assert.OK(t, err)
+
+	_, err = Open(path, []byte("passphrase"))
+	assert.Equal(t, err, ErrInvalidVault)
+}
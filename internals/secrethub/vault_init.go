@@ -0,0 +1,44 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// VaultInitCommand creates a local vault.
+type VaultInitCommand struct {
+	io          ui.IO
+	username    string
+	createVault func(username string) (string, error)
+}
+
+// NewVaultInitCommand creates a new VaultInitCommand.
+func NewVaultInitCommand(io ui.IO, createVault func(username string) (string, error)) *VaultInitCommand {
+	return &VaultInitCommand{
+		io:          io,
+		createVault: createVault,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *VaultInitCommand) Register(r command.Registerer) {
+	clause := r.Command("init", "Create a local vault, encrypted with a passphrase. The vault has a single account, "+
+		"of which the username is the namespace of the repositories in the vault.")
+	clause.Flag("username", "The username of the account in the vault.").Default("local").StringVar(&cmd.username)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run creates the local vault.
+func (cmd *VaultInitCommand) Run() error {
+	path, err := cmd.createVault(cmd.username)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Created a local vault at %s.\n", path)
+	fmt.Fprintf(cmd.io.Output(), "Use it with --backend local or SECRETHUB_BACKEND=local and create your first repository with:\n\n    secrethub repo init %s/<repo> --backend local\n", cmd.username)
+	return nil
+}
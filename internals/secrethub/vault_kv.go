@@ -0,0 +1,232 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// Errors
+var (
+	ErrVaultNoAddress        = errMain.Code("vault_no_address").Error("could not find the address of the Vault server: set VAULT_ADDR")
+	ErrVaultNoToken          = errMain.Code("vault_no_token").Error("could not find a Vault token: set VAULT_TOKEN or log in with `vault login`")
+	ErrVaultRequestFailed    = errMain.Code("vault_request_failed").ErrorPref("request to Vault failed: %s")
+	ErrInvalidVaultKVVersion = errMain.Code("invalid_vault_kv_version").ErrorPref("invalid KV version %d: must be 1 or 2")
+)
+
+// vaultKV is a KV secrets engine mount of HashiCorp Vault.
+type vaultKV interface {
+	// List returns the keys below the path. The keys of folders end with a slash.
+	List(path string) ([]string, error)
+	// Read returns the data of the given version of the secret. Version 0 is the latest version.
+	Read(path string, version int) (map[string]interface{}, error)
+	// Versions returns the versions of the secret that are not deleted or destroyed, oldest first.
+	// KV version 1 does not keep history, so it only returns the latest version, 0.
+	Versions(path string) ([]int, error)
+}
+
+// newVaultKVFunc creates a client for the KV secrets engine at the mount.
+// When kvVersion is 0, the version of the secrets engine is detected.
+type newVaultKVFunc func(mount string, kvVersion int) (vaultKV, error)
+
+// newVaultKV creates a client for the KV secrets engine at the mount, using the
+// address, token and namespace that the Vault CLI uses.
+func newVaultKV(mount string, kvVersion int) (vaultKV, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, ErrVaultNoAddress
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		home, err := homedir.Dir()
+		if err == nil {
+			data, err := ioutil.ReadFile(filepath.Join(home, ".vault-token"))
+			if err == nil {
+				token = strings.TrimSpace(string(data))
+			}
+		}
+	}
+	if token == "" {
+		return nil, ErrVaultNoToken
+	}
+
+	kv := &vaultKVClient{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		addr:      strings.TrimSuffix(addr, "/"),
+		token:     token,
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		mount:     strings.Trim(mount, "/"),
+		version:   kvVersion,
+	}
+
+	if kv.version == 0 {
+		var err error
+		kv.version, err = kv.detectVersion()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if kv.version != 1 && kv.version != 2 {
+		return nil, ErrInvalidVaultKVVersion(kv.version)
+	}
+	return kv, nil
+}
+
+// vaultKVClient implements vaultKV with the Vault HTTP API.
+type vaultKVClient struct {
+	client    *http.Client
+	addr      string
+	token     string
+	namespace string
+	mount     string
+	version   int
+}
+
+// detectVersion returns the version of the KV secrets engine from the options of the mount.
+func (c *vaultKVClient) detectVersion() (int, error) {
+	var resp struct {
+		Data struct {
+			Options map[string]string `json:"options"`
+		} `json:"data"`
+	}
+	_, err := c.do("GET", "sys/internal/ui/mounts/"+c.mount, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.Data.Options["version"] == "2" {
+		return 2, nil
+	}
+	return 1, nil
+}
+
+// List returns the keys below the path.
+func (c *vaultKVClient) List(path string) ([]string, error) {
+	var resp struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	found, err := c.do("LIST", c.apiPath("metadata", path), &resp)
+	if err != nil || !found {
+		return nil, err
+	}
+	return resp.Data.Keys, nil
+}
+
+// Read returns the data of the given version of the secret.
+func (c *vaultKVClient) Read(path string, version int) (map[string]interface{}, error) {
+	if c.version == 1 {
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		_, err := c.do("GET", c.apiPath("", path), &resp)
+		return resp.Data, err
+	}
+
+	p := c.apiPath("data", path)
+	if version > 0 {
+		p += "?version=" + strconv.Itoa(version)
+	}
+
+	var resp struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	_, err := c.do("GET", p, &resp)
+	return resp.Data.Data, err
+}
+
+// Versions returns the versions of the secret that are not deleted or destroyed, oldest first.
+func (c *vaultKVClient) Versions(path string) ([]int, error) {
+	if c.version == 1 {
+		return []int{0}, nil
+	}
+
+	var resp struct {
+		Data struct {
+			Versions map[string]struct {
+				DeletionTime string `json:"deletion_time"`
+				Destroyed    bool   `json:"destroyed"`
+			} `json:"versions"`
+		} `json:"data"`
+	}
+	_, err := c.do("GET", c.apiPath("metadata", path), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	for key, version := range resp.Data.Versions {
+		if version.DeletionTime != "" || version.Destroyed {
+			continue
+		}
+		n, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, ErrVaultRequestFailed(fmt.Sprintf("unexpected version %q", key))
+		}
+		versions = append(versions, n)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// apiPath returns the API path of a secret. The KV version 2 API has separate paths for
+// the data and metadata of secrets, which are not used for KV version 1.
+func (c *vaultKVClient) apiPath(kind string, path string) string {
+	if c.version == 1 || kind == "" {
+		return c.mount + "/" + path
+	}
+	return c.mount + "/" + kind + "/" + path
+}
+
+// do sends a request to Vault and decodes the response into out.
+// It returns false when Vault responds that the path does not exist.
+func (c *vaultKVClient) do(method string, path string, out interface{}) (bool, error) {
+	req, err := http.NewRequest(method, c.addr+"/v1/"+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, ErrVaultRequestFailed(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if resp.StatusCode >= 300 {
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&errResp)
+		if err != nil || len(errResp.Errors) == 0 {
+			return false, ErrVaultRequestFailed(resp.Status)
+		}
+		return false, ErrVaultRequestFailed(strings.Join(errResp.Errors, ", "))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return false, ErrVaultRequestFailed(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,104 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// VaultPushCommand copies the repositories in the local vault to an account on the SecretHub API.
+type VaultPushCommand struct {
+	io             ui.IO
+	namespace      string
+	onConflict     string
+	newVaultClient newClientFunc
+	newClient      newClientFunc
+}
+
+// NewVaultPushCommand creates a new VaultPushCommand.
+func NewVaultPushCommand(io ui.IO, newVaultClient newClientFunc, newClient newClientFunc) *VaultPushCommand {
+	return &VaultPushCommand{
+		io:             io,
+		newVaultClient: newVaultClient,
+		newClient:      newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *VaultPushCommand) Register(r command.Registerer) {
+	clause := r.Command("push", "Copy all repositories in the local vault to the SecretHub API, with all versions of their secrets. "+
+		"Repositories that do not exist yet are created. The local vault is left as it is.")
+	clause.Flag("namespace", "The namespace to push the repositories to. Defaults to your username.").StringVar(&cmd.namespace)
+	clause.Flag("on-conflict", "What to do with secrets that already exist in the repositories: skip them, overwrite them by writing the local versions on top or rename the local secret by adding an "+importRenameSuffix+" suffix.").HintOptions(conflictSkip, conflictOverwrite, conflictRename).Default(conflictSkip).StringVar(&cmd.onConflict)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run pushes the repositories in the local vault.
+func (cmd *VaultPushCommand) Run() error {
+	if cmd.onConflict != conflictSkip && cmd.onConflict != conflictOverwrite && cmd.onConflict != conflictRename {
+		return ErrInvalidConflictStrategy(cmd.onConflict)
+	}
+
+	vaultClient, err := cmd.newVaultClient()
+	if err != nil {
+		return err
+	}
+
+	repos, err := vaultClient.Repos().ListMine()
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		fmt.Fprintln(cmd.io.Output(), "The local vault has no repositories to push.")
+		return nil
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	namespace := cmd.namespace
+	if namespace == "" {
+		me, err := client.Me().GetUser()
+		if err != nil {
+			return err
+		}
+		namespace = me.Username
+	}
+
+	for _, repo := range repos {
+		target, err := api.NewRepoPath(namespace + "/" + repo.Name)
+		if err != nil {
+			return err
+		}
+
+		archive, err := newRepoBackup(vaultClient, repo.Path())
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Repos().Get(target.Value())
+		notFound := api.IsErrNotFound(err)
+		if err != nil && !notFound {
+			return err
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "Pushing %s to %s\n", repo.Path(), target)
+		importCmd := RepoImportCommand{
+			io:         cmd.io,
+			path:       target,
+			onConflict: cmd.onConflict,
+			createRepo: notFound,
+		}
+		err = importCmd.importArchive(client, archive)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestVaultPushCommand_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrethub-vault")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+
+	factory := &clientFactory{
+		io:              fakeui.NewIO(t),
+		vaultFile:       filepath.Join(dir, "vault"),
+		vaultPassphrase: "correct horse battery staple",
+	}
+	_, err = factory.CreateVault("local")
+	assert.OK(t, err)
+	_, err = factory.CreateVault("local")
+	assert.Equal(t, err, ErrVaultExists(factory.vaultFile))
+
+	vaultClient, err := factory.NewVaultClient()
+	assert.OK(t, err)
+	_, err = vaultClient.Repos().Create("local/repo")
+	assert.OK(t, err)
+	_, err = vaultClient.Dirs().Create("local/repo/dir")
+	assert.OK(t, err)
+	_, err = vaultClient.Secrets().Write("local/repo/dir/secret", []byte("first"))
+	assert.OK(t, err)
+	_, err = vaultClient.Secrets().Write("local/repo/dir/secret", []byte("second"))
+	assert.OK(t, err)
+
+	type write struct {
+		path string
+		data string
+	}
+
+	cases := map[string]struct {
+		repoExists      bool
+		expectedCreated []string
+	}{
+		"new repository": {
+			expectedCreated: []string{"dev/repo", "dev/repo/dir"},
+		},
+		"existing repository": {
+			repoExists:      true,
+			expectedCreated: []string{"dev/repo/dir"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var created []string
+			var written []write
+			cmd := VaultPushCommand{
+				io:             fakeui.NewIO(t),
+				namespace:      "dev",
+				onConflict:     conflictSkip,
+				newVaultClient: factory.NewVaultClient,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						RepoService: &fakeclient.RepoService{
+							GetFunc: func(path string) (*api.Repo, error) {
+								if tc.repoExists {
+									return &api.Repo{}, nil
+								}
+								return nil, api.ErrRepoNotFound(path)
+							},
+							CreateFunc: func(path string) (*api.Repo, error) {
+								created = append(created, path)
+								return &api.Repo{}, nil
+							},
+						},
+						DirService: &fakeclient.DirService{
+							ExistsFunc: func(path string) (bool, error) {
+								return false, nil
+							},
+							CreateFunc: func(path string) (*api.Dir, error) {
+								created = append(created, path)
+								return &api.Dir{}, nil
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+								written = append(written, write{path: path, data: string(data)})
+								return &api.SecretVersion{}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.OK(t, err)
+			assert.Equal(t, created, tc.expectedCreated)
+			assert.Equal(t, written, []write{
+				{path: "dev/repo/dir/secret", data: "first"},
+				{path: "dev/repo/dir/secret", data: "second"},
+			})
+		})
+	}
+}
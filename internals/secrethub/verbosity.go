@@ -0,0 +1,203 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/progress"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	// quietOutput is set with the --quiet flag to only output the requested data.
+	quietOutput bool
+	// verbosity is the number of times the --verbose flag is given.
+	verbosity int
+	// noProgress is set with the --no-progress flag to not report the progress of bulk operations.
+	noProgress bool
+)
+
+// The verbosity levels of the --verbose flag.
+const (
+	// verbosityAPICalls logs the method, URL, status and duration of every API call.
+	verbosityAPICalls = 1
+	// verbosityAPIBodies also logs the bodies of the API calls, with the secret values redacted.
+	verbosityAPIBodies = 2
+)
+
+// quietFlag configures the global behaviour to only output the requested data.
+type quietFlag bool
+
+// init suppresses the messages that report progress and success based on the value of the flag.
+func (f quietFlag) init() {
+	quietOutput = bool(f)
+}
+
+// RegisterVerbosityFlags registers the quiet, verbose and no-progress flags that configure the amount of output.
+func RegisterVerbosityFlags(r FlagRegisterer) {
+	flag := quietFlag(false)
+	r.Flag("quiet", "Only output the requested data, without messages that report progress or success. List commands only print the paths, names or IDs.").Short('q').SetValue(&flag)
+	r.Flag("verbose", "Log every API call to stderr. Repeat the flag to also log the request and response bodies, with the secret values redacted.").CounterVar(&verbosity)
+	r.Flag("no-progress", "Do not report the progress of operations on many secrets, such as imports and exports, to stderr.").BoolVar(&noProgress)
+}
+
+// newProgressCounter returns a counter that reports the progress of the described operation on
+// total items to stderr. Nothing is reported for a single item or when --no-progress is set.
+// A progress bar is only drawn when stdout is not written to the same terminal, as the lines
+// that are written to stdout would otherwise end up on the line of the bar.
+func newProgressCounter(description string, total int) progress.Counter {
+	if noProgress || total <= 1 {
+		return progress.NewDiscardCounter()
+	}
+	redraw := terminal.IsTerminal(int(os.Stderr.Fd())) && !terminal.IsTerminal(int(os.Stdout.Fd()))
+	return progress.NewCounter(os.Stderr, description, total, redraw)
+}
+
+// String implements the flag.Value interface.
+func (f quietFlag) String() string {
+	return strconv.FormatBool(bool(f))
+}
+
+// Set suppresses messages when the given value is true.
+func (f *quietFlag) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*f = quietFlag(b)
+	f.init()
+	return nil
+}
+
+// IsBoolFlag makes the flag a boolean flag when used in a Kingpin application.
+// Thus, the flag can be used without argument (--quiet or -q).
+func (f quietFlag) IsBoolFlag() bool {
+	return true
+}
+
+// statusWriter returns the writer to write messages that report progress or success to.
+// These messages are discarded when the --quiet flag is set.
+func statusWriter(w io.Writer) io.Writer {
+	if quietOutput {
+		return ioutil.Discard
+	}
+	return w
+}
+
+// redactedFields are the parts of the names of the fields in API requests and responses
+// that hold secret values, encrypted data or key material.
+var redactedFields = []string{"encrypted", "key", "secret", "signature", "password", "token", "data", "verifier"}
+
+// redactedValue replaces the values of redacted fields.
+const redactedValue = "[REDACTED]"
+
+// loggingTransport is a http.RoundTripper that logs the API calls it performs.
+type loggingTransport struct {
+	next   http.RoundTripper
+	w      io.Writer
+	bodies bool
+}
+
+// newLoggingTransport returns a transport that logs the API calls to w before passing them on to next.
+func newLoggingTransport(next http.RoundTripper, w io.Writer, bodies bool) *loggingTransport {
+	return &loggingTransport{
+		next:   next,
+		w:      w,
+		bodies: bodies,
+	}
+}
+
+// RoundTrip logs the request and its response.
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.bodies && req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		fmt.Fprintf(t.w, "> %s %s\n%s\n", req.Method, req.URL, redactBody(body))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(t.w, "%s %s failed after %s: %s\n", req.Method, req.URL, duration, err)
+		return nil, err
+	}
+	fmt.Fprintf(t.w, "%s %s %d (%s)\n", req.Method, req.URL, resp.StatusCode, duration)
+
+	if t.bodies && resp.Body != nil {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		fmt.Fprintf(t.w, "< %s\n", redactBody(body))
+	}
+	return resp, nil
+}
+
+// redactBody returns the body with the values of the fields that can hold secrets redacted.
+// Bodies that are not JSON are redacted completely.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var decoded interface{}
+	err := json.Unmarshal(body, &decoded)
+	if err != nil {
+		return redactedValue
+	}
+
+	redacted, err := json.Marshal(redactJSON(decoded))
+	if err != nil {
+		return redactedValue
+	}
+	return string(redacted)
+}
+
+// redactJSON replaces the values of the fields that can hold secrets in the decoded JSON value.
+func redactJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for name, field := range value {
+			if isRedactedField(name) {
+				value[name] = redactedValue
+			} else {
+				value[name] = redactJSON(field)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactJSON(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// isRedactedField returns whether the field with the given name can hold a secret.
+func isRedactedField(name string) bool {
+	name = strings.ToLower(name)
+	for _, redacted := range redactedFields {
+		if strings.Contains(name, redacted) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package secrethub
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRedactBody(t *testing.T) {
+	cases := map[string]struct {
+		body     string
+		expected string
+	}{
+		"empty": {
+			body:     "",
+			expected: "",
+		},
+		"not json": {
+			body:     "plaintext",
+			expected: redactedValue,
+		},
+		"nested": {
+			body:     `{"name":"password","version":{"encrypted_data":{"key":"abc"},"status":"ok"}}`,
+			expected: `{"name":"password","version":{"encrypted_data":"[REDACTED]","status":"ok"}}`,
+		},
+		"list": {
+			body:     `[{"SecretKey":"abc","path":"company/repo"}]`,
+			expected: `[{"SecretKey":"[REDACTED]","path":"company/repo"}]`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := redactBody([]byte(tc.body))
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLoggingTransport(t *testing.T) {
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		assert.Equal(t, string(body), `{"encrypted_value":"abc"}`)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"token":"def"}`)),
+		}, nil
+	})
+
+	var buf bytes.Buffer
+	transport := newLoggingTransport(next, &buf, true)
+
+	req, err := http.NewRequest("POST", "https://api.secrethub.io/secrets", strings.NewReader(`{"encrypted_value":"abc"}`))
+	assert.OK(t, err)
+
+	resp, err := transport.RoundTrip(req)
+	assert.OK(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.OK(t, err)
+	assert.Equal(t, string(body), `{"token":"def"}`)
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, lines[0], "> POST https://api.secrethub.io/secrets")
+	assert.Equal(t, lines[1], `{"encrypted_value":"[REDACTED]"}`)
+	assert.Equal(t, strings.HasPrefix(lines[2], "POST https://api.secrethub.io/secrets 200 ("), true)
+	assert.Equal(t, lines[3], `< {"token":"[REDACTED]"}`)
+}
+
+func TestStatusWriter(t *testing.T) {
+	defer func() {
+		quietOutput = false
+	}()
+
+	var buf bytes.Buffer
+	quietOutput = true
+	_, err := statusWriter(&buf).Write([]byte("Write complete!"))
+	assert.OK(t, err)
+	assert.Equal(t, buf.String(), "")
+
+	quietOutput = false
+	_, err = statusWriter(&buf).Write([]byte("Write complete!"))
+	assert.OK(t, err)
+	assert.Equal(t, buf.String(), "Write complete!")
+}
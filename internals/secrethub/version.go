@@ -4,4 +4,7 @@ package secrethub
 var (
 	Version string
 	Commit  string
+	// ReleasePublicKey is the base64 encoded Ed25519 public key with which the checksums of releases are
+	// signed. Builds without it cannot update themselves.
+	ReleasePublicKey string
 )
@@ -0,0 +1,198 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+var errWebhook = errio.Namespace("webhook")
+
+// Errors
+var (
+	ErrInvalidWebhookURL = errWebhook.Code("invalid_url").ErrorPref("invalid webhook URL %q: must be an http or https URL")
+	ErrWebhookNotFound   = errWebhook.Code("not_found").ErrorPref("the repository %s has no webhook with ID %s")
+	ErrInvalidWebhooks   = errWebhook.Code("invalid_webhooks").ErrorPref("the webhooks of the repository %s are invalid: %s")
+	ErrWebhookDelivery   = errWebhook.Code("delivery_failed").ErrorPref("could not deliver the event to %s: %s")
+	ErrNoWebhookEvents   = errWebhook.Code("no_events").Error("at least one event is required: use --events with read, write, delete or acl")
+)
+
+// repoWebhooksSecretName is the name of the secret in the root directory of a repository that contains
+// its webhooks. The SecretHub API does not deliver webhooks itself, so they are delivered by `webhook serve`.
+const repoWebhooksSecretName = ".webhooks"
+
+// The headers of the requests a webhook receives.
+const (
+	webhookEventHeader     = "X-SecretHub-Event"
+	webhookSignatureHeader = "X-SecretHub-Signature"
+)
+
+// webhook is an endpoint that is notified of the audit events of a repository.
+type webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	// SigningKey is used to sign the requests to the webhook with HMAC-SHA256,
+	// so the receiver can verify that they are sent by someone with access to the repository.
+	SigningKey string `json:"signing_key"`
+}
+
+// matches returns whether the webhook is notified of the event.
+func (w webhook) matches(record auditRecord) bool {
+	return containsString(w.Events, record.Category)
+}
+
+// WebhookCommand handles the webhooks of repositories.
+type WebhookCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewWebhookCommand creates a new WebhookCommand.
+func NewWebhookCommand(io ui.IO, newClient newClientFunc) *WebhookCommand {
+	return &WebhookCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *WebhookCommand) Register(r command.Registerer) {
+	clause := r.Command("webhook", "Notify external systems of changes to the secrets in a repository. "+
+		"The webhooks of a repository are stored in the secret "+repoWebhooksSecretName+" in its root and delivered by `webhook serve`, "+
+		"which polls the audit log of the repository.")
+	NewWebhookCreateCommand(cmd.io, cmd.newClient).Register(clause)
+	NewWebhookListCommand(cmd.io, cmd.newClient).Register(clause)
+	NewWebhookRmCommand(cmd.io, cmd.newClient).Register(clause)
+	NewWebhookServeCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// repoWebhooksPath returns the path of the secret that contains the webhooks of the repository.
+func repoWebhooksPath(repoPath api.RepoPath) string {
+	return repoPath.GetDirPath().JoinSecret(repoWebhooksSecretName).Value()
+}
+
+// getRepoWebhooks returns the webhooks of the repository, which are empty when it has none.
+func getRepoWebhooks(client secrethub.ClientInterface, repoPath api.RepoPath) ([]webhook, error) {
+	secret, err := client.Secrets().Versions().GetWithData(repoWebhooksPath(repoPath))
+	if api.IsErrNotFound(err) {
+		return []webhook{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var webhooks []webhook
+	err = json.Unmarshal(secret.Data, &webhooks)
+	if err != nil {
+		return nil, ErrInvalidWebhooks(repoPath, err)
+	}
+	return webhooks, nil
+}
+
+// writeRepoWebhooks replaces the webhooks of the repository.
+func writeRepoWebhooks(client secrethub.ClientInterface, repoPath api.RepoPath, webhooks []webhook) error {
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		return err
+	}
+	_, err = client.Secrets().Write(repoWebhooksPath(repoPath), data)
+	return err
+}
+
+// validateWebhookURL returns an error when the URL cannot be delivered to.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL(raw)
+	}
+	return nil
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// signWebhookPayload returns the value of the signature header of the payload.
+func signWebhookPayload(key string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverWebhook posts the record as JSON to the webhook.
+func deliverWebhook(client *http.Client, hook webhook, record auditRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return ErrWebhookDelivery(hook.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, record.Category)
+	req.Header.Set(webhookSignatureHeader, signWebhookPayload(hook.SigningKey, payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ErrWebhookDelivery(hook.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return ErrWebhookDelivery(hook.URL, resp.Status)
+	}
+	return nil
+}
+
+// webhookEventsValue is a flag value for a comma separated list of event categories.
+type webhookEventsValue struct {
+	events *[]string
+}
+
+// Set adds the given event categories after validating them.
+func (v *webhookEventsValue) Set(value string) error {
+	actions := auditActionsValue{actions: v.events}
+	for _, event := range strings.Split(value, ",") {
+		err := actions.Set(strings.TrimSpace(event))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String implements the flag.Value interface.
+func (v *webhookEventsValue) String() string {
+	if v.events == nil {
+		return ""
+	}
+	return strings.Join(*v.events, ",")
+}
+
+// IsCumulative makes the flag repeatable when used in a Kingpin application.
+func (v *webhookEventsValue) IsCumulative() bool {
+	return true
+}
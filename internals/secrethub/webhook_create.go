@@ -0,0 +1,95 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// WebhookCreateCommand adds a webhook to a repository.
+type WebhookCreateCommand struct {
+	io        ui.IO
+	path      api.RepoPath
+	url       string
+	events    []string
+	newClient newClientFunc
+	newID     func() (string, error)
+	now       func() time.Time
+}
+
+// NewWebhookCreateCommand creates a new WebhookCreateCommand.
+func NewWebhookCreateCommand(io ui.IO, newClient newClientFunc) *WebhookCreateCommand {
+	return &WebhookCreateCommand{
+		io:        io,
+		newClient: newClient,
+		newID: func() (string, error) {
+			return randomHex(4)
+		},
+		now: time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *WebhookCreateCommand) Register(r command.Registerer) {
+	clause := r.Command("create", "Add a webhook to a repository. Events are posted to the URL as the JSON records of `audit export`, "+
+		"with the category of the event in the "+webhookEventHeader+" header and an HMAC-SHA256 signature of the body in the "+webhookSignatureHeader+" header. "+
+		"The key to verify the signature with is printed once.")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("url", "The http or https URL to post the events to.").Required().StringVar(&cmd.url)
+	clause.Flag("events", "The events to notify the webhook of, as a comma separated list of read, write, delete and acl (membership changes).").
+		Required().HintOptions(auditActionRead, auditActionWrite, auditActionDelete, auditActionACL).SetValue(&webhookEventsValue{events: &cmd.events})
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run adds the webhook.
+func (cmd *WebhookCreateCommand) Run() error {
+	err := validateWebhookURL(cmd.url)
+	if err != nil {
+		return err
+	}
+	if len(cmd.events) == 0 {
+		return ErrNoWebhookEvents
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := getRepoWebhooks(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	id, err := cmd.newID()
+	if err != nil {
+		return err
+	}
+	signingKey, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+
+	webhooks = append(webhooks, webhook{
+		ID:         id,
+		URL:        cmd.url,
+		Events:     cmd.events,
+		CreatedAt:  cmd.now().UTC(),
+		SigningKey: signingKey,
+	})
+	err = writeRepoWebhooks(client, cmd.path, webhooks)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Created the webhook %s for %s events of %s.\n", id, strings.Join(cmd.events, ", "), cmd.path)
+	fmt.Fprintf(cmd.io.Output(), "Signing key: %s\n", signingKey)
+	fmt.Fprintf(cmd.io.Output(), "Events are delivered while `secrethub webhook serve %s` is running.\n", cmd.path)
+	return nil
+}
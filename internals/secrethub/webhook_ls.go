@@ -0,0 +1,67 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// WebhookListCommand lists the webhooks of a repository.
+type WebhookListCommand struct {
+	io            ui.IO
+	path          api.RepoPath
+	quiet         bool
+	useTimestamps bool
+	newClient     newClientFunc
+}
+
+// NewWebhookListCommand creates a new WebhookListCommand.
+func NewWebhookListCommand(io ui.IO, newClient newClientFunc) *WebhookListCommand {
+	return &WebhookListCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *WebhookListCommand) Register(r command.Registerer) {
+	clause := r.Command("ls", "List the webhooks of a repository.")
+	clause.Alias("list")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run lists the webhooks.
+func (cmd *WebhookListCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := getRepoWebhooks(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	if cmd.quiet || quietOutput {
+		for _, hook := range webhooks {
+			fmt.Fprintln(cmd.io.Output(), hook.ID)
+		}
+		return nil
+	}
+
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "ID", "URL", "EVENTS", "CREATED")
+	for _, hook := range webhooks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", hook.ID, hook.URL, strings.Join(hook.Events, ","), timeFormatter.Format(hook.CreatedAt.Local()))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,67 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// WebhookRmCommand removes a webhook from a repository.
+type WebhookRmCommand struct {
+	io        ui.IO
+	path      api.RepoPath
+	id        string
+	newClient newClientFunc
+}
+
+// NewWebhookRmCommand creates a new WebhookRmCommand.
+func NewWebhookRmCommand(io ui.IO, newClient newClientFunc) *WebhookRmCommand {
+	return &WebhookRmCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *WebhookRmCommand) Register(r command.Registerer) {
+	clause := r.Command("rm", "Remove a webhook from a repository.")
+	clause.Alias("remove")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Arg("id", "The ID of the webhook, as shown by `webhook ls`.").Required().StringVar(&cmd.id)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run removes the webhook.
+func (cmd *WebhookRmCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := getRepoWebhooks(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]webhook, 0, len(webhooks))
+	for _, hook := range webhooks {
+		if hook.ID != cmd.id {
+			remaining = append(remaining, hook)
+		}
+	}
+	if len(remaining) == len(webhooks) {
+		return ErrWebhookNotFound(cmd.path, cmd.id)
+	}
+
+	err = writeRepoWebhooks(client, cmd.path, remaining)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Removed the webhook %s from %s.\n", cmd.id, cmd.path)
+	return nil
+}
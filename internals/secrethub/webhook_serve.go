@@ -0,0 +1,129 @@
+package secrethub
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// WebhookServeCommand delivers the audit events of a repository to its webhooks.
+type WebhookServeCommand struct {
+	io         ui.IO
+	path       api.RepoPath
+	interval   time.Duration
+	cursorFile string
+	newClient  newClientFunc
+	httpClient *http.Client
+	sleep      func(time.Duration)
+}
+
+// NewWebhookServeCommand creates a new WebhookServeCommand.
+func NewWebhookServeCommand(io ui.IO, newClient newClientFunc) *WebhookServeCommand {
+	return &WebhookServeCommand{
+		io:         io,
+		newClient:  newClient,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		sleep:      time.Sleep,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *WebhookServeCommand) Register(r command.Registerer) {
+	clause := r.Command("serve", "Keep running and deliver new audit events of a repository to its webhooks. "+
+		"Changes to the webhooks are picked up while running. An event that cannot be delivered to a webhook is reported and not retried.")
+	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Flag("interval", "The interval at which to poll for new events.").Default("60s").DurationVar(&cmd.interval)
+	clause.Flag("cursor-file", "Path to a file in which the position of the newest delivered event is stored. When set, delivery resumes after the events that have been delivered before. Otherwise, only events logged after the command started are delivered.").StringVar(&cmd.cursorFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run polls for new audit events and delivers them, until the process is interrupted.
+func (cmd *WebhookServeCommand) Run() error {
+	if cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	var pos auditPosition
+	var err error
+	if cmd.cursorFile != "" {
+		pos, err = readAuditCursor(cmd.cursorFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pos.at.IsZero() {
+		iter, _, err := auditRecordSource(api.Path(cmd.path.Value()), cmd.newClient)
+		if err != nil {
+			return err
+		}
+
+		pos, err = newestAuditPosition(iter)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Delivering events of %s to its webhooks every %s.\n", cmd.path, cmd.interval)
+
+	for {
+		err = cmd.deliver(&pos)
+		if err != nil {
+			return err
+		}
+
+		cmd.sleep(cmd.interval)
+	}
+}
+
+// deliver sends the events that have not been delivered yet to the webhooks they match and advances the position past them.
+func (cmd *WebhookServeCommand) deliver(pos *auditPosition) error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := getRepoWebhooks(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	iter, newRecord, err := auditRecordSource(api.Path(cmd.path.Value()), cmd.newClient)
+	if err != nil {
+		return err
+	}
+
+	events, err := eventsAfter(iter, *pos)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		record, err := newRecord(event)
+		if err != nil {
+			return err
+		}
+
+		for _, hook := range webhooks {
+			if !hook.matches(record) {
+				continue
+			}
+			err = deliverWebhook(cmd.httpClient, hook, record)
+			if err != nil {
+				fmt.Fprintf(cmd.io.Output(), "%s %s\n", colorize(colorRoleWarning, "Warning:"), err)
+			}
+		}
+		pos.advance(event)
+	}
+
+	if cmd.cursorFile != "" && len(events) > 0 {
+		return writeAuditCursor(cmd.cursorFile, *pos)
+	}
+	return nil
+}
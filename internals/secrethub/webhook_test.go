@@ -0,0 +1,196 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// webhookTestClient returns a fake client with the given webhooks in the repository namespace/repo,
+// which stores the webhooks that are written in written.
+func webhookTestClient(existing string, written *[]webhook) secrethub.ClientInterface {
+	return fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					if existing == "" {
+						return nil, api.ErrSecretNotFound
+					}
+					return &api.SecretVersion{Data: []byte(existing)}, nil
+				},
+			},
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				if path != "namespace/repo/.webhooks" {
+					return nil, api.ErrSecretNotFound
+				}
+				return &api.SecretVersion{Version: 1}, json.Unmarshal(data, written)
+			},
+		},
+	}
+}
+
+func TestWebhookCreateCommand_Run(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	existing := `[{"id":"00000001","url":"https://example.com/old","events":["delete"],"created_at":"2019-01-01T00:00:00Z","signing_key":"key"}]`
+
+	cases := map[string]struct {
+		url      string
+		events   []string
+		existing string
+		expected []webhook
+		err      error
+	}{
+		"first webhook": {
+			url:    "https://example.com/hook",
+			events: []string{"write", "delete"},
+			expected: []webhook{
+				{ID: "0badcafe", URL: "https://example.com/hook", Events: []string{"write", "delete"}, CreatedAt: now},
+			},
+		},
+		"added to existing": {
+			url:      "http://localhost:8080",
+			events:   []string{"acl"},
+			existing: existing,
+			expected: []webhook{
+				{ID: "00000001", URL: "https://example.com/old", Events: []string{"delete"}, CreatedAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), SigningKey: "key"},
+				{ID: "0badcafe", URL: "http://localhost:8080", Events: []string{"acl"}, CreatedAt: now},
+			},
+		},
+		"invalid url": {
+			url:    "ftp://example.com",
+			events: []string{"write"},
+			err:    ErrInvalidWebhookURL("ftp://example.com"),
+		},
+		"no events": {
+			url: "https://example.com/hook",
+			err: ErrNoWebhookEvents,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written []webhook
+			cmd := WebhookCreateCommand{
+				io:     fakeui.NewIO(t),
+				path:   "namespace/repo",
+				url:    tc.url,
+				events: tc.events,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return webhookTestClient(tc.existing, &written), nil
+				},
+				newID: func() (string, error) {
+					return "0badcafe", nil
+				},
+				now: func() time.Time { return now },
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				// The signing key is random, so it is only checked to be set.
+				last := &written[len(written)-1]
+				assert.Equal(t, len(last.SigningKey), 64)
+				last.SigningKey = ""
+				assert.Equal(t, written, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWebhookRmCommand_Run(t *testing.T) {
+	existing := `[{"id":"00000001","url":"https://example.com/a","events":["write"]},{"id":"00000002","url":"https://example.com/b","events":["read"]}]`
+
+	cases := map[string]struct {
+		id       string
+		expected []webhook
+		err      error
+	}{
+		"remove": {
+			id: "00000001",
+			expected: []webhook{
+				{ID: "00000002", URL: "https://example.com/b", Events: []string{"read"}},
+			},
+		},
+		"not found": {
+			id:  "00000003",
+			err: ErrWebhookNotFound("namespace/repo", "00000003"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written []webhook
+			cmd := WebhookRmCommand{
+				io:   fakeui.NewIO(t),
+				path: "namespace/repo",
+				id:   tc.id,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return webhookTestClient(existing, &written), nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, written, tc.expected)
+		})
+	}
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	record := auditRecord{
+		EventID:  "e1",
+		Action:   "create.secret_version",
+		Category: auditActionWrite,
+		Subject:  "namespace/repo/secret:2",
+	}
+
+	cases := map[string]struct {
+		status int
+		err    bool
+	}{
+		"delivered": {
+			status: http.StatusNoContent,
+		},
+		"rejected": {
+			status: http.StatusInternalServerError,
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var body []byte
+			var header http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = ioutil.ReadAll(r.Body)
+				header = r.Header
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			hook := webhook{URL: server.URL, SigningKey: "key"}
+
+			err := deliverWebhook(server.Client(), hook, record)
+
+			assert.Equal(t, err != nil, tc.err)
+			assert.Equal(t, header.Get(webhookEventHeader), "write")
+			assert.Equal(t, header.Get(webhookSignatureHeader), signWebhookPayload("key", body))
+
+			var received auditRecord
+			assert.OK(t, json.Unmarshal(body, &received))
+			assert.Equal(t, received, record)
+		})
+	}
+}
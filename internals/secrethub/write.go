@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/clip"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
@@ -17,6 +18,7 @@ var (
 	errEmptySecret                     = errMain.Code("cannot_write_empty_secret").Error("secret is empty or contains only whitespace")
 	errClipAndInFile                   = errMain.Code("clip_and_in_file").Error("clip and in-file cannot be used together")
 	errMultilineWithNonInteractiveFlag = errMain.Code("multiline_flag_conflict").Error("multiline cannot be used together with clip or in-file")
+	errCannotClearClipboard            = errMain.Code("cannot_clear_clipboard").ErrorPref("the secret has been written, but the clipboard could not be cleared: %s")
 )
 
 // WriteCommand is a command to write content to a secret.
@@ -26,17 +28,28 @@ type WriteCommand struct {
 	inFile       string
 	multiline    bool
 	useClipboard bool
+	clearClip    bool
 	noTrim       bool
+	enforce      bool
+	breached     bool
+	checkBreach  breachChecker
 	clipper      clip.Clipper
 	newClient    newClientFunc
+	ephemeral    bool
+	deleteAfter  time.Duration
+	secrets      func() ephemeralSecretStore
 }
 
 // NewWriteCommand creates a new WriteCommand.
-func NewWriteCommand(io ui.IO, newClient newClientFunc) *WriteCommand {
+func NewWriteCommand(io ui.IO, newClient newClientFunc, credentialStore CredentialConfig) *WriteCommand {
 	return &WriteCommand{
-		clipper:   clip.NewClipboard(),
-		io:        io,
-		newClient: newClient,
+		clipper:     clip.NewClipboard(),
+		io:          io,
+		newClient:   newClient,
+		checkBreach: defaultBreachChecker(),
+		secrets: func() ephemeralSecretStore {
+			return newEphemeralSecretStore(credentialStore.ConfigDir())
+		},
 	}
 }
 
@@ -45,9 +58,16 @@ func (cmd *WriteCommand) Register(r command.Registerer) {
 	clause := r.Command("write", "Write a secret.")
 	clause.Arg("secret-path", "The path to the secret").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.path)
 	clause.Flag("clip", "Use clipboard content as input.").Short('c').BoolVar(&cmd.useClipboard)
+	clause.Flag("from-clipboard", "Use clipboard content as input and clear the clipboard after the secret is written. "+
+		"The clipboard is only cleared when it still contains the value of the secret.").BoolVar(&cmd.clearClip)
 	clause.Flag("multiline", "Prompt for multiple lines of input, until an EOF is reached. On Linux/Mac, press CTRL-D to end input. On Windows, press CTRL-Z and then ENTER to end input.").Short('m').BoolVar(&cmd.multiline)
 	clause.Flag("no-trim", "Do not trim leading and trailing whitespace in the secret.").BoolVar(&cmd.noTrim)
 	clause.Flag("in-file", "Use the contents of this file as the value of the secret.").Short('i').StringVar(&cmd.inFile)
+	clause.Flag("enforce-strength", "Refuse to write the secret when it is a weak or breached password, instead of only warning about it.").BoolVar(&cmd.enforce)
+	clause.Flag("check-breached", "Check whether the secret has appeared in a data breach with the Have I Been Pwned API. "+
+		"Only the first 5 characters of the SHA-1 hash of the secret are sent.").BoolVar(&cmd.breached)
+	clause.Flag("delete-after", "Make the secret ephemeral, e.g. to hand over temporary credentials. "+
+		"The written version is tracked locally and removed by `secrethub gc` once the given duration (e.g. 24h or 7d) has passed.").IsSetByUser(&cmd.ephemeral).SetValue((*durationValue)(&cmd.deleteAfter))
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -55,6 +75,7 @@ func (cmd *WriteCommand) Register(r command.Registerer) {
 // Run handles the command with the options as specified in the command.
 func (cmd *WriteCommand) Run() error {
 	var err error
+	cmd.useClipboard = cmd.useClipboard || cmd.clearClip
 
 	// This error is checked here to fail fast.
 	// The error is also checked in the client.
@@ -71,12 +92,29 @@ func (cmd *WriteCommand) Run() error {
 		return errClipAndInFile
 	}
 
+	if cmd.ephemeral && cmd.deleteAfter <= 0 {
+		return ErrInvalidExpiry
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	// The policy is checked before reading the value, so that the user is not prompted in vain.
+	err = checkSecretPolicy(newPolicyStore(client), cmd.path)
+	if err != nil {
+		return err
+	}
+
 	var data []byte
+	var clipped []byte
 	if cmd.useClipboard {
 		data, err = cmd.clipper.ReadAll()
 		if err != nil {
 			return err
 		}
+		clipped = data
 	} else if cmd.inFile != "" {
 		data, err = ioutil.ReadFile(cmd.inFile)
 		if err != nil {
@@ -110,12 +148,19 @@ func (cmd *WriteCommand) Run() error {
 		return errEmptySecret
 	}
 
-	_, err = fmt.Fprint(cmd.io.Output(), "Writing secret value...\n")
+	err = checkStrength(statusWriter(cmd.io.Output()), secretWeakness(data), cmd.enforce)
 	if err != nil {
 		return err
 	}
 
-	client, err := cmd.newClient()
+	if cmd.breached {
+		err = checkBreached(statusWriter(cmd.io.Output()), cmd.checkBreach, data, cmd.enforce)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprint(statusWriter(cmd.io.Output()), "Writing secret value...\n")
 	if err != nil {
 		return err
 	}
@@ -125,10 +170,57 @@ func (cmd *WriteCommand) Run() error {
 		return err
 	}
 
-	_, err = fmt.Fprintf(cmd.io.Output(), "Write complete! The given value has been written to %s:%d\n", cmd.path, version.Version)
+	_, err = fmt.Fprintf(statusWriter(cmd.io.Output()), "Write complete! The given value has been written to %s:%d\n", cmd.path, version.Version)
 	if err != nil {
 		return err
 	}
 
+	if cmd.ephemeral {
+		writtenAt := version.CreatedAt
+		if writtenAt.IsZero() {
+			writtenAt = time.Now().UTC()
+		}
+		expiresAt := writtenAt.Add(cmd.deleteAfter)
+
+		err = cmd.secrets().Add(ephemeralSecret{
+			Path:      cmd.path.Value(),
+			Version:   version.Version,
+			WrittenAt: writtenAt,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(statusWriter(cmd.io.Output()), "The secret expires at %s. Run `secrethub gc` to remove expired secrets.\n", expiresAt.Local().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.clearClip {
+		return cmd.clearClipboard(clipped)
+	}
+
 	return nil
 }
+
+// clearClipboard clears the clipboard when it still contains the value that was written,
+// so that anything copied in the meantime is not removed.
+func (cmd *WriteCommand) clearClipboard(written []byte) error {
+	current, err := cmd.clipper.ReadAll()
+	if err != nil {
+		return errCannotClearClipboard(err)
+	}
+	if !bytes.Equal(current, written) {
+		return nil
+	}
+
+	err = cmd.clipper.WriteAll(nil)
+	if err != nil {
+		return errCannotClearClipboard(err)
+	}
+
+	_, err = fmt.Fprintln(statusWriter(cmd.io.Output()), "The clipboard has been cleared.")
+	return err
+}
@@ -16,11 +16,18 @@ import (
 	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
 )
 
+// The warnings that are written for the weak values the tests write.
+const (
+	weakSecretWarning       = "Warning: the secret is weak: it contains a common password or word and could be guessed in 10.0 minutes. Use --enforce-strength to refuse weak secrets.\n"
+	weakSpacedSecretWarning = "Warning: the secret is weak: it contains a common password or word and could be guessed in 10.0 hours. Use --enforce-strength to refuse weak secrets.\n"
+)
+
 func TestWriteCommand_Run(t *testing.T) {
 	testErr := errio.Namespace("test").Code("test").Error("test error")
 
 	cases := map[string]struct {
 		cmd         WriteCommand
+		policy      string
 		writeFunc   func(path string, data []byte) (*api.SecretVersion, error)
 		in          string
 		piped       bool
@@ -63,7 +70,7 @@ func TestWriteCommand_Run(t *testing.T) {
 			err:  nil,
 			path: "namespace/repo/secret",
 			data: []byte("secret value"),
-			out:  "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\n",
+			out:  weakSecretWarning + "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\n",
 		},
 		"client error": {
 			cmd: WriteCommand{
@@ -77,7 +84,16 @@ func TestWriteCommand_Run(t *testing.T) {
 			err:  secrethub.ErrEmptySecret,
 			path: "namespace/repo/secret",
 			data: []byte("secret value"),
-			out:  "Writing secret value...\n",
+			out:  weakSecretWarning + "Writing secret value...\n",
+		},
+		"enforce strength": {
+			cmd: WriteCommand{
+				path:    "namespace/repo/secret",
+				enforce: true,
+			},
+			in:    "secret value",
+			piped: true,
+			err:   ErrWeakSecret("it contains a common password or word and could be guessed in 10.0 minutes"),
 		},
 		"write space no-trim": {
 			cmd: WriteCommand{
@@ -103,7 +119,7 @@ func TestWriteCommand_Run(t *testing.T) {
 			err:  nil,
 			path: "namespace/repo/secret",
 			data: []byte("secret value"),
-			out:  "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\n",
+			out:  weakSecretWarning + "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\n",
 		},
 		"write secret prefixed with a space, no-trim": {
 			cmd: WriteCommand{
@@ -120,7 +136,7 @@ func TestWriteCommand_Run(t *testing.T) {
 			err:  nil,
 			path: "namespace/repo/secret",
 			data: []byte(" secret value"),
-			out:  "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\n",
+			out:  weakSpacedSecretWarning + "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\n",
 		},
 		"ask secret success": {
 			cmd: WriteCommand{
@@ -177,6 +193,22 @@ func TestWriteCommand_Run(t *testing.T) {
 			data: []byte("clipped secret value"),
 			out:  "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\n",
 		},
+		"from clipboard with clear": {
+			cmd: WriteCommand{
+				path:      "namespace/repo/secret",
+				clearClip: true,
+				clipper:   fakeclip.NewWithValue([]byte("clipped secret value\n")),
+			},
+			writeFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				return &api.SecretVersion{
+					Version: 1,
+				}, nil
+			},
+			err:  nil,
+			path: "namespace/repo/secret",
+			data: []byte("clipped secret value"),
+			out:  "Writing secret value...\nWrite complete! The given value has been written to namespace/repo/secret:1\nThe clipboard has been cleared.\n",
+		},
 		"from clipboard error": {
 			cmd: WriteCommand{
 				path:         "namespace/repo/secret",
@@ -185,6 +217,13 @@ func TestWriteCommand_Run(t *testing.T) {
 			},
 			err: clip.ErrCannotRead("read error"),
 		},
+		"policy violation": {
+			cmd: WriteCommand{
+				path: "namespace/repo/secret",
+			},
+			policy: "org: namespace\nenvironments: [dev, prod]\n",
+			err:    ErrPolicyViolation("namespace/repo/secret", "namespace", "secrets must be stored in one of the environment directories (dev, prod)"),
+		},
 		"clip and in-file": {
 			cmd: WriteCommand{
 				inFile:       "file",
@@ -202,6 +241,7 @@ func TestWriteCommand_Run(t *testing.T) {
 			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
 				return fakeclient.Client{
 					SecretService: &fakeclient.SecretService{
+						VersionService: fakePolicyVersionService(tc.policy),
 						WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
 							argPath = path
 							argData = data
@@ -234,3 +274,40 @@ func TestWriteCommand_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteCommand_clearClipboard(t *testing.T) {
+	cases := map[string]struct {
+		clipboard []byte
+		expected  []byte
+		out       string
+	}{
+		"clipboard unchanged": {
+			clipboard: []byte("secret value"),
+			expected:  nil,
+			out:       "The clipboard has been cleared.\n",
+		},
+		"clipboard changed": {
+			clipboard: []byte("something else"),
+			expected:  []byte("something else"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			clipper := fakeclip.NewWithValue(tc.clipboard)
+			cmd := WriteCommand{
+				io:      io,
+				clipper: clipper,
+			}
+
+			err := cmd.clearClipboard([]byte("secret value"))
+			assert.OK(t, err)
+
+			actual, err := clipper.ReadAll()
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
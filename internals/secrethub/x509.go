@@ -0,0 +1,112 @@
+package secrethub
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrInvalidCertificate = errMain.Code("invalid_certificate").ErrorPref("%s does not contain a PEM encoded certificate: %s")
+	ErrInvalidPrivateKey  = errMain.Code("invalid_private_key").ErrorPref("the private key does not belong to the certificate: %s")
+)
+
+// The names of the secrets a certificate is stored in, in the directory of the certificate.
+const (
+	certificateSecretName = "cert"
+	privateKeySecretName  = "key"
+	chainSecretName       = "chain"
+)
+
+// X509Command handles storing TLS certificates in SecretHub and tracking when they expire.
+type X509Command struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewX509Command creates a new X509Command.
+func NewX509Command(io ui.IO, newClient newClientFunc) *X509Command {
+	return &X509Command{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *X509Command) Register(r command.Registerer) {
+	clause := r.Command("x509", "Store TLS certificates in SecretHub and keep track of when they expire. "+
+		"A certificate is stored in a directory with the secrets "+certificateSecretName+", "+privateKeySecretName+" and, optionally, "+chainSecretName+".")
+	NewX509StoreCommand(cmd.io, cmd.newClient).Register(clause)
+	NewX509InspectCommand(cmd.io, cmd.newClient).Register(clause)
+	NewX509ExpiringCommand(cmd.io, cmd.newClient).Register(clause)
+}
+
+// parseCertificates parses all PEM encoded certificates in data. The name is used in the error
+// that is returned when data does not contain any certificate.
+func parseCertificates(name string, data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, ErrInvalidCertificate(name, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, ErrInvalidCertificate(name, "no certificate found")
+	}
+	return certs, nil
+}
+
+// certificateNames returns the DNS names, IP addresses, email addresses and URIs the certificate is valid for.
+func certificateNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	return names
+}
+
+// daysLeft returns the number of whole days until the certificate expires.
+// The result is negative when the certificate has already expired.
+func daysLeft(cert *x509.Certificate, now time.Time) int {
+	return int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+}
+
+// formatDaysLeft returns the time until the certificate expires in days, colored when the
+// certificate has expired or expires within the given duration.
+func formatDaysLeft(cert *x509.Certificate, now time.Time, warnWithin time.Duration) interface{} {
+	days := daysLeft(cert, now)
+	switch {
+	case !now.Before(cert.NotAfter):
+		return colorize(colorRoleFlagged, fmt.Sprintf("expired %s ago", pluralize("day", "days", -days)))
+	case cert.NotAfter.Before(now.Add(warnWithin)):
+		return colorize(colorRoleWarning, pluralize("day", "days", days))
+	default:
+		return pluralize("day", "days", days)
+	}
+}
+
+// certificateSecretPath returns the path of the certificate secret in the given directory.
+func certificateSecretPath(dirPath string) string {
+	return strings.TrimSuffix(dirPath, "/") + "/" + certificateSecretName
+}
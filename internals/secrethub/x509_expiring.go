@@ -0,0 +1,114 @@
+package secrethub
+
+import (
+	"crypto/x509"
+	"fmt"
+	"path"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/docker/go-units"
+)
+
+// Errors
+var (
+	ErrCertificatesExpiring = errMain.Code("certificates_expiring").ErrorPref("%s expired or about to expire")
+)
+
+// X509ExpiringCommand reports the stored certificates that expire soon.
+type X509ExpiringCommand struct {
+	io        ui.IO
+	root      string
+	within    time.Duration
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewX509ExpiringCommand creates a new X509ExpiringCommand.
+func NewX509ExpiringCommand(io ui.IO, newClient newClientFunc) *X509ExpiringCommand {
+	return &X509ExpiringCommand{
+		io:        io,
+		within:    defaultExpiryWarning,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *X509ExpiringCommand) Register(r command.Registerer) {
+	clause := r.Command("expiring", "List the certificates that have expired or expire soon. All secrets named "+certificateSecretName+
+		" in the repositories you have access to are checked, or only those in the given namespace, repository or directory. "+
+		"The command fails when a certificate is listed, so it can be used in scheduled checks.")
+	clause.Arg("path", "The namespace, repository or directory to check. Defaults to all repositories you have access to.").StringVar(&cmd.root)
+	clause.Flag("within", "List the certificates that expire within this duration, e.g. 30d or 72h.").Default("30d").SetValue((*durationValue)(&cmd.within))
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// expiringCertificate is a stored certificate that expires soon.
+type expiringCertificate struct {
+	path string
+	cert *x509.Certificate
+}
+
+// Run checks the certificates and lists the ones that expire soon.
+func (cmd *X509ExpiringCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	paths, err := listSecretPaths(client, cmd.root)
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	deadline := now.Add(cmd.within)
+	var expiring []expiringCertificate
+	for _, secretPath := range paths {
+		if path.Base(secretPath) != certificateSecretName {
+			continue
+		}
+
+		secret, err := client.Secrets().Versions().GetWithData(secretPath)
+		if err != nil {
+			return err
+		}
+		certs, err := parseCertificates(secretPath, secret.Data)
+		if err != nil {
+			fmt.Fprintf(statusWriter(cmd.io.Output()), "Skipped %s: %s\n", secretPath, err)
+			continue
+		}
+
+		// The first certificate is the leaf, any others are its chain.
+		if certs[0].NotAfter.Before(deadline) {
+			expiring = append(expiring, expiringCertificate{path: secretPath, cert: certs[0]})
+		}
+	}
+
+	if len(expiring) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "No certificates expire within %s.\n", units.HumanDuration(cmd.within))
+		return nil
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].cert.NotAfter.Before(expiring[j].cert.NotAfter)
+	})
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "PATH", "SUBJECT", "EXPIRES", "DAYS LEFT")
+	for _, e := range expiring {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.path, e.cert.Subject.CommonName, e.cert.NotAfter.UTC().Format("2006-01-02"), formatDaysLeft(e.cert, now, cmd.within))
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	return ErrCertificatesExpiring(pluralize("certificate", "certificates", len(expiring)))
+}
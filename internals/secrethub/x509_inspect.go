@@ -0,0 +1,84 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// defaultExpiryWarning is how long before a certificate expires it is highlighted.
+const defaultExpiryWarning = 30 * 24 * time.Hour
+
+// X509InspectCommand prints the details of a stored certificate.
+type X509InspectCommand struct {
+	io        ui.IO
+	path      string
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewX509InspectCommand creates a new X509InspectCommand.
+func NewX509InspectCommand(io ui.IO, newClient newClientFunc) *X509InspectCommand {
+	return &X509InspectCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *X509InspectCommand) Register(r command.Registerer) {
+	clause := r.Command("inspect", "Print the subject, issuer, names and validity period of a certificate. "+
+		"The path is either a secret with one or more PEM encoded certificates, such as a chain, "+
+		"or a directory the certificate is stored in with `x509 store`.")
+	clause.Arg("path", "The secret or directory with the certificate ("+secretPathOptionalVersionPlaceHolder+" or "+dirPathPlaceHolder+")").Required().StringVar(&cmd.path)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the certificates.
+func (cmd *X509InspectCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	path := cmd.path
+	secret, err := client.Secrets().Versions().GetWithData(path)
+	if api.IsErrNotFound(err) {
+		path = certificateSecretPath(cmd.path)
+		secret, err = client.Secrets().Versions().GetWithData(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	certs, err := parseCertificates(path, secret.Data)
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	for i, cert := range certs {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Subject:\t%s\n", cert.Subject)
+		fmt.Fprintf(w, "Issuer:\t%s\n", cert.Issuer)
+		if names := certificateNames(cert); len(names) > 0 {
+			fmt.Fprintf(w, "Names:\t%s\n", strings.Join(names, ", "))
+		}
+		fmt.Fprintf(w, "Serial:\t%X\n", cert.SerialNumber)
+		fmt.Fprintf(w, "Not before:\t%s\n", cert.NotBefore.UTC().Format(time.RFC3339))
+		fmt.Fprintf(w, "Not after:\t%s\n", cert.NotAfter.UTC().Format(time.RFC3339))
+		fmt.Fprintf(w, "Expires in:\t%s\n", formatDaysLeft(cert, now, defaultExpiryWarning))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,113 @@
+package secrethub
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// X509StoreCommand stores a certificate with its private key and chain in a directory.
+type X509StoreCommand struct {
+	io        ui.IO
+	dirPath   api.DirPath
+	certFile  string
+	keyFile   string
+	chainFile string
+	readFile  func(filename string) ([]byte, error)
+	newClient newClientFunc
+}
+
+// NewX509StoreCommand creates a new X509StoreCommand.
+func NewX509StoreCommand(io ui.IO, newClient newClientFunc) *X509StoreCommand {
+	return &X509StoreCommand{
+		io:        io,
+		readFile:  ioutil.ReadFile,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *X509StoreCommand) Register(r command.Registerer) {
+	clause := r.Command("store", "Store a certificate, its private key and its chain in a directory. "+
+		"The certificate is checked to match the private key before anything is written. "+
+		"The directory is created when it does not exist yet.")
+	clause.Arg("dir-path", "The directory to store the certificate in").Required().PlaceHolder(dirPathPlaceHolder).SetValue(&cmd.dirPath)
+	clause.Flag("cert", "The PEM encoded certificate file.").Required().StringVar(&cmd.certFile)
+	clause.Flag("key", "The PEM encoded private key file of the certificate.").Required().StringVar(&cmd.keyFile)
+	clause.Flag("chain", "The PEM encoded file with the intermediate certificates.").StringVar(&cmd.chainFile)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run validates the certificate and writes it to the directory.
+func (cmd *X509StoreCommand) Run() error {
+	certPEM, err := cmd.readFile(cmd.certFile)
+	if err != nil {
+		return ErrReadFile(cmd.certFile, err)
+	}
+	keyPEM, err := cmd.readFile(cmd.keyFile)
+	if err != nil {
+		return ErrReadFile(cmd.keyFile, err)
+	}
+	certs, err := parseCertificates(cmd.certFile, certPEM)
+	if err != nil {
+		return err
+	}
+	_, err = tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return ErrInvalidPrivateKey(err)
+	}
+
+	secrets := map[string][]byte{
+		certificateSecretName: certPEM,
+		privateKeySecretName:  keyPEM,
+	}
+	if cmd.chainFile != "" {
+		chainPEM, err := cmd.readFile(cmd.chainFile)
+		if err != nil {
+			return ErrReadFile(cmd.chainFile, err)
+		}
+		_, err = parseCertificates(cmd.chainFile, chainPEM)
+		if err != nil {
+			return err
+		}
+		secrets[chainSecretName] = chainPEM
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.Dirs().Exists(cmd.dirPath.Value())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err = client.Dirs().Create(cmd.dirPath.Value())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range []string{certificateSecretName, privateKeySecretName, chainSecretName} {
+		data, ok := secrets[name]
+		if !ok {
+			continue
+		}
+		_, err = client.Secrets().Write(cmd.dirPath.JoinSecret(name).Value(), data)
+		if err != nil {
+			return err
+		}
+	}
+
+	cert := certs[0]
+	fmt.Fprintf(cmd.io.Output(), "Stored the certificate for %s in %s. It expires on %s.\n",
+		cert.Subject.CommonName, cmd.dirPath, cert.NotAfter.UTC().Format("2006-01-02"))
+	return nil
+}
@@ -0,0 +1,200 @@
+package secrethub
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// x509TestNow is the time the certificate tests run at.
+var x509TestNow = time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// generateTestCertificate returns a PEM encoded self-signed certificate for the given name that
+// expires after the given duration, and its PEM encoded private key.
+func generateTestCertificate(t *testing.T, name string, validFor time.Duration) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.OK(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    x509TestNow.Add(-24 * time.Hour),
+		NotAfter:     x509TestNow.Add(validFor),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.OK(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.OK(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestX509StoreCommand_Run(t *testing.T) {
+	certPEM, keyPEM := generateTestCertificate(t, "example.com", 90*24*time.Hour)
+	_, otherKeyPEM := generateTestCertificate(t, "example.com", 90*24*time.Hour)
+
+	cases := map[string]struct {
+		files   map[string][]byte
+		chain   string
+		written []string
+		err     error
+	}{
+		"success": {
+			files:   map[string][]byte{"cert.pem": certPEM, "key.pem": keyPEM},
+			written: []string{"namespace/repo/tls/cert", "namespace/repo/tls/key"},
+		},
+		"with chain": {
+			files:   map[string][]byte{"cert.pem": certPEM, "key.pem": keyPEM, "chain.pem": certPEM},
+			chain:   "chain.pem",
+			written: []string{"namespace/repo/tls/cert", "namespace/repo/tls/key", "namespace/repo/tls/chain"},
+		},
+		"key does not match": {
+			files: map[string][]byte{"cert.pem": certPEM, "key.pem": otherKeyPEM},
+			err:   ErrInvalidPrivateKey("tls: private key does not match public key"),
+		},
+		"not a certificate": {
+			files: map[string][]byte{"cert.pem": keyPEM, "key.pem": keyPEM},
+			err:   ErrInvalidCertificate("cert.pem", "no certificate found"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written []string
+			io := fakeui.NewIO(t)
+			cmd := X509StoreCommand{
+				io:        io,
+				dirPath:   "namespace/repo/tls",
+				certFile:  "cert.pem",
+				keyFile:   "key.pem",
+				chainFile: tc.chain,
+				readFile: func(filename string) ([]byte, error) {
+					return tc.files[filename], nil
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							ExistsFunc: func(path string) (bool, error) {
+								return true, nil
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+								written = append(written, path)
+								return &api.SecretVersion{}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, written, tc.written)
+		})
+	}
+}
+
+func TestX509ExpiringCommand_Run(t *testing.T) {
+	expiringPEM, _ := generateTestCertificate(t, "soon.example.com", 10*24*time.Hour+time.Hour)
+	expiredPEM, _ := generateTestCertificate(t, "old.example.com", -2*24*time.Hour)
+	validPEM, _ := generateTestCertificate(t, "example.com", 90*24*time.Hour)
+
+	cases := map[string]struct {
+		secrets map[string][]byte
+		out     string
+		err     error
+	}{
+		"expiring": {
+			secrets: map[string][]byte{
+				"soon/cert":  expiringPEM,
+				"old/cert":   expiredPEM,
+				"valid/cert": validPEM,
+				"valid/key":  []byte("not a certificate"),
+			},
+			out: "PATH                      SUBJECT           EXPIRES     DAYS LEFT\n" +
+				"namespace/repo/old/cert   old.example.com   2020-05-30  expired 2 days ago\n" +
+				"namespace/repo/soon/cert  soon.example.com  2020-06-11  10 days\n",
+			err: ErrCertificatesExpiring("2 certificates"),
+		},
+		"none": {
+			secrets: map[string][]byte{
+				"valid/cert": validPEM,
+			},
+			out: "No certificates expire within 4 weeks.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDirID := uuid.New()
+			tree := &api.Tree{
+				ParentPath: "namespace",
+				RootDir:    &api.Dir{Name: "repo", DirID: rootDirID},
+				Dirs:       map[uuid.UUID]*api.Dir{rootDirID: {Name: "repo", DirID: rootDirID}},
+				Secrets:    map[uuid.UUID]*api.Secret{},
+			}
+			dirIDs := map[string]uuid.UUID{}
+			for secretPath := range tc.secrets {
+				dirName, secretName := path.Split(secretPath)
+				dirName = strings.TrimSuffix(dirName, "/")
+				dirID, ok := dirIDs[dirName]
+				if !ok {
+					dirID = uuid.New()
+					dirIDs[dirName] = dirID
+					tree.Dirs[dirID] = &api.Dir{Name: dirName, DirID: dirID, ParentID: &rootDirID}
+				}
+				secretID := uuid.New()
+				tree.Secrets[secretID] = &api.Secret{Name: secretName, SecretID: secretID, DirID: dirID}
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := X509ExpiringCommand{
+				io:     io,
+				root:   "namespace/repo",
+				within: defaultExpiryWarning,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return tree, nil
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									return &api.SecretVersion{Data: tc.secrets[strings.TrimPrefix(path, "namespace/repo/")]}, nil
+								},
+							},
+						},
+					}, nil
+				},
+				now: func() time.Time { return x509TestNow },
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}